@@ -0,0 +1,296 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package community
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+)
+
+// infomapTol is the minimum reduction in description length
+// accepted when moving a node between modules.
+const infomapTol = 1e-10
+
+// MapEquation returns the description length in bits of a random walk on the
+// undirected graph g using the two-level module codebook given by
+// communities. If communities is nil, each node is placed in its own module.
+// MapEquation will panic if g has any edge with negative edge weight.
+//
+// The description length is
+//  L = q H(Q) + \sum_i p_i H(P_i),
+// where q is the rate at which the walk moves between modules, H(Q) is the
+// entropy of the module exit rates, p_i is the rate of use of the codebook of
+// module i and H(P_i) is the entropy of the codebook of module i as described
+// in Rosvall and Bergstrom doi:10.1073/pnas.0706851105. The stationary
+// distribution of the random walk is proportional to the weighted node degree.
+//
+// MapEquation returns zero if g has no edges.
+func MapEquation(g graph.Undirected, communities [][]graph.Node) float64 {
+	a := newAdjacency(g)
+	if a.m2 == 0 {
+		return 0
+	}
+	labels := make([]int, len(a.nodes))
+	if communities == nil {
+		for i := range labels {
+			labels[i] = i
+		}
+	} else {
+		m := membership(communities)
+		for i, n := range a.nodes {
+			c, ok := m[n.ID()]
+			if !ok {
+				panic("community: node not in community")
+			}
+			labels[i] = c
+		}
+		renumber(labels)
+	}
+	return newMapCoder(a, labels).codeLength() / math.Ln2
+}
+
+// Infomap returns the communities of the undirected graph g that minimise the
+// description length of a random walk on g given by the map equation. If src
+// is nil, the global random source is used. Infomap will panic if g has any
+// edge with negative edge weight.
+//
+// The map equation is minimised using a greedy search with repeated local
+// moving of nodes between modules and aggregation of modules as described in
+// Rosvall, Axelsson and Bergstrom doi:10.1140/epjst/e2010-01179-1. See
+// MapEquation for the description length that is minimised.
+//
+// The returned communities are sorted by the lowest node ID in each community
+// and the nodes within each community are sorted by ID.
+//
+// graph.Undirect may be used as a shim to allow community detection in
+// directed graphs, although the flow of the random walk will then ignore
+// edge direction.
+func Infomap(g graph.Undirected, src rand.Source) [][]graph.Node {
+	a := newAdjacency(g)
+	nodes := a.nodes
+	rnd := randFor(src)
+
+	level := make([]int, len(a.edges))
+	for i := range level {
+		level[i] = i
+	}
+	if a.m2 == 0 {
+		return communitiesOf(nodes, level)
+	}
+
+	// flow holds the stationary visit rate of each node
+	// of the original graph. The node entropy term of the
+	// map equation depends only on these.
+	flow := make([]float64, len(a.degree))
+	for i, k := range a.degree {
+		flow[i] = k / a.m2
+	}
+	for {
+		comm := make([]int, len(a.edges))
+		for i := range comm {
+			comm[i] = i
+		}
+		c := newMapCoder(a, comm)
+		c.nodeEntropy = nodeEntropy(flow)
+		c.moveNodes(rnd)
+		n := renumber(comm)
+		if n == len(comm) {
+			break
+		}
+		a = a.aggregate(comm, n)
+		for i, l := range level {
+			level[i] = comm[l]
+		}
+	}
+	return communitiesOf(nodes, level)
+}
+
+// mapCoder holds the state required to evaluate and optimise the
+// two-level map equation for a partition of the nodes of an adjacency.
+type mapCoder struct {
+	a *adjacency
+
+	// comm holds the module of each node.
+	comm []int
+
+	// flow and exit hold the visit rate and
+	// exit rate of each module, and size holds
+	// the number of nodes in each module.
+	flow []float64
+	exit []float64
+	size []int
+
+	// exitTotal is the sum of module exit rates
+	// and the remaining fields hold the sums of
+	// the p log p terms of the map equation.
+	exitTotal    float64
+	exitLogExit  float64
+	totalLogFlow float64
+	nodeEntropy  float64
+}
+
+// newMapCoder returns a mapCoder for the adjacency a with modules given
+// by comm. The module labels in comm must be less than the number of nodes
+// in a. The node entropy term is computed from the nodes of a.
+func newMapCoder(a *adjacency, comm []int) *mapCoder {
+	n := len(comm)
+	c := &mapCoder{
+		a:    a,
+		comm: comm,
+		flow: make([]float64, n),
+		exit: make([]float64, n),
+		size: make([]int, n),
+	}
+	flow := make([]float64, n)
+	for u, m := range comm {
+		flow[u] = a.degree[u] / a.m2
+		c.flow[m] += flow[u]
+		c.size[m]++
+		for _, e := range a.edges[u] {
+			if comm[e.node] != m {
+				c.exit[m] += e.weight / a.m2
+			}
+		}
+	}
+	c.nodeEntropy = nodeEntropy(flow)
+	for m := range c.flow {
+		c.exitTotal += c.exit[m]
+		c.exitLogExit += plogp(c.exit[m])
+		c.totalLogFlow += plogp(c.exit[m] + c.flow[m])
+	}
+	return c
+}
+
+// codeLength returns the description length in nats.
+func (c *mapCoder) codeLength() float64 {
+	return plogp(c.exitTotal) - 2*c.exitLogExit - c.nodeEntropy + c.totalLogFlow
+}
+
+// moveNodes repeatedly moves nodes to the neighbouring module that most
+// reduces the description length until no move improves it.
+func (c *mapCoder) moveNodes(rnd randSource) {
+	a := c.a
+	n := len(c.comm)
+	weightTo := make([]float64, n)
+	var (
+		touched []int
+		free    []int
+	)
+	for m, s := range c.size {
+		if s == 0 {
+			free = append(free, m)
+		}
+	}
+	for moved := true; moved; {
+		moved = false
+		for _, u := range rnd.Perm(n) {
+			from := c.comm[u]
+			var out float64
+			for _, e := range a.edges[u] {
+				m := c.comm[e.node]
+				if weightTo[m] == 0 {
+					touched = append(touched, m)
+				}
+				weightTo[m] += e.weight / a.m2
+				out += e.weight / a.m2
+			}
+			flow := a.degree[u] / a.m2
+
+			best := from
+			var bestDelta, bestWeight float64
+			if c.size[from] > 1 && len(free) != 0 {
+				best = free[len(free)-1]
+				bestDelta = c.delta(from, best, flow, out, weightTo[from], 0)
+			}
+			for _, m := range touched {
+				if m == from {
+					continue
+				}
+				if d := c.delta(from, m, flow, out, weightTo[from], weightTo[m]); d < bestDelta {
+					best, bestDelta, bestWeight = m, d, weightTo[m]
+				}
+			}
+			wFrom := weightTo[from]
+			for _, m := range touched {
+				weightTo[m] = 0
+			}
+			touched = touched[:0]
+
+			if best == from || bestDelta > -infomapTol {
+				continue
+			}
+			if c.size[best] == 0 {
+				free = free[:len(free)-1]
+			}
+			c.move(u, from, best, flow, out, wFrom, bestWeight)
+			if c.size[from] == 0 {
+				free = append(free, from)
+			}
+			moved = true
+		}
+	}
+}
+
+// delta returns the change in description length from moving a node with
+// visit rate flow and total edge flow out from module from to module to,
+// where wFrom and wTo are the flows between the node and the other nodes
+// of the two modules.
+func (c *mapCoder) delta(from, to int, flow, out, wFrom, wTo float64) float64 {
+	exitFrom := c.exit[from] - out + 2*wFrom
+	exitTo := c.exit[to] + out - 2*wTo
+	flowFrom := c.flow[from] - flow
+	flowTo := c.flow[to] + flow
+
+	exitTotal := c.exitTotal - c.exit[from] - c.exit[to] + exitFrom + exitTo
+	exitLogExit := c.exitLogExit -
+		plogp(c.exit[from]) - plogp(c.exit[to]) +
+		plogp(exitFrom) + plogp(exitTo)
+	totalLogFlow := c.totalLogFlow -
+		plogp(c.exit[from]+c.flow[from]) - plogp(c.exit[to]+c.flow[to]) +
+		plogp(exitFrom+flowFrom) + plogp(exitTo+flowTo)
+
+	return plogp(exitTotal) - 2*exitLogExit - c.nodeEntropy + totalLogFlow - c.codeLength()
+}
+
+// move moves node u with visit rate flow and total edge flow out from
+// module from to module to, where wFrom and wTo are the flows between u
+// and the other nodes of the two modules.
+func (c *mapCoder) move(u, from, to int, flow, out, wFrom, wTo float64) {
+	c.exitTotal -= c.exit[from] + c.exit[to]
+	c.exitLogExit -= plogp(c.exit[from]) + plogp(c.exit[to])
+	c.totalLogFlow -= plogp(c.exit[from]+c.flow[from]) + plogp(c.exit[to]+c.flow[to])
+
+	c.exit[from] += 2*wFrom - out
+	c.exit[to] += out - 2*wTo
+	c.flow[from] -= flow
+	c.flow[to] += flow
+	c.size[from]--
+	c.size[to]++
+	c.comm[u] = to
+
+	c.exitTotal += c.exit[from] + c.exit[to]
+	c.exitLogExit += plogp(c.exit[from]) + plogp(c.exit[to])
+	c.totalLogFlow += plogp(c.exit[from]+c.flow[from]) + plogp(c.exit[to]+c.flow[to])
+}
+
+// nodeEntropy returns the sum of p log p over the node visit rates.
+func nodeEntropy(flow []float64) float64 {
+	var h float64
+	for _, p := range flow {
+		h += plogp(p)
+	}
+	return h
+}
+
+// plogp returns p log p, or zero if p is zero.
+func plogp(p float64) float64 {
+	if p <= 0 {
+		return 0
+	}
+	return p * math.Log(p)
+}
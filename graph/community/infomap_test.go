@@ -0,0 +1,109 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package community
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// ringOfCliques is a ring of four 5-cliques joined by single edges.
+var ringOfCliques = func() []intset {
+	g := make([]intset, 20)
+	for c := 0; c < 4; c++ {
+		for i := 0; i < 5; i++ {
+			u := 5*c + i
+			g[u] = make(intset)
+			for j := i + 1; j < 5; j++ {
+				g[u][5*c+j] = struct{}{}
+			}
+		}
+		g[5*c][(5*c+9)%20] = struct{}{}
+	}
+	return g
+}()
+
+func TestMapEquation(t *testing.T) {
+	const tol = 1e-12
+
+	// A single module codes a random walk with
+	// the entropy of its stationary distribution.
+	g := undirectedFrom([]intset{
+		0: linksTo(1, 2, 3),
+		1: linksTo(2, 3),
+		2: linksTo(3),
+	})
+	got := MapEquation(g, [][]graph.Node{graph.NodesOf(g.Nodes())})
+	if !scalar.EqualWithinAbsOrRel(got, 2, tol, tol) {
+		t.Errorf("unexpected description length for single module: got:%v want:2", got)
+	}
+
+	// Each node in its own module requires a two-level code
+	// for the 4-clique. Every step exits a module with
+	// rate 1/4 per module and the entropies of the module
+	// codebooks are each one bit.
+	got = MapEquation(g, nil)
+	want := 2.0 + 4*(0.5*1)
+	if !scalar.EqualWithinAbsOrRel(got, want, tol, tol) {
+		t.Errorf("unexpected description length for singleton modules: got:%v want:%v", got, want)
+	}
+
+	if got := MapEquation(simple.NewUndirectedGraph(), nil); got != 0 {
+		t.Errorf("unexpected description length for empty graph: got:%v want:0", got)
+	}
+}
+
+func TestInfomap(t *testing.T) {
+	g := undirectedFrom(ringOfCliques)
+	var want [][]graph.Node
+	for c := 0; c < 4; c++ {
+		var comm []graph.Node
+		for i := 0; i < 5; i++ {
+			comm = append(comm, simple.Node(5*c+i))
+		}
+		want = append(want, comm)
+	}
+
+	wg := simple.NewWeightedUndirectedGraph(0, 0)
+	for _, e := range graph.EdgesOf(g.Edges()) {
+		wg.SetWeightedEdge(simple.WeightedEdge{F: e.From(), T: e.To(), W: 2})
+	}
+
+	for seed := uint64(1); seed <= 5; seed++ {
+		for _, g := range []graph.Undirected{g, wg} {
+			got := Infomap(g, rand.NewSource(seed))
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("unexpected communities for seed %d:\ngot: %v\nwant:%v", seed, got, want)
+			}
+		}
+	}
+
+	for _, test := range communityUndirectedQTests {
+		g := undirectedFrom(test.g)
+		got := Infomap(g, rand.NewSource(1))
+		checkPartition(t, test.name, g, got)
+
+		// The partition found should be no worse than
+		// the trivial partitions.
+		l := MapEquation(g, got)
+		one := MapEquation(g, [][]graph.Node{graph.NodesOf(g.Nodes())})
+		if l > one+1e-12 {
+			t.Errorf("unexpected description length for %q: got:%v single module:%v", test.name, l, one)
+		}
+		if all := MapEquation(g, nil); l > all+1e-12 {
+			t.Errorf("unexpected description length for %q: got:%v singleton modules:%v", test.name, l, all)
+		}
+		if math.IsNaN(l) {
+			t.Errorf("NaN description length for %q", test.name)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package community
+
+import (
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+)
+
+// LabelPropagation returns the communities of the undirected graph g found
+// by asynchronous label propagation as described in Raghavan, Albert and
+// Kumara doi:10.1103/PhysRevE.76.036106. If src is nil, the global random
+// source is used. LabelPropagation will panic if g has any edge with
+// negative edge weight.
+//
+// Each node starts with a unique label. Nodes are visited in a random order
+// and each node adopts the label with the greatest total edge weight among
+// its neighbours, breaking ties uniformly at random and keeping its current
+// label if that is among the best. The process terminates when no node
+// changes its label during a pass over the nodes. Label propagation runs in
+// near linear time in the number of edges and so is suitable for very large
+// graphs, but the communities found depend on the visiting order.
+//
+// The returned communities are sorted by the lowest node ID in each community
+// and the nodes within each community are sorted by ID.
+//
+// graph.Undirect may be used as a shim to allow community detection in
+// directed graphs.
+func LabelPropagation(g graph.Undirected, src rand.Source) [][]graph.Node {
+	a := newAdjacency(g)
+	rnd := randFor(src)
+
+	n := len(a.edges)
+	labels := make([]int, n)
+	for i := range labels {
+		labels[i] = i
+	}
+
+	weightTo := make([]float64, n)
+	var touched, best []int
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	for changed := true; changed; {
+		changed = false
+		for i := range order {
+			j := i + rnd.Intn(n-i)
+			order[i], order[j] = order[j], order[i]
+		}
+		for _, u := range order {
+			if len(a.edges[u]) == 0 {
+				continue
+			}
+			for _, e := range a.edges[u] {
+				l := labels[e.node]
+				if weightTo[l] == 0 {
+					touched = append(touched, l)
+				}
+				weightTo[l] += e.weight
+			}
+
+			var max float64
+			best = best[:0]
+			for _, l := range touched {
+				switch w := weightTo[l]; {
+				case w > max:
+					max = w
+					best = append(best[:0], l)
+				case w == max:
+					best = append(best, l)
+				}
+			}
+			keep := weightTo[labels[u]] == max
+			for _, l := range touched {
+				weightTo[l] = 0
+			}
+			touched = touched[:0]
+
+			if keep || len(best) == 0 {
+				continue
+			}
+			labels[u] = best[rnd.Intn(len(best))]
+			changed = true
+		}
+	}
+
+	return communitiesOf(a.nodes, labels)
+}
@@ -0,0 +1,74 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package community
+
+import (
+	"reflect"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// twoCliques is a pair of disconnected 4-cliques.
+var twoCliques = []intset{
+	0: linksTo(1, 2, 3),
+	1: linksTo(2, 3),
+	2: linksTo(3),
+	4: linksTo(5, 6, 7),
+	5: linksTo(6, 7),
+	6: linksTo(7),
+}
+
+func TestLabelPropagation(t *testing.T) {
+	g := undirectedFrom(twoCliques)
+	want := [][]graph.Node{
+		{simple.Node(0), simple.Node(1), simple.Node(2), simple.Node(3)},
+		{simple.Node(4), simple.Node(5), simple.Node(6), simple.Node(7)},
+	}
+	for seed := uint64(1); seed <= 10; seed++ {
+		got := LabelPropagation(g, rand.NewSource(seed))
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("unexpected communities for seed %d:\ngot: %v\nwant:%v", seed, got, want)
+		}
+	}
+
+	for _, test := range communityUndirectedQTests {
+		g := undirectedFrom(test.g)
+		for seed := uint64(1); seed <= 5; seed++ {
+			got := LabelPropagation(g, rand.NewSource(seed))
+			checkPartition(t, test.name, g, got)
+			checkLabelsStable(t, test.name, g, got)
+		}
+	}
+
+	got := LabelPropagation(dupGraph, rand.NewSource(1))
+	checkPartition(t, "duplication", dupGraph, got)
+	checkLabelsStable(t, "duplication", dupGraph, got)
+}
+
+// checkLabelsStable checks that every node with neighbours is in the
+// community that is most common among its neighbours.
+func checkLabelsStable(t *testing.T, name string, g graph.Undirected, communities [][]graph.Node) {
+	t.Helper()
+	comm := membership(communities)
+	for _, u := range graph.NodesOf(g.Nodes()) {
+		count := make(map[int]int)
+		for _, v := range graph.NodesOf(g.From(u.ID())) {
+			count[comm[v.ID()]]++
+		}
+		var max int
+		for _, c := range count {
+			if c > max {
+				max = c
+			}
+		}
+		if len(count) != 0 && count[comm[u.ID()]] != max {
+			t.Errorf("node %d does not have a majority label for %q", u.ID(), name)
+		}
+	}
+}
@@ -0,0 +1,475 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package community
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+)
+
+// leidenTheta is the randomness parameter θ used when selecting
+// merges during the refinement phase of the Leiden algorithm.
+const leidenTheta = 0.01
+
+// Leiden returns the communities of the undirected graph g found by
+// maximising the modularity at the given resolution using the Leiden
+// algorithm. If src is nil, the global random source is used. Leiden will
+// panic if g has any edge with negative edge weight.
+//
+// The modularity optimised is
+//  Q = 1/2m \sum_{ij} [ A_{ij} - (\gamma k_i k_j)/2m ] \delta(c_i,c_j).
+//
+// The Leiden algorithm improves on the Louvain algorithm used by Modularize
+// by refining each partition before aggregation so that the returned
+// communities are guaranteed to be connected. The algorithm is described in
+// Traag, Waltman and van Eck doi:10.1038/s41598-019-41695-z.
+//
+// The returned communities are sorted by the lowest node ID in each community
+// and the nodes within each community are sorted by ID.
+//
+// graph.Undirect may be used as a shim to allow community detection in
+// directed graphs.
+func Leiden(g graph.Undirected, resolution float64, src rand.Source) [][]graph.Node {
+	a := newAdjacency(g)
+	rnd := randFor(src)
+
+	// Iterate the algorithm from the previous
+	// partition until no improvement is found.
+	labels := make([]int, len(a.nodes))
+	for i := range labels {
+		labels[i] = i
+	}
+	q := a.modularity(labels, resolution)
+	for {
+		next := a.leiden(labels, resolution, rnd)
+		nq := a.modularity(next, resolution)
+		if nq <= q {
+			break
+		}
+		labels, q = next, nq
+	}
+	return communitiesOf(a.nodes, labels)
+}
+
+// leiden performs a single run of the Leiden algorithm on a starting from
+// the partition given by labels and returns the community labels of the
+// nodes of a.
+func (a *adjacency) leiden(labels []int, resolution float64, rnd randSource) []int {
+	// level holds the node of the current
+	// aggregate graph containing each of the
+	// nodes of a, and comm holds the community
+	// of each aggregate node.
+	level := make([]int, len(a.edges))
+	comm := make([]int, len(a.edges))
+	for i := range level {
+		level[i] = i
+	}
+	copy(comm, labels)
+	renumber(comm)
+	for {
+		a.moveNodesFast(comm, resolution, rnd)
+		n := renumber(comm)
+		if n == len(comm) {
+			break
+		}
+
+		refined := a.refine(comm, resolution, rnd)
+		nr := renumber(refined)
+		if nr == len(comm) {
+			// No merges were possible during
+			// refinement so aggregate on the
+			// unrefined partition.
+			copy(refined, comm)
+			nr = n
+		}
+
+		next := make([]int, nr)
+		for i, r := range refined {
+			next[r] = comm[i]
+		}
+		a = a.aggregate(refined, nr)
+		for i, l := range level {
+			level[i] = refined[l]
+		}
+		comm = next
+	}
+
+	next := make([]int, len(level))
+	for i, l := range level {
+		next[i] = comm[l]
+	}
+	return next
+}
+
+// modularity returns the modularity of the partition of a given by labels
+// at the given resolution. Labels must be less than the number of nodes in a.
+func (a *adjacency) modularity(labels []int, resolution float64) float64 {
+	if a.m2 == 0 {
+		return 0
+	}
+	internal := make([]float64, len(labels))
+	total := make([]float64, len(labels))
+	for u, c := range labels {
+		internal[c] += 2 * a.self[u]
+		total[c] += a.degree[u]
+		for _, e := range a.edges[u] {
+			if labels[e.node] == c {
+				internal[c] += e.weight
+			}
+		}
+	}
+	var q float64
+	for c := range internal {
+		q += internal[c] - resolution*total[c]*total[c]/a.m2
+	}
+	return q / a.m2
+}
+
+// adjacency is a dense weighted undirected graph representation
+// used by the community detection algorithms that work on node
+// indices rather than graph.Node values.
+type adjacency struct {
+	// nodes holds the nodes of the original
+	// graph indexed by node index.
+	nodes []graph.Node
+
+	// edges holds the neighbours of each
+	// node excluding self loops.
+	edges [][]neighbour
+
+	// self holds the weight of self loops
+	// for each node.
+	self []float64
+
+	// degree holds the weighted degree of
+	// each node with self loops counted twice.
+	degree []float64
+
+	// m2 is twice the total edge weight.
+	m2 float64
+}
+
+// neighbour is a weighted connection to a node index.
+type neighbour struct {
+	node   int
+	weight float64
+}
+
+// newAdjacency returns the dense representation of g. The nodes of the
+// returned adjacency hold the nodes of g.
+func newAdjacency(g graph.Undirected) *adjacency {
+	nodes := graph.NodesOf(g.Nodes())
+	indexOf := make(map[int64]int, len(nodes))
+	for i, n := range nodes {
+		indexOf[n.ID()] = i
+	}
+	weight := positiveWeightFuncFor(g)
+
+	a := &adjacency{
+		nodes:  nodes,
+		edges:  make([][]neighbour, len(nodes)),
+		self:   make([]float64, len(nodes)),
+		degree: make([]float64, len(nodes)),
+	}
+	for i, u := range nodes {
+		uid := u.ID()
+		to := g.From(uid)
+		for to.Next() {
+			vid := to.Node().ID()
+			w := weight(uid, vid)
+			if vid == uid {
+				a.self[i] = w
+				a.degree[i] += 2 * w
+				a.m2 += 2 * w
+				continue
+			}
+			a.edges[i] = append(a.edges[i], neighbour{node: indexOf[vid], weight: w})
+			a.degree[i] += w
+			a.m2 += w
+		}
+	}
+	return a
+}
+
+// aggregate returns the graph obtained by collapsing the nodes of a into
+// the n groups given by membership.
+func (a *adjacency) aggregate(membership []int, n int) *adjacency {
+	agg := &adjacency{
+		edges:  make([][]neighbour, n),
+		self:   make([]float64, n),
+		degree: make([]float64, n),
+		m2:     a.m2,
+	}
+	weights := make([]map[int]float64, n)
+	for u, edges := range a.edges {
+		cu := membership[u]
+		agg.self[cu] += a.self[u]
+		agg.degree[cu] += a.degree[u]
+		for _, e := range edges {
+			cv := membership[e.node]
+			if cu == cv {
+				// Each internal edge is seen
+				// from both of its ends.
+				agg.self[cu] += e.weight / 2
+				continue
+			}
+			if weights[cu] == nil {
+				weights[cu] = make(map[int]float64)
+			}
+			if _, ok := weights[cu][cv]; !ok {
+				agg.edges[cu] = append(agg.edges[cu], neighbour{node: cv})
+			}
+			weights[cu][cv] += e.weight
+		}
+	}
+	for u, edges := range agg.edges {
+		for i, e := range edges {
+			edges[i].weight = weights[u][e.node]
+		}
+	}
+	return agg
+}
+
+// moveNodesFast performs the fast local moving phase of the Leiden
+// algorithm, updating comm in place. Community labels in comm must be
+// less than the number of nodes in a.
+func (a *adjacency) moveNodesFast(comm []int, resolution float64, rnd randSource) {
+	n := len(comm)
+	if n == 0 || a.m2 == 0 {
+		return
+	}
+	total := make([]float64, n)
+	size := make([]int, n)
+	for u, c := range comm {
+		total[c] += a.degree[u]
+		size[c]++
+	}
+	var free []int
+	for c, s := range size {
+		if s == 0 {
+			free = append(free, c)
+		}
+	}
+
+	queue := rnd.Perm(n)
+	queued := make([]bool, n)
+	for i := range queued {
+		queued[i] = true
+	}
+	weightTo := make([]float64, n)
+	var touched []int
+	for len(queue) != 0 {
+		u := queue[0]
+		queue = queue[1:]
+		queued[u] = false
+
+		cu := comm[u]
+		k := a.degree[u]
+		total[cu] -= k
+		size[cu]--
+
+		for _, e := range a.edges[u] {
+			c := comm[e.node]
+			if weightTo[c] == 0 {
+				touched = append(touched, c)
+			}
+			weightTo[c] += e.weight
+		}
+
+		best := cu
+		bestGain := weightTo[cu] - resolution*k*total[cu]/a.m2
+		for _, c := range touched {
+			gain := weightTo[c] - resolution*k*total[c]/a.m2
+			if gain > bestGain {
+				best, bestGain = c, gain
+			}
+		}
+		if bestGain < 0 {
+			// Moving to an empty community
+			// has zero gain.
+			if size[cu] != 0 {
+				best = free[len(free)-1]
+				free = free[:len(free)-1]
+			} else {
+				best = cu
+			}
+		}
+		for _, c := range touched {
+			weightTo[c] = 0
+		}
+		touched = touched[:0]
+
+		comm[u] = best
+		total[best] += k
+		size[best]++
+		if best == cu {
+			continue
+		}
+		if size[cu] == 0 {
+			free = append(free, cu)
+		}
+		for _, e := range a.edges[u] {
+			v := e.node
+			if !queued[v] && comm[v] != best {
+				queue = append(queue, v)
+				queued[v] = true
+			}
+		}
+	}
+}
+
+// refine returns the refined partition of the nodes of a within the
+// communities held in comm. Nodes are only merged with well-connected
+// subsets of their community.
+func (a *adjacency) refine(comm []int, resolution float64, rnd randSource) []int {
+	n := len(comm)
+	refined := make([]int, n)
+	total := make([]float64, n)
+	size := make([]int, n)
+	for u := range refined {
+		refined[u] = u
+		total[u] = a.degree[u]
+		size[u] = 1
+	}
+
+	// commTotal holds the total degree of each
+	// community and external holds the weight
+	// of edges from each refined community to
+	// the rest of its community.
+	commTotal := make([]float64, n)
+	for u, c := range comm {
+		commTotal[c] += a.degree[u]
+	}
+	external := make([]float64, n)
+	for u, edges := range a.edges {
+		for _, e := range edges {
+			if comm[e.node] == comm[u] {
+				external[u] += e.weight
+			}
+		}
+	}
+
+	weightTo := make([]float64, n)
+	var (
+		touched []int
+		cand    []int
+		gains   []float64
+	)
+	for _, u := range rnd.Perm(n) {
+		if size[refined[u]] != 1 {
+			continue
+		}
+		c := comm[u]
+		k := a.degree[u]
+		if external[u] < resolution*k*(commTotal[c]-k)/a.m2 {
+			continue
+		}
+
+		for _, e := range a.edges[u] {
+			if comm[e.node] != c {
+				continue
+			}
+			r := refined[e.node]
+			if weightTo[r] == 0 {
+				touched = append(touched, r)
+			}
+			weightTo[r] += e.weight
+		}
+
+		// Staying in the singleton has zero gain.
+		ru := refined[u]
+		cand = append(cand[:0], ru)
+		gains = append(gains[:0], 0)
+		maxGain := 0.0
+		for _, r := range touched {
+			if external[r] < resolution*total[r]*(commTotal[c]-total[r])/a.m2 {
+				continue
+			}
+			gain := (weightTo[r] - resolution*k*total[r]/a.m2) / (a.m2 / 2)
+			if gain < 0 {
+				continue
+			}
+			cand = append(cand, r)
+			gains = append(gains, gain)
+			if gain > maxGain {
+				maxGain = gain
+			}
+		}
+
+		// Choose a refined community with probability
+		// proportional to exp(gain/θ).
+		var sum float64
+		for i, gain := range gains {
+			gains[i] = math.Exp((gain - maxGain) / leidenTheta)
+			sum += gains[i]
+		}
+		chosen := cand[len(cand)-1]
+		x := rnd.Float64() * sum
+		for i, p := range gains {
+			if x < p {
+				chosen = cand[i]
+				break
+			}
+			x -= p
+		}
+
+		if chosen != ru {
+			refined[u] = chosen
+			size[ru] = 0
+			total[ru] = 0
+			size[chosen]++
+			total[chosen] += k
+			external[chosen] += external[u] - 2*weightTo[chosen]
+		}
+
+		for _, r := range touched {
+			weightTo[r] = 0
+		}
+		touched = touched[:0]
+	}
+	return refined
+}
+
+// renumber relabels the values in labels to be contiguous from zero
+// in order of first appearance and returns the number of distinct labels.
+func renumber(labels []int) int {
+	index := make(map[int]int)
+	for i, l := range labels {
+		j, ok := index[l]
+		if !ok {
+			j = len(index)
+			index[l] = j
+		}
+		labels[i] = j
+	}
+	return len(index)
+}
+
+// randSource is the random number source used by the community
+// detection algorithms.
+type randSource interface {
+	Intn(int) int
+	Float64() float64
+	Perm(int) []int
+}
+
+// randFor returns a randSource using src, or the global random
+// source if src is nil.
+func randFor(src rand.Source) randSource {
+	if src == nil {
+		return globalRand{}
+	}
+	return rand.New(src)
+}
+
+// globalRand is a randSource using the global random source.
+type globalRand struct{}
+
+func (globalRand) Intn(n int) int   { return rand.Intn(n) }
+func (globalRand) Float64() float64 { return rand.Float64() }
+func (globalRand) Perm(n int) []int { return rand.Perm(n) }
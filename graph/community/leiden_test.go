@@ -0,0 +1,181 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package community
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+	"gonum.org/v1/gonum/graph/topo"
+)
+
+func TestLeiden(t *testing.T) {
+	for _, test := range communityUndirectedQTests {
+		g := undirectedFrom(test.g)
+		for seed := uint64(1); seed <= 5; seed++ {
+			got := Leiden(g, 1, rand.NewSource(seed))
+			checkPartition(t, test.name, g, got)
+			checkConnected(t, test.name, g, got)
+
+			want := test.wantLevels[0].q
+			if math.IsInf(want, -1) {
+				// The graph has no edges.
+				if len(got) != g.Nodes().Len() {
+					t.Errorf("unexpected number of communities for %q: got:%d want:%d", test.name, len(got), g.Nodes().Len())
+				}
+				continue
+			}
+			// The Leiden algorithm finds a local optimum
+			// close to the best known partition.
+			if q := Q(g, got, 1); q < want-0.05 {
+				t.Errorf("unexpected modularity for %q seed %d: got:%v want>=%v", test.name, seed, q, want-0.05)
+			}
+			checkNodeOptimal(t, test.name, g, got)
+		}
+	}
+}
+
+func TestLeidenDuplication(t *testing.T) {
+	src := rand.NewSource(1)
+	got := Leiden(dupGraph, 1, src)
+	checkPartition(t, "duplication", dupGraph, got)
+	checkConnected(t, "duplication", dupGraph, got)
+
+	// The Leiden algorithm should find at least as good
+	// a partition as the Louvain algorithm.
+	louvain := Modularize(dupGraph, 1, src).Communities()
+	if q, ql := Q(dupGraph, got, 1), Q(dupGraph, louvain, 1); q < ql-1e-2 {
+		t.Errorf("unexpected modularity: got:%v want>=%v", q, ql)
+	}
+}
+
+func TestLeidenResolution(t *testing.T) {
+	g := undirectedFrom(zachary)
+
+	// A very low resolution places all
+	// nodes of a connected graph in a
+	// single community.
+	got := Leiden(g, 1e-3, rand.NewSource(1))
+	if len(got) != 1 {
+		t.Errorf("unexpected number of communities at low resolution: got:%d want:1", len(got))
+	}
+
+	// A very high resolution places all
+	// nodes in singleton communities.
+	got = Leiden(g, 1e3, rand.NewSource(1))
+	if len(got) != g.Nodes().Len() {
+		t.Errorf("unexpected number of communities at high resolution: got:%d want:%d", len(got), g.Nodes().Len())
+	}
+}
+
+func BenchmarkLeiden(b *testing.B) {
+	src := rand.New(rand.NewSource(1))
+	for i := 0; i < b.N; i++ {
+		Leiden(dupGraph, 1, src)
+	}
+}
+
+func undirectedFrom(g []intset) *simple.UndirectedGraph {
+	dst := simple.NewUndirectedGraph()
+	for u, e := range g {
+		// Add nodes that are not defined by an edge.
+		if dst.Node(int64(u)) == nil {
+			dst.AddNode(simple.Node(u))
+		}
+		for v := range e {
+			dst.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v)})
+		}
+	}
+	return dst
+}
+
+// checkPartition checks that communities is a partition of the
+// nodes of g in the canonical order returned by communitiesOf.
+func checkPartition(t *testing.T, name string, g graph.Graph, communities [][]graph.Node) {
+	t.Helper()
+	seen := make(map[int64]bool)
+	for i, c := range communities {
+		if len(c) == 0 {
+			t.Errorf("empty community for %q", name)
+			continue
+		}
+		if i > 0 && communities[i-1][0].ID() > c[0].ID() {
+			t.Errorf("communities not sorted for %q", name)
+		}
+		for j, n := range c {
+			if j > 0 && c[j-1].ID() > n.ID() {
+				t.Errorf("community nodes not sorted for %q", name)
+			}
+			if seen[n.ID()] {
+				t.Errorf("node %d in more than one community for %q", n.ID(), name)
+			}
+			seen[n.ID()] = true
+			if g.Node(n.ID()) == nil {
+				t.Errorf("node %d not in graph for %q", n.ID(), name)
+			}
+		}
+	}
+	if len(seen) != g.Nodes().Len() {
+		t.Errorf("unexpected number of partitioned nodes for %q: got:%d want:%d", name, len(seen), g.Nodes().Len())
+	}
+}
+
+// checkNodeOptimal checks that no single node move between communities
+// increases the modularity of the partition.
+func checkNodeOptimal(t *testing.T, name string, g graph.Undirected, communities [][]graph.Node) {
+	t.Helper()
+	q := Q(g, communities, 1)
+	for i, c := range communities {
+		for j, n := range c {
+			for k := 0; k <= len(communities); k++ {
+				if k == i {
+					continue
+				}
+				moved := make([][]graph.Node, len(communities), len(communities)+1)
+				copy(moved, communities)
+				moved[i] = append(append([]graph.Node(nil), c[:j]...), c[j+1:]...)
+				if k == len(communities) {
+					moved = append(moved, []graph.Node{n})
+				} else {
+					moved[k] = append(append([]graph.Node(nil), moved[k]...), n)
+				}
+				if mq := Q(g, moved, 1); mq > q+1e-12 {
+					t.Errorf("moving node %d improves modularity for %q: %v > %v", n.ID(), name, mq, q)
+					return
+				}
+			}
+		}
+	}
+}
+
+// checkConnected checks that each community induces a connected
+// subgraph of g.
+func checkConnected(t *testing.T, name string, g graph.Undirected, communities [][]graph.Node) {
+	t.Helper()
+	for _, c := range communities {
+		sub := simple.NewUndirectedGraph()
+		in := make(map[int64]bool)
+		for _, n := range c {
+			sub.AddNode(n)
+			in[n.ID()] = true
+		}
+		for _, u := range c {
+			to := g.From(u.ID())
+			for to.Next() {
+				v := to.Node()
+				if in[v.ID()] && u.ID() < v.ID() {
+					sub.SetEdge(simple.Edge{F: u, T: v})
+				}
+			}
+		}
+		if cc := topo.ConnectedComponents(sub); len(cc) != 1 {
+			t.Errorf("community %v is not connected for %q: %d components", c, name, len(cc))
+		}
+	}
+}
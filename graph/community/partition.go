@@ -0,0 +1,153 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package community
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+)
+
+// NormalizedMutualInformation returns the normalized mutual information
+// between the partitions a and b of a set of nodes,
+//  NMI = 2 I(a; b) / (H(a) + H(b)),
+// where I is the mutual information between the community memberships
+// of the partitions and H is the entropy of a partition. The returned
+// value is one when the partitions are identical and zero when they are
+// independent. If both partitions have a single community, the returned
+// value is one.
+//
+// NormalizedMutualInformation will panic if a and b do not partition the
+// same set of nodes or if a node is in more than one community of either
+// partition.
+func NormalizedMutualInformation(a, b [][]graph.Node) float64 {
+	table, sizeA, sizeB, n := contingency(a, b)
+	if n == 0 {
+		return math.NaN()
+	}
+
+	var hA, hB, mi float64
+	for _, s := range sizeA {
+		p := float64(s) / float64(n)
+		hA -= p * math.Log(p)
+	}
+	for _, s := range sizeB {
+		p := float64(s) / float64(n)
+		hB -= p * math.Log(p)
+	}
+	if hA == 0 && hB == 0 {
+		return 1
+	}
+	for k, c := range table {
+		p := float64(c) / float64(n)
+		mi += p * math.Log(p*float64(n)*float64(n)/(float64(sizeA[k[0]])*float64(sizeB[k[1]])))
+	}
+	return 2 * mi / (hA + hB)
+}
+
+// AdjustedRandIndex returns the adjusted Rand index between the partitions
+// a and b of a set of nodes as described in Hubert and Arabie
+// doi:10.1007/BF01908075. The adjusted Rand index is the Rand index corrected
+// for chance agreement between the partitions. It is one when the partitions
+// are identical and has an expected value of zero for random partitions.
+//
+// AdjustedRandIndex will panic if a and b do not partition the same set of
+// nodes or if a node is in more than one community of either partition.
+func AdjustedRandIndex(a, b [][]graph.Node) float64 {
+	table, sizeA, sizeB, n := contingency(a, b)
+	if n == 0 {
+		return math.NaN()
+	}
+
+	var index, sumA, sumB float64
+	for _, c := range table {
+		index += choose2(c)
+	}
+	for _, s := range sizeA {
+		sumA += choose2(s)
+	}
+	for _, s := range sizeB {
+		sumB += choose2(s)
+	}
+	var expected float64
+	if n > 1 {
+		expected = sumA * sumB / choose2(n)
+	}
+	max := (sumA + sumB) / 2
+	if max == expected {
+		// Both partitions are trivial.
+		return 1
+	}
+	return (index - expected) / (max - expected)
+}
+
+// contingency returns the contingency table of the partitions a and b
+// keyed by pairs of community indices into a and b, the sizes of the
+// communities of a and b and the number of partitioned nodes.
+func contingency(a, b [][]graph.Node) (table map[[2]int]int, sizeA, sizeB []int, n int) {
+	commA := membership(a)
+	commB := membership(b)
+	if len(commA) != len(commB) {
+		panic("community: partitions do not cover the same nodes")
+	}
+	table = make(map[[2]int]int)
+	sizeA = make([]int, len(a))
+	sizeB = make([]int, len(b))
+	for id, ca := range commA {
+		cb, ok := commB[id]
+		if !ok {
+			panic("community: partitions do not cover the same nodes")
+		}
+		table[[2]int{ca, cb}]++
+		sizeA[ca]++
+		sizeB[cb]++
+	}
+	return table, sizeA, sizeB, len(commA)
+}
+
+// membership returns a mapping from node IDs to community indices
+// for the partition p.
+func membership(p [][]graph.Node) map[int64]int {
+	m := make(map[int64]int)
+	for i, c := range p {
+		for _, n := range c {
+			id := n.ID()
+			if _, ok := m[id]; ok {
+				panic("community: node in more than one community")
+			}
+			m[id] = i
+		}
+	}
+	return m
+}
+
+// choose2 returns n choose 2.
+func choose2(n int) float64 {
+	return float64(n) * float64(n-1) / 2
+}
+
+// communitiesOf returns the communities of nodes given by the labels
+// of each node. The returned communities are sorted by their lowest
+// node ID and the nodes within each community are sorted by ID.
+func communitiesOf(nodes []graph.Node, labels []int) [][]graph.Node {
+	index := make(map[int]int)
+	var communities [][]graph.Node
+	for i, l := range labels {
+		j, ok := index[l]
+		if !ok {
+			j = len(communities)
+			index[l] = j
+			communities = append(communities, nil)
+		}
+		communities[j] = append(communities[j], nodes[i])
+	}
+	for _, c := range communities {
+		sort.Sort(ordered.ByID(c))
+	}
+	sort.Sort(ordered.BySliceIDs(communities))
+	return communities
+}
@@ -0,0 +1,102 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package community
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+var partitionComparisonTests = []struct {
+	name string
+	a, b []intset
+
+	wantNMI, wantARI float64
+}{
+	{
+		name:    "identical",
+		a:       []intset{linksTo(0, 1), linksTo(2, 3, 4)},
+		b:       []intset{linksTo(2, 3, 4), linksTo(0, 1)},
+		wantNMI: 1, wantARI: 1,
+	},
+	{
+		name:    "independent",
+		a:       []intset{linksTo(0, 1), linksTo(2, 3)},
+		b:       []intset{linksTo(0, 2), linksTo(1, 3)},
+		wantNMI: 0, wantARI: -0.5,
+	},
+	{
+		// Values from scikit-learn's normalized_mutual_info_score
+		// and adjusted_rand_score for [0 0 1 1] and [0 0 1 2].
+		name:    "refinement",
+		a:       []intset{linksTo(0, 1), linksTo(2, 3)},
+		b:       []intset{linksTo(0, 1), linksTo(2), linksTo(3)},
+		wantNMI: 0.8, wantARI: 0.5714285714285715,
+	},
+	{
+		name:    "single communities",
+		a:       []intset{linksTo(0, 1, 2)},
+		b:       []intset{linksTo(0, 1, 2)},
+		wantNMI: 1, wantARI: 1,
+	},
+	{
+		name:    "single and singletons",
+		a:       []intset{linksTo(0, 1, 2)},
+		b:       []intset{linksTo(0), linksTo(1), linksTo(2)},
+		wantNMI: 0, wantARI: 0,
+	},
+}
+
+func TestPartitionComparison(t *testing.T) {
+	const tol = 1e-12
+	for _, test := range partitionComparisonTests {
+		a := partitionOf(test.a)
+		b := partitionOf(test.b)
+		for _, swap := range []bool{false, true} {
+			if swap {
+				a, b = b, a
+			}
+			if got := NormalizedMutualInformation(a, b); !scalar.EqualWithinAbsOrRel(got, test.wantNMI, tol, tol) {
+				t.Errorf("unexpected NMI for %q: got:%v want:%v", test.name, got, test.wantNMI)
+			}
+			if got := AdjustedRandIndex(a, b); !scalar.EqualWithinAbsOrRel(got, test.wantARI, tol, tol) {
+				t.Errorf("unexpected ARI for %q: got:%v want:%v", test.name, got, test.wantARI)
+			}
+		}
+	}
+
+	if got := NormalizedMutualInformation(nil, nil); !math.IsNaN(got) {
+		t.Errorf("unexpected NMI for empty partitions: got:%v want:NaN", got)
+	}
+
+	for _, b := range [][]intset{
+		{linksTo(0, 1)},
+		{linksTo(0, 1, 3)},
+		{linksTo(0, 1), linksTo(1, 2)},
+	} {
+		panicked := func() (panicked bool) {
+			defer func() { panicked = recover() != nil }()
+			AdjustedRandIndex(partitionOf([]intset{linksTo(0, 1, 2)}), partitionOf(b))
+			return false
+		}()
+		if !panicked {
+			t.Errorf("expected panic for mismatched partition %v", b)
+		}
+	}
+}
+
+func partitionOf(p []intset) [][]graph.Node {
+	communities := make([][]graph.Node, len(p))
+	for i, c := range p {
+		for n := range c {
+			communities[i] = append(communities[i], simple.Node(n))
+		}
+	}
+	return communities
+}
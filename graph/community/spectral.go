@@ -0,0 +1,170 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package community
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/spectral"
+	"gonum.org/v1/gonum/mat"
+)
+
+// kMeansRestarts is the number of k-means clusterings performed
+// by Spectral, keeping the clustering with the lowest inertia.
+const kMeansRestarts = 10
+
+// Spectral returns k communities of the undirected graph g found by spectral
+// clustering. The nodes of g are embedded using the eigenvectors of the k
+// smallest eigenvalues of the symmetric normalized Laplacian of g, with each
+// embedded node normalized to unit length, and the embedding is partitioned
+// by k-means clustering as described in Ng, Jordan and Weiss "On spectral
+// clustering: Analysis and an algorithm" (2002). The best of several k-means
+// clusterings is kept. If src is nil, the global random source is used for
+// k-means initialization.
+//
+// Fewer than k communities are returned if k-means clustering yields empty
+// clusters. Spectral will panic if k is less than one or greater than the
+// number of nodes in g, or if g contains self edges.
+//
+// The returned communities are sorted by the lowest node ID in each community
+// and the nodes within each community are sorted by ID.
+func Spectral(g graph.Undirected, k int, src rand.Source) [][]graph.Node {
+	l := spectral.NewSymNormLaplacian(g)
+	n := len(l.Nodes)
+	if k < 1 || k > n {
+		panic("community: invalid number of communities")
+	}
+	rnd := randFor(src)
+
+	var eig mat.EigenSym
+	ok := eig.Factorize(l.Matrix.(mat.Symmetric), true)
+	if !ok {
+		panic("community: eigendecomposition failed")
+	}
+	var ev mat.Dense
+	eig.VectorsTo(&ev)
+
+	// Eigenvalues are returned in ascending order
+	// so the embedding is the first k columns.
+	x := make([][]float64, n)
+	for i := range x {
+		row := make([]float64, k)
+		for j := range row {
+			row[j] = ev.At(i, j)
+		}
+		if norm := floats.Norm(row, 2); norm != 0 {
+			floats.Scale(1/norm, row)
+		}
+		x[i] = row
+	}
+
+	var (
+		best    []int
+		inertia = math.Inf(1)
+	)
+	for i := 0; i < kMeansRestarts; i++ {
+		labels, d := kMeans(x, k, rnd)
+		if d < inertia {
+			best, inertia = labels, d
+		}
+	}
+	return communitiesOf(l.Nodes, best)
+}
+
+// kMeans returns the cluster labels of the points in x partitioned into k
+// clusters by Lloyd's algorithm with k-means++ initialization, and the sum
+// of squared distances from each point to its cluster center.
+func kMeans(x [][]float64, k int, rnd randSource) (labels []int, inertia float64) {
+	n := len(x)
+	dim := len(x[0])
+
+	// Choose initial centers with probability
+	// proportional to the squared distance from
+	// the nearest chosen center.
+	centers := make([][]float64, 0, k)
+	centers = append(centers, append([]float64(nil), x[rnd.Intn(n)]...))
+	dist := make([]float64, n)
+	for i := range dist {
+		dist[i] = math.Inf(1)
+	}
+	for len(centers) < k {
+		c := centers[len(centers)-1]
+		var sum float64
+		for i, p := range x {
+			if d := floats.Distance(p, c, 2); d*d < dist[i] {
+				dist[i] = d * d
+			}
+			sum += dist[i]
+		}
+		next := n - 1
+		if sum != 0 {
+			r := rnd.Float64() * sum
+			for i, d := range dist {
+				if r < d {
+					next = i
+					break
+				}
+				r -= d
+			}
+		} else {
+			next = rnd.Intn(n)
+		}
+		centers = append(centers, append([]float64(nil), x[next]...))
+	}
+
+	labels = make([]int, n)
+	for i := range labels {
+		labels[i] = -1
+	}
+	counts := make([]int, k)
+	for {
+		changed := false
+		inertia = 0
+		for i, p := range x {
+			best := 0
+			bestDist := math.Inf(1)
+			for j, c := range centers {
+				if d := floats.Distance(p, c, 2); d < bestDist {
+					best, bestDist = j, d
+				}
+			}
+			inertia += bestDist * bestDist
+			if labels[i] != best {
+				labels[i] = best
+				changed = true
+			}
+		}
+		if !changed {
+			break
+		}
+
+		for j := range centers {
+			for d := range centers[j] {
+				centers[j][d] = 0
+			}
+			counts[j] = 0
+		}
+		for i, p := range x {
+			floats.Add(centers[labels[i]], p)
+			counts[labels[i]]++
+		}
+		for j, c := range centers {
+			if counts[j] != 0 {
+				floats.Scale(1/float64(counts[j]), c)
+			} else {
+				// Move empty clusters to infinity
+				// so that they are not chosen again.
+				for d := 0; d < dim; d++ {
+					c[d] = math.Inf(1)
+				}
+			}
+		}
+	}
+	return labels, inertia
+}
@@ -0,0 +1,61 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package community
+
+import (
+	"reflect"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+func TestSpectral(t *testing.T) {
+	g := undirectedFrom(ringOfCliques)
+	var want [][]graph.Node
+	for c := 0; c < 4; c++ {
+		var comm []graph.Node
+		for i := 0; i < 5; i++ {
+			comm = append(comm, simple.Node(5*c+i))
+		}
+		want = append(want, comm)
+	}
+	for seed := uint64(1); seed <= 5; seed++ {
+		got := Spectral(g, 4, rand.NewSource(seed))
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("unexpected communities for seed %d:\ngot: %v\nwant:%v", seed, got, want)
+		}
+	}
+
+	got := Spectral(g, 1, rand.NewSource(1))
+	if len(got) != 1 {
+		t.Errorf("unexpected number of communities for k=1: got:%d want:1", len(got))
+	}
+	checkPartition(t, "ring of cliques", g, got)
+
+	// Disconnected components are separated.
+	g = undirectedFrom(twoCliques)
+	got = Spectral(g, 2, rand.NewSource(1))
+	want = [][]graph.Node{
+		{simple.Node(0), simple.Node(1), simple.Node(2), simple.Node(3)},
+		{simple.Node(4), simple.Node(5), simple.Node(6), simple.Node(7)},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected communities for two cliques:\ngot: %v\nwant:%v", got, want)
+	}
+
+	for _, k := range []int{0, g.Nodes().Len() + 1} {
+		panicked := func() (panicked bool) {
+			defer func() { panicked = recover() != nil }()
+			Spectral(g, k, nil)
+			return false
+		}()
+		if !panicked {
+			t.Errorf("expected panic for k=%d", k)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edgelist
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/encoding"
+)
+
+// EdgeListIDSetter is implemented by types that can set an edge list ID.
+type EdgeListIDSetter interface {
+	SetEdgeListID(id string)
+}
+
+// Unmarshal parses the edge list-encoded data using comma as the field
+// delimiter and stores the result in dst.
+func Unmarshal(data []byte, comma rune, dst encoding.Builder) error {
+	return unmarshal(data, comma, dst, func(from, to graph.Node) interface{} {
+		e := dst.NewEdge(from, to)
+		dst.SetEdge(e)
+		return e
+	})
+}
+
+// UnmarshalMulti parses the edge list-encoded data as a multigraph using
+// comma as the field delimiter and stores the result in dst.
+func UnmarshalMulti(data []byte, comma rune, dst encoding.MultiBuilder) error {
+	return unmarshal(data, comma, dst, func(from, to graph.Node) interface{} {
+		l := dst.NewLine(from, to)
+		dst.SetLine(l)
+		return l
+	})
+}
+
+// unmarshal parses the edge list-encoded data and stores the result in dst,
+// using setEdge to create and add edges between nodes.
+func unmarshal(data []byte, comma rune, dst graph.NodeAdder, setEdge func(from, to graph.Node) interface{}) error {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.Comma = comma
+	records, err := r.ReadAll()
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		return errors.New("edgelist: missing header")
+	}
+	header := records[0]
+	if len(header) < 2 {
+		return errors.New("edgelist: too few fields in header")
+	}
+
+	ids := make(map[string]graph.Node)
+	node := func(id string) graph.Node {
+		if n, ok := ids[id]; ok {
+			return n
+		}
+		n := dst.NewNode()
+		if n, ok := n.(EdgeListIDSetter); ok {
+			n.SetEdgeListID(id)
+		}
+		dst.AddNode(n)
+		ids[id] = n
+		return n
+	}
+
+	for i, rec := range records[1:] {
+		if rec[0] == "" {
+			return fmt.Errorf("edgelist: record %d: empty source", i+1)
+		}
+		u := node(rec[0])
+		if rec[1] == "" {
+			continue
+		}
+		e := setEdge(u, node(rec[1]))
+		s, ok := e.(encoding.AttributeSetter)
+		if !ok {
+			continue
+		}
+		for j, v := range rec[2:] {
+			if v == "" {
+				continue
+			}
+			a := encoding.Attribute{Key: header[j+2], Value: v}
+			err = s.SetAttribute(a)
+			if err != nil {
+				return fmt.Errorf("unable to unmarshal edge list attribute (%s=%s): %v", a.Key, a.Value, err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package edgelist implements delimited edge list marshaling and
+// unmarshaling of graphs, such as comma-separated (CSV) and tab-separated
+// (TSV) edge lists.
+//
+// Format
+//
+// An edge list is a header record followed by one record for each edge of
+// the graph. The first two fields of each record hold the IDs of the source
+// and target nodes of the edge, and any remaining fields hold edge attribute
+// values, keyed by the corresponding field of the header record. Nodes
+// without edges are listed with an empty target field.
+//
+// Fields are quoted as described in RFC 4180 when necessary.
+//
+// Attributes
+//
+// Edge attributes are encoded for edges that implement encoding.Attributer,
+// with the header record holding each attribute key in the order that it is
+// first seen. Empty attribute values are not distinguished from missing
+// attributes, and are not set during unmarshaling. Node attributes are not
+// represented.
+package edgelist // import "gonum.org/v1/gonum/graph/encoding/edgelist"
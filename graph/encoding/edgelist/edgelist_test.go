@@ -0,0 +1,242 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edgelist
+
+import (
+	"testing"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/encoding"
+	"gonum.org/v1/gonum/graph/multi"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+var roundTripTests = []struct {
+	name     string
+	comma    rune
+	directed bool
+	multi    bool
+	want     string
+}{
+	{
+		name:     "directed csv",
+		comma:    CSV,
+		directed: true,
+		want: `source,target,weight,label
+a,b,1.5,
+b,a,,back
+b,c,2,"a, ""quoted"" label"
+d,,,
+`,
+	},
+	{
+		name:     "undirected tsv",
+		comma:    TSV,
+		directed: false,
+		want: "source\ttarget\tlabel\n" +
+			"x y\ty\txy\n" +
+			"x y\tz\t\"a\tb\"\n" +
+			"w\t\t\n",
+	},
+	{
+		name:     "directed multigraph",
+		comma:    CSV,
+		directed: true,
+		multi:    true,
+		want: `source,target,label
+a,a,
+a,b,first
+a,b,second
+b,a,
+`,
+	},
+	{
+		name:     "undirected multigraph",
+		comma:    TSV,
+		directed: false,
+		multi:    true,
+		want: "source\ttarget\n" +
+			"a\tb\n" +
+			"a\tb\n" +
+			"c\t\n",
+	},
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, test := range roundTripTests {
+		var (
+			b   []byte
+			err error
+		)
+		if test.multi {
+			dst := newMultigraph(test.directed)
+			err = UnmarshalMulti([]byte(test.want), test.comma, dst)
+			if err != nil {
+				t.Errorf("unexpected error unmarshaling %s: %v", test.name, err)
+				continue
+			}
+			b, err = MarshalMulti(dst, test.comma)
+		} else {
+			dst := newGraph(test.directed)
+			err = Unmarshal([]byte(test.want), test.comma, dst)
+			if err != nil {
+				t.Errorf("unexpected error unmarshaling %s: %v", test.name, err)
+				continue
+			}
+			b, err = Marshal(dst, test.comma)
+		}
+		if err != nil {
+			t.Errorf("unexpected error marshaling %s: %v", test.name, err)
+			continue
+		}
+		if got := string(b); got != test.want {
+			t.Errorf("unexpected round trip result for %s:\ngot:\n%s\nwant:\n%s", test.name, got, test.want)
+		}
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	g := simple.NewDirectedGraph()
+	g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(2)})
+	g.SetEdge(simple.Edge{F: simple.Node(2), T: simple.Node(1)})
+	g.AddNode(simple.Node(3))
+
+	const want = `source,target
+0,2
+2,1
+3,
+`
+
+	b, err := Marshal(g, CSV)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := string(b); got != want {
+		t.Errorf("unexpected marshaling result:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+
+	bad := newGraph(false)
+	bad.AddNode(bad.NewNode())
+	_, err = Marshal(bad, CSV)
+	if err == nil {
+		t.Error("expected error for empty node ID")
+	}
+}
+
+var unmarshalErrorTests = []struct {
+	name string
+	data string
+}{
+	{name: "empty", data: ""},
+	{name: "short header", data: "source\n"},
+	{name: "empty source", data: "source,target\n,a\n"},
+	{name: "wrong number of fields", data: "source,target\na,b,c\n"},
+	{name: "bad quote", data: "source,target\n\"a,b\n"},
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	for _, test := range unmarshalErrorTests {
+		err := UnmarshalMulti([]byte(test.data), CSV, newMultigraph(true))
+		if err == nil {
+			t.Errorf("expected error for %s", test.name)
+		}
+	}
+}
+
+type directedGraph struct {
+	*simple.DirectedGraph
+	attributes
+}
+
+func (g *directedGraph) NewNode() graph.Node {
+	return &testNode{Node: g.DirectedGraph.NewNode()}
+}
+
+func (g *directedGraph) NewEdge(from, to graph.Node) graph.Edge {
+	return &testEdge{Edge: g.DirectedGraph.NewEdge(from, to)}
+}
+
+type undirectedGraph struct {
+	*simple.UndirectedGraph
+	attributes
+}
+
+func (g *undirectedGraph) NewNode() graph.Node {
+	return &testNode{Node: g.UndirectedGraph.NewNode()}
+}
+
+func (g *undirectedGraph) NewEdge(from, to graph.Node) graph.Edge {
+	return &testEdge{Edge: g.UndirectedGraph.NewEdge(from, to)}
+}
+
+func newGraph(directed bool) encoding.Builder {
+	if directed {
+		return &directedGraph{DirectedGraph: simple.NewDirectedGraph()}
+	}
+	return &undirectedGraph{UndirectedGraph: simple.NewUndirectedGraph()}
+}
+
+type directedMultigraph struct {
+	*multi.DirectedGraph
+	attributes
+}
+
+func (g *directedMultigraph) NewNode() graph.Node {
+	return &testNode{Node: g.DirectedGraph.NewNode()}
+}
+
+func (g *directedMultigraph) NewLine(from, to graph.Node) graph.Line {
+	return &testLine{Line: g.DirectedGraph.NewLine(from, to)}
+}
+
+type undirectedMultigraph struct {
+	*multi.UndirectedGraph
+	attributes
+}
+
+func (g *undirectedMultigraph) NewNode() graph.Node {
+	return &testNode{Node: g.UndirectedGraph.NewNode()}
+}
+
+func (g *undirectedMultigraph) NewLine(from, to graph.Node) graph.Line {
+	return &testLine{Line: g.UndirectedGraph.NewLine(from, to)}
+}
+
+func newMultigraph(directed bool) encoding.MultiBuilder {
+	if directed {
+		return &directedMultigraph{DirectedGraph: multi.NewDirectedGraph()}
+	}
+	return &undirectedMultigraph{UndirectedGraph: multi.NewUndirectedGraph()}
+}
+
+type testNode struct {
+	id string
+	graph.Node
+	attributes
+}
+
+func (n *testNode) EdgeListID() string      { return n.id }
+func (n *testNode) SetEdgeListID(id string) { n.id = id }
+
+type testEdge struct {
+	graph.Edge
+	attributes
+}
+
+type testLine struct {
+	graph.Line
+	attributes
+}
+
+// attributes is a helper for graph, node and edge attributes.
+type attributes []encoding.Attribute
+
+func (a attributes) Attributes() []encoding.Attribute {
+	return []encoding.Attribute(a)
+}
+func (a *attributes) SetAttribute(attr encoding.Attribute) error {
+	*a = append(*a, attr)
+	return nil
+}
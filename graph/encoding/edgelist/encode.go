@@ -0,0 +1,220 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edgelist
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/encoding"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+)
+
+// Field delimiters for common edge list formats.
+const (
+	CSV = ','  // Comma-separated values.
+	TSV = '\t' // Tab-separated values.
+)
+
+// Node is an edge list graph node.
+type Node interface {
+	// EdgeListID returns an edge list node ID.
+	// The returned ID must not be empty.
+	EdgeListID() string
+}
+
+// Marshal returns the edge list encoding for the graph g using comma as the
+// field delimiter.
+//
+// Graph serialization will work for a graph.Graph without modification,
+// however, node IDs and edge attributes are only encoded for values
+// implementing the Node and encoding.Attributer interfaces. Nodes that do not
+// implement Node are identified by their graph.Node ID.
+func Marshal(g graph.Graph, comma rune) ([]byte, error) {
+	var p printer
+	nodes := graph.NodesOf(g.Nodes())
+	sort.Sort(ordered.ByID(nodes))
+
+	_, isDirected := g.(graph.Directed)
+	visited := make(map[[2]int64]bool)
+	for _, n := range nodes {
+		nid := n.ID()
+		to := graph.NodesOf(g.From(nid))
+		sort.Sort(ordered.ByID(to))
+		if len(to) == 0 && !hasEdgesTo(g, nid) {
+			err := p.addNode(n)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		for _, t := range to {
+			tid := t.ID()
+			if visited[[2]int64{nid, tid}] {
+				continue
+			}
+			visited[[2]int64{nid, tid}] = true
+			if !isDirected {
+				visited[[2]int64{tid, nid}] = true
+			}
+			err := p.addEdge(n, t, g.Edge(nid, tid))
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return p.write(comma)
+}
+
+// MarshalMulti returns the edge list encoding for the multigraph g using comma
+// as the field delimiter.
+//
+// Graph serialization will work for a graph.Multigraph without modification,
+// however, node IDs and line attributes are only encoded for values
+// implementing the Node and encoding.Attributer interfaces. Nodes that do not
+// implement Node are identified by their graph.Node ID.
+func MarshalMulti(g graph.Multigraph, comma rune) ([]byte, error) {
+	var p printer
+	nodes := graph.NodesOf(g.Nodes())
+	sort.Sort(ordered.ByID(nodes))
+
+	visited := make(map[int64]bool)
+	for _, n := range nodes {
+		nid := n.ID()
+		to := graph.NodesOf(g.From(nid))
+		sort.Sort(ordered.ByID(to))
+		if len(to) == 0 && !hasEdgesTo(g, nid) {
+			err := p.addNode(n)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		for _, t := range to {
+			tid := t.ID()
+			lines := graph.LinesOf(g.Lines(nid, tid))
+			sort.Sort(ordered.LinesByIDs(lines))
+			for _, l := range lines {
+				lid := l.ID()
+				if visited[lid] {
+					continue
+				}
+				visited[lid] = true
+				err := p.addEdge(n, t, l)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return p.write(comma)
+}
+
+// hasEdgesTo returns whether the node with ID id has edges leading to
+// it in g if g is directed.
+func hasEdgesTo(g interface{}, id int64) bool {
+	d, ok := g.(interface{ To(int64) graph.Nodes })
+	return ok && d.To(id).Len() != 0
+}
+
+// printer accumulates the records of an edge list.
+type printer struct {
+	keys    []string
+	column  map[string]int
+	records [][]string
+}
+
+// addNode adds a record for the isolated node n.
+func (p *printer) addNode(n graph.Node) error {
+	id, err := nodeID(n)
+	if err != nil {
+		return err
+	}
+	p.records = append(p.records, []string{id, ""})
+	return nil
+}
+
+// addEdge adds a record for the edge or line e from the node from to the
+// node to.
+func (p *printer) addEdge(from, to graph.Node, e interface{}) error {
+	if p.column == nil {
+		p.column = make(map[string]int)
+	}
+	fid, err := nodeID(from)
+	if err != nil {
+		return err
+	}
+	tid, err := nodeID(to)
+	if err != nil {
+		return err
+	}
+	rec := []string{fid, tid}
+	if a, ok := e.(encoding.Attributer); ok {
+		seen := make(map[string]bool)
+		for _, attr := range a.Attributes() {
+			if seen[attr.Key] {
+				return fmt.Errorf("edgelist: duplicate edge attribute key %q", attr.Key)
+			}
+			seen[attr.Key] = true
+			c, ok := p.column[attr.Key]
+			if !ok {
+				c = len(p.keys) + 2
+				p.column[attr.Key] = c
+				p.keys = append(p.keys, attr.Key)
+			}
+			for len(rec) <= c {
+				rec = append(rec, "")
+			}
+			rec[c] = attr.Value
+		}
+	}
+	p.records = append(p.records, rec)
+	return nil
+}
+
+// write returns the edge list encoding of the accumulated records.
+func (p *printer) write(comma rune) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Comma = comma
+	n := len(p.keys) + 2
+	err := w.Write(append([]string{"source", "target"}, p.keys...))
+	if err != nil {
+		return nil, err
+	}
+	for _, rec := range p.records {
+		for len(rec) < n {
+			rec = append(rec, "")
+		}
+		err = w.Write(rec)
+		if err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	err = w.Error()
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// nodeID returns the edge list ID of n.
+func nodeID(n graph.Node) (string, error) {
+	switch n := n.(type) {
+	case Node:
+		id := n.EdgeListID()
+		if id == "" {
+			return "", errors.New("edgelist: empty node ID")
+		}
+		return id, nil
+	default:
+		return fmt.Sprint(n.ID()), nil
+	}
+}
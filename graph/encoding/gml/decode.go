@@ -0,0 +1,332 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gml
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"strconv"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/encoding"
+)
+
+// Unmarshal parses the GML-encoded data and stores the result in dst.
+// If the number of graphs encoded in data is not one, an error is returned and
+// dst will hold the first graph in data.
+func Unmarshal(data []byte, dst encoding.Builder) error {
+	return unmarshal(data, dst, func(from, to graph.Node) interface{} {
+		e := dst.NewEdge(from, to)
+		dst.SetEdge(e)
+		return e
+	})
+}
+
+// UnmarshalMulti parses the GML-encoded data as a multigraph and stores the
+// result in dst.
+// If the number of graphs encoded in data is not one, an error is returned and
+// dst will hold the first graph in data.
+func UnmarshalMulti(data []byte, dst encoding.MultiBuilder) error {
+	return unmarshal(data, dst, func(from, to graph.Node) interface{} {
+		l := dst.NewLine(from, to)
+		dst.SetLine(l)
+		return l
+	})
+}
+
+// unmarshal parses the GML-encoded data and stores the result in dst,
+// using setEdge to create and add edges between nodes.
+func unmarshal(data []byte, dst graph.NodeAdder, setEdge func(from, to graph.Node) interface{}) error {
+	p := parser{data: data, line: 1}
+	file, err := p.parseList(false)
+	if err != nil {
+		return err
+	}
+	var graphs []pair
+	for _, kv := range file {
+		if kv.key == "graph" {
+			if kv.kind != listKind {
+				return errors.New("gml: graph is not a list")
+			}
+			graphs = append(graphs, kv)
+		}
+	}
+	if len(graphs) == 0 {
+		return errors.New("gml: no graph")
+	}
+	err = copyGraph(dst, graphs[0].list, setEdge)
+	if err == nil && len(graphs) != 1 {
+		err = fmt.Errorf("invalid number of graphs; expected 1, got %d", len(graphs))
+	}
+	return err
+}
+
+// copyGraph copies the nodes and edges from the GML graph list src to
+// the destination graph.
+func copyGraph(dst graph.NodeAdder, src []pair, setEdge func(from, to graph.Node) interface{}) error {
+	var attrs []pair
+	ids := make(map[int64]graph.Node)
+	for _, kv := range src {
+		switch kv.key {
+		case "directed", "multigraph":
+			// Graph structure is determined by dst.
+		case "node":
+			if kv.kind != listKind {
+				return errors.New("gml: node is not a list")
+			}
+			id, rest, err := intValues(kv.list, "node", "id")
+			if err != nil {
+				return err
+			}
+			if _, exists := ids[id[0]]; exists {
+				return fmt.Errorf("gml: duplicate node id %d", id[0])
+			}
+			n := dst.NewNode()
+			dst.AddNode(n)
+			ids[id[0]] = n
+			err = setAttributes(n, "node", rest)
+			if err != nil {
+				return err
+			}
+		case "edge":
+			if kv.kind != listKind {
+				return errors.New("gml: edge is not a list")
+			}
+		default:
+			attrs = append(attrs, kv)
+		}
+	}
+	err := setAttributes(dst, "graph", attrs)
+	if err != nil {
+		return err
+	}
+
+	// Edges are added after all nodes since GML
+	// does not require nodes to precede edges.
+	for _, kv := range src {
+		if kv.key != "edge" {
+			continue
+		}
+		ends, rest, err := intValues(kv.list, "edge", "source", "target")
+		if err != nil {
+			return err
+		}
+		from, ok := ids[ends[0]]
+		if !ok {
+			return fmt.Errorf("gml: edge source %d not in graph", ends[0])
+		}
+		to, ok := ids[ends[1]]
+		if !ok {
+			return fmt.Errorf("gml: edge target %d not in graph", ends[1])
+		}
+		err = setAttributes(setEdge(from, to), "edge", rest)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// intValues returns the integer values for each of the keys in the GML list,
+// and the remaining pairs of the list. Each key must appear exactly once.
+func intValues(list []pair, level string, keys ...string) (values []int64, rest []pair, err error) {
+	values = make([]int64, len(keys))
+	found := make([]bool, len(keys))
+outer:
+	for _, kv := range list {
+		for i, k := range keys {
+			if kv.key != k {
+				continue
+			}
+			if found[i] {
+				return nil, nil, fmt.Errorf("gml: duplicate %s %s", level, k)
+			}
+			found[i] = true
+			if kv.kind != integerKind {
+				return nil, nil, fmt.Errorf("gml: %s %s is not an integer", level, k)
+			}
+			values[i], err = strconv.ParseInt(kv.value, 10, 64)
+			if err != nil {
+				return nil, nil, fmt.Errorf("gml: invalid %s %s: %v", level, k, err)
+			}
+			continue outer
+		}
+		rest = append(rest, kv)
+	}
+	for i, k := range keys {
+		if !found[i] {
+			return nil, nil, fmt.Errorf("gml: missing %s %s", level, k)
+		}
+	}
+	return values, rest, nil
+}
+
+// setAttributes sets the attributes held in list on dst if dst is an
+// encoding.AttributeSetter. Nested lists are flattened with their keys
+// joined by '.'.
+func setAttributes(dst interface{}, level string, list []pair) error {
+	s, ok := dst.(encoding.AttributeSetter)
+	if !ok {
+		return nil
+	}
+	return setList(s, level, "", list)
+}
+
+func setList(dst encoding.AttributeSetter, level, prefix string, list []pair) error {
+	for _, kv := range list {
+		key := prefix + kv.key
+		if kv.kind == listKind {
+			err := setList(dst, level, key+".", kv.list)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		a := encoding.Attribute{Key: key, Value: kv.value}
+		err := dst.SetAttribute(a)
+		if err != nil {
+			return fmt.Errorf("unable to unmarshal %s GML attribute (%s=%s): %v", level, a.Key, a.Value, err)
+		}
+	}
+	return nil
+}
+
+// pair is a GML key-value pair. If the value is a list, kind
+// is listKind and value is empty.
+type pair struct {
+	key   string
+	kind  kind
+	value string
+	list  []pair
+}
+
+// kind is the kind of a GML value.
+type kind int
+
+const (
+	stringKind kind = iota
+	integerKind
+	realKind
+	listKind
+)
+
+// parser is a GML parser.
+type parser struct {
+	data []byte
+	pos  int
+	line int
+}
+
+// parseList parses a GML list. If nested is true, the list must
+// be terminated by a ']', otherwise it must be terminated by
+// the end of the data.
+func (p *parser) parseList(nested bool) ([]pair, error) {
+	var list []pair
+	for {
+		p.skipSpace()
+		if p.pos == len(p.data) {
+			if nested {
+				return nil, p.errorf("unexpected end of data")
+			}
+			return list, nil
+		}
+		if p.data[p.pos] == ']' {
+			if !nested {
+				return nil, p.errorf("unexpected ']'")
+			}
+			p.pos++
+			return list, nil
+		}
+
+		key := p.scan(isKeyByte)
+		if !isKey(key) {
+			return nil, p.errorf("invalid key %q", key)
+		}
+		p.skipSpace()
+		if p.pos == len(p.data) {
+			return nil, p.errorf("missing value for key %q", key)
+		}
+		kv := pair{key: key}
+		switch c := p.data[p.pos]; {
+		case c == '[':
+			p.pos++
+			l, err := p.parseList(true)
+			if err != nil {
+				return nil, err
+			}
+			kv.kind = listKind
+			kv.list = l
+		case c == '"':
+			p.pos++
+			start := p.pos
+			for p.pos < len(p.data) && p.data[p.pos] != '"' {
+				if p.data[p.pos] == '\n' {
+					p.line++
+				}
+				p.pos++
+			}
+			if p.pos == len(p.data) {
+				return nil, p.errorf("unterminated string")
+			}
+			kv.kind = stringKind
+			kv.value = html.UnescapeString(string(p.data[start:p.pos]))
+			p.pos++
+		default:
+			v := p.scan(isNumberByte)
+			switch {
+			case reInteger.MatchString(v):
+				kv.kind = integerKind
+			case isNumber(v):
+				kv.kind = realKind
+			default:
+				return nil, p.errorf("invalid value for key %q", key)
+			}
+			kv.value = v
+		}
+		list = append(list, kv)
+	}
+}
+
+// skipSpace skips white space and comments.
+func (p *parser) skipSpace() {
+	for p.pos < len(p.data) {
+		switch p.data[p.pos] {
+		case '\n':
+			p.line++
+		case ' ', '\t', '\r':
+		case '#':
+			for p.pos < len(p.data) && p.data[p.pos] != '\n' {
+				p.pos++
+			}
+			continue
+		default:
+			return
+		}
+		p.pos++
+	}
+}
+
+// scan returns the run of bytes satisfying fn starting at the
+// current position.
+func (p *parser) scan(fn func(byte) bool) string {
+	start := p.pos
+	for p.pos < len(p.data) && fn(p.data[p.pos]) {
+		p.pos++
+	}
+	return string(p.data[start:p.pos])
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("gml: line %d: %s", p.line, fmt.Sprintf(format, args...))
+}
+
+func isKeyByte(c byte) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
+
+func isNumberByte(c byte) bool {
+	return c == '+' || c == '-' || c == '.' || c == 'e' || c == 'E' || ('0' <= c && c <= '9')
+}
@@ -0,0 +1,29 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package gml implements Graph Modelling Language (GML) marshaling and
+// unmarshaling of graphs.
+//
+// See M. Himsolt, "GML: A portable Graph File Format", Universität Passau,
+// for the specification of the GML format.
+//
+// Node identity
+//
+// Nodes are identified in GML by their integer id key. During marshaling,
+// the graph.Node ID is used as the GML node id. During unmarshaling, nodes
+// are created with the destination's NewNode method, so GML node ids are
+// not retained.
+//
+// Attributes
+//
+// Node, edge and graph attributes are encoded as GML key-value pairs. Values
+// that are GML integers or reals are encoded unquoted and all other values
+// are encoded as GML strings with '&' and '"' characters escaped as HTML
+// entities. Nested GML lists are represented as attributes with keys formed
+// by joining the keys of each level of the list with a '.', so the x value in
+//
+//  graphics [ x 1.0 ]
+//
+// is represented by the attribute graphics.x=1.0.
+package gml // import "gonum.org/v1/gonum/graph/encoding/gml"
@@ -0,0 +1,306 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gml
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/encoding"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+)
+
+// Marshal returns the GML encoding for the graph g, applying the prefix and
+// indent to the encoding. Name is used to specify the graph label. If name is
+// empty, no label is written for the graph.
+//
+// Graph serialization will work for a graph.Graph without modification,
+// however, attributes are only encoded for the graph, nodes and edges that
+// implement encoding.Attributer.
+func Marshal(g graph.Graph, name, prefix, indent string) ([]byte, error) {
+	p := printer{prefix: prefix, indent: indent}
+	_, isDirected := g.(graph.Directed)
+	err := p.printFrontMatter(g, name, isDirected, false)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := graph.NodesOf(g.Nodes())
+	sort.Sort(ordered.ByID(nodes))
+	for _, n := range nodes {
+		err = p.printNode(n)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	visited := make(map[[2]int64]bool)
+	for _, n := range nodes {
+		nid := n.ID()
+		to := graph.NodesOf(g.From(nid))
+		sort.Sort(ordered.ByID(to))
+		for _, t := range to {
+			tid := t.ID()
+			if visited[[2]int64{nid, tid}] {
+				continue
+			}
+			visited[[2]int64{nid, tid}] = true
+			if !isDirected {
+				visited[[2]int64{tid, nid}] = true
+			}
+			err = p.printEdge(nid, tid, g.Edge(nid, tid))
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	p.closeBlock("]")
+	return p.buf.Bytes(), nil
+}
+
+// MarshalMulti returns the GML encoding for the multigraph g, applying the
+// prefix and indent to the encoding. Name is used to specify the graph label.
+// If name is empty, no label is written for the graph.
+//
+// Graph serialization will work for a graph.Multigraph without modification,
+// however, attributes are only encoded for the graph, nodes and lines that
+// implement encoding.Attributer.
+func MarshalMulti(g graph.Multigraph, name, prefix, indent string) ([]byte, error) {
+	p := printer{prefix: prefix, indent: indent}
+	_, isDirected := g.(graph.Directed)
+	err := p.printFrontMatter(g, name, isDirected, true)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := graph.NodesOf(g.Nodes())
+	sort.Sort(ordered.ByID(nodes))
+	for _, n := range nodes {
+		err = p.printNode(n)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	visited := make(map[int64]bool)
+	for _, n := range nodes {
+		nid := n.ID()
+		to := graph.NodesOf(g.From(nid))
+		sort.Sort(ordered.ByID(to))
+		for _, t := range to {
+			tid := t.ID()
+			lines := graph.LinesOf(g.Lines(nid, tid))
+			sort.Sort(ordered.LinesByIDs(lines))
+			for _, l := range lines {
+				lid := l.ID()
+				if visited[lid] {
+					continue
+				}
+				visited[lid] = true
+				err = p.printEdge(nid, tid, l)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	p.closeBlock("]")
+	return p.buf.Bytes(), nil
+}
+
+// reserved holds the keys used to encode graph structure
+// at each level of a GML graph.
+var reserved = map[string][]string{
+	"graph": {"directed", "multigraph", "node", "edge"},
+	"node":  {"id"},
+	"edge":  {"source", "target"},
+}
+
+type printer struct {
+	buf bytes.Buffer
+
+	prefix string
+	indent string
+	depth  int
+}
+
+func (p *printer) printFrontMatter(g interface{}, name string, isDirected, isMulti bool) error {
+	p.buf.WriteString(p.prefix)
+	p.openBlock("graph [")
+	p.newline()
+	p.buf.WriteString("directed ")
+	p.writeBool(isDirected)
+	if isMulti {
+		p.newline()
+		p.buf.WriteString("multigraph 1")
+	}
+	if name != "" {
+		p.newline()
+		p.buf.WriteString("label ")
+		p.buf.WriteString(quote(name))
+	}
+	if a, ok := g.(encoding.Attributer); ok {
+		return p.writeAttributes("graph", a.Attributes())
+	}
+	return nil
+}
+
+func (p *printer) printNode(n graph.Node) error {
+	p.newline()
+	p.openBlock("node [")
+	p.newline()
+	fmt.Fprintf(&p.buf, "id %d", n.ID())
+	if a, ok := n.(encoding.Attributer); ok {
+		err := p.writeAttributes("node", a.Attributes())
+		if err != nil {
+			return err
+		}
+	}
+	p.closeBlock("]")
+	return nil
+}
+
+func (p *printer) printEdge(from, to int64, e interface{}) error {
+	p.newline()
+	p.openBlock("edge [")
+	p.newline()
+	fmt.Fprintf(&p.buf, "source %d", from)
+	p.newline()
+	fmt.Fprintf(&p.buf, "target %d", to)
+	if a, ok := e.(encoding.Attributer); ok {
+		err := p.writeAttributes("edge", a.Attributes())
+		if err != nil {
+			return err
+		}
+	}
+	p.closeBlock("]")
+	return nil
+}
+
+func (p *printer) writeBool(b bool) {
+	if b {
+		p.buf.WriteByte('1')
+	} else {
+		p.buf.WriteByte('0')
+	}
+}
+
+// writeAttributes writes the attributes of a graph, node or edge,
+// writing attributes with '.'-separated keys as nested lists.
+func (p *printer) writeAttributes(level string, attrs []encoding.Attribute) error {
+	for _, a := range attrs {
+		for _, k := range reserved[level] {
+			if a.Key == k {
+				return fmt.Errorf("gml: reserved %s attribute key %q", level, a.Key)
+			}
+		}
+	}
+	return p.writeList(attrs, 0)
+}
+
+// writeList writes the attributes as GML key-value pairs, using the
+// '.'-separated elements of the attribute keys from index depth.
+// Attributes sharing a key element are written together in the order
+// the key element first appears in attrs.
+func (p *printer) writeList(attrs []encoding.Attribute, depth int) error {
+	var (
+		keys   []string
+		groups = make(map[string][]encoding.Attribute)
+	)
+	for _, a := range attrs {
+		path := strings.Split(a.Key, ".")
+		k := path[depth]
+		if !isKey(k) {
+			return fmt.Errorf("gml: invalid attribute key %q", a.Key)
+		}
+		if _, ok := groups[k]; !ok {
+			keys = append(keys, k)
+		}
+		groups[k] = append(groups[k], a)
+	}
+
+	for _, k := range keys {
+		for _, a := range groups[k] {
+			if strings.Count(a.Key, ".") != depth {
+				continue
+			}
+			p.newline()
+			p.buf.WriteString(k)
+			p.buf.WriteByte(' ')
+			p.buf.WriteString(quote(a.Value))
+		}
+
+		var sub []encoding.Attribute
+		for _, a := range groups[k] {
+			if strings.Count(a.Key, ".") != depth {
+				sub = append(sub, a)
+			}
+		}
+		if len(sub) == 0 {
+			continue
+		}
+		p.newline()
+		p.openBlock(k + " [")
+		err := p.writeList(sub, depth+1)
+		if err != nil {
+			return err
+		}
+		p.closeBlock("]")
+	}
+	return nil
+}
+
+func (p *printer) newline() {
+	p.buf.WriteByte('\n')
+	p.buf.WriteString(p.prefix)
+	for i := 0; i < p.depth; i++ {
+		p.buf.WriteString(p.indent)
+	}
+}
+
+func (p *printer) openBlock(b string) {
+	p.buf.WriteString(b)
+	p.depth++
+}
+
+func (p *printer) closeBlock(b string) {
+	p.depth--
+	p.newline()
+	p.buf.WriteString(b)
+}
+
+// Regular expressions to match GML keys and numbers.
+var (
+	reKey     = regexp.MustCompile(`^[a-zA-Z_][0-9a-zA-Z_]*$`)
+	reInteger = regexp.MustCompile(`^[-+]?[0-9]+$`)
+	reReal    = regexp.MustCompile(`^[-+]?[0-9]*\.[0-9]*([eE][-+]?[0-9]+)?$`)
+)
+
+// isKey reports whether s is a valid GML key.
+func isKey(s string) bool {
+	return reKey.MatchString(s)
+}
+
+// isNumber reports whether s is a GML integer or real.
+func isNumber(s string) bool {
+	return reInteger.MatchString(s) || (reReal.MatchString(s) && strings.ContainsAny(s, "0123456789"))
+}
+
+// quote returns s as a GML value. If s is a number it is returned
+// unaltered, otherwise it is quoted with '&' and '"' escaped.
+func quote(s string) string {
+	if isNumber(s) {
+		return s
+	}
+	return `"` + escaper.Replace(s) + `"`
+}
+
+var escaper = strings.NewReplacer("&", "&amp;", `"`, "&quot;")
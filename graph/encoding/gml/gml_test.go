@@ -0,0 +1,381 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gml
+
+import (
+	"reflect"
+	"testing"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/encoding"
+	"gonum.org/v1/gonum/graph/multi"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+var roundTripTests = []struct {
+	name     string
+	directed bool
+	multi    bool
+	want     string
+}{
+	{
+		name:     "directed",
+		directed: true,
+		want: `graph [
+	directed 1
+	label "a &quot;test&quot; &amp; graph"
+	node [
+		id 0
+		label "A"
+		graphics [
+			x 1.5
+			y -2
+			fill "#ff0000"
+		]
+	]
+	node [
+		id 1
+		label "B"
+	]
+	node [
+		id 2
+	]
+	edge [
+		source 0
+		target 1
+		weight 1.5
+	]
+	edge [
+		source 1
+		target 0
+	]
+	edge [
+		source 1
+		target 2
+		weight 2
+	]
+]`,
+	},
+	{
+		name:     "undirected",
+		directed: false,
+		want: `graph [
+	directed 0
+	node [
+		id 0
+	]
+	node [
+		id 1
+	]
+	node [
+		id 2
+	]
+	edge [
+		source 0
+		target 1
+		label "xy"
+	]
+	edge [
+		source 0
+		target 2
+	]
+]`,
+	},
+	{
+		name:     "directed multigraph",
+		directed: true,
+		multi:    true,
+		want: `graph [
+	directed 1
+	multigraph 1
+	node [
+		id 0
+	]
+	node [
+		id 1
+	]
+	edge [
+		source 0
+		target 0
+	]
+	edge [
+		source 0
+		target 1
+		label "first"
+	]
+	edge [
+		source 0
+		target 1
+		label "second"
+	]
+	edge [
+		source 1
+		target 0
+	]
+]`,
+	},
+	{
+		name:     "undirected multigraph",
+		directed: false,
+		multi:    true,
+		want: `graph [
+	directed 0
+	multigraph 1
+	node [
+		id 0
+	]
+	node [
+		id 1
+	]
+	edge [
+		source 0
+		target 1
+	]
+	edge [
+		source 0
+		target 1
+	]
+]`,
+	},
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, test := range roundTripTests {
+		var (
+			b   []byte
+			err error
+		)
+		if test.multi {
+			dst := newMultigraph(test.directed)
+			err = UnmarshalMulti([]byte(test.want), dst)
+			if err != nil {
+				t.Errorf("unexpected error unmarshaling %s: %v", test.name, err)
+				continue
+			}
+			b, err = MarshalMulti(dst, "", "", "\t")
+		} else {
+			dst := newGraph(test.directed)
+			err = Unmarshal([]byte(test.want), dst)
+			if err != nil {
+				t.Errorf("unexpected error unmarshaling %s: %v", test.name, err)
+				continue
+			}
+			b, err = Marshal(dst, "", "", "\t")
+		}
+		if err != nil {
+			t.Errorf("unexpected error marshaling %s: %v", test.name, err)
+			continue
+		}
+		if got := string(b); got != test.want {
+			t.Errorf("unexpected round trip result for %s:\ngot:\n%s\nwant:\n%s", test.name, got, test.want)
+		}
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	g := simple.NewDirectedGraph()
+	g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(2)})
+	g.SetEdge(simple.Edge{F: simple.Node(2), T: simple.Node(1)})
+
+	const want = `> graph [
+>   directed 1
+>   label "G"
+>   node [
+>     id 0
+>   ]
+>   node [
+>     id 1
+>   ]
+>   node [
+>     id 2
+>   ]
+>   edge [
+>     source 0
+>     target 2
+>   ]
+>   edge [
+>     source 2
+>     target 1
+>   ]
+> ]`
+
+	b, err := Marshal(g, "G", "> ", "  ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := string(b); got != want {
+		t.Errorf("unexpected marshaling result:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+
+	_, err = Marshal(newGraph(false), "", "", "")
+	if err != nil {
+		t.Errorf("unexpected error marshaling empty graph: %v", err)
+	}
+	bad := newGraph(false).(*undirectedGraph)
+	bad.attributes = attributes{{Key: "node", Value: "x"}}
+	_, err = Marshal(bad, "", "", "")
+	if err == nil {
+		t.Error("expected error for reserved attribute key")
+	}
+	bad.attributes = attributes{{Key: "a b", Value: "x"}}
+	_, err = Marshal(bad, "", "", "")
+	if err == nil {
+		t.Error("expected error for invalid attribute key")
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	const data = `# A comment.
+Creator "test"
+graph [ directed 0 label "G"
+	edge [ target 3 source 1 ]
+	node [ id 1 label "one" ]
+	node [ id 3 # trailing comment
+		label "three
+lines" ]
+]`
+
+	dst := newGraph(false).(*undirectedGraph)
+	err := Unmarshal([]byte(data), dst)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := (attributes{{Key: "label", Value: "G"}}); !reflect.DeepEqual(dst.attributes, want) {
+		t.Errorf("unexpected graph attributes: got:%v want:%v", dst.attributes, want)
+	}
+	labels := make(map[string]bool)
+	nodes := dst.Nodes()
+	for nodes.Next() {
+		n := nodes.Node().(*testNode)
+		for _, a := range n.attributes {
+			labels[a.Value] = true
+		}
+	}
+	if want := map[string]bool{"one": true, "three\nlines": true}; !reflect.DeepEqual(labels, want) {
+		t.Errorf("unexpected node labels: got:%v want:%v", labels, want)
+	}
+	if dst.Edges().Len() != 1 {
+		t.Errorf("unexpected number of edges: got:%d want:1", dst.Edges().Len())
+	}
+}
+
+var unmarshalErrorTests = []struct {
+	name string
+	data string
+}{
+	{name: "no graph", data: `Creator "test"`},
+	{name: "two graphs", data: `graph [ ] graph [ ]`},
+	{name: "graph not list", data: `graph 1`},
+	{name: "unterminated list", data: `graph [ node [ id 0 ]`},
+	{name: "unexpected close", data: `graph [ ] ]`},
+	{name: "unterminated string", data: `graph [ label "G ]`},
+	{name: "invalid value", data: `graph [ label G ]`},
+	{name: "missing node id", data: `graph [ node [ label "a" ] ]`},
+	{name: "non-integer node id", data: `graph [ node [ id 1.0 ] ]`},
+	{name: "duplicate node id", data: `graph [ node [ id 0 ] node [ id 0 ] ]`},
+	{name: "missing node", data: `graph [ node [ id 0 ] edge [ source 0 target 1 ] ]`},
+	{name: "missing target", data: `graph [ node [ id 0 ] edge [ source 0 ] ]`},
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	for _, test := range unmarshalErrorTests {
+		err := UnmarshalMulti([]byte(test.data), newMultigraph(true))
+		if err == nil {
+			t.Errorf("expected error for %s", test.name)
+		}
+	}
+}
+
+type directedGraph struct {
+	*simple.DirectedGraph
+	attributes
+}
+
+func (g *directedGraph) NewNode() graph.Node {
+	return &testNode{Node: g.DirectedGraph.NewNode()}
+}
+
+func (g *directedGraph) NewEdge(from, to graph.Node) graph.Edge {
+	return &testEdge{Edge: g.DirectedGraph.NewEdge(from, to)}
+}
+
+type undirectedGraph struct {
+	*simple.UndirectedGraph
+	attributes
+}
+
+func (g *undirectedGraph) NewNode() graph.Node {
+	return &testNode{Node: g.UndirectedGraph.NewNode()}
+}
+
+func (g *undirectedGraph) NewEdge(from, to graph.Node) graph.Edge {
+	return &testEdge{Edge: g.UndirectedGraph.NewEdge(from, to)}
+}
+
+func newGraph(directed bool) encoding.Builder {
+	if directed {
+		return &directedGraph{DirectedGraph: simple.NewDirectedGraph()}
+	}
+	return &undirectedGraph{UndirectedGraph: simple.NewUndirectedGraph()}
+}
+
+type directedMultigraph struct {
+	*multi.DirectedGraph
+	attributes
+}
+
+func (g *directedMultigraph) NewNode() graph.Node {
+	return &testNode{Node: g.DirectedGraph.NewNode()}
+}
+
+func (g *directedMultigraph) NewLine(from, to graph.Node) graph.Line {
+	return &testLine{Line: g.DirectedGraph.NewLine(from, to)}
+}
+
+type undirectedMultigraph struct {
+	*multi.UndirectedGraph
+	attributes
+}
+
+func (g *undirectedMultigraph) NewNode() graph.Node {
+	return &testNode{Node: g.UndirectedGraph.NewNode()}
+}
+
+func (g *undirectedMultigraph) NewLine(from, to graph.Node) graph.Line {
+	return &testLine{Line: g.UndirectedGraph.NewLine(from, to)}
+}
+
+func newMultigraph(directed bool) encoding.MultiBuilder {
+	if directed {
+		return &directedMultigraph{DirectedGraph: multi.NewDirectedGraph()}
+	}
+	return &undirectedMultigraph{UndirectedGraph: multi.NewUndirectedGraph()}
+}
+
+type testNode struct {
+	graph.Node
+	attributes
+}
+
+type testEdge struct {
+	graph.Edge
+	attributes
+}
+
+type testLine struct {
+	graph.Line
+	attributes
+}
+
+// attributes is a helper for graph, node and edge attributes.
+type attributes []encoding.Attribute
+
+func (a attributes) Attributes() []encoding.Attribute {
+	return []encoding.Attribute(a)
+}
+func (a *attributes) SetAttribute(attr encoding.Attribute) error {
+	*a = append(*a, attr)
+	return nil
+}
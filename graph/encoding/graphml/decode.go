@@ -0,0 +1,182 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package graphml
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/encoding"
+)
+
+// GraphMLIDSetter is implemented by types that can set a GraphML ID.
+type GraphMLIDSetter interface {
+	SetGraphMLID(id string)
+}
+
+// Unmarshal parses the GraphML-encoded data and stores the result in dst.
+// If the number of graphs encoded in data is not one, an error is returned and
+// dst will hold the first graph in data.
+func Unmarshal(data []byte, dst encoding.Builder) error {
+	return unmarshal(data, dst, func(from, to graph.Node) interface{} {
+		e := dst.NewEdge(from, to)
+		dst.SetEdge(e)
+		return e
+	})
+}
+
+// UnmarshalMulti parses the GraphML-encoded data as a multigraph and stores
+// the result in dst.
+// If the number of graphs encoded in data is not one, an error is returned and
+// dst will hold the first graph in data.
+func UnmarshalMulti(data []byte, dst encoding.MultiBuilder) error {
+	return unmarshal(data, dst, func(from, to graph.Node) interface{} {
+		l := dst.NewLine(from, to)
+		dst.SetLine(l)
+		return l
+	})
+}
+
+// unmarshal parses the GraphML-encoded data and stores the result in dst,
+// using setEdge to create and add edges between nodes.
+func unmarshal(data []byte, dst graph.NodeAdder, setEdge func(from, to graph.Node) interface{}) error {
+	var doc document
+	err := xml.Unmarshal(data, &doc)
+	if err != nil {
+		return err
+	}
+	if len(doc.Graphs) == 0 {
+		return errors.New("graphml: no graph")
+	}
+
+	dec := decoder{keys: doc.Keys, index: make(map[string]int, len(doc.Keys))}
+	for i, k := range doc.Keys {
+		dec.index[k.ID] = i
+	}
+	err = dec.copyGraph(dst, doc.Graphs[0], setEdge)
+	if err == nil && len(doc.Graphs) != 1 {
+		err = fmt.Errorf("invalid number of graphs; expected 1, got %d", len(doc.Graphs))
+	}
+	return err
+}
+
+// decoder holds the GraphML key declarations of a document.
+type decoder struct {
+	keys  []key
+	index map[string]int
+}
+
+// copyGraph copies the nodes and edges from the GraphML graph element
+// src to the destination graph.
+func (dec decoder) copyGraph(dst graph.NodeAdder, src graphElem, setEdge func(from, to graph.Node) interface{}) error {
+	if len(src.Hyperedges) != 0 {
+		return errors.New("graphml: hyperedges not supported")
+	}
+	var directed bool
+	switch src.EdgeDefault {
+	case "directed":
+		directed = true
+	case "undirected":
+	default:
+		return fmt.Errorf("graphml: invalid edge default %q", src.EdgeDefault)
+	}
+
+	if dst, ok := dst.(GraphMLIDSetter); ok {
+		dst.SetGraphMLID(src.ID)
+	}
+	err := dec.setAttributes(dst, "graph", src.Data)
+	if err != nil {
+		return err
+	}
+
+	ids := make(map[string]graph.Node, len(src.Nodes))
+	for _, n := range src.Nodes {
+		if len(n.Graphs) != 0 {
+			return errors.New("graphml: nested graphs not supported")
+		}
+		if _, exists := ids[n.ID]; exists {
+			return fmt.Errorf("graphml: duplicate node ID %q", n.ID)
+		}
+		u := dst.NewNode()
+		if u, ok := u.(GraphMLIDSetter); ok {
+			u.SetGraphMLID(n.ID)
+		}
+		dst.AddNode(u)
+		ids[n.ID] = u
+		err = dec.setAttributes(u, "node", n.Data)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, e := range src.Edges {
+		switch e.Directed {
+		case "":
+		case "true":
+			if !directed {
+				return errors.New("graphml: mixed graphs not supported")
+			}
+		case "false":
+			if directed {
+				return errors.New("graphml: mixed graphs not supported")
+			}
+		default:
+			return fmt.Errorf("graphml: invalid edge directed value %q", e.Directed)
+		}
+		from, ok := ids[e.Source]
+		if !ok {
+			return fmt.Errorf("graphml: edge source %q not in graph", e.Source)
+		}
+		to, ok := ids[e.Target]
+		if !ok {
+			return fmt.Errorf("graphml: edge target %q not in graph", e.Target)
+		}
+		err = dec.setAttributes(setEdge(from, to), "edge", e.Data)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setAttributes sets the attributes held in d and the defaults of the keys
+// for the domain that are not held in d on dst if dst is an
+// encoding.AttributeSetter.
+func (dec decoder) setAttributes(dst interface{}, domain string, d []data) error {
+	s, ok := dst.(encoding.AttributeSetter)
+	if !ok {
+		return nil
+	}
+	have := make(map[string]bool, len(d))
+	for _, v := range d {
+		i, ok := dec.index[v.Key]
+		if !ok {
+			return fmt.Errorf("graphml: undeclared key %q", v.Key)
+		}
+		k := dec.keys[i]
+		if !k.isFor(domain) {
+			return fmt.Errorf("graphml: key %q not valid for %s", v.Key, domain)
+		}
+		have[v.Key] = true
+		a := encoding.Attribute{Key: k.attrName(), Value: v.Value}
+		err := s.SetAttribute(a)
+		if err != nil {
+			return fmt.Errorf("unable to unmarshal %s GraphML attribute (%s=%s): %v", domain, a.Key, a.Value, err)
+		}
+	}
+	for _, k := range dec.keys {
+		if k.Default == nil || have[k.ID] || !k.isFor(domain) {
+			continue
+		}
+		a := encoding.Attribute{Key: k.attrName(), Value: *k.Default}
+		err := s.SetAttribute(a)
+		if err != nil {
+			return fmt.Errorf("unable to unmarshal default %s GraphML attribute (%s=%s): %v", domain, a.Key, a.Value, err)
+		}
+	}
+	return nil
+}
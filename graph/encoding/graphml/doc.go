@@ -0,0 +1,24 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package graphml implements GraphML marshaling and unmarshaling of graphs.
+//
+// See the GraphML Primer and specification for more information on the
+// GraphML format:
+//
+// GraphML Primer: http://graphml.graphdrawing.org/primer/graphml-primer.html
+//
+// GraphML specification: http://graphml.graphdrawing.org/specification.html
+//
+// Attributes
+//
+// Node, edge and graph attributes are encoded as GraphML data elements with
+// string typed keys. Keys are declared for each attribute name used by the
+// graph, its nodes and its edges during marshaling. During unmarshaling, data
+// values and key defaults are passed to the graph, node and edge values that
+// implement encoding.AttributeSetter using the name of the key as the
+// attribute key.
+//
+// Nested graphs, hyperedges, ports and mixed graphs are not supported.
+package graphml // import "gonum.org/v1/gonum/graph/encoding/graphml"
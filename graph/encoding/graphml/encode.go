@@ -0,0 +1,212 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package graphml
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/encoding"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+)
+
+// Node is a GraphML graph node.
+type Node interface {
+	// GraphMLID returns a GraphML node ID.
+	GraphMLID() string
+}
+
+// Graph wraps named graph.Graph values.
+type Graph interface {
+	graph.Graph
+	GraphMLID() string
+}
+
+// Multigraph wraps named graph.Multigraph values.
+type Multigraph interface {
+	graph.Multigraph
+	GraphMLID() string
+}
+
+// Marshal returns the GraphML encoding for the graph g, applying the prefix
+// and indent to the encoding. Name is used to specify the graph ID. If name
+// is empty and g implements Graph, the returned string from GraphMLID will be
+// used.
+//
+// Graph serialization will work for a graph.Graph without modification,
+// however, node IDs and attributes are only encoded for values implementing
+// the Node, Graph and encoding.Attributer interfaces. Nodes that do not
+// implement Node are given the ID "n" followed by their graph.Node ID.
+func Marshal(g graph.Graph, name, prefix, indent string) ([]byte, error) {
+	if name == "" {
+		if g, ok := g.(Graph); ok {
+			name = g.GraphMLID()
+		}
+	}
+
+	var e encoder
+	elem := e.graph(g, name)
+	nodes := graph.NodesOf(g.Nodes())
+	sort.Sort(ordered.ByID(nodes))
+	for _, n := range nodes {
+		elem.Nodes = append(elem.Nodes, e.node(n))
+	}
+
+	_, isDirected := g.(graph.Directed)
+	visited := make(map[[2]int64]bool)
+	for _, n := range nodes {
+		nid := n.ID()
+		to := graph.NodesOf(g.From(nid))
+		sort.Sort(ordered.ByID(to))
+		for _, t := range to {
+			tid := t.ID()
+			if visited[[2]int64{nid, tid}] {
+				continue
+			}
+			visited[[2]int64{nid, tid}] = true
+			if !isDirected {
+				visited[[2]int64{tid, nid}] = true
+			}
+			elem.Edges = append(elem.Edges, e.edge(n, t, g.Edge(nid, tid)))
+		}
+	}
+
+	return e.marshal(elem, prefix, indent)
+}
+
+// MarshalMulti returns the GraphML encoding for the multigraph g, applying
+// the prefix and indent to the encoding. Name is used to specify the graph ID.
+// If name is empty and g implements Multigraph, the returned string from
+// GraphMLID will be used.
+//
+// Graph serialization will work for a graph.Multigraph without modification,
+// however, node IDs and attributes are only encoded for values implementing
+// the Node, Multigraph and encoding.Attributer interfaces. Nodes that do not
+// implement Node are given the ID "n" followed by their graph.Node ID.
+func MarshalMulti(g graph.Multigraph, name, prefix, indent string) ([]byte, error) {
+	if name == "" {
+		if g, ok := g.(Multigraph); ok {
+			name = g.GraphMLID()
+		}
+	}
+
+	var e encoder
+	elem := e.graph(g, name)
+	nodes := graph.NodesOf(g.Nodes())
+	sort.Sort(ordered.ByID(nodes))
+	for _, n := range nodes {
+		elem.Nodes = append(elem.Nodes, e.node(n))
+	}
+
+	visited := make(map[int64]bool)
+	for _, n := range nodes {
+		nid := n.ID()
+		to := graph.NodesOf(g.From(nid))
+		sort.Sort(ordered.ByID(to))
+		for _, t := range to {
+			tid := t.ID()
+			lines := graph.LinesOf(g.Lines(nid, tid))
+			sort.Sort(ordered.LinesByIDs(lines))
+			for _, l := range lines {
+				lid := l.ID()
+				if visited[lid] {
+					continue
+				}
+				visited[lid] = true
+				elem.Edges = append(elem.Edges, e.edge(n, t, l))
+			}
+		}
+	}
+
+	return e.marshal(elem, prefix, indent)
+}
+
+// encoder accumulates the GraphML keys required to
+// encode the attributes of a graph.
+type encoder struct {
+	ids  map[[2]string]string
+	keys []key
+}
+
+// graph returns a graph element for g with the given ID.
+func (e *encoder) graph(g interface{}, id string) graphElem {
+	elem := graphElem{ID: id, EdgeDefault: "undirected"}
+	if _, ok := g.(graph.Directed); ok {
+		elem.EdgeDefault = "directed"
+	}
+	if a, ok := g.(encoding.Attributer); ok {
+		elem.Data = e.data("graph", a)
+	}
+	return elem
+}
+
+// node returns a node element for n.
+func (e *encoder) node(n graph.Node) node {
+	elem := node{ID: nodeID(n)}
+	if a, ok := n.(encoding.Attributer); ok {
+		elem.Data = e.data("node", a)
+	}
+	return elem
+}
+
+// edge returns an edge element joining from and to for the edge or line, l.
+func (e *encoder) edge(from, to graph.Node, l interface{}) edge {
+	elem := edge{Source: nodeID(from), Target: nodeID(to)}
+	if a, ok := l.(encoding.Attributer); ok {
+		elem.Data = e.data("edge", a)
+	}
+	return elem
+}
+
+// data returns the data elements for the attributes of a, declaring
+// keys for the attributes in the given domain if necessary.
+func (e *encoder) data(domain string, a encoding.Attributer) []data {
+	attrs := a.Attributes()
+	if len(attrs) == 0 {
+		return nil
+	}
+	if e.ids == nil {
+		e.ids = make(map[[2]string]string)
+	}
+	d := make([]data, len(attrs))
+	for i, attr := range attrs {
+		k := [2]string{domain, attr.Key}
+		id, ok := e.ids[k]
+		if !ok {
+			id = fmt.Sprintf("d%d", len(e.keys))
+			e.ids[k] = id
+			e.keys = append(e.keys, key{ID: id, For: domain, Name: attr.Key, Type: "string"})
+		}
+		d[i] = data{Key: id, Value: attr.Value}
+	}
+	return d
+}
+
+// marshal returns the GraphML encoding of a document holding the
+// graph element and the keys used by e.
+func (e *encoder) marshal(elem graphElem, prefix, indent string) ([]byte, error) {
+	doc := document{XMLNS: namespace, Keys: e.keys, Graphs: []graphElem{elem}}
+	b, err := xml.MarshalIndent(doc, prefix, indent)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	buf.WriteString(prefix)
+	buf.WriteString(xml.Header)
+	buf.Write(b)
+	return buf.Bytes(), nil
+}
+
+func nodeID(n graph.Node) string {
+	switch n := n.(type) {
+	case Node:
+		return n.GraphMLID()
+	default:
+		return fmt.Sprintf("n%d", n.ID())
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package graphml
+
+import "encoding/xml"
+
+// namespace is the GraphML XML namespace.
+const namespace = "http://graphml.graphdrawing.org/xmlns"
+
+// document is a GraphML document.
+type document struct {
+	XMLName xml.Name    `xml:"graphml"`
+	XMLNS   string      `xml:"xmlns,attr,omitempty"`
+	Keys    []key       `xml:"key"`
+	Graphs  []graphElem `xml:"graph"`
+}
+
+// key is a GraphML attribute declaration.
+type key struct {
+	ID      string  `xml:"id,attr"`
+	For     string  `xml:"for,attr,omitempty"`
+	Name    string  `xml:"attr.name,attr,omitempty"`
+	Type    string  `xml:"attr.type,attr,omitempty"`
+	Default *string `xml:"default"`
+}
+
+// isFor returns whether the key applies to the given domain.
+func (k key) isFor(domain string) bool {
+	return k.For == "" || k.For == "all" || k.For == domain
+}
+
+// attrName returns the attribute name of the key, or the
+// key ID if the key is unnamed.
+func (k key) attrName() string {
+	if k.Name == "" {
+		return k.ID
+	}
+	return k.Name
+}
+
+// graphElem is a GraphML graph element.
+type graphElem struct {
+	XMLName     xml.Name    `xml:"graph"`
+	ID          string      `xml:"id,attr,omitempty"`
+	EdgeDefault string      `xml:"edgedefault,attr"`
+	Data        []data      `xml:"data"`
+	Nodes       []node      `xml:"node"`
+	Edges       []edge      `xml:"edge"`
+	Hyperedges  []hyperedge `xml:"hyperedge"`
+}
+
+// node is a GraphML node element.
+type node struct {
+	ID     string      `xml:"id,attr"`
+	Data   []data      `xml:"data"`
+	Graphs []graphElem `xml:"graph"`
+}
+
+// edge is a GraphML edge element.
+type edge struct {
+	ID       string `xml:"id,attr,omitempty"`
+	Directed string `xml:"directed,attr,omitempty"`
+	Source   string `xml:"source,attr"`
+	Target   string `xml:"target,attr"`
+	Data     []data `xml:"data"`
+}
+
+// hyperedge is a GraphML hyperedge element. Hyperedges
+// are not supported, but are recorded to allow their
+// presence to be reported.
+type hyperedge struct{}
+
+// data is a GraphML data element.
+type data struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
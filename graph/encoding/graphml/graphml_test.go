@@ -0,0 +1,357 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package graphml
+
+import (
+	"testing"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/encoding"
+	"gonum.org/v1/gonum/graph/multi"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+var roundTripTests = []struct {
+	name     string
+	directed bool
+	multi    bool
+	want     string
+}{
+	{
+		name:     "directed",
+		directed: true,
+		want: `<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+	<key id="d0" for="graph" attr.name="label" attr.type="string"></key>
+	<key id="d1" for="node" attr.name="color" attr.type="string"></key>
+	<key id="d2" for="node" attr.name="label" attr.type="string"></key>
+	<key id="d3" for="edge" attr.name="weight" attr.type="string"></key>
+	<graph id="G" edgedefault="directed">
+		<data key="d0">a &lt;test&gt; graph</data>
+		<node id="a">
+			<data key="d1">red</data>
+			<data key="d2">A</data>
+		</node>
+		<node id="b">
+			<data key="d2">B</data>
+		</node>
+		<node id="c"></node>
+		<edge source="a" target="b">
+			<data key="d3">1.5</data>
+		</edge>
+		<edge source="b" target="a"></edge>
+		<edge source="b" target="c">
+			<data key="d3">2</data>
+		</edge>
+	</graph>
+</graphml>`,
+	},
+	{
+		name:     "undirected",
+		directed: false,
+		want: `<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+	<key id="d0" for="edge" attr.name="label" attr.type="string"></key>
+	<graph id="U" edgedefault="undirected">
+		<node id="x"></node>
+		<node id="y"></node>
+		<node id="z"></node>
+		<edge source="x" target="y">
+			<data key="d0">xy</data>
+		</edge>
+		<edge source="x" target="z"></edge>
+	</graph>
+</graphml>`,
+	},
+	{
+		name:     "directed multigraph",
+		directed: true,
+		multi:    true,
+		want: `<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+	<key id="d0" for="edge" attr.name="label" attr.type="string"></key>
+	<graph id="M" edgedefault="directed">
+		<node id="a"></node>
+		<node id="b"></node>
+		<edge source="a" target="a"></edge>
+		<edge source="a" target="b">
+			<data key="d0">first</data>
+		</edge>
+		<edge source="a" target="b">
+			<data key="d0">second</data>
+		</edge>
+		<edge source="b" target="a"></edge>
+	</graph>
+</graphml>`,
+	},
+	{
+		name:     "undirected multigraph",
+		directed: false,
+		multi:    true,
+		want: `<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+	<graph edgedefault="undirected">
+		<node id="a"></node>
+		<node id="b"></node>
+		<edge source="a" target="b"></edge>
+		<edge source="a" target="b"></edge>
+		<edge source="a" target="b"></edge>
+	</graph>
+</graphml>`,
+	},
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, test := range roundTripTests {
+		var (
+			b   []byte
+			err error
+		)
+		if test.multi {
+			dst := newMultigraph(test.directed)
+			err = UnmarshalMulti([]byte(test.want), dst)
+			if err != nil {
+				t.Errorf("unexpected error unmarshaling %s: %v", test.name, err)
+				continue
+			}
+			b, err = MarshalMulti(dst, "", "", "\t")
+		} else {
+			dst := newGraph(test.directed)
+			err = Unmarshal([]byte(test.want), dst)
+			if err != nil {
+				t.Errorf("unexpected error unmarshaling %s: %v", test.name, err)
+				continue
+			}
+			b, err = Marshal(dst, "", "", "\t")
+		}
+		if err != nil {
+			t.Errorf("unexpected error marshaling %s: %v", test.name, err)
+			continue
+		}
+		if got := string(b); got != test.want {
+			t.Errorf("unexpected round trip result for %s:\ngot:\n%s\nwant:\n%s", test.name, got, test.want)
+		}
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	g := simple.NewUndirectedGraph()
+	g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1)})
+	g.SetEdge(simple.Edge{F: simple.Node(2), T: simple.Node(1)})
+	g.AddNode(simple.Node(3))
+
+	const want = `> <?xml version="1.0" encoding="UTF-8"?>
+> <graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+>   <graph id="g" edgedefault="undirected">
+>     <node id="n0"></node>
+>     <node id="n1"></node>
+>     <node id="n2"></node>
+>     <node id="n3"></node>
+>     <edge source="n0" target="n1"></edge>
+>     <edge source="n1" target="n2"></edge>
+>   </graph>
+> </graphml>`
+
+	b, err := Marshal(g, "g", "> ", "  ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := string(b); got != want {
+		t.Errorf("unexpected marshaling result:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestUnmarshalDefaults(t *testing.T) {
+	const data = `<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+	<key id="c" for="node" attr.name="color" attr.type="string">
+		<default>yellow</default>
+	</key>
+	<key id="w" for="edge" attr.name="weight" attr.type="double"/>
+	<graph id="G" edgedefault="undirected">
+		<node id="n0">
+			<data key="c">green</data>
+		</node>
+		<node id="n1"/>
+		<edge id="e0" source="n0" target="n1">
+			<data key="w">1.0</data>
+		</edge>
+	</graph>
+</graphml>`
+
+	dst := newGraph(false).(*undirectedGraph)
+	err := Unmarshal([]byte(data), dst)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]string{"n0": "green", "n1": "yellow"}
+	nodes := dst.Nodes()
+	for nodes.Next() {
+		n := nodes.Node().(*testNode)
+		if len(n.attributes) != 1 || n.attributes[0] != (encoding.Attribute{Key: "color", Value: want[n.id]}) {
+			t.Errorf("unexpected attributes for node %s: got:%v want color=%s", n.id, n.attributes, want[n.id])
+		}
+	}
+	edges := dst.Edges()
+	for edges.Next() {
+		e := edges.Edge().(*testEdge)
+		if len(e.attributes) != 1 || e.attributes[0] != (encoding.Attribute{Key: "weight", Value: "1.0"}) {
+			t.Errorf("unexpected attributes for edge: got:%v want weight=1.0", e.attributes)
+		}
+	}
+}
+
+var unmarshalErrorTests = []struct {
+	name string
+	data string
+}{
+	{
+		name: "no graph",
+		data: `<graphml></graphml>`,
+	},
+	{
+		name: "two graphs",
+		data: `<graphml><graph edgedefault="directed"/><graph edgedefault="directed"/></graphml>`,
+	},
+	{
+		name: "duplicate node",
+		data: `<graphml><graph edgedefault="directed"><node id="a"/><node id="a"/></graph></graphml>`,
+	},
+	{
+		name: "missing node",
+		data: `<graphml><graph edgedefault="directed"><node id="a"/><edge source="a" target="b"/></graph></graphml>`,
+	},
+	{
+		name: "mixed graph",
+		data: `<graphml><graph edgedefault="directed"><node id="a"/><edge source="a" target="a" directed="false"/></graph></graphml>`,
+	},
+	{
+		name: "undeclared key",
+		data: `<graphml><graph edgedefault="directed"><node id="a"><data key="d0">x</data></node></graph></graphml>`,
+	},
+	{
+		name: "nested graph",
+		data: `<graphml><graph edgedefault="directed"><node id="a"><graph edgedefault="directed"/></node></graph></graphml>`,
+	},
+	{
+		name: "hyperedge",
+		data: `<graphml><graph edgedefault="directed"><node id="a"/><hyperedge><endpoint node="a"/></hyperedge></graph></graphml>`,
+	},
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	for _, test := range unmarshalErrorTests {
+		err := UnmarshalMulti([]byte(test.data), newMultigraph(true))
+		if err == nil {
+			t.Errorf("expected error for %s", test.name)
+		}
+	}
+}
+
+// graphMLGraph is a graph with GraphML IDs and attributes that
+// creates user-defined nodes and edges.
+type graphMLGraph struct {
+	id string
+	attributes
+}
+
+func (g *graphMLGraph) GraphMLID() string      { return g.id }
+func (g *graphMLGraph) SetGraphMLID(id string) { g.id = id }
+
+type directedGraph struct {
+	*simple.DirectedGraph
+	graphMLGraph
+}
+
+func (g *directedGraph) NewNode() graph.Node {
+	return &testNode{Node: g.DirectedGraph.NewNode()}
+}
+
+func (g *directedGraph) NewEdge(from, to graph.Node) graph.Edge {
+	return &testEdge{Edge: g.DirectedGraph.NewEdge(from, to)}
+}
+
+type undirectedGraph struct {
+	*simple.UndirectedGraph
+	graphMLGraph
+}
+
+func (g *undirectedGraph) NewNode() graph.Node {
+	return &testNode{Node: g.UndirectedGraph.NewNode()}
+}
+
+func (g *undirectedGraph) NewEdge(from, to graph.Node) graph.Edge {
+	return &testEdge{Edge: g.UndirectedGraph.NewEdge(from, to)}
+}
+
+func newGraph(directed bool) encoding.Builder {
+	if directed {
+		return &directedGraph{DirectedGraph: simple.NewDirectedGraph()}
+	}
+	return &undirectedGraph{UndirectedGraph: simple.NewUndirectedGraph()}
+}
+
+type directedMultigraph struct {
+	*multi.DirectedGraph
+	graphMLGraph
+}
+
+func (g *directedMultigraph) NewNode() graph.Node {
+	return &testNode{Node: g.DirectedGraph.NewNode()}
+}
+
+func (g *directedMultigraph) NewLine(from, to graph.Node) graph.Line {
+	return &testLine{Line: g.DirectedGraph.NewLine(from, to)}
+}
+
+type undirectedMultigraph struct {
+	*multi.UndirectedGraph
+	graphMLGraph
+}
+
+func (g *undirectedMultigraph) NewNode() graph.Node {
+	return &testNode{Node: g.UndirectedGraph.NewNode()}
+}
+
+func (g *undirectedMultigraph) NewLine(from, to graph.Node) graph.Line {
+	return &testLine{Line: g.UndirectedGraph.NewLine(from, to)}
+}
+
+func newMultigraph(directed bool) encoding.MultiBuilder {
+	if directed {
+		return &directedMultigraph{DirectedGraph: multi.NewDirectedGraph()}
+	}
+	return &undirectedMultigraph{UndirectedGraph: multi.NewUndirectedGraph()}
+}
+
+type testNode struct {
+	graph.Node
+	id string
+	attributes
+}
+
+func (n *testNode) GraphMLID() string      { return n.id }
+func (n *testNode) SetGraphMLID(id string) { n.id = id }
+
+type testEdge struct {
+	graph.Edge
+	attributes
+}
+
+type testLine struct {
+	graph.Line
+	attributes
+}
+
+// attributes is a helper for graph, node and edge attributes.
+type attributes []encoding.Attribute
+
+func (a attributes) Attributes() []encoding.Attribute {
+	return []encoding.Attribute(a)
+}
+func (a *attributes) SetAttribute(attr encoding.Attribute) error {
+	*a = append(*a, attr)
+	return nil
+}
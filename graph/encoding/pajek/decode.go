@@ -0,0 +1,293 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pajek
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/encoding"
+)
+
+// Unmarshal parses the Pajek-encoded data and stores the result in dst.
+// Edges listed in *Edges sections are added to directed destinations with
+// their listed orientation. Arcs are not permitted when dst is undirected.
+func Unmarshal(data []byte, dst encoding.Builder) error {
+	return unmarshal(data, dst, func(from, to graph.Node) interface{} {
+		e := dst.NewEdge(from, to)
+		dst.SetEdge(e)
+		return e
+	})
+}
+
+// UnmarshalMulti parses the Pajek-encoded data as a multigraph and stores the
+// result in dst.
+// Edges listed in *Edges sections are added to directed destinations with
+// their listed orientation. Arcs are not permitted when dst is undirected.
+func UnmarshalMulti(data []byte, dst encoding.MultiBuilder) error {
+	return unmarshal(data, dst, func(from, to graph.Node) interface{} {
+		l := dst.NewLine(from, to)
+		dst.SetLine(l)
+		return l
+	})
+}
+
+// section is a Pajek network file section.
+type section int
+
+const (
+	none section = iota
+	vertices
+	arcs
+	edges
+	arcsList
+	edgesList
+)
+
+// unmarshal parses the Pajek-encoded data and stores the result in dst,
+// using setEdge to create and add edges between nodes.
+func unmarshal(data []byte, dst graph.NodeAdder, setEdge func(from, to graph.Node) interface{}) error {
+	_, isDirected := dst.(graph.Directed)
+
+	var (
+		nodes   []graph.Node
+		current = none
+	)
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	sc.Buffer(nil, len(data)+1)
+	for line := 1; sc.Scan(); line++ {
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || text[0] == '%' {
+			continue
+		}
+
+		if text[0] == '*' {
+			fields := strings.Fields(text)
+			switch strings.ToLower(fields[0]) {
+			case "*network":
+				if nodes != nil {
+					return fmt.Errorf("pajek: line %d: unexpected network", line)
+				}
+				name := strings.TrimSpace(text[len(fields[0]):])
+				if s, ok := dst.(encoding.AttributeSetter); ok && name != "" {
+					a := encoding.Attribute{Key: "name", Value: name}
+					err := s.SetAttribute(a)
+					if err != nil {
+						return fmt.Errorf("unable to unmarshal graph Pajek attribute (%s=%s): %v", a.Key, a.Value, err)
+					}
+				}
+				current = none
+			case "*vertices":
+				if nodes != nil {
+					return fmt.Errorf("pajek: line %d: duplicate vertices section", line)
+				}
+				if len(fields) < 2 {
+					return fmt.Errorf("pajek: line %d: missing number of vertices", line)
+				}
+				n, err := strconv.Atoi(fields[1])
+				if err != nil || n < 0 {
+					return fmt.Errorf("pajek: line %d: invalid number of vertices %q", line, fields[1])
+				}
+				nodes = make([]graph.Node, n)
+				for i := range nodes {
+					nodes[i] = dst.NewNode()
+					dst.AddNode(nodes[i])
+				}
+				current = vertices
+			case "*arcs":
+				if !isDirected {
+					return fmt.Errorf("pajek: line %d: arcs in undirected graph", line)
+				}
+				current = arcs
+			case "*arcslist":
+				if !isDirected {
+					return fmt.Errorf("pajek: line %d: arcs in undirected graph", line)
+				}
+				current = arcsList
+			case "*edges":
+				current = edges
+			case "*edgeslist":
+				current = edgesList
+			default:
+				return fmt.Errorf("pajek: line %d: unsupported section %s", line, fields[0])
+			}
+			if current != none && nodes == nil {
+				return fmt.Errorf("pajek: line %d: section before vertices", line)
+			}
+			continue
+		}
+
+		toks, err := tokenize(text)
+		if err != nil {
+			return fmt.Errorf("pajek: line %d: %v", line, err)
+		}
+		switch current {
+		case none:
+			return fmt.Errorf("pajek: line %d: data outside section", line)
+		case vertices:
+			u, err := vertex(nodes, toks[0])
+			if err != nil {
+				return fmt.Errorf("pajek: line %d: %v", line, err)
+			}
+			err = setVertexAttributes(u, toks[1:])
+			if err != nil {
+				return fmt.Errorf("pajek: line %d: %v", line, err)
+			}
+		case arcs, edges:
+			if len(toks) < 2 {
+				return fmt.Errorf("pajek: line %d: missing edge end point", line)
+			}
+			u, err := vertex(nodes, toks[0])
+			if err != nil {
+				return fmt.Errorf("pajek: line %d: %v", line, err)
+			}
+			v, err := vertex(nodes, toks[1])
+			if err != nil {
+				return fmt.Errorf("pajek: line %d: %v", line, err)
+			}
+			err = setEdgeAttributes(setEdge(u, v), toks[2:])
+			if err != nil {
+				return fmt.Errorf("pajek: line %d: %v", line, err)
+			}
+		case arcsList, edgesList:
+			u, err := vertex(nodes, toks[0])
+			if err != nil {
+				return fmt.Errorf("pajek: line %d: %v", line, err)
+			}
+			for _, t := range toks[1:] {
+				v, err := vertex(nodes, t)
+				if err != nil {
+					return fmt.Errorf("pajek: line %d: %v", line, err)
+				}
+				setEdge(u, v)
+			}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return err
+	}
+	if nodes == nil {
+		return errors.New("pajek: no vertices")
+	}
+	return nil
+}
+
+// token is a white space separated Pajek token.
+type token struct {
+	text   string
+	quoted bool
+}
+
+// tokenize splits text into white space separated tokens, treating
+// double quoted text as a single token.
+func tokenize(text string) ([]token, error) {
+	var toks []token
+	for {
+		text = strings.TrimLeftFunc(text, unicode.IsSpace)
+		if text == "" {
+			return toks, nil
+		}
+		if text[0] == '"' {
+			end := strings.IndexByte(text[1:], '"')
+			if end < 0 {
+				return nil, errors.New("unterminated string")
+			}
+			toks = append(toks, token{text: text[1 : end+1], quoted: true})
+			text = text[end+2:]
+			continue
+		}
+		end := strings.IndexFunc(text, unicode.IsSpace)
+		if end < 0 {
+			end = len(text)
+		}
+		toks = append(toks, token{text: text[:end]})
+		text = text[end:]
+	}
+}
+
+// vertex returns the node corresponding to the Pajek vertex number in t.
+func vertex(nodes []graph.Node, t token) (graph.Node, error) {
+	i, err := strconv.Atoi(t.text)
+	if err != nil || t.quoted {
+		return nil, fmt.Errorf("invalid vertex number %q", t.text)
+	}
+	if i < 1 || len(nodes) < i {
+		return nil, fmt.Errorf("vertex number %d out of range", i)
+	}
+	return nodes[i-1], nil
+}
+
+// isNumber reports whether t is an unquoted number.
+func isNumber(t token) bool {
+	if t.quoted {
+		return false
+	}
+	_, err := strconv.ParseFloat(t.text, 64)
+	return err == nil
+}
+
+// setVertexAttributes sets the attributes of the vertex parameters held
+// in toks on dst if dst is an encoding.AttributeSetter.
+func setVertexAttributes(dst interface{}, toks []token) error {
+	s, ok := dst.(encoding.AttributeSetter)
+	if !ok || len(toks) == 0 {
+		return nil
+	}
+	attrs := []encoding.Attribute{{Key: "label", Value: toks[0].text}}
+	toks = toks[1:]
+	for _, k := range []string{"x", "y", "z"} {
+		if len(toks) == 0 || !isNumber(toks[0]) {
+			break
+		}
+		attrs = append(attrs, encoding.Attribute{Key: k, Value: toks[0].text})
+		toks = toks[1:]
+	}
+	return setAttributes(s, "vertex", attrs, toks)
+}
+
+// setEdgeAttributes sets the attributes of the edge parameters held
+// in toks on dst if dst is an encoding.AttributeSetter.
+func setEdgeAttributes(dst interface{}, toks []token) error {
+	s, ok := dst.(encoding.AttributeSetter)
+	if !ok {
+		return nil
+	}
+	var attrs []encoding.Attribute
+	if len(toks) != 0 && isNumber(toks[0]) {
+		attrs = append(attrs, encoding.Attribute{Key: "weight", Value: toks[0].text})
+		toks = toks[1:]
+	}
+	return setAttributes(s, "edge", attrs, toks)
+}
+
+// setAttributes sets the positional attributes and then the keyword-value
+// pairs held in toks on dst.
+func setAttributes(dst encoding.AttributeSetter, kind string, attrs []encoding.Attribute, toks []token) error {
+	for len(toks) != 0 {
+		if !toks[0].quoted && shapes[toks[0].text] {
+			attrs = append(attrs, encoding.Attribute{Key: "shape", Value: toks[0].text})
+			toks = toks[1:]
+			continue
+		}
+		if len(toks) < 2 {
+			return fmt.Errorf("missing value for %s parameter %q", kind, toks[0].text)
+		}
+		attrs = append(attrs, encoding.Attribute{Key: toks[0].text, Value: toks[1].text})
+		toks = toks[2:]
+	}
+	for _, a := range attrs {
+		err := dst.SetAttribute(a)
+		if err != nil {
+			return fmt.Errorf("unable to unmarshal %s Pajek attribute (%s=%s): %v", kind, a.Key, a.Value, err)
+		}
+	}
+	return nil
+}
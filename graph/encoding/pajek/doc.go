@@ -0,0 +1,28 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package pajek implements Pajek .net marshaling and unmarshaling of graphs.
+//
+// See the Pajek manual for more information on the Pajek network format.
+//
+// Vertices
+//
+// Pajek vertices are numbered from 1 to n. During marshaling, nodes are
+// numbered in order of ascending graph.Node ID. During unmarshaling, nodes
+// are created with the destination's NewNode method, so Pajek vertex numbers
+// are not retained.
+//
+// Attributes
+//
+// Vertex labels and coordinates and edge weights are positional in the Pajek
+// format. They are represented by the "label", "x", "y" and "z" vertex
+// attributes and the "weight" edge attribute. The vertex shape keywords
+// ellipse, box, diamond, triangle, cross and empty are represented by the
+// "shape" vertex attribute. All other attributes are encoded as Pajek
+// keyword-value pairs following the positional values. The name of the
+// network is represented by the "name" graph attribute.
+//
+// Edge weights are taken from graph.WeightedEdge and graph.WeightedLine
+// values during marshaling when no "weight" attribute is present.
+package pajek // import "gonum.org/v1/gonum/graph/encoding/pajek"
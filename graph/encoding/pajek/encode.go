@@ -0,0 +1,318 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pajek
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/encoding"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+)
+
+// Marshal returns the Pajek encoding for the graph g. Name is used to specify
+// the network name. If name is empty and g implements encoding.Attributer,
+// the value of its "name" attribute will be used.
+//
+// Graph serialization will work for a graph.Graph without modification,
+// however, vertex and edge parameters are only encoded for nodes and edges
+// that implement encoding.Attributer. Nodes with attributes but without a
+// "label" attribute are labeled with their graph.Node ID.
+func Marshal(g graph.Graph, name string) ([]byte, error) {
+	var p printer
+	nodes, err := p.printVertices(g, g.Nodes(), name)
+	if err != nil {
+		return nil, err
+	}
+
+	_, isDirected := g.(graph.Directed)
+	p.printEdgeHeader(isDirected)
+	visited := make(map[[2]int64]bool)
+	for _, n := range nodes {
+		nid := n.ID()
+		to := graph.NodesOf(g.From(nid))
+		sort.Sort(ordered.ByID(to))
+		for _, t := range to {
+			tid := t.ID()
+			if visited[[2]int64{nid, tid}] {
+				continue
+			}
+			visited[[2]int64{nid, tid}] = true
+			if !isDirected {
+				visited[[2]int64{tid, nid}] = true
+			}
+			err = p.printEdge(nid, tid, g.Edge(nid, tid))
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return p.buf.Bytes(), nil
+}
+
+// MarshalMulti returns the Pajek encoding for the multigraph g. Name is used
+// to specify the network name. If name is empty and g implements
+// encoding.Attributer, the value of its "name" attribute will be used.
+//
+// Graph serialization will work for a graph.Multigraph without modification,
+// however, vertex and edge parameters are only encoded for nodes and lines
+// that implement encoding.Attributer. Nodes with attributes but without a
+// "label" attribute are labeled with their graph.Node ID.
+func MarshalMulti(g graph.Multigraph, name string) ([]byte, error) {
+	var p printer
+	nodes, err := p.printVertices(g, g.Nodes(), name)
+	if err != nil {
+		return nil, err
+	}
+
+	_, isDirected := g.(graph.Directed)
+	p.printEdgeHeader(isDirected)
+	visited := make(map[int64]bool)
+	for _, n := range nodes {
+		nid := n.ID()
+		to := graph.NodesOf(g.From(nid))
+		sort.Sort(ordered.ByID(to))
+		for _, t := range to {
+			tid := t.ID()
+			lines := graph.LinesOf(g.Lines(nid, tid))
+			sort.Sort(ordered.LinesByIDs(lines))
+			for _, l := range lines {
+				lid := l.ID()
+				if visited[lid] {
+					continue
+				}
+				visited[lid] = true
+				err = p.printEdge(nid, tid, l)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return p.buf.Bytes(), nil
+}
+
+// shapes is the set of Pajek vertex shape keywords.
+var shapes = map[string]bool{
+	"ellipse":  true,
+	"box":      true,
+	"diamond":  true,
+	"triangle": true,
+	"cross":    true,
+	"empty":    true,
+}
+
+type printer struct {
+	buf bytes.Buffer
+
+	// vertex is the Pajek vertex number
+	// of each graph.Node ID.
+	vertex map[int64]int
+}
+
+// printVertices writes the network name and vertices of the graph g with
+// the given nodes and returns the nodes in vertex number order.
+func (p *printer) printVertices(g interface{}, it graph.Nodes, name string) ([]graph.Node, error) {
+	if name == "" {
+		if a, ok := g.(encoding.Attributer); ok {
+			for _, attr := range a.Attributes() {
+				if attr.Key == "name" {
+					name = attr.Value
+				}
+			}
+		}
+	}
+	if name != "" {
+		if strings.ContainsAny(name, "\n\r") {
+			return nil, fmt.Errorf("pajek: invalid network name %q", name)
+		}
+		fmt.Fprintf(&p.buf, "*Network %s\n", name)
+	}
+
+	nodes := graph.NodesOf(it)
+	sort.Sort(ordered.ByID(nodes))
+	fmt.Fprintf(&p.buf, "*Vertices %d\n", len(nodes))
+	p.vertex = make(map[int64]int, len(nodes))
+	for i, n := range nodes {
+		p.vertex[n.ID()] = i + 1
+		err := p.printVertex(i+1, n)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+func (p *printer) printVertex(i int, n graph.Node) error {
+	var attrs []encoding.Attribute
+	if a, ok := n.(encoding.Attributer); ok {
+		attrs = a.Attributes()
+	}
+	p.buf.WriteString(strconv.Itoa(i))
+	if len(attrs) == 0 {
+		p.buf.WriteByte('\n')
+		return nil
+	}
+
+	label := strconv.FormatInt(n.ID(), 10)
+	var (
+		coords [3]string
+		shape  string
+		params []encoding.Attribute
+	)
+	for _, a := range attrs {
+		switch a.Key {
+		case "label":
+			label = a.Value
+		case "x":
+			coords[0] = a.Value
+		case "y":
+			coords[1] = a.Value
+		case "z":
+			coords[2] = a.Value
+		case "shape":
+			if !shapes[a.Value] {
+				return fmt.Errorf("pajek: invalid vertex shape %q", a.Value)
+			}
+			shape = a.Value
+		default:
+			params = append(params, a)
+		}
+	}
+
+	if strings.ContainsAny(label, "\"\n\r") {
+		return fmt.Errorf("pajek: invalid vertex label %q", label)
+	}
+	p.buf.WriteString(` "`)
+	p.buf.WriteString(label)
+	p.buf.WriteByte('"')
+	for j, c := range coords {
+		if c == "" {
+			for _, c := range coords[j+1:] {
+				if c != "" {
+					return fmt.Errorf("pajek: missing vertex coordinate for vertex %d", i)
+				}
+			}
+			break
+		}
+		if _, err := strconv.ParseFloat(c, 64); err != nil {
+			return fmt.Errorf("pajek: invalid vertex coordinate %q", c)
+		}
+		p.buf.WriteByte(' ')
+		p.buf.WriteString(c)
+	}
+	if shape != "" {
+		p.buf.WriteByte(' ')
+		p.buf.WriteString(shape)
+	}
+	err := p.writeParams(params)
+	if err != nil {
+		return err
+	}
+	p.buf.WriteByte('\n')
+	return nil
+}
+
+func (p *printer) printEdgeHeader(isDirected bool) {
+	if isDirected {
+		p.buf.WriteString("*Arcs\n")
+	} else {
+		p.buf.WriteString("*Edges\n")
+	}
+}
+
+func (p *printer) printEdge(from, to int64, e interface{}) error {
+	fmt.Fprintf(&p.buf, "%d %d", p.vertex[from], p.vertex[to])
+
+	var (
+		weight    string
+		hasWeight bool
+		params    []encoding.Attribute
+	)
+	if a, ok := e.(encoding.Attributer); ok {
+		for _, attr := range a.Attributes() {
+			if attr.Key == "weight" {
+				weight = attr.Value
+				hasWeight = true
+				continue
+			}
+			params = append(params, attr)
+		}
+	}
+	if !hasWeight {
+		switch e := e.(type) {
+		case graph.WeightedEdge:
+			weight = strconv.FormatFloat(e.Weight(), 'g', -1, 64)
+			hasWeight = true
+		case graph.WeightedLine:
+			weight = strconv.FormatFloat(e.Weight(), 'g', -1, 64)
+			hasWeight = true
+		}
+	}
+	if hasWeight {
+		if _, err := strconv.ParseFloat(weight, 64); err != nil {
+			return fmt.Errorf("pajek: invalid edge weight %q", weight)
+		}
+		p.buf.WriteByte(' ')
+		p.buf.WriteString(weight)
+	}
+	err := p.writeParams(params)
+	if err != nil {
+		return err
+	}
+	p.buf.WriteByte('\n')
+	return nil
+}
+
+// writeParams writes the attributes as Pajek keyword-value pairs.
+func (p *printer) writeParams(params []encoding.Attribute) error {
+	for _, a := range params {
+		if !isKeyword(a.Key) {
+			return fmt.Errorf("pajek: invalid attribute key %q", a.Key)
+		}
+		v, err := quote(a.Value)
+		if err != nil {
+			return err
+		}
+		p.buf.WriteByte(' ')
+		p.buf.WriteString(a.Key)
+		p.buf.WriteByte(' ')
+		p.buf.WriteString(v)
+	}
+	return nil
+}
+
+// isKeyword reports whether s can be used as a Pajek parameter keyword.
+func isKeyword(s string) bool {
+	if s == "" || shapes[s] {
+		return false
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return false
+	}
+	for _, r := range s {
+		if unicode.IsSpace(r) || r == '"' {
+			return false
+		}
+	}
+	return true
+}
+
+// quote returns s quoted if it is empty or contains white space. It returns
+// an error if s contains a double quote or a line break.
+func quote(s string) (string, error) {
+	if strings.ContainsAny(s, "\"\n\r") {
+		return "", fmt.Errorf("pajek: invalid value %q", s)
+	}
+	if s == "" || strings.IndexFunc(s, unicode.IsSpace) >= 0 || s[0] == '%' || s[0] == '*' {
+		return `"` + s + `"`, nil
+	}
+	return s, nil
+}
@@ -0,0 +1,313 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pajek
+
+import (
+	"reflect"
+	"testing"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/encoding"
+	"gonum.org/v1/gonum/graph/multi"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+var roundTripTests = []struct {
+	name     string
+	directed bool
+	multi    bool
+	want     string
+}{
+	{
+		name:     "directed",
+		directed: true,
+		want: `*Network test network
+*Vertices 4
+1 "A" 0.1 0.2 ellipse ic Red
+2 "second node" 0.5 0.5 0
+3 "C" bc "Light Blue"
+4
+*Arcs
+1 2 1.5 c Blue
+2 1
+2 3 2 l "an edge"
+`,
+	},
+	{
+		name:     "undirected",
+		directed: false,
+		want: `*Vertices 3
+1
+2
+3
+*Edges
+1 2 -1
+1 3
+`,
+	},
+	{
+		name:     "directed multigraph",
+		directed: true,
+		multi:    true,
+		want: `*Vertices 2
+1 "a"
+2 "b"
+*Arcs
+1 1
+1 2 1
+1 2 2
+2 1
+`,
+	},
+	{
+		name:     "undirected multigraph",
+		directed: false,
+		multi:    true,
+		want: `*Vertices 2
+1
+2
+*Edges
+1 2
+1 2 p Dotted
+`,
+	},
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, test := range roundTripTests {
+		var (
+			b   []byte
+			err error
+		)
+		if test.multi {
+			dst := newMultigraph(test.directed)
+			err = UnmarshalMulti([]byte(test.want), dst)
+			if err != nil {
+				t.Errorf("unexpected error unmarshaling %s: %v", test.name, err)
+				continue
+			}
+			b, err = MarshalMulti(dst, "")
+		} else {
+			dst := newGraph(test.directed)
+			err = Unmarshal([]byte(test.want), dst)
+			if err != nil {
+				t.Errorf("unexpected error unmarshaling %s: %v", test.name, err)
+				continue
+			}
+			b, err = Marshal(dst, "")
+		}
+		if err != nil {
+			t.Errorf("unexpected error marshaling %s: %v", test.name, err)
+			continue
+		}
+		if got := string(b); got != test.want {
+			t.Errorf("unexpected round trip result for %s:\ngot:\n%s\nwant:\n%s", test.name, got, test.want)
+		}
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	g := simple.NewWeightedUndirectedGraph(0, 0)
+	g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(10), T: simple.Node(5), W: 0.5})
+	g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(10), T: simple.Node(20), W: 2})
+
+	const want = `*Network weighted
+*Vertices 3
+1
+2
+3
+*Edges
+1 2 0.5
+2 3 2
+`
+
+	b, err := Marshal(g, "weighted")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := string(b); got != want {
+		t.Errorf("unexpected marshaling result:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestMarshalErrors(t *testing.T) {
+	for _, attrs := range []attributes{
+		{{Key: "label", Value: `a "quoted" label`}},
+		{{Key: "y", Value: "1"}},
+		{{Key: "x", Value: "one"}},
+		{{Key: "shape", Value: "circle"}},
+		{{Key: "1", Value: "one"}},
+		{{Key: "a key", Value: "value"}},
+	} {
+		g := newGraph(false)
+		n := g.NewNode().(*testNode)
+		n.attributes = attrs
+		g.AddNode(n)
+		_, err := Marshal(g, "")
+		if err == nil {
+			t.Errorf("expected error for node attributes %v", attrs)
+		}
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	const data = `% A comment.
+*network Test
+*vertices 3
+ 2 "two"   1 2 3 x_fact 2
+*arcslist
+1 2 3
+3 1
+*arcs :1 "relation"
+2 3 1.5
+*edges
+3 2
+`
+
+	dst := newGraph(true).(*directedGraph)
+	err := Unmarshal([]byte(data), dst)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := (attributes{{Key: "name", Value: "Test"}}); !reflect.DeepEqual(dst.attributes, want) {
+		t.Errorf("unexpected graph attributes: got:%v want:%v", dst.attributes, want)
+	}
+	want := attributes{
+		{Key: "label", Value: "two"},
+		{Key: "x", Value: "1"},
+		{Key: "y", Value: "2"},
+		{Key: "z", Value: "3"},
+		{Key: "x_fact", Value: "2"},
+	}
+	var got attributes
+	nodes := dst.Nodes()
+	for nodes.Next() {
+		got = append(got, nodes.Node().(*testNode).attributes...)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected vertex attributes: got:%v want:%v", got, want)
+	}
+	if dst.Edges().Len() != 5 {
+		t.Errorf("unexpected number of edges: got:%d want:5", dst.Edges().Len())
+	}
+}
+
+var unmarshalErrorTests = []struct {
+	name     string
+	directed bool
+	data     string
+}{
+	{name: "no vertices", directed: true, data: "*Network empty\n"},
+	{name: "arcs before vertices", directed: true, data: "*Arcs\n*Vertices 1\n"},
+	{name: "invalid vertices", directed: true, data: "*Vertices n\n"},
+	{name: "vertex out of range", directed: true, data: "*Vertices 2\n*Arcs\n1 3\n"},
+	{name: "missing end point", directed: true, data: "*Vertices 2\n*Arcs\n1\n"},
+	{name: "missing value", directed: true, data: "*Vertices 2\n1 \"a\" ic\n"},
+	{name: "unterminated label", directed: true, data: "*Vertices 2\n1 \"a\n"},
+	{name: "matrix", directed: true, data: "*Vertices 2\n*Matrix\n0 1\n1 0\n"},
+	{name: "arcs in undirected", directed: false, data: "*Vertices 2\n*Arcs\n1 2\n"},
+	{name: "data outside section", directed: true, data: "1 2\n*Vertices 2\n"},
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	for _, test := range unmarshalErrorTests {
+		err := UnmarshalMulti([]byte(test.data), newMultigraph(test.directed))
+		if err == nil {
+			t.Errorf("expected error for %s", test.name)
+		}
+	}
+}
+
+type directedGraph struct {
+	*simple.DirectedGraph
+	attributes
+}
+
+func (g *directedGraph) NewNode() graph.Node {
+	return &testNode{Node: g.DirectedGraph.NewNode()}
+}
+
+func (g *directedGraph) NewEdge(from, to graph.Node) graph.Edge {
+	return &testEdge{Edge: g.DirectedGraph.NewEdge(from, to)}
+}
+
+type undirectedGraph struct {
+	*simple.UndirectedGraph
+	attributes
+}
+
+func (g *undirectedGraph) NewNode() graph.Node {
+	return &testNode{Node: g.UndirectedGraph.NewNode()}
+}
+
+func (g *undirectedGraph) NewEdge(from, to graph.Node) graph.Edge {
+	return &testEdge{Edge: g.UndirectedGraph.NewEdge(from, to)}
+}
+
+func newGraph(directed bool) encoding.Builder {
+	if directed {
+		return &directedGraph{DirectedGraph: simple.NewDirectedGraph()}
+	}
+	return &undirectedGraph{UndirectedGraph: simple.NewUndirectedGraph()}
+}
+
+type directedMultigraph struct {
+	*multi.DirectedGraph
+	attributes
+}
+
+func (g *directedMultigraph) NewNode() graph.Node {
+	return &testNode{Node: g.DirectedGraph.NewNode()}
+}
+
+func (g *directedMultigraph) NewLine(from, to graph.Node) graph.Line {
+	return &testLine{Line: g.DirectedGraph.NewLine(from, to)}
+}
+
+type undirectedMultigraph struct {
+	*multi.UndirectedGraph
+	attributes
+}
+
+func (g *undirectedMultigraph) NewNode() graph.Node {
+	return &testNode{Node: g.UndirectedGraph.NewNode()}
+}
+
+func (g *undirectedMultigraph) NewLine(from, to graph.Node) graph.Line {
+	return &testLine{Line: g.UndirectedGraph.NewLine(from, to)}
+}
+
+func newMultigraph(directed bool) encoding.MultiBuilder {
+	if directed {
+		return &directedMultigraph{DirectedGraph: multi.NewDirectedGraph()}
+	}
+	return &undirectedMultigraph{UndirectedGraph: multi.NewUndirectedGraph()}
+}
+
+type testNode struct {
+	graph.Node
+	attributes
+}
+
+type testEdge struct {
+	graph.Edge
+	attributes
+}
+
+type testLine struct {
+	graph.Line
+	attributes
+}
+
+// attributes is a helper for graph, node and edge attributes.
+type attributes []encoding.Attribute
+
+func (a attributes) Attributes() []encoding.Attribute {
+	return []encoding.Attribute(a)
+}
+func (a *attributes) SetAttribute(attr encoding.Attribute) error {
+	*a = append(*a, attr)
+	return nil
+}
@@ -0,0 +1,181 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gen
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+)
+
+// ConfigurationModel constructs a configuration model multigraph in the
+// destination, dst, with the given degree sequence. The i-th node created
+// has degree[i] line end points, and the end points are paired uniformly
+// at random, so the multigraph may include self loops and parallel lines.
+// A self loop contributes two to the degree of its node. If dst is a
+// graph.Directed, lines are oriented arbitrarily. If src is not nil it is
+// used as the random source, otherwise rand.Intn is used. The graph is
+// constructed in O(n+m) time.
+func ConfigurationModel(dst graph.MultigraphBuilder, degree []int, src rand.Source) error {
+	var sum int
+	for i, d := range degree {
+		if d < 0 {
+			return fmt.Errorf("gen: bad degree: degree[%d]=%d", i, d)
+		}
+		sum += d
+	}
+	if sum%2 != 0 {
+		return fmt.Errorf("gen: odd degree sum: sum=%d", sum)
+	}
+	var rnd func(int) int
+	if src == nil {
+		rnd = rand.Intn
+	} else {
+		rnd = rand.New(src).Intn
+	}
+
+	nodes := make([]graph.Node, len(degree))
+	for i := range nodes {
+		u := dst.NewNode()
+		dst.AddNode(u)
+		nodes[i] = u
+	}
+	stubs := make([]graph.Node, 0, sum)
+	for i, d := range degree {
+		for j := 0; j < d; j++ {
+			stubs = append(stubs, nodes[i])
+		}
+	}
+	for i := len(stubs) - 1; i > 0; i-- {
+		j := rnd(i + 1)
+		stubs[i], stubs[j] = stubs[j], stubs[i]
+	}
+	for i := 0; i < len(stubs); i += 2 {
+		dst.SetLine(dst.NewLine(stubs[i], stubs[i+1]))
+	}
+	return nil
+}
+
+// RandomRegular constructs a random d-regular graph of order n in the
+// destination, dst. If dst is a graph.Directed, edges are added in both
+// directions so that every node has d in and d out edges. The product of n
+// and d must be even and d must be less than n. If src is not nil it is used
+// as the random source, otherwise rand.Intn is used.
+//
+// The algorithm used is essentially as described in "Generating random
+// regular graphs quickly", Steger and Wormald, Combinatorics, Probability
+// and Computing 8(4):377-396. The distribution of the generated graphs is
+// asymptotically uniform for slowly growing d.
+func RandomRegular(dst GraphBuilder, n, d int, src rand.Source) error {
+	if n < 0 {
+		return fmt.Errorf("gen: bad order: n=%d", n)
+	}
+	if d < 0 || (d >= n && d != 0) {
+		return fmt.Errorf("gen: bad degree: d=%d", d)
+	}
+	if n*d%2 != 0 {
+		return fmt.Errorf("gen: odd degree sum: n=%d d=%d", n, d)
+	}
+	var rnd func(int) int
+	if src == nil {
+		rnd = rand.Intn
+	} else {
+		rnd = rand.New(src).Intn
+	}
+
+	// The pairing may fail to complete, but this
+	// happens with low probability for each attempt.
+	const attempts = 100
+	for i := 0; i < attempts; i++ {
+		edges, ok := regularPairing(n, d, rnd)
+		if !ok {
+			continue
+		}
+		nodes := addNodes(dst, n)
+		_, isDirected := dst.(graph.Directed)
+		for _, e := range edges {
+			u, v := nodes[e[0]], nodes[e[1]]
+			dst.SetEdge(dst.NewEdge(u, v))
+			if isDirected {
+				dst.SetEdge(dst.NewEdge(v, u))
+			}
+		}
+		return nil
+	}
+	return errors.New("gen: failed to construct regular graph")
+}
+
+// regularPairing returns the edges of a random d-regular simple graph of
+// order n formed by repeatedly joining random pairs of unpaired end points
+// that do not form a self loop or parallel edge. It returns false if the
+// pairing reaches a state where no suitable pair remains.
+func regularPairing(n, d int, rnd func(int) int) (edges [][2]int, ok bool) {
+	stubs := make([]int, 0, n*d)
+	for u := 0; u < n; u++ {
+		for i := 0; i < d; i++ {
+			stubs = append(stubs, u)
+		}
+	}
+	adjacent := make(map[[2]int]bool, n*d/2)
+	suitable := func(u, v int) bool {
+		if u > v {
+			u, v = v, u
+		}
+		return u != v && !adjacent[[2]int{u, v}]
+	}
+	// remove removes the end points at i and j from stubs.
+	remove := func(i, j int) {
+		if i < j {
+			i, j = j, i
+		}
+		last := len(stubs) - 1
+		stubs[i] = stubs[last]
+		stubs = stubs[:last]
+		last--
+		stubs[j] = stubs[last]
+		stubs = stubs[:last]
+	}
+
+	edges = make([][2]int, 0, n*d/2)
+	for len(stubs) != 0 {
+		// Try random pairs first, falling back to an
+		// exhaustive search for suitable pairs when
+		// random selection repeatedly fails.
+		i, j := -1, -1
+		for try := 0; try < 10; try++ {
+			a, b := rnd(len(stubs)), rnd(len(stubs))
+			if a != b && suitable(stubs[a], stubs[b]) {
+				i, j = a, b
+				break
+			}
+		}
+		if i < 0 {
+			var pairs [][2]int
+			for a := range stubs {
+				for b := a + 1; b < len(stubs); b++ {
+					if suitable(stubs[a], stubs[b]) {
+						pairs = append(pairs, [2]int{a, b})
+					}
+				}
+			}
+			if len(pairs) == 0 {
+				return nil, false
+			}
+			p := pairs[rnd(len(pairs))]
+			i, j = p[0], p[1]
+		}
+		u, v := stubs[i], stubs[j]
+		if u > v {
+			u, v = v, u
+		}
+		adjacent[[2]int{u, v}] = true
+		edges = append(edges, [2]int{u, v})
+		remove(i, j)
+	}
+	return edges, true
+}
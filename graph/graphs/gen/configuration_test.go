@@ -0,0 +1,101 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gen
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/multi"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+func TestConfigurationModel(t *testing.T) {
+	t.Parallel()
+	for seed := uint64(1); seed <= 10; seed++ {
+		degree := []int{3, 1, 4, 1, 5, 0, 2, 6, 5, 3}
+		g := multi.NewUndirectedGraph()
+		err := ConfigurationModel(g, degree, rand.NewSource(seed))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := g.Nodes().Len(); got != len(degree) {
+			t.Errorf("unexpected number of nodes: got:%d want:%d", got, len(degree))
+		}
+		for i, want := range degree {
+			var got int
+			to := graph.NodesOf(g.From(int64(i)))
+			for _, v := range to {
+				lines := g.Lines(int64(i), v.ID())
+				n := lines.Len()
+				if v.ID() == int64(i) {
+					// Self loops contribute two
+					// to the degree of the node.
+					n *= 2
+				}
+				got += n
+			}
+			if got != want {
+				t.Errorf("unexpected degree for node %d with seed=%d: got:%d want:%d", i, seed, got, want)
+			}
+		}
+	}
+
+	err := ConfigurationModel(multi.NewUndirectedGraph(), []int{1, 2}, nil)
+	if err == nil {
+		t.Error("expected error for odd degree sum")
+	}
+}
+
+func TestRandomRegular(t *testing.T) {
+	t.Parallel()
+	for n := 0; n <= 20; n++ {
+		for d := 0; d < n; d++ {
+			if n*d%2 != 0 {
+				err := RandomRegular(simple.NewUndirectedGraph(), n, d, nil)
+				if err == nil {
+					t.Errorf("expected error for odd degree sum: n=%d d=%d", n, d)
+				}
+				continue
+			}
+			g := &gnUndirected{UndirectedBuilder: simple.NewUndirectedGraph()}
+			err := RandomRegular(g, n, d, rand.NewSource(uint64(n*d)))
+			if err != nil {
+				t.Fatalf("unexpected error: n=%d d=%d: %v", n, d, err)
+			}
+			checkSimple(t, g.addSelfLoop, g.addMultipleEdge, "n=%d d=%d", n, d)
+			nodes := graph.NodesOf(g.Nodes())
+			if len(nodes) != n {
+				t.Errorf("unexpected number of nodes: n=%d d=%d: got:%d", n, d, len(nodes))
+			}
+			for _, u := range nodes {
+				if got := g.From(u.ID()).Len(); got != d {
+					t.Errorf("unexpected degree for node %d: n=%d d=%d: got:%d", u.ID(), n, d, got)
+				}
+			}
+		}
+	}
+}
+
+func TestRandomRegularDirected(t *testing.T) {
+	t.Parallel()
+	g := &gnDirected{DirectedBuilder: simple.NewDirectedGraph()}
+	err := RandomRegular(g, 20, 3, rand.NewSource(1))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checkSimple(t, g.addSelfLoop, g.addMultipleEdge, "directed")
+	nodes := graph.NodesOf(g.Nodes())
+	for _, u := range nodes {
+		if got := g.From(u.ID()).Len(); got != 3 {
+			t.Errorf("unexpected out degree for node %d: got:%d want:3", u.ID(), got)
+		}
+		if got := g.To(u.ID()).Len(); got != 3 {
+			t.Errorf("unexpected in degree for node %d: got:%d want:3", u.ID(), got)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gen
+
+import (
+	"fmt"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+)
+
+// Complete constructs a complete graph of order n in the destination, dst.
+// If dst is a graph.Directed, edges are added in both directions between
+// each pair of nodes.
+func Complete(dst graph.Builder, n int) error {
+	if n < 0 {
+		return fmt.Errorf("gen: bad order: n=%d", n)
+	}
+	nodes := addNodes(dst, n)
+	_, isDirected := dst.(graph.Directed)
+	for i, u := range nodes {
+		for _, v := range nodes[i+1:] {
+			dst.SetEdge(dst.NewEdge(u, v))
+			if isDirected {
+				dst.SetEdge(dst.NewEdge(v, u))
+			}
+		}
+	}
+	return nil
+}
+
+// Path constructs a path graph of order n in the destination, dst. Nodes are
+// joined in the order they are created, and if dst is a graph.Directed edges
+// are directed from earlier to later nodes.
+func Path(dst graph.Builder, n int) error {
+	if n < 0 {
+		return fmt.Errorf("gen: bad order: n=%d", n)
+	}
+	nodes := addNodes(dst, n)
+	for i := 1; i < n; i++ {
+		dst.SetEdge(dst.NewEdge(nodes[i-1], nodes[i]))
+	}
+	return nil
+}
+
+// Cycle constructs a cycle graph of order n in the destination, dst. Nodes are
+// joined in the order they are created, and if dst is a graph.Directed edges
+// are directed from earlier to later nodes with the last node joined to the
+// first.
+func Cycle(dst graph.Builder, n int) error {
+	if n < 3 {
+		return fmt.Errorf("gen: bad order: n=%d", n)
+	}
+	nodes := addNodes(dst, n)
+	for i := range nodes {
+		dst.SetEdge(dst.NewEdge(nodes[i], nodes[(i+1)%n]))
+	}
+	return nil
+}
+
+// Star constructs a star graph of order n in the destination, dst. The first
+// node created is the center of the star, and if dst is a graph.Directed edges
+// are directed from the center to the leaves.
+func Star(dst graph.Builder, n int) error {
+	if n < 1 {
+		return fmt.Errorf("gen: bad order: n=%d", n)
+	}
+	nodes := addNodes(dst, n)
+	for _, v := range nodes[1:] {
+		dst.SetEdge(dst.NewEdge(nodes[0], v))
+	}
+	return nil
+}
+
+// Grid constructs an N-dimensional grid graph in the destination, dst. The
+// dims parameter specifies the length of each of the N dimensions. Nodes are
+// joined to their neighbors at unit Manhattan distance and, if periodic is true,
+// the nodes at the ends of each dimension are joined so that the grid forms a
+// torus. If dst is a graph.Directed, edges are directed toward increasing
+// coordinates, with periodic edges directed from the last node in a
+// dimension to the first.
+//
+// Nodes are created in order such that the node with coordinates c is the
+// i-th node created where i = c[0] + dims[0]*(c[1] + dims[1]*(c[2] + ...)).
+func Grid(dst GraphBuilder, dims []int, periodic bool) error {
+	if len(dims) == 0 {
+		return fmt.Errorf("gen: no dimensions")
+	}
+	n := 1
+	for _, d := range dims {
+		if d < 1 {
+			return fmt.Errorf("gen: bad dimension length: d=%d", d)
+		}
+		n *= d
+	}
+	nodes := addNodes(dst, n)
+
+	hasEdge := dst.HasEdgeBetween
+	if d, ok := dst.(graph.Directed); ok {
+		hasEdge = d.HasEdgeFromTo
+	}
+	next := make([]int, len(dims))
+	iterateOver(dims, func(c []int) {
+		u := nodes[idxFrom(c, dims)]
+		for d, m := range dims {
+			copy(next, c)
+			next[d]++
+			if next[d] == m {
+				if !periodic || m < 3 {
+					// Shorter dimensions would otherwise
+					// give self loops or repeated edges.
+					continue
+				}
+				next[d] = 0
+			}
+			v := nodes[idxFrom(next, dims)]
+			if !hasEdge(u.ID(), v.ID()) {
+				dst.SetEdge(dst.NewEdge(u, v))
+			}
+		}
+	})
+	return nil
+}
+
+// KaryTree constructs a complete k-ary tree of order n in the destination,
+// dst. Nodes are created in breadth first order starting from the root, so
+// the parent of the i-th node created is the ((i-1)/k)-th node. If dst is a
+// graph.Directed, edges are directed from parents to children.
+func KaryTree(dst graph.Builder, n, k int) error {
+	if n < 0 {
+		return fmt.Errorf("gen: bad order: n=%d", n)
+	}
+	if k < 1 {
+		return fmt.Errorf("gen: bad branching factor: k=%d", k)
+	}
+	nodes := addNodes(dst, n)
+	for i := 1; i < n; i++ {
+		dst.SetEdge(dst.NewEdge(nodes[(i-1)/k], nodes[i]))
+	}
+	return nil
+}
+
+// RandomTree constructs a uniformly random labeled tree of order n in the
+// destination, dst, by decoding a random Prüfer sequence. If dst is a
+// graph.Directed, edges are directed away from the last node created. If src
+// is not nil it is used as the random source, otherwise rand.Intn is used.
+// The graph is constructed in O(n) time.
+func RandomTree(dst graph.Builder, n int, src rand.Source) error {
+	if n < 1 {
+		return fmt.Errorf("gen: bad order: n=%d", n)
+	}
+	var rnd func(int) int
+	if src == nil {
+		rnd = rand.Intn
+	} else {
+		rnd = rand.New(src).Intn
+	}
+
+	nodes := addNodes(dst, n)
+	if n == 1 {
+		return nil
+	}
+
+	prufer := make([]int, n-2)
+	degree := make([]int, n)
+	for i := range degree {
+		degree[i] = 1
+	}
+	for i := range prufer {
+		prufer[i] = rnd(n)
+		degree[prufer[i]]++
+	}
+
+	// Decode the sequence in linear time. The leaf joined at
+	// each step is the smallest numbered remaining leaf. The
+	// last node is always the final remaining node and so is
+	// the root when edges are directed toward the leaves.
+	var (
+		ptr  int
+		leaf int
+	)
+	for degree[ptr] != 1 {
+		ptr++
+	}
+	leaf = ptr
+	for _, v := range prufer {
+		dst.SetEdge(dst.NewEdge(nodes[v], nodes[leaf]))
+		degree[v]--
+		if v < ptr && degree[v] == 1 {
+			leaf = v
+			continue
+		}
+		ptr++
+		for degree[ptr] != 1 {
+			ptr++
+		}
+		leaf = ptr
+	}
+	dst.SetEdge(dst.NewEdge(nodes[n-1], nodes[leaf]))
+	return nil
+}
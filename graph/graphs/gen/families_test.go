@@ -0,0 +1,209 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gen
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+	"gonum.org/v1/gonum/graph/topo"
+)
+
+func TestComplete(t *testing.T) {
+	t.Parallel()
+	for n := 0; n <= 10; n++ {
+		g := &gnUndirected{UndirectedBuilder: simple.NewUndirectedGraph()}
+		err := Complete(g, n)
+		if err != nil {
+			t.Fatalf("unexpected error: n=%d: %v", n, err)
+		}
+		checkSimple(t, g.addSelfLoop, g.addMultipleEdge, "n=%d", n)
+		if got, want := edgeCount(g), n*(n-1)/2; got != want {
+			t.Errorf("unexpected number of edges: n=%d: got:%d want:%d", n, got, want)
+		}
+
+		d := &gnDirected{DirectedBuilder: simple.NewDirectedGraph()}
+		err = Complete(d, n)
+		if err != nil {
+			t.Fatalf("unexpected error: n=%d: %v", n, err)
+		}
+		checkSimple(t, d.addSelfLoop, d.addMultipleEdge, "directed n=%d", n)
+		if got, want := edgeCount(d), n*(n-1); got != want {
+			t.Errorf("unexpected number of directed edges: n=%d: got:%d want:%d", n, got, want)
+		}
+	}
+}
+
+func TestPathCycleStar(t *testing.T) {
+	t.Parallel()
+	for n := 3; n <= 10; n++ {
+		for _, test := range []struct {
+			name      string
+			fn        func(graph.Builder, int) error
+			wantEdges int
+			wantMax   int
+		}{
+			{name: "path", fn: Path, wantEdges: n - 1, wantMax: 2},
+			{name: "cycle", fn: Cycle, wantEdges: n, wantMax: 2},
+			{name: "star", fn: Star, wantEdges: n - 1, wantMax: n - 1},
+		} {
+			g := &gnUndirected{UndirectedBuilder: simple.NewUndirectedGraph()}
+			err := test.fn(g, n)
+			if err != nil {
+				t.Fatalf("unexpected error: %s n=%d: %v", test.name, n, err)
+			}
+			checkSimple(t, g.addSelfLoop, g.addMultipleEdge, "%s n=%d", test.name, n)
+			if got := edgeCount(g); got != test.wantEdges {
+				t.Errorf("unexpected number of edges: %s n=%d: got:%d want:%d", test.name, n, got, test.wantEdges)
+			}
+			if got := maxDegree(g); got != test.wantMax {
+				t.Errorf("unexpected maximum degree: %s n=%d: got:%d want:%d", test.name, n, got, test.wantMax)
+			}
+			if len(topo.ConnectedComponents(g)) != 1 {
+				t.Errorf("unexpected disconnected graph: %s n=%d", test.name, n)
+			}
+		}
+	}
+}
+
+func TestGrid(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		dims      []int
+		periodic  bool
+		wantEdges int
+	}{
+		{dims: []int{1}, wantEdges: 0},
+		{dims: []int{5}, wantEdges: 4},
+		{dims: []int{5}, periodic: true, wantEdges: 5},
+		{dims: []int{3, 4}, wantEdges: 2*4 + 3*3},
+		{dims: []int{3, 4}, periodic: true, wantEdges: 2 * 3 * 4},
+		{dims: []int{2, 4}, periodic: true, wantEdges: 4 + 2*4},
+		{dims: []int{3, 3, 3}, periodic: true, wantEdges: 3 * 27},
+	} {
+		g := &gnUndirected{UndirectedBuilder: simple.NewUndirectedGraph()}
+		err := Grid(g, test.dims, test.periodic)
+		if err != nil {
+			t.Fatalf("unexpected error: dims=%v periodic=%t: %v", test.dims, test.periodic, err)
+		}
+		checkSimple(t, g.addSelfLoop, g.addMultipleEdge, "dims=%v periodic=%t", test.dims, test.periodic)
+		if got := edgeCount(g); got != test.wantEdges {
+			t.Errorf("unexpected number of edges: dims=%v periodic=%t: got:%d want:%d", test.dims, test.periodic, got, test.wantEdges)
+		}
+	}
+
+	// Check node placement in a 3×2 grid.
+	g := simple.NewUndirectedGraph()
+	err := Grid(g, []int{3, 2}, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, e := range [][2]int64{{0, 1}, {1, 2}, {3, 4}, {4, 5}, {0, 3}, {1, 4}, {2, 5}} {
+		if !g.HasEdgeBetween(e[0], e[1]) {
+			t.Errorf("missing grid edge %d--%d", e[0], e[1])
+		}
+	}
+}
+
+func TestTrees(t *testing.T) {
+	t.Parallel()
+	for n := 1; n <= 20; n++ {
+		for k := 1; k <= 4; k++ {
+			g := &gnUndirected{UndirectedBuilder: simple.NewUndirectedGraph()}
+			err := KaryTree(g, n, k)
+			if err != nil {
+				t.Fatalf("unexpected error: n=%d k=%d: %v", n, k, err)
+			}
+			checkTree(t, g, n, "k-ary n=%d k=%d", n, k)
+			// A node with a parent and k children
+			// exists when n is at least 2k+2.
+			if got := maxDegree(g); n >= 2*k+2 && got != k+1 {
+				t.Errorf("unexpected maximum degree: n=%d k=%d: got:%d want:%d", n, k, got, k+1)
+			}
+		}
+
+		for seed := uint64(1); seed <= 5; seed++ {
+			g := &gnUndirected{UndirectedBuilder: simple.NewUndirectedGraph()}
+			err := RandomTree(g, n, rand.NewSource(seed))
+			if err != nil {
+				t.Fatalf("unexpected error: n=%d: %v", n, err)
+			}
+			checkTree(t, g, n, "random n=%d seed=%d", n, seed)
+		}
+	}
+}
+
+func TestRandomTreeDirected(t *testing.T) {
+	t.Parallel()
+	for n := 2; n <= 20; n++ {
+		g := simple.NewDirectedGraph()
+		err := RandomTree(g, n, rand.NewSource(1))
+		if err != nil {
+			t.Fatalf("unexpected error: n=%d: %v", n, err)
+		}
+		// All nodes other than the root must
+		// have exactly one parent.
+		nodes := graph.NodesOf(g.Nodes())
+		for _, u := range nodes {
+			want := 1
+			if u.ID() == int64(n-1) {
+				want = 0
+			}
+			if got := g.To(u.ID()).Len(); got != want {
+				t.Errorf("unexpected number of parents for node %d: n=%d: got:%d want:%d", u.ID(), n, got, want)
+			}
+		}
+	}
+}
+
+func checkSimple(t *testing.T, selfLoop, multipleEdge bool, format string, args ...interface{}) {
+	t.Helper()
+	if selfLoop {
+		t.Errorf("unexpected self edge: "+format, args...)
+	}
+	if multipleEdge {
+		t.Errorf("unexpected multiple edge: "+format, args...)
+	}
+}
+
+func checkTree(t *testing.T, g *gnUndirected, n int, format string, args ...interface{}) {
+	t.Helper()
+	checkSimple(t, g.addSelfLoop, g.addMultipleEdge, format, args...)
+	if got := g.Nodes().Len(); got != n {
+		t.Errorf("unexpected number of nodes: "+format+": got:%d want:%d", append(args, got, n)...)
+	}
+	if got := edgeCount(g); got != n-1 {
+		t.Errorf("unexpected number of edges: "+format+": got:%d want:%d", append(args, got, n-1)...)
+	}
+	if len(topo.ConnectedComponents(g)) != 1 {
+		t.Errorf("unexpected disconnected tree: "+format, args...)
+	}
+}
+
+func maxDegree(g graph.Graph) int {
+	var max int
+	nodes := g.Nodes()
+	for nodes.Next() {
+		if d := g.From(nodes.Node().ID()).Len(); d > max {
+			max = d
+		}
+	}
+	return max
+}
+
+func edgeCount(g graph.Graph) int {
+	var n int
+	nodes := graph.NodesOf(g.Nodes())
+	for _, u := range nodes {
+		n += g.From(u.ID()).Len()
+	}
+	if _, ok := g.(graph.Directed); !ok {
+		n /= 2
+	}
+	return n
+}
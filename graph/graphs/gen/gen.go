@@ -18,3 +18,14 @@ func abs(a int) int {
 	}
 	return a
 }
+
+// addNodes adds n new nodes to dst and returns them in order of creation.
+func addNodes(dst graph.Builder, n int) []graph.Node {
+	nodes := make([]graph.Node, n)
+	for i := range nodes {
+		u := dst.NewNode()
+		dst.AddNode(u)
+		nodes[i] = u
+	}
+	return nodes
+}
@@ -0,0 +1,128 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gen
+
+import (
+	"fmt"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/spatial/kdtree"
+)
+
+// RandomGeometric constructs a random geometric graph of order n in the
+// destination, dst. Nodes are placed uniformly at random in the unit
+// hypercube of the given number of dimensions and each pair of nodes closer
+// than or equal to the Euclidean distance r is joined by an edge. If dst is
+// a graph.Directed, edges are added in both directions. The position of the
+// i-th node created is returned in pos[i]. If src is not nil it is used as
+// the random source, otherwise rand.Float64 is used.
+//
+// Neighboring nodes are found using a k-d tree, so the graph is constructed
+// in O(n log n + m) expected time for small r, where m is the number of edges
+// added.
+func RandomGeometric(dst graph.Builder, n, dims int, r float64, src rand.Source) (pos [][]float64, err error) {
+	if n < 0 {
+		return nil, fmt.Errorf("gen: bad order: n=%d", n)
+	}
+	if dims < 1 {
+		return nil, fmt.Errorf("gen: bad dimensions: dims=%d", dims)
+	}
+	if r < 0 {
+		return nil, fmt.Errorf("gen: bad radius: r=%v", r)
+	}
+	var rnd func() float64
+	if src == nil {
+		rnd = rand.Float64
+	} else {
+		rnd = rand.New(src).Float64
+	}
+
+	nodes := addNodes(dst, n)
+	pos = make([][]float64, n)
+	points := make(locatedPoints, n)
+	for i := range pos {
+		pos[i] = make([]float64, dims)
+		for j := range pos[i] {
+			pos[i][j] = rnd()
+		}
+		points[i] = locatedPoint{pos: pos[i], idx: i}
+	}
+	if n == 0 {
+		return pos, nil
+	}
+
+	// Construct the tree from a copy since
+	// the tree reorders the points.
+	tree := kdtree.New(append(locatedPoints(nil), points...), false)
+	_, isDirected := dst.(graph.Directed)
+	for _, p := range points {
+		k := kdtree.NewDistKeeper(r * r)
+		tree.NearestSet(k, p)
+		for _, c := range k.Heap {
+			if c.Comparable == nil {
+				continue
+			}
+			j := c.Comparable.(locatedPoint).idx
+			if j <= p.idx {
+				continue
+			}
+			u, v := nodes[p.idx], nodes[j]
+			dst.SetEdge(dst.NewEdge(u, v))
+			if isDirected {
+				dst.SetEdge(dst.NewEdge(v, u))
+			}
+		}
+	}
+	return pos, nil
+}
+
+// locatedPoint is a kdtree.Comparable holding the position
+// of the node at index idx.
+type locatedPoint struct {
+	pos []float64
+	idx int
+}
+
+func (p locatedPoint) Compare(c kdtree.Comparable, d kdtree.Dim) float64 {
+	return p.pos[d] - c.(locatedPoint).pos[d]
+}
+func (p locatedPoint) Dims() int { return len(p.pos) }
+func (p locatedPoint) Distance(c kdtree.Comparable) float64 {
+	q := c.(locatedPoint)
+	var sum float64
+	for d, v := range p.pos {
+		v -= q.pos[d]
+		sum += v * v
+	}
+	return sum
+}
+
+// locatedPoints is a collection of located points that satisfies kdtree.Interface.
+type locatedPoints []locatedPoint
+
+func (p locatedPoints) Index(i int) kdtree.Comparable         { return p[i] }
+func (p locatedPoints) Len() int                              { return len(p) }
+func (p locatedPoints) Pivot(d kdtree.Dim) int                { return locatedPlane{Dim: d, locatedPoints: p}.Pivot() }
+func (p locatedPoints) Slice(start, end int) kdtree.Interface { return p[start:end] }
+
+// locatedPlane allows a locatedPoints to be pivoted on a dimension.
+type locatedPlane struct {
+	kdtree.Dim
+	locatedPoints
+}
+
+func (p locatedPlane) Less(i, j int) bool {
+	return p.locatedPoints[i].pos[p.Dim] < p.locatedPoints[j].pos[p.Dim]
+}
+func (p locatedPlane) Pivot() int { return kdtree.Partition(p, kdtree.MedianOfRandoms(p, 100)) }
+func (p locatedPlane) Slice(start, end int) kdtree.SortSlicer {
+	p.locatedPoints = p.locatedPoints[start:end]
+	return p
+}
+func (p locatedPlane) Swap(i, j int) {
+	p.locatedPoints[i], p.locatedPoints[j] = p.locatedPoints[j], p.locatedPoints[i]
+}
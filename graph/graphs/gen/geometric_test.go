@@ -0,0 +1,61 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gen
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+func TestRandomGeometric(t *testing.T) {
+	t.Parallel()
+	for _, n := range []int{0, 1, 10, 200} {
+		for dims := 1; dims <= 3; dims++ {
+			for _, r := range []float64{0, 0.1, 0.3, 2} {
+				for _, dst := range []graph.Builder{
+					&gnUndirected{UndirectedBuilder: simple.NewUndirectedGraph()},
+					&gnDirected{DirectedBuilder: simple.NewDirectedGraph()},
+				} {
+					pos, err := RandomGeometric(dst, n, dims, r, rand.NewSource(1))
+					if err != nil {
+						t.Fatalf("unexpected error: n=%d dims=%d r=%v: %v", n, dims, r, err)
+					}
+					switch g := dst.(type) {
+					case *gnUndirected:
+						checkSimple(t, g.addSelfLoop, g.addMultipleEdge, "n=%d dims=%d r=%v", n, dims, r)
+					case *gnDirected:
+						checkSimple(t, g.addSelfLoop, g.addMultipleEdge, "directed n=%d dims=%d r=%v", n, dims, r)
+					}
+					if len(pos) != n {
+						t.Fatalf("unexpected number of positions: got:%d want:%d", len(pos), n)
+					}
+
+					// Check against a brute force search. Nodes
+					// are created with IDs in order from zero.
+					g := dst.(graph.Graph)
+					nodes := graph.NodesOf(g.Nodes())
+					for _, u := range nodes {
+						for _, v := range nodes {
+							if u.ID() == v.ID() {
+								continue
+							}
+							want := floats.Distance(pos[u.ID()], pos[v.ID()], 2) <= r
+							got := g.Edge(u.ID(), v.ID()) != nil
+							if got != want {
+								t.Errorf("unexpected edge state for %d--%d: n=%d dims=%d r=%v: got:%t want:%t",
+									u.ID(), v.ID(), n, dims, r, got, want)
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gen
+
+import (
+	"fmt"
+	"math"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+)
+
+// Kronecker constructs a stochastic Kronecker graph in the destination, dst,
+// from the square initiator matrix of edge probabilities raised to the k-th
+// Kronecker power. The graph has m^k nodes where m is the order of the
+// initiator, and the probability of an edge between the nodes u and v is the
+// product of the initiator elements indexed by the base m digits of u and v.
+// If dst is undirected, the initiator must be symmetric. If src is not nil it
+// is used as the random source, otherwise rand.Float64 is used.
+//
+// Edges are placed by recursively descending into the probability matrix
+// once for each of the expected number of edges, (Σ initiator)^k, or half of
+// that for undirected graphs. Self loops and edges that have already been
+// placed are discarded, so the graph may have fewer edges than expected.
+// The graph is constructed in O(m^k + k(Σ initiator)^k) time.
+//
+// The algorithm used is described in "Kronecker graphs: an approach to
+// modeling networks", Leskovec et al., Journal of Machine Learning Research
+// 11:985-1042.
+func Kronecker(dst GraphBuilder, initiator [][]float64, k int, src rand.Source) error {
+	m := len(initiator)
+	if m < 1 {
+		return fmt.Errorf("gen: empty initiator")
+	}
+	if k < 1 {
+		return fmt.Errorf("gen: bad power: k=%d", k)
+	}
+	hasEdge := dst.HasEdgeBetween
+	d, isDirected := dst.(graph.Directed)
+	if isDirected {
+		hasEdge = d.HasEdgeFromTo
+	}
+	var sum float64
+	for i, row := range initiator {
+		if len(row) != m {
+			return fmt.Errorf("gen: initiator not square: %d != %d", len(row), m)
+		}
+		for j, v := range row {
+			if v < 0 || v > 1 {
+				return fmt.Errorf("gen: bad probability: initiator[%d][%d]=%v", i, j, v)
+			}
+			if !isDirected && v != initiator[j][i] {
+				return fmt.Errorf("gen: asymmetric initiator for undirected graph: initiator[%d][%d]=%v initiator[%d][%d]=%v", i, j, v, j, i, initiator[j][i])
+			}
+			sum += v
+		}
+	}
+	n := 1
+	for i := 0; i < k; i++ {
+		if n > int(^uint(0)>>1)/m {
+			return fmt.Errorf("gen: graph too large: %d^%d nodes", m, k)
+		}
+		n *= m
+	}
+
+	var rnd func() float64
+	if src == nil {
+		rnd = rand.Float64
+	} else {
+		rnd = rand.New(src).Float64
+	}
+
+	nodes := addNodes(dst, n)
+	if sum == 0 {
+		return nil
+	}
+
+	// cdf is the cumulative distribution of
+	// initiator cells in row major order.
+	cdf := make([]float64, m*m)
+	var acc float64
+	for i, row := range initiator {
+		for j, v := range row {
+			acc += v / sum
+			cdf[i*m+j] = acc
+		}
+	}
+	cdf[len(cdf)-1] = 1
+
+	edges := math.Min(math.Pow(sum, float64(k)), float64(n)*float64(n-1))
+	if !isDirected {
+		edges /= 2
+	}
+	for e := 0; e < int(math.Round(edges)); e++ {
+		var u, v int
+		for l := 0; l < k; l++ {
+			r := rnd()
+			c := 0
+			for cdf[c] <= r && c < len(cdf)-1 {
+				c++
+			}
+			u = u*m + c/m
+			v = v*m + c%m
+		}
+		if u == v || hasEdge(nodes[u].ID(), nodes[v].ID()) {
+			continue
+		}
+		dst.SetEdge(dst.NewEdge(nodes[u], nodes[v]))
+	}
+	return nil
+}
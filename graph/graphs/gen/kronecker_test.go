@@ -0,0 +1,73 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gen
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+func TestKronecker(t *testing.T) {
+	t.Parallel()
+	initiator := [][]float64{
+		{0.9, 0.5},
+		{0.5, 0.1},
+	}
+	for k := 1; k <= 10; k++ {
+		for _, dst := range []GraphBuilder{
+			&gnUndirected{UndirectedBuilder: simple.NewUndirectedGraph()},
+			&gnDirected{DirectedBuilder: simple.NewDirectedGraph()},
+		} {
+			err := Kronecker(dst, initiator, k, rand.NewSource(1))
+			if err != nil {
+				t.Fatalf("unexpected error: k=%d: %v", k, err)
+			}
+			switch g := dst.(type) {
+			case *gnUndirected:
+				checkSimple(t, g.addSelfLoop, g.addMultipleEdge, "k=%d", k)
+			case *gnDirected:
+				checkSimple(t, g.addSelfLoop, g.addMultipleEdge, "directed k=%d", k)
+			}
+			g := dst.(graph.Graph)
+			if got, want := g.Nodes().Len(), 1<<uint(k); got != want {
+				t.Errorf("unexpected number of nodes: k=%d: got:%d want:%d", k, got, want)
+			}
+		}
+	}
+
+	// The node with all zero digits is the most connected
+	// and the node with all one digits is the least.
+	g := simple.NewDirectedGraph()
+	err := Kronecker(g, initiator, 8, rand.NewSource(1))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hi, lo := g.From(0).Len(), g.From(255).Len(); hi <= lo {
+		t.Errorf("unexpected degree ordering: deg(0)=%d deg(255)=%d", hi, lo)
+	}
+}
+
+func TestKroneckerErrors(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		initiator [][]float64
+		k         int
+	}{
+		{initiator: nil, k: 1},
+		{initiator: [][]float64{{0.5, 0.5}, {0.5}}, k: 1},
+		{initiator: [][]float64{{0.5, 0.4}, {0.3, 0.5}}, k: 1},
+		{initiator: [][]float64{{1.5, 0.4}, {0.4, 0.5}}, k: 1},
+		{initiator: [][]float64{{0.5, 0.4}, {0.4, 0.5}}, k: 0},
+	} {
+		err := Kronecker(simple.NewUndirectedGraph(), test.initiator, test.k, nil)
+		if err == nil {
+			t.Errorf("expected error for initiator=%v k=%d", test.initiator, test.k)
+		}
+	}
+}
@@ -0,0 +1,436 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gen
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+)
+
+// StochasticBlockModel constructs a stochastic block model graph in the
+// destination, dst. The sizes parameter specifies the number of nodes in
+// each block and edges between nodes in blocks i and j are formed with the
+// probability p[i][j]. If dst is undirected, p must be symmetric. If dst is
+// a graph.Directed, p[i][j] is the probability of an edge from a node in
+// block i to a node in block j. The nodes of each block are returned in
+// blocks. If src is not nil it is used as the random source, otherwise
+// rand.Float64 is used. The graph is constructed in O(n+m) time where m is
+// the number of edges added.
+func StochasticBlockModel(dst graph.Builder, sizes []int, p [][]float64, src rand.Source) (blocks [][]graph.Node, err error) {
+	if len(p) != len(sizes) {
+		return nil, fmt.Errorf("gen: probability matrix size mismatch: %d != %d", len(p), len(sizes))
+	}
+	_, isDirected := dst.(graph.Directed)
+	for i, row := range p {
+		if sizes[i] < 0 {
+			return nil, fmt.Errorf("gen: bad block size: sizes[%d]=%d", i, sizes[i])
+		}
+		if len(row) != len(sizes) {
+			return nil, fmt.Errorf("gen: probability matrix size mismatch: %d != %d", len(row), len(sizes))
+		}
+		for j, v := range row {
+			if v < 0 || v > 1 {
+				return nil, fmt.Errorf("gen: bad probability: p[%d][%d]=%v", i, j, v)
+			}
+			if !isDirected && v != p[j][i] {
+				return nil, fmt.Errorf("gen: asymmetric probability matrix for undirected graph: p[%d][%d]=%v p[%d][%d]=%v", i, j, v, j, i, p[j][i])
+			}
+		}
+	}
+
+	var rnd func() float64
+	if src == nil {
+		rnd = rand.Float64
+	} else {
+		rnd = rand.New(src).Float64
+	}
+
+	blocks = make([][]graph.Node, len(sizes))
+	for i, n := range sizes {
+		blocks[i] = addNodes(dst, n)
+	}
+
+	for i, bi := range blocks {
+		for j, bj := range blocks {
+			if !isDirected && j < i {
+				continue
+			}
+			switch {
+			case i != j:
+				bernoulliIndices(len(bi)*len(bj), p[i][j], rnd, func(k int) {
+					dst.SetEdge(dst.NewEdge(bi[k/len(bj)], bj[k%len(bj)]))
+				})
+			case isDirected:
+				n := len(bi)
+				bernoulliIndices(n*(n-1), p[i][i], rnd, func(k int) {
+					u := k / (n - 1)
+					v := k % (n - 1)
+					if v >= u {
+						v++
+					}
+					dst.SetEdge(dst.NewEdge(bi[u], bi[v]))
+				})
+			default:
+				n := len(bi)
+				bernoulliIndices(n*(n-1)/2, p[i][i], rnd, func(k int) {
+					v, w := edgeNodesFor(k, bi)
+					dst.SetEdge(dst.NewEdge(w, v))
+				})
+			}
+		}
+	}
+
+	return blocks, nil
+}
+
+// bernoulliIndices calls fn in increasing order for each index in [0, n)
+// selected independently with probability p. The indices are found by
+// geometric skipping in O(np) time.
+func bernoulliIndices(n int, p float64, rnd func() float64, fn func(i int)) {
+	switch {
+	case p <= 0:
+		return
+	case p >= 1:
+		for i := 0; i < n; i++ {
+			fn(i)
+		}
+		return
+	}
+	lp := math.Log(1 - p)
+	for i := -1; ; {
+		skip := math.Log(1-rnd()) / lp
+		if skip >= float64(n-i-1) {
+			return
+		}
+		i += 1 + int(skip)
+		fn(i)
+	}
+}
+
+// LFRParameters holds the parameters of an LFR benchmark graph.
+type LFRParameters struct {
+	// Nodes is the number of nodes
+	// in the graph.
+	Nodes int
+
+	// MeanDegree and MaxDegree are the
+	// mean and maximum node degrees.
+	MeanDegree float64
+	MaxDegree  int
+
+	// DegreeExponent is the exponent of
+	// the power law distribution of node
+	// degrees. It corresponds to τ1 in
+	// the paper.
+	DegreeExponent float64
+
+	// CommunityExponent is the exponent
+	// of the power law distribution of
+	// community sizes. It corresponds to
+	// τ2 in the paper.
+	CommunityExponent float64
+
+	// MinCommunity and MaxCommunity are
+	// the minimum and maximum community
+	// sizes.
+	MinCommunity, MaxCommunity int
+
+	// Mixing is the fraction of each
+	// node's edges that join it to nodes
+	// in other communities. It corresponds
+	// to μ in the paper.
+	Mixing float64
+}
+
+// LFR constructs an LFR benchmark graph in the destination, dst, with the
+// given parameters. Node degrees and community sizes are drawn from power law
+// distributions and each node has a fraction of approximately param.Mixing
+// of its edges joining it to nodes outside its community. The communities are
+// returned in communities. If src is not nil it is used as the random source,
+// otherwise rand.Float64 and rand.Intn are used.
+//
+// Edges are formed by randomly pairing edge end points. End point pairings
+// that would form a self loop or repeat an edge are discarded after a number
+// of attempts, so node degrees may be slightly lower than drawn.
+//
+// The algorithm is essentially as described in doi:10.1103/PhysRevE.78.046110.
+func LFR(dst graph.UndirectedBuilder, param LFRParameters, src rand.Source) (communities [][]graph.Node, err error) {
+	n := param.Nodes
+	switch {
+	case n < 1:
+		return nil, fmt.Errorf("gen: bad number of nodes: n=%d", n)
+	case param.MaxDegree < 1 || param.MaxDegree >= n:
+		return nil, fmt.Errorf("gen: bad maximum degree: max=%d", param.MaxDegree)
+	case param.MeanDegree < 1 || param.MeanDegree > float64(param.MaxDegree):
+		return nil, fmt.Errorf("gen: bad mean degree: mean=%v", param.MeanDegree)
+	case param.DegreeExponent <= 0:
+		return nil, fmt.Errorf("gen: bad degree exponent: tau1=%v", param.DegreeExponent)
+	case param.CommunityExponent <= 0:
+		return nil, fmt.Errorf("gen: bad community size exponent: tau2=%v", param.CommunityExponent)
+	case param.MinCommunity < 1 || param.MinCommunity > param.MaxCommunity || param.MaxCommunity > n:
+		return nil, fmt.Errorf("gen: bad community size range: [%d,%d]", param.MinCommunity, param.MaxCommunity)
+	case param.Mixing < 0 || param.Mixing > 1:
+		return nil, fmt.Errorf("gen: bad mixing parameter: mu=%v", param.Mixing)
+	}
+
+	var (
+		rnd  func() float64
+		rndN func(int) int
+	)
+	if src == nil {
+		rnd = rand.Float64
+		rndN = rand.Intn
+	} else {
+		r := rand.New(src)
+		rnd = r.Float64
+		rndN = r.Intn
+	}
+
+	// Draw node degrees.
+	maxDeg := float64(param.MaxDegree)
+	minDeg, ok := powerLawMin(param.MeanDegree, maxDeg, param.DegreeExponent)
+	if !ok {
+		return nil, fmt.Errorf("gen: mean degree not attainable: mean=%v", param.MeanDegree)
+	}
+	degree := make([]int, n)
+	var sum int
+	for i := range degree {
+		degree[i] = int(math.Round(powerLaw(minDeg, maxDeg, param.DegreeExponent, rnd())))
+		if degree[i] < 1 {
+			degree[i] = 1
+		}
+		sum += degree[i]
+	}
+	if sum%2 != 0 {
+		for i, d := range degree {
+			if d < param.MaxDegree {
+				degree[i]++
+				break
+			}
+		}
+	}
+
+	// Draw community sizes.
+	sizes, err := lfrCommunitySizes(param, rnd)
+	if err != nil {
+		return nil, err
+	}
+
+	// Assign nodes to communities in descending order of
+	// internal degree so that nodes requiring large
+	// communities are placed first.
+	internal := make([]int, n)
+	for i, d := range degree {
+		internal[i] = int(math.Round((1 - param.Mixing) * float64(d)))
+	}
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return internal[order[i]] > internal[order[j]] })
+	community := make([]int, n)
+	members := make([][]int, len(sizes))
+	var candidates []int
+	for _, u := range order {
+		candidates = candidates[:0]
+		largest := -1
+		for c, s := range sizes {
+			if len(members[c]) == s {
+				continue
+			}
+			if internal[u] < s {
+				candidates = append(candidates, c)
+			}
+			if largest < 0 || s > sizes[largest] {
+				largest = c
+			}
+		}
+		var c int
+		if len(candidates) != 0 {
+			c = candidates[rndN(len(candidates))]
+		} else {
+			// No community with free space is large enough,
+			// so move excess internal edges to external edges.
+			c = largest
+			internal[u] = sizes[c] - 1
+		}
+		community[u] = c
+		members[c] = append(members[c], u)
+	}
+
+	nodes := addNodes(dst, n)
+	seen := make(map[[2]int]bool)
+	join := func(u, v int) bool {
+		if u == v {
+			return false
+		}
+		if u > v {
+			u, v = v, u
+		}
+		if seen[[2]int{u, v}] {
+			return false
+		}
+		seen[[2]int{u, v}] = true
+		dst.SetEdge(dst.NewEdge(nodes[u], nodes[v]))
+		return true
+	}
+
+	// Wire internal edges within each community.
+	for _, m := range members {
+		var stubs []int
+		for _, u := range m {
+			for i := 0; i < internal[u]; i++ {
+				stubs = append(stubs, u)
+			}
+		}
+		pairStubs(stubs, rndN, join)
+	}
+
+	// Wire external edges between communities.
+	var stubs []int
+	for u, d := range degree {
+		for i := internal[u]; i < d; i++ {
+			stubs = append(stubs, u)
+		}
+	}
+	pairStubs(stubs, rndN, func(u, v int) bool {
+		return community[u] != community[v] && join(u, v)
+	})
+
+	communities = make([][]graph.Node, len(members))
+	for c, m := range members {
+		communities[c] = make([]graph.Node, len(m))
+		for i, u := range m {
+			communities[c][i] = nodes[u]
+		}
+	}
+	return communities, nil
+}
+
+// lfrCommunitySizes returns community sizes drawn from a power law
+// distribution with the parameters in param that sum to param.Nodes.
+func lfrCommunitySizes(param LFRParameters, rnd func() float64) ([]int, error) {
+	min := param.MinCommunity
+	max := param.MaxCommunity
+	var (
+		sizes []int
+		sum   int
+	)
+	for sum < param.Nodes {
+		s := int(math.Round(powerLaw(float64(min), float64(max), param.CommunityExponent, rnd())))
+		sizes = append(sizes, s)
+		sum += s
+	}
+
+	// Adjust sizes to the number of nodes, shrinking communities
+	// toward the minimum size and then dropping communities if
+	// there are too many nodes, and growing communities toward
+	// the maximum size if there are too few.
+	diff := param.Nodes - sum
+	for diff < 0 {
+		for i := len(sizes) - 1; i >= 0 && diff < 0; i-- {
+			d := sizes[i] - min
+			if d > -diff {
+				d = -diff
+			}
+			sizes[i] -= d
+			diff += d
+		}
+		if diff < 0 {
+			diff += sizes[len(sizes)-1]
+			sizes = sizes[:len(sizes)-1]
+		}
+	}
+	for i := 0; diff > 0 && i < len(sizes); i++ {
+		d := max - sizes[i]
+		if d > diff {
+			d = diff
+		}
+		sizes[i] += d
+		diff -= d
+	}
+	if diff != 0 {
+		return nil, errors.New("gen: unable to partition nodes into communities")
+	}
+	return sizes, nil
+}
+
+// pairStubs randomly pairs the edge end points in stubs, calling join for
+// each pair. If join returns false, the pair is returned to the pool of
+// unpaired end points. Pairing is attempted for a limited number of rounds.
+func pairStubs(stubs []int, rndN func(int) int, join func(u, v int) bool) {
+	const rounds = 10
+	for r := 0; r < rounds && len(stubs) > 1; r++ {
+		for i := len(stubs) - 1; i > 0; i-- {
+			j := rndN(i + 1)
+			stubs[i], stubs[j] = stubs[j], stubs[i]
+		}
+		rest := stubs[:0]
+		for i := 0; i+1 < len(stubs); i += 2 {
+			u, v := stubs[i], stubs[i+1]
+			if !join(u, v) {
+				rest = append(rest, u, v)
+			}
+		}
+		if len(stubs)%2 != 0 {
+			rest = append(rest, stubs[len(stubs)-1])
+		}
+		stubs = rest
+	}
+}
+
+// powerLaw returns the value of the inverse cumulative distribution
+// function at u for a continuous power law distribution with exponent
+// tau truncated to [min, max].
+func powerLaw(min, max, tau, u float64) float64 {
+	if tau == 1 {
+		return min * math.Pow(max/min, u)
+	}
+	a := math.Pow(min, 1-tau)
+	b := math.Pow(max, 1-tau)
+	return math.Pow(a+u*(b-a), 1/(1-tau))
+}
+
+// powerLawMean returns the mean of a continuous power law distribution
+// with exponent tau truncated to [min, max].
+func powerLawMean(min, max, tau float64) float64 {
+	switch tau {
+	case 1:
+		return (max - min) / math.Log(max/min)
+	case 2:
+		return math.Log(max/min) / (1/min - 1/max)
+	default:
+		return (1 - tau) / (2 - tau) * (math.Pow(max, 2-tau) - math.Pow(min, 2-tau)) / (math.Pow(max, 1-tau) - math.Pow(min, 1-tau))
+	}
+}
+
+// powerLawMin returns the lower bound of a continuous power law
+// distribution with exponent tau and upper bound max that has the
+// given mean. The lower bound is searched for in [1, max] and
+// powerLawMin returns false if no bound is found.
+func powerLawMin(mean, max, tau float64) (min float64, ok bool) {
+	if mean == max {
+		return max, true
+	}
+	lo, hi := 1.0, max
+	if powerLawMean(lo, hi, tau) > mean {
+		return 0, false
+	}
+	for i := 0; i < 100; i++ {
+		mid := (lo + hi) / 2
+		if powerLawMean(mid, max, tau) < mean {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return (lo + hi) / 2, true
+}
@@ -0,0 +1,185 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gen
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+func TestStochasticBlockModelDeterministic(t *testing.T) {
+	t.Parallel()
+	sizes := []int{10, 20, 5}
+	p := [][]float64{
+		{1, 0, 0},
+		{0, 1, 0},
+		{0, 0, 1},
+	}
+	g := &gnUndirected{UndirectedBuilder: simple.NewUndirectedGraph()}
+	blocks, err := StochasticBlockModel(g, sizes, p, rand.NewSource(1))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checkSimple(t, g.addSelfLoop, g.addMultipleEdge, "sizes=%v", sizes)
+	for i, b := range blocks {
+		if len(b) != sizes[i] {
+			t.Errorf("unexpected block size for block %d: got:%d want:%d", i, len(b), sizes[i])
+		}
+		for j, u := range b {
+			for _, v := range b[:j] {
+				if !g.HasEdgeBetween(u.ID(), v.ID()) {
+					t.Errorf("missing edge in block %d: %d--%d", i, u.ID(), v.ID())
+				}
+			}
+		}
+	}
+	if got, want := edgeCount(g), 45+190+10; got != want {
+		t.Errorf("unexpected number of edges: got:%d want:%d", got, want)
+	}
+}
+
+func TestStochasticBlockModel(t *testing.T) {
+	t.Parallel()
+	sizes := []int{200, 300}
+	p := [][]float64{
+		{0.1, 0.01},
+		{0.01, 0.2},
+	}
+	for _, dst := range []graph.Builder{
+		&gnUndirected{UndirectedBuilder: simple.NewUndirectedGraph()},
+		&gnDirected{DirectedBuilder: simple.NewDirectedGraph()},
+	} {
+		_, isDirected := dst.(graph.Directed)
+		blocks, err := StochasticBlockModel(dst, sizes, p, rand.NewSource(1))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		switch g := dst.(type) {
+		case *gnUndirected:
+			checkSimple(t, g.addSelfLoop, g.addMultipleEdge, "undirected")
+		case *gnDirected:
+			checkSimple(t, g.addSelfLoop, g.addMultipleEdge, "directed")
+		}
+
+		block := make(map[int64]int)
+		for i, b := range blocks {
+			for _, u := range b {
+				block[u.ID()] = i
+			}
+		}
+		counts := make([][]int, len(sizes))
+		for i := range counts {
+			counts[i] = make([]int, len(sizes))
+		}
+		g := dst.(graph.Graph)
+		nodes := graph.NodesOf(g.Nodes())
+		for _, u := range nodes {
+			for _, v := range graph.NodesOf(g.From(u.ID())) {
+				counts[block[u.ID()]][block[v.ID()]]++
+			}
+		}
+		for i, row := range counts {
+			for j, got := range row {
+				pairs := float64(sizes[i] * sizes[j])
+				if i == j {
+					pairs = float64(sizes[i] * (sizes[i] - 1))
+				}
+				if !isDirected && i == j {
+					// Undirected edges within blocks
+					// are counted in both directions.
+					got /= 2
+					pairs /= 2
+				}
+				want := p[i][j] * pairs
+				sd := math.Sqrt(pairs * p[i][j] * (1 - p[i][j]))
+				if math.Abs(float64(got)-want) > 5*sd {
+					t.Errorf("unexpected number of edges between blocks %d and %d (directed=%t): got:%d want:%.0f±%.0f",
+						i, j, isDirected, got, want, 5*sd)
+				}
+			}
+		}
+	}
+}
+
+func TestStochasticBlockModelErrors(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		sizes []int
+		p     [][]float64
+	}{
+		{sizes: []int{1, 2}, p: [][]float64{{0.5}}},
+		{sizes: []int{1, 2}, p: [][]float64{{0.5, 0.1}, {0.1}}},
+		{sizes: []int{1, 2}, p: [][]float64{{0.5, 0.1}, {0.2, 0.5}}},
+		{sizes: []int{1, 2}, p: [][]float64{{1.5, 0.1}, {0.1, 0.5}}},
+		{sizes: []int{-1, 2}, p: [][]float64{{0.5, 0.1}, {0.1, 0.5}}},
+	} {
+		_, err := StochasticBlockModel(simple.NewUndirectedGraph(), test.sizes, test.p, nil)
+		if err == nil {
+			t.Errorf("expected error for sizes=%v p=%v", test.sizes, test.p)
+		}
+	}
+}
+
+func TestLFR(t *testing.T) {
+	t.Parallel()
+	for _, mu := range []float64{0, 0.1, 0.3, 0.5} {
+		param := LFRParameters{
+			Nodes:             1000,
+			MeanDegree:        15,
+			MaxDegree:         50,
+			DegreeExponent:    2,
+			CommunityExponent: 1,
+			MinCommunity:      20,
+			MaxCommunity:      100,
+			Mixing:            mu,
+		}
+		g := &gnUndirected{UndirectedBuilder: simple.NewUndirectedGraph()}
+		communities, err := LFR(g, param, rand.NewSource(1))
+		if err != nil {
+			t.Fatalf("unexpected error: mu=%v: %v", mu, err)
+		}
+		checkSimple(t, g.addSelfLoop, g.addMultipleEdge, "mu=%v", mu)
+
+		community := make(map[int64]int)
+		for i, c := range communities {
+			if len(c) < param.MinCommunity || param.MaxCommunity < len(c) {
+				t.Errorf("community size out of range: mu=%v: got:%d", mu, len(c))
+			}
+			for _, u := range c {
+				if _, exists := community[u.ID()]; exists {
+					t.Errorf("node %d in more than one community: mu=%v", u.ID(), mu)
+				}
+				community[u.ID()] = i
+			}
+		}
+		if len(community) != param.Nodes {
+			t.Errorf("unexpected number of nodes in communities: mu=%v: got:%d want:%d", mu, len(community), param.Nodes)
+		}
+
+		var degree, external int
+		nodes := graph.NodesOf(g.Nodes())
+		for _, u := range nodes {
+			for _, v := range graph.NodesOf(g.From(u.ID())) {
+				degree++
+				if community[u.ID()] != community[v.ID()] {
+					external++
+				}
+			}
+		}
+		mean := float64(degree) / float64(param.Nodes)
+		if math.Abs(mean-param.MeanDegree) > 1.5 {
+			t.Errorf("unexpected mean degree: mu=%v: got:%v want:%v", mu, mean, param.MeanDegree)
+		}
+		mixing := float64(external) / float64(degree)
+		if math.Abs(mixing-mu) > 0.05 {
+			t.Errorf("unexpected mixing: got:%v want:%v", mixing, mu)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package layout
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/path"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/mds"
+)
+
+// graphDistances returns the shortest path distances between all pairs of
+// nodes in g, ignoring edge direction, indexed by the position of the nodes
+// in nodes. If g is a graph.Weighted, edge weights are used as edge lengths
+// and the shorter of a pair of reciprocal directed edges is used. Pairs of
+// nodes in different connected components are given a distance one greater
+// than the largest finite distance in the graph.
+//
+// graphDistances will panic if g has a negative edge weight.
+func graphDistances(g graph.Graph, nodes []graph.Node) [][]float64 {
+	switch d := g.(type) {
+	case graph.WeightedDirected:
+		g = graph.UndirectWeighted{G: d, Merge: shorter}
+	case graph.Directed:
+		g = graph.Undirect{G: d}
+	}
+	paths := path.DijkstraAllPaths(g)
+
+	dist := make([][]float64, len(nodes))
+	var max float64
+	for i, u := range nodes {
+		dist[i] = make([]float64, len(nodes))
+		for j, v := range nodes {
+			w := paths.Weight(u.ID(), v.ID())
+			dist[i][j] = w
+			if !math.IsInf(w, 1) && w > max {
+				max = w
+			}
+		}
+	}
+	for _, row := range dist {
+		for j, w := range row {
+			if math.IsInf(w, 1) {
+				row[j] = max + 1
+			}
+		}
+	}
+	return dist
+}
+
+// shorter returns the weight of the shorter of the edges xe and ye,
+// ignoring absent edges.
+func shorter(x, y float64, xe, ye graph.Edge) float64 {
+	switch {
+	case xe == nil:
+		return y
+	case ye == nil:
+		return x
+	default:
+		return math.Min(x, y)
+	}
+}
+
+// initialPositions returns initial node positions in dims dimensions for
+// nodes. If the layout is initialized, positions are obtained from coord,
+// otherwise a Torgerson scaling of the graph distances, dist, is used
+// where possible. Dimensions that cannot be obtained by scaling are filled
+// with random values, which are small if a scaling was found. Coincident
+// nodes are separated by a small random displacement. Random values are
+// obtained from src, or the global random number generator if src is nil.
+func initialPositions(nodes []graph.Node, dist [][]float64, dims int, initialized bool, coord func(id int64) []float64, src rand.Source) [][]float64 {
+	var rnd func() float64
+	if src == nil {
+		rnd = rand.Float64
+	} else {
+		rnd = rand.New(src).Float64
+	}
+
+	pos := make([][]float64, len(nodes))
+	if initialized {
+		for i, n := range nodes {
+			pos[i] = coord(n.ID())
+		}
+		separate(pos, rnd)
+		return pos
+	}
+	var (
+		k int
+		v mat.Dense
+	)
+	if len(nodes) != 0 {
+		d := mat.NewSymDense(len(nodes), nil)
+		for i, row := range dist {
+			for j := i; j < len(row); j++ {
+				d.SetSym(i, j, row[j])
+			}
+		}
+		k, _ = mds.TorgersonScaling(&v, nil, d)
+	}
+	// Dimensions not provided by the scaling are only
+	// given small values unless no scaling was found.
+	scale := 1.0
+	if k != 0 {
+		scale = 1e-3
+	}
+	for i := range nodes {
+		pos[i] = make([]float64, dims)
+		for j := range pos[i] {
+			if j < k {
+				pos[i][j] = v.At(i, j)
+			} else {
+				pos[i][j] = scale * rnd()
+			}
+		}
+	}
+	separate(pos, rnd)
+	return pos
+}
+
+// separate displaces positions that coincide with an earlier position
+// by a small random amount.
+func separate(pos [][]float64, rnd func() float64) {
+	for i := range pos {
+		for j := 0; j < i; j++ {
+			if !floats.EqualApprox(pos[i], pos[j], 1e-8) {
+				continue
+			}
+			for c := range pos[i] {
+				pos[i][c] += 1e-3 * (rnd() - 0.5)
+			}
+			j = -1
+		}
+	}
+}
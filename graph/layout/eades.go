@@ -12,6 +12,7 @@ import (
 	"gonum.org/v1/gonum/graph"
 	"gonum.org/v1/gonum/spatial/barneshut"
 	"gonum.org/v1/gonum/spatial/r2"
+	"gonum.org/v1/gonum/spatial/r3"
 )
 
 // EadesR2 implements the graph layout algorithm essentially as
@@ -93,35 +94,7 @@ func (u *EadesR2) Update(g graph.Graph, layout LayoutR2) bool {
 		u.forces[i] = f
 	}
 
-	// Handle edge weighting for attraction.
-	var weight func(uid, vid int64) float64
-	if wg, ok := g.(graph.Weighted); ok {
-		if _, ok := g.(graph.Directed); ok {
-			weight = func(xid, yid int64) float64 {
-				var w float64
-				f, ok := wg.Weight(xid, yid)
-				if ok {
-					w += f
-				}
-				r, ok := wg.Weight(yid, xid)
-				if ok {
-					w += r
-				}
-				return w
-			}
-		} else {
-			weight = func(xid, yid int64) float64 {
-				w, ok := wg.Weight(xid, yid)
-				if ok {
-					return w
-				}
-				return 0
-			}
-		}
-	} else {
-		// This is only called when the adjacency is known so just return unit.
-		weight = func(_, _ int64) float64 { return 1 }
-	}
+	weight := attractionWeight(g)
 
 	seen := make(map[[2]int64]bool)
 	for u.nodes.Next() {
@@ -167,6 +140,37 @@ func (u *EadesR2) Update(g graph.Graph, layout LayoutR2) bool {
 	return true
 }
 
+// attractionWeight returns a function returning the weight of the
+// attraction between adjacent nodes in g.
+func attractionWeight(g graph.Graph) func(xid, yid int64) float64 {
+	wg, ok := g.(graph.Weighted)
+	if !ok {
+		// This is only called when the adjacency is known so just return unit.
+		return func(_, _ int64) float64 { return 1 }
+	}
+	if _, ok := g.(graph.Directed); ok {
+		return func(xid, yid int64) float64 {
+			var w float64
+			f, ok := wg.Weight(xid, yid)
+			if ok {
+				w += f
+			}
+			r, ok := wg.Weight(yid, xid)
+			if ok {
+				w += r
+			}
+			return w
+		}
+	}
+	return func(xid, yid int64) float64 {
+		w, ok := wg.Weight(xid, yid)
+		if ok {
+			return w
+		}
+		return 0
+	}
+}
+
 type eadesR2Node struct {
 	id  int64
 	pos r2.Vec
@@ -174,3 +178,136 @@ type eadesR2Node struct {
 
 func (p eadesR2Node) Coord2() r2.Vec { return p.pos }
 func (p eadesR2Node) Mass() float64  { return 1 }
+
+// EadesR3 implements the graph layout algorithm essentially as
+// described in "A heuristic for graph drawing", Congressus
+// numerantium 42:149-160, extended to three dimensions.
+// The implementation here uses the Barnes-Hut approximation for
+// global repulsion calculation, and edge weights are considered
+// when calculating adjacent node attraction.
+type EadesR3 struct {
+	// Updates is the number of updates to perform.
+	Updates int
+
+	// Repulsion is the strength of the global
+	// repulsive force between nodes in the
+	// layout. It corresponds to C3 in the paper.
+	Repulsion float64
+
+	// Rate is the gradient descent rate. It
+	// corresponds to C4 in the paper.
+	Rate float64
+
+	// Theta is the Barnes-Hut theta constant.
+	Theta float64
+
+	// Src is the source of randomness used
+	// to initialize the nodes' locations. If
+	// Src is nil, the global random number
+	// generator is used.
+	Src rand.Source
+
+	nodes   graph.Nodes
+	indexOf map[int64]int
+
+	particles []barneshut.Particle3
+	forces    []r3.Vec
+}
+
+// Update is the EadesR3 spatial graph update function.
+func (u *EadesR3) Update(g graph.Graph, layout LayoutR3) bool {
+	if u.Updates <= 0 {
+		return false
+	}
+	u.Updates--
+
+	if !layout.IsInitialized() {
+		var rnd func() float64
+		if u.Src == nil {
+			rnd = rand.Float64
+		} else {
+			rnd = rand.New(u.Src).Float64
+		}
+		u.nodes = g.Nodes()
+		u.indexOf = make(map[int64]int, u.nodes.Len())
+		if u.nodes.Len() >= 0 {
+			u.particles = make([]barneshut.Particle3, 0, u.nodes.Len())
+		}
+		for u.nodes.Next() {
+			id := u.nodes.Node().ID()
+			u.indexOf[id] = len(u.particles)
+			u.particles = append(u.particles, eadesR3Node{id: id, pos: r3.Vec{X: rnd(), Y: rnd(), Z: rnd()}})
+		}
+		u.forces = make([]r3.Vec, len(u.particles))
+	}
+	u.nodes.Reset()
+
+	// Apply global repulsion.
+	volume, err := barneshut.NewVolume(u.particles)
+	if err != nil {
+		return false
+	}
+	var updated bool
+	for i, p := range u.particles {
+		f := volume.ForceOn(p, u.Theta, barneshut.Gravity3).Scale(-u.Repulsion)
+		// Prevent marginal updates that can be caused by
+		// floating point error when nodes are very far apart.
+		if r3.Norm(f) > 1e-12 {
+			updated = true
+		}
+		u.forces[i] = f
+	}
+
+	weight := attractionWeight(g)
+
+	seen := make(map[[2]int64]bool)
+	for u.nodes.Next() {
+		xid := u.nodes.Node().ID()
+		xidx := u.indexOf[xid]
+		to := g.From(xid)
+		for to.Next() {
+			yid := to.Node().ID()
+			if seen[[2]int64{xid, yid}] {
+				continue
+			}
+			seen[[2]int64{yid, xid}] = true
+			yidx := u.indexOf[yid]
+
+			// Apply adjacent node attraction.
+			v := u.particles[yidx].Coord3().Sub(u.particles[xidx].Coord3())
+			f := v.Scale(weight(xid, yid) * math.Log(r3.Norm(v)))
+			if math.IsInf(f.X, 0) || math.IsInf(f.Y, 0) || math.IsInf(f.Z, 0) {
+				return false
+			}
+			if r3.Norm(f) > 1e-12 {
+				updated = true
+			}
+			u.forces[xidx] = u.forces[xidx].Add(f)
+			u.forces[yidx] = u.forces[yidx].Sub(f)
+		}
+	}
+
+	if !updated {
+		return false
+	}
+
+	rate := u.Rate
+	if rate == 0 {
+		rate = 0.1
+	}
+	for i, f := range u.forces {
+		n := u.particles[i].(eadesR3Node)
+		n.pos = n.pos.Add(f.Scale(rate))
+		u.particles[i] = n
+		layout.SetCoord3(n.id, n.pos)
+	}
+	return true
+}
+
+type eadesR3Node struct {
+	id  int64
+	pos r3.Vec
+}
+
+func (p eadesR3Node) Coord3() r3.Vec { return p.pos }
+func (p eadesR3Node) Mass() float64  { return 1 }
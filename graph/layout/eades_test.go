@@ -5,6 +5,7 @@
 package layout
 
 import (
+	"math"
 	"path/filepath"
 	"testing"
 
@@ -231,3 +232,32 @@ func TestEadesR2(t *testing.T) {
 		}
 	}
 }
+
+func TestEadesR3(t *testing.T) {
+	for _, test := range eadesR2Tests {
+		u := EadesR3{
+			Updates:   test.param.Updates,
+			Repulsion: test.param.Repulsion,
+			Rate:      test.param.Rate,
+			Theta:     test.param.Theta,
+			Src:       rand.NewSource(1),
+		}
+		o := NewOptimizerR3(test.g, u.Update)
+		var n int
+		for o.Update() {
+			n++
+		}
+		if n > test.param.Updates {
+			t.Errorf("unexpected number of iterations for %q: got:%d want<=%d", test.name, n, test.param.Updates)
+		}
+
+		nodes := test.g.Nodes()
+		for nodes.Next() {
+			id := nodes.Node().ID()
+			p := o.Coord3(id)
+			if math.IsNaN(p.X) || math.IsNaN(p.Y) || math.IsNaN(p.Z) {
+				t.Errorf("unexpected NaN position for node %d in %q: %v", id, test.name, p)
+			}
+		}
+	}
+}
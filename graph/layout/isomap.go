@@ -9,6 +9,7 @@ import (
 	"gonum.org/v1/gonum/graph/path"
 	"gonum.org/v1/gonum/mat"
 	"gonum.org/v1/gonum/spatial/r2"
+	"gonum.org/v1/gonum/spatial/r3"
 	"gonum.org/v1/gonum/stat/mds"
 )
 
@@ -41,6 +42,24 @@ func (IsomapR2) Update(g graph.Graph, layout LayoutR2) bool {
 	return false
 }
 
+// IsomapR3 implements a graph layout algorithm based on the Isomap
+// non-linear dimensionality reduction method. It is the three
+// dimensional analogue of IsomapR2 and has the same limitations.
+type IsomapR3 struct{}
+
+// Update is the IsomapR3 spatial graph update function.
+func (IsomapR3) Update(g graph.Graph, layout LayoutR3) bool {
+	nodes := graph.NodesOf(g.Nodes())
+	v := isomap(g, nodes, 3)
+	if v == nil {
+		return false
+	}
+	for i, n := range nodes {
+		layout.SetCoord3(n.ID(), r3.Vec{X: v.At(i, 0), Y: v.At(i, 1), Z: v.At(i, 2)})
+	}
+	return false
+}
+
 func isomap(g graph.Graph, nodes []graph.Node, dims int) *mat.Dense {
 	p, ok := path.FloydWarshall(g)
 	if !ok {
@@ -8,9 +8,11 @@ import (
 	"path/filepath"
 	"testing"
 
+	"gonum.org/v1/gonum/floats/scalar"
 	"gonum.org/v1/gonum/graph"
 	"gonum.org/v1/gonum/graph/simple"
 	"gonum.org/v1/gonum/spatial/r2"
+	"gonum.org/v1/gonum/spatial/r3"
 	"gonum.org/v1/plot"
 	"gonum.org/v1/plot/vg"
 )
@@ -188,3 +190,25 @@ func TestIsomapR2(t *testing.T) {
 		}
 	}
 }
+
+func TestIsomapR3(t *testing.T) {
+	// The complete graph on four nodes has a regular
+	// tetrahedron as its exact embedding in R3.
+	g := simple.NewUndirectedGraph()
+	for i := 0; i < 4; i++ {
+		for j := i + 1; j < 4; j++ {
+			g.SetEdge(simple.Edge{F: simple.Node(i), T: simple.Node(j)})
+		}
+	}
+	o := NewOptimizerR3(orderedGraph{g}, IsomapR3{}.Update)
+	for o.Update() {
+	}
+	for i := int64(0); i < 4; i++ {
+		for j := i + 1; j < 4; j++ {
+			got := r3.Norm(o.Coord3(i).Sub(o.Coord3(j)))
+			if !scalar.EqualWithinAbsOrRel(got, 1, 1e-10, 1e-10) {
+				t.Errorf("unexpected distance between %d and %d: got:%v want:1", i, j, got)
+			}
+		}
+	}
+}
@@ -0,0 +1,309 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package layout
+
+import (
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/spatial/r2"
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+// KamadaKawaiR2 implements the graph layout algorithm described in
+// "An algorithm for drawing general undirected graphs", Information
+// Processing Letters 31(1):7-15. Nodes are connected by springs whose
+// natural lengths are proportional to the shortest path distance
+// between the nodes, and the energy of the spring system is minimized
+// by moving one node at a time using Newton-Raphson steps.
+//
+// Edge direction is ignored and, if the graph is a graph.Weighted,
+// edge weights are used as edge lengths; edge weights must not be
+// negative. Pairs of nodes in different connected components are
+// treated as being one step further apart than the most distant
+// connected pair. The all pairs shortest path distances are
+// calculated using Dijkstra's algorithm and so KamadaKawaiR2 will
+// not scale to large graphs.
+type KamadaKawaiR2 struct {
+	// Updates is the maximum number of updates
+	// to perform. Each update moves a single
+	// node.
+	Updates int
+
+	// Length is the ideal length of an edge
+	// with unit length. If Length is zero,
+	// one is used. It corresponds to L in
+	// the paper.
+	Length float64
+
+	// Strength is the strength of springs
+	// between nodes. If Strength is zero,
+	// one is used. It corresponds to K in
+	// the paper.
+	Strength float64
+
+	// Tolerance is the magnitude of the
+	// energy gradient below which the layout
+	// is considered to have converged. If
+	// Tolerance is zero, 1e-4 is used.
+	Tolerance float64
+
+	// Src is the source of randomness used
+	// to initialize the nodes' locations
+	// when they cannot be obtained from a
+	// Torgerson scaling of the node distances.
+	// If Src is nil, the global random number
+	// generator is used.
+	Src rand.Source
+
+	kk *kamadaKawai
+}
+
+// Update is the KamadaKawaiR2 spatial graph update function. If the
+// layout is initialized when Update is first called, the layout's
+// node positions are used as the starting configuration.
+func (u *KamadaKawaiR2) Update(g graph.Graph, layout LayoutR2) bool {
+	if u.Updates <= 0 {
+		return false
+	}
+	u.Updates--
+
+	if u.kk == nil || !layout.IsInitialized() {
+		coord := func(id int64) []float64 {
+			c := layout.Coord2(id)
+			return []float64{c.X, c.Y}
+		}
+		u.kk = newKamadaKawai(g, 2, u.Length, u.Strength, layout.IsInitialized(), coord, u.Src)
+		for i, id := range u.kk.ids {
+			layout.SetCoord2(id, r2.Vec{X: u.kk.pos[i][0], Y: u.kk.pos[i][1]})
+		}
+	}
+
+	i, ok := u.kk.step(u.Tolerance)
+	if !ok {
+		return false
+	}
+	layout.SetCoord2(u.kk.ids[i], r2.Vec{X: u.kk.pos[i][0], Y: u.kk.pos[i][1]})
+	return true
+}
+
+// KamadaKawaiR3 implements the graph layout algorithm described in
+// "An algorithm for drawing general undirected graphs", Information
+// Processing Letters 31(1):7-15, in three dimensions. See KamadaKawaiR2
+// for details.
+type KamadaKawaiR3 struct {
+	// Updates is the maximum number of updates
+	// to perform. Each update moves a single
+	// node.
+	Updates int
+
+	// Length is the ideal length of an edge
+	// with unit length. If Length is zero,
+	// one is used. It corresponds to L in
+	// the paper.
+	Length float64
+
+	// Strength is the strength of springs
+	// between nodes. If Strength is zero,
+	// one is used. It corresponds to K in
+	// the paper.
+	Strength float64
+
+	// Tolerance is the magnitude of the
+	// energy gradient below which the layout
+	// is considered to have converged. If
+	// Tolerance is zero, 1e-4 is used.
+	Tolerance float64
+
+	// Src is the source of randomness used
+	// to initialize the nodes' locations
+	// when they cannot be obtained from a
+	// Torgerson scaling of the node distances.
+	// If Src is nil, the global random number
+	// generator is used.
+	Src rand.Source
+
+	kk *kamadaKawai
+}
+
+// Update is the KamadaKawaiR3 spatial graph update function. If the
+// layout is initialized when Update is first called, the layout's
+// node positions are used as the starting configuration.
+func (u *KamadaKawaiR3) Update(g graph.Graph, layout LayoutR3) bool {
+	if u.Updates <= 0 {
+		return false
+	}
+	u.Updates--
+
+	if u.kk == nil || !layout.IsInitialized() {
+		coord := func(id int64) []float64 {
+			c := layout.Coord3(id)
+			return []float64{c.X, c.Y, c.Z}
+		}
+		u.kk = newKamadaKawai(g, 3, u.Length, u.Strength, layout.IsInitialized(), coord, u.Src)
+		for i, id := range u.kk.ids {
+			layout.SetCoord3(id, r3.Vec{X: u.kk.pos[i][0], Y: u.kk.pos[i][1], Z: u.kk.pos[i][2]})
+		}
+	}
+
+	i, ok := u.kk.step(u.Tolerance)
+	if !ok {
+		return false
+	}
+	layout.SetCoord3(u.kk.ids[i], r3.Vec{X: u.kk.pos[i][0], Y: u.kk.pos[i][1], Z: u.kk.pos[i][2]})
+	return true
+}
+
+// kamadaKawai holds the state of a Kamada-Kawai spring system.
+type kamadaKawai struct {
+	ids []int64
+	pos [][]float64
+
+	// k and l are the spring strengths
+	// and natural lengths between nodes.
+	k, l [][]float64
+
+	// grad is the gradient of the energy
+	// with respect to each node's position.
+	grad [][]float64
+}
+
+func newKamadaKawai(g graph.Graph, dims int, length, strength float64, initialized bool, coord func(int64) []float64, src rand.Source) *kamadaKawai {
+	if length == 0 {
+		length = 1
+	}
+	if strength == 0 {
+		strength = 1
+	}
+	nodes := graph.NodesOf(g.Nodes())
+	dist := graphDistances(g, nodes)
+	kk := kamadaKawai{
+		ids:  make([]int64, len(nodes)),
+		pos:  initialPositions(nodes, dist, dims, initialized, coord, src),
+		k:    make([][]float64, len(nodes)),
+		l:    make([][]float64, len(nodes)),
+		grad: make([][]float64, len(nodes)),
+	}
+	for i, n := range nodes {
+		kk.ids[i] = n.ID()
+		kk.k[i] = make([]float64, len(nodes))
+		kk.l[i] = make([]float64, len(nodes))
+		for j, d := range dist[i] {
+			if i == j || d == 0 {
+				continue
+			}
+			kk.k[i][j] = strength / (d * d)
+			kk.l[i][j] = length * d
+		}
+	}
+	for i := range nodes {
+		kk.grad[i] = kk.gradient(i)
+	}
+	return &kk
+}
+
+// gradient returns the gradient of the energy with respect to the
+// position of node i.
+func (kk *kamadaKawai) gradient(i int) []float64 {
+	g := make([]float64, len(kk.pos[i]))
+	for j := range kk.pos {
+		if j != i {
+			kk.addTerm(g, i, j, 1)
+		}
+	}
+	return g
+}
+
+// addTerm adds the contribution of the spring between nodes i and j
+// to the energy gradient with respect to the position of node i, dst,
+// scaled by f.
+func (kk *kamadaKawai) addTerm(dst []float64, i, j int, f float64) {
+	d := floats.Distance(kk.pos[i], kk.pos[j], 2)
+	if d == 0 {
+		return
+	}
+	k := kk.k[i][j]
+	l := kk.l[i][j]
+	for c := range dst {
+		delta := kk.pos[i][c] - kk.pos[j][c]
+		dst[c] += f * k * (delta - l*delta/d)
+	}
+}
+
+// step moves the node with the largest energy gradient by a single
+// Newton-Raphson step and returns the index of the moved node. If the
+// largest gradient magnitude is less than tol, no node is moved and
+// step returns false.
+func (kk *kamadaKawai) step(tol float64) (int, bool) {
+	if tol == 0 {
+		tol = 1e-4
+	}
+	m := -1
+	max := tol
+	for i, g := range kk.grad {
+		n := floats.Norm(g, 2)
+		if n >= max {
+			m = i
+			max = n
+		}
+	}
+	if m < 0 {
+		return -1, false
+	}
+
+	for i := range kk.pos {
+		if i != m {
+			kk.addTerm(kk.grad[i], i, m, -1)
+		}
+	}
+
+	dims := len(kk.pos[m])
+	hess := mat.NewSymDense(dims, nil)
+	var sumK float64
+	for j := range kk.pos {
+		d := floats.Distance(kk.pos[m], kk.pos[j], 2)
+		if j == m || d == 0 {
+			continue
+		}
+		k := kk.k[m][j]
+		l := kk.l[m][j]
+		sumK += k
+		for a := 0; a < dims; a++ {
+			da := kk.pos[m][a] - kk.pos[j][a]
+			for b := a; b < dims; b++ {
+				db := kk.pos[m][b] - kk.pos[j][b]
+				h := k * l * da * db / (d * d * d)
+				if a == b {
+					h += k * (1 - l/d)
+				}
+				hess.SetSym(a, b, hess.At(a, b)+h)
+			}
+		}
+	}
+
+	// Take a Newton-Raphson step if the Hessian is positive definite,
+	// otherwise fall back to a scaled gradient descent step.
+	delta := mat.NewVecDense(dims, nil)
+	var chol mat.Cholesky
+	if chol.Factorize(hess) && chol.SolveVecTo(delta, mat.NewVecDense(dims, kk.grad[m])) == nil {
+		delta.ScaleVec(-1, delta)
+	} else if sumK != 0 {
+		delta.ScaleVec(-1/sumK, mat.NewVecDense(dims, kk.grad[m]))
+	}
+	for c := range kk.pos[m] {
+		kk.pos[m][c] += delta.AtVec(c)
+	}
+
+	for i := range kk.pos {
+		if i != m {
+			kk.addTerm(kk.grad[i], i, m, 1)
+		}
+	}
+	kk.grad[m] = kk.gradient(m)
+
+	return m, true
+}
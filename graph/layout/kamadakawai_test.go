@@ -0,0 +1,188 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package layout
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+	"gonum.org/v1/gonum/spatial/r2"
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+// exactLayoutTests are graphs with layouts that exactly
+// reproduce the graph distances between all nodes in
+// both R2 and R3.
+var exactLayoutTests = []struct {
+	name string
+	g    graph.Graph
+	want [][]float64
+}{
+	{
+		name: "line",
+		g: func() graph.Graph {
+			g := simple.NewUndirectedGraph()
+			g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1)})
+			return orderedGraph{g}
+		}(),
+		want: [][]float64{
+			{0, 1},
+			{1, 0},
+		},
+	},
+	{
+		name: "path",
+		g: func() graph.Graph {
+			g := simple.NewUndirectedGraph()
+			for i := 0; i < 4; i++ {
+				g.SetEdge(simple.Edge{F: simple.Node(i), T: simple.Node(i + 1)})
+			}
+			return orderedGraph{g}
+		}(),
+		want: [][]float64{
+			{0, 1, 2, 3, 4},
+			{1, 0, 1, 2, 3},
+			{2, 1, 0, 1, 2},
+			{3, 2, 1, 0, 1},
+			{4, 3, 2, 1, 0},
+		},
+	},
+	{
+		name: "directed weighted path",
+		g: func() graph.Graph {
+			g := simple.NewWeightedDirectedGraph(0, math.Inf(1))
+			g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(0), T: simple.Node(1), W: 2})
+			g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(2), T: simple.Node(1), W: 1})
+			g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(1), T: simple.Node(2), W: 3})
+			// Not wrapped in orderedGraph since the layout
+			// depends on the graph being graph.Weighted.
+			return g
+		}(),
+		want: [][]float64{
+			{0, 2, 3},
+			{2, 0, 1},
+			{3, 1, 0},
+		},
+	},
+	{
+		name: "triangle",
+		g: func() graph.Graph {
+			g := simple.NewUndirectedGraph()
+			g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1)})
+			g.SetEdge(simple.Edge{F: simple.Node(1), T: simple.Node(2)})
+			g.SetEdge(simple.Edge{F: simple.Node(2), T: simple.Node(0)})
+			return orderedGraph{g}
+		}(),
+		want: [][]float64{
+			{0, 1, 1},
+			{1, 0, 1},
+			{1, 1, 0},
+		},
+	},
+}
+
+func TestKamadaKawaiR2(t *testing.T) {
+	for _, test := range exactLayoutTests {
+		for _, length := range []float64{0, 2} {
+			u := KamadaKawaiR2{Updates: 1000, Length: length, Tolerance: 1e-8, Src: rand.NewSource(1)}
+			o := NewOptimizerR2(test.g, u.Update)
+			var n int
+			for o.Update() {
+				n++
+			}
+			if n == 1000 {
+				t.Errorf("failed to converge for %q with length=%v", test.name, length)
+			}
+			if length == 0 {
+				length = 1
+			}
+			checkLayoutDistances(t, test.name, test.want, length, 1e-6, func(uid, vid int64) float64 {
+				return r2.Norm(o.Coord2(uid).Sub(o.Coord2(vid)))
+			})
+		}
+	}
+}
+
+func TestKamadaKawaiR3(t *testing.T) {
+	for _, test := range exactLayoutTests {
+		u := KamadaKawaiR3{Updates: 1000, Tolerance: 1e-8, Src: rand.NewSource(1)}
+		o := NewOptimizerR3(test.g, u.Update)
+		var n int
+		for o.Update() {
+			n++
+		}
+		if n == 1000 {
+			t.Errorf("failed to converge for %q", test.name)
+		}
+		checkLayoutDistances(t, test.name, test.want, 1, 1e-6, func(uid, vid int64) float64 {
+			return r3.Norm(o.Coord3(uid).Sub(o.Coord3(vid)))
+		})
+	}
+}
+
+func TestKamadaKawaiR2Initialized(t *testing.T) {
+	// Start from a layout with all nodes on a line
+	// through the origin, with the wrong spacing.
+	test := exactLayoutTests[1]
+	o := NewOptimizerR2(test.g, nil)
+	layout := o.layout
+	for i := int64(0); i < 5; i++ {
+		layout.SetCoord2(i, r2.Vec{X: float64(i * i), Y: float64(i * i)})
+	}
+	u := KamadaKawaiR2{Updates: 1000, Tolerance: 1e-8}
+	for u.Update(test.g, layout) {
+	}
+	checkLayoutDistances(t, test.name, test.want, 1, 1e-6, func(uid, vid int64) float64 {
+		return r2.Norm(layout.Coord2(uid).Sub(layout.Coord2(vid)))
+	})
+}
+
+func TestKamadaKawaiR2Disconnected(t *testing.T) {
+	g := simple.NewUndirectedGraph()
+	g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1)})
+	g.SetEdge(simple.Edge{F: simple.Node(2), T: simple.Node(3)})
+	g.AddNode(simple.Node(4))
+
+	u := KamadaKawaiR2{Updates: 1000, Src: rand.NewSource(1)}
+	o := NewOptimizerR2(orderedGraph{g}, u.Update)
+	for o.Update() {
+	}
+	for i := int64(0); i < 5; i++ {
+		p := o.Coord2(i)
+		if math.IsNaN(p.X) || math.IsNaN(p.Y) {
+			t.Errorf("unexpected NaN position for node %d: %v", i, p)
+		}
+		for j := int64(0); j < i; j++ {
+			if o.Coord2(j) == p {
+				t.Errorf("unexpected coincident nodes %d and %d", i, j)
+			}
+		}
+	}
+	for _, e := range [][2]int64{{0, 1}, {2, 3}} {
+		got := r2.Norm(o.Coord2(e[0]).Sub(o.Coord2(e[1])))
+		if !scalar.EqualWithinAbsOrRel(got, 1, 0.1, 0.1) {
+			t.Errorf("unexpected edge length for %v: got:%v want:~1", e, got)
+		}
+	}
+}
+
+// checkLayoutDistances checks that the distances between nodes in a
+// layout returned by dist match want scaled by length.
+func checkLayoutDistances(t *testing.T, name string, want [][]float64, length, tol float64, dist func(uid, vid int64) float64) {
+	t.Helper()
+	for i, row := range want {
+		for j, w := range row {
+			got := dist(int64(i), int64(j))
+			if !scalar.EqualWithinAbsOrRel(got, length*w, tol, tol) {
+				t.Errorf("unexpected distance between %d and %d for %q: got:%v want:%v", i, j, name, got, length*w)
+			}
+		}
+	}
+}
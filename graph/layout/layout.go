@@ -7,6 +7,7 @@ package layout
 import (
 	"gonum.org/v1/gonum/graph"
 	"gonum.org/v1/gonum/spatial/r2"
+	"gonum.org/v1/gonum/spatial/r3"
 )
 
 // GraphR2 is a graph with planar spatial representation of node positions.
@@ -21,3 +22,16 @@ type NodeR2 struct {
 	graph.Node
 	Coord2 r2.Vec
 }
+
+// GraphR3 is a graph with spatial representation of node positions.
+type GraphR3 interface {
+	graph.Graph
+	LayoutNodeR3(id int64) NodeR3
+}
+
+// NodeR3 is a graph node with spatial representation of its position.
+// A NodeR3 is only valid when the graph.Node is not nil.
+type NodeR3 struct {
+	graph.Node
+	Coord3 r3.Vec
+}
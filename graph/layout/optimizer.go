@@ -7,6 +7,7 @@ package layout
 import (
 	"gonum.org/v1/gonum/graph"
 	"gonum.org/v1/gonum/spatial/r2"
+	"gonum.org/v1/gonum/spatial/r3"
 )
 
 // LayoutR2 implements graph layout updates and representations.
@@ -104,3 +105,99 @@ func (g OptimizerR2) HasEdgeBetween(xid, yid int64) bool { return g.g.HasEdgeBet
 // must be directly reachable from u as defined by the
 // From method.
 func (g OptimizerR2) Edge(uid, vid int64) graph.Edge { return g.g.Edge(uid, vid) }
+
+// LayoutR3 implements graph layout updates and representations.
+type LayoutR3 interface {
+	// IsInitialized returns whether the Layout is initialized.
+	IsInitialized() bool
+
+	// SetCoord3 sets the coordinates of the node with the given
+	// id to coords.
+	SetCoord3(id int64, coords r3.Vec)
+
+	// Coord3 returns the coordinated of the node with the given
+	// id in the graph layout.
+	Coord3(id int64) r3.Vec
+}
+
+// NewOptimizerR3 returns a new layout optimizer. If g implements LayoutR3 the layout
+// will be updated into g, otherwise the OptimizerR3 will hold the graph layout. A nil
+// value for update is a valid no-op layout update function.
+func NewOptimizerR3(g graph.Graph, update func(graph.Graph, LayoutR3) bool) OptimizerR3 {
+	l, ok := g.(LayoutR3)
+	if !ok {
+		l = make(coordinatesR3)
+	}
+	return OptimizerR3{
+		g:       g,
+		layout:  l,
+		Updater: update,
+	}
+}
+
+// coordinatesR3 is the default layout store for R3.
+type coordinatesR3 map[int64]r3.Vec
+
+func (c coordinatesR3) IsInitialized() bool            { return len(c) != 0 }
+func (c coordinatesR3) SetCoord3(id int64, pos r3.Vec) { c[id] = pos }
+func (c coordinatesR3) Coord3(id int64) r3.Vec         { return c[id] }
+
+// OptimizerR3 is a helper type that holds a graph and layout
+// optimization state.
+type OptimizerR3 struct {
+	g      graph.Graph
+	layout LayoutR3
+
+	// Updater is the function called for each call to Update.
+	// It updates the OptimizerR3's spatial distribution of the
+	// nodes in the backing graph.
+	Updater func(graph.Graph, LayoutR3) bool
+}
+
+// Coord3 returns the location of the node with the given
+// ID. The returned value is only valid if the node exists
+// in the graph.
+func (g OptimizerR3) Coord3(id int64) r3.Vec {
+	return g.layout.Coord3(id)
+}
+
+// Update updates the locations of the nodes in the graph
+// according to the provided update function. It returns whether
+// the update function is able to further refine the graph's
+// node locations.
+func (g OptimizerR3) Update() bool {
+	if g.Updater == nil {
+		return false
+	}
+	return g.Updater(g.g, g.layout)
+}
+
+// LayoutNodeR3 implements the GraphR3 interface.
+func (g OptimizerR3) LayoutNodeR3(id int64) NodeR3 {
+	n := g.g.Node(id)
+	if n == nil {
+		return NodeR3{}
+	}
+	return NodeR3{Node: n, Coord3: g.Coord3(id)}
+}
+
+// Node returns the node with the given ID if it exists
+// in the graph, and nil otherwise.
+func (g OptimizerR3) Node(id int64) graph.Node { return g.g.Node(id) }
+
+// Nodes returns all the nodes in the graph.
+func (g OptimizerR3) Nodes() graph.Nodes { return g.g.Nodes() }
+
+// From returns all nodes that can be reached directly
+// from the node with the given ID.
+func (g OptimizerR3) From(id int64) graph.Nodes { return g.g.From(id) }
+
+// HasEdgeBetween returns whether an edge exists between
+// nodes with IDs xid and yid without considering direction.
+func (g OptimizerR3) HasEdgeBetween(xid, yid int64) bool { return g.g.HasEdgeBetween(xid, yid) }
+
+// Edge returns the edge from u to v, with IDs uid and vid,
+// if such an edge exists and nil otherwise. The node v
+// must be directly reachable from u as defined by the
+// From method.
+func (g OptimizerR3) Edge(uid, vid int64) graph.Edge { return g.g.Edge(uid, vid) }
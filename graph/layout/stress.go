@@ -0,0 +1,236 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package layout
+
+import (
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/spatial/r2"
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+// StressR2 implements a graph layout algorithm based on stress
+// majorization as described in "Graph drawing by stress majorization",
+// Gansner, Koren and North, Graph Drawing 2004, LNCS 3383:239-250.
+// The layout minimizes the weighted stress
+//
+//  Σ_{i<j} d_ij^-2 (‖x_i-x_j‖ - d_ij)²
+//
+// where d_ij is the shortest path distance between nodes i and j.
+// Each update majorizes the stress with respect to each node's
+// position in turn, so the stress does not increase between updates.
+// The initial layout is obtained by Torgerson scaling of the node
+// distances.
+//
+// Edge direction is ignored and, if the graph is a graph.Weighted,
+// edge weights are used as edge lengths; edge weights must not be
+// negative. Pairs of nodes in different connected components are
+// treated as being one step further apart than the most distant
+// connected pair. The all pairs shortest path distances are
+// calculated using Dijkstra's algorithm and so StressR2 will not
+// scale to large graphs.
+type StressR2 struct {
+	// Updates is the maximum number of
+	// updates to perform.
+	Updates int
+
+	// Tolerance is the relative reduction in
+	// stress below which the layout is
+	// considered to have converged. If
+	// Tolerance is zero, 1e-4 is used.
+	Tolerance float64
+
+	// Src is the source of randomness used
+	// to initialize the nodes' locations
+	// when they cannot be obtained from a
+	// Torgerson scaling of the node distances.
+	// If Src is nil, the global random number
+	// generator is used.
+	Src rand.Source
+
+	s *stress
+}
+
+// Update is the StressR2 spatial graph update function. If the layout is
+// initialized when Update is first called, the layout's node positions are
+// used as the starting configuration.
+func (u *StressR2) Update(g graph.Graph, layout LayoutR2) bool {
+	if u.Updates <= 0 {
+		return false
+	}
+	u.Updates--
+
+	if u.s == nil || !layout.IsInitialized() {
+		coord := func(id int64) []float64 {
+			c := layout.Coord2(id)
+			return []float64{c.X, c.Y}
+		}
+		u.s = newStress(g, 2, layout.IsInitialized(), coord, u.Src)
+	}
+
+	ok := u.s.update(u.Tolerance)
+	for i, id := range u.s.ids {
+		layout.SetCoord2(id, r2.Vec{X: u.s.pos[i][0], Y: u.s.pos[i][1]})
+	}
+	return ok
+}
+
+// Stress returns the stress of the current layout. It returns zero if
+// Update has not been called.
+func (u *StressR2) Stress() float64 {
+	if u.s == nil {
+		return 0
+	}
+	return u.s.value()
+}
+
+// StressR3 implements a graph layout algorithm based on stress
+// majorization as described in "Graph drawing by stress majorization",
+// Gansner, Koren and North, Graph Drawing 2004, LNCS 3383:239-250, in
+// three dimensions. See StressR2 for details.
+type StressR3 struct {
+	// Updates is the maximum number of
+	// updates to perform.
+	Updates int
+
+	// Tolerance is the relative reduction in
+	// stress below which the layout is
+	// considered to have converged. If
+	// Tolerance is zero, 1e-4 is used.
+	Tolerance float64
+
+	// Src is the source of randomness used
+	// to initialize the nodes' locations
+	// when they cannot be obtained from a
+	// Torgerson scaling of the node distances.
+	// If Src is nil, the global random number
+	// generator is used.
+	Src rand.Source
+
+	s *stress
+}
+
+// Update is the StressR3 spatial graph update function. If the layout is
+// initialized when Update is first called, the layout's node positions are
+// used as the starting configuration.
+func (u *StressR3) Update(g graph.Graph, layout LayoutR3) bool {
+	if u.Updates <= 0 {
+		return false
+	}
+	u.Updates--
+
+	if u.s == nil || !layout.IsInitialized() {
+		coord := func(id int64) []float64 {
+			c := layout.Coord3(id)
+			return []float64{c.X, c.Y, c.Z}
+		}
+		u.s = newStress(g, 3, layout.IsInitialized(), coord, u.Src)
+	}
+
+	ok := u.s.update(u.Tolerance)
+	for i, id := range u.s.ids {
+		layout.SetCoord3(id, r3.Vec{X: u.s.pos[i][0], Y: u.s.pos[i][1], Z: u.s.pos[i][2]})
+	}
+	return ok
+}
+
+// Stress returns the stress of the current layout. It returns zero if
+// Update has not been called.
+func (u *StressR3) Stress() float64 {
+	if u.s == nil {
+		return 0
+	}
+	return u.s.value()
+}
+
+// stress holds the state of a stress majorization layout.
+type stress struct {
+	ids []int64
+	pos [][]float64
+
+	// dist and weight are the target distances
+	// and weights of each pair of nodes.
+	dist, weight [][]float64
+
+	// next is working space for a node position.
+	next []float64
+}
+
+func newStress(g graph.Graph, dims int, initialized bool, coord func(int64) []float64, src rand.Source) *stress {
+	nodes := graph.NodesOf(g.Nodes())
+	dist := graphDistances(g, nodes)
+	s := stress{
+		ids:    make([]int64, len(nodes)),
+		pos:    initialPositions(nodes, dist, dims, initialized, coord, src),
+		dist:   dist,
+		weight: make([][]float64, len(nodes)),
+		next:   make([]float64, dims),
+	}
+	for i, n := range nodes {
+		s.ids[i] = n.ID()
+		s.weight[i] = make([]float64, len(nodes))
+		for j, d := range dist[i] {
+			if i != j && d != 0 {
+				s.weight[i][j] = 1 / (d * d)
+			}
+		}
+	}
+	return &s
+}
+
+// value returns the weighted stress of the layout.
+func (s *stress) value() float64 {
+	var sum float64
+	for i := range s.pos {
+		for j := i + 1; j < len(s.pos); j++ {
+			r := floats.Distance(s.pos[i], s.pos[j], 2) - s.dist[i][j]
+			sum += s.weight[i][j] * r * r
+		}
+	}
+	return sum
+}
+
+// update performs a single majorization sweep over all nodes and
+// returns whether the relative reduction in stress was at least tol.
+func (s *stress) update(tol float64) bool {
+	if tol == 0 {
+		tol = 1e-4
+	}
+	before := s.value()
+	if before == 0 {
+		return false
+	}
+	for i, x := range s.pos {
+		for c := range s.next {
+			s.next[c] = 0
+		}
+		var sumW float64
+		for j, y := range s.pos {
+			w := s.weight[i][j]
+			if w == 0 {
+				continue
+			}
+			sumW += w
+			d := floats.Distance(x, y, 2)
+			for c := range s.next {
+				v := y[c]
+				if d != 0 {
+					v += s.dist[i][j] * (x[c] - y[c]) / d
+				}
+				s.next[c] += w * v
+			}
+		}
+		if sumW == 0 {
+			continue
+		}
+		for c := range x {
+			x[c] = s.next[c] / sumW
+		}
+	}
+	after := s.value()
+	return (before-after)/before >= tol
+}
@@ -0,0 +1,130 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package layout
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+	"gonum.org/v1/gonum/spatial/r2"
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+func TestStressR2(t *testing.T) {
+	for _, test := range exactLayoutTests {
+		u := StressR2{Updates: 1000, Tolerance: 1e-12, Src: rand.NewSource(1)}
+		o := NewOptimizerR2(test.g, u.Update)
+		for o.Update() {
+		}
+		checkLayoutDistances(t, test.name, test.want, 1, 1e-6, func(uid, vid int64) float64 {
+			return r2.Norm(o.Coord2(uid).Sub(o.Coord2(vid)))
+		})
+	}
+}
+
+func TestStressR3(t *testing.T) {
+	for _, test := range exactLayoutTests {
+		u := StressR3{Updates: 1000, Tolerance: 1e-12, Src: rand.NewSource(1)}
+		o := NewOptimizerR3(test.g, u.Update)
+		for o.Update() {
+		}
+		checkLayoutDistances(t, test.name, test.want, 1, 1e-6, func(uid, vid int64) float64 {
+			return r3.Norm(o.Coord3(uid).Sub(o.Coord3(vid)))
+		})
+	}
+}
+
+var stressDecreaseTests = []struct {
+	name string
+	g    graph.Graph
+}{
+	{
+		name: "tube",
+		g: func() graph.Graph {
+			edges := []simple.Edge{
+				{F: simple.Node(0), T: simple.Node(1)},
+				{F: simple.Node(0), T: simple.Node(2)},
+				{F: simple.Node(0), T: simple.Node(3)},
+				{F: simple.Node(1), T: simple.Node(2)},
+				{F: simple.Node(1), T: simple.Node(4)},
+				{F: simple.Node(2), T: simple.Node(5)},
+				{F: simple.Node(3), T: simple.Node(4)},
+				{F: simple.Node(3), T: simple.Node(5)},
+				{F: simple.Node(3), T: simple.Node(6)},
+				{F: simple.Node(4), T: simple.Node(5)},
+				{F: simple.Node(4), T: simple.Node(7)},
+				{F: simple.Node(5), T: simple.Node(8)},
+				{F: simple.Node(6), T: simple.Node(7)},
+				{F: simple.Node(6), T: simple.Node(8)},
+				{F: simple.Node(7), T: simple.Node(8)},
+			}
+			g := simple.NewUndirectedGraph()
+			for _, e := range edges {
+				g.SetEdge(e)
+			}
+			return orderedGraph{g}
+		}(),
+	},
+	{
+		name: "disconnected",
+		g: func() graph.Graph {
+			g := simple.NewUndirectedGraph()
+			g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1)})
+			g.SetEdge(simple.Edge{F: simple.Node(1), T: simple.Node(2)})
+			g.SetEdge(simple.Edge{F: simple.Node(3), T: simple.Node(4)})
+			g.AddNode(simple.Node(5))
+			return orderedGraph{g}
+		}(),
+	},
+	{
+		name: "random",
+		g: func() graph.Graph {
+			rnd := rand.New(rand.NewSource(1))
+			g := simple.NewUndirectedGraph()
+			for i := 0; i < 30; i++ {
+				g.AddNode(simple.Node(i))
+			}
+			for i := 0; i < 30; i++ {
+				for j := i + 1; j < 30; j++ {
+					if rnd.Float64() < 0.1 {
+						g.SetEdge(simple.Edge{F: simple.Node(i), T: simple.Node(j)})
+					}
+				}
+			}
+			return orderedGraph{g}
+		}(),
+	},
+}
+
+func TestStressDecreases(t *testing.T) {
+	for _, test := range stressDecreaseTests {
+		// Start from a random layout rather than a
+		// Torgerson scaling to exercise majorization.
+		layout := make(coordinatesR2)
+		rnd := rand.New(rand.NewSource(1))
+		nodes := test.g.Nodes()
+		for nodes.Next() {
+			layout.SetCoord2(nodes.Node().ID(), r2.Vec{X: rnd.Float64(), Y: rnd.Float64()})
+		}
+
+		u := StressR2{Updates: 100}
+		prev := -1.0
+		var n int
+		for u.Update(test.g, layout) {
+			n++
+			s := u.Stress()
+			if prev >= 0 && s > prev*(1+1e-12) {
+				t.Errorf("unexpected stress increase for %q at update %d: %v > %v", test.name, n, s, prev)
+			}
+			prev = s
+		}
+		if n == 0 {
+			t.Errorf("unexpected immediate convergence for %q", test.name)
+		}
+	}
+}
@@ -0,0 +1,504 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package layout
+
+import (
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+	"gonum.org/v1/gonum/spatial/r2"
+)
+
+// SugiyamaR2 implements a layered graph layout algorithm based on the
+// approach described in "Methods for visual understanding of hierarchical
+// system structures", Sugiyama, Tagawa and Toda, IEEE Transactions on
+// Systems, Man, and Cybernetics 11(2):109-125.
+//
+// The layout is constructed in four phases. Cycles are first removed by
+// reversing a small set of edges found using the greedy heuristic of
+// Eades, Lin and Smyth. Nodes are then assigned to layers by longest path
+// layering, with edges spanning more than one layer replaced by chains of
+// dummy nodes. Edge crossings are reduced by alternating downward and
+// upward layer sweeps that order nodes by the barycenter of their
+// neighbors. Finally, each node is placed as close to the mean position
+// of its neighbors as the node separation allows.
+//
+// Layers are placed from the top of the layout, with edges directed
+// downward where possible. Undirected graphs are oriented using a
+// breadth first traversal from their lowest ID nodes. Self loops are
+// ignored and the positions of dummy nodes are not recorded in the
+// layout.
+type SugiyamaR2 struct {
+	// LayerSeparation is the vertical distance
+	// between adjacent layers. If LayerSeparation
+	// is zero, one is used.
+	LayerSeparation float64
+
+	// NodeSeparation is the minimum horizontal
+	// distance between adjacent nodes in a layer.
+	// If NodeSeparation is zero, one is used.
+	NodeSeparation float64
+
+	// Sweeps is the maximum number of layer
+	// sweeps used for crossing minimization
+	// and coordinate assignment. If Sweeps is
+	// zero, 24 is used.
+	Sweeps int
+}
+
+// Update is the SugiyamaR2 spatial graph update function.
+func (u SugiyamaR2) Update(g graph.Graph, layout LayoutR2) bool {
+	layerSep := u.LayerSeparation
+	if layerSep == 0 {
+		layerSep = 1
+	}
+	nodeSep := u.NodeSeparation
+	if nodeSep == 0 {
+		nodeSep = 1
+	}
+	sweeps := u.Sweeps
+	if sweeps == 0 {
+		sweeps = 24
+	}
+
+	nodes := graph.NodesOf(g.Nodes())
+	sort.Sort(ordered.ByID(nodes))
+	if len(nodes) == 0 {
+		return false
+	}
+
+	l := newLayering(g, nodes)
+	l.minimizeCrossings(sweeps)
+	x := l.assignCoordinates(sweeps, nodeSep)
+	for i, n := range nodes {
+		layout.SetCoord2(n.ID(), r2.Vec{X: x[i], Y: float64(-l.layer[i]) * layerSep})
+	}
+	return false
+}
+
+// layering is a proper layering of a graph. Vertices with indices less
+// than the number of nodes in the graph correspond to graph nodes and
+// the remaining vertices are dummy nodes.
+type layering struct {
+	// nodes is the number of graph nodes.
+	nodes int
+
+	// layer is the layer of each vertex.
+	layer []int
+
+	// up and down are the neighbors of each
+	// vertex in the layers above and below.
+	up, down [][]int
+
+	// layers holds the vertices of each layer
+	// in order and pos is the position of
+	// each vertex in its layer.
+	layers [][]int
+	pos    []int
+}
+
+// newLayering returns a proper layering of the graph g with the given
+// nodes sorted by ID.
+func newLayering(g graph.Graph, nodes []graph.Node) *layering {
+	indexOf := make(map[int64]int, len(nodes))
+	for i, n := range nodes {
+		indexOf[n.ID()] = i
+	}
+
+	// Collect the edges of g without self loops.
+	out := make([][]int, len(nodes))
+	for i, n := range nodes {
+		to := graph.NodesOf(g.From(n.ID()))
+		sort.Sort(ordered.ByID(to))
+		for _, v := range to {
+			j := indexOf[v.ID()]
+			if j != i {
+				out[i] = append(out[i], j)
+			}
+		}
+	}
+
+	// Find an order of the nodes such that edges pointing
+	// backward in the order are few, and orient edges forward.
+	var order []int
+	if _, ok := g.(graph.Directed); ok {
+		order = greedyOrder(out)
+	} else {
+		order = breadthFirstOrder(out)
+	}
+	rank := make([]int, len(nodes))
+	for r, i := range order {
+		rank[i] = r
+	}
+	succ := make([][]int, len(nodes))
+	seen := make(map[[2]int]bool)
+	for i, adj := range out {
+		for _, j := range adj {
+			e := [2]int{i, j}
+			if rank[j] < rank[i] {
+				e = [2]int{j, i}
+			}
+			if seen[e] {
+				continue
+			}
+			seen[e] = true
+			succ[e[0]] = append(succ[e[0]], e[1])
+		}
+	}
+
+	// Assign layers by longest path from the sources.
+	layer := make([]int, len(nodes))
+	isSource := make([]bool, len(nodes))
+	for i := range isSource {
+		isSource[i] = true
+	}
+	for _, i := range order {
+		for _, j := range succ[i] {
+			isSource[j] = false
+			if layer[i]+1 > layer[j] {
+				layer[j] = layer[i] + 1
+			}
+		}
+	}
+	// Move sources down to just above their
+	// highest successor to shorten their edges.
+	for _, i := range order {
+		if !isSource[i] || len(succ[i]) == 0 {
+			continue
+		}
+		min := int(^uint(0) >> 1)
+		for _, j := range succ[i] {
+			if layer[j] < min {
+				min = layer[j]
+			}
+		}
+		layer[i] = min - 1
+	}
+
+	// Split long edges with dummy vertices.
+	l := layering{
+		nodes: len(nodes),
+		layer: layer,
+		up:    make([][]int, len(nodes)),
+		down:  make([][]int, len(nodes)),
+	}
+	for _, i := range order {
+		for _, j := range succ[i] {
+			prev := i
+			for k := layer[i] + 1; k < layer[j]; k++ {
+				d := len(l.layer)
+				l.layer = append(l.layer, k)
+				l.up = append(l.up, []int{prev})
+				l.down = append(l.down, nil)
+				l.down[prev] = append(l.down[prev], d)
+				prev = d
+			}
+			l.down[prev] = append(l.down[prev], j)
+			l.up[j] = append(l.up[j], prev)
+		}
+	}
+
+	// Place vertices in their layers in traversal order,
+	// with dummy vertices following their predecessors.
+	var depth int
+	for _, k := range l.layer {
+		if k+1 > depth {
+			depth = k + 1
+		}
+	}
+	l.layers = make([][]int, depth)
+	l.pos = make([]int, len(l.layer))
+	placed := make([]bool, len(l.layer))
+	var place func(v int)
+	place = func(v int) {
+		if placed[v] {
+			return
+		}
+		placed[v] = true
+		k := l.layer[v]
+		l.pos[v] = len(l.layers[k])
+		l.layers[k] = append(l.layers[k], v)
+		for _, w := range l.down[v] {
+			if w >= len(nodes) {
+				place(w)
+			}
+		}
+	}
+	for _, i := range order {
+		place(i)
+	}
+	return &l
+}
+
+// greedyOrder returns an ordering of the vertices of the directed graph
+// described by the adjacency list out using the greedy feedback arc set
+// heuristic described in "A fast and effective heuristic for the feedback
+// arc set problem", Eades, Lin and Smyth, Information Processing Letters
+// 47(6):319-323.
+func greedyOrder(out [][]int) []int {
+	n := len(out)
+	in := make([][]int, n)
+	outDeg := make([]int, n)
+	inDeg := make([]int, n)
+	for i, adj := range out {
+		for _, j := range adj {
+			in[j] = append(in[j], i)
+			outDeg[i]++
+			inDeg[j]++
+		}
+	}
+
+	removed := make([]bool, n)
+	remove := func(v int) {
+		removed[v] = true
+		for _, w := range out[v] {
+			inDeg[w]--
+		}
+		for _, w := range in[v] {
+			outDeg[w]--
+		}
+	}
+
+	var head, tail []int
+	for remaining := n; remaining > 0; {
+		var found bool
+		for v := 0; v < n; v++ {
+			if !removed[v] && outDeg[v] == 0 {
+				tail = append(tail, v)
+				remove(v)
+				remaining--
+				found = true
+			}
+		}
+		for v := 0; v < n; v++ {
+			if !removed[v] && inDeg[v] == 0 {
+				head = append(head, v)
+				remove(v)
+				remaining--
+				found = true
+			}
+		}
+		if found || remaining == 0 {
+			continue
+		}
+		best := -1
+		for v := 0; v < n; v++ {
+			if removed[v] {
+				continue
+			}
+			if best < 0 || outDeg[v]-inDeg[v] > outDeg[best]-inDeg[best] {
+				best = v
+			}
+		}
+		head = append(head, best)
+		remove(best)
+		remaining--
+	}
+	for i := len(tail) - 1; i >= 0; i-- {
+		head = append(head, tail[i])
+	}
+	return head
+}
+
+// breadthFirstOrder returns the order in which the vertices of the
+// undirected graph described by the adjacency list out are visited
+// by breadth first traversals starting from the lowest unvisited
+// vertex.
+func breadthFirstOrder(out [][]int) []int {
+	order := make([]int, 0, len(out))
+	visited := make([]bool, len(out))
+	for s := range out {
+		if visited[s] {
+			continue
+		}
+		visited[s] = true
+		queue := []int{s}
+		for len(queue) != 0 {
+			v := queue[0]
+			queue = queue[1:]
+			order = append(order, v)
+			for _, w := range out[v] {
+				if !visited[w] {
+					visited[w] = true
+					queue = append(queue, w)
+				}
+			}
+		}
+	}
+	return order
+}
+
+// minimizeCrossings reorders the vertices within each layer to reduce
+// the number of edge crossings using at most the given number of
+// barycenter sweeps. The ordering with the fewest crossings is retained.
+func (l *layering) minimizeCrossings(sweeps int) {
+	best := l.crossings()
+	bestLayers := copyLayers(l.layers)
+	key := make([]float64, len(l.layer))
+	for s := 0; s < sweeps && best != 0; s++ {
+		if s%2 == 0 {
+			for k := 1; k < len(l.layers); k++ {
+				l.sortByBarycenter(l.layers[k], l.up, key)
+			}
+		} else {
+			for k := len(l.layers) - 2; k >= 0; k-- {
+				l.sortByBarycenter(l.layers[k], l.down, key)
+			}
+		}
+		c := l.crossings()
+		if c < best {
+			best = c
+			bestLayers = copyLayers(l.layers)
+		}
+	}
+	l.layers = bestLayers
+	for _, layer := range l.layers {
+		for p, v := range layer {
+			l.pos[v] = p
+		}
+	}
+}
+
+// sortByBarycenter sorts the vertices in layer by the mean position of
+// their neighbors in adj. Vertices without neighbors retain their position.
+func (l *layering) sortByBarycenter(layer []int, adj [][]int, key []float64) {
+	for _, v := range layer {
+		if len(adj[v]) == 0 {
+			key[v] = float64(l.pos[v])
+			continue
+		}
+		var sum float64
+		for _, w := range adj[v] {
+			sum += float64(l.pos[w])
+		}
+		key[v] = sum / float64(len(adj[v]))
+	}
+	sort.SliceStable(layer, func(i, j int) bool { return key[layer[i]] < key[layer[j]] })
+	for p, v := range layer {
+		l.pos[v] = p
+	}
+}
+
+// crossings returns the number of edge crossings in the layering.
+func (l *layering) crossings() int {
+	var n int
+	for k := 0; k < len(l.layers)-1; k++ {
+		// Edges between layers k and k+1 are listed in order of their
+		// upper end and then their lower end. Crossings are inversions
+		// in the sequence of lower end positions and are counted with
+		// a Fenwick tree.
+		var ends []int
+		for _, v := range l.layers[k] {
+			lower := make([]int, len(l.down[v]))
+			for i, w := range l.down[v] {
+				lower[i] = l.pos[w]
+			}
+			sort.Ints(lower)
+			ends = append(ends, lower...)
+		}
+		tree := make([]int, len(l.layers[k+1])+1)
+		for i, p := range ends {
+			// Count previous ends strictly greater than p.
+			var le int
+			for j := p + 1; j > 0; j -= j & -j {
+				le += tree[j]
+			}
+			n += i - le
+			for j := p + 1; j < len(tree); j += j & -j {
+				tree[j]++
+			}
+		}
+	}
+	return n
+}
+
+// assignCoordinates returns the horizontal coordinates of the graph
+// nodes in the layering. Vertices are iteratively moved toward the mean
+// coordinate of their neighbors while retaining their order and a
+// separation of at least sep within their layer.
+func (l *layering) assignCoordinates(sweeps int, sep float64) []float64 {
+	x := make([]float64, len(l.layer))
+	for v, p := range l.pos {
+		x[v] = float64(p) * sep
+	}
+	want := make([]float64, len(l.layer))
+	for s := 0; s < sweeps; s++ {
+		for i := range l.layers {
+			k := i
+			if s%2 != 0 {
+				k = len(l.layers) - 1 - i
+			}
+			layer := l.layers[k]
+			for p, v := range layer {
+				n := len(l.up[v]) + len(l.down[v])
+				if n == 0 {
+					want[p] = x[v]
+					continue
+				}
+				var sum float64
+				for _, w := range l.up[v] {
+					sum += x[w]
+				}
+				for _, w := range l.down[v] {
+					sum += x[w]
+				}
+				want[p] = sum / float64(n)
+			}
+			place(x, layer, want[:len(layer)], sep)
+		}
+	}
+
+	min := x[0]
+	for _, v := range x {
+		if v < min {
+			min = v
+		}
+	}
+	for v := range x {
+		x[v] -= min
+	}
+	return x[:l.nodes]
+}
+
+// place sets the coordinates in x of the vertices in layer to the values
+// closest to want in the least squares sense such that adjacent vertices
+// are separated by at least sep. The placement is found by isotonic
+// regression using the pool adjacent violators algorithm.
+func place(x []float64, layer []int, want []float64, sep float64) {
+	type block struct {
+		sum float64
+		n   int
+	}
+	blocks := make([]block, 0, len(layer))
+	for p, w := range want {
+		blocks = append(blocks, block{sum: w - float64(p)*sep, n: 1})
+		for len(blocks) > 1 {
+			last := blocks[len(blocks)-1]
+			prev := blocks[len(blocks)-2]
+			if prev.sum/float64(prev.n) <= last.sum/float64(last.n) {
+				break
+			}
+			blocks = blocks[:len(blocks)-1]
+			blocks[len(blocks)-1] = block{sum: prev.sum + last.sum, n: prev.n + last.n}
+		}
+	}
+	var p int
+	for _, b := range blocks {
+		mean := b.sum / float64(b.n)
+		for i := 0; i < b.n; i++ {
+			x[layer[p]] = mean + float64(p)*sep
+			p++
+		}
+	}
+}
+
+func copyLayers(layers [][]int) [][]int {
+	c := make([][]int, len(layers))
+	for i, l := range layers {
+		c[i] = append([]int(nil), l...)
+	}
+	return c
+}
@@ -0,0 +1,206 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package layout
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// Directed test graphs are not wrapped in orderedGraph since
+// the layout depends on the graph being graph.Directed, and
+// SugiyamaR2 orders nodes by ID.
+var sugiyamaR2Tests = []struct {
+	name  string
+	g     graph.Graph
+	param SugiyamaR2
+
+	// isDAG indicates that all edges
+	// should point downward.
+	isDAG bool
+
+	// wantCrossings is the expected number
+	// of crossings between edges spanning
+	// a single layer.
+	wantCrossings int
+}{
+	{
+		name: "binary tree",
+		g: func() graph.Graph {
+			// Node IDs are permuted so that the
+			// initial ordering has crossings.
+			perm := []int64{7, 12, 3, 0, 9, 14, 5, 1, 10, 13, 2, 8, 6, 11, 4}
+			g := simple.NewDirectedGraph()
+			for i := 1; i < len(perm); i++ {
+				g.SetEdge(simple.Edge{F: simple.Node(perm[(i-1)/2]), T: simple.Node(perm[i])})
+			}
+			return g
+		}(),
+		isDAG:         true,
+		wantCrossings: 0,
+	},
+	{
+		name: "dependencies",
+		g: func() graph.Graph {
+			edges := []simple.Edge{
+				{F: simple.Node(0), T: simple.Node(1)},
+				{F: simple.Node(0), T: simple.Node(2)},
+				{F: simple.Node(0), T: simple.Node(5)},
+				{F: simple.Node(1), T: simple.Node(3)},
+				{F: simple.Node(2), T: simple.Node(3)},
+				{F: simple.Node(2), T: simple.Node(4)},
+				{F: simple.Node(3), T: simple.Node(5)},
+				{F: simple.Node(4), T: simple.Node(5)},
+				{F: simple.Node(6), T: simple.Node(4)},
+			}
+			g := simple.NewDirectedGraph()
+			for _, e := range edges {
+				g.SetEdge(e)
+			}
+			return g
+		}(),
+		param:         SugiyamaR2{LayerSeparation: 2, NodeSeparation: 0.5},
+		isDAG:         true,
+		wantCrossings: 0,
+	},
+	{
+		name: "cycle",
+		g: func() graph.Graph {
+			g := simple.NewDirectedGraph()
+			for i := 0; i < 5; i++ {
+				g.SetEdge(simple.Edge{F: simple.Node(i), T: simple.Node((i + 1) % 5)})
+			}
+			g.SetEdge(simple.Edge{F: simple.Node(2), T: simple.Node(0)})
+			return g
+		}(),
+		wantCrossings: -1,
+	},
+	{
+		name: "undirected grid",
+		g: func() graph.Graph {
+			g := simple.NewUndirectedGraph()
+			for i := 0; i < 3; i++ {
+				for j := 0; j < 3; j++ {
+					if i < 2 {
+						g.SetEdge(simple.Edge{F: simple.Node(3*i + j), T: simple.Node(3*(i+1) + j)})
+					}
+					if j < 2 {
+						g.SetEdge(simple.Edge{F: simple.Node(3*i + j), T: simple.Node(3*i + j + 1)})
+					}
+				}
+			}
+			return orderedGraph{g}
+		}(),
+		wantCrossings: -1,
+	},
+	{
+		name: "disconnected with reciprocal edges",
+		g: func() graph.Graph {
+			g := simple.NewDirectedGraph()
+			g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1)})
+			g.SetEdge(simple.Edge{F: simple.Node(2), T: simple.Node(3)})
+			g.SetEdge(simple.Edge{F: simple.Node(3), T: simple.Node(2)})
+			g.AddNode(simple.Node(4))
+			return g
+		}(),
+		wantCrossings: 0,
+	},
+}
+
+func TestSugiyamaR2(t *testing.T) {
+	for _, test := range sugiyamaR2Tests {
+		o := NewOptimizerR2(test.g, test.param.Update)
+		if o.Update() {
+			t.Errorf("unexpected further update for %q", test.name)
+		}
+
+		layerSep := test.param.LayerSeparation
+		if layerSep == 0 {
+			layerSep = 1
+		}
+		nodeSep := test.param.NodeSeparation
+		if nodeSep == 0 {
+			nodeSep = 1
+		}
+
+		nodes := graph.NodesOf(test.g.Nodes())
+		for i, u := range nodes {
+			p := o.Coord2(u.ID())
+			if layer := -p.Y / layerSep; layer != math.Trunc(layer) || layer < 0 {
+				t.Errorf("node %d not in a layer for %q: y=%v", u.ID(), test.name, p.Y)
+			}
+			for _, v := range nodes[:i] {
+				q := o.Coord2(v.ID())
+				if p.Y == q.Y && math.Abs(p.X-q.X) < nodeSep-1e-10 {
+					t.Errorf("nodes %d and %d too close for %q: %v %v", u.ID(), v.ID(), test.name, p, q)
+				}
+			}
+		}
+
+		type span struct{ upper, lower int64 }
+		var spans []span
+		for _, u := range nodes {
+			uid := u.ID()
+			for _, v := range graph.NodesOf(test.g.From(uid)) {
+				vid := v.ID()
+				if uid == vid {
+					continue
+				}
+				p := o.Coord2(uid)
+				q := o.Coord2(vid)
+				if p.Y == q.Y {
+					t.Errorf("unexpected edge within a layer for %q: %d--%d", test.name, uid, vid)
+				}
+				if test.isDAG && p.Y <= q.Y {
+					t.Errorf("unexpected upward edge for %q: %d->%d", test.name, uid, vid)
+				}
+				s := span{upper: uid, lower: vid}
+				if p.Y < q.Y {
+					s = span{upper: vid, lower: uid}
+				}
+				if math.Abs(p.Y-q.Y) == layerSep {
+					spans = append(spans, s)
+				}
+			}
+		}
+		if test.wantCrossings < 0 {
+			continue
+		}
+		var crossings int
+		for i, a := range spans {
+			for _, b := range spans[:i] {
+				if o.Coord2(a.upper).Y != o.Coord2(b.upper).Y {
+					continue
+				}
+				du := o.Coord2(a.upper).X - o.Coord2(b.upper).X
+				dl := o.Coord2(a.lower).X - o.Coord2(b.lower).X
+				if du*dl < 0 {
+					crossings++
+				}
+			}
+		}
+		if crossings != test.wantCrossings {
+			t.Errorf("unexpected number of crossings for %q: got:%d want:%d", test.name, crossings, test.wantCrossings)
+		}
+	}
+}
+
+func TestLayeringCrossings(t *testing.T) {
+	// Two layers with edges 0-4, 1-3 and 2-3 have a
+	// crossing between 0-4 and each of 1-3 and 2-3.
+	l := layering{
+		down: [][]int{
+			{4}, {3}, {3}, nil, nil,
+		},
+		layers: [][]int{{0, 1, 2}, {3, 4}},
+		pos:    []int{0, 1, 2, 0, 1},
+	}
+	if got := l.crossings(); got != 2 {
+		t.Errorf("unexpected number of crossings: got:%d want:2", got)
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package network
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/graph"
+)
+
+// DegreeAssortativity returns the degree assortativity coefficient of the
+// graph g, the Pearson correlation coefficient of the degrees of the nodes at
+// either end of each edge in g. If g is a graph.Directed, the out-degree of the
+// source node of each edge is correlated with the in-degree of its target. If
+// g is undirected, each edge is counted in both directions. If g is a
+// graph.Weighted, node strengths, the sums of the weights of the edges
+// incident to each node, are used in place of degrees, and each edge
+// contributes to the correlation in proportion to its weight. Self loops are
+// ignored.
+//
+// DegreeAssortativity returns NaN if g has no edges or if all the edges in g
+// join nodes with the same degree.
+func DegreeAssortativity(g graph.Graph) float64 {
+	weight := func(uid, vid int64) float64 { return 1 }
+	if wg, ok := g.(graph.Weighted); ok {
+		weight = func(uid, vid int64) float64 {
+			w, _ := wg.Weight(uid, vid)
+			return w
+		}
+	}
+
+	nodes := graph.NodesOf(g.Nodes())
+	out := make(map[int64]float64, len(nodes))
+	in := make(map[int64]float64, len(nodes))
+	for _, u := range nodes {
+		uid := u.ID()
+		to := g.From(uid)
+		for to.Next() {
+			vid := to.Node().ID()
+			if vid == uid {
+				continue
+			}
+			w := weight(uid, vid)
+			out[uid] += w
+			in[vid] += w
+		}
+	}
+
+	// Accumulate the weighted moments of the
+	// end point degrees over all edges.
+	var sumW, sumX, sumY, sumXX, sumYY, sumXY float64
+	for _, u := range nodes {
+		uid := u.ID()
+		to := g.From(uid)
+		for to.Next() {
+			vid := to.Node().ID()
+			if vid == uid {
+				continue
+			}
+			w := weight(uid, vid)
+			x := out[uid]
+			y := in[vid]
+			sumW += w
+			sumX += w * x
+			sumY += w * y
+			sumXX += w * x * x
+			sumYY += w * y * y
+			sumXY += w * x * y
+		}
+	}
+	if sumW == 0 {
+		return math.NaN()
+	}
+
+	meanX := sumX / sumW
+	meanY := sumY / sumW
+	cov := sumXY/sumW - meanX*meanY
+	varX := sumXX/sumW - meanX*meanX
+	varY := sumYY/sumW - meanY*meanY
+	return cov / math.Sqrt(varX*varY)
+}
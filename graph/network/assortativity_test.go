@@ -0,0 +1,115 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package network
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+var degreeAssortativityTests = []struct {
+	name     string
+	g        []set
+	directed bool
+
+	want float64
+}{
+	{
+		name: "star",
+		g:    []set{A: linksTo(B, C, D, E)},
+		want: -1,
+	},
+	{
+		name: "disjoint edge and triangle",
+		g: []set{
+			A: linksTo(B),
+			C: linksTo(D, E),
+			D: linksTo(E),
+		},
+		want: 1,
+	},
+	{
+		// Degrees are 1, 2, 2, 1 along the path. Counting each
+		// edge in both directions gives the pairs (1,2), (2,1)
+		// twice and (2,2) twice.
+		name: "path",
+		g: []set{
+			A: linksTo(B),
+			B: linksTo(C),
+			C: linksTo(D),
+		},
+		want: -0.5,
+	},
+	{
+		// Pairs of out-degree of source and in-degree
+		// of target are (2,1), (2,2) and (1,2).
+		name: "directed",
+		g: []set{
+			A: linksTo(B, C),
+			B: linksTo(C),
+		},
+		directed: true,
+		want:     -0.5,
+	},
+	{
+		name: "regular",
+		g: []set{
+			A: linksTo(B),
+			B: linksTo(C),
+			C: linksTo(A),
+		},
+		want: math.NaN(),
+	},
+	{
+		name: "no edges",
+		g:    []set{A: nil, B: nil},
+		want: math.NaN(),
+	},
+}
+
+func TestDegreeAssortativity(t *testing.T) {
+	const tol = 1e-12
+	for _, test := range degreeAssortativityTests {
+		var g builder
+		if test.directed {
+			g = simple.NewDirectedGraph()
+		} else {
+			g = simple.NewUndirectedGraph()
+		}
+		for u, e := range test.g {
+			// Add nodes that are not defined by an edge.
+			if g.Node(int64(u)) == nil {
+				g.AddNode(simple.Node(u))
+			}
+			for v := range e {
+				g.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v)})
+			}
+		}
+		got := DegreeAssortativity(g)
+		if math.IsNaN(test.want) {
+			if !math.IsNaN(got) {
+				t.Errorf("unexpected assortativity for %q: got:%v want:NaN", test.name, got)
+			}
+			continue
+		}
+		if !scalar.EqualWithinAbsOrRel(got, test.want, tol, tol) {
+			t.Errorf("unexpected assortativity for %q: got:%v want:%v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestDegreeAssortativityWeighted(t *testing.T) {
+	// Uniform weights give the unweighted coefficient.
+	g := simple.NewWeightedUndirectedGraph(0, 0)
+	for _, e := range [][2]int64{{A, B}, {B, C}, {C, D}} {
+		g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(e[0]), T: simple.Node(e[1]), W: 2})
+	}
+	if got := DegreeAssortativity(g); !scalar.EqualWithinAbsOrRel(got, -0.5, 1e-12, 1e-12) {
+		t.Errorf("unexpected weighted assortativity: got:%v want:-0.5", got)
+	}
+}
@@ -7,6 +7,8 @@ package network
 import (
 	"math"
 
+	"golang.org/x/exp/rand"
+
 	"gonum.org/v1/gonum/graph"
 	"gonum.org/v1/gonum/graph/internal/linear"
 	"gonum.org/v1/gonum/graph/path"
@@ -32,8 +34,48 @@ func Betweenness(g graph.Graph) map[int64]float64 {
 	// Also note special case for sparse networks:
 	// http://wwwold.iit.cnr.it/staff/marco.pellegrini/papiri/asonam-final.pdf
 
+	nodes := graph.NodesOf(g.Nodes())
+	return betweenness(g, nodes, nodes, 1)
+}
+
+// BetweennessSampled returns an estimate of the non-zero betweenness centrality
+// for nodes in the unweighted graph g. The estimate is obtained by accumulating
+// the dependencies of k source nodes chosen uniformly at random without
+// replacement and scaling the result by n/k where n is the number of nodes in g,
+// as described in
+//
+// Brandes and Pich, "Centrality estimation in large networks." International
+// Journal of Bifurcation and Chaos 17(7):2303-2318 (2007).
+//
+// If k is greater than or equal to the number of nodes in g, the exact
+// betweenness is returned. If src is nil, the global random source is used.
+func BetweennessSampled(g graph.Graph, k int, src rand.Source) map[int64]float64 {
+	if k < 1 {
+		panic("network: non-positive sample size")
+	}
+	nodes := graph.NodesOf(g.Nodes())
+	if k >= len(nodes) {
+		return betweenness(g, nodes, nodes, 1)
+	}
+
+	var shuffle func(int, func(int, int))
+	if src == nil {
+		shuffle = rand.Shuffle
+	} else {
+		shuffle = rand.New(src).Shuffle
+	}
+	sources := make([]graph.Node, len(nodes))
+	copy(sources, nodes)
+	shuffle(len(sources), func(i, j int) { sources[i], sources[j] = sources[j], sources[i] })
+
+	return betweenness(g, nodes, sources[:k], float64(len(nodes))/float64(k))
+}
+
+// betweenness returns the node betweenness of the nodes in g accumulated
+// from the given source nodes and scaled by scale.
+func betweenness(g graph.Graph, nodes, sources []graph.Node, scale float64) map[int64]float64 {
 	cb := make(map[int64]float64)
-	brandes(g, func(s graph.Node, stack linear.NodeStack, p map[int64][]graph.Node, delta, sigma map[int64]float64) {
+	brandesFrom(g, nodes, sources, func(s graph.Node, stack linear.NodeStack, p map[int64][]graph.Node, delta, sigma map[int64]float64) {
 		for stack.Len() != 0 {
 			w := stack.Pop()
 			for _, v := range p[w.ID()] {
@@ -41,7 +83,7 @@ func Betweenness(g graph.Graph) map[int64]float64 {
 			}
 			if w.ID() != s.ID() {
 				if d := delta[w.ID()]; d != 0 {
-					cb[w.ID()] += d
+					cb[w.ID()] += scale * d
 				}
 			}
 		}
@@ -89,8 +131,14 @@ func EdgeBetweenness(g graph.Graph) map[[2]int64]float64 {
 // to algorithm 1 in http://algo.uni-konstanz.de/publications/b-vspbc-08.pdf with
 // the accumulation loop provided by the accumulate closure.
 func brandes(g graph.Graph, accumulate func(s graph.Node, stack linear.NodeStack, p map[int64][]graph.Node, delta, sigma map[int64]float64)) {
+	nodes := graph.NodesOf(g.Nodes())
+	brandesFrom(g, nodes, nodes, accumulate)
+}
+
+// brandesFrom performs the work of brandes for the nodes of g, restricting
+// the single-source shortest path searches to the given sources.
+func brandesFrom(g graph.Graph, nodes, sources []graph.Node, accumulate func(s graph.Node, stack linear.NodeStack, p map[int64][]graph.Node, delta, sigma map[int64]float64)) {
 	var (
-		nodes = graph.NodesOf(g.Nodes())
 		stack linear.NodeStack
 		p     = make(map[int64][]graph.Node, len(nodes))
 		sigma = make(map[int64]float64, len(nodes))
@@ -98,7 +146,7 @@ func brandes(g graph.Graph, accumulate func(s graph.Node, stack linear.NodeStack
 		delta = make(map[int64]float64, len(nodes))
 		queue linear.NodeQueue
 	)
-	for _, s := range nodes {
+	for _, s := range sources {
 		stack = stack[:0]
 
 		for _, w := range nodes {
@@ -10,6 +10,8 @@ import (
 	"sort"
 	"testing"
 
+	"golang.org/x/exp/rand"
+
 	"gonum.org/v1/gonum/floats/scalar"
 	"gonum.org/v1/gonum/graph/path"
 	"gonum.org/v1/gonum/graph/simple"
@@ -235,6 +237,50 @@ func TestEdgeBetweenness(t *testing.T) {
 	}
 }
 
+func TestBetweennessSampled(t *testing.T) {
+	for i, test := range betweennessTests {
+		g := simple.NewUndirectedGraph()
+		for u, e := range test.g {
+			// Add nodes that are not defined by an edge.
+			if g.Node(int64(u)) == nil {
+				g.AddNode(simple.Node(u))
+			}
+			for v := range e {
+				g.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v)})
+			}
+		}
+		prec := 1 - int(math.Log10(test.wantTol))
+
+		// Sampling all nodes gives the exact betweenness.
+		got := BetweennessSampled(g, len(test.g), rand.NewSource(1))
+		for n := range test.g {
+			if !scalar.EqualWithinAbsOrRel(got[int64(n)], test.want[int64(n)], test.wantTol, test.wantTol) {
+				t.Errorf("unexpected sampled betweenness result for test %d:\ngot: %v\nwant:%v",
+					i, orderedFloats(got, prec), orderedFloats(test.want, prec))
+				break
+			}
+		}
+
+		// The sampled betweenness is an unbiased estimator.
+		const trials = 2000
+		k := len(test.g) / 2
+		src := rand.NewSource(1)
+		mean := make(map[int64]float64)
+		for j := 0; j < trials; j++ {
+			for n, c := range BetweennessSampled(g, k, src) {
+				mean[n] += c / trials
+			}
+		}
+		for n := range test.g {
+			if !scalar.EqualWithinAbsOrRel(mean[int64(n)], test.want[int64(n)], 0.5, 0.05) {
+				t.Errorf("unexpected mean sampled betweenness result for test %d:\ngot: %v\nwant:%v",
+					i, orderedFloats(mean, prec), orderedFloats(test.want, prec))
+				break
+			}
+		}
+	}
+}
+
 func TestBetweennessWeighted(t *testing.T) {
 	for i, test := range betweennessTests {
 		g := simple.NewWeightedUndirectedGraph(0, math.Inf(1))
@@ -0,0 +1,164 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package network
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/mat"
+)
+
+// EigenvectorCentrality returns the eigenvector centrality weights for nodes
+// of the graph g, terminating when the 2-norm of the vector difference between
+// iterations is below tol. The centrality of a node is proportional to the sum
+// of the centralities of the nodes linking to it,
+//
+//  x_v = 1/λ \sum_{u → v} w_{uv} x_u,
+//
+// where λ is the largest eigenvalue of the adjacency matrix of g. The returned
+// weights have unit 2-norm and the returned map is keyed on the graph node IDs.
+// If g is a graph.Weighted, edge weights are used as the entries of the
+// adjacency matrix, otherwise every edge has a weight of one. If g is a
+// graph.Directed, centrality flows along the direction of the edges.
+//
+// The weights are found by power iteration on A+I which converges for
+// connected undirected graphs and strongly connected directed graphs.
+// EigenvectorCentrality returns nil if g has no edges.
+func EigenvectorCentrality(g graph.Graph, tol float64) map[int64]float64 {
+	nodes, m := inAdjacency(g)
+	if !hasEdges(m) {
+		return nil
+	}
+
+	last := make([]float64, len(nodes))
+	lastV := mat.NewVecDense(len(nodes), last)
+	vec := make([]float64, len(nodes))
+	for i := range vec {
+		vec[i] = 1 / math.Sqrt(float64(len(nodes)))
+	}
+	v := mat.NewVecDense(len(nodes), vec)
+
+	for {
+		lastV, v = v, lastV
+		last, vec = vec, last
+		m.mulVecUnitary(v, lastV)
+		var norm float64
+		for i, x := range vec {
+			x += last[i]
+			vec[i] = x
+			norm += x * x
+		}
+		norm = math.Sqrt(norm)
+		if norm == 0 {
+			return nil
+		}
+		for i := range vec {
+			vec[i] /= norm
+		}
+		if normDiff(vec, last) < tol {
+			break
+		}
+	}
+
+	ranks := make(map[int64]float64, len(nodes))
+	for i, r := range vec {
+		ranks[nodes[i].ID()] = r
+	}
+	return ranks
+}
+
+// KatzCentrality returns the Katz centrality weights for nodes of the graph g
+// using the attenuation factor alpha and the base centrality beta, terminating
+// when the 2-norm of the vector difference between iterations is below tol.
+// The Katz centrality is the solution of
+//
+//  x_v = α \sum_{u → v} w_{uv} x_u + β,
+//
+// and counts the walks ending at each node with walks of length k attenuated
+// by α^k. If g is a graph.Weighted, edge weights are used as the entries of
+// the adjacency matrix, otherwise every edge has a weight of one. If g is a
+// graph.Directed, centrality flows along the direction of the edges. The
+// returned map is keyed on the graph node IDs and the weights are not
+// normalized.
+//
+// The iteration converges when alpha is less than the reciprocal of the
+// largest eigenvalue of the adjacency matrix of g. KatzCentrality returns
+// nil if the iteration diverges.
+func KatzCentrality(g graph.Graph, alpha, beta, tol float64) map[int64]float64 {
+	nodes, m := inAdjacency(g)
+
+	last := make([]float64, len(nodes))
+	lastV := mat.NewVecDense(len(nodes), last)
+	vec := make([]float64, len(nodes))
+	v := mat.NewVecDense(len(nodes), vec)
+
+	for len(nodes) != 0 {
+		lastV, v = v, lastV
+		last, vec = vec, last
+		m.mulVecUnitary(v, lastV)
+		var norm float64
+		for i, x := range vec {
+			x = alpha*x + beta
+			vec[i] = x
+			norm += x * x
+		}
+		if math.IsInf(norm, 0) || math.IsNaN(norm) {
+			return nil
+		}
+		if normDiff(vec, last) < tol {
+			break
+		}
+	}
+
+	ranks := make(map[int64]float64, len(nodes))
+	for i, r := range vec {
+		ranks[nodes[i].ID()] = r
+	}
+	return ranks
+}
+
+// inAdjacency returns the nodes of g and the transpose of the adjacency
+// matrix of g in row compressed form, so that row i holds the weights of
+// the edges leading into node i. Self loops are ignored.
+func inAdjacency(g graph.Graph) ([]graph.Node, rowCompressedMatrix) {
+	nodes := graph.NodesOf(g.Nodes())
+	indexOf := make(map[int64]int, len(nodes))
+	for i, n := range nodes {
+		indexOf[n.ID()] = i
+	}
+
+	weight := func(uid, vid int64) float64 { return 1 }
+	if wg, ok := g.(graph.Weighted); ok {
+		weight = func(uid, vid int64) float64 {
+			w, _ := wg.Weight(uid, vid)
+			return w
+		}
+	}
+
+	m := make(rowCompressedMatrix, len(nodes))
+	for j, u := range nodes {
+		uid := u.ID()
+		to := g.From(uid)
+		for to.Next() {
+			vid := to.Node().ID()
+			if vid == uid {
+				continue
+			}
+			m.addTo(indexOf[vid], j, weight(uid, vid))
+		}
+	}
+	return nodes, m
+}
+
+// hasEdges returns whether any row of m has an element.
+func hasEdges(m rowCompressedMatrix) bool {
+	for _, r := range m {
+		if len(r) != 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,165 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package network
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+	"gonum.org/v1/gonum/mat"
+)
+
+var eigenvectorCentralityTests = []struct {
+	g []set
+}{
+	{
+		// Example graph from http://en.wikipedia.org/wiki/File:PageRanks-Example.svg 16:17, 8 July 2009
+		// with the isolated node removed.
+		g: []set{
+			A: linksTo(B),
+			B: linksTo(C),
+			C: linksTo(B),
+			D: linksTo(A, B),
+			E: linksTo(D, B, F),
+			F: linksTo(B, E),
+			G: linksTo(B, E),
+			H: linksTo(B, E),
+			I: linksTo(B, E),
+			J: linksTo(E),
+			K: linksTo(E),
+		},
+	},
+	{
+		g: []set{
+			A: linksTo(B, C),
+			B: linksTo(D),
+			C: linksTo(D, E),
+			D: linksTo(E),
+			E: linksTo(A),
+		},
+	},
+	{
+		// A bipartite graph for which power iteration
+		// without the shift does not converge.
+		g: []set{
+			A: linksTo(D, E),
+			B: linksTo(D, E),
+			C: linksTo(E),
+		},
+	},
+}
+
+func TestEigenvectorCentrality(t *testing.T) {
+	const tol = 1e-10
+	for i, test := range eigenvectorCentralityTests {
+		g := simple.NewUndirectedGraph()
+		for u, e := range test.g {
+			// Add nodes that are not defined by an edge.
+			if g.Node(int64(u)) == nil {
+				g.AddNode(simple.Node(u))
+			}
+			for v := range e {
+				g.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v)})
+			}
+		}
+		got := EigenvectorCentrality(g, tol)
+
+		// Compare with the leading eigenvector of
+		// the adjacency matrix.
+		nodes := graph.NodesOf(g.Nodes())
+		n := len(nodes)
+		a := mat.NewSymDense(n, nil)
+		for i, u := range nodes {
+			for j, v := range nodes {
+				if g.HasEdgeBetween(u.ID(), v.ID()) {
+					a.SetSym(i, j, 1)
+				}
+			}
+		}
+		var eig mat.EigenSym
+		if !eig.Factorize(a, true) {
+			t.Fatalf("failed to factorize adjacency matrix for test %d", i)
+		}
+		var ev mat.Dense
+		eig.VectorsTo(&ev)
+		for j, u := range nodes {
+			want := math.Abs(ev.At(j, n-1))
+			if !scalar.EqualWithinAbsOrRel(got[u.ID()], want, 1e-6, 1e-6) {
+				t.Errorf("unexpected eigenvector centrality for test %d node %c: got:%v want:%v",
+					i, u.ID()+'A', got[u.ID()], want)
+			}
+		}
+	}
+
+	if got := EigenvectorCentrality(simple.NewUndirectedGraph(), 1e-10); got != nil {
+		t.Errorf("unexpected result for empty graph: %v", got)
+	}
+}
+
+func TestKatzCentrality(t *testing.T) {
+	const (
+		alpha = 0.1
+		beta  = 1.0
+		tol   = 1e-12
+	)
+	for i, test := range eigenvectorCentralityTests {
+		for _, directed := range []bool{false, true} {
+			var g builder
+			if directed {
+				g = simple.NewDirectedGraph()
+			} else {
+				g = simple.NewUndirectedGraph()
+			}
+			for u, e := range test.g {
+				// Add nodes that are not defined by an edge.
+				if g.Node(int64(u)) == nil {
+					g.AddNode(simple.Node(u))
+				}
+				for v := range e {
+					g.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v)})
+				}
+			}
+			got := KatzCentrality(g, alpha, beta, tol)
+
+			// Compare with the solution of (I - αAᵀ)x = β1.
+			nodes := graph.NodesOf(g.Nodes())
+			n := len(nodes)
+			m := mat.NewDense(n, n, nil)
+			b := mat.NewVecDense(n, nil)
+			for i, u := range nodes {
+				m.Set(i, i, 1)
+				b.SetVec(i, beta)
+				for j, v := range nodes {
+					if g.Edge(v.ID(), u.ID()) != nil {
+						m.Set(i, j, -alpha)
+					}
+				}
+			}
+			var want mat.VecDense
+			err := want.SolveVec(m, b)
+			if err != nil {
+				t.Fatalf("failed to solve Katz system for test %d: %v", i, err)
+			}
+			for j, u := range nodes {
+				if !scalar.EqualWithinAbsOrRel(got[u.ID()], want.AtVec(j), 1e-9, 1e-9) {
+					t.Errorf("unexpected Katz centrality for test %d directed=%t node %c: got:%v want:%v",
+						i, directed, u.ID()+'A', got[u.ID()], want.AtVec(j))
+				}
+			}
+
+			// An attenuation factor larger than the reciprocal of
+			// the spectral radius diverges.
+			if directed {
+				continue
+			}
+			if got := KatzCentrality(g, 1, beta, tol); got != nil {
+				t.Errorf("expected divergence for test %d", i)
+			}
+		}
+	}
+}
@@ -0,0 +1,350 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package network
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/graph"
+)
+
+// Triangles returns the non-zero number of triangles that each node of the
+// undirected graph g is part of. The returned map is keyed on the graph node
+// IDs. Self loops are ignored. Triangles of a directed graph may be counted
+// without regard to edge direction by passing graph.Undirect{G: g}.
+func Triangles(g graph.Undirected) map[int64]int {
+	nodes, nbrs := neighbourSets(g)
+	t := make(map[int64]int)
+	for _, u := range nodes {
+		uid := u.ID()
+		var n int
+		for vid := range nbrs[uid] {
+			n += intersectionSize(nbrs[uid], nbrs[vid])
+		}
+		// Each triangle is counted once from each
+		// of the other two nodes.
+		if n != 0 {
+			t[uid] = n / 2
+		}
+	}
+	return t
+}
+
+// LocalClustering returns the non-zero local clustering coefficients for nodes
+// of the undirected graph g. For a node v with degree k_v the coefficient is
+//
+//  C(v) = 2 T(v) / (k_v (k_v - 1)),
+//
+// where T(v) is the number of triangles v is part of. If g is a
+// graph.WeightedUndirected the weighted coefficient of Onnela et al. is
+// returned,
+//
+//  C(v) = 1 / (k_v (k_v - 1)) \sum_{u,w} (ŵ_{vu} ŵ_{uw} ŵ_{wv})^{1/3},
+//
+// where the sum is over ordered pairs of neighbours of v and ŵ are the edge
+// weights divided by the largest edge weight in g. The returned map is keyed
+// on the graph node IDs. Self loops are ignored. LocalClusteringDirected
+// returns the coefficients for directed graphs.
+func LocalClustering(g graph.Undirected) map[int64]float64 {
+	nodes, nbrs := neighbourSets(g)
+	c := make(map[int64]float64)
+
+	wg, weighted := g.(graph.WeightedUndirected)
+	if !weighted {
+		for _, u := range nodes {
+			uid := u.ID()
+			k := len(nbrs[uid])
+			if k < 2 {
+				continue
+			}
+			var n int
+			for vid := range nbrs[uid] {
+				n += intersectionSize(nbrs[uid], nbrs[vid])
+			}
+			if n != 0 {
+				c[uid] = float64(n) / float64(k*(k-1))
+			}
+		}
+		return c
+	}
+
+	var maxWeight float64
+	for _, u := range nodes {
+		uid := u.ID()
+		for vid := range nbrs[uid] {
+			if w, _ := wg.Weight(uid, vid); w > maxWeight {
+				maxWeight = w
+			}
+		}
+	}
+	weight := func(uid, vid int64) float64 {
+		w, _ := wg.Weight(uid, vid)
+		return w / maxWeight
+	}
+	for _, u := range nodes {
+		uid := u.ID()
+		k := len(nbrs[uid])
+		if k < 2 {
+			continue
+		}
+		var sum float64
+		for vid := range nbrs[uid] {
+			for wid := range nbrs[vid] {
+				if _, ok := nbrs[uid][wid]; !ok {
+					continue
+				}
+				sum += math.Cbrt(weight(uid, vid) * weight(vid, wid) * weight(wid, uid))
+			}
+		}
+		if sum != 0 {
+			c[uid] = sum / float64(k*(k-1))
+		}
+	}
+	return c
+}
+
+// AverageClustering returns the mean of the local clustering coefficients of
+// the nodes of the undirected graph g as returned by LocalClustering. Nodes
+// with fewer than two neighbours have a coefficient of zero. AverageClustering
+// returns NaN if g has no nodes.
+func AverageClustering(g graph.Undirected) float64 {
+	n := g.Nodes().Len()
+	var sum float64
+	for _, c := range LocalClustering(g) {
+		sum += c
+	}
+	return sum / float64(n)
+}
+
+// LocalClusteringDirected returns the non-zero local clustering coefficients
+// for nodes of the directed graph g, counting all directed triangles as
+// described in
+//
+//  G. Fagiolo, "Clustering in complex directed networks", Physical Review E
+//  76 (2007) 026107.
+//
+// For a node v with total degree d_v, the sum of its in and out degrees, and
+// d↔_v reciprocated edges the coefficient is
+//
+//  C(v) = (A + Aᵀ)³_vv / (2 (d_v (d_v - 1) - 2 d↔_v)),
+//
+// where A is the adjacency matrix of g. If g is a graph.WeightedDirected the
+// elements of A are replaced by the cube roots of the edge weights divided by
+// the largest edge weight in g, so that for symmetric graphs the coefficients
+// are those returned by LocalClustering. The returned map is keyed on the
+// graph node IDs. Self loops are ignored.
+func LocalClusteringDirected(g graph.Directed) map[int64]float64 {
+	nodes, adj := directedAdjacency(g)
+	a := func(uid, vid int64) float64 {
+		return adj[uid][vid] + adj[vid][uid]
+	}
+	c := make(map[int64]float64)
+	for _, u := range nodes {
+		uid := u.ID()
+
+		// The neighbours of u, irrespective of direction,
+		// and the degree counts.
+		var d, recip int
+		nbrs := make(map[int64]struct{})
+		for vid := range adj[uid] {
+			nbrs[vid] = struct{}{}
+			d++
+			if _, ok := adj[vid][uid]; ok {
+				recip++
+			}
+		}
+		to := g.To(uid)
+		for to.Next() {
+			if vid := to.Node().ID(); vid != uid {
+				nbrs[vid] = struct{}{}
+				d++
+			}
+		}
+		den := 2 * (d*(d-1) - 2*recip)
+		if den == 0 {
+			continue
+		}
+
+		var sum float64
+		for vid := range nbrs {
+			for wid := range nbrs {
+				if wid == vid {
+					continue
+				}
+				sum += a(uid, vid) * a(vid, wid) * a(wid, uid)
+			}
+		}
+		if sum != 0 {
+			c[uid] = sum / float64(den)
+		}
+	}
+	return c
+}
+
+// AverageClusteringDirected returns the mean of the local clustering
+// coefficients of the nodes of the directed graph g as returned by
+// LocalClusteringDirected. Nodes without possible triangles have a
+// coefficient of zero. AverageClusteringDirected returns NaN if g has
+// no nodes.
+func AverageClusteringDirected(g graph.Directed) float64 {
+	n := g.Nodes().Len()
+	var sum float64
+	for _, c := range LocalClusteringDirected(g) {
+		sum += c
+	}
+	return sum / float64(n)
+}
+
+// GlobalClustering returns the global clustering coefficient, or
+// transitivity, of the undirected graph g,
+//
+//  C = 3 × triangles / connected triples,
+//
+// the fraction of paths of length two in g that are closed. GlobalClustering
+// returns zero if g has no paths of length two. Self loops are ignored. The
+// transitivity of a directed graph may be computed without regard to edge
+// direction by passing graph.Undirect{G: g}.
+func GlobalClustering(g graph.Undirected) float64 {
+	nodes, nbrs := neighbourSets(g)
+	var closed, triples int
+	for _, u := range nodes {
+		uid := u.ID()
+		k := len(nbrs[uid])
+		triples += k * (k - 1) / 2
+		for vid := range nbrs[uid] {
+			closed += intersectionSize(nbrs[uid], nbrs[vid])
+		}
+	}
+	if triples == 0 {
+		return 0
+	}
+	// Each triangle contributes six ordered pairs to closed.
+	return float64(closed) / float64(2*triples)
+}
+
+// RichClub returns the rich-club coefficients of the undirected graph g,
+//
+//  φ(k) = 2 E_k / (N_k (N_k - 1)),
+//
+// where N_k is the number of nodes with degree greater than k and E_k is the
+// number of edges between them. The returned map is keyed on the degree k for
+// each k where N_k is greater than one. Self loops are ignored. The rich-club
+// coefficients of a directed graph may be computed without regard to edge
+// direction by passing graph.Undirect{G: g}.
+func RichClub(g graph.Undirected) map[int]float64 {
+	nodes, nbrs := neighbourSets(g)
+
+	// Count the nodes with each degree and the edges
+	// by the smaller degree of their end points.
+	var maxDeg int
+	for _, u := range nodes {
+		if k := len(nbrs[u.ID()]); k > maxDeg {
+			maxDeg = k
+		}
+	}
+	nodesByDeg := make([]int, maxDeg+1)
+	edgesByDeg := make([]int, maxDeg+1)
+	for _, u := range nodes {
+		uid := u.ID()
+		ku := len(nbrs[uid])
+		nodesByDeg[ku]++
+		for vid := range nbrs[uid] {
+			if vid < uid {
+				continue
+			}
+			kv := len(nbrs[vid])
+			if kv < ku {
+				edgesByDeg[kv]++
+			} else {
+				edgesByDeg[ku]++
+			}
+		}
+	}
+
+	phi := make(map[int]float64)
+	var nk, ek int
+	for k := maxDeg; k >= 0; k-- {
+		// nk and ek hold the node and edge counts
+		// for degrees greater than k.
+		if nk > 1 {
+			phi[k] = 2 * float64(ek) / float64(nk*(nk-1))
+		}
+		nk += nodesByDeg[k]
+		ek += edgesByDeg[k]
+	}
+	return phi
+}
+
+// neighbourSets returns the nodes of g and the set of neighbours of each
+// node excluding the node itself.
+func neighbourSets(g graph.Undirected) ([]graph.Node, map[int64]map[int64]struct{}) {
+	nodes := graph.NodesOf(g.Nodes())
+	nbrs := make(map[int64]map[int64]struct{}, len(nodes))
+	for _, u := range nodes {
+		uid := u.ID()
+		s := make(map[int64]struct{})
+		to := g.From(uid)
+		for to.Next() {
+			if vid := to.Node().ID(); vid != uid {
+				s[vid] = struct{}{}
+			}
+		}
+		nbrs[uid] = s
+	}
+	return nodes, nbrs
+}
+
+// directedAdjacency returns the nodes of g and the adjacency of each node
+// excluding the node itself, keyed by the IDs of the nodes the edges lead
+// to. The elements of the adjacency are 1, or if g is a graph.WeightedDirected
+// the cube roots of the edge weights divided by the largest edge weight.
+func directedAdjacency(g graph.Directed) ([]graph.Node, map[int64]map[int64]float64) {
+	nodes := graph.NodesOf(g.Nodes())
+	adj := make(map[int64]map[int64]float64, len(nodes))
+	wg, weighted := g.(graph.WeightedDirected)
+	var maxWeight float64
+	for _, u := range nodes {
+		uid := u.ID()
+		a := make(map[int64]float64)
+		to := g.From(uid)
+		for to.Next() {
+			vid := to.Node().ID()
+			if vid == uid {
+				continue
+			}
+			a[vid] = 1
+			if weighted {
+				w, _ := wg.Weight(uid, vid)
+				a[vid] = w
+				if w > maxWeight {
+					maxWeight = w
+				}
+			}
+		}
+		adj[uid] = a
+	}
+	if weighted {
+		for _, a := range adj {
+			for vid, w := range a {
+				a[vid] = math.Cbrt(w / maxWeight)
+			}
+		}
+	}
+	return nodes, adj
+}
+
+// intersectionSize returns the number of elements common to a and b.
+func intersectionSize(a, b map[int64]struct{}) int {
+	if len(b) < len(a) {
+		a, b = b, a
+	}
+	var n int
+	for e := range a {
+		if _, ok := b[e]; ok {
+			n++
+		}
+	}
+	return n
+}
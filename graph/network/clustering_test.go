@@ -0,0 +1,311 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package network
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+var clusteringTests = []struct {
+	name string
+	g    []set
+
+	wantTriangles map[int64]int
+	wantLocal     map[int64]float64
+	wantAverage   float64
+	wantGlobal    float64
+}{
+	{
+		name: "K4",
+		g: []set{
+			A: linksTo(B, C, D),
+			B: linksTo(C, D),
+			C: linksTo(D),
+			D: nil,
+		},
+		wantTriangles: map[int64]int{A: 3, B: 3, C: 3, D: 3},
+		wantLocal:     map[int64]float64{A: 1, B: 1, C: 1, D: 1},
+		wantAverage:   1,
+		wantGlobal:    1,
+	},
+	{
+		name: "triangle with pendant",
+		g: []set{
+			A: linksTo(B, C),
+			B: linksTo(C),
+			C: linksTo(D),
+			D: nil,
+		},
+		wantTriangles: map[int64]int{A: 1, B: 1, C: 1},
+		wantLocal:     map[int64]float64{A: 1, B: 1, C: 1 / 3.},
+		wantAverage:   7 / 12.,
+		wantGlobal:    0.6,
+	},
+	{
+		name: "star",
+		g: []set{
+			A: linksTo(B, C, D, E),
+			B: nil,
+			C: nil,
+			D: nil,
+			E: nil,
+		},
+		wantTriangles: map[int64]int{},
+		wantLocal:     map[int64]float64{},
+		wantAverage:   0,
+		wantGlobal:    0,
+	},
+	{
+		name: "isolated nodes",
+		g: []set{
+			A: nil,
+			B: nil,
+		},
+		wantTriangles: map[int64]int{},
+		wantLocal:     map[int64]float64{},
+		wantAverage:   0,
+		wantGlobal:    0,
+	},
+}
+
+func TestClustering(t *testing.T) {
+	const tol = 1e-12
+	for _, test := range clusteringTests {
+		g := simple.NewUndirectedGraph()
+		for u, e := range test.g {
+			// Add nodes that are not defined by an edge.
+			if g.Node(int64(u)) == nil {
+				g.AddNode(simple.Node(u))
+			}
+			for v := range e {
+				g.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v)})
+			}
+		}
+
+		gotTriangles := Triangles(g)
+		if !reflect.DeepEqual(gotTriangles, test.wantTriangles) {
+			t.Errorf("unexpected triangle counts for %q: got:%v want:%v", test.name, gotTriangles, test.wantTriangles)
+		}
+		gotLocal := LocalClustering(g)
+		if !equalFloatMaps(gotLocal, test.wantLocal, tol) {
+			t.Errorf("unexpected local clustering for %q: got:%v want:%v", test.name, gotLocal, test.wantLocal)
+		}
+		gotAverage := AverageClustering(g)
+		if !scalar.EqualWithinAbsOrRel(gotAverage, test.wantAverage, tol, tol) {
+			t.Errorf("unexpected average clustering for %q: got:%v want:%v", test.name, gotAverage, test.wantAverage)
+		}
+		gotGlobal := GlobalClustering(g)
+		if !scalar.EqualWithinAbsOrRel(gotGlobal, test.wantGlobal, tol, tol) {
+			t.Errorf("unexpected global clustering for %q: got:%v want:%v", test.name, gotGlobal, test.wantGlobal)
+		}
+
+		// Directed graphs with reciprocated edges have
+		// the coefficients of the undirected graph.
+		d := simple.NewDirectedGraph()
+		for _, u := range graph.NodesOf(g.Nodes()) {
+			d.AddNode(u)
+		}
+		for _, e := range graph.EdgesOf(g.Edges()) {
+			d.SetEdge(e)
+			d.SetEdge(e.ReversedEdge())
+		}
+		gotLocal = LocalClusteringDirected(d)
+		if !equalFloatMaps(gotLocal, test.wantLocal, tol) {
+			t.Errorf("unexpected directed local clustering for %q: got:%v want:%v", test.name, gotLocal, test.wantLocal)
+		}
+		gotAverage = AverageClusteringDirected(d)
+		if !scalar.EqualWithinAbsOrRel(gotAverage, test.wantAverage, tol, tol) {
+			t.Errorf("unexpected directed average clustering for %q: got:%v want:%v", test.name, gotAverage, test.wantAverage)
+		}
+	}
+
+	if got := AverageClustering(simple.NewUndirectedGraph()); !math.IsNaN(got) {
+		t.Errorf("unexpected average clustering for empty graph: got:%v want:NaN", got)
+	}
+	if got := AverageClusteringDirected(simple.NewDirectedGraph()); !math.IsNaN(got) {
+		t.Errorf("unexpected directed average clustering for empty graph: got:%v want:NaN", got)
+	}
+}
+
+func TestLocalClusteringDirected(t *testing.T) {
+	const tol = 1e-12
+	for _, test := range []struct {
+		name string
+		g    []set
+		want map[int64]float64
+	}{
+		{
+			// Each node has one of the two possible
+			// triangles given its edges.
+			name: "cycle",
+			g: []set{
+				A: linksTo(B),
+				B: linksTo(C),
+				C: linksTo(A),
+			},
+			want: map[int64]float64{A: 0.5, B: 0.5, C: 0.5},
+		},
+		{
+			name: "feed forward",
+			g: []set{
+				A: linksTo(B, C),
+				B: linksTo(C),
+				C: nil,
+			},
+			want: map[int64]float64{A: 0.5, B: 0.5, C: 0.5},
+		},
+		{
+			// C has total degree 4 and one reciprocated
+			// edge, so 2(4×3-2) = 20 possible triangles.
+			// The triangles through A and B count 8 since
+			// the edge between B and C is reciprocated.
+			// All the possible triangles of A are present.
+			name: "partly reciprocated",
+			g: []set{
+				A: linksTo(B, C),
+				B: linksTo(A, C),
+				C: linksTo(B, D),
+				D: nil,
+			},
+			want: map[int64]float64{A: 1, B: 8 / 16., C: 8 / 20.},
+		},
+		{
+			name: "reciprocated pair",
+			g: []set{
+				A: linksTo(B),
+				B: linksTo(A),
+			},
+			want: map[int64]float64{},
+		},
+	} {
+		g := simple.NewDirectedGraph()
+		for u, e := range test.g {
+			if g.Node(int64(u)) == nil {
+				g.AddNode(simple.Node(u))
+			}
+			for v := range e {
+				g.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v)})
+			}
+		}
+		got := LocalClusteringDirected(g)
+		if !equalFloatMaps(got, test.want, tol) {
+			t.Errorf("unexpected directed local clustering for %q: got:%v want:%v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestLocalClusteringWeighted(t *testing.T) {
+	g := simple.NewWeightedUndirectedGraph(0, 0)
+	for _, e := range []simple.WeightedEdge{
+		{F: simple.Node(A), T: simple.Node(B), W: 2},
+		{F: simple.Node(B), T: simple.Node(C), W: 1},
+		{F: simple.Node(C), T: simple.Node(A), W: 1},
+		{F: simple.Node(C), T: simple.Node(D), W: 2},
+	} {
+		g.SetWeightedEdge(e)
+	}
+	// Normalized weights are AB=1, BC=1/2, CA=1/2, CD=1 so the
+	// geometric mean of the triangle weights is 4^(-1/3).
+	tri := math.Cbrt(0.25)
+	want := map[int64]float64{
+		A: tri,
+		B: tri,
+		C: tri / 3,
+	}
+	got := LocalClustering(g)
+	if !equalFloatMaps(got, want, 1e-12) {
+		t.Errorf("unexpected weighted local clustering: got:%v want:%v", got, want)
+	}
+
+	d := simple.NewWeightedDirectedGraph(0, 0)
+	for _, e := range graph.WeightedEdgesOf(g.WeightedEdges()) {
+		d.SetWeightedEdge(e)
+		d.SetWeightedEdge(simple.WeightedEdge{F: e.To(), T: e.From(), W: e.Weight()})
+	}
+	got = LocalClusteringDirected(d)
+	if !equalFloatMaps(got, want, 1e-12) {
+		t.Errorf("unexpected weighted directed local clustering: got:%v want:%v", got, want)
+	}
+
+	// Uniform weights give the unweighted coefficients.
+	u := simple.NewWeightedUndirectedGraph(0, 0)
+	for _, e := range graph.EdgesOf(g.Edges()) {
+		u.SetWeightedEdge(simple.WeightedEdge{F: e.From(), T: e.To(), W: 3})
+	}
+	got = LocalClustering(u)
+	want = map[int64]float64{A: 1, B: 1, C: 1 / 3.}
+	if !equalFloatMaps(got, want, 1e-12) {
+		t.Errorf("unexpected uniformly weighted local clustering: got:%v want:%v", got, want)
+	}
+}
+
+func TestRichClub(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 20; trial++ {
+		const n = 30
+		g := simple.NewUndirectedGraph()
+		for i := 0; i < n; i++ {
+			g.AddNode(simple.Node(i))
+		}
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				if rnd.Float64() < 0.2 {
+					g.SetEdge(simple.Edge{F: simple.Node(i), T: simple.Node(j)})
+				}
+			}
+		}
+
+		got := RichClub(g)
+
+		// Compare with a direct count over the nodes
+		// with degree greater than k.
+		want := make(map[int]float64)
+		nodes := graph.NodesOf(g.Nodes())
+		for k := 0; k < n; k++ {
+			var rich []graph.Node
+			for _, u := range nodes {
+				if g.From(u.ID()).Len() > k {
+					rich = append(rich, u)
+				}
+			}
+			if len(rich) < 2 {
+				continue
+			}
+			var e int
+			for i, u := range rich {
+				for _, v := range rich[i+1:] {
+					if g.HasEdgeBetween(u.ID(), v.ID()) {
+						e++
+					}
+				}
+			}
+			want[k] = 2 * float64(e) / float64(len(rich)*(len(rich)-1))
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("unexpected rich-club coefficients for trial %d:\ngot: %v\nwant:%v", trial, got, want)
+		}
+	}
+}
+
+func equalFloatMaps(a, b map[int64]float64, tol float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		w, ok := b[k]
+		if !ok || !scalar.EqualWithinAbsOrRel(v, w, tol, tol) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,173 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package path
+
+import (
+	"gonum.org/v1/gonum/graph"
+)
+
+// Arborescence generates a minimum spanning arborescence of g rooted at root
+// using the Chu–Liu/Edmonds algorithm, placing the result in the destination,
+// dst. A spanning arborescence is a directed tree in which there is exactly
+// one path from the root to every other node. The destination is not cleared
+// first. The weight of the minimum spanning arborescence is returned. If not
+// all nodes of g are reachable from root, the minimum arborescence spanning
+// the nodes reachable from root will be constructed in dst, and nodes not
+// reachable from root will be added to dst without edges.
+//
+// Nodes and Edges from g are used to construct dst, so if the Node and Edge
+// types used in g are pointer or reference-like, then the values will be shared
+// between the graphs.
+//
+// If dst has nodes that exist in g, Arborescence will panic. Arborescence will
+// also panic if root is not a node in g.
+//
+// The time complexity of Arborescence is O(|V|.|E|).
+func Arborescence(dst WeightedBuilder, g graph.WeightedDirected, root graph.Node) float64 {
+	if g.Node(root.ID()) == nil {
+		panic("arborescence: root not in graph")
+	}
+	for _, u := range graph.NodesOf(g.Nodes()) {
+		dst.AddNode(u)
+	}
+
+	// Find the nodes reachable from
+	// root in breadth first order.
+	nodes := []graph.Node{g.Node(root.ID())}
+	indexOf := map[int64]int{root.ID(): 0}
+	for i := 0; i < len(nodes); i++ {
+		for _, v := range graph.NodesOf(g.From(nodes[i].ID())) {
+			if _, ok := indexOf[v.ID()]; !ok {
+				indexOf[v.ID()] = len(nodes)
+				nodes = append(nodes, v)
+			}
+		}
+	}
+
+	var edges []arbEdge
+	for i, u := range nodes {
+		uid := u.ID()
+		for _, v := range graph.NodesOf(g.From(uid)) {
+			j := indexOf[v.ID()]
+			if i == j {
+				continue
+			}
+			w, ok := g.Weight(uid, v.ID())
+			if !ok {
+				panic("arborescence: unexpected invalid weight")
+			}
+			edges = append(edges, arbEdge{from: i, to: j, weight: w})
+		}
+	}
+
+	var w float64
+	for _, i := range chuLiu(len(nodes), 0, edges) {
+		e := edges[i]
+		dst.SetWeightedEdge(g.WeightedEdge(nodes[e.from].ID(), nodes[e.to].ID()))
+		w += e.weight
+	}
+	return w
+}
+
+// arbEdge is an edge of a graph being contracted by the
+// Chu–Liu/Edmonds algorithm. The orig field holds the index
+// of the edge in the graph before contraction.
+type arbEdge struct {
+	from, to int
+	weight   float64
+	orig     int
+}
+
+// chuLiu returns the indices into edges of the edges of a minimum
+// arborescence rooted at root of the graph with n nodes and the given
+// edges. All nodes must be reachable from root.
+func chuLiu(n, root int, edges []arbEdge) []int {
+	// Find the cheapest edge into each node.
+	in := make([]int, n)
+	for i := range in {
+		in[i] = -1
+	}
+	for i, e := range edges {
+		if e.to != root && e.from != e.to && (in[e.to] < 0 || e.weight < edges[in[e.to]].weight) {
+			in[e.to] = i
+		}
+	}
+
+	// Find the cycles formed by the cheapest edges,
+	// labelling the nodes of each cycle with the
+	// index of the node that replaces the cycle.
+	id := make([]int, n)
+	visited := make([]int, n)
+	for i := range id {
+		id[i] = -1
+		visited[i] = -1
+	}
+	var cycles int
+	for v := range in {
+		u := v
+		for u != root && visited[u] != v && id[u] < 0 {
+			visited[u] = v
+			u = edges[in[u]].from
+		}
+		if u != root && id[u] < 0 {
+			for x := edges[in[u]].from; x != u; x = edges[in[x]].from {
+				id[x] = cycles
+			}
+			id[u] = cycles
+			cycles++
+		}
+	}
+	if cycles == 0 {
+		tree := make([]int, 0, n-1)
+		for v, i := range in {
+			if v != root {
+				tree = append(tree, i)
+			}
+		}
+		return tree
+	}
+
+	// Contract the cycles and find the minimum
+	// arborescence of the contracted graph.
+	inCycle := make([]bool, n)
+	m := cycles
+	for v := range id {
+		if id[v] < 0 {
+			id[v] = m
+			m++
+		} else {
+			inCycle[v] = true
+		}
+	}
+	var contracted []arbEdge
+	for i, e := range edges {
+		from, to := id[e.from], id[e.to]
+		if from == to {
+			continue
+		}
+		w := e.weight
+		if e.to != root {
+			w -= edges[in[e.to]].weight
+		}
+		contracted = append(contracted, arbEdge{from: from, to: to, weight: w, orig: i})
+	}
+
+	// Expand the cycles, keeping all cycle edges
+	// except the edge into the node entered from
+	// outside the cycle.
+	var tree []int
+	entered := make([]bool, n)
+	for _, i := range chuLiu(m, id[root], contracted) {
+		orig := contracted[i].orig
+		tree = append(tree, orig)
+		entered[edges[orig].to] = true
+	}
+	for v, c := range inCycle {
+		if c && !entered[v] {
+			tree = append(tree, in[v])
+		}
+	}
+	return tree
+}
@@ -0,0 +1,217 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package path
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+var arborescenceTests = []struct {
+	name  string
+	edges []simple.WeightedEdge
+	root  int64
+	want  float64
+}{
+	{
+		name: "single node",
+		edges: []simple.WeightedEdge{
+			{F: simple.Node(0), T: simple.Node(1), W: 1},
+		},
+		root: 1,
+		want: 0,
+	},
+	{
+		// The cheapest edges into 1 and 2 form a cycle
+		// that must be broken by the edge from the root.
+		name: "cycle",
+		edges: []simple.WeightedEdge{
+			{F: simple.Node(0), T: simple.Node(1), W: 10},
+			{F: simple.Node(0), T: simple.Node(2), W: 12},
+			{F: simple.Node(1), T: simple.Node(2), W: 1},
+			{F: simple.Node(2), T: simple.Node(1), W: 2},
+		},
+		root: 0,
+		want: 11,
+	},
+	{
+		// Nested cycles from Chu and Liu (1965).
+		name: "nested cycles",
+		edges: []simple.WeightedEdge{
+			{F: simple.Node(0), T: simple.Node(1), W: 5},
+			{F: simple.Node(0), T: simple.Node(2), W: 1},
+			{F: simple.Node(0), T: simple.Node(3), W: 1},
+			{F: simple.Node(1), T: simple.Node(2), W: 11},
+			{F: simple.Node(1), T: simple.Node(4), W: 3},
+			{F: simple.Node(2), T: simple.Node(1), W: 10},
+			{F: simple.Node(2), T: simple.Node(3), W: 1},
+			{F: simple.Node(3), T: simple.Node(2), W: 8},
+			{F: simple.Node(3), T: simple.Node(4), W: 9},
+			{F: simple.Node(4), T: simple.Node(1), W: 7},
+		},
+		root: 0,
+		want: 1 + 1 + 3 + 5,
+	},
+	{
+		name: "unreachable nodes",
+		edges: []simple.WeightedEdge{
+			{F: simple.Node(0), T: simple.Node(1), W: 2},
+			{F: simple.Node(1), T: simple.Node(2), W: 2},
+			{F: simple.Node(3), T: simple.Node(0), W: 1},
+			{F: simple.Node(3), T: simple.Node(2), W: 1},
+		},
+		root: 0,
+		want: 4,
+	},
+}
+
+func TestArborescence(t *testing.T) {
+	t.Parallel()
+	for _, test := range arborescenceTests {
+		g := simple.NewWeightedDirectedGraph(0, math.Inf(1))
+		for _, e := range test.edges {
+			g.SetWeightedEdge(e)
+		}
+		dst := simple.NewWeightedDirectedGraph(0, math.Inf(1))
+		w := Arborescence(dst, g, simple.Node(test.root))
+		if w != test.want {
+			t.Errorf("unexpected arborescence weight for %q: got:%v want:%v", test.name, w, test.want)
+		}
+		checkArborescence(t, test.name, g, dst, test.root, w)
+	}
+}
+
+func TestArborescenceRandom(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		n := 2 + rnd.Intn(5)
+		g := randomWeightedGraph(n, 3*n, true, rnd).(*simple.WeightedDirectedGraph)
+		root := int64(rnd.Intn(n))
+		dst := simple.NewWeightedDirectedGraph(0, math.Inf(1))
+		w := Arborescence(dst, g, simple.Node(root))
+		if want := bruteForceArborescence(g, root); w != want {
+			t.Errorf("unexpected arborescence weight for test %d: got:%v want:%v", i, w, want)
+		}
+		checkArborescence(t, "random", g, dst, root, w)
+	}
+}
+
+// checkArborescence checks that dst is an arborescence of the nodes of g
+// reachable from root with the given weight.
+func checkArborescence(t *testing.T, name string, g, dst graph.WeightedDirected, root int64, weight float64) {
+	t.Helper()
+	if dst.Nodes().Len() != g.Nodes().Len() {
+		t.Errorf("unexpected number of nodes for %q: got:%d want:%d", name, dst.Nodes().Len(), g.Nodes().Len())
+	}
+	reachable := DijkstraFrom(g.Node(root), g)
+	seen := map[int64]bool{root: true}
+	queue := []int64{root}
+	var w float64
+	for len(queue) != 0 {
+		u := queue[0]
+		queue = queue[1:]
+		to := dst.From(u)
+		for to.Next() {
+			v := to.Node().ID()
+			if seen[v] {
+				t.Errorf("node %d reached twice in arborescence for %q", v, name)
+				continue
+			}
+			seen[v] = true
+			queue = append(queue, v)
+			ew, ok := g.Weight(u, v)
+			if !ok {
+				t.Errorf("edge %d->%d not in graph for %q", u, v, name)
+			}
+			w += ew
+		}
+	}
+	nodes := g.Nodes()
+	for nodes.Next() {
+		id := nodes.Node().ID()
+		if seen[id] != !math.IsInf(reachable.WeightTo(id), 1) {
+			t.Errorf("unexpected reachability of node %d in arborescence for %q", id, name)
+		}
+		if id != root && seen[id] && dst.To(id).Len() != 1 {
+			t.Errorf("unexpected in-degree of node %d in arborescence for %q: got:%d want:1", id, name, dst.To(id).Len())
+		}
+	}
+	if w != weight {
+		t.Errorf("unexpected arborescence edge weight sum for %q: got:%v want:%v", name, w, weight)
+	}
+}
+
+// bruteForceArborescence returns the weight of the minimum arborescence of
+// the nodes of g reachable from root by examining every choice of parent.
+func bruteForceArborescence(g graph.WeightedDirected, root int64) float64 {
+	reachable := DijkstraFrom(g.Node(root), g)
+	var nodes []int64
+	var parents [][]int64
+	it := g.Nodes()
+	for it.Next() {
+		id := it.Node().ID()
+		if id == root || math.IsInf(reachable.WeightTo(id), 1) {
+			continue
+		}
+		nodes = append(nodes, id)
+		var p []int64
+		to := g.To(id)
+		for to.Next() {
+			if pid := to.Node().ID(); !math.IsInf(reachable.WeightTo(pid), 1) {
+				p = append(p, pid)
+			}
+		}
+		parents = append(parents, p)
+	}
+
+	best := math.Inf(1)
+	choice := make([]int, len(nodes))
+	parentOf := make(map[int64]int64)
+	for {
+		var w float64
+		for i, id := range nodes {
+			p := parents[i][choice[i]]
+			parentOf[id] = p
+			ew, _ := g.Weight(p, id)
+			w += ew
+		}
+		if w < best && isTree(parentOf, root) {
+			best = w
+		}
+
+		i := 0
+		for ; i < len(choice); i++ {
+			choice[i]++
+			if choice[i] < len(parents[i]) {
+				break
+			}
+			choice[i] = 0
+		}
+		if i == len(choice) {
+			break
+		}
+	}
+	return best
+}
+
+// isTree returns whether following the parents of every node leads to root.
+func isTree(parentOf map[int64]int64, root int64) bool {
+	for u := range parentOf {
+		steps := 0
+		for v := u; v != root; v = parentOf[v] {
+			steps++
+			if steps > len(parentOf) {
+				return false
+			}
+		}
+	}
+	return true
+}
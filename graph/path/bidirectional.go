@@ -0,0 +1,218 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package path
+
+import (
+	"container/heap"
+	"math"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+	"gonum.org/v1/gonum/graph/internal/set"
+)
+
+// BidirectionalDijkstra returns the shortest path from s to t in g found by
+// simultaneous Dijkstra searches forward from s and backward from t. The
+// path and its cost are returned in a Shortest holding only the nodes on the
+// path. If the graph does not implement Weighted, UniformCost is used. If g
+// is a graph.Directed, the backward search follows the edges returned by
+// g.To. BidirectionalDijkstra will panic if g has a reachable negative edge
+// weight.
+//
+// BidirectionalDijkstra typically expands far fewer nodes than DijkstraFrom
+// for a single s-t query.
+func BidirectionalDijkstra(s, t graph.Node, g graph.Graph) Shortest {
+	path, _ := bidirectional(s, t, g, nil)
+	return path
+}
+
+// BidirectionalAStar returns the A*-shortest path from s to t in g found by
+// simultaneous A* searches forward from s and backward from t using the
+// heuristic h. The path and its cost are returned in a Shortest holding only
+// the nodes on the path, along with the number of expanded nodes.
+//
+// The forward and backward searches use the average of the forward and
+// backward heuristic estimates as described in Ikeda et al. "A fast algorithm
+// for finding better routes by AI search techniques" (1994), so h(x, y) must
+// estimate the cost of the path from x to y for any pair of nodes. The path
+// will be the shortest path if the heuristic is consistent. A heuristic is
+// consistent if for any edge from u to v with weight w and any node n,
+//  h(u, n) ≤ w + h(v, n) and h(n, v) ≤ h(n, u) + w.
+//
+// If h is nil, BidirectionalAStar will use the g.HeuristicCost method if g
+// implements HeuristicCoster, falling back to NullHeuristic otherwise. If
+// the graph does not implement Weighted, UniformCost is used. If g is a
+// graph.Directed, the backward search follows the edges returned by g.To.
+// BidirectionalAStar will panic if g has a reachable negative edge weight.
+func BidirectionalAStar(s, t graph.Node, g graph.Graph, h Heuristic) (path Shortest, expanded int) {
+	if h == nil {
+		if g, ok := g.(HeuristicCoster); ok {
+			h = g.HeuristicCost
+		} else {
+			h = NullHeuristic
+		}
+	}
+	return bidirectional(s, t, g, h)
+}
+
+// bidirectional is the common implementation of BidirectionalDijkstra and
+// BidirectionalAStar. If h is nil, no heuristic is used.
+func bidirectional(s, t graph.Node, g graph.Graph, h Heuristic) (path Shortest, expanded int) {
+	if g.Node(s.ID()) == nil || g.Node(t.ID()) == nil {
+		return Shortest{from: s}, 0
+	}
+	if s.ID() == t.ID() {
+		return shortestAlong([]graph.Node{s}, []float64{0}), 0
+	}
+
+	var weight Weighting
+	if wg, ok := g.(Weighted); ok {
+		weight = wg.Weight
+	} else {
+		weight = UniformCost(g)
+	}
+	to := g.From
+	if dg, ok := g.(graph.Directed); ok {
+		to = dg.To
+	}
+
+	// The forward and backward searches are Dijkstra
+	// searches on the edge weights reduced by the
+	// consistent potential
+	//  p(n) = (h(n, t) - h(s, n)) / 2.
+	potential := func(graph.Node) float64 { return 0 }
+	if h != nil {
+		potential = func(n graph.Node) float64 { return (h(n, t) - h(s, n)) / 2 }
+	}
+
+	fwd := newFrontier(s, g.From, weight, potential)
+	bwd := newFrontier(t, to, func(xid, yid int64) (float64, bool) { return weight(yid, xid) }, func(n graph.Node) float64 { return -potential(n) })
+
+	best := math.Inf(1)
+	var meet graph.Node
+	for fwd.queue.Len() != 0 && bwd.queue.Len() != 0 {
+		if fwd.queue[0].dist+bwd.queue[0].dist >= best {
+			break
+		}
+		f, o := fwd, bwd
+		if bwd.queue.Len() < fwd.queue.Len() {
+			f, o = bwd, fwd
+		}
+
+		u := heap.Pop(&f.queue).(distanceNode).node
+		uid := u.ID()
+		if f.settled.Has(uid) {
+			continue
+		}
+		f.settled.Add(uid)
+		expanded++
+
+		it := f.next(uid)
+		for it.Next() {
+			v := it.Node()
+			vid := v.ID()
+			w, ok := f.weight(uid, vid)
+			if !ok {
+				panic("path: bidirectional search unexpected invalid weight")
+			}
+			if w < 0 {
+				panic("path: bidirectional search negative edge weight")
+			}
+			d := f.dist[uid] + w
+			if cur, ok := f.dist[vid]; !ok || d < cur {
+				f.dist[vid] = d
+				f.parent[vid] = u
+				heap.Push(&f.queue, distanceNode{node: v, dist: d + f.potential(v)})
+			}
+			if od, ok := o.dist[vid]; ok && f.dist[vid]+od < best {
+				best = f.dist[vid] + od
+				meet = v
+			}
+		}
+	}
+	if meet == nil {
+		return shortestAlong([]graph.Node{s}, []float64{0}), expanded
+	}
+
+	// Construct the path from s to the meeting
+	// node and then on from there to t.
+	var nodes []graph.Node
+	var dist []float64
+	for n := meet; n != nil; n = fwd.parent[n.ID()] {
+		nodes = append(nodes, n)
+		dist = append(dist, fwd.dist[n.ID()])
+	}
+	ordered.Reverse(nodes)
+	reverseFloats(dist)
+	for n := bwd.parent[meet.ID()]; n != nil; n = bwd.parent[n.ID()] {
+		nodes = append(nodes, n)
+		dist = append(dist, best-bwd.dist[n.ID()])
+	}
+	return shortestAlong(nodes, dist), expanded
+}
+
+// frontier is the state of one direction of a bidirectional search.
+type frontier struct {
+	dist    map[int64]float64
+	parent  map[int64]graph.Node
+	settled set.Int64s
+	queue   priorityQueue
+
+	next      func(int64) graph.Nodes
+	weight    Weighting
+	potential func(graph.Node) float64
+}
+
+// newFrontier returns a frontier for a search starting from u, following
+// the nodes returned by next with edge weights given by weight. Nodes are
+// prioritised by their distance from u plus their potential.
+func newFrontier(u graph.Node, next func(int64) graph.Nodes, weight Weighting, potential func(graph.Node) float64) *frontier {
+	return &frontier{
+		dist:    map[int64]float64{u.ID(): 0},
+		parent:  map[int64]graph.Node{u.ID(): nil},
+		settled: make(set.Int64s),
+		queue:   priorityQueue{{node: u, dist: potential(u)}},
+
+		next:      next,
+		weight:    weight,
+		potential: potential,
+	}
+}
+
+// shortestAlong returns a Shortest holding the path through the given
+// nodes with the given distances from the first node. Any cycles in the
+// path, which must have zero weight, are removed.
+func shortestAlong(nodes []graph.Node, dist []float64) Shortest {
+	indexOf := make(map[int64]int, len(nodes))
+	var n int
+	for i, u := range nodes {
+		if j, ok := indexOf[u.ID()]; ok {
+			for _, v := range nodes[j+1 : n] {
+				delete(indexOf, v.ID())
+			}
+			n = j + 1
+			continue
+		}
+		indexOf[u.ID()] = n
+		nodes[n] = u
+		dist[n] = dist[i]
+		n++
+	}
+	nodes = nodes[:n]
+	dist = dist[:n]
+
+	p := newShortestFrom(nodes[0], nodes)
+	for i := 1; i < len(nodes); i++ {
+		p.set(i, dist[i], i-1)
+	}
+	return p
+}
+
+// reverseFloats reverses the order of elements in f.
+func reverseFloats(f []float64) {
+	for i, j := 0, len(f)-1; i < j; i, j = i+1, j-1 {
+		f[i], f[j] = f[j], f[i]
+	}
+}
@@ -0,0 +1,180 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package path
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/path/internal/testgraphs"
+	"gonum.org/v1/gonum/graph/simple"
+	"gonum.org/v1/gonum/graph/topo"
+)
+
+func TestBidirectionalDijkstra(t *testing.T) {
+	t.Parallel()
+	for _, test := range testgraphs.ShortestPathTests {
+		if test.HasNegativeWeight {
+			continue
+		}
+		g := test.Graph()
+		for _, e := range test.Edges {
+			g.SetWeightedEdge(e)
+		}
+
+		pt := BidirectionalDijkstra(test.Query.From(), test.Query.To(), g.(graph.Graph))
+		checkShortestQuery(t, test.Name, pt, test.Query.From().ID(), test.Query.To().ID(), test.Weight, test.WantPaths)
+
+		np, weight := pt.To(test.NoPathFor.To().ID())
+		if test.NoPathFor.From().ID() == test.Query.From().ID() && (np != nil || !math.IsInf(weight, 1)) {
+			t.Errorf("%q: unexpected path:\ngot: path=%v weight=%f\nwant:path=<nil> weight=+Inf",
+				test.Name, np, weight)
+		}
+	}
+}
+
+func TestBidirectionalAStar(t *testing.T) {
+	t.Parallel()
+	for _, test := range aStarTests {
+		pt, _ := BidirectionalAStar(simple.Node(test.s), simple.Node(test.t), test.g, test.heuristic)
+
+		p, cost := pt.To(test.t)
+
+		if !topo.IsPathIn(test.g, p) {
+			t.Errorf("got path that is not path in input graph for %q", test.name)
+		}
+
+		ap, _ := AStar(simple.Node(test.s), simple.Node(test.t), test.g, test.heuristic)
+		if want := ap.WeightTo(test.t); cost != want {
+			t.Errorf("unexpected cost for %q: got:%v want:%v", test.name, cost, want)
+		}
+
+		var got = make([]int64, 0, len(p))
+		for _, n := range p {
+			got = append(got, n.ID())
+		}
+		if test.wantPath != nil && !reflect.DeepEqual(got, test.wantPath) {
+			t.Errorf("unexpected result for %q:\ngot: %v\nwant:%v", test.name, got, test.wantPath)
+		}
+	}
+}
+
+func TestBidirectionalRandom(t *testing.T) {
+	t.Parallel()
+	for _, directed := range []bool{true, false} {
+		for seed := uint64(1); seed <= 5; seed++ {
+			g := randomWeightedGraph(50, 150, directed, rand.NewSource(seed))
+			lm := NewLandmarks(g, 4)
+			for sid := int64(0); sid < 50; sid += 7 {
+				want := DijkstraFrom(simple.Node(sid), g)
+				for tid := int64(0); tid < 50; tid++ {
+					w := want.WeightTo(tid)
+
+					got := BidirectionalDijkstra(simple.Node(sid), simple.Node(tid), g)
+					checkRandomPath(t, "bidirectional Dijkstra", g, got, sid, tid, w)
+
+					got, _ = BidirectionalAStar(simple.Node(sid), simple.Node(tid), g, lm.HeuristicCost)
+					checkRandomPath(t, "bidirectional A*", g, got, sid, tid, w)
+				}
+			}
+		}
+	}
+}
+
+// randomWeightedGraph returns a weighted graph with n nodes and up to m
+// edges with random endpoints and weights.
+func randomWeightedGraph(n, m int, directed bool, src rand.Source) graph.Weighted {
+	rnd := rand.New(src)
+	var g interface {
+		graph.Weighted
+		AddNode(graph.Node)
+		SetWeightedEdge(graph.WeightedEdge)
+	}
+	if directed {
+		g = simple.NewWeightedDirectedGraph(0, math.Inf(1))
+	} else {
+		g = simple.NewWeightedUndirectedGraph(0, math.Inf(1))
+	}
+	for i := 0; i < n; i++ {
+		g.AddNode(simple.Node(i))
+	}
+	for i := 0; i < m; i++ {
+		u, v := rnd.Intn(n), rnd.Intn(n)
+		if u == v {
+			continue
+		}
+		g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(u), T: simple.Node(v), W: float64(1 + rnd.Intn(10))})
+	}
+	return g
+}
+
+// checkRandomPath checks that the path from sid to tid in p is a path
+// in g with the given weight.
+func checkRandomPath(t *testing.T, name string, g graph.Weighted, p Shortest, sid, tid int64, want float64) {
+	t.Helper()
+	path, weight := p.To(tid)
+	if weight != want {
+		t.Errorf("unexpected %s weight from %d to %d: got:%v want:%v", name, sid, tid, weight, want)
+		return
+	}
+	if math.IsInf(want, 1) {
+		if path != nil {
+			t.Errorf("unexpected %s path from %d to %d: got:%v", name, sid, tid, path)
+		}
+		return
+	}
+	if path[0].ID() != sid || path[len(path)-1].ID() != tid {
+		t.Errorf("unexpected %s path ends from %d to %d: got:%v", name, sid, tid, path)
+		return
+	}
+	var sum float64
+	for i := 1; i < len(path); i++ {
+		w, ok := g.Weight(path[i-1].ID(), path[i].ID())
+		if !ok {
+			t.Errorf("%s path from %d to %d is not a path in g: %v", name, sid, tid, path)
+			return
+		}
+		sum += w
+	}
+	if sum != want {
+		t.Errorf("unexpected %s path weight from %d to %d: got:%v want:%v", name, sid, tid, sum, want)
+	}
+}
+
+// checkShortestQuery checks that p holds one of the wanted paths from sid
+// to tid with the given weight.
+func checkShortestQuery(t *testing.T, name string, p Shortest, sid, tid int64, weight float64, wantPaths [][]int64) {
+	t.Helper()
+	if p.From().ID() != sid {
+		t.Fatalf("%q: unexpected from node ID: got:%d want:%d", name, p.From().ID(), sid)
+	}
+
+	path, w := p.To(tid)
+	if w != weight {
+		t.Errorf("%q: unexpected weight from To: got:%f want:%f", name, w, weight)
+	}
+	if w := p.WeightTo(tid); w != weight {
+		t.Errorf("%q: unexpected weight from WeightTo: got:%f want:%f", name, w, weight)
+	}
+
+	var got []int64
+	for _, n := range path {
+		got = append(got, n.ID())
+	}
+	ok := len(got) == 0 && len(wantPaths) == 0
+	for _, sp := range wantPaths {
+		if reflect.DeepEqual(got, sp) {
+			ok = true
+			break
+		}
+	}
+	if !ok {
+		t.Errorf("%q: unexpected shortest path:\ngot: %v\nwant from:%v", name, got, wantPaths)
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package path
+
+import (
+	"runtime"
+	"sync"
+
+	"gonum.org/v1/gonum/graph"
+)
+
+// Boruvka generates a minimum spanning tree of g by repeated concurrent
+// selection of the lightest edge leaving each component, placing the result
+// in the destination, dst. If the edge weights of g are distinct it will be
+// the unique minimum spanning tree of g. The destination is not cleared
+// first. The weight of the minimum spanning tree is returned. If g is not
+// connected, a minimum spanning forest will be constructed in dst and the
+// sum of minimum spanning tree weights will be returned.
+//
+// The search for the lightest edges is performed by GOMAXPROCS goroutines
+// each examining a share of the edges of g. Ties between edges of equal
+// weight are broken consistently, so Boruvka is suitable for graphs with
+// non-distinct edge weights.
+//
+// Nodes and Edges from g are used to construct dst, so if the Node and Edge
+// types used in g are pointer or reference-like, then the values will be shared
+// between the graphs.
+//
+// If dst has nodes that exist in g, Boruvka will panic.
+func Boruvka(dst WeightedBuilder, g UndirectedWeightLister) float64 {
+	nodes := graph.NodesOf(g.Nodes())
+	indexOf := make(map[int64]int, len(nodes))
+	for i, u := range nodes {
+		dst.AddNode(u)
+		indexOf[u.ID()] = i
+	}
+	edges := graph.WeightedEdgesOf(g.WeightedEdges())
+
+	ds := newDisjointSet()
+	for _, u := range nodes {
+		ds.makeSet(u.ID())
+	}
+
+	workers := runtime.GOMAXPROCS(0)
+	if workers > len(edges) {
+		workers = len(edges)
+	}
+	comp := make([]int, len(nodes))
+	lightest := make([][]int, workers)
+
+	var w float64
+	for {
+		// Label the components so that the
+		// workers do not need to mutate ds.
+		label := make(map[*disjointSetNode]int)
+		for i, u := range nodes {
+			r := ds.find(u.ID())
+			l, ok := label[r]
+			if !ok {
+				l = len(label)
+				label[r] = l
+			}
+			comp[i] = l
+		}
+		n := len(label)
+
+		var wg sync.WaitGroup
+		for k := range lightest {
+			wg.Add(1)
+			go func(k int) {
+				defer wg.Done()
+				best := lightest[k][:0]
+				for i := 0; i < n; i++ {
+					best = append(best, -1)
+				}
+				for i := k; i < len(edges); i += workers {
+					e := edges[i]
+					cu := comp[indexOf[e.From().ID()]]
+					cv := comp[indexOf[e.To().ID()]]
+					if cu == cv {
+						continue
+					}
+					if best[cu] < 0 || lighter(edges, i, best[cu]) {
+						best[cu] = i
+					}
+					if best[cv] < 0 || lighter(edges, i, best[cv]) {
+						best[cv] = i
+					}
+				}
+				lightest[k] = best
+			}(k)
+		}
+		wg.Wait()
+
+		var merged bool
+		for c := 0; c < n; c++ {
+			best := -1
+			for _, l := range lightest {
+				if i := l[c]; i >= 0 && (best < 0 || lighter(edges, i, best)) {
+					best = i
+				}
+			}
+			if best < 0 {
+				continue
+			}
+			e := edges[best]
+			if s1, s2 := ds.find(e.From().ID()), ds.find(e.To().ID()); s1 != s2 {
+				ds.union(s1, s2)
+				dst.SetWeightedEdge(g.WeightedEdge(e.From().ID(), e.To().ID()))
+				w += e.Weight()
+				merged = true
+			}
+		}
+		if !merged {
+			return w
+		}
+	}
+}
+
+// lighter returns whether edges[i] is lighter than edges[j], breaking
+// ties by edge index.
+func lighter(edges []graph.WeightedEdge, i, j int) bool {
+	wi, wj := edges[i].Weight(), edges[j].Weight()
+	return wi < wj || (wi == wj && i < j)
+}
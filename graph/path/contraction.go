@@ -0,0 +1,559 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package path
+
+import (
+	"container/heap"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+)
+
+// chVersion is the current binary codec version of ContractionHierarchy.
+const chVersion uint32 = 0x1
+
+// chWitnessLimit is the maximum number of nodes settled by a witness
+// search during contraction. Witness searches that reach the limit add
+// a shortcut that may not be necessary, which does not affect the
+// correctness of queries.
+const chWitnessLimit = 500
+
+var (
+	errCHTooSmall  = errors.New("path: input slice too small")
+	errCHBadBuffer = errors.New("path: data buffer size mismatch")
+	errCHBadData   = errors.New("path: invalid contraction hierarchy data")
+)
+
+// ContractionHierarchy is a preprocessed graph that answers shortest path
+// queries between pairs of nodes. Contraction hierarchies are suited to
+// static graphs, such as road networks, that are queried many times. See
+// Geisberger et al. "Contraction hierarchies: Faster and simpler
+// hierarchical routing in road networks" (2008) for details of the method.
+type ContractionHierarchy struct {
+	nodes   []graph.Node
+	indexOf map[int64]int
+
+	// rank holds the contraction order of
+	// each node.
+	rank []int
+
+	// up[i] holds the edges from node i to
+	// higher ranked nodes and down[i] holds
+	// the edges to node i from higher ranked
+	// nodes.
+	up   [][]chEdge
+	down [][]chEdge
+}
+
+// chEdge is an edge of a contraction hierarchy. Shortcut edges hold the
+// index of the contracted node they bypass in via, which is -1 for edges
+// of the original graph.
+type chEdge struct {
+	node   int
+	weight float64
+	via    int
+}
+
+// NewContractionHierarchy returns a contraction hierarchy for the graph g.
+// If the graph does not implement Weighted, UniformCost is used. If g is a
+// graph.Undirected, edges may be traversed in either direction. Self edges
+// are ignored. NewContractionHierarchy will panic if g has a negative edge
+// weight.
+//
+// Nodes are contracted in order of increasing edge difference with the
+// number of contracted neighbours as a tie-breaking term, with priorities
+// updated lazily.
+func NewContractionHierarchy(g graph.Graph) *ContractionHierarchy {
+	nodes := graph.NodesOf(g.Nodes())
+	sort.Sort(ordered.ByID(nodes))
+	n := len(nodes)
+	indexOf := make(map[int64]int, n)
+	for i, u := range nodes {
+		indexOf[u.ID()] = i
+	}
+
+	var weight Weighting
+	if wg, ok := g.(Weighted); ok {
+		weight = wg.Weight
+	} else {
+		weight = UniformCost(g)
+	}
+
+	// out and in hold the edges of the graph
+	// of uncontracted nodes, including shortcuts.
+	c := contractor{
+		out:        make([]map[int]chEdge, n),
+		in:         make([]map[int]chEdge, n),
+		contracted: make([]bool, n),
+		deleted:    make([]int, n),
+		dist:       make(map[int]float64),
+	}
+	for i := range nodes {
+		c.out[i] = make(map[int]chEdge)
+		c.in[i] = make(map[int]chEdge)
+	}
+	for i, u := range nodes {
+		uid := u.ID()
+		to := g.From(uid)
+		for to.Next() {
+			vid := to.Node().ID()
+			if vid == uid {
+				continue
+			}
+			w, ok := weight(uid, vid)
+			if !ok {
+				panic("path: contraction hierarchy unexpected invalid weight")
+			}
+			if w < 0 {
+				panic("path: contraction hierarchy negative edge weight")
+			}
+			c.addEdge(i, indexOf[vid], w, -1)
+		}
+	}
+
+	ch := &ContractionHierarchy{
+		nodes:   nodes,
+		indexOf: indexOf,
+		rank:    make([]int, n),
+		up:      make([][]chEdge, n),
+		down:    make([][]chEdge, n),
+	}
+	q := make(chQueue, n)
+	for i := range q {
+		q[i] = chPriority{node: i, priority: c.priority(i)}
+	}
+	heap.Init(&q)
+	for r := 0; q.Len() != 0; {
+		u := heap.Pop(&q).(chPriority)
+		if p := c.priority(u.node); q.Len() != 0 && p > q[0].priority {
+			u.priority = p
+			heap.Push(&q, u)
+			continue
+		}
+
+		for _, s := range c.shortcuts(u.node) {
+			c.addEdge(s.from, s.to, s.weight, u.node)
+		}
+		ch.up[u.node] = sortedEdges(c.out[u.node])
+		ch.down[u.node] = sortedEdges(c.in[u.node])
+		for v := range c.in[u.node] {
+			delete(c.out[v], u.node)
+			c.deleted[v]++
+		}
+		for v := range c.out[u.node] {
+			delete(c.in[v], u.node)
+			c.deleted[v]++
+		}
+		c.out[u.node] = nil
+		c.in[u.node] = nil
+		c.contracted[u.node] = true
+		ch.rank[u.node] = r
+		r++
+	}
+	return ch
+}
+
+// contractor holds the state of contraction hierarchy construction.
+type contractor struct {
+	out, in    []map[int]chEdge
+	contracted []bool
+	deleted    []int
+
+	// dist is the witness search work space.
+	dist map[int]float64
+}
+
+// addEdge adds an edge from u to v with the given weight if there is
+// no shorter edge from u to v.
+func (c *contractor) addEdge(u, v int, weight float64, via int) {
+	if e, ok := c.out[u][v]; ok && e.weight <= weight {
+		return
+	}
+	c.out[u][v] = chEdge{node: v, weight: weight, via: via}
+	c.in[v][u] = chEdge{node: u, weight: weight, via: via}
+}
+
+// shortcut is a shortcut edge required by the contraction of a node.
+type shortcut struct {
+	from, to int
+	weight   float64
+}
+
+// shortcuts returns the shortcuts that must be added to preserve
+// shortest path distances between the remaining nodes when v is
+// contracted.
+func (c *contractor) shortcuts(v int) []shortcut {
+	var s []shortcut
+	targets := sortedEdges(c.out[v])
+	for _, in := range sortedEdges(c.in[v]) {
+		var max float64
+		for _, out := range targets {
+			if out.node != in.node {
+				max = math.Max(max, in.weight+out.weight)
+			}
+		}
+		c.witness(in.node, v, max)
+		for _, out := range targets {
+			if out.node == in.node {
+				continue
+			}
+			w := in.weight + out.weight
+			if d, ok := c.dist[out.node]; ok && d <= w {
+				continue
+			}
+			s = append(s, shortcut{from: in.node, to: out.node, weight: w})
+		}
+	}
+	return s
+}
+
+// witness performs a Dijkstra search from u in the graph of uncontracted
+// nodes excluding v, stopping when the search distance exceeds max or the
+// witness limit is reached. The distances found are left in c.dist.
+func (c *contractor) witness(u, v int, max float64) {
+	for k := range c.dist {
+		delete(c.dist, k)
+	}
+	c.dist[u] = 0
+	q := chQueue{{node: u}}
+	for settled := 0; q.Len() != 0 && settled < chWitnessLimit; {
+		mid := heap.Pop(&q).(chPriority)
+		if mid.priority > c.dist[mid.node] {
+			continue
+		}
+		if mid.priority > max {
+			break
+		}
+		settled++
+		for x, e := range c.out[mid.node] {
+			if x == v {
+				continue
+			}
+			d := mid.priority + e.weight
+			if cur, ok := c.dist[x]; !ok || d < cur {
+				c.dist[x] = d
+				heap.Push(&q, chPriority{node: x, priority: d})
+			}
+		}
+	}
+}
+
+// priority returns the contraction priority of the node v.
+func (c *contractor) priority(v int) float64 {
+	return float64(len(c.shortcuts(v))-len(c.in[v])-len(c.out[v])) + float64(c.deleted[v])
+}
+
+// sortedEdges returns the edges in m sorted by node.
+func sortedEdges(m map[int]chEdge) []chEdge {
+	if len(m) == 0 {
+		return nil
+	}
+	e := make([]chEdge, 0, len(m))
+	for _, v := range m {
+		e = append(e, v)
+	}
+	sort.Slice(e, func(i, j int) bool { return e[i].node < e[j].node })
+	return e
+}
+
+// ShortestBetween returns the shortest path from the node with ID sid to
+// the node with ID tid. The path and its cost are returned in a Shortest
+// holding only the nodes on the path. If either node is not in the
+// hierarchy, the returned Shortest has no nodes.
+func (ch *ContractionHierarchy) ShortestBetween(sid, tid int64) Shortest {
+	s, ok := ch.indexOf[sid]
+	if !ok {
+		return Shortest{from: chNode(sid)}
+	}
+	t, ok := ch.indexOf[tid]
+	if !ok {
+		return Shortest{from: ch.nodes[s]}
+	}
+	if s == t {
+		return shortestAlong([]graph.Node{ch.nodes[s]}, []float64{0})
+	}
+
+	// Perform a bidirectional search, forward from s
+	// on edges to higher ranked nodes and backward from
+	// t on edges from higher ranked nodes. Each search
+	// stops when it cannot improve the best path found.
+	var (
+		dist   = [2]map[int]float64{{s: 0}, {t: 0}}
+		parent = [2]map[int]int{{s: -1}, {t: -1}}
+		queue  = [2]chQueue{{{node: s}}, {{node: t}}}
+		edges  = [2][][]chEdge{ch.up, ch.down}
+		best   = math.Inf(1)
+		meet   = -1
+	)
+	for queue[0].Len() != 0 || queue[1].Len() != 0 {
+		d := 0
+		if queue[0].Len() == 0 || (queue[1].Len() != 0 && queue[1][0].priority < queue[0][0].priority) {
+			d = 1
+		}
+		mid := heap.Pop(&queue[d]).(chPriority)
+		if mid.priority > dist[d][mid.node] {
+			continue
+		}
+		if mid.priority >= best {
+			queue[d] = queue[d][:0]
+			continue
+		}
+		if od, ok := dist[1-d][mid.node]; ok && mid.priority+od < best {
+			best = mid.priority + od
+			meet = mid.node
+		}
+		for _, e := range edges[d][mid.node] {
+			w := mid.priority + e.weight
+			if cur, ok := dist[d][e.node]; !ok || w < cur {
+				dist[d][e.node] = w
+				parent[d][e.node] = mid.node
+				heap.Push(&queue[d], chPriority{node: e.node, priority: w})
+			}
+		}
+	}
+	if meet < 0 {
+		return shortestAlong([]graph.Node{ch.nodes[s]}, []float64{0})
+	}
+
+	// Unpack the shortcuts on the path from s to
+	// the meeting node and from there on to t.
+	var up []int
+	for u := meet; u != s; u = parent[0][u] {
+		up = append(up, u)
+	}
+	up = append(up, s)
+	path := []int{s}
+	for i := len(up) - 1; i > 0; i-- {
+		path = ch.unpack(path, up[i], up[i-1])
+	}
+	for u := meet; u != t; u = parent[1][u] {
+		path = ch.unpack(path, u, parent[1][u])
+	}
+
+	nodes := make([]graph.Node, len(path))
+	weights := make([]float64, len(path))
+	for i, u := range path {
+		nodes[i] = ch.nodes[u]
+		if i != 0 {
+			weights[i] = weights[i-1] + ch.edge(path[i-1], u).weight
+		}
+	}
+	return shortestAlong(nodes, weights)
+}
+
+// unpack appends the nodes after u on the path of original graph edges
+// represented by the hierarchy edge from u to v to path.
+func (ch *ContractionHierarchy) unpack(path []int, u, v int) []int {
+	e := ch.edge(u, v)
+	if e.via < 0 {
+		return append(path, v)
+	}
+	path = ch.unpack(path, u, e.via)
+	return ch.unpack(path, e.via, v)
+}
+
+// edge returns the hierarchy edge from u to v.
+func (ch *ContractionHierarchy) edge(u, v int) chEdge {
+	var edges []chEdge
+	var other int
+	if ch.rank[u] < ch.rank[v] {
+		edges, other = ch.up[u], v
+	} else {
+		edges, other = ch.down[v], u
+	}
+	i := sort.Search(len(edges), func(i int) bool { return edges[i].node >= other })
+	if i == len(edges) || edges[i].node != other {
+		panic("path: missing contraction hierarchy edge")
+	}
+	return edges[i]
+}
+
+// chPriority is a node priority for contraction hierarchy construction
+// and queries.
+type chPriority struct {
+	node     int
+	priority float64
+}
+
+// chQueue is a min-priority queue of chPriority values.
+type chQueue []chPriority
+
+func (q chQueue) Len() int            { return len(q) }
+func (q chQueue) Less(i, j int) bool  { return q[i].priority < q[j].priority }
+func (q chQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *chQueue) Push(n interface{}) { *q = append(*q, n.(chPriority)) }
+func (q *chQueue) Pop() interface{} {
+	t := *q
+	var n chPriority
+	n, *q = t[len(t)-1], t[:len(t)-1]
+	return n
+}
+
+// chNode is the graph.Node type of nodes of an unmarshaled
+// ContractionHierarchy.
+type chNode int64
+
+// ID returns the ID of the node.
+func (n chNode) ID() int64 { return int64(n) }
+
+const (
+	chHeaderSize = 12
+	chEdgeSize   = 24
+)
+
+// MarshalBinary encodes the receiver into a binary form and returns the result.
+//
+// ContractionHierarchy is little-endian encoded as follows:
+//   0 -  3  Version = 1          (uint32)
+//   4 - 11  number of nodes      (int64)
+//  12 - ..  node records in order of ascending node ID
+//
+// Each node record is encoded as follows:
+//   0 -  7  node ID              (int64)
+//   8 - 15  rank                 (int64)
+//  16 - 23  number of up edges   (int64)
+//  24 - ..  up edges
+//   . - ..  number of down edges (int64)
+//   . - ..  down edges
+//
+// Each edge is encoded as follows:
+//   0 -  7  node index           (int64)
+//   8 - 15  weight               (float64)
+//  16 - 23  via node index or -1 (int64)
+func (ch *ContractionHierarchy) MarshalBinary() ([]byte, error) {
+	size := chHeaderSize
+	for i := range ch.nodes {
+		size += 32 + (len(ch.up[i])+len(ch.down[i]))*chEdgeSize
+	}
+	buf := make([]byte, size)
+	binary.LittleEndian.PutUint32(buf, chVersion)
+	binary.LittleEndian.PutUint64(buf[4:], uint64(len(ch.nodes)))
+	p := chHeaderSize
+	putInt := func(v int64) {
+		binary.LittleEndian.PutUint64(buf[p:], uint64(v))
+		p += 8
+	}
+	putEdges := func(edges []chEdge) {
+		putInt(int64(len(edges)))
+		for _, e := range edges {
+			putInt(int64(e.node))
+			binary.LittleEndian.PutUint64(buf[p:], math.Float64bits(e.weight))
+			p += 8
+			putInt(int64(e.via))
+		}
+	}
+	for i, u := range ch.nodes {
+		putInt(u.ID())
+		putInt(int64(ch.rank[i]))
+		putEdges(ch.up[i])
+		putEdges(ch.down[i])
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary decodes the binary form into the receiver.
+// It panics if the receiver is a non-empty ContractionHierarchy.
+// The nodes of the decoded hierarchy hold only the node IDs of
+// the original graph.
+//
+// See MarshalBinary for the on-disk layout.
+//
+// UnmarshalBinary does not limit the size of the unmarshaled hierarchy,
+// and so it should not be used on untrusted data.
+func (ch *ContractionHierarchy) UnmarshalBinary(data []byte) error {
+	if len(ch.nodes) != 0 {
+		panic("path: unmarshal into non-empty contraction hierarchy")
+	}
+	if len(data) < chHeaderSize {
+		return errCHTooSmall
+	}
+	if v := binary.LittleEndian.Uint32(data); v != chVersion {
+		return fmt.Errorf("path: incorrect version: %d", v)
+	}
+	n := int64(binary.LittleEndian.Uint64(data[4:]))
+	if n < 0 || n > int64(len(data)-chHeaderSize)/32 {
+		return errCHBadBuffer
+	}
+
+	p := chHeaderSize
+	getInt := func() (int64, bool) {
+		if len(data)-p < 8 {
+			return 0, false
+		}
+		v := int64(binary.LittleEndian.Uint64(data[p:]))
+		p += 8
+		return v, true
+	}
+	getEdges := func() ([]chEdge, error) {
+		m, ok := getInt()
+		if !ok || m < 0 || m > int64(len(data)-p)/chEdgeSize {
+			return nil, errCHBadBuffer
+		}
+		if m == 0 {
+			return nil, nil
+		}
+		edges := make([]chEdge, m)
+		for i := range edges {
+			node, _ := getInt()
+			weight := math.Float64frombits(binary.LittleEndian.Uint64(data[p:]))
+			p += 8
+			via, _ := getInt()
+			if node < 0 || node >= n || via < -1 || via >= n {
+				return nil, errCHBadData
+			}
+			edges[i] = chEdge{node: int(node), weight: weight, via: int(via)}
+		}
+		return edges, nil
+	}
+
+	nodes := make([]graph.Node, n)
+	indexOf := make(map[int64]int, n)
+	rank := make([]int, n)
+	up := make([][]chEdge, n)
+	down := make([][]chEdge, n)
+	for i := range nodes {
+		id, ok := getInt()
+		if !ok {
+			return errCHBadBuffer
+		}
+		if _, exists := indexOf[id]; exists {
+			return errCHBadData
+		}
+		nodes[i] = chNode(id)
+		indexOf[id] = i
+		r, ok := getInt()
+		if !ok {
+			return errCHBadBuffer
+		}
+		if r < 0 || r >= n {
+			return errCHBadData
+		}
+		rank[i] = int(r)
+		var err error
+		up[i], err = getEdges()
+		if err != nil {
+			return err
+		}
+		down[i], err = getEdges()
+		if err != nil {
+			return err
+		}
+	}
+	if p != len(data) {
+		return errCHBadBuffer
+	}
+
+	ch.nodes = nodes
+	ch.indexOf = indexOf
+	ch.rank = rank
+	ch.up = up
+	ch.down = down
+	return nil
+}
@@ -0,0 +1,127 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package path
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/path/internal/testgraphs"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+func TestContractionHierarchy(t *testing.T) {
+	t.Parallel()
+	for _, test := range testgraphs.ShortestPathTests {
+		if test.HasNegativeWeight {
+			continue
+		}
+		g := test.Graph()
+		for _, e := range test.Edges {
+			g.SetWeightedEdge(e)
+		}
+
+		ch := NewContractionHierarchy(g.(graph.Graph))
+		pt := ch.ShortestBetween(test.Query.From().ID(), test.Query.To().ID())
+		checkShortestQuery(t, test.Name, pt, test.Query.From().ID(), test.Query.To().ID(), test.Weight, test.WantPaths)
+
+		np := ch.ShortestBetween(test.NoPathFor.From().ID(), test.NoPathFor.To().ID())
+		if p, weight := np.To(test.NoPathFor.To().ID()); p != nil || !math.IsInf(weight, 1) {
+			t.Errorf("%q: unexpected path:\ngot: path=%v weight=%f\nwant:path=<nil> weight=+Inf",
+				test.Name, p, weight)
+		}
+	}
+}
+
+func TestContractionHierarchyRandom(t *testing.T) {
+	t.Parallel()
+	for _, directed := range []bool{true, false} {
+		for seed := uint64(1); seed <= 5; seed++ {
+			g := randomWeightedGraph(60, 200, directed, rand.NewSource(seed))
+			ch := NewContractionHierarchy(g)
+			for sid := int64(0); sid < 60; sid += 5 {
+				want := DijkstraFrom(simple.Node(sid), g)
+				for tid := int64(0); tid < 60; tid++ {
+					got := ch.ShortestBetween(sid, tid)
+					checkRandomPath(t, "contraction hierarchy", g, got, sid, tid, want.WeightTo(tid))
+				}
+			}
+		}
+	}
+}
+
+func TestContractionHierarchyMarshal(t *testing.T) {
+	t.Parallel()
+	g := randomWeightedGraph(60, 200, true, rand.NewSource(1))
+	ch := NewContractionHierarchy(g)
+	data, err := ch.MarshalBinary()
+	if err != nil {
+		t.Fatalf("unexpected error marshaling: %v", err)
+	}
+
+	var got ContractionHierarchy
+	err = got.UnmarshalBinary(data)
+	if err != nil {
+		t.Fatalf("unexpected error unmarshaling: %v", err)
+	}
+	for sid := int64(0); sid < 60; sid += 3 {
+		want := DijkstraFrom(simple.Node(sid), g)
+		for tid := int64(0); tid < 60; tid++ {
+			checkRandomPath(t, "unmarshaled contraction hierarchy", g, got.ShortestBetween(sid, tid), sid, tid, want.WeightTo(tid))
+		}
+	}
+
+	remarshaled, err := got.MarshalBinary()
+	if err != nil {
+		t.Fatalf("unexpected error remarshaling: %v", err)
+	}
+	if string(remarshaled) != string(data) {
+		t.Error("unexpected change in marshaled data after round trip")
+	}
+
+	for _, bad := range [][]byte{
+		nil,
+		data[:len(data)-1],
+		append(append([]byte(nil), data...), 0),
+	} {
+		var ch ContractionHierarchy
+		if err := ch.UnmarshalBinary(bad); err == nil {
+			t.Errorf("expected error unmarshaling %d bytes", len(bad))
+		}
+	}
+
+	panicked := func() (panicked bool) {
+		defer func() {
+			panicked = recover() != nil
+		}()
+		got.UnmarshalBinary(data)
+		return false
+	}()
+	if !panicked {
+		t.Error("expected panic unmarshaling into non-empty contraction hierarchy")
+	}
+}
+
+func BenchmarkContractionHierarchy(b *testing.B) {
+	g := randomWeightedGraph(1000, 4000, true, rand.NewSource(1))
+	ch := NewContractionHierarchy(g)
+	rnd := rand.New(rand.NewSource(1))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ch.ShortestBetween(int64(rnd.Intn(1000)), int64(rnd.Intn(1000)))
+	}
+}
+
+func BenchmarkBidirectionalDijkstra(b *testing.B) {
+	g := randomWeightedGraph(1000, 4000, true, rand.NewSource(1))
+	rnd := rand.New(rand.NewSource(1))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		BidirectionalDijkstra(simple.Node(rnd.Intn(1000)), simple.Node(rnd.Intn(1000)), g)
+	}
+}
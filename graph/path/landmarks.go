@@ -0,0 +1,169 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package path
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+)
+
+// Landmarks holds the shortest path distances between a set of landmark
+// nodes and all the nodes of a graph. Landmarks provides a consistent
+// heuristic for A* search using landmarks and the triangle inequality
+// (ALT) as described in Goldberg and Harrelson "Computing the shortest
+// path: A* search meets graph theory" (2005).
+type Landmarks struct {
+	landmarks []graph.Node
+	indexOf   map[int64]int
+
+	// from[l][i] and to[l][i] hold the distance
+	// from landmark l to node i and from node i
+	// to landmark l.
+	from [][]float64
+	to   [][]float64
+}
+
+// NewLandmarks returns the distances between at most k landmark nodes
+// and all the nodes of g. Landmarks are chosen deterministically by
+// farthest selection: each new landmark is the node farthest from all
+// previously chosen landmarks, with unreachable nodes considered to be
+// farthest. If the graph does not implement Weighted, UniformCost is used.
+// NewLandmarks will panic if g has a negative edge weight or if k is less
+// than one.
+func NewLandmarks(g graph.Graph, k int) *Landmarks {
+	if k < 1 {
+		panic("path: invalid number of landmarks")
+	}
+	nodes := graph.NodesOf(g.Nodes())
+	sort.Sort(ordered.ByID(nodes))
+	indexOf := make(map[int64]int, len(nodes))
+	for i, n := range nodes {
+		indexOf[n.ID()] = i
+	}
+	l := &Landmarks{indexOf: indexOf}
+	if len(nodes) == 0 {
+		return l
+	}
+
+	var rev *reverseGraph
+	if dg, ok := g.(graph.Directed); ok {
+		rev = newReverseGraph(dg)
+	}
+	distances := func(u graph.Node, g graph.Graph) []float64 {
+		d := make([]float64, len(nodes))
+		p := DijkstraFrom(u, g)
+		for i, n := range nodes {
+			d[i] = p.WeightTo(n.ID())
+		}
+		return d
+	}
+
+	// The first landmark is the node farthest
+	// from the node with the lowest ID.
+	nearest := distances(nodes[0], g)
+	for len(l.landmarks) < k {
+		next := -1
+		max := 0.0
+		for i, d := range nearest {
+			if d > max {
+				next, max = i, d
+			}
+		}
+		if next < 0 {
+			break
+		}
+		u := nodes[next]
+		from := distances(u, g)
+		to := from
+		if rev != nil {
+			to = distances(u, rev)
+		}
+		l.landmarks = append(l.landmarks, u)
+		l.from = append(l.from, from)
+		l.to = append(l.to, to)
+
+		if len(l.landmarks) == 1 {
+			copy(nearest, from)
+		} else {
+			for i, d := range from {
+				nearest[i] = math.Min(nearest[i], d)
+			}
+		}
+		nearest[next] = 0
+	}
+	return l
+}
+
+// Nodes returns the landmark nodes in the order they were chosen.
+func (l *Landmarks) Nodes() []graph.Node {
+	return l.landmarks
+}
+
+// HeuristicCost returns a lower bound on the cost of the shortest path
+// from x to y derived from the triangle inequality for each landmark.
+// HeuristicCost returns zero if either node is not in the graph used to
+// construct the landmarks.
+func (l *Landmarks) HeuristicCost(x, y graph.Node) float64 {
+	i, ok := l.indexOf[x.ID()]
+	if !ok {
+		return 0
+	}
+	j, ok := l.indexOf[y.ID()]
+	if !ok {
+		return 0
+	}
+
+	// For each landmark L,
+	//  d(x, y) ≥ d(L, y) - d(L, x) and
+	//  d(x, y) ≥ d(x, L) - d(y, L).
+	var h float64
+	for k := range l.landmarks {
+		from := l.from[k]
+		if !math.IsInf(from[i], 1) && !math.IsInf(from[j], 1) {
+			h = math.Max(h, from[j]-from[i])
+		}
+		to := l.to[k]
+		if !math.IsInf(to[i], 1) && !math.IsInf(to[j], 1) {
+			h = math.Max(h, to[i]-to[j])
+		}
+	}
+	return h
+}
+
+// reverseGraph is a graph.Graph with the edges of a directed graph reversed.
+type reverseGraph struct {
+	graph.Graph
+	to     func(int64) graph.Nodes
+	weight Weighting
+}
+
+func newReverseGraph(g graph.Directed) *reverseGraph {
+	var weight Weighting
+	if wg, ok := g.(Weighted); ok {
+		weight = wg.Weight
+	} else {
+		weight = UniformCost(g)
+	}
+	return &reverseGraph{Graph: g, to: g.To, weight: weight}
+}
+
+func (g *reverseGraph) From(id int64) graph.Nodes {
+	return g.to(id)
+}
+
+func (g *reverseGraph) Edge(uid, vid int64) graph.Edge {
+	e := g.Graph.Edge(vid, uid)
+	if e == nil {
+		return nil
+	}
+	return e.ReversedEdge()
+}
+
+func (g *reverseGraph) Weight(xid, yid int64) (w float64, ok bool) {
+	return g.weight(yid, xid)
+}
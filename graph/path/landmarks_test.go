@@ -0,0 +1,66 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package path
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+func TestLandmarksAdmissible(t *testing.T) {
+	t.Parallel()
+	for _, directed := range []bool{true, false} {
+		for seed := uint64(1); seed <= 5; seed++ {
+			g := randomWeightedGraph(40, 120, directed, rand.NewSource(seed))
+			lm := NewLandmarks(g, 3)
+			if len(lm.Nodes()) != 3 {
+				t.Errorf("unexpected number of landmarks for directed=%t seed %d: got:%d want:3",
+					directed, seed, len(lm.Nodes()))
+			}
+			paths := DijkstraAllPaths(g)
+			nodes := g.Nodes()
+			for nodes.Next() {
+				x := nodes.Node()
+				others := g.Nodes()
+				for others.Next() {
+					y := others.Node()
+					h := lm.HeuristicCost(x, y)
+					if h < 0 {
+						t.Errorf("negative heuristic from %d to %d: %v", x.ID(), y.ID(), h)
+					}
+					if d := paths.Weight(x.ID(), y.ID()); h > d {
+						t.Errorf("inadmissible heuristic from %d to %d for directed=%t seed %d: %v > %v",
+							x.ID(), y.ID(), directed, seed, h, d)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestLandmarksAStar(t *testing.T) {
+	t.Parallel()
+	g := randomWeightedGraph(200, 800, true, rand.NewSource(1))
+	lm := NewLandmarks(g, 8)
+	var expandedALT, expandedNull int
+	for sid := int64(0); sid < 200; sid += 13 {
+		want := DijkstraFrom(simple.Node(sid), g)
+		for tid := int64(0); tid < 200; tid += 11 {
+			got, n := AStar(simple.Node(sid), simple.Node(tid), g, lm.HeuristicCost)
+			expandedALT += n
+			checkRandomPath(t, "ALT", g, got, sid, tid, want.WeightTo(tid))
+
+			_, n = AStar(simple.Node(sid), simple.Node(tid), g, NullHeuristic)
+			expandedNull += n
+		}
+	}
+	if expandedALT >= expandedNull {
+		t.Errorf("landmark heuristic did not reduce search: ALT expanded %d, null heuristic expanded %d",
+			expandedALT, expandedNull)
+	}
+}
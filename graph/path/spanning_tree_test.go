@@ -8,8 +8,11 @@ import (
 	"math"
 	"testing"
 
+	"golang.org/x/exp/rand"
+
 	"gonum.org/v1/gonum/graph"
 	"gonum.org/v1/gonum/graph/simple"
+	"gonum.org/v1/gonum/graph/topo"
 )
 
 func TestVerifySpanningTreeTests(t *testing.T) {
@@ -294,3 +297,28 @@ func TestPrim(t *testing.T) {
 		return Prim(dst, g)
 	}, t)
 }
+
+func TestBoruvka(t *testing.T) {
+	t.Parallel()
+	testMinumumSpanning(func(dst WeightedBuilder, g spanningGraph) float64 {
+		return Boruvka(dst, g)
+	}, t)
+}
+
+func TestBoruvkaRandom(t *testing.T) {
+	t.Parallel()
+	for seed := uint64(1); seed <= 10; seed++ {
+		g := randomWeightedGraph(100, 300, false, rand.NewSource(seed)).(*simple.WeightedUndirectedGraph)
+
+		want := Kruskal(simple.NewWeightedUndirectedGraph(0, math.Inf(1)), g)
+		dst := simple.NewWeightedUndirectedGraph(0, math.Inf(1))
+		got := Boruvka(dst, g)
+		if got != want {
+			t.Errorf("unexpected minimum spanning forest weight for seed %d: got:%v want:%v", seed, got, want)
+		}
+		if n, e := dst.Nodes().Len(), dst.Edges().Len(); e != n-len(topo.ConnectedComponents(g)) {
+			t.Errorf("unexpected number of edges in spanning forest for seed %d: got:%d want:%d",
+				seed, e, n-len(topo.ConnectedComponents(g)))
+		}
+	}
+}
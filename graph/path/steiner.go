@@ -0,0 +1,282 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package path
+
+import (
+	"container/heap"
+	"math"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/set"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// SteinerKMB generates an approximate minimum Steiner tree of g connecting
+// the terminal nodes using the algorithm of Kou, Markowsky and Berman
+// doi:10.1007/BF00288961, placing the result in the destination, dst. The
+// destination is not cleared first. The weight of the Steiner tree is
+// returned. The weight of the tree is within a factor of 2(1-1/l) of the
+// minimum, where l is the number of leaves in the minimum Steiner tree.
+//
+// The tree is constructed from a minimum spanning tree of the complete graph
+// of shortest path distances between terminals. If the terminals are not
+// all connected in g, a Steiner forest will be constructed in dst and the
+// sum of the Steiner tree weights will be returned.
+//
+// Nodes and Edges from g are used to construct dst, so if the Node and Edge
+// types used in g are pointer or reference-like, then the values will be shared
+// between the graphs. Only nodes in the Steiner tree are added to dst.
+//
+// If dst has nodes that exist in the Steiner tree, SteinerKMB will panic.
+// SteinerKMB will also panic if a terminal is not in g or g has a negative
+// edge weight.
+//
+// The time complexity of SteinerKMB is O(|T|.|E|.log|V|) where T is the set
+// of terminals.
+func SteinerKMB(dst WeightedBuilder, g graph.WeightedUndirected, terminals []graph.Node) float64 {
+	terms := steinerTerminals(g, terminals)
+
+	// Find the minimum spanning tree of the
+	// complete graph of terminal distances.
+	closure := simple.NewWeightedUndirectedGraph(0, math.Inf(1))
+	paths := make([]Shortest, len(terms))
+	for i, u := range terms {
+		closure.AddNode(u)
+		paths[i] = DijkstraFrom(u, g)
+		for _, v := range terms[:i] {
+			w := paths[i].WeightTo(v.ID())
+			if !math.IsInf(w, 1) {
+				closure.SetWeightedEdge(simple.WeightedEdge{F: u, T: v, W: w})
+			}
+		}
+	}
+	mst := simple.NewWeightedUndirectedGraph(0, math.Inf(1))
+	Kruskal(mst, closure)
+
+	// Expand the edges of the minimum spanning
+	// tree into their shortest paths in g.
+	indexOf := make(map[int64]int, len(terms))
+	for i, u := range terms {
+		indexOf[u.ID()] = i
+	}
+	sub := simple.NewWeightedUndirectedGraph(0, math.Inf(1))
+	edges := mst.Edges()
+	for edges.Next() {
+		e := edges.Edge()
+		p, _ := paths[indexOf[e.From().ID()]].To(e.To().ID())
+		for i := 1; i < len(p); i++ {
+			addSteinerEdge(sub, g, p[i-1], p[i])
+		}
+	}
+	return steinerTree(dst, g, sub, terms)
+}
+
+// SteinerMehlhorn generates an approximate minimum Steiner tree of g
+// connecting the terminal nodes using the algorithm of Mehlhorn
+// doi:10.1016/0020-0190(88)90066-X, placing the result in the destination,
+// dst. The destination is not cleared first. The weight of the Steiner tree
+// is returned. The weight of the tree is within a factor of 2(1-1/l) of the
+// minimum, where l is the number of leaves in the minimum Steiner tree.
+//
+// The tree is constructed from a minimum spanning tree of the graph of
+// terminals connected by the edges of g that join the Voronoi regions of the
+// terminals. This requires only a single shortest path search, so
+// SteinerMehlhorn is faster than SteinerKMB when there are many terminals.
+// If the terminals are not all connected in g, a Steiner forest will be
+// constructed in dst and the sum of the Steiner tree weights will be
+// returned.
+//
+// Nodes and Edges from g are used to construct dst, so if the Node and Edge
+// types used in g are pointer or reference-like, then the values will be shared
+// between the graphs. Only nodes in the Steiner tree are added to dst.
+//
+// If dst has nodes that exist in the Steiner tree, SteinerMehlhorn will panic.
+// SteinerMehlhorn will also panic if a terminal is not in g or g has a
+// negative edge weight.
+//
+// The time complexity of SteinerMehlhorn is O(|E|.log|V|).
+func SteinerMehlhorn(dst WeightedBuilder, g graph.WeightedUndirected, terminals []graph.Node) float64 {
+	terms := steinerTerminals(g, terminals)
+
+	// Partition the nodes of g into the Voronoi
+	// regions of the terminals with a single
+	// multiple source Dijkstra search.
+	dist := make(map[int64]float64)
+	source := make(map[int64]graph.Node)
+	parent := make(map[int64]graph.Node)
+	var q priorityQueue
+	for _, u := range terms {
+		dist[u.ID()] = 0
+		source[u.ID()] = u
+		q = append(q, distanceNode{node: u})
+	}
+	heap.Init(&q)
+	for q.Len() != 0 {
+		mid := heap.Pop(&q).(distanceNode)
+		mnid := mid.node.ID()
+		if mid.dist > dist[mnid] {
+			continue
+		}
+		to := g.From(mnid)
+		for to.Next() {
+			v := to.Node()
+			vid := v.ID()
+			w, ok := g.Weight(mnid, vid)
+			if !ok {
+				panic("steiner: unexpected invalid weight")
+			}
+			if w < 0 {
+				panic("steiner: negative edge weight")
+			}
+			joint := mid.dist + w
+			if d, ok := dist[vid]; !ok || joint < d {
+				dist[vid] = joint
+				source[vid] = source[mnid]
+				parent[vid] = mid.node
+				heap.Push(&q, distanceNode{node: v, dist: joint})
+			}
+		}
+	}
+
+	// Find the lightest edge of g joining each
+	// pair of adjacent Voronoi regions.
+	type bridge struct {
+		u, v   graph.Node
+		weight float64
+	}
+	bridges := make(map[[2]int64]bridge)
+	nodes := g.Nodes()
+	for nodes.Next() {
+		u := nodes.Node()
+		uid := u.ID()
+		su, ok := source[uid]
+		if !ok {
+			continue
+		}
+		to := g.From(uid)
+		for to.Next() {
+			v := to.Node()
+			vid := v.ID()
+			sv := source[vid]
+			if su.ID() >= sv.ID() {
+				continue
+			}
+			w, _ := g.Weight(uid, vid)
+			w += dist[uid] + dist[vid]
+			key := [2]int64{su.ID(), sv.ID()}
+			if b, ok := bridges[key]; !ok || w < b.weight {
+				bridges[key] = bridge{u: u, v: v, weight: w}
+			}
+		}
+	}
+
+	// Find the minimum spanning tree of the
+	// graph of terminals joined by bridges and
+	// expand its edges into paths in g.
+	closure := simple.NewWeightedUndirectedGraph(0, math.Inf(1))
+	for _, u := range terms {
+		closure.AddNode(u)
+	}
+	for key, b := range bridges {
+		closure.SetWeightedEdge(simple.WeightedEdge{F: closure.Node(key[0]), T: closure.Node(key[1]), W: b.weight})
+	}
+	mst := simple.NewWeightedUndirectedGraph(0, math.Inf(1))
+	Kruskal(mst, closure)
+
+	sub := simple.NewWeightedUndirectedGraph(0, math.Inf(1))
+	edges := mst.Edges()
+	for edges.Next() {
+		e := edges.Edge()
+		uid, vid := e.From().ID(), e.To().ID()
+		if uid > vid {
+			uid, vid = vid, uid
+		}
+		b := bridges[[2]int64{uid, vid}]
+		addSteinerEdge(sub, g, b.u, b.v)
+		for _, n := range []graph.Node{b.u, b.v} {
+			for p := parent[n.ID()]; p != nil; n, p = p, parent[p.ID()] {
+				addSteinerEdge(sub, g, p, n)
+			}
+		}
+	}
+	return steinerTree(dst, g, sub, terms)
+}
+
+// steinerTerminals returns the unique nodes of g corresponding to
+// the terminals.
+func steinerTerminals(g graph.Graph, terminals []graph.Node) []graph.Node {
+	seen := make(set.Int64s)
+	var terms []graph.Node
+	for _, u := range terminals {
+		n := g.Node(u.ID())
+		if n == nil {
+			panic("steiner: terminal not in graph")
+		}
+		if seen.Has(u.ID()) {
+			continue
+		}
+		seen.Add(u.ID())
+		terms = append(terms, n)
+	}
+	return terms
+}
+
+// addSteinerEdge adds the edge between u and v in g to dst.
+func addSteinerEdge(dst *simple.WeightedUndirectedGraph, g graph.WeightedUndirected, u, v graph.Node) {
+	w, ok := g.Weight(u.ID(), v.ID())
+	if !ok {
+		panic("steiner: unexpected invalid weight")
+	}
+	dst.SetWeightedEdge(simple.WeightedEdge{F: u, T: v, W: w})
+}
+
+// steinerTree places the minimum spanning tree of sub with non-terminal
+// leaves repeatedly removed into dst and returns its weight.
+func steinerTree(dst WeightedBuilder, g graph.WeightedUndirected, sub *simple.WeightedUndirectedGraph, terms []graph.Node) float64 {
+	tree := simple.NewWeightedUndirectedGraph(0, math.Inf(1))
+	Kruskal(tree, sub)
+	for _, u := range terms {
+		if tree.Node(u.ID()) == nil {
+			tree.AddNode(u)
+		}
+	}
+
+	isTerminal := make(set.Int64s)
+	for _, u := range terms {
+		isTerminal.Add(u.ID())
+	}
+	var leaves []graph.Node
+	nodes := tree.Nodes()
+	for nodes.Next() {
+		u := nodes.Node()
+		if !isTerminal.Has(u.ID()) && tree.From(u.ID()).Len() <= 1 {
+			leaves = append(leaves, u)
+		}
+	}
+	for len(leaves) != 0 {
+		u := leaves[len(leaves)-1]
+		leaves = leaves[:len(leaves)-1]
+		to := graph.NodesOf(tree.From(u.ID()))
+		tree.RemoveNode(u.ID())
+		for _, v := range to {
+			if !isTerminal.Has(v.ID()) && tree.From(v.ID()).Len() <= 1 {
+				leaves = append(leaves, v)
+			}
+		}
+	}
+
+	nodes = tree.Nodes()
+	for nodes.Next() {
+		dst.AddNode(nodes.Node())
+	}
+	var w float64
+	edges := tree.WeightedEdges()
+	for edges.Next() {
+		e := edges.WeightedEdge()
+		dst.SetWeightedEdge(g.WeightedEdge(e.From().ID(), e.To().ID()))
+		w += e.Weight()
+	}
+	return w
+}
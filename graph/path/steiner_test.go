@@ -0,0 +1,214 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package path
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+	"gonum.org/v1/gonum/graph/topo"
+)
+
+var steinerFuncs = []struct {
+	name string
+	fn   func(dst WeightedBuilder, g graph.WeightedUndirected, terminals []graph.Node) float64
+}{
+	{name: "KMB", fn: SteinerKMB},
+	{name: "Mehlhorn", fn: SteinerMehlhorn},
+}
+
+var steinerTests = []struct {
+	name      string
+	edges     []simple.WeightedEdge
+	terminals []int64
+	want      float64
+}{
+	{
+		name: "single terminal",
+		edges: []simple.WeightedEdge{
+			{F: simple.Node(0), T: simple.Node(1), W: 1},
+		},
+		terminals: []int64{0},
+		want:      0,
+	},
+	{
+		// A star with a Steiner node at its centre.
+		name: "star",
+		edges: []simple.WeightedEdge{
+			{F: simple.Node(0), T: simple.Node(3), W: 1},
+			{F: simple.Node(1), T: simple.Node(3), W: 1},
+			{F: simple.Node(2), T: simple.Node(3), W: 1},
+			{F: simple.Node(0), T: simple.Node(1), W: 3},
+			{F: simple.Node(1), T: simple.Node(2), W: 3},
+		},
+		terminals: []int64{0, 1, 2},
+		want:      3,
+	},
+	{
+		// Non-terminal leaves are pruned.
+		name: "path with spur",
+		edges: []simple.WeightedEdge{
+			{F: simple.Node(0), T: simple.Node(1), W: 1},
+			{F: simple.Node(1), T: simple.Node(2), W: 1},
+			{F: simple.Node(1), T: simple.Node(3), W: 1},
+			{F: simple.Node(3), T: simple.Node(4), W: 1},
+		},
+		terminals: []int64{0, 2},
+		want:      2,
+	},
+	{
+		name: "disconnected terminals",
+		edges: []simple.WeightedEdge{
+			{F: simple.Node(0), T: simple.Node(1), W: 1},
+			{F: simple.Node(1), T: simple.Node(2), W: 1},
+			{F: simple.Node(3), T: simple.Node(4), W: 2},
+			{F: simple.Node(4), T: simple.Node(5), W: 2},
+		},
+		terminals: []int64{0, 2, 3, 5},
+		want:      6,
+	},
+}
+
+func TestSteiner(t *testing.T) {
+	t.Parallel()
+	for _, fn := range steinerFuncs {
+		for _, test := range steinerTests {
+			g := simple.NewWeightedUndirectedGraph(0, math.Inf(1))
+			for _, e := range test.edges {
+				g.SetWeightedEdge(e)
+			}
+			var terminals []graph.Node
+			for _, id := range test.terminals {
+				terminals = append(terminals, simple.Node(id))
+			}
+			dst := simple.NewWeightedUndirectedGraph(0, math.Inf(1))
+			w := fn.fn(dst, g, terminals)
+			if w != test.want {
+				t.Errorf("unexpected %s Steiner tree weight for %q: got:%v want:%v", fn.name, test.name, w, test.want)
+			}
+			checkSteinerTree(t, fn.name+" "+test.name, g, dst, terminals, w)
+		}
+	}
+}
+
+func TestSteinerRandom(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 50; i++ {
+		n := 4 + rnd.Intn(6)
+		g := randomWeightedGraph(n, 3*n, false, rnd).(*simple.WeightedUndirectedGraph)
+		perm := rnd.Perm(n)
+		var terminals []graph.Node
+		for _, id := range perm[:2+rnd.Intn(n-2)] {
+			terminals = append(terminals, simple.Node(id))
+		}
+		opt := bruteForceSteiner(g, terminals)
+		for _, fn := range steinerFuncs {
+			dst := simple.NewWeightedUndirectedGraph(0, math.Inf(1))
+			w := fn.fn(dst, g, terminals)
+			checkSteinerTree(t, fn.name, g, dst, terminals, w)
+			if w < opt || w > 2*opt {
+				t.Errorf("unexpected %s Steiner tree weight for test %d: got:%v want in [%v,%v]", fn.name, i, w, opt, 2*opt)
+			}
+		}
+	}
+}
+
+// checkSteinerTree checks that dst is a forest of edges of g containing
+// the terminals, with only terminal leaves, that has the given weight and
+// in which terminals are connected if they are connected in g.
+func checkSteinerTree(t *testing.T, name string, g graph.WeightedUndirected, dst *simple.WeightedUndirectedGraph, terminals []graph.Node, weight float64) {
+	t.Helper()
+	isTerminal := make(map[int64]bool)
+	for _, u := range terminals {
+		isTerminal[u.ID()] = true
+		if dst.Node(u.ID()) == nil {
+			t.Errorf("terminal %d not in Steiner tree for %q", u.ID(), name)
+		}
+	}
+	nodes := dst.Nodes()
+	for nodes.Next() {
+		u := nodes.Node()
+		if !isTerminal[u.ID()] && dst.From(u.ID()).Len() < 2 {
+			t.Errorf("non-terminal leaf %d in Steiner tree for %q", u.ID(), name)
+		}
+	}
+	var w float64
+	edges := dst.WeightedEdges()
+	for edges.Next() {
+		e := edges.WeightedEdge()
+		ew, ok := g.Weight(e.From().ID(), e.To().ID())
+		if !ok {
+			t.Errorf("edge %d--%d not in graph for %q", e.From().ID(), e.To().ID(), name)
+		}
+		w += ew
+	}
+	if w != weight {
+		t.Errorf("unexpected Steiner tree edge weight sum for %q: got:%v want:%v", name, w, weight)
+	}
+	cc := topo.ConnectedComponents(dst)
+	if dst.Edges().Len() != dst.Nodes().Len()-len(cc) {
+		t.Errorf("Steiner tree is not a forest for %q", name)
+	}
+	paths := DijkstraAllPaths(g)
+	for _, c := range cc {
+		for _, u := range terminals {
+			if !topo.PathExistsIn(dst, c[0], u) && !math.IsInf(paths.Weight(c[0].ID(), u.ID()), 1) {
+				t.Errorf("terminal %d not connected to %d in Steiner tree for %q", u.ID(), c[0].ID(), name)
+			}
+		}
+	}
+}
+
+// bruteForceSteiner returns the weight of the minimum Steiner tree of g
+// connecting the terminals by finding the minimum spanning tree of every
+// node subset containing the terminals.
+func bruteForceSteiner(g *simple.WeightedUndirectedGraph, terminals []graph.Node) float64 {
+	isTerminal := make(map[int64]bool)
+	for _, u := range terminals {
+		isTerminal[u.ID()] = true
+	}
+	var others []graph.Node
+	nodes := graph.NodesOf(g.Nodes())
+	for _, u := range nodes {
+		if !isTerminal[u.ID()] {
+			others = append(others, u)
+		}
+	}
+
+	best := math.Inf(1)
+	for mask := 0; mask < 1<<uint(len(others)); mask++ {
+		sub := simple.NewWeightedUndirectedGraph(0, math.Inf(1))
+		in := make(map[int64]bool)
+		for _, u := range terminals {
+			sub.AddNode(u)
+			in[u.ID()] = true
+		}
+		for i, u := range others {
+			if mask&(1<<uint(i)) != 0 {
+				sub.AddNode(u)
+				in[u.ID()] = true
+			}
+		}
+		edges := g.WeightedEdges()
+		for edges.Next() {
+			e := edges.WeightedEdge()
+			if in[e.From().ID()] && in[e.To().ID()] {
+				sub.SetWeightedEdge(e)
+			}
+		}
+		if len(topo.ConnectedComponents(sub)) != 1 {
+			continue
+		}
+		if w := Kruskal(simple.NewWeightedUndirectedGraph(0, math.Inf(1)), sub); w < best {
+			best = w
+		}
+	}
+	return best
+}
@@ -0,0 +1,255 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package topo
+
+import (
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+	"gonum.org/v1/gonum/graph/internal/set"
+)
+
+// ArticulationPoints returns the articulation points of the undirected graph
+// g, the nodes whose removal increases the number of connected components of
+// g. The returned nodes are ordered by ID.
+func ArticulationPoints(g graph.Undirected) []graph.Node {
+	return biconnected(g).cuts
+}
+
+// Bridges returns the bridges of the undirected graph g, the edges whose
+// removal increases the number of connected components of g. Each bridge is
+// the edge returned by g.Edge from the end point with the lower ID, and the
+// bridges are ordered by the IDs of their end points.
+func Bridges(g graph.Undirected) []graph.Edge {
+	b := biconnected(g).bridges
+	if len(b) == 0 {
+		return nil
+	}
+	bridges := make([]graph.Edge, len(b))
+	for i, e := range b {
+		bridges[i] = g.Edge(e[0].ID(), e[1].ID())
+	}
+	return bridges
+}
+
+// BiconnectedComponents returns the biconnected components, or blocks, of the
+// undirected graph g. The blocks are the maximal subgraphs of g that have no
+// articulation points. Every edge of g belongs to exactly one block; a bridge
+// forms a block of two nodes and an isolated node forms a block on its own.
+// Articulation points belong to more than one block. Self loops are ignored.
+//
+// The nodes of each block are ordered by ID and the blocks are ordered by the
+// IDs of their nodes.
+func BiconnectedComponents(g graph.Undirected) [][]graph.Node {
+	return biconnected(g).blocks
+}
+
+// BlockCutTree builds the block-cut tree of the undirected graph g in dst
+// using BlockCutNode nodes and BlockCutEdge edges. Each block of g, as
+// returned by BiconnectedComponents, and each articulation point of g is
+// represented by a node in dst. An edge joins each articulation point to
+// each block containing it. The blocks are given IDs from zero in the order
+// returned by BiconnectedComponents, followed by the articulation points in
+// ID order. The dst graph is not cleared.
+//
+// The block-cut tree of a connected graph is a tree. For a disconnected graph
+// it is a forest with one tree for each connected component.
+func BlockCutTree(dst Builder, g graph.Undirected) {
+	bc := biconnected(g)
+
+	cuts := make(map[int64]BlockCutNode, len(bc.cuts))
+	for i, n := range bc.cuts {
+		c := BlockCutNode{id: int64(len(bc.blocks) + i), nodes: []graph.Node{n}, cut: true}
+		cuts[n.ID()] = c
+		dst.AddNode(c)
+	}
+	for i, blk := range bc.blocks {
+		b := BlockCutNode{id: int64(i), nodes: blk}
+		dst.AddNode(b)
+		for _, n := range blk {
+			c, ok := cuts[n.ID()]
+			if !ok {
+				continue
+			}
+			dst.SetEdge(BlockCutEdge{from: b, to: c})
+		}
+	}
+}
+
+// BlockCutNode is a node in a block-cut tree. It represents either a block
+// or an articulation point of the underlying graph.
+type BlockCutNode struct {
+	id    int64
+	nodes []graph.Node
+	cut   bool
+}
+
+// ID returns the node ID.
+func (n BlockCutNode) ID() int64 { return n.id }
+
+// Nodes returns the nodes of the underlying graph in the block, or the
+// articulation point if the node represents an articulation point.
+func (n BlockCutNode) Nodes() []graph.Node { return n.nodes }
+
+// IsCut returns whether the node represents an articulation point.
+func (n BlockCutNode) IsCut() bool { return n.cut }
+
+// BlockCutEdge is an edge in a block-cut tree.
+type BlockCutEdge struct {
+	from, to BlockCutNode
+}
+
+// From returns the from node of the edge.
+func (e BlockCutEdge) From() graph.Node { return e.from }
+
+// To returns the to node of the edge.
+func (e BlockCutEdge) To() graph.Node { return e.to }
+
+// ReversedEdge returns a new BlockCutEdge with
+// the edge end points swapped.
+func (e BlockCutEdge) ReversedEdge() graph.Edge { e.from, e.to = e.to, e.from; return e }
+
+// biconnectivity holds the biconnected structure of an undirected graph.
+type biconnectivity struct {
+	blocks  [][]graph.Node
+	cuts    []graph.Node
+	bridges [][2]graph.Node
+}
+
+// dfsFrame is a depth-first search stack frame for biconnected.
+type dfsFrame struct {
+	u        graph.Node
+	nbrs     graph.Nodes
+	children int
+}
+
+// biconnected returns the blocks, articulation points and bridges of g
+// using the algorithm of Hopcroft and Tarjan, "Algorithm 447: Efficient
+// algorithms for graph manipulation", Communications of the ACM 16 (1973)
+// 372-378. The depth-first search is performed iteratively so that deep
+// graphs do not exhaust the goroutine stack.
+func biconnected(g graph.Undirected) biconnectivity {
+	nodes := graph.NodesOf(g.Nodes())
+	sort.Sort(ordered.ByID(nodes))
+
+	var (
+		bc    biconnectivity
+		disc  = make(map[int64]int, len(nodes))
+		low   = make(map[int64]int, len(nodes))
+		cuts  = make(set.Int64s)
+		time  int
+		stack []dfsFrame
+		edges [][2]graph.Node
+	)
+	for _, root := range nodes {
+		rid := root.ID()
+		if _, ok := disc[rid]; ok {
+			continue
+		}
+		disc[rid] = time
+		low[rid] = time
+		time++
+		stack = append(stack[:0], dfsFrame{u: root, nbrs: g.From(rid)})
+		isolated := true
+		for len(stack) != 0 {
+			f := &stack[len(stack)-1]
+			uid := f.u.ID()
+			if f.nbrs.Next() {
+				v := f.nbrs.Node()
+				vid := v.ID()
+				if vid == uid {
+					// Ignore self loops.
+					continue
+				}
+				isolated = false
+				dv, seen := disc[vid]
+				switch {
+				case !seen:
+					disc[vid] = time
+					low[vid] = time
+					time++
+					f.children++
+					edges = append(edges, [2]graph.Node{f.u, v})
+					stack = append(stack, dfsFrame{u: v, nbrs: g.From(vid)})
+				case dv < disc[uid] && (len(stack) < 2 || vid != stack[len(stack)-2].u.ID()):
+					// Back edge to an ancestor other than the parent.
+					if dv < low[uid] {
+						low[uid] = dv
+					}
+					edges = append(edges, [2]graph.Node{f.u, v})
+				}
+				continue
+			}
+
+			// All neighbours of u have been visited.
+			u := *f
+			stack = stack[:len(stack)-1]
+			if len(stack) == 0 {
+				if u.children > 1 {
+					cuts.Add(uid)
+				}
+				break
+			}
+			p := stack[len(stack)-1].u
+			pid := p.ID()
+			if low[uid] < low[pid] {
+				low[pid] = low[uid]
+			}
+			if low[uid] > disc[pid] {
+				bc.bridges = append(bc.bridges, ordered2(p, u.u))
+			}
+			if low[uid] >= disc[pid] {
+				// p separates the subtree rooted at u, so the
+				// edges on the stack down to (p, u) form a block.
+				if len(stack) > 1 {
+					cuts.Add(pid)
+				}
+				blk := set.NewNodes()
+				for {
+					e := edges[len(edges)-1]
+					edges = edges[:len(edges)-1]
+					blk.Add(e[0])
+					blk.Add(e[1])
+					if e[0].ID() == pid && e[1].ID() == uid {
+						break
+					}
+				}
+				block := make([]graph.Node, 0, len(blk))
+				for _, n := range blk {
+					block = append(block, n)
+				}
+				sort.Sort(ordered.ByID(block))
+				bc.blocks = append(bc.blocks, block)
+			}
+		}
+		if isolated {
+			bc.blocks = append(bc.blocks, []graph.Node{root})
+		}
+	}
+
+	sort.Sort(ordered.BySliceIDs(bc.blocks))
+	for _, n := range nodes {
+		if cuts.Has(n.ID()) {
+			bc.cuts = append(bc.cuts, n)
+		}
+	}
+	sort.Slice(bc.bridges, func(i, j int) bool {
+		a, b := bc.bridges[i], bc.bridges[j]
+		if a[0].ID() != b[0].ID() {
+			return a[0].ID() < b[0].ID()
+		}
+		return a[1].ID() < b[1].ID()
+	})
+	return bc
+}
+
+// ordered2 returns u and v ordered by ID.
+func ordered2(u, v graph.Node) [2]graph.Node {
+	if v.ID() < u.ID() {
+		return [2]graph.Node{v, u}
+	}
+	return [2]graph.Node{u, v}
+}
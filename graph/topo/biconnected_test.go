@@ -0,0 +1,287 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package topo
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+var biconnectedTests = []struct {
+	name string
+	g    []intset
+
+	wantCuts    []int64
+	wantBridges [][2]int64
+	wantBlocks  [][]int64
+}{
+	{
+		name: "empty",
+	},
+	{
+		name:       "isolated",
+		g:          []intset{0: nil, 1: nil},
+		wantBlocks: [][]int64{{0}, {1}},
+	},
+	{
+		name: "path",
+		g: []intset{
+			0: linksTo(1),
+			1: linksTo(2),
+			2: nil,
+		},
+		wantCuts:    []int64{1},
+		wantBridges: [][2]int64{{0, 1}, {1, 2}},
+		wantBlocks:  [][]int64{{0, 1}, {1, 2}},
+	},
+	{
+		name: "cycle",
+		g: []intset{
+			0: linksTo(1),
+			1: linksTo(2),
+			2: linksTo(3),
+			3: linksTo(0),
+		},
+		wantBlocks: [][]int64{{0, 1, 2, 3}},
+	},
+	{
+		name: "triangles",
+		g: []intset{
+			0:  linksTo(1, 2),
+			1:  linksTo(2),
+			2:  linksTo(3),
+			3:  linksTo(4, 5),
+			4:  linksTo(5),
+			5:  linksTo(6),
+			6:  nil,
+			7:  nil,
+			8:  linksTo(9),
+			9:  linksTo(10, 11),
+			10: linksTo(11),
+			11: nil,
+		},
+		wantCuts:    []int64{2, 3, 5, 9},
+		wantBridges: [][2]int64{{2, 3}, {5, 6}, {8, 9}},
+		wantBlocks: [][]int64{
+			{0, 1, 2},
+			{2, 3},
+			{3, 4, 5},
+			{5, 6},
+			{7},
+			{8, 9},
+			{9, 10, 11},
+		},
+	},
+	{
+		name: "bowtie",
+		g: []intset{
+			0: linksTo(1, 2),
+			1: linksTo(2),
+			2: linksTo(3, 4),
+			3: linksTo(4),
+			4: nil,
+		},
+		wantCuts:   []int64{2},
+		wantBlocks: [][]int64{{0, 1, 2}, {2, 3, 4}},
+	},
+	{
+		name:        "batagelj-zaversnik",
+		g:           batageljZaversnikGraph,
+		wantCuts:    []int64{4, 11, 15},
+		wantBridges: [][2]int64{{4, 5}, {9, 11}, {10, 11}, {15, 16}},
+		wantBlocks: [][]int64{
+			{0},
+			{1, 2, 3, 4},
+			{4, 5},
+			{6, 7, 8, 11, 12, 13, 14, 15, 17, 18, 19, 20},
+			{9, 11},
+			{10, 11},
+			{15, 16},
+		},
+	},
+}
+
+func TestBiconnected(t *testing.T) {
+	for _, test := range biconnectedTests {
+		g := undirectedFrom(test.g)
+
+		gotCuts := ids(ArticulationPoints(g))
+		if !reflect.DeepEqual(gotCuts, test.wantCuts) {
+			t.Errorf("unexpected articulation points for %q: got:%v want:%v", test.name, gotCuts, test.wantCuts)
+		}
+
+		var gotBridges [][2]int64
+		for _, e := range Bridges(g) {
+			gotBridges = append(gotBridges, [2]int64{e.From().ID(), e.To().ID()})
+		}
+		if !reflect.DeepEqual(gotBridges, test.wantBridges) {
+			t.Errorf("unexpected bridges for %q: got:%v want:%v", test.name, gotBridges, test.wantBridges)
+		}
+
+		var gotBlocks [][]int64
+		for _, b := range BiconnectedComponents(g) {
+			gotBlocks = append(gotBlocks, ids(b))
+		}
+		if !reflect.DeepEqual(gotBlocks, test.wantBlocks) {
+			t.Errorf("unexpected blocks for %q:\ngot: %v\nwant:%v", test.name, gotBlocks, test.wantBlocks)
+		}
+
+		bct := simple.NewUndirectedGraph()
+		BlockCutTree(bct, g)
+		if n := bct.Nodes().Len(); n != len(test.wantBlocks)+len(test.wantCuts) {
+			t.Errorf("unexpected number of block-cut tree nodes for %q: got:%d want:%d",
+				test.name, n, len(test.wantBlocks)+len(test.wantCuts))
+		}
+		// A forest has one fewer edge than nodes in each tree.
+		trees := len(ConnectedComponents(bct))
+		if e := bct.Edges().Len(); e != bct.Nodes().Len()-trees {
+			t.Errorf("block-cut tree for %q is not a forest", test.name)
+		}
+		edges := bct.Edges()
+		for edges.Next() {
+			e := edges.Edge()
+			u := e.From().(BlockCutNode)
+			v := e.To().(BlockCutNode)
+			if u.IsCut() == v.IsCut() {
+				t.Errorf("block-cut tree edge for %q does not join a block and a cut node", test.name)
+				continue
+			}
+			if u.IsCut() {
+				u, v = v, u
+			}
+			if !containsID(u.Nodes(), v.Nodes()[0].ID()) {
+				t.Errorf("cut node %d not in joined block %v for %q", v.Nodes()[0].ID(), ids(u.Nodes()), test.name)
+			}
+		}
+	}
+}
+
+func TestBiconnectedRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 50; trial++ {
+		g := randomUndirected(12, 0.2, rnd)
+		base := len(ConnectedComponents(g))
+
+		var wantCuts []int64
+		for _, n := range graph.NodesOf(g.Nodes()) {
+			h := simple.NewUndirectedGraph()
+			graph.Copy(h, g)
+			h.RemoveNode(n.ID())
+			if len(ConnectedComponents(h)) > base {
+				wantCuts = append(wantCuts, n.ID())
+			}
+		}
+		sort.Sort(ordered.Int64s(wantCuts))
+		if got := ids(ArticulationPoints(g)); !reflect.DeepEqual(got, wantCuts) {
+			t.Errorf("unexpected articulation points for trial %d: got:%v want:%v", trial, got, wantCuts)
+		}
+
+		var wantBridges int
+		for _, e := range graph.EdgesOf(g.Edges()) {
+			h := simple.NewUndirectedGraph()
+			graph.Copy(h, g)
+			h.RemoveEdge(e.From().ID(), e.To().ID())
+			if len(ConnectedComponents(h)) > base {
+				wantBridges++
+			}
+		}
+		if got := len(Bridges(g)); got != wantBridges {
+			t.Errorf("unexpected number of bridges for trial %d: got:%d want:%d", trial, got, wantBridges)
+		}
+
+		// Every edge belongs to exactly one block.
+		var pairs int
+		for _, b := range BiconnectedComponents(g) {
+			for i, u := range b {
+				for _, v := range b[i+1:] {
+					if g.HasEdgeBetween(u.ID(), v.ID()) {
+						pairs++
+					}
+				}
+			}
+		}
+		if edges := g.Edges().Len(); pairs != edges {
+			t.Errorf("blocks do not partition edges for trial %d: got:%d edges want:%d", trial, pairs, edges)
+		}
+	}
+}
+
+func TestWeaklyConnectedComponents(t *testing.T) {
+	g := simple.NewDirectedGraph()
+	for _, e := range [][2]int64{{0, 1}, {2, 1}, {3, 4}, {5, 4}, {5, 3}} {
+		g.SetEdge(simple.Edge{F: simple.Node(e[0]), T: simple.Node(e[1])})
+	}
+	g.AddNode(simple.Node(6))
+
+	var got [][]int64
+	for _, c := range WeaklyConnectedComponents(g) {
+		ids := ids(c)
+		sort.Sort(ordered.Int64s(ids))
+		got = append(got, ids)
+	}
+	sort.Sort(ordered.BySliceValues(got))
+	want := [][]int64{{0, 1, 2}, {3, 4, 5}, {6}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected weakly connected components: got:%v want:%v", got, want)
+	}
+}
+
+func undirectedFrom(adj []intset) *simple.UndirectedGraph {
+	g := simple.NewUndirectedGraph()
+	for u, e := range adj {
+		if g.Node(int64(u)) == nil {
+			g.AddNode(simple.Node(u))
+		}
+		for v := range e {
+			if g.Node(v) == nil {
+				g.AddNode(simple.Node(v))
+			}
+			g.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v)})
+		}
+	}
+	return g
+}
+
+func randomUndirected(n int, p float64, rnd *rand.Rand) *simple.UndirectedGraph {
+	g := simple.NewUndirectedGraph()
+	for i := 0; i < n; i++ {
+		g.AddNode(simple.Node(i))
+	}
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			if rnd.Float64() < p {
+				g.SetEdge(simple.Edge{F: simple.Node(i), T: simple.Node(j)})
+			}
+		}
+	}
+	return g
+}
+
+func ids(nodes []graph.Node) []int64 {
+	if len(nodes) == 0 {
+		return nil
+	}
+	ids := make([]int64, len(nodes))
+	for i, n := range nodes {
+		ids[i] = n.ID()
+	}
+	return ids
+}
+
+func containsID(nodes []graph.Node, id int64) bool {
+	for _, n := range nodes {
+		if n.ID() == id {
+			return true
+		}
+	}
+	return false
+}
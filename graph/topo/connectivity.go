@@ -0,0 +1,408 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package topo
+
+import (
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+)
+
+const maxInt = int(^uint(0) >> 1)
+
+// LocalEdgeConnectivity returns the local edge connectivity of s and t in g,
+// the maximum number of edge-disjoint paths from s to t. By Menger's theorem
+// this is the minimum number of edges that must be removed from g to leave no
+// path from s to t. If g is a graph.Directed, paths follow the direction of
+// the edges.
+//
+// LocalEdgeConnectivity will panic if s or t is not in g or if s and t are
+// the same node.
+func LocalEdgeConnectivity(g graph.Graph, s, t graph.Node) int {
+	nodes, idx := indexNodes(g)
+	si, ti := endPoints(idx, s, t)
+	f := edgeNetwork(g, nodes, idx)
+	return f.maxFlow(si, ti, maxInt)
+}
+
+// LocalNodeConnectivity returns the local node connectivity of s and t in g,
+// the maximum number of paths from s to t that share no nodes other than s
+// and t. If s and t are not adjacent, by Menger's theorem this is the minimum
+// number of nodes that must be removed from g to leave no path from s to t.
+// An edge from s to t counts as a single path. If g is a graph.Directed,
+// paths follow the direction of the edges.
+//
+// LocalNodeConnectivity will panic if s or t is not in g or if s and t are
+// the same node.
+func LocalNodeConnectivity(g graph.Graph, s, t graph.Node) int {
+	nodes, idx := indexNodes(g)
+	si, ti := endPoints(idx, s, t)
+	f := nodeNetwork(g, nodes, idx)
+	return f.maxFlow(2*si+1, 2*ti, maxInt)
+}
+
+// EdgeConnectivity returns the edge connectivity of g, the minimum number of
+// edges that must be removed from g to disconnect it. If g is a
+// graph.Directed, the connectivity is with respect to strong connection.
+// EdgeConnectivity returns zero if g has fewer than two nodes.
+func EdgeConnectivity(g graph.Graph) int {
+	nodes, idx := indexNodes(g)
+	if len(nodes) < 2 {
+		return 0
+	}
+	_, directed := g.(graph.Directed)
+	f := edgeNetwork(g, nodes, idx)
+
+	// Any minimum cut separates the first node from some
+	// other node, so only flows involving the first node
+	// need to be considered.
+	k := maxInt
+	for t := 1; t < len(nodes) && k != 0; t++ {
+		f.reset()
+		k = min(k, f.maxFlow(0, t, k))
+		if directed {
+			f.reset()
+			k = min(k, f.maxFlow(t, 0, k))
+		}
+	}
+	return k
+}
+
+// NodeConnectivity returns the node connectivity of g, the minimum number of
+// nodes that must be removed from g to disconnect it or to leave it with a
+// single node. If g is a graph.Directed, the connectivity is with respect to
+// strong connection. The node connectivity of a complete graph on n nodes is
+// n-1. NodeConnectivity returns zero if g has fewer than two nodes.
+//
+// NodeConnectivity uses the algorithm described in Even, "An algorithm for
+// determining whether the connectivity of a graph is at least k", SIAM
+// Journal on Computing 4 (1975) 393-396.
+func NodeConnectivity(g graph.Graph) int {
+	nodes, idx := indexNodes(g)
+	n := len(nodes)
+	if n < 2 {
+		return 0
+	}
+	var (
+		canReach func(uid, vid int64) bool
+		directed bool
+	)
+	switch g := g.(type) {
+	case graph.Directed:
+		canReach = g.HasEdgeFromTo
+		directed = true
+	default:
+		canReach = g.HasEdgeBetween
+	}
+	f := nodeNetwork(g, nodes, idx)
+
+	// A minimum separating set of size k must exclude at
+	// least one of any k+1 nodes, and that node is separated
+	// from some other node by the set.
+	k := n - 1
+	for i := 0; i <= k && i < n; i++ {
+		uid := nodes[i].ID()
+		for j := i + 1; j < n; j++ {
+			vid := nodes[j].ID()
+			if !canReach(uid, vid) {
+				f.reset()
+				k = min(k, f.maxFlow(2*i+1, 2*j, k))
+			}
+			if directed && !canReach(vid, uid) {
+				f.reset()
+				k = min(k, f.maxFlow(2*j+1, 2*i, k))
+			}
+		}
+	}
+	return k
+}
+
+// KEdgeConnectedComponents returns the k-edge-connected components of g, the
+// maximal sets of nodes where the local edge connectivity in g between each
+// pair of nodes in the set is at least k. If g is a graph.Directed, the
+// local edge connectivity must be at least k in both directions. The nodes
+// of each component are ordered by ID and the components are ordered by the
+// ID of their first node.
+//
+// For undirected graphs the components are found from a flow equivalent
+// tree constructed using the algorithm described in Gusfield, "Very simple
+// methods for all pairs network flow analysis", SIAM Journal on Computing
+// 19 (1990) 143-155.
+func KEdgeConnectedComponents(g graph.Graph, k int) [][]graph.Node {
+	nodes, idx := indexNodes(g)
+	n := len(nodes)
+	if n == 0 {
+		return nil
+	}
+	f := edgeNetwork(g, nodes, idx)
+
+	comp := make([]int, n)
+	if _, directed := g.(graph.Directed); directed {
+		// k-edge-connectivity is an equivalence relation,
+		// so each node need only be compared with the
+		// representative of each class.
+		for i := range comp {
+			comp[i] = -1
+		}
+		for r := 0; r < n; r++ {
+			if comp[r] >= 0 {
+				continue
+			}
+			comp[r] = r
+			for w := r + 1; w < n; w++ {
+				if comp[w] >= 0 {
+					continue
+				}
+				f.reset()
+				if f.maxFlow(r, w, k) < k {
+					continue
+				}
+				f.reset()
+				if f.maxFlow(w, r, k) < k {
+					continue
+				}
+				comp[w] = r
+			}
+		}
+	} else {
+		parent := make([]int, n)
+		flow := make([]int, n)
+		for s := 1; s < n; s++ {
+			t := parent[s]
+			f.reset()
+			flow[s] = f.maxFlow(s, t, maxInt)
+			side := f.sourceSide(s)
+			for i := s + 1; i < n; i++ {
+				if side[i] && parent[i] == t {
+					parent[i] = s
+				}
+			}
+		}
+
+		// Nodes are k-edge-connected when the minimum flow on
+		// the path between them in the flow equivalent tree is
+		// at least k. Parents always precede their children.
+		for i := range comp {
+			comp[i] = i
+			if i != 0 && flow[i] >= k {
+				comp[i] = comp[parent[i]]
+			}
+		}
+	}
+
+	byRep := make(map[int]int)
+	var cc [][]graph.Node
+	for i, c := range comp {
+		j, ok := byRep[c]
+		if !ok {
+			j = len(cc)
+			byRep[c] = j
+			cc = append(cc, nil)
+		}
+		cc[j] = append(cc[j], nodes[i])
+	}
+	return cc
+}
+
+// indexNodes returns the nodes of g ordered by ID and a map from node IDs
+// to their index.
+func indexNodes(g graph.Graph) ([]graph.Node, map[int64]int) {
+	nodes := graph.NodesOf(g.Nodes())
+	sort.Sort(ordered.ByID(nodes))
+	idx := make(map[int64]int, len(nodes))
+	for i, n := range nodes {
+		idx[n.ID()] = i
+	}
+	return nodes, idx
+}
+
+// endPoints returns the indices of s and t, panicking if they are not
+// distinct nodes in the index.
+func endPoints(idx map[int64]int, s, t graph.Node) (si, ti int) {
+	si, ok := idx[s.ID()]
+	if !ok {
+		panic("topo: source node not in graph")
+	}
+	ti, ok = idx[t.ID()]
+	if !ok {
+		panic("topo: target node not in graph")
+	}
+	if si == ti {
+		panic("topo: source and target are the same node")
+	}
+	return si, ti
+}
+
+// edgeNetwork returns a flow network with a vertex for each node of g and
+// a unit capacity arc for each edge. Self loops are ignored.
+func edgeNetwork(g graph.Graph, nodes []graph.Node, idx map[int64]int) *flowNetwork {
+	_, directed := g.(graph.Directed)
+	f := newFlowNetwork(len(nodes))
+	for i, u := range nodes {
+		to := g.From(u.ID())
+		for to.Next() {
+			j := idx[to.Node().ID()]
+			switch {
+			case i == j:
+			case directed:
+				f.addArc(i, j, 1, 0)
+			case i < j:
+				f.addArc(i, j, 1, 1)
+			}
+		}
+	}
+	return f
+}
+
+// nodeNetwork returns a flow network where each node i of g is split into
+// an entry vertex 2i and an exit vertex 2i+1 joined by a unit capacity arc,
+// and each edge from u to v is an arc from the exit of u to the entry of v.
+// Self loops are ignored.
+func nodeNetwork(g graph.Graph, nodes []graph.Node, idx map[int64]int) *flowNetwork {
+	_, directed := g.(graph.Directed)
+	f := newFlowNetwork(2 * len(nodes))
+	for i, u := range nodes {
+		f.addArc(2*i, 2*i+1, 1, 0)
+		to := g.From(u.ID())
+		for to.Next() {
+			j := idx[to.Node().ID()]
+			switch {
+			case i == j:
+			case directed:
+				f.addArc(2*i+1, 2*j, 1, 0)
+			case i < j:
+				f.addArc(2*i+1, 2*j, 1, 0)
+				f.addArc(2*j+1, 2*i, 1, 0)
+			}
+		}
+	}
+	return f
+}
+
+// flowNetwork is a residual network with integer capacities for computing
+// maximum flows with Dinic's algorithm. Arcs are stored in pairs so that the
+// reverse of arc a is a^1.
+type flowNetwork struct {
+	head []int // head holds the first arc out of each vertex, or -1.
+	next []int // next holds the next arc with the same tail, or -1.
+	to   []int // to holds the head vertex of each arc.
+
+	cap  []int // cap holds the residual capacity of each arc.
+	orig []int // orig holds the initial capacity of each arc.
+
+	level []int
+	iter  []int
+	queue []int
+}
+
+func newFlowNetwork(n int) *flowNetwork {
+	f := &flowNetwork{
+		head:  make([]int, n),
+		level: make([]int, n),
+		iter:  make([]int, n),
+	}
+	for i := range f.head {
+		f.head[i] = -1
+	}
+	return f
+}
+
+// addArc adds an arc from u to v with capacity c, and the reverse arc with
+// capacity rc.
+func (f *flowNetwork) addArc(u, v, c, rc int) {
+	f.to = append(f.to, v, u)
+	f.orig = append(f.orig, c, rc)
+	f.next = append(f.next, f.head[u], f.head[v])
+	f.head[u] = len(f.to) - 2
+	f.head[v] = len(f.to) - 1
+	f.cap = append(f.cap, c, rc)
+}
+
+// reset restores the initial capacities of the network.
+func (f *flowNetwork) reset() {
+	copy(f.cap, f.orig)
+}
+
+// maxFlow returns the value of a maximum flow from s to t, leaving the
+// residual capacities in the network. The search stops once the flow
+// reaches limit.
+func (f *flowNetwork) maxFlow(s, t, limit int) int {
+	var flow int
+	for flow < limit && f.bfs(s, t) {
+		copy(f.iter, f.head)
+		for flow < limit {
+			pushed := f.dfs(s, t, limit-flow)
+			if pushed == 0 {
+				break
+			}
+			flow += pushed
+		}
+	}
+	return flow
+}
+
+// bfs computes the level graph from s and returns whether t is reachable.
+func (f *flowNetwork) bfs(s, t int) bool {
+	for i := range f.level {
+		f.level[i] = -1
+	}
+	f.level[s] = 0
+	f.queue = append(f.queue[:0], s)
+	for len(f.queue) != 0 {
+		u := f.queue[0]
+		f.queue = f.queue[1:]
+		for a := f.head[u]; a >= 0; a = f.next[a] {
+			v := f.to[a]
+			if f.cap[a] > 0 && f.level[v] < 0 {
+				f.level[v] = f.level[u] + 1
+				f.queue = append(f.queue, v)
+			}
+		}
+	}
+	return f.level[t] >= 0
+}
+
+// dfs pushes a blocking flow of at most limit along level graph paths from
+// u to t and returns the amount pushed.
+func (f *flowNetwork) dfs(u, t, limit int) int {
+	if u == t {
+		return limit
+	}
+	for ; f.iter[u] >= 0; f.iter[u] = f.next[f.iter[u]] {
+		a := f.iter[u]
+		v := f.to[a]
+		if f.cap[a] <= 0 || f.level[v] != f.level[u]+1 {
+			continue
+		}
+		pushed := f.dfs(v, t, min(limit, f.cap[a]))
+		if pushed > 0 {
+			f.cap[a] -= pushed
+			f.cap[a^1] += pushed
+			return pushed
+		}
+	}
+	return 0
+}
+
+// sourceSide returns the vertices reachable from s in the residual network,
+// the source side of a minimum cut after a call to maxFlow.
+func (f *flowNetwork) sourceSide(s int) []bool {
+	side := make([]bool, len(f.head))
+	side[s] = true
+	f.queue = append(f.queue[:0], s)
+	for len(f.queue) != 0 {
+		u := f.queue[0]
+		f.queue = f.queue[1:]
+		for a := f.head[u]; a >= 0; a = f.next[a] {
+			v := f.to[a]
+			if f.cap[a] > 0 && !side[v] {
+				side[v] = true
+				f.queue = append(f.queue, v)
+			}
+		}
+	}
+	return side
+}
@@ -0,0 +1,338 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package topo
+
+import (
+	"reflect"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+var connectivityTests = []struct {
+	name     string
+	g        graph.Graph
+	wantEdge int
+	wantNode int
+}{
+	{name: "empty", g: simple.NewUndirectedGraph()},
+	{name: "single", g: completeUndirected(1)},
+	{name: "K2", g: completeUndirected(2), wantEdge: 1, wantNode: 1},
+	{name: "K5", g: completeUndirected(5), wantEdge: 4, wantNode: 4},
+	{name: "path", g: undirectedFrom([]intset{0: linksTo(1), 1: linksTo(2), 2: nil}), wantEdge: 1, wantNode: 1},
+	{name: "cycle", g: undirectedFrom([]intset{0: linksTo(1), 1: linksTo(2), 2: linksTo(3), 3: linksTo(0)}), wantEdge: 2, wantNode: 2},
+	{name: "disconnected", g: undirectedFrom([]intset{0: linksTo(1), 1: nil, 2: linksTo(3), 3: nil})},
+	{
+		// Two K4 joined at a single node.
+		name: "bowtie K4",
+		g: undirectedFrom([]intset{
+			0: linksTo(1, 2, 3),
+			1: linksTo(2, 3),
+			2: linksTo(3),
+			3: linksTo(4, 5, 6),
+			4: linksTo(5, 6),
+			5: linksTo(6),
+			6: nil,
+		}),
+		wantEdge: 3, wantNode: 1,
+	},
+	{
+		name: "directed cycle",
+		g: directedFrom([]intset{
+			0: linksTo(1),
+			1: linksTo(2),
+			2: linksTo(0),
+		}),
+		wantEdge: 1, wantNode: 1,
+	},
+	{
+		name: "directed not strongly connected",
+		g: directedFrom([]intset{
+			0: linksTo(1, 2),
+			1: linksTo(2),
+			2: nil,
+		}),
+	},
+}
+
+func TestConnectivity(t *testing.T) {
+	for _, test := range connectivityTests {
+		if got := EdgeConnectivity(test.g); got != test.wantEdge {
+			t.Errorf("unexpected edge connectivity for %q: got:%d want:%d", test.name, got, test.wantEdge)
+		}
+		if got := NodeConnectivity(test.g); got != test.wantNode {
+			t.Errorf("unexpected node connectivity for %q: got:%d want:%d", test.name, got, test.wantNode)
+		}
+	}
+}
+
+func TestConnectivityRandom(t *testing.T) {
+	const n = 7
+	rnd := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 40; trial++ {
+		for _, directed := range []bool{false, true} {
+			var g graph.Graph
+			if directed {
+				g = randomDirected(n, 0.5, rnd)
+			} else {
+				g = randomUndirected(n, 0.5, rnd)
+			}
+
+			if got, want := EdgeConnectivity(g), bruteEdgeConnectivity(g, -1, -1); got != want {
+				t.Errorf("unexpected edge connectivity for trial %d directed=%t: got:%d want:%d",
+					trial, directed, got, want)
+			}
+			if got, want := NodeConnectivity(g), bruteNodeConnectivity(g); got != want {
+				t.Errorf("unexpected node connectivity for trial %d directed=%t: got:%d want:%d",
+					trial, directed, got, want)
+			}
+
+			s, tn := simple.Node(0), simple.Node(n-1)
+			if got, want := LocalEdgeConnectivity(g, s, tn), bruteEdgeConnectivity(g, 0, n-1); got != want {
+				t.Errorf("unexpected local edge connectivity for trial %d directed=%t: got:%d want:%d",
+					trial, directed, got, want)
+			}
+			if !hasEdge(g, 0, n-1) {
+				if got, want := LocalNodeConnectivity(g, s, tn), bruteLocalNodeConnectivity(g, 0, n-1); got != want {
+					t.Errorf("unexpected local node connectivity for trial %d directed=%t: got:%d want:%d",
+						trial, directed, got, want)
+				}
+			}
+
+			for k := 1; k <= 4; k++ {
+				got := componentIDs(KEdgeConnectedComponents(g, k))
+				want := bruteKEdgeComponents(g, n, k)
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("unexpected %d-edge-connected components for trial %d directed=%t:\ngot: %v\nwant:%v",
+						k, trial, directed, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestLocalNodeConnectivityAdjacent(t *testing.T) {
+	// The direct edge counts as one path.
+	g := completeUndirected(4)
+	if got := LocalNodeConnectivity(g, simple.Node(0), simple.Node(1)); got != 3 {
+		t.Errorf("unexpected local node connectivity: got:%d want:3", got)
+	}
+}
+
+// bruteEdgeConnectivity returns the minimum number of edges leaving a
+// set of nodes of g, restricted to sets containing s and not t if s and
+// t are not negative.
+func bruteEdgeConnectivity(g graph.Graph, s, t int) int {
+	nodes := graph.NodesOf(g.Nodes())
+	n := len(nodes)
+	best := -1
+	for mask := 1; mask < 1<<uint(n)-1; mask++ {
+		in := func(id int64) bool { return mask&(1<<uint(id)) != 0 }
+		if s >= 0 && (!in(int64(s)) || in(int64(t))) {
+			continue
+		}
+		var cut int
+		for _, u := range nodes {
+			if !in(u.ID()) {
+				continue
+			}
+			for _, v := range graph.NodesOf(g.From(u.ID())) {
+				if !in(v.ID()) {
+					cut++
+				}
+			}
+		}
+		if best < 0 || cut < best {
+			best = cut
+		}
+	}
+	if best < 0 {
+		return 0
+	}
+	return best
+}
+
+// bruteNodeConnectivity returns the size of the smallest set of nodes
+// whose removal leaves g disconnected or with at most one node.
+func bruteNodeConnectivity(g graph.Graph) int {
+	nodes := graph.NodesOf(g.Nodes())
+	n := len(nodes)
+	best := n - 1
+	for mask := 0; mask < 1<<uint(n); mask++ {
+		size := popCount(mask)
+		if size >= best {
+			continue
+		}
+		if !connectedWithout(g, mask) {
+			best = size
+		}
+	}
+	return best
+}
+
+// bruteLocalNodeConnectivity returns the size of the smallest set of nodes
+// other than s and t whose removal leaves no path from s to t.
+func bruteLocalNodeConnectivity(g graph.Graph, s, t int) int {
+	n := g.Nodes().Len()
+	best := n
+	for mask := 0; mask < 1<<uint(n); mask++ {
+		if mask&(1<<uint(s)) != 0 || mask&(1<<uint(t)) != 0 {
+			continue
+		}
+		size := popCount(mask)
+		if size >= best {
+			continue
+		}
+		h := without(g, mask)
+		if !PathExistsIn(h, h.Node(int64(s)), h.Node(int64(t))) {
+			best = size
+		}
+	}
+	return best
+}
+
+func bruteKEdgeComponents(g graph.Graph, n, k int) [][]int64 {
+	_, directed := g.(graph.Directed)
+	comp := make([]int, n)
+	for i := range comp {
+		comp[i] = -1
+	}
+	var cc [][]int64
+	for i := 0; i < n; i++ {
+		if comp[i] >= 0 {
+			continue
+		}
+		comp[i] = len(cc)
+		c := []int64{int64(i)}
+		for j := i + 1; j < n; j++ {
+			if comp[j] >= 0 {
+				continue
+			}
+			if bruteEdgeConnectivity(g, i, j) < k {
+				continue
+			}
+			if directed && bruteEdgeConnectivity(g, j, i) < k {
+				continue
+			}
+			comp[j] = comp[i]
+			c = append(c, int64(j))
+		}
+		cc = append(cc, c)
+	}
+	return cc
+}
+
+// connectedWithout returns whether g with the nodes in mask removed is
+// connected, or strongly connected if g is directed, and has more than one
+// node.
+func connectedWithout(g graph.Graph, mask int) bool {
+	h := without(g, mask)
+	if h.Nodes().Len() < 2 {
+		return false
+	}
+	if d, ok := h.(graph.Directed); ok {
+		return len(TarjanSCC(d)) == 1
+	}
+	return len(ConnectedComponents(h.(graph.Undirected))) == 1
+}
+
+func without(g graph.Graph, mask int) graph.Graph {
+	var h graph.Builder
+	if _, ok := g.(graph.Directed); ok {
+		h = simple.NewDirectedGraph()
+	} else {
+		h = simple.NewUndirectedGraph()
+	}
+	for _, u := range graph.NodesOf(g.Nodes()) {
+		if mask&(1<<uint(u.ID())) != 0 {
+			continue
+		}
+		h.AddNode(u)
+	}
+	for _, u := range graph.NodesOf(g.Nodes()) {
+		if mask&(1<<uint(u.ID())) != 0 {
+			continue
+		}
+		for _, v := range graph.NodesOf(g.From(u.ID())) {
+			if mask&(1<<uint(v.ID())) != 0 {
+				continue
+			}
+			h.SetEdge(g.Edge(u.ID(), v.ID()))
+		}
+	}
+	return h.(graph.Graph)
+}
+
+func hasEdge(g graph.Graph, u, v int64) bool {
+	if d, ok := g.(graph.Directed); ok {
+		return d.HasEdgeFromTo(u, v)
+	}
+	return g.(graph.Undirected).HasEdgeBetween(u, v)
+}
+
+func componentIDs(cc [][]graph.Node) [][]int64 {
+	var ids [][]int64
+	for _, c := range cc {
+		var cid []int64
+		for _, n := range c {
+			cid = append(cid, n.ID())
+		}
+		ids = append(ids, cid)
+	}
+	return ids
+}
+
+func popCount(x int) int {
+	var n int
+	for ; x != 0; x &= x - 1 {
+		n++
+	}
+	return n
+}
+
+func completeUndirected(n int) *simple.UndirectedGraph {
+	g := simple.NewUndirectedGraph()
+	for i := 0; i < n; i++ {
+		g.AddNode(simple.Node(i))
+		for j := 0; j < i; j++ {
+			g.SetEdge(simple.Edge{F: simple.Node(i), T: simple.Node(j)})
+		}
+	}
+	return g
+}
+
+func directedFrom(adj []intset) *simple.DirectedGraph {
+	g := simple.NewDirectedGraph()
+	for u, e := range adj {
+		if g.Node(int64(u)) == nil {
+			g.AddNode(simple.Node(u))
+		}
+		for v := range e {
+			if g.Node(v) == nil {
+				g.AddNode(simple.Node(v))
+			}
+			g.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v)})
+		}
+	}
+	return g
+}
+
+func randomDirected(n int, p float64, rnd *rand.Rand) *simple.DirectedGraph {
+	g := simple.NewDirectedGraph()
+	for i := 0; i < n; i++ {
+		g.AddNode(simple.Node(i))
+	}
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			if i != j && rnd.Float64() < p {
+				g.SetEdge(simple.Edge{F: simple.Node(i), T: simple.Node(j)})
+			}
+		}
+	}
+	return g
+}
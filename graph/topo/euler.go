@@ -0,0 +1,213 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package topo
+
+import (
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+)
+
+// EulerianCircuit returns an Eulerian circuit of the multigraph g, a closed
+// walk that traverses every line of g exactly once. The circuit is returned
+// as a sequence of lines where the To node of each line is the From node of
+// the next and the To node of the last line is the From node of the first.
+// If g is a graph.DirectedMultigraph the circuit follows the direction of
+// the lines, otherwise lines of an undirected multigraph are oriented in the
+// direction of traversal. Nodes without lines are ignored.
+//
+// EulerianCircuit returns nil if g has no lines or no Eulerian circuit.
+// The circuit is found using Hierholzer's algorithm and starts at the node
+// with the lowest ID that has lines.
+func EulerianCircuit(g graph.Multigraph) []graph.Line {
+	e := newEulerian(g)
+	if e.lines == 0 {
+		return nil
+	}
+	start, odd := e.oddNodes()
+	if odd != 0 {
+		return nil
+	}
+	return e.walk(start)
+}
+
+// EulerianPath returns an Eulerian path of the multigraph g, a walk that
+// traverses every line of g exactly once. The path is returned as a
+// sequence of lines where the To node of each line is the From node of the
+// next. If g is a graph.DirectedMultigraph the path follows the direction
+// of the lines, otherwise lines of an undirected multigraph are oriented in
+// the direction of traversal. Nodes without lines are ignored.
+//
+// If g has an Eulerian circuit, EulerianPath returns the circuit returned
+// by EulerianCircuit. Otherwise the path starts at the node with the lowest
+// ID of the two possible start nodes. EulerianPath returns nil if g has no
+// lines or no Eulerian path.
+func EulerianPath(g graph.Multigraph) []graph.Line {
+	e := newEulerian(g)
+	if e.lines == 0 {
+		return nil
+	}
+	start, odd := e.oddNodes()
+	if odd > 2 {
+		return nil
+	}
+	return e.walk(start)
+}
+
+// eulerian holds the adjacency of a multigraph for Hierholzer's algorithm.
+type eulerian struct {
+	directed bool
+	nodes    []graph.Node
+	adj      map[int64][]eulerLine
+	in       map[int64]int
+	lines    int
+}
+
+// eulerLine is a line leaving a node and the key identifying the
+// underlying line.
+type eulerLine struct {
+	line graph.Line
+	key  lineKey
+}
+
+// lineKey identifies a line of a multigraph. For undirected
+// multigraphs u is not greater than v.
+type lineKey struct {
+	u, v, id int64
+}
+
+func newEulerian(g graph.Multigraph) *eulerian {
+	_, directed := g.(graph.DirectedMultigraph)
+	nodes := graph.NodesOf(g.Nodes())
+	sort.Sort(ordered.ByID(nodes))
+	e := &eulerian{
+		directed: directed,
+		nodes:    nodes,
+		adj:      make(map[int64][]eulerLine, len(nodes)),
+		in:       make(map[int64]int),
+	}
+	for _, u := range nodes {
+		uid := u.ID()
+		to := graph.NodesOf(g.From(uid))
+		sort.Sort(ordered.ByID(to))
+		for _, v := range to {
+			vid := v.ID()
+			lines := graph.LinesOf(g.Lines(uid, vid))
+			sort.Sort(ordered.LinesByIDs(lines))
+			for _, l := range lines {
+				key := lineKey{u: uid, v: vid, id: l.ID()}
+				if !directed && vid < uid {
+					key.u, key.v = key.v, key.u
+				}
+				e.adj[uid] = append(e.adj[uid], eulerLine{line: l, key: key})
+				e.in[vid]++
+				if directed || uid <= vid {
+					e.lines++
+				}
+			}
+		}
+	}
+	return e
+}
+
+// oddNodes returns the node to start a walk from and the number of nodes
+// preventing an Eulerian circuit. For directed multigraphs these are the
+// nodes with unequal in and out degree, and the number returned is greater
+// than two if any node is unbalanced by more than one or the unbalanced
+// nodes cannot be the ends of a path. For undirected multigraphs they are
+// the nodes with odd degree.
+func (e *eulerian) oddNodes() (start graph.Node, odd int) {
+	var first graph.Node
+	for _, u := range e.nodes {
+		uid := u.ID()
+		out := len(e.adj[uid])
+		if out == 0 && e.in[uid] == 0 {
+			continue
+		}
+		if first == nil {
+			first = u
+		}
+		if e.directed {
+			switch d := out - e.in[uid]; d {
+			case 0:
+			case 1:
+				if start != nil {
+					return nil, 3
+				}
+				start = u
+				odd++
+			case -1:
+				odd++
+			default:
+				return nil, 3
+			}
+			continue
+		}
+		// Self loops appear once in the adjacency
+		// but contribute two to the degree.
+		deg := out
+		for _, l := range e.adj[uid] {
+			if l.line.To().ID() == uid {
+				deg++
+			}
+		}
+		if deg%2 != 0 {
+			if start == nil {
+				start = u
+			}
+			odd++
+		}
+	}
+	if e.directed && odd != 0 && (odd != 2 || start == nil) {
+		return nil, 3
+	}
+	if start == nil {
+		start = first
+	}
+	return start, odd
+}
+
+// walk returns the lines of an Eulerian walk starting at start, or nil if
+// the walk does not include all the lines.
+func (e *eulerian) walk(start graph.Node) []graph.Line {
+	type frame struct {
+		node graph.Node
+		line graph.Line
+	}
+	var (
+		next  = make(map[int64]int)
+		used  = make(map[lineKey]bool, e.lines)
+		stack = []frame{{node: start}}
+		path  = make([]graph.Line, 0, e.lines)
+	)
+	for len(stack) != 0 {
+		uid := stack[len(stack)-1].node.ID()
+		adj := e.adj[uid]
+		i := next[uid]
+		for i < len(adj) && used[adj[i].key] {
+			i++
+		}
+		if i < len(adj) {
+			next[uid] = i + 1
+			used[adj[i].key] = true
+			stack = append(stack, frame{node: adj[i].line.To(), line: adj[i].line})
+			continue
+		}
+		next[uid] = i
+		if l := stack[len(stack)-1].line; l != nil {
+			path = append(path, l)
+		}
+		stack = stack[:len(stack)-1]
+	}
+	if len(path) != e.lines {
+		// The lines are not connected.
+		return nil
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
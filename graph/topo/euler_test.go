@@ -0,0 +1,205 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package topo
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/multi"
+)
+
+var eulerianTests = []struct {
+	name     string
+	directed bool
+	lines    [][2]int64
+	nodes    []int64
+
+	wantPath    bool
+	wantCircuit bool
+}{
+	{name: "empty"},
+	{name: "isolated", nodes: []int64{0, 1}},
+	{
+		// The seven bridges of Königsberg.
+		name:  "königsberg",
+		lines: [][2]int64{{0, 1}, {0, 1}, {0, 2}, {0, 2}, {0, 3}, {1, 3}, {2, 3}},
+	},
+	{
+		name:     "königsberg less one bridge",
+		lines:    [][2]int64{{0, 1}, {0, 2}, {0, 2}, {0, 3}, {1, 3}, {2, 3}},
+		wantPath: true,
+	},
+	{
+		name:        "parallel lines and self loops",
+		lines:       [][2]int64{{0, 1}, {0, 1}, {1, 1}, {1, 2}, {2, 1}, {2, 0}, {2, 0}, {2, 2}, {2, 2}},
+		nodes:       []int64{5},
+		wantPath:    true,
+		wantCircuit: true,
+	},
+	{
+		name:  "disconnected",
+		lines: [][2]int64{{0, 1}, {1, 2}, {2, 0}, {3, 4}, {4, 5}, {5, 3}},
+	},
+	{
+		name:     "directed circuit",
+		directed: true,
+		lines:    [][2]int64{{0, 1}, {1, 2}, {2, 0}, {0, 3}, {3, 0}, {1, 1}},
+		wantPath: true, wantCircuit: true,
+	},
+	{
+		name:     "directed path",
+		directed: true,
+		lines:    [][2]int64{{0, 1}, {1, 2}, {2, 0}, {0, 3}, {3, 0}, {3, 4}},
+		wantPath: true,
+	},
+	{
+		name:     "directed path parallel",
+		directed: true,
+		lines:    [][2]int64{{2, 1}, {2, 1}, {1, 2}, {1, 0}},
+		wantPath: true,
+	},
+	{
+		name:     "directed unbalanced",
+		directed: true,
+		lines:    [][2]int64{{0, 1}, {0, 2}, {1, 2}, {0, 3}},
+	},
+	{
+		name:     "directed two sinks",
+		directed: true,
+		lines:    [][2]int64{{0, 1}, {0, 2}},
+	},
+}
+
+func TestEulerian(t *testing.T) {
+	for _, test := range eulerianTests {
+		g := multigraphFrom(test.directed, test.nodes, test.lines)
+
+		path := EulerianPath(g)
+		if (path != nil) != test.wantPath {
+			t.Errorf("unexpected Eulerian path existence for %q: got:%t want:%t", test.name, path != nil, test.wantPath)
+		}
+		if path != nil {
+			checkEulerian(t, test.name, g, path, len(test.lines), false)
+		}
+
+		circuit := EulerianCircuit(g)
+		if (circuit != nil) != test.wantCircuit {
+			t.Errorf("unexpected Eulerian circuit existence for %q: got:%t want:%t", test.name, circuit != nil, test.wantCircuit)
+		}
+		if circuit != nil {
+			checkEulerian(t, test.name, g, circuit, len(test.lines), true)
+		}
+	}
+}
+
+func TestEulerianRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 20; trial++ {
+		for _, directed := range []bool{false, true} {
+			// The union of closed walks from a common node
+			// has an Eulerian circuit.
+			var lines [][2]int64
+			for w := 0; w < 5; w++ {
+				u := int64(0)
+				for s := 0; s < 2+rnd.Intn(6); s++ {
+					v := int64(rnd.Intn(8))
+					lines = append(lines, [2]int64{u, v})
+					u = v
+				}
+				lines = append(lines, [2]int64{u, 0})
+			}
+			g := multigraphFrom(directed, nil, lines)
+			circuit := EulerianCircuit(g)
+			if circuit == nil {
+				t.Errorf("no Eulerian circuit found for trial %d directed=%t", trial, directed)
+				continue
+			}
+			checkEulerian(t, "random", g, circuit, len(lines), true)
+
+			// Removing a line that is not a self loop leaves
+			// an Eulerian path but no circuit.
+			for i, l := range lines {
+				if l[0] == l[1] {
+					continue
+				}
+				rest := append(append([][2]int64(nil), lines[:i]...), lines[i+1:]...)
+				g := multigraphFrom(directed, nil, rest)
+				if EulerianCircuit(g) != nil {
+					t.Errorf("unexpected Eulerian circuit for trial %d directed=%t", trial, directed)
+				}
+				path := EulerianPath(g)
+				if path == nil {
+					t.Errorf("no Eulerian path found for trial %d directed=%t", trial, directed)
+					break
+				}
+				checkEulerian(t, "random", g, path, len(rest), false)
+				if directed && (path[0].From().ID() != l[1] || path[len(path)-1].To().ID() != l[0]) {
+					t.Errorf("unexpected Eulerian path ends for trial %d: got:%d→%d want:%d→%d",
+						trial, path[0].From().ID(), path[len(path)-1].To().ID(), l[1], l[0])
+				}
+				break
+			}
+		}
+	}
+}
+
+func checkEulerian(t *testing.T, name string, g graph.Multigraph, walk []graph.Line, n int, closed bool) {
+	t.Helper()
+	if len(walk) != n {
+		t.Errorf("unexpected walk length for %q: got:%d want:%d", name, len(walk), n)
+	}
+	type key struct{ u, v, id int64 }
+	_, directed := g.(graph.DirectedMultigraph)
+	seen := make(map[key]bool)
+	for i, l := range walk {
+		u, v := l.From().ID(), l.To().ID()
+		if i > 0 && walk[i-1].To().ID() != u {
+			t.Errorf("walk for %q is not connected at step %d", name, i)
+		}
+		found := false
+		for _, gl := range graph.LinesOf(g.Lines(u, v)) {
+			if gl.ID() == l.ID() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("walk line %d→%d (%d) for %q not in graph", u, v, l.ID(), name)
+		}
+		k := key{u, v, l.ID()}
+		if !directed && v < u {
+			k.u, k.v = v, u
+		}
+		if seen[k] {
+			t.Errorf("walk line %d→%d (%d) for %q used more than once", u, v, l.ID(), name)
+		}
+		seen[k] = true
+	}
+	if closed && walk[0].From().ID() != walk[len(walk)-1].To().ID() {
+		t.Errorf("circuit for %q is not closed", name)
+	}
+}
+
+func multigraphFrom(directed bool, nodes []int64, lines [][2]int64) graph.Multigraph {
+	var g interface {
+		graph.Multigraph
+		graph.MultigraphBuilder
+	}
+	if directed {
+		g = multi.NewDirectedGraph()
+	} else {
+		g = multi.NewUndirectedGraph()
+	}
+	for _, id := range nodes {
+		g.AddNode(multi.Node(id))
+	}
+	for _, l := range lines {
+		g.SetLine(g.NewLine(multi.Node(l[0]), multi.Node(l[1])))
+	}
+	return g
+}
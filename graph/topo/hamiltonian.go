@@ -0,0 +1,194 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package topo
+
+import (
+	"gonum.org/v1/gonum/graph"
+)
+
+// heldKarpLimit is the largest number of nodes for which Hamiltonian
+// paths and cycles are found by dynamic programming.
+const heldKarpLimit = 20
+
+// HamiltonianPath returns a Hamiltonian path of g, a path that visits every
+// node of g exactly once, or nil if no such path exists. If g is a
+// graph.Directed, the path follows the direction of the edges.
+//
+// Finding a Hamiltonian path is NP-complete, so HamiltonianPath is only
+// suitable for small graphs. For graphs with up to 20 nodes the dynamic
+// programming algorithm of Held and Karp is used, taking O(2^n n^2) time and
+// O(2^n) space. Larger graphs are searched by backtracking, which may take
+// time exponential in the number of nodes.
+func HamiltonianPath(g graph.Graph) []graph.Node {
+	h := newHamiltonian(g)
+	switch {
+	case h.n == 0:
+		return nil
+	case h.n <= heldKarpLimit:
+		return h.heldKarp(false)
+	default:
+		for s := 0; s < h.n; s++ {
+			if p := h.backtrack(s, false); p != nil {
+				return p
+			}
+		}
+		return nil
+	}
+}
+
+// HamiltonianCycle returns a Hamiltonian cycle of g, a cycle that visits
+// every node of g exactly once, or nil if no such cycle exists. The cycle is
+// returned as a sequence of nodes starting with the node with the lowest ID;
+// the edge closing the cycle from the last node to the first is implied. If
+// g is a graph.Directed, the cycle follows the direction of the edges.
+// Undirected graphs with fewer than three nodes have no Hamiltonian cycle.
+//
+// HamiltonianCycle uses the same algorithms as HamiltonianPath and is only
+// suitable for small graphs.
+func HamiltonianCycle(g graph.Graph) []graph.Node {
+	h := newHamiltonian(g)
+	switch {
+	case h.n == 0:
+		return nil
+	case !h.directed && h.n < 3:
+		return nil
+	case h.n <= heldKarpLimit:
+		return h.heldKarp(true)
+	default:
+		return h.backtrack(0, true)
+	}
+}
+
+// hamiltonian holds the adjacency of a graph indexed by node position.
+type hamiltonian struct {
+	n        int
+	directed bool
+	nodes    []graph.Node
+	adj      [][]int
+	hasEdge  func(u, v int) bool
+}
+
+func newHamiltonian(g graph.Graph) *hamiltonian {
+	nodes, idx := indexNodes(g)
+	h := &hamiltonian{
+		n:     len(nodes),
+		nodes: nodes,
+		adj:   make([][]int, len(nodes)),
+	}
+	var canReach func(uid, vid int64) bool
+	switch g := g.(type) {
+	case graph.Directed:
+		canReach = g.HasEdgeFromTo
+		h.directed = true
+	default:
+		canReach = g.HasEdgeBetween
+	}
+	h.hasEdge = func(u, v int) bool { return canReach(nodes[u].ID(), nodes[v].ID()) }
+	for i, u := range nodes {
+		to := g.From(u.ID())
+		for to.Next() {
+			if j := idx[to.Node().ID()]; j != i {
+				h.adj[i] = append(h.adj[i], j)
+			}
+		}
+	}
+	return h
+}
+
+// heldKarp returns a Hamiltonian path or cycle using dynamic programming
+// over subsets of nodes.
+func (h *hamiltonian) heldKarp(cycle bool) []graph.Node {
+	// ends[mask] holds the set of nodes v in mask such that
+	// a path visiting exactly the nodes in mask ends at v.
+	// Cycles are anchored at the first node.
+	full := uint32(1)<<uint(h.n) - 1
+	ends := make([]uint32, full+1)
+	if cycle {
+		ends[1] = 1
+	} else {
+		for v := 0; v < h.n; v++ {
+			ends[1<<uint(v)] = 1 << uint(v)
+		}
+	}
+	for mask := uint32(1); mask < full; mask++ {
+		e := ends[mask]
+		for v := 0; e != 0; v++ {
+			if e&(1<<uint(v)) == 0 {
+				continue
+			}
+			e &^= 1 << uint(v)
+			for _, w := range h.adj[v] {
+				if mask&(1<<uint(w)) == 0 {
+					ends[mask|1<<uint(w)] |= 1 << uint(w)
+				}
+			}
+		}
+	}
+
+	last := -1
+	for v := 0; v < h.n; v++ {
+		if ends[full]&(1<<uint(v)) == 0 {
+			continue
+		}
+		if cycle && !h.hasEdge(v, 0) {
+			continue
+		}
+		last = v
+		break
+	}
+	if last < 0 || (cycle && h.n == 1) {
+		return nil
+	}
+
+	// Walk back through the subsets to recover the path.
+	path := make([]graph.Node, h.n)
+	mask := full
+	for i := h.n - 1; i > 0; i-- {
+		path[i] = h.nodes[last]
+		mask &^= 1 << uint(last)
+		for u := 0; u < h.n; u++ {
+			if ends[mask]&(1<<uint(u)) != 0 && h.hasEdge(u, last) {
+				last = u
+				break
+			}
+		}
+	}
+	path[0] = h.nodes[last]
+	return path
+}
+
+// backtrack returns a Hamiltonian path or cycle starting from node s using
+// a depth-first search.
+func (h *hamiltonian) backtrack(s int, cycle bool) []graph.Node {
+	visited := make([]bool, h.n)
+	path := make([]int, 0, h.n)
+	var search func(u int) bool
+	search = func(u int) bool {
+		visited[u] = true
+		path = append(path, u)
+		if len(path) == h.n {
+			if !cycle || h.hasEdge(u, s) {
+				return true
+			}
+		} else {
+			for _, v := range h.adj[u] {
+				if !visited[v] && search(v) {
+					return true
+				}
+			}
+		}
+		visited[u] = false
+		path = path[:len(path)-1]
+		return false
+	}
+	if !search(s) {
+		return nil
+	}
+	nodes := make([]graph.Node, len(path))
+	for i, u := range path {
+		nodes[i] = h.nodes[u]
+	}
+	return nodes
+}
@@ -0,0 +1,157 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package topo
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+var hamiltonianTests = []struct {
+	name string
+	g    graph.Graph
+
+	wantPath  bool
+	wantCycle bool
+}{
+	{name: "empty", g: simple.NewUndirectedGraph()},
+	{name: "single", g: completeUndirected(1), wantPath: true},
+	{name: "K2", g: completeUndirected(2), wantPath: true},
+	{name: "K5", g: completeUndirected(5), wantPath: true, wantCycle: true},
+	{
+		name:     "star",
+		g:        undirectedFrom([]intset{0: linksTo(1, 2, 3)}),
+		wantPath: false,
+	},
+	{
+		name:     "path",
+		g:        undirectedFrom([]intset{0: linksTo(2), 1: linksTo(3), 2: linksTo(3)}),
+		wantPath: true,
+	},
+	{
+		// The Petersen graph has a Hamiltonian path
+		// but no Hamiltonian cycle.
+		name: "petersen",
+		g: undirectedFrom([]intset{
+			0: linksTo(1, 4, 5),
+			1: linksTo(2, 6),
+			2: linksTo(3, 7),
+			3: linksTo(4, 8),
+			4: linksTo(9),
+			5: linksTo(7, 8),
+			6: linksTo(8, 9),
+			7: linksTo(9),
+		}),
+		wantPath: true,
+	},
+	{
+		name:     "directed 2-cycle",
+		g:        directedFrom([]intset{0: linksTo(1), 1: linksTo(0)}),
+		wantPath: true, wantCycle: true,
+	},
+	{
+		name:     "directed path",
+		g:        directedFrom([]intset{2: linksTo(0), 0: linksTo(1), 3: linksTo(2)}),
+		wantPath: true,
+	},
+	{
+		name: "directed no path",
+		g:    directedFrom([]intset{0: linksTo(1, 2)}),
+	},
+}
+
+func TestHamiltonian(t *testing.T) {
+	for _, test := range hamiltonianTests {
+		path := HamiltonianPath(test.g)
+		if (path != nil) != test.wantPath {
+			t.Errorf("unexpected Hamiltonian path existence for %q: got:%t want:%t", test.name, path != nil, test.wantPath)
+		}
+		if path != nil {
+			checkHamiltonian(t, test.name, test.g, path, false)
+		}
+		cycle := HamiltonianCycle(test.g)
+		if (cycle != nil) != test.wantCycle {
+			t.Errorf("unexpected Hamiltonian cycle existence for %q: got:%t want:%t", test.name, cycle != nil, test.wantCycle)
+		}
+		if cycle != nil {
+			checkHamiltonian(t, test.name, test.g, cycle, true)
+		}
+	}
+}
+
+func TestHamiltonianBacktrack(t *testing.T) {
+	// Check that the backtracking search agrees with
+	// the dynamic programming search.
+	rnd := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 50; trial++ {
+		for _, directed := range []bool{false, true} {
+			var g graph.Graph
+			if directed {
+				g = randomDirected(8, 0.3, rnd)
+			} else {
+				g = randomUndirected(8, 0.35, rnd)
+			}
+			h := newHamiltonian(g)
+
+			dp := h.heldKarp(false)
+			var bt []graph.Node
+			for s := 0; s < h.n && bt == nil; s++ {
+				bt = h.backtrack(s, false)
+			}
+			if (dp != nil) != (bt != nil) {
+				t.Errorf("path search mismatch for trial %d directed=%t: held-karp:%t backtrack:%t",
+					trial, directed, dp != nil, bt != nil)
+			}
+			if bt != nil {
+				checkHamiltonian(t, "backtrack", g, bt, false)
+			}
+
+			dp = h.heldKarp(true)
+			bt = h.backtrack(0, true)
+			if (dp != nil) != (bt != nil) {
+				t.Errorf("cycle search mismatch for trial %d directed=%t: held-karp:%t backtrack:%t",
+					trial, directed, dp != nil, bt != nil)
+			}
+			if bt != nil {
+				checkHamiltonian(t, "backtrack", g, bt, true)
+			}
+		}
+	}
+
+	// Graphs larger than the Held-Karp limit use backtracking.
+	g := undirectedFrom(nil)
+	for i := 0; i < heldKarpLimit+5; i++ {
+		g.SetEdge(simple.Edge{F: simple.Node(i), T: simple.Node((i + 3) % (heldKarpLimit + 5))})
+	}
+	cycle := HamiltonianCycle(g)
+	if cycle == nil {
+		t.Fatal("no Hamiltonian cycle found for large cycle graph")
+	}
+	checkHamiltonian(t, "large cycle", g, cycle, true)
+}
+
+func checkHamiltonian(t *testing.T, name string, g graph.Graph, path []graph.Node, cycle bool) {
+	t.Helper()
+	if len(path) != g.Nodes().Len() {
+		t.Errorf("unexpected length for %q: got:%d want:%d", name, len(path), g.Nodes().Len())
+	}
+	seen := make(map[int64]bool)
+	for i, n := range path {
+		if seen[n.ID()] {
+			t.Errorf("node %d visited more than once for %q", n.ID(), name)
+		}
+		seen[n.ID()] = true
+		if i > 0 && !hasEdge(g, path[i-1].ID(), n.ID()) {
+			t.Errorf("no edge %d→%d for %q", path[i-1].ID(), n.ID(), name)
+		}
+	}
+	if cycle && !hasEdge(g, path[len(path)-1].ID(), path[0].ID()) {
+		t.Errorf("cycle not closed for %q", name)
+	}
+}
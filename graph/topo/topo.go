@@ -70,6 +70,13 @@ func ConnectedComponents(g graph.Undirected) [][]graph.Node {
 	return cc
 }
 
+// WeaklyConnectedComponents returns the weakly connected components of the
+// directed graph g, the connected components of g when edge directions are
+// ignored.
+func WeaklyConnectedComponents(g graph.Directed) [][]graph.Node {
+	return ConnectedComponents(graph.Undirect{G: g})
+}
+
 // Equal returns whether two graphs are topologically equal. To be
 // considered topologically equal, a and b must have identical sets
 // of nodes and be identically traversable.
@@ -0,0 +1,145 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package topo
+
+import (
+	"gonum.org/v1/gonum/graph"
+)
+
+// TransitiveClosure builds the transitive closure of the directed graph g in
+// dst. The closure has the nodes of g and an edge from u to v for each pair
+// of distinct nodes where v is reachable from u in g. Edges of g are copied
+// to dst and new edges are created with dst.NewEdge. Self edges are not
+// added for nodes on cycles.
+//
+// The dst graph is not cleared. TransitiveClosure will panic if a node ID
+// in g matches a node ID in dst.
+func TransitiveClosure(dst graph.Builder, g graph.Directed) {
+	nodes, idx := indexNodes(g)
+	words := (len(nodes) + 63) / 64
+
+	// TarjanSCC returns the strongly connected components
+	// in reverse topological order, so the reachability of
+	// each component's successors is known when it is met.
+	sccs := TarjanSCC(g)
+	comp := make(map[int64]int, len(nodes))
+	for i, c := range sccs {
+		for _, n := range c {
+			comp[n.ID()] = i
+		}
+	}
+	reach := make([]bitset, len(sccs))
+	for i, c := range sccs {
+		r := make(bitset, words)
+		if len(c) > 1 {
+			for _, n := range c {
+				r.set(idx[n.ID()])
+			}
+		}
+		for _, u := range c {
+			to := g.From(u.ID())
+			for to.Next() {
+				v := to.Node()
+				j := comp[v.ID()]
+				if j == i {
+					continue
+				}
+				r.set(idx[v.ID()])
+				r.union(reach[j])
+			}
+		}
+		reach[i] = r
+	}
+
+	for _, u := range nodes {
+		dst.AddNode(u)
+	}
+	for i, u := range nodes {
+		uid := u.ID()
+		r := reach[comp[uid]]
+		for j, v := range nodes {
+			if j == i || !r.has(j) {
+				continue
+			}
+			e := g.Edge(uid, v.ID())
+			if e == nil {
+				e = dst.NewEdge(u, v)
+			}
+			dst.SetEdge(e)
+		}
+	}
+}
+
+// TransitiveReduction builds the transitive reduction of the directed
+// acyclic graph g in dst. The reduction has the nodes of g and the edges of
+// g from u to v for which there is no other path from u to v. It is the
+// smallest graph with the same reachability as g.
+//
+// If g is not acyclic, TransitiveReduction returns the Unorderable error
+// returned by Sort and dst is not modified. Otherwise the dst graph is not
+// cleared and TransitiveReduction will panic if a node ID in g matches a
+// node ID in dst.
+func TransitiveReduction(dst graph.Builder, g graph.Directed) error {
+	sorted, err := Sort(g)
+	if err != nil {
+		return err
+	}
+	idx := make(map[int64]int, len(sorted))
+	for i, n := range sorted {
+		idx[n.ID()] = i
+	}
+	words := (len(sorted) + 63) / 64
+
+	// Compute the nodes reachable from each node in
+	// reverse topological order.
+	reach := make([]bitset, len(sorted))
+	for i := len(sorted) - 1; i >= 0; i-- {
+		r := make(bitset, words)
+		to := g.From(sorted[i].ID())
+		for to.Next() {
+			j := idx[to.Node().ID()]
+			r.set(j)
+			r.union(reach[j])
+		}
+		reach[i] = r
+	}
+
+	for _, u := range sorted {
+		dst.AddNode(u)
+	}
+	cover := make(bitset, words)
+	for _, u := range sorted {
+		uid := u.ID()
+		to := graph.NodesOf(g.From(uid))
+
+		// An edge from u to v is redundant if v is reachable
+		// from any successor of u. No successor can reach
+		// itself since g is acyclic.
+		for i := range cover {
+			cover[i] = 0
+		}
+		for _, v := range to {
+			cover.union(reach[idx[v.ID()]])
+		}
+		for _, v := range to {
+			if !cover.has(idx[v.ID()]) {
+				dst.SetEdge(g.Edge(uid, v.ID()))
+			}
+		}
+	}
+	return nil
+}
+
+// bitset is a set of non-negative integers.
+type bitset []uint64
+
+func (s bitset) set(i int)      { s[i/64] |= 1 << uint(i%64) }
+func (s bitset) has(i int) bool { return s[i/64]&(1<<uint(i%64)) != 0 }
+
+func (s bitset) union(t bitset) {
+	for i, w := range t {
+		s[i] |= w
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package topo
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+func TestTransitiveClosure(t *testing.T) {
+	g := directedFrom([]intset{
+		0: linksTo(1),
+		1: linksTo(2),
+		2: linksTo(1, 3),
+		3: nil,
+		4: linksTo(0),
+		5: nil,
+	})
+	dst := simple.NewDirectedGraph()
+	TransitiveClosure(dst, g)
+	got := edgePairs(dst)
+	want := [][2]int64{
+		{0, 1}, {0, 2}, {0, 3},
+		{1, 2}, {1, 3},
+		{2, 1}, {2, 3},
+		{4, 0}, {4, 1}, {4, 2}, {4, 3},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected transitive closure:\ngot: %v\nwant:%v", got, want)
+	}
+	if dst.Nodes().Len() != 6 {
+		t.Errorf("unexpected number of nodes: got:%d want:6", dst.Nodes().Len())
+	}
+}
+
+func TestTransitiveReduction(t *testing.T) {
+	g := directedFrom([]intset{
+		0: linksTo(1, 2, 3, 4),
+		1: linksTo(3),
+		2: linksTo(3, 4),
+		3: linksTo(4),
+		5: nil,
+	})
+	dst := simple.NewDirectedGraph()
+	err := TransitiveReduction(dst, g)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := edgePairs(dst)
+	want := [][2]int64{{0, 1}, {0, 2}, {1, 3}, {2, 3}, {3, 4}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected transitive reduction:\ngot: %v\nwant:%v", got, want)
+	}
+
+	cyclic := directedFrom([]intset{0: linksTo(1), 1: linksTo(0)})
+	dst = simple.NewDirectedGraph()
+	err = TransitiveReduction(dst, cyclic)
+	if _, ok := err.(Unorderable); !ok {
+		t.Errorf("expected Unorderable error for cyclic graph, got: %v", err)
+	}
+	if dst.Nodes().Len() != 0 {
+		t.Error("dst modified for cyclic graph")
+	}
+}
+
+func TestTransitiveRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 20; trial++ {
+		// Random DAG with edges from lower to higher IDs.
+		const n = 70
+		g := simple.NewDirectedGraph()
+		for i := 0; i < n; i++ {
+			g.AddNode(simple.Node(i))
+		}
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				if rnd.Float64() < 0.05 {
+					g.SetEdge(simple.Edge{F: simple.Node(i), T: simple.Node(j)})
+				}
+			}
+		}
+
+		closure := simple.NewDirectedGraph()
+		TransitiveClosure(closure, g)
+		for _, u := range graph.NodesOf(g.Nodes()) {
+			for _, v := range graph.NodesOf(g.Nodes()) {
+				want := u.ID() != v.ID() && PathExistsIn(g, u, v)
+				if got := closure.HasEdgeFromTo(u.ID(), v.ID()); got != want {
+					t.Errorf("unexpected closure edge %d→%d for trial %d: got:%t want:%t", u.ID(), v.ID(), trial, got, want)
+				}
+			}
+		}
+
+		reduction := simple.NewDirectedGraph()
+		err := TransitiveReduction(reduction, g)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		// The reduction has the same closure as g and
+		// no edge can be removed without changing it.
+		rc := simple.NewDirectedGraph()
+		TransitiveClosure(rc, reduction)
+		if !reflect.DeepEqual(edgePairs(rc), edgePairs(closure)) {
+			t.Errorf("reduction closure differs from closure for trial %d", trial)
+		}
+		for _, e := range edgePairs(reduction) {
+			if !g.HasEdgeFromTo(e[0], e[1]) {
+				t.Errorf("reduction edge %d→%d not in graph for trial %d", e[0], e[1], trial)
+			}
+			h := simple.NewDirectedGraph()
+			graph.Copy(h, reduction)
+			h.RemoveEdge(e[0], e[1])
+			if PathExistsIn(h, h.Node(e[0]), h.Node(e[1])) {
+				t.Errorf("redundant reduction edge %d→%d for trial %d", e[0], e[1], trial)
+			}
+		}
+	}
+}
+
+func edgePairs(g graph.Graph) [][2]int64 {
+	var pairs [][2]int64
+	for _, u := range graph.NodesOf(g.Nodes()) {
+		for _, v := range graph.NodesOf(g.From(u.ID())) {
+			pairs = append(pairs, [2]int64{u.ID(), v.ID()})
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i][0] != pairs[j][0] {
+			return pairs[i][0] < pairs[j][0]
+		}
+		return pairs[i][1] < pairs[j][1]
+	})
+	return pairs
+}
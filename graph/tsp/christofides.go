@@ -0,0 +1,85 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tsp
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/multi"
+	"gonum.org/v1/gonum/graph/path"
+	"gonum.org/v1/gonum/graph/simple"
+	"gonum.org/v1/gonum/graph/topo"
+)
+
+// Christofides returns a tour of all the nodes of the complete undirected
+// graph g constructed by the algorithm of Christofides "Worst-case analysis
+// of a new heuristic for the travelling salesman problem" (1976). If the
+// edge weights of g satisfy the triangle inequality, the weight of the tour
+// is within a factor of 3/2 of the minimum.
+//
+// The tour is constructed from an Eulerian circuit of the union of a
+// minimum spanning tree of g and a minimum weight perfect matching of the
+// nodes with odd degree in the tree, skipping nodes that have already been
+// visited. The tour starts at the node with the lowest ID.
+//
+// Christofides will panic if g is not complete.
+func Christofides(g graph.WeightedUndirected) Tour {
+	d := newDistances(g)
+	n := len(d.nodes)
+	for i := range d.d {
+		for j, w := range d.d[i] {
+			if i != j && math.IsInf(w, 1) {
+				panic("tsp: graph is not complete")
+			}
+		}
+	}
+	if n < 3 {
+		order := make([]int, n)
+		for i := range order {
+			order[i] = i
+		}
+		return d.tour(order)
+	}
+
+	mst := simple.NewWeightedUndirectedGraph(0, math.Inf(1))
+	path.Prim(mst, g)
+
+	// Join the nodes with odd degree in the
+	// minimum spanning tree with a minimum
+	// weight perfect matching.
+	var odd []int
+	for i, u := range d.nodes {
+		if mst.From(u.ID()).Len()%2 != 0 {
+			odd = append(odd, i)
+		}
+	}
+	mate := minWeightPerfectMatching(odd, d.d)
+
+	euler := multi.NewUndirectedGraph()
+	edges := mst.Edges()
+	for edges.Next() {
+		e := edges.Edge()
+		euler.SetLine(euler.NewLine(e.From(), e.To()))
+	}
+	for i, u := range odd {
+		if v := mate[i]; u < v {
+			euler.SetLine(euler.NewLine(d.nodes[u], d.nodes[v]))
+		}
+	}
+
+	// Shortcut the Eulerian circuit to
+	// visit each node once.
+	visited := make([]bool, n)
+	order := make([]int, 0, n)
+	for _, l := range topo.EulerianCircuit(euler) {
+		u := d.indexOf[l.From().ID()]
+		if !visited[u] {
+			visited[u] = true
+			order = append(order, u)
+		}
+	}
+	return d.tour(order)
+}
@@ -0,0 +1,35 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tsp
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestChristofides(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for test := 0; test < 50; test++ {
+		n := 1 + rnd.Intn(12)
+		g := euclidean(n, rnd)
+		got := Christofides(g)
+		checkTour(t, "Christofides", g, got)
+		if n > 0 && got.Nodes[0].ID() != 0 {
+			t.Errorf("unexpected start node for test %d: got:%d want:0", test, got.Nodes[0].ID())
+		}
+		if opt := HeldKarp(g).Weight; got.Weight > 1.5*opt+1e-12 {
+			t.Errorf("tour weight exceeds bound for test %d: got:%v want<=%v", test, got.Weight, 1.5*opt)
+		}
+	}
+}
+
+func BenchmarkChristofides(b *testing.B) {
+	g := euclidean(200, rand.New(rand.NewSource(1)))
+	for i := 0; i < b.N; i++ {
+		Christofides(g)
+	}
+}
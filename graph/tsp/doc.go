@@ -0,0 +1,6 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package tsp provides travelling salesman and vehicle routing functions.
+package tsp // import "gonum.org/v1/gonum/graph/tsp"
@@ -0,0 +1,281 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tsp
+
+import (
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+)
+
+const (
+	// lkCandidates is the number of nearest neighbours of
+	// each node considered by LinKernighan.
+	lkCandidates = 10
+
+	// lkMaxDepth is the maximum number of sequential
+	// exchanges in a LinKernighan move.
+	lkMaxDepth = 50
+)
+
+// TwoOpt returns the tour obtained from t by 2-opt local search on the
+// undirected graph g. Pairs of tour edges are repeatedly replaced by the
+// pair of edges that reconnect the tour with a reversed segment while this
+// reduces the tour weight. The returned tour starts at the same node as t.
+//
+// TwoOpt will panic if t is not a tour of all the nodes of g.
+func TwoOpt(g graph.WeightedUndirected, t Tour) Tour {
+	d := newDistances(g)
+	order := d.indices(t)
+	n := len(order)
+	for improved := true; improved; {
+		improved = false
+		for i := 0; i < n-2; i++ {
+			a, b := order[i], order[i+1]
+			for j := i + 2; j < n; j++ {
+				if i == 0 && j == n-1 {
+					continue
+				}
+				c, e := order[j], order[(j+1)%n]
+				delta := d.d[a][c] + d.d[b][e] - d.d[a][b] - d.d[c][e]
+				if delta < -tol {
+					reverseInts(order[i+1 : j+1])
+					b = order[i+1]
+					improved = true
+				}
+			}
+		}
+	}
+	return d.tour(order)
+}
+
+// OrOpt returns the tour obtained from t by Or-opt local search on g.
+// Segments of one to three consecutive nodes are repeatedly moved to the
+// position elsewhere in the tour that most reduces the tour weight while
+// this reduces the tour weight. Segments are not reversed, so OrOpt may
+// be used with directed graphs.
+//
+// OrOpt will panic if t is not a tour of all the nodes of g.
+func OrOpt(g graph.Weighted, t Tour) Tour {
+	d := newDistances(g)
+	order := d.indices(t)
+	n := len(order)
+	for improved := true; improved; {
+		improved = false
+		for l := 1; l <= 3 && l+2 <= n; l++ {
+			for i := 0; i+l <= n; i++ {
+				first, last := order[i], order[i+l-1]
+				prev, next := order[(i-1+n)%n], order[(i+l)%n]
+				removed := d.d[prev][first] + d.d[last][next] - d.d[prev][next]
+
+				// Find the best edge outside the segment
+				// to insert the segment into.
+				best, bestDelta := -1, -tol
+				for k := 0; k < n; k++ {
+					if k >= i-1 && k < i+l || (i == 0 && k == n-1) {
+						continue
+					}
+					p, q := order[k], order[(k+1)%n]
+					if delta := d.d[p][first] + d.d[last][q] - d.d[p][q] - removed; delta < bestDelta {
+						best, bestDelta = k, delta
+					}
+				}
+				if best < 0 {
+					continue
+				}
+
+				seg := append([]int(nil), order[i:i+l]...)
+				rest := append(append([]int(nil), order[:i]...), order[i+l:]...)
+				p := order[best]
+				moved := make([]int, 0, n)
+				for _, u := range rest {
+					moved = append(moved, u)
+					if u == p {
+						moved = append(moved, seg...)
+					}
+				}
+				order = moved
+				improved = true
+			}
+		}
+	}
+	// Restore the starting node of t.
+	start := d.indexOf[t.Nodes[0].ID()]
+	for i, u := range order {
+		if u == start {
+			order = append(order[i:], order[:i]...)
+			break
+		}
+	}
+	return d.tour(order)
+}
+
+// LinKernighan returns the tour obtained from t by Lin–Kernighan local
+// search on the undirected graph g as described in Lin and Kernighan
+// doi:10.1287/opre.21.2.498. Each move is a sequence of up to 50 exchanges
+// in which a tour edge is replaced while the cumulative gain is positive,
+// keeping the prefix of the sequence with the greatest reduction in tour
+// weight. Each exchange is performed as a 2-opt move and only the 10
+// nearest neighbours of each node are considered as new edge endpoints.
+// The returned tour starts at the same node as t.
+//
+// LinKernighan will panic if t is not a tour of all the nodes of g.
+func LinKernighan(g graph.WeightedUndirected, t Tour) Tour {
+	d := newDistances(g)
+	order := d.indices(t)
+	n := len(order)
+	if n < 4 {
+		return d.tour(order)
+	}
+	lk := newLinKernighan(d, order)
+	for improved := true; improved; {
+		improved = false
+		for t1 := 0; t1 < n; t1++ {
+			for dir := 0; dir < 2; dir++ {
+				if lk.move(t1) > tol {
+					improved = true
+				}
+				lk.reverse()
+			}
+		}
+	}
+
+	// Restore the starting node of t.
+	start := d.indexOf[t.Nodes[0].ID()]
+	i := lk.pos[start]
+	order = append(lk.tour[i:], lk.tour[:i]...)
+	return d.tour(order)
+}
+
+// linKernighan holds the state of a Lin–Kernighan search.
+type linKernighan struct {
+	d    [][]float64
+	tour []int
+	pos  []int
+
+	// near holds the nearest neighbours
+	// of each node in order of distance.
+	near [][]int
+
+	used  []bool
+	flips [][2]int
+}
+
+func newLinKernighan(d distances, order []int) *linKernighan {
+	n := len(order)
+	lk := &linKernighan{
+		d:    d.d,
+		tour: order,
+		pos:  make([]int, n),
+		near: make([][]int, n),
+		used: make([]bool, n),
+	}
+	for i, u := range order {
+		lk.pos[u] = i
+	}
+	k := lkCandidates
+	if k > n-1 {
+		k = n - 1
+	}
+	for u := range lk.near {
+		near := make([]int, 0, n-1)
+		for v := 0; v < n; v++ {
+			if v != u {
+				near = append(near, v)
+			}
+		}
+		sort.SliceStable(near, func(i, j int) bool { return d.d[u][near[i]] < d.d[u][near[j]] })
+		lk.near[u] = near[:k]
+	}
+	return lk
+}
+
+func (lk *linKernighan) succ(u int) int { return lk.tour[(lk.pos[u]+1)%len(lk.tour)] }
+func (lk *linKernighan) pred(u int) int { return lk.tour[(lk.pos[u]-1+len(lk.tour))%len(lk.tour)] }
+
+// move performs the best sequential exchange starting with the removal of
+// the tour edge from t1 to its successor and returns the reduction in tour
+// weight. The tour is left unchanged if no exchange reduces its weight.
+func (lk *linKernighan) move(t1 int) float64 {
+	d := lk.d
+	g := d[t1][lk.succ(t1)]
+	var best float64
+	bestLen := 0
+	lk.flips = lk.flips[:0]
+	for depth := 0; depth < lkMaxDepth; depth++ {
+		t2 := lk.succ(t1)
+
+		// Choose the new neighbour t4 of t2 that
+		// maximises the gain after removing the edge
+		// from t4's predecessor t3 to t4.
+		t4 := -1
+		var val float64
+		for _, c := range lk.near[t2] {
+			gain := g - d[t2][c]
+			if gain <= 0 {
+				break
+			}
+			if c == t1 || c == lk.succ(t2) || lk.used[c] {
+				continue
+			}
+			if v := gain + d[lk.pred(c)][c]; t4 < 0 || v > val {
+				t4, val = c, v
+			}
+		}
+		if t4 < 0 {
+			break
+		}
+		t3 := lk.pred(t4)
+
+		// Replace the edges t1-t2 and t3-t4
+		// with t1-t3 and t2-t4.
+		lk.flip(lk.pos[t2], lk.pos[t3])
+		lk.used[t4] = true
+		g += d[t3][t4] - d[t2][t4]
+		if gain := g - d[t1][t3]; gain > best {
+			best = gain
+			bestLen = len(lk.flips)
+		}
+	}
+
+	// Undo the exchanges after the
+	// best prefix of the sequence.
+	for i := len(lk.flips) - 1; i >= bestLen; i-- {
+		lk.reverseSegment(lk.flips[i][0], lk.flips[i][1])
+	}
+	for i := range lk.used {
+		lk.used[i] = false
+	}
+	lk.flips = lk.flips[:0]
+	return best
+}
+
+// flip reverses the segment of the tour from position i to position j
+// and records the flip.
+func (lk *linKernighan) flip(i, j int) {
+	lk.reverseSegment(i, j)
+	lk.flips = append(lk.flips, [2]int{i, j})
+}
+
+// reverseSegment reverses the segment of the tour from position i to
+// position j inclusive, wrapping around the end of the tour.
+func (lk *linKernighan) reverseSegment(i, j int) {
+	n := len(lk.tour)
+	l := (j-i+n)%n + 1
+	for k := 0; k < l/2; k++ {
+		a, b := (i+k)%n, (j-k+n)%n
+		lk.tour[a], lk.tour[b] = lk.tour[b], lk.tour[a]
+		lk.pos[lk.tour[a]] = a
+		lk.pos[lk.tour[b]] = b
+	}
+}
+
+// reverse reverses the orientation of the tour.
+func (lk *linKernighan) reverse() {
+	reverseInts(lk.tour)
+	for i, u := range lk.tour {
+		lk.pos[u] = i
+	}
+}
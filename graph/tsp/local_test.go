@@ -0,0 +1,110 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tsp
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+func TestLocalSearch(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name   string
+		search func(g *simple.WeightedUndirectedGraph, t Tour) Tour
+	}{
+		{name: "2-opt", search: func(g *simple.WeightedUndirectedGraph, t Tour) Tour { return TwoOpt(g, t) }},
+		{name: "Or-opt", search: func(g *simple.WeightedUndirectedGraph, t Tour) Tour { return OrOpt(g, t) }},
+		{name: "Lin-Kernighan", search: func(g *simple.WeightedUndirectedGraph, t Tour) Tour { return LinKernighan(g, t) }},
+	} {
+		rnd := rand.New(rand.NewSource(1))
+		for i := 0; i < 20; i++ {
+			n := 1 + rnd.Intn(10)
+			g := euclidean(n, rnd)
+			start := randomTour(g, rnd)
+			got := test.search(g, start)
+			checkTour(t, test.name, g, got)
+			if got.Weight > start.Weight+1e-12 {
+				t.Errorf("%s increased tour weight for test %d: %v > %v", test.name, i, got.Weight, start.Weight)
+			}
+			if got.Nodes[0].ID() != start.Nodes[0].ID() {
+				t.Errorf("%s changed start node for test %d: got:%d want:%d", test.name, i, got.Nodes[0].ID(), start.Nodes[0].ID())
+			}
+		}
+	}
+}
+
+func TestTwoOptLocalOptimum(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		g := euclidean(30, rnd)
+		got := TwoOpt(g, randomTour(g, rnd))
+		d := newDistances(g)
+		order := d.indices(got)
+		n := len(order)
+		for a := 0; a < n; a++ {
+			for b := a + 2; b < n; b++ {
+				if a == 0 && b == n-1 {
+					continue
+				}
+				u, v, x, y := order[a], order[a+1], order[b], order[(b+1)%n]
+				if delta := d.d[u][x] + d.d[v][y] - d.d[u][v] - d.d[x][y]; delta < -1e-9 {
+					t.Errorf("improving 2-opt move remains for test %d: %v", i, delta)
+				}
+			}
+		}
+	}
+}
+
+func TestLinKernighanQuality(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 10; i++ {
+		g := euclidean(11, rnd)
+		opt := HeldKarp(g).Weight
+		got := LinKernighan(g, NearestNeighbour(g, simple.Node(0)))
+		// Lin-Kernighan is expected to find tours
+		// very close to optimal on small instances.
+		if got.Weight > 1.05*opt {
+			t.Errorf("poor Lin-Kernighan tour for test %d: got:%v optimum:%v", i, got.Weight, opt)
+		}
+	}
+}
+
+func TestOrOptDirected(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		g := randomDirected(8, rnd)
+		start := randomTour(g, rnd)
+		got := OrOpt(g, start)
+		checkTour(t, "directed Or-opt", g, got)
+		if got.Weight > start.Weight {
+			t.Errorf("Or-opt increased tour weight for test %d: %v > %v", i, got.Weight, start.Weight)
+		}
+	}
+}
+
+// randomTour returns a random tour of the nodes of g.
+func randomTour(g graph.Weighted, rnd *rand.Rand) Tour {
+	nodes := graph.NodesOf(g.Nodes())
+	rnd.Shuffle(len(nodes), func(i, j int) { nodes[i], nodes[j] = nodes[j], nodes[i] })
+	return Tour{Nodes: nodes, Weight: tourWeight(g, nodes)}
+}
+
+func BenchmarkLinKernighan(b *testing.B) {
+	rnd := rand.New(rand.NewSource(1))
+	g := euclidean(200, rnd)
+	t := randomTour(g, rnd)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		LinKernighan(g, t)
+	}
+}
@@ -0,0 +1,662 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tsp
+
+import "math"
+
+// minWeightPerfectMatching returns a minimum weight perfect matching of
+// the complete graph with edge weights given by the symmetric matrix d
+// indexed by nodes. The matching is returned as the mate of each node.
+// The number of nodes must be even.
+func minWeightPerfectMatching(nodes []int, d [][]float64) []int {
+	n := len(nodes)
+	if n == 0 {
+		return nil
+	}
+	var max float64
+	for i, u := range nodes {
+		for _, v := range nodes[i+1:] {
+			max = math.Max(max, d[u][v])
+		}
+	}
+
+	// A maximum cardinality matching maximising the
+	// weight max+1-d is a minimum weight perfect
+	// matching for d.
+	edges := make([]matchEdge, 0, n*(n-1)/2)
+	for i, u := range nodes {
+		for j := i + 1; j < n; j++ {
+			edges = append(edges, matchEdge{i: i, j: j, weight: max + 1 - d[u][nodes[j]]})
+		}
+	}
+	mate := newBlossomMatcher(n, edges, true).match()
+	for i, m := range mate {
+		if m < 0 {
+			panic("tsp: no perfect matching")
+		}
+		mate[i] = nodes[m]
+	}
+	return mate
+}
+
+// matchEdge is a weighted edge of a graph to be matched.
+type matchEdge struct {
+	i, j   int
+	weight float64
+}
+
+// blossomMatcher finds a maximum weight matching of a general graph using
+// Edmonds' blossom algorithm with the primal-dual method as described in
+// Galil "Efficient algorithms for finding maximum matching in graphs"
+// doi:10.1145/6462.6502. The implementation follows the O(n^3) formulation
+// of Van Rantwijk's mwmatching.
+//
+// Edge endpoints are numbered so that endpoint p is the vertex
+// endpoint[p] of edge p/2, and p^1 is the other endpoint of that edge.
+// Vertices are numbered 0 to n-1 and non-trivial blossoms n to 2n-1.
+type blossomMatcher struct {
+	n     int
+	edges []matchEdge
+
+	maxCardinality bool
+
+	endpoint  []int
+	neighbend [][]int
+
+	mate []int
+
+	// label is 0 for unlabelled, 1 for S, 2 for T
+	// and has bit 4 set during blossom scanning.
+	label    []int
+	labelend []int
+
+	inblossom        []int
+	blossomparent    []int
+	blossomchilds    [][]int
+	blossombase      []int
+	blossomendps     [][]int
+	bestedge         []int
+	blossombestedges [][]int
+	unusedblossoms   []int
+
+	dualvar   []float64
+	allowedge []bool
+	queue     []int
+}
+
+func newBlossomMatcher(n int, edges []matchEdge, maxCardinality bool) *blossomMatcher {
+	var max float64
+	for _, e := range edges {
+		max = math.Max(max, e.weight)
+	}
+	m := &blossomMatcher{
+		n:              n,
+		edges:          edges,
+		maxCardinality: maxCardinality,
+
+		endpoint:  make([]int, 2*len(edges)),
+		neighbend: make([][]int, n),
+
+		mate:             make([]int, n),
+		label:            make([]int, 2*n),
+		labelend:         make([]int, 2*n),
+		inblossom:        make([]int, n),
+		blossomparent:    make([]int, 2*n),
+		blossomchilds:    make([][]int, 2*n),
+		blossombase:      make([]int, 2*n),
+		blossomendps:     make([][]int, 2*n),
+		bestedge:         make([]int, 2*n),
+		blossombestedges: make([][]int, 2*n),
+		dualvar:          make([]float64, 2*n),
+		allowedge:        make([]bool, len(edges)),
+	}
+	for k, e := range edges {
+		m.endpoint[2*k] = e.i
+		m.endpoint[2*k+1] = e.j
+		m.neighbend[e.i] = append(m.neighbend[e.i], 2*k+1)
+		m.neighbend[e.j] = append(m.neighbend[e.j], 2*k)
+	}
+	for i := 0; i < 2*n; i++ {
+		m.labelend[i] = -1
+		m.blossomparent[i] = -1
+		m.bestedge[i] = -1
+		if i < n {
+			m.mate[i] = -1
+			m.inblossom[i] = i
+			m.blossombase[i] = i
+			m.dualvar[i] = max
+		} else {
+			m.blossombase[i] = -1
+			m.unusedblossoms = append(m.unusedblossoms, i)
+		}
+	}
+	return m
+}
+
+// slack returns the slack of edge k.
+func (m *blossomMatcher) slack(k int) float64 {
+	e := m.edges[k]
+	return m.dualvar[e.i] + m.dualvar[e.j] - 2*e.weight
+}
+
+// leaves returns the vertices contained in blossom b.
+func (m *blossomMatcher) leaves(b int) []int {
+	if b < m.n {
+		return []int{b}
+	}
+	var l []int
+	for _, t := range m.blossomchilds[b] {
+		if t < m.n {
+			l = append(l, t)
+		} else {
+			l = append(l, m.leaves(t)...)
+		}
+	}
+	return l
+}
+
+// assignLabel assigns label t to the top-level blossom containing vertex w
+// reached through the edge with remote endpoint p.
+func (m *blossomMatcher) assignLabel(w, t, p int) {
+	b := m.inblossom[w]
+	m.label[w], m.label[b] = t, t
+	m.labelend[w], m.labelend[b] = p, p
+	m.bestedge[w], m.bestedge[b] = -1, -1
+	switch t {
+	case 1:
+		m.queue = append(m.queue, m.leaves(b)...)
+	case 2:
+		base := m.blossombase[b]
+		m.assignLabel(m.endpoint[m.mate[base]], 1, m.mate[base]^1)
+	}
+}
+
+// scanBlossom traces back from vertices v and w to discover either a new
+// blossom or an augmenting path. It returns the base vertex of the new
+// blossom or -1.
+func (m *blossomMatcher) scanBlossom(v, w int) int {
+	var path []int
+	base := -1
+	for v != -1 || w != -1 {
+		b := m.inblossom[v]
+		if m.label[b]&4 != 0 {
+			base = m.blossombase[b]
+			break
+		}
+		path = append(path, b)
+		m.label[b] = 5
+		if m.labelend[b] == -1 {
+			v = -1
+		} else {
+			v = m.endpoint[m.labelend[b]]
+			b = m.inblossom[v]
+			v = m.endpoint[m.labelend[b]]
+		}
+		if w != -1 {
+			v, w = w, v
+		}
+	}
+	for _, b := range path {
+		m.label[b] = 1
+	}
+	return base
+}
+
+// addBlossom constructs a new blossom with the given base, containing
+// edge k which connects a pair of S vertices.
+func (m *blossomMatcher) addBlossom(base, k int) {
+	v, w := m.edges[k].i, m.edges[k].j
+	bb := m.inblossom[base]
+	bv := m.inblossom[v]
+	bw := m.inblossom[w]
+
+	b := m.unusedblossoms[len(m.unusedblossoms)-1]
+	m.unusedblossoms = m.unusedblossoms[:len(m.unusedblossoms)-1]
+	m.blossombase[b] = base
+	m.blossomparent[b] = -1
+	m.blossomparent[bb] = b
+
+	var path, endps []int
+	for bv != bb {
+		m.blossomparent[bv] = b
+		path = append(path, bv)
+		endps = append(endps, m.labelend[bv])
+		v = m.endpoint[m.labelend[bv]]
+		bv = m.inblossom[v]
+	}
+	path = append(path, bb)
+	reverseInts(path)
+	reverseInts(endps)
+	endps = append(endps, 2*k)
+	for bw != bb {
+		m.blossomparent[bw] = b
+		path = append(path, bw)
+		endps = append(endps, m.labelend[bw]^1)
+		w = m.endpoint[m.labelend[bw]]
+		bw = m.inblossom[w]
+	}
+	m.blossomchilds[b] = path
+	m.blossomendps[b] = endps
+
+	m.label[b] = 1
+	m.labelend[b] = m.labelend[bb]
+	m.dualvar[b] = 0
+	for _, v := range m.leaves(b) {
+		if m.label[m.inblossom[v]] == 2 {
+			m.queue = append(m.queue, v)
+		}
+		m.inblossom[v] = b
+	}
+
+	// Compute the least-slack edges to
+	// neighbouring S blossoms.
+	bestedgeto := make([]int, 2*m.n)
+	for i := range bestedgeto {
+		bestedgeto[i] = -1
+	}
+	for _, bv := range path {
+		var nblists [][]int
+		if m.blossombestedges[bv] == nil {
+			for _, v := range m.leaves(bv) {
+				nblist := make([]int, len(m.neighbend[v]))
+				for i, p := range m.neighbend[v] {
+					nblist[i] = p / 2
+				}
+				nblists = append(nblists, nblist)
+			}
+		} else {
+			nblists = [][]int{m.blossombestedges[bv]}
+		}
+		for _, nblist := range nblists {
+			for _, k := range nblist {
+				j := m.edges[k].j
+				if m.inblossom[j] == b {
+					j = m.edges[k].i
+				}
+				bj := m.inblossom[j]
+				if bj != b && m.label[bj] == 1 && (bestedgeto[bj] == -1 || m.slack(k) < m.slack(bestedgeto[bj])) {
+					bestedgeto[bj] = k
+				}
+			}
+		}
+		m.blossombestedges[bv] = nil
+		m.bestedge[bv] = -1
+	}
+	var best []int
+	for _, k := range bestedgeto {
+		if k != -1 {
+			best = append(best, k)
+		}
+	}
+	m.blossombestedges[b] = best
+	m.bestedge[b] = -1
+	for _, k := range best {
+		if m.bestedge[b] == -1 || m.slack(k) < m.slack(m.bestedge[b]) {
+			m.bestedge[b] = k
+		}
+	}
+}
+
+// expandBlossom expands the blossom b, relabelling its sub-blossoms if
+// the expansion is not at the end of a stage.
+func (m *blossomMatcher) expandBlossom(b int, endstage bool) {
+	for _, s := range m.blossomchilds[b] {
+		m.blossomparent[s] = -1
+		switch {
+		case s < m.n:
+			m.inblossom[s] = s
+		case endstage && m.dualvar[s] == 0:
+			m.expandBlossom(s, endstage)
+		default:
+			for _, v := range m.leaves(s) {
+				m.inblossom[v] = s
+			}
+		}
+	}
+
+	if !endstage && m.label[b] == 2 {
+		childs := m.blossomchilds[b]
+		endps := m.blossomendps[b]
+		entrychild := m.inblossom[m.endpoint[m.labelend[b]^1]]
+		j := indexOf(childs, entrychild)
+		var jstep, endptrick int
+		if j&1 != 0 {
+			j -= len(childs)
+			jstep = 1
+		} else {
+			jstep = -1
+			endptrick = 1
+		}
+		p := m.labelend[b]
+		for j != 0 {
+			m.label[m.endpoint[p^1]] = 0
+			m.label[m.endpoint[at(endps, j-endptrick)^endptrick^1]] = 0
+			m.assignLabel(m.endpoint[p^1], 2, p)
+			m.allowedge[at(endps, j-endptrick)/2] = true
+			j += jstep
+			p = at(endps, j-endptrick) ^ endptrick
+			m.allowedge[p/2] = true
+			j += jstep
+		}
+		bv := at(childs, j)
+		m.label[m.endpoint[p^1]], m.label[bv] = 2, 2
+		m.labelend[m.endpoint[p^1]], m.labelend[bv] = p, p
+		m.bestedge[bv] = -1
+		j += jstep
+		for at(childs, j) != entrychild {
+			bv := at(childs, j)
+			if m.label[bv] == 1 {
+				j += jstep
+				continue
+			}
+			v := -1
+			for _, l := range m.leaves(bv) {
+				if m.label[l] != 0 {
+					v = l
+					break
+				}
+			}
+			if v >= 0 {
+				m.label[v] = 0
+				m.label[m.endpoint[m.mate[m.blossombase[bv]]]] = 0
+				m.assignLabel(v, 2, m.labelend[v])
+			}
+			j += jstep
+		}
+	}
+
+	m.label[b], m.labelend[b] = -1, -1
+	m.blossomchilds[b], m.blossomendps[b] = nil, nil
+	m.blossombase[b] = -1
+	m.blossombestedges[b] = nil
+	m.bestedge[b] = -1
+	m.unusedblossoms = append(m.unusedblossoms, b)
+}
+
+// augmentBlossom swaps matched and unmatched edges over an alternating
+// path through blossom b between vertex v and the base vertex.
+func (m *blossomMatcher) augmentBlossom(b, v int) {
+	t := v
+	for m.blossomparent[t] != b {
+		t = m.blossomparent[t]
+	}
+	if t >= m.n {
+		m.augmentBlossom(t, v)
+	}
+	childs := m.blossomchilds[b]
+	endps := m.blossomendps[b]
+	i := indexOf(childs, t)
+	j := i
+	var jstep, endptrick int
+	if i&1 != 0 {
+		j -= len(childs)
+		jstep = 1
+	} else {
+		jstep = -1
+		endptrick = 1
+	}
+	for j != 0 {
+		j += jstep
+		t = at(childs, j)
+		p := at(endps, j-endptrick) ^ endptrick
+		if t >= m.n {
+			m.augmentBlossom(t, m.endpoint[p])
+		}
+		j += jstep
+		t = at(childs, j)
+		if t >= m.n {
+			m.augmentBlossom(t, m.endpoint[p^1])
+		}
+		m.mate[m.endpoint[p]] = p ^ 1
+		m.mate[m.endpoint[p^1]] = p
+	}
+	m.blossomchilds[b] = append(append([]int(nil), childs[i:]...), childs[:i]...)
+	m.blossomendps[b] = append(append([]int(nil), endps[i:]...), endps[:i]...)
+	m.blossombase[b] = m.blossombase[m.blossomchilds[b][0]]
+}
+
+// augmentMatching swaps matched and unmatched edges over an alternating
+// path between two single vertices through edge k.
+func (m *blossomMatcher) augmentMatching(k int) {
+	v, w := m.edges[k].i, m.edges[k].j
+	for _, sp := range [2][2]int{{v, 2*k + 1}, {w, 2 * k}} {
+		s, p := sp[0], sp[1]
+		for {
+			bs := m.inblossom[s]
+			if bs >= m.n {
+				m.augmentBlossom(bs, s)
+			}
+			m.mate[s] = p
+			if m.labelend[bs] == -1 {
+				break
+			}
+			t := m.endpoint[m.labelend[bs]]
+			bt := m.inblossom[t]
+			s = m.endpoint[m.labelend[bt]]
+			j := m.endpoint[m.labelend[bt]^1]
+			if bt >= m.n {
+				m.augmentBlossom(bt, j)
+			}
+			m.mate[j] = m.labelend[bt]
+			p = m.labelend[bt] ^ 1
+		}
+	}
+}
+
+// match returns the mate of each vertex in a maximum weight matching, or
+// -1 for unmatched vertices.
+func (m *blossomMatcher) match() []int {
+	n := m.n
+	for stage := 0; stage < n; stage++ {
+		for i := range m.label {
+			m.label[i] = 0
+			m.bestedge[i] = -1
+		}
+		for i := n; i < 2*n; i++ {
+			m.blossombestedges[i] = nil
+		}
+		for i := range m.allowedge {
+			m.allowedge[i] = false
+		}
+		m.queue = m.queue[:0]
+		for v := 0; v < n; v++ {
+			if m.mate[v] == -1 && m.label[m.inblossom[v]] == 0 {
+				m.assignLabel(v, 1, -1)
+			}
+		}
+
+		augmented := false
+		for {
+			for len(m.queue) != 0 && !augmented {
+				v := m.queue[len(m.queue)-1]
+				m.queue = m.queue[:len(m.queue)-1]
+				for _, p := range m.neighbend[v] {
+					k := p / 2
+					w := m.endpoint[p]
+					if m.inblossom[v] == m.inblossom[w] {
+						continue
+					}
+					var kslack float64
+					if !m.allowedge[k] {
+						kslack = m.slack(k)
+						if kslack <= 0 {
+							m.allowedge[k] = true
+						}
+					}
+					switch {
+					case m.allowedge[k]:
+						switch {
+						case m.label[m.inblossom[w]] == 0:
+							m.assignLabel(w, 2, p^1)
+						case m.label[m.inblossom[w]] == 1:
+							base := m.scanBlossom(v, w)
+							if base >= 0 {
+								m.addBlossom(base, k)
+							} else {
+								m.augmentMatching(k)
+								augmented = true
+							}
+						case m.label[w] == 0:
+							m.label[w] = 2
+							m.labelend[w] = p ^ 1
+						}
+					case m.label[m.inblossom[w]] == 1:
+						b := m.inblossom[v]
+						if m.bestedge[b] == -1 || kslack < m.slack(m.bestedge[b]) {
+							m.bestedge[b] = k
+						}
+					case m.label[w] == 0:
+						if m.bestedge[w] == -1 || kslack < m.slack(m.bestedge[w]) {
+							m.bestedge[w] = k
+						}
+					}
+					if augmented {
+						break
+					}
+				}
+			}
+			if augmented {
+				break
+			}
+
+			// Compute the dual variable update.
+			deltatype := -1
+			var delta float64
+			deltaedge, deltablossom := -1, -1
+			if !m.maxCardinality {
+				deltatype = 1
+				delta = minFloat(m.dualvar[:n])
+			}
+			for v := 0; v < n; v++ {
+				if m.label[m.inblossom[v]] == 0 && m.bestedge[v] != -1 {
+					d := m.slack(m.bestedge[v])
+					if deltatype == -1 || d < delta {
+						delta = d
+						deltatype = 2
+						deltaedge = m.bestedge[v]
+					}
+				}
+			}
+			for b := 0; b < 2*n; b++ {
+				if m.blossomparent[b] == -1 && m.label[b] == 1 && m.bestedge[b] != -1 {
+					d := m.slack(m.bestedge[b]) / 2
+					if deltatype == -1 || d < delta {
+						delta = d
+						deltatype = 3
+						deltaedge = m.bestedge[b]
+					}
+				}
+			}
+			for b := n; b < 2*n; b++ {
+				if m.blossombase[b] >= 0 && m.blossomparent[b] == -1 && m.label[b] == 2 && (deltatype == -1 || m.dualvar[b] < delta) {
+					delta = m.dualvar[b]
+					deltatype = 4
+					deltablossom = b
+				}
+			}
+			if deltatype == -1 {
+				// No further improvement is possible
+				// with a maximum cardinality matching.
+				deltatype = 1
+				delta = math.Max(0, minFloat(m.dualvar[:n]))
+			}
+
+			for v := 0; v < n; v++ {
+				switch m.label[m.inblossom[v]] {
+				case 1:
+					m.dualvar[v] -= delta
+				case 2:
+					m.dualvar[v] += delta
+				}
+			}
+			for b := n; b < 2*n; b++ {
+				if m.blossombase[b] >= 0 && m.blossomparent[b] == -1 {
+					switch m.label[b] {
+					case 1:
+						m.dualvar[b] += delta
+					case 2:
+						m.dualvar[b] -= delta
+					}
+				}
+			}
+
+			switch deltatype {
+			case 1:
+				// The optimum has been reached.
+			case 2:
+				m.allowedge[deltaedge] = true
+				i := m.edges[deltaedge].i
+				if m.label[m.inblossom[i]] == 0 {
+					i = m.edges[deltaedge].j
+				}
+				m.queue = append(m.queue, i)
+			case 3:
+				m.allowedge[deltaedge] = true
+				m.queue = append(m.queue, m.edges[deltaedge].i)
+			case 4:
+				m.expandBlossom(deltablossom, false)
+			}
+			if deltatype == 1 {
+				break
+			}
+		}
+		if !augmented {
+			break
+		}
+
+		// Expand all S blossoms with zero dual
+		// at the end of the stage.
+		for b := n; b < 2*n; b++ {
+			if m.blossomparent[b] == -1 && m.blossombase[b] >= 0 && m.label[b] == 1 && m.dualvar[b] == 0 {
+				m.expandBlossom(b, true)
+			}
+		}
+	}
+
+	mate := make([]int, n)
+	for v, p := range m.mate {
+		if p >= 0 {
+			mate[v] = m.endpoint[p]
+		} else {
+			mate[v] = -1
+		}
+	}
+	return mate
+}
+
+// at returns the element of s at index i, counting
+// from the end of s if i is negative.
+func at(s []int, i int) int {
+	if i < 0 {
+		i += len(s)
+	}
+	return s[i]
+}
+
+// indexOf returns the index of v in s.
+func indexOf(s []int, v int) int {
+	for i, e := range s {
+		if e == v {
+			return i
+		}
+	}
+	panic("tsp: missing blossom child")
+}
+
+// minFloat returns the minimum value in s.
+func minFloat(s []float64) float64 {
+	min := math.Inf(1)
+	for _, v := range s {
+		min = math.Min(min, v)
+	}
+	return min
+}
+
+// reverseInts reverses the order of elements in s.
+func reverseInts(s []int) {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tsp
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestMinWeightPerfectMatching(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for test := 0; test < 200; test++ {
+		n := 2 * (1 + rnd.Intn(8))
+		d := make([][]float64, n)
+		for i := range d {
+			d[i] = make([]float64, n)
+		}
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				var w float64
+				if test%2 == 0 {
+					// Use integer weights to
+					// exercise ties.
+					w = float64(rnd.Intn(5))
+				} else {
+					w = rnd.Float64()
+				}
+				d[i][j], d[j][i] = w, w
+			}
+		}
+		nodes := make([]int, n)
+		for i := range nodes {
+			nodes[i] = i
+		}
+
+		mate := minWeightPerfectMatching(nodes, d)
+		var got float64
+		for i, m := range mate {
+			if mate[m] != i || m == i {
+				t.Fatalf("invalid matching for test %d: %v", test, mate)
+			}
+			if i < m {
+				got += d[i][m]
+			}
+		}
+		if want := bruteForceMatching(d, 0, make(map[int]float64)); math.Abs(got-want) > 1e-12 {
+			t.Errorf("unexpected matching weight for test %d: got:%v want:%v", test, got, want)
+		}
+	}
+}
+
+// bruteForceMatching returns the minimum weight of a perfect matching of
+// the nodes not in the matched set.
+func bruteForceMatching(d [][]float64, matched int, memo map[int]float64) float64 {
+	i := 0
+	for i < len(d) && matched&(1<<uint(i)) != 0 {
+		i++
+	}
+	if i == len(d) {
+		return 0
+	}
+	if w, ok := memo[matched]; ok {
+		return w
+	}
+	best := math.Inf(1)
+	for j := i + 1; j < len(d); j++ {
+		if matched&(1<<uint(j)) != 0 {
+			continue
+		}
+		best = math.Min(best, d[i][j]+bruteForceMatching(d, matched|1<<uint(i)|1<<uint(j), memo))
+	}
+	memo[matched] = best
+	return best
+}
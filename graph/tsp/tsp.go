@@ -0,0 +1,225 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tsp
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+)
+
+// heldKarpMaxNodes is the largest number of nodes accepted by HeldKarp.
+const heldKarpMaxNodes = 20
+
+// tol is the minimum reduction in tour weight accepted by local search.
+const tol = 1e-10
+
+// Tour is a closed tour through the nodes of a graph.
+type Tour struct {
+	// Nodes holds the nodes of the tour in
+	// visiting order. The tour returns from
+	// the last node to the first.
+	Nodes []graph.Node
+
+	// Weight is the total weight of the
+	// edges of the tour.
+	Weight float64
+}
+
+// distances holds the edge weights of a graph as a dense matrix.
+type distances struct {
+	nodes   []graph.Node
+	indexOf map[int64]int
+
+	// d[i][j] is the weight of the edge from node
+	// i to node j, or +Inf if there is no edge.
+	d [][]float64
+}
+
+// newDistances returns the edge weights of g. The nodes are ordered by ID.
+func newDistances(g graph.Weighted) distances {
+	nodes := graph.NodesOf(g.Nodes())
+	sort.Sort(ordered.ByID(nodes))
+	indexOf := make(map[int64]int, len(nodes))
+	for i, u := range nodes {
+		indexOf[u.ID()] = i
+	}
+	d := make([][]float64, len(nodes))
+	for i, u := range nodes {
+		d[i] = make([]float64, len(nodes))
+		for j, v := range nodes {
+			if i == j {
+				continue
+			}
+			w, ok := g.Weight(u.ID(), v.ID())
+			if !ok {
+				w = math.Inf(1)
+			}
+			d[i][j] = w
+		}
+	}
+	return distances{nodes: nodes, indexOf: indexOf, d: d}
+}
+
+// tour returns the Tour visiting the nodes with the given indices.
+func (d distances) tour(order []int) Tour {
+	t := Tour{Nodes: make([]graph.Node, len(order))}
+	for i, u := range order {
+		t.Nodes[i] = d.nodes[u]
+	}
+	t.Weight = d.weight(order)
+	return t
+}
+
+// weight returns the weight of the closed tour visiting the nodes with
+// the given indices.
+func (d distances) weight(order []int) float64 {
+	if len(order) < 2 {
+		return 0
+	}
+	var w float64
+	for i, u := range order {
+		w += d.d[u][order[(i+1)%len(order)]]
+	}
+	return w
+}
+
+// indices returns the node indices of the nodes of t. It panics if t is
+// not a tour of all the nodes.
+func (d distances) indices(t Tour) []int {
+	if len(t.Nodes) != len(d.nodes) {
+		panic("tsp: tour does not visit all nodes")
+	}
+	order := make([]int, len(t.Nodes))
+	seen := make([]bool, len(d.nodes))
+	for i, u := range t.Nodes {
+		j, ok := d.indexOf[u.ID()]
+		if !ok || seen[j] {
+			panic("tsp: tour does not visit all nodes")
+		}
+		seen[j] = true
+		order[i] = j
+	}
+	return order
+}
+
+// NearestNeighbour returns a tour of all the nodes of g constructed by
+// starting at the start node and repeatedly moving to the nearest unvisited
+// node, breaking ties by lowest node ID. If the graph is not complete, the
+// tour may include missing edges and so have infinite weight.
+//
+// NearestNeighbour will panic if start is not a node in g.
+func NearestNeighbour(g graph.Weighted, start graph.Node) Tour {
+	d := newDistances(g)
+	u, ok := d.indexOf[start.ID()]
+	if !ok {
+		panic("tsp: start node not in graph")
+	}
+
+	visited := make([]bool, len(d.nodes))
+	visited[u] = true
+	order := []int{u}
+	for len(order) < len(d.nodes) {
+		next := -1
+		for v, w := range d.d[u] {
+			if !visited[v] && (next < 0 || w < d.d[u][next]) {
+				next = v
+			}
+		}
+		visited[next] = true
+		order = append(order, next)
+		u = next
+	}
+	return d.tour(order)
+}
+
+// HeldKarp returns a minimum weight tour of all the nodes of g found by the
+// dynamic programming algorithm of Held and Karp doi:10.1137/0110015. The
+// tour starts at the node with the lowest ID. If g has no tour visiting all
+// the nodes, the returned tour has infinite weight. The graph g may be
+// directed.
+//
+// The time complexity of HeldKarp is O(2^n.n^2) and the space complexity
+// is O(2^n.n) for a graph with n nodes. HeldKarp will panic if g has more
+// than 20 nodes.
+func HeldKarp(g graph.Weighted) Tour {
+	d := newDistances(g)
+	n := len(d.nodes)
+	if n > heldKarpMaxNodes {
+		panic("tsp: too many nodes for Held-Karp")
+	}
+	if n < 3 {
+		order := make([]int, n)
+		for i := range order {
+			order[i] = i
+		}
+		return d.tour(order)
+	}
+
+	// cost[s*m+j] is the minimum weight of a path from
+	// node 0 through the set of nodes s ending at node
+	// j+1, where nodes 1 to n-1 are represented by bits
+	// 0 to m-1 of s.
+	m := n - 1
+	cost := make([]float64, (1<<uint(m))*m)
+	parent := make([]int8, len(cost))
+	for i := range cost {
+		cost[i] = math.Inf(1)
+	}
+	for j := 0; j < m; j++ {
+		cost[(1<<uint(j))*m+j] = d.d[0][j+1]
+		parent[(1<<uint(j))*m+j] = -1
+	}
+	for s := 1; s < 1<<uint(m); s++ {
+		for j := 0; j < m; j++ {
+			if s&(1<<uint(j)) == 0 {
+				continue
+			}
+			c := cost[s*m+j]
+			if math.IsInf(c, 1) {
+				continue
+			}
+			for k := 0; k < m; k++ {
+				if s&(1<<uint(k)) != 0 {
+					continue
+				}
+				t := (s|1<<uint(k))*m + k
+				if w := c + d.d[j+1][k+1]; w < cost[t] {
+					cost[t] = w
+					parent[t] = int8(j)
+				}
+			}
+		}
+	}
+
+	full := 1<<uint(m) - 1
+	best := math.Inf(1)
+	last := 0
+	for j := 0; j < m; j++ {
+		if w := cost[full*m+j] + d.d[j+1][0]; w < best {
+			best = w
+			last = j
+		}
+	}
+	if math.IsInf(best, 1) {
+		// There is no tour, so return the
+		// nodes in order of ID.
+		order := make([]int, n)
+		for i := range order {
+			order[i] = i
+		}
+		return d.tour(order)
+	}
+	order := make([]int, n)
+	for s, j, i := full, last, n-1; i > 0; i-- {
+		order[i] = j + 1
+		p := int(parent[s*m+j])
+		s &^= 1 << uint(j)
+		j = p
+	}
+	return d.tour(order)
+}
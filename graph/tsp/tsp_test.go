@@ -0,0 +1,177 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tsp
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+func TestNearestNeighbour(t *testing.T) {
+	t.Parallel()
+	// Nodes on a line at 0, 1, 3 and 7.
+	g := simple.NewWeightedUndirectedGraph(0, math.Inf(1))
+	x := []float64{0, 1, 3, 7}
+	for i := range x {
+		for j := i + 1; j < len(x); j++ {
+			g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(i), T: simple.Node(j), W: x[j] - x[i]})
+		}
+	}
+	got := NearestNeighbour(g, simple.Node(2))
+	checkTour(t, "line", g, got)
+	want := []int64{2, 1, 0, 3}
+	for i, n := range got.Nodes {
+		if n.ID() != want[i] {
+			t.Errorf("unexpected tour: got:%v want:%v", ids(got.Nodes), want)
+			break
+		}
+	}
+	if got.Weight != 14 {
+		t.Errorf("unexpected tour weight: got:%v want:14", got.Weight)
+	}
+}
+
+func TestHeldKarp(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for test := 0; test < 50; test++ {
+		n := 1 + rnd.Intn(7)
+		var g graph.Weighted
+		if test%2 == 0 {
+			g = euclidean(n, rnd)
+		} else {
+			g = randomDirected(n, rnd)
+		}
+		got := HeldKarp(g)
+		checkTour(t, "Held-Karp", g, got)
+		if want := bruteForceTour(g); math.Abs(got.Weight-want) > 1e-12 {
+			t.Errorf("unexpected tour weight for test %d: got:%v want:%v", test, got.Weight, want)
+		}
+	}
+}
+
+func TestHeldKarpNoTour(t *testing.T) {
+	t.Parallel()
+	g := simple.NewWeightedDirectedGraph(0, math.Inf(1))
+	for _, e := range []simple.WeightedEdge{
+		{F: simple.Node(0), T: simple.Node(1), W: 1},
+		{F: simple.Node(1), T: simple.Node(2), W: 1},
+		{F: simple.Node(2), T: simple.Node(1), W: 1},
+	} {
+		g.SetWeightedEdge(e)
+	}
+	if got := HeldKarp(g); !math.IsInf(got.Weight, 1) {
+		t.Errorf("unexpected tour weight for graph without tour: got:%v want:+Inf", got.Weight)
+	}
+}
+
+// euclidean returns a complete undirected graph of n random
+// points in the unit square weighted by Euclidean distance.
+func euclidean(n int, rnd *rand.Rand) *simple.WeightedUndirectedGraph {
+	g := simple.NewWeightedUndirectedGraph(0, math.Inf(1))
+	x := make([][2]float64, n)
+	for i := range x {
+		x[i] = [2]float64{rnd.Float64(), rnd.Float64()}
+		g.AddNode(simple.Node(i))
+	}
+	for i := range x {
+		for j := i + 1; j < n; j++ {
+			w := math.Hypot(x[i][0]-x[j][0], x[i][1]-x[j][1])
+			g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(i), T: simple.Node(j), W: w})
+		}
+	}
+	return g
+}
+
+// randomDirected returns a complete directed graph with n
+// nodes and random edge weights.
+func randomDirected(n int, rnd *rand.Rand) *simple.WeightedDirectedGraph {
+	g := simple.NewWeightedDirectedGraph(0, math.Inf(1))
+	for i := 0; i < n; i++ {
+		g.AddNode(simple.Node(i))
+	}
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			if i != j {
+				g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(i), T: simple.Node(j), W: float64(rnd.Intn(20))})
+			}
+		}
+	}
+	return g
+}
+
+// checkTour checks that tour visits every node of g once and has the
+// weight of its edges.
+func checkTour(t *testing.T, name string, g graph.Weighted, tour Tour) {
+	t.Helper()
+	if len(tour.Nodes) != g.Nodes().Len() {
+		t.Errorf("unexpected number of nodes in %s tour: got:%d want:%d", name, len(tour.Nodes), g.Nodes().Len())
+		return
+	}
+	seen := make(map[int64]bool)
+	for _, n := range tour.Nodes {
+		if seen[n.ID()] || g.Node(n.ID()) == nil {
+			t.Errorf("invalid %s tour: %v", name, ids(tour.Nodes))
+			return
+		}
+		seen[n.ID()] = true
+	}
+	if w := tourWeight(g, tour.Nodes); math.Abs(w-tour.Weight) > 1e-12 {
+		t.Errorf("unexpected %s tour weight: got:%v want:%v", name, tour.Weight, w)
+	}
+}
+
+// tourWeight returns the weight of the closed tour through nodes in g.
+func tourWeight(g graph.Weighted, nodes []graph.Node) float64 {
+	if len(nodes) < 2 {
+		return 0
+	}
+	var w float64
+	for i, u := range nodes {
+		ew, ok := g.Weight(u.ID(), nodes[(i+1)%len(nodes)].ID())
+		if !ok {
+			return math.Inf(1)
+		}
+		w += ew
+	}
+	return w
+}
+
+// bruteForceTour returns the minimum tour weight of g by examining every
+// tour starting at the first node.
+func bruteForceTour(g graph.Weighted) float64 {
+	nodes := graph.NodesOf(g.Nodes())
+	best := math.Inf(1)
+	var permute func(k int)
+	permute = func(k int) {
+		if k == len(nodes) {
+			best = math.Min(best, tourWeight(g, nodes))
+			return
+		}
+		for i := k; i < len(nodes); i++ {
+			nodes[k], nodes[i] = nodes[i], nodes[k]
+			permute(k + 1)
+			nodes[k], nodes[i] = nodes[i], nodes[k]
+		}
+	}
+	if len(nodes) < 2 {
+		return 0
+	}
+	permute(1)
+	return best
+}
+
+func ids(nodes []graph.Node) []int64 {
+	id := make([]int64, len(nodes))
+	for i, n := range nodes {
+		id[i] = n.ID()
+	}
+	return id
+}
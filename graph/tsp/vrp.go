@@ -0,0 +1,112 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tsp
+
+import (
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+)
+
+// Savings returns routes for the capacitated vehicle routing problem on g
+// constructed by the parallel savings algorithm of Clarke and Wright
+// doi:10.1287/opre.12.4.568. Each route is a Tour starting at the depot
+// node that visits a set of customers whose total demand does not exceed
+// capacity. Every node of g other than the depot is a customer with demand
+// given by the demand function.
+//
+// Each customer starts on its own route and pairs of routes are merged in
+// order of decreasing saving,
+//  s(i, j) = w(i, depot) + w(depot, j) - w(i, j),
+// when customer i ends one route and customer j starts another, the saving
+// is positive and the merged route is within capacity. If g is a
+// graph.Undirected, routes may be reversed to allow a merge.
+//
+// The returned routes are ordered by the lowest ID of the customers on each
+// route. Savings will panic if depot is not a node in g or if the demand
+// of any customer exceeds capacity.
+func Savings(g graph.Weighted, depot graph.Node, demand func(graph.Node) float64, capacity float64) []Tour {
+	d := newDistances(g)
+	dep, ok := d.indexOf[depot.ID()]
+	if !ok {
+		panic("tsp: depot not in graph")
+	}
+	_, undirected := g.(graph.Undirected)
+
+	type route struct {
+		nodes []int
+		load  float64
+	}
+	n := len(d.nodes)
+	routeOf := make([]*route, n)
+	for i, u := range d.nodes {
+		if i == dep {
+			continue
+		}
+		q := demand(u)
+		if q > capacity {
+			panic("tsp: demand exceeds capacity")
+		}
+		routeOf[i] = &route{nodes: []int{i}, load: q}
+	}
+
+	type saving struct {
+		i, j int
+		s    float64
+	}
+	var savings []saving
+	for i := 0; i < n; i++ {
+		if i == dep {
+			continue
+		}
+		for j := 0; j < n; j++ {
+			if j == dep || j == i || (undirected && j < i) {
+				continue
+			}
+			if s := d.d[i][dep] + d.d[dep][j] - d.d[i][j]; s > 0 {
+				savings = append(savings, saving{i: i, j: j, s: s})
+			}
+		}
+	}
+	sort.SliceStable(savings, func(a, b int) bool { return savings[a].s > savings[b].s })
+
+	for _, s := range savings {
+		ri, rj := routeOf[s.i], routeOf[s.j]
+		if ri == rj || ri.load+rj.load > capacity {
+			continue
+		}
+		first := func(r *route, u int) bool { return r.nodes[0] == u }
+		last := func(r *route, u int) bool { return r.nodes[len(r.nodes)-1] == u }
+		if undirected {
+			// Orient the routes so that i ends
+			// ri and j starts rj.
+			if !last(ri, s.i) && first(ri, s.i) {
+				reverseInts(ri.nodes)
+			}
+			if !first(rj, s.j) && last(rj, s.j) {
+				reverseInts(rj.nodes)
+			}
+		}
+		if !last(ri, s.i) || !first(rj, s.j) {
+			continue
+		}
+		ri.nodes = append(ri.nodes, rj.nodes...)
+		ri.load += rj.load
+		for _, u := range rj.nodes {
+			routeOf[u] = ri
+		}
+	}
+
+	var routes []Tour
+	seen := make(map[*route]bool)
+	for i, r := range routeOf {
+		if i == dep || seen[r] {
+			continue
+		}
+		seen[r] = true
+		routes = append(routes, d.tour(append([]int{dep}, r.nodes...)))
+	}
+	return routes
+}
@@ -0,0 +1,120 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tsp
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+func TestSavings(t *testing.T) {
+	t.Parallel()
+	// Two clusters of customers on opposite
+	// sides of the depot at the origin.
+	g := simple.NewWeightedUndirectedGraph(0, math.Inf(1))
+	x := [][2]float64{{0, 0}, {10, 0}, {10, 1}, {11, 0}, {-10, 0}, {-10, 1}}
+	for i := range x {
+		for j := i + 1; j < len(x); j++ {
+			w := math.Hypot(x[i][0]-x[j][0], x[i][1]-x[j][1])
+			g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(i), T: simple.Node(j), W: w})
+		}
+	}
+	unit := func(graph.Node) float64 { return 1 }
+
+	routes := Savings(g, simple.Node(0), unit, 3)
+	checkRoutes(t, "clusters", g, routes, 0, unit, 3)
+	if len(routes) != 2 {
+		t.Fatalf("unexpected number of routes: got:%d want:2", len(routes))
+	}
+	for i, want := range [][]int64{{1, 2, 3}, {4, 5}} {
+		got := make(map[int64]bool)
+		for _, n := range routes[i].Nodes[1:] {
+			got[n.ID()] = true
+		}
+		for _, id := range want {
+			if !got[id] {
+				t.Errorf("unexpected customers on route %d: got:%v want:%v", i, ids(routes[i].Nodes[1:]), want)
+				break
+			}
+		}
+	}
+
+	// A capacity of one requires a
+	// route for each customer.
+	routes = Savings(g, simple.Node(0), unit, 1)
+	checkRoutes(t, "unit capacity", g, routes, 0, unit, 1)
+	if len(routes) != 5 {
+		t.Errorf("unexpected number of routes: got:%d want:5", len(routes))
+	}
+}
+
+func TestSavingsRandom(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		var g graph.Weighted
+		if i%2 == 0 {
+			g = euclidean(30, rnd)
+		} else {
+			g = randomDirected(30, rnd)
+		}
+		demands := make([]float64, 30)
+		for j := range demands {
+			demands[j] = 1 + float64(rnd.Intn(5))
+		}
+		demand := func(n graph.Node) float64 { return demands[n.ID()] }
+		routes := Savings(g, simple.Node(0), demand, 15)
+		checkRoutes(t, "random", g, routes, 0, demand, 15)
+
+		var total float64
+		for _, r := range routes {
+			total += r.Weight
+		}
+		var separate float64
+		for j := int64(1); j < 30; j++ {
+			out, _ := g.Weight(0, j)
+			back, _ := g.Weight(j, 0)
+			separate += out + back
+		}
+		if total > separate {
+			t.Errorf("savings routes heavier than separate routes for test %d: %v > %v", i, total, separate)
+		}
+	}
+}
+
+// checkRoutes checks that each customer of g is on exactly one route,
+// that routes start at the depot and are within capacity, and that the
+// route weights are correct.
+func checkRoutes(t *testing.T, name string, g graph.Weighted, routes []Tour, depot int64, demand func(graph.Node) float64, capacity float64) {
+	t.Helper()
+	seen := make(map[int64]bool)
+	for _, r := range routes {
+		if r.Nodes[0].ID() != depot {
+			t.Errorf("route does not start at depot for %q: %v", name, ids(r.Nodes))
+		}
+		var load float64
+		for _, n := range r.Nodes[1:] {
+			if seen[n.ID()] || n.ID() == depot {
+				t.Errorf("node %d visited more than once for %q", n.ID(), name)
+			}
+			seen[n.ID()] = true
+			load += demand(n)
+		}
+		if load > capacity {
+			t.Errorf("route exceeds capacity for %q: %v > %v", name, load, capacity)
+		}
+		if w := tourWeight(g, r.Nodes); math.Abs(w-r.Weight) > 1e-12 {
+			t.Errorf("unexpected route weight for %q: got:%v want:%v", name, r.Weight, w)
+		}
+	}
+	if len(seen) != g.Nodes().Len()-1 {
+		t.Errorf("unexpected number of customers on routes for %q: got:%d want:%d", name, len(seen), g.Nodes().Len()-1)
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package card
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"hash"
+	"math"
+	"math/bits"
+)
+
+// Bloom is a Bloom filter for approximate set membership, as described in
+// Bloom, "Space/time trade-offs in hash coding with allowable errors",
+// Communications of the ACM 13 (1970) 422-426. A Bloom filter never reports
+// that an item written to it is absent, but may report that an absent item
+// is present.
+type Bloom struct {
+	m uint64
+	k int
+
+	hash hash.Hash64
+
+	bits []uint64
+}
+
+// NewBloom returns a new Bloom filter with m bits and k hash functions
+// derived from h. The values of m and k must be positive.
+func NewBloom(m, k int, h hash.Hash64) (*Bloom, error) {
+	if m <= 0 || k <= 0 {
+		return nil, errors.New("card: dimension out of range")
+	}
+	return &Bloom{
+		m: uint64(m), k: k,
+		hash: h,
+		bits: make([]uint64, (m+63)/64),
+	}, nil
+}
+
+// BloomDims returns the number of bits and hash functions of a Bloom filter
+// holding n items with a false positive rate of p.
+func BloomDims(n int, p float64) (m, k int) {
+	mf := math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2))
+	k = int(math.Round(mf / float64(n) * math.Ln2))
+	if k < 1 {
+		k = 1
+	}
+	return int(mf), k
+}
+
+// Write notes the data in b as a member of the set held by the receiver.
+//
+// Write satisfies the io.Writer interface. If the hash.Hash64 type passed to
+// NewBloom satisfies the hash.Hash contract, Write will always return a nil
+// error.
+func (f *Bloom) Write(b []byte) (int, error) {
+	x, n, err := sum64(f.hash, b)
+	h1, h2 := x, x>>32|1
+	for i := 0; i < f.k; i++ {
+		idx := (h1 + uint64(i)*h2) % f.m
+		f.bits[idx/64] |= 1 << (idx % 64)
+	}
+	return n, err
+}
+
+// Contains returns whether the data in b may have been written to the
+// receiver. If Contains returns false, b has not been written.
+func (f *Bloom) Contains(b []byte) bool {
+	x, _, _ := sum64(f.hash, b)
+	h1, h2 := x, x>>32|1
+	for i := 0; i < f.k; i++ {
+		idx := (h1 + uint64(i)*h2) % f.m
+		if f.bits[idx/64]&(1<<(idx%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Count returns an estimate of the cardinality of the set of items written
+// to the receiver, using the estimator of Swamidass and Baldi.
+func (f *Bloom) Count() float64 {
+	var x int
+	for _, w := range f.bits {
+		x += bits.OnesCount64(w)
+	}
+	m := float64(f.m)
+	return -m / float64(f.k) * math.Log1p(-float64(x)/m)
+}
+
+// Union places the union of the sets held in a and b into the receiver.
+// Union will return an error if the dimensions or hash functions of a and b
+// do not match or if the receiver has a hash function that is set and does
+// not match those of a and b. Hash functions provided by hash.Hash64
+// implementations x and y match when reflect.TypeOf(x) == reflect.TypeOf(y).
+//
+// If the receiver does not have a set hash function, it can be set after
+// a call to Union with the SetHash method.
+func (f *Bloom) Union(a, b *Bloom) error {
+	if a.m != b.m || a.k != b.k {
+		return errors.New("card: mismatched dimensions")
+	}
+	err := matchHash64(f.hash, a.hash, b.hash)
+	if err != nil {
+		return err
+	}
+	if f != a && f != b {
+		*f = Bloom{m: a.m, k: a.k, hash: f.hash, bits: make([]uint64, len(a.bits))}
+	}
+	for i, w := range a.bits {
+		f.bits[i] = w | b.bits[i]
+	}
+	return nil
+}
+
+// SetHash sets the hash function of the receiver if it is nil. SetHash
+// will return an error if it is called on a receiver with a non-nil
+// hash function.
+func (f *Bloom) SetHash(fn hash.Hash64) error {
+	if f.hash != nil {
+		return errors.New("card: hash function already set")
+	}
+	f.hash = fn
+	return nil
+}
+
+// Reset clears the receiver allowing it to be reused. Reset does not alter
+// the dimensions of the receiver or the hash function that is used.
+func (f *Bloom) Reset() {
+	for i := range f.bits {
+		f.bits[i] = 0
+	}
+}
+
+// MarshalBinary marshals the filter in the receiver. It encodes the name of
+// the hash function, the dimensions of the filter and the filter data. The
+// receiver must have a non-nil hash function.
+func (f *Bloom) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	enc := gob.NewEncoder(&buf)
+	err := encodeHash64(enc, f.hash)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range []interface{}{f.m, f.k, f.bits} {
+		err = enc.Encode(v)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary unmarshals the binary representation of a filter into
+// the receiver. The dimensions of the receiver will be set after return. If
+// the receiver has a non-nil hash function value, it must be the same type
+// as the one that was stored in the binary data, otherwise the hash function
+// is obtained from the functions registered with RegisterHash.
+func (f *Bloom) UnmarshalBinary(b []byte) error {
+	dec := gob.NewDecoder(bytes.NewReader(b))
+	err := decodeHash64(dec, &f.hash)
+	if err != nil {
+		return err
+	}
+	f.bits = f.bits[:0]
+	for _, v := range []interface{}{&f.m, &f.k, &f.bits} {
+		err = dec.Decode(v)
+		if err != nil {
+			return err
+		}
+	}
+	if uint64(len(f.bits)) != (f.m+63)/64 {
+		return errors.New("card: invalid filter data")
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package card
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"hash"
+	"math"
+)
+
+// CountMin is a Count-Min sketch for estimating the frequencies of items in
+// a stream, as described in Cormode and Muthukrishnan, "An improved data
+// stream summary: the count-min sketch and its applications", Journal of
+// Algorithms 55 (2005) 58-75.
+//
+// The frequency estimates never underestimate the true frequency, and with
+// probability at least 1-δ overestimate it by at most ε times the total count
+// when the sketch has width ceil(e/ε) and depth ceil(ln(1/δ)).
+type CountMin struct {
+	width, depth int
+
+	hash hash.Hash64
+
+	total uint64
+	count []uint64
+}
+
+// NewCountMin returns a new Count-Min sketch with the given width and depth.
+// The width and depth must be positive.
+func NewCountMin(width, depth int, h hash.Hash64) (*CountMin, error) {
+	if width <= 0 || depth <= 0 {
+		return nil, errors.New("card: dimension out of range")
+	}
+	return &CountMin{
+		width: width, depth: depth,
+		hash:  h,
+		count: make([]uint64, width*depth),
+	}, nil
+}
+
+// CountMinDims returns the width and depth of a Count-Min sketch that
+// overestimates frequencies by at most eps times the total count with
+// probability at least 1-delta.
+func CountMinDims(eps, delta float64) (width, depth int) {
+	return int(math.Ceil(math.E / eps)), int(math.Ceil(math.Log(1 / delta)))
+}
+
+// Write notes the data in b as a single observation into the sketch held by
+// the receiver.
+//
+// Write satisfies the io.Writer interface. If the hash.Hash64 type passed to
+// NewCountMin satisfies the hash.Hash contract, Write will always return a
+// nil error.
+func (c *CountMin) Write(b []byte) (int, error) {
+	return c.Add(b, 1)
+}
+
+// Add notes the data in b as n observations into the sketch held by the
+// receiver.
+func (c *CountMin) Add(b []byte, n uint64) (int, error) {
+	x, written, err := sum64(c.hash, b)
+	for i := 0; i < c.depth; i++ {
+		c.count[i*c.width+c.index(x, i)] += n
+	}
+	c.total += n
+	return written, err
+}
+
+// index returns the column of row i for the hash value x using the double
+// hashing of Kirsch and Mitzenmacher.
+func (c *CountMin) index(x uint64, i int) int {
+	h1 := x & 0xffffffff
+	h2 := x >> 32
+	return int((h1 + uint64(i)*h2) % uint64(c.width))
+}
+
+// Frequency returns an estimate of the number of observations of the data
+// in b noted by the receiver.
+func (c *CountMin) Frequency(b []byte) uint64 {
+	x, _, _ := sum64(c.hash, b)
+	min := uint64(math.MaxUint64)
+	for i := 0; i < c.depth; i++ {
+		if v := c.count[i*c.width+c.index(x, i)]; v < min {
+			min = v
+		}
+	}
+	return min
+}
+
+// Total returns the total number of observations noted by the receiver.
+func (c *CountMin) Total() uint64 {
+	return c.total
+}
+
+// Union places the union of the sketches in a and b into the receiver, so
+// that the receiver's frequencies estimate those of the combined streams.
+// Union will return an error if the dimensions or hash functions of a and b
+// do not match or if the receiver has a hash function that is set and does
+// not match those of a and b. Hash functions provided by hash.Hash64
+// implementations x and y match when reflect.TypeOf(x) == reflect.TypeOf(y).
+//
+// If the receiver does not have a set hash function, it can be set after
+// a call to Union with the SetHash method.
+func (c *CountMin) Union(a, b *CountMin) error {
+	if a.width != b.width || a.depth != b.depth {
+		return errors.New("card: mismatched dimensions")
+	}
+	err := matchHash64(c.hash, a.hash, b.hash)
+	if err != nil {
+		return err
+	}
+	if c != a && c != b {
+		*c = CountMin{width: a.width, depth: a.depth, hash: c.hash, count: make([]uint64, len(a.count))}
+	}
+	for i, v := range a.count {
+		c.count[i] = v + b.count[i]
+	}
+	c.total = a.total + b.total
+	return nil
+}
+
+// SetHash sets the hash function of the receiver if it is nil. SetHash
+// will return an error if it is called on a receiver with a non-nil
+// hash function.
+func (c *CountMin) SetHash(fn hash.Hash64) error {
+	if c.hash != nil {
+		return errors.New("card: hash function already set")
+	}
+	c.hash = fn
+	return nil
+}
+
+// Reset clears the receiver's counts allowing it to be reused. Reset does
+// not alter the dimensions of the receiver or the hash function that is used.
+func (c *CountMin) Reset() {
+	for i := range c.count {
+		c.count[i] = 0
+	}
+	c.total = 0
+}
+
+// MarshalBinary marshals the sketch in the receiver. It encodes the name of
+// the hash function, the dimensions of the sketch and the sketch data. The
+// receiver must have a non-nil hash function.
+func (c *CountMin) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	enc := gob.NewEncoder(&buf)
+	err := encodeHash64(enc, c.hash)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range []interface{}{c.width, c.depth, c.total, c.count} {
+		err = enc.Encode(v)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary unmarshals the binary representation of a sketch into
+// the receiver. The dimensions of the receiver will be set after return. If
+// the receiver has a non-nil hash function value, it must be the same type
+// as the one that was stored in the binary data, otherwise the hash function
+// is obtained from the functions registered with RegisterHash.
+func (c *CountMin) UnmarshalBinary(b []byte) error {
+	dec := gob.NewDecoder(bytes.NewReader(b))
+	err := decodeHash64(dec, &c.hash)
+	if err != nil {
+		return err
+	}
+	c.count = c.count[:0]
+	for _, v := range []interface{}{&c.width, &c.depth, &c.total, &c.count} {
+		err = dec.Decode(v)
+		if err != nil {
+			return err
+		}
+	}
+	if len(c.count) != c.width*c.depth {
+		return errors.New("card: invalid sketch data")
+	}
+	return nil
+}
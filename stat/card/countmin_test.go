@@ -0,0 +1,103 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package card
+
+import (
+	"hash/fnv"
+	"strconv"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestCountMin(t *testing.T) {
+	t.Parallel()
+	const eps, delta = 1e-3, 1e-3
+	width, depth := CountMinDims(eps, delta)
+	if width != 2719 || depth != 7 {
+		t.Errorf("unexpected dimensions: got %d×%d, want 2719×7", width, depth)
+	}
+	a, err := NewCountMin(width, depth, fnv.New64a())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b, _ := NewCountMin(width, depth, fnv.New64a())
+
+	// Draw items from a Zipf distribution so that there are
+	// heavy hitters.
+	rnd := rand.New(rand.NewSource(1))
+	zipf := rand.NewZipf(rnd, 1.2, 1, 1e5)
+	exact := make(map[uint64]uint64)
+	const n = 1e5
+	for i := 0; i < n; i++ {
+		v := zipf.Uint64()
+		exact[v]++
+		s := a
+		if i%2 == 1 {
+			s = b
+		}
+		s.Write(strconv.AppendUint(nil, v, 10))
+	}
+	var u CountMin
+	err = u.Union(a, b)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = u.SetHash(fnv.New64a())
+	if err != nil {
+		t.Fatalf("unexpected error setting hash: %v", err)
+	}
+	if u.SetHash(fnv.New64a()) == nil {
+		t.Error("expected error setting hash twice")
+	}
+	if u.Total() != n {
+		t.Errorf("unexpected total: got %d, want %d", u.Total(), uint64(n))
+	}
+	var bad int
+	for v, want := range exact {
+		got := u.Frequency(strconv.AppendUint(nil, v, 10))
+		if got < want {
+			t.Errorf("frequency of %d underestimated: got %d, want %d", v, got, want)
+		}
+		if float64(got-want) > eps*n {
+			bad++
+		}
+	}
+	if float64(bad) > 2*delta*float64(len(exact)) {
+		t.Errorf("too many estimates outside error bound: %d of %d", bad, len(exact))
+	}
+
+	// A sketch encoded and decoded returns the same estimates.
+	buf, err := u.MarshalBinary()
+	if err != nil {
+		t.Fatalf("unexpected error marshaling: %v", err)
+	}
+	dst := &CountMin{hash: fnv.New64a()}
+	err = dst.UnmarshalBinary(buf)
+	if err != nil {
+		t.Fatalf("unexpected error unmarshaling: %v", err)
+	}
+	for v := range exact {
+		k := strconv.AppendUint(nil, v, 10)
+		if dst.Frequency(k) != u.Frequency(k) {
+			t.Errorf("mismatched frequency after round trip for %d", v)
+			break
+		}
+	}
+
+	c, _ := NewCountMin(width+1, depth, fnv.New64a())
+	if err := u.Union(a, c); err == nil {
+		t.Error("expected error for mismatched dimensions")
+	}
+	d, _ := NewCountMin(width, depth, fnv.New64())
+	if err := u.Union(a, d); err == nil {
+		t.Error("expected error for mismatched hash functions")
+	}
+
+	u.Reset()
+	if u.Total() != 0 || u.Frequency([]byte("1")) != 0 {
+		t.Error("sketch not empty after reset")
+	}
+}
@@ -0,0 +1,327 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package card
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"hash"
+)
+
+const (
+	// cuckooSlots is the number of fingerprints held in each bucket.
+	cuckooSlots = 4
+	// maxKicks is the maximum number of relocations attempted when
+	// inserting into a full bucket.
+	maxKicks = 500
+)
+
+// errCuckooFull is returned when an item cannot be inserted into a
+// cuckoo filter.
+var errCuckooFull = errors.New("card: cuckoo filter full")
+
+// Cuckoo is a cuckoo filter for approximate set membership with deletion,
+// as described in Fan, Andersen, Kaminsky and Mitzenmacher, "Cuckoo filter:
+// Practically better than Bloom", CoNEXT '14 (2014) 75-88. The filter stores
+// 16-bit fingerprints of the items in buckets of four.
+//
+// A cuckoo filter never reports that an item written to it and not deleted
+// is absent, but may report that an absent item is present.
+type Cuckoo struct {
+	mask uint64
+
+	hash hash.Hash64
+
+	buckets []uint16
+	n       int
+
+	// victim holds an evicted fingerprint that could
+	// not be placed when the filter became full.
+	victim      uint16
+	victimIndex uint64
+
+	// state is the state of the generator used to
+	// choose fingerprints to evict.
+	state uint64
+}
+
+// NewCuckoo returns a new cuckoo filter with at least the given number of
+// buckets, each holding four fingerprints. The number of buckets is rounded
+// up to a power of two and must be positive.
+func NewCuckoo(buckets int, h hash.Hash64) (*Cuckoo, error) {
+	if buckets <= 0 {
+		return nil, errors.New("card: dimension out of range")
+	}
+	m := uint64(1)
+	for m < uint64(buckets) {
+		m <<= 1
+	}
+	return &Cuckoo{
+		mask:    m - 1,
+		hash:    h,
+		buckets: make([]uint16, m*cuckooSlots),
+		state:   1,
+	}, nil
+}
+
+// fingerprint returns the fingerprint and primary bucket of the data in b.
+func (f *Cuckoo) fingerprint(b []byte) (fp uint16, i uint64, n int, err error) {
+	x, n, err := sum64(f.hash, b)
+	fp = uint16(x >> 48)
+	if fp == 0 {
+		// Zero marks an empty slot.
+		fp = 1
+	}
+	return fp, x & f.mask, n, err
+}
+
+// alt returns the alternate bucket for the fingerprint fp in bucket i.
+func (f *Cuckoo) alt(i uint64, fp uint16) uint64 {
+	// Mix the fingerprint with the multiplier of MurmurHash2.
+	return (i ^ (uint64(fp) * 0x5bd1e995)) & f.mask
+}
+
+// Write inserts the data in b into the set held by the receiver. Write
+// returns an error if the filter is full. Writing the same data more than
+// once stores multiple copies of its fingerprint, so that it must be deleted
+// as many times.
+//
+// Write satisfies the io.Writer interface.
+func (f *Cuckoo) Write(b []byte) (int, error) {
+	fp, i, n, err := f.fingerprint(b)
+	if err != nil {
+		return n, err
+	}
+	return n, f.insert(fp, i)
+}
+
+func (f *Cuckoo) insert(fp uint16, i uint64) error {
+	if f.victim != 0 {
+		return errCuckooFull
+	}
+	j := f.alt(i, fp)
+	if f.place(i, fp) || f.place(j, fp) {
+		f.n++
+		return nil
+	}
+	if f.next()&1 == 1 {
+		i = j
+	}
+	for k := 0; k < maxKicks; k++ {
+		slot := i*cuckooSlots + f.next()%cuckooSlots
+		fp, f.buckets[slot] = f.buckets[slot], fp
+		i = f.alt(i, fp)
+		if f.place(i, fp) {
+			f.n++
+			return nil
+		}
+	}
+	// Keep the last evicted fingerprint so that no
+	// item that was inserted is lost.
+	f.victim = fp
+	f.victimIndex = i
+	f.n++
+	return nil
+}
+
+// place places fp in an empty slot of bucket i, returning whether it
+// succeeded.
+func (f *Cuckoo) place(i uint64, fp uint16) bool {
+	for s := i * cuckooSlots; s < (i+1)*cuckooSlots; s++ {
+		if f.buckets[s] == 0 {
+			f.buckets[s] = fp
+			return true
+		}
+	}
+	return false
+}
+
+// next returns the next value of a xorshift generator.
+func (f *Cuckoo) next() uint64 {
+	f.state ^= f.state << 13
+	f.state ^= f.state >> 7
+	f.state ^= f.state << 17
+	return f.state
+}
+
+// Contains returns whether the data in b may be a member of the set held by
+// the receiver. If Contains returns false, b is not a member.
+func (f *Cuckoo) Contains(b []byte) bool {
+	fp, i, _, _ := f.fingerprint(b)
+	j := f.alt(i, fp)
+	if f.victim == fp && (f.victimIndex == i || f.victimIndex == j) {
+		return true
+	}
+	return f.find(i, fp) >= 0 || f.find(j, fp) >= 0
+}
+
+// find returns the slot holding fp in bucket i, or -1 if fp is not present.
+func (f *Cuckoo) find(i uint64, fp uint16) int {
+	for s := i * cuckooSlots; s < (i+1)*cuckooSlots; s++ {
+		if f.buckets[s] == fp {
+			return int(s)
+		}
+	}
+	return -1
+}
+
+// Delete removes one copy of the data in b from the set held by the receiver,
+// returning whether a matching fingerprint was found. Only data that has been
+// written to the receiver should be deleted, otherwise the fingerprint of a
+// different item may be removed.
+func (f *Cuckoo) Delete(b []byte) bool {
+	fp, i, _, _ := f.fingerprint(b)
+	j := f.alt(i, fp)
+	s := f.find(i, fp)
+	if s < 0 {
+		s = f.find(j, fp)
+	}
+	if s >= 0 {
+		f.buckets[s] = 0
+		f.n--
+		if f.victim != 0 {
+			// Try to reinsert the victim now there is space.
+			fp, i := f.victim, f.victimIndex
+			f.victim = 0
+			f.n--
+			_ = f.insert(fp, i)
+		}
+		return true
+	}
+	if f.victim == fp && (f.victimIndex == i || f.victimIndex == j) {
+		f.victim = 0
+		f.n--
+		return true
+	}
+	return false
+}
+
+// Count returns the number of fingerprints held by the receiver. This is the
+// number of writes less the number of successful deletions.
+func (f *Cuckoo) Count() float64 {
+	return float64(f.n)
+}
+
+// Union places the union of the sets held in a and b into the receiver by
+// inserting the fingerprints of a and b. Union will return an error if the
+// number of buckets or hash functions of a and b do not match, if the receiver
+// has a hash function that is set and does not match those of a and b, or if
+// the receiver becomes full. Hash functions provided by hash.Hash64
+// implementations x and y match when reflect.TypeOf(x) == reflect.TypeOf(y).
+//
+// If the receiver does not have a set hash function, it can be set after
+// a call to Union with the SetHash method.
+func (f *Cuckoo) Union(a, b *Cuckoo) error {
+	if a.mask != b.mask {
+		return errors.New("card: mismatched dimensions")
+	}
+	err := matchHash64(f.hash, a.hash, b.hash)
+	if err != nil {
+		return err
+	}
+	var src []*Cuckoo
+	switch {
+	case f == a && f == b:
+		src = []*Cuckoo{a.clone()}
+	case f == a:
+		src = []*Cuckoo{b}
+	case f == b:
+		src = []*Cuckoo{a}
+	default:
+		src = []*Cuckoo{a, b}
+		*f = Cuckoo{mask: a.mask, hash: f.hash, buckets: make([]uint16, len(a.buckets)), state: 1}
+	}
+	for _, c := range src {
+		for s, fp := range c.buckets {
+			if fp == 0 {
+				continue
+			}
+			err = f.insert(fp, uint64(s/cuckooSlots))
+			if err != nil {
+				return err
+			}
+		}
+		if c.victim != 0 {
+			err = f.insert(c.victim, c.victimIndex)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (f *Cuckoo) clone() *Cuckoo {
+	c := *f
+	c.buckets = append([]uint16(nil), f.buckets...)
+	return &c
+}
+
+// SetHash sets the hash function of the receiver if it is nil. SetHash
+// will return an error if it is called on a receiver with a non-nil
+// hash function.
+func (f *Cuckoo) SetHash(fn hash.Hash64) error {
+	if f.hash != nil {
+		return errors.New("card: hash function already set")
+	}
+	f.hash = fn
+	return nil
+}
+
+// Reset clears the receiver allowing it to be reused. Reset does not alter
+// the number of buckets of the receiver or the hash function that is used.
+func (f *Cuckoo) Reset() {
+	for i := range f.buckets {
+		f.buckets[i] = 0
+	}
+	f.n = 0
+	f.victim = 0
+	f.victimIndex = 0
+	f.state = 1
+}
+
+// MarshalBinary marshals the filter in the receiver. It encodes the name of
+// the hash function, the number of buckets and the filter data. The receiver
+// must have a non-nil hash function.
+func (f *Cuckoo) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	enc := gob.NewEncoder(&buf)
+	err := encodeHash64(enc, f.hash)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range []interface{}{f.mask, f.n, f.victim, f.victimIndex, f.state, f.buckets} {
+		err = enc.Encode(v)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary unmarshals the binary representation of a filter into
+// the receiver. The number of buckets of the receiver will be set after
+// return. If the receiver has a non-nil hash function value, it must be the
+// same type as the one that was stored in the binary data, otherwise the hash
+// function is obtained from the functions registered with RegisterHash.
+func (f *Cuckoo) UnmarshalBinary(b []byte) error {
+	dec := gob.NewDecoder(bytes.NewReader(b))
+	err := decodeHash64(dec, &f.hash)
+	if err != nil {
+		return err
+	}
+	f.buckets = f.buckets[:0]
+	for _, v := range []interface{}{&f.mask, &f.n, &f.victim, &f.victimIndex, &f.state, &f.buckets} {
+		err = dec.Decode(v)
+		if err != nil {
+			return err
+		}
+	}
+	if uint64(len(f.buckets)) != (f.mask+1)*cuckooSlots {
+		return errors.New("card: invalid filter data")
+	}
+	return nil
+}
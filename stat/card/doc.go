@@ -2,5 +2,6 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// Package card provides cardinality estimation functions.
+// Package card provides cardinality estimation functions and streaming
+// sketches for frequency, quantile and set membership estimation.
 package card // import "gonum.org/v1/gonum/stat/card"
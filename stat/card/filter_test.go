@@ -0,0 +1,186 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package card
+
+import (
+	"encoding"
+	"hash/fnv"
+	"math"
+	"strconv"
+	"testing"
+)
+
+type filter interface {
+	Write([]byte) (int, error)
+	Contains([]byte) bool
+	Count() float64
+	Reset()
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+}
+
+func TestFilters(t *testing.T) {
+	t.Parallel()
+	const n = 10000
+	m, k := BloomDims(n, 0.01)
+	if m != 95851 || k != 7 {
+		t.Errorf("unexpected Bloom dimensions: got m=%d k=%d, want m=95851 k=7", m, k)
+	}
+	for _, test := range []struct {
+		name   string
+		filter func() filter
+		empty  func() filter
+		fpRate float64
+	}{
+		{
+			name:   "Bloom",
+			filter: func() filter { f, _ := NewBloom(m, k, fnv.New64a()); return f },
+			empty:  func() filter { return &Bloom{hash: fnv.New64a()} },
+			fpRate: 0.01,
+		},
+		{
+			name:   "Cuckoo",
+			filter: func() filter { f, _ := NewCuckoo(n/2, fnv.New64a()); return f },
+			empty:  func() filter { return &Cuckoo{hash: fnv.New64a()} },
+			// The false positive rate is at most 2*4/2^16.
+			fpRate: 8.0 / (1 << 16),
+		},
+	} {
+		f := test.filter()
+		for i := 0; i < n; i++ {
+			_, err := f.Write([]byte("in-" + strconv.Itoa(i)))
+			if err != nil {
+				t.Fatalf("%s: unexpected error writing: %v", test.name, err)
+			}
+		}
+		for i := 0; i < n; i++ {
+			if !f.Contains([]byte("in-" + strconv.Itoa(i))) {
+				t.Errorf("%s: false negative for item %d", test.name, i)
+			}
+		}
+		var fp int
+		const trials = 100000
+		for i := 0; i < trials; i++ {
+			if f.Contains([]byte("out-" + strconv.Itoa(i))) {
+				fp++
+			}
+		}
+		if rate := float64(fp) / trials; rate > 1.5*test.fpRate {
+			t.Errorf("%s: false positive rate too high: got %v, want at most %v", test.name, rate, test.fpRate)
+		}
+		if count := f.Count(); math.Abs(count-n) > 0.02*n {
+			t.Errorf("%s: unexpected count: got %v, want %v", test.name, count, n)
+		}
+
+		buf, err := f.MarshalBinary()
+		if err != nil {
+			t.Fatalf("%s: unexpected error marshaling: %v", test.name, err)
+		}
+		dst := test.empty()
+		err = dst.UnmarshalBinary(buf)
+		if err != nil {
+			t.Fatalf("%s: unexpected error unmarshaling: %v", test.name, err)
+		}
+		for i := 0; i < 1000; i++ {
+			item := []byte("out-" + strconv.Itoa(i))
+			if dst.Contains(item) != f.Contains(item) {
+				t.Errorf("%s: mismatched membership after round trip", test.name)
+				break
+			}
+		}
+
+		f.Reset()
+		if f.Contains([]byte("in-0")) || f.Count() != 0 {
+			t.Errorf("%s: filter not empty after reset", test.name)
+		}
+	}
+}
+
+func TestBloomUnion(t *testing.T) {
+	t.Parallel()
+	a, _ := NewBloom(1000, 3, fnv.New64a())
+	b, _ := NewBloom(1000, 3, fnv.New64a())
+	a.Write([]byte("a"))
+	b.Write([]byte("b"))
+	var u Bloom
+	err := u.Union(a, b)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = u.SetHash(fnv.New64a())
+	if err != nil {
+		t.Fatalf("unexpected error setting hash: %v", err)
+	}
+	if !u.Contains([]byte("a")) || !u.Contains([]byte("b")) {
+		t.Error("union missing member")
+	}
+	c, _ := NewBloom(1000, 4, fnv.New64a())
+	if err := u.Union(a, c); err == nil {
+		t.Error("expected error for mismatched dimensions")
+	}
+}
+
+func TestCuckoo(t *testing.T) {
+	t.Parallel()
+	f, _ := NewCuckoo(100, fnv.New64a())
+	g, _ := NewCuckoo(100, fnv.New64a())
+	for i := 0; i < 100; i++ {
+		f.Write([]byte("f-" + strconv.Itoa(i)))
+		g.Write([]byte("g-" + strconv.Itoa(i)))
+	}
+	for i := 0; i < 50; i++ {
+		if !f.Delete([]byte("f-" + strconv.Itoa(i))) {
+			t.Errorf("failed to delete item %d", i)
+		}
+	}
+	if f.Count() != 50 {
+		t.Errorf("unexpected count after deletion: %v", f.Count())
+	}
+	var deleted int
+	for i := 0; i < 50; i++ {
+		if !f.Contains([]byte("f-" + strconv.Itoa(i))) {
+			deleted++
+		}
+	}
+	if deleted < 45 {
+		t.Errorf("deleted items still present: %d of 50 absent", deleted)
+	}
+
+	var u Cuckoo
+	err := u.Union(f, g)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = u.SetHash(fnv.New64a())
+	if err != nil {
+		t.Fatalf("unexpected error setting hash: %v", err)
+	}
+	if u.Count() != 150 {
+		t.Errorf("unexpected count of union: %v", u.Count())
+	}
+	for i := 50; i < 100; i++ {
+		if !u.Contains([]byte("f-"+strconv.Itoa(i))) || !u.Contains([]byte("g-"+strconv.Itoa(i))) {
+			t.Errorf("union missing item %d", i)
+		}
+	}
+
+	// Fill a small filter until it reports that it is full.
+	small, _ := NewCuckoo(4, fnv.New64a())
+	var i int
+	for ; i < 100; i++ {
+		_, err := small.Write([]byte(strconv.Itoa(i)))
+		if err != nil {
+			break
+		}
+	}
+	if i == 100 {
+		t.Fatal("expected filter to become full")
+	}
+	for j := 0; j < i; j++ {
+		if !small.Contains([]byte(strconv.Itoa(j))) {
+			t.Errorf("false negative in full filter for item %d", j)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package card
+
+import (
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"hash"
+	"reflect"
+)
+
+// sum64 returns the hash of b computed by h, leaving h reset.
+func sum64(h hash.Hash64, b []byte) (x uint64, n int, err error) {
+	n, err = h.Write(b)
+	x = h.Sum64()
+	h.Reset()
+	return x, n, err
+}
+
+// matchHash64 returns an error if the hash functions of the sketches being
+// combined into dst do not match. The hash function of dst is not checked
+// if it is nil.
+func matchHash64(dst, a, b hash.Hash64) error {
+	ta := reflect.TypeOf(a)
+	if reflect.TypeOf(b) != ta {
+		return errors.New("card: mismatched hash function")
+	}
+	if dst != nil && reflect.TypeOf(dst) != ta {
+		return errors.New("card: mismatched hash function")
+	}
+	return nil
+}
+
+// encodeHash64 encodes the hash size and the name of the hash function h.
+func encodeHash64(enc *gob.Encoder, h hash.Hash64) error {
+	if h == nil {
+		return errors.New("card: hash function not set")
+	}
+	err := enc.Encode(uint8(w64))
+	if err != nil {
+		return err
+	}
+	return enc.Encode(typeNameOf(h))
+}
+
+// decodeHash64 decodes a hash size and name encoded by encodeHash64. If
+// *h is nil it is set to a new hash from the registered hash functions,
+// otherwise the type of *h must match the decoded name.
+func decodeHash64(dec *gob.Decoder, h *hash.Hash64) error {
+	var size uint8
+	err := dec.Decode(&size)
+	if err != nil {
+		return err
+	}
+	if size != w64 {
+		return fmt.Errorf("card: mismatched hash function size: dst=%d src=%d", w64, size)
+	}
+	var srcHash string
+	err = dec.Decode(&srcHash)
+	if err != nil {
+		return err
+	}
+	if *h == nil {
+		*h = hash64For(srcHash)
+		if *h == nil {
+			return fmt.Errorf("card: hash function not set and no hash registered for %q", srcHash)
+		}
+		return nil
+	}
+	dstHash := typeNameOf(*h)
+	if dstHash != srcHash {
+		return fmt.Errorf("card: mismatched hash function: dst=%s src=%s", dstHash, srcHash)
+	}
+	return nil
+}
@@ -0,0 +1,252 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package card
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"math"
+	"sort"
+)
+
+// TDigest is a merging t-digest for estimating quantiles of a stream of
+// values, as described in Dunning and Ertl, "Computing extremely accurate
+// quantiles using t-digests", arXiv:1902.04023. The digest summarizes the
+// values by a set of weighted centroids whose sizes are limited by the
+// arcsine scale function, so that quantile estimates are most accurate in
+// the tails of the distribution.
+type TDigest struct {
+	compression float64
+
+	// mean and weight hold the merged centroids
+	// in order of increasing mean.
+	mean, weight []float64
+
+	// buffer holds values that have not yet
+	// been merged into the centroids.
+	buffer, bufWeight []float64
+
+	total    float64
+	min, max float64
+}
+
+// NewTDigest returns a new t-digest with the given compression. The number
+// of centroids retained is approximately the compression, which must be at
+// least 10. A compression of 100 is typical.
+func NewTDigest(compression float64) (*TDigest, error) {
+	if !(compression >= 10) {
+		return nil, errors.New("card: compression out of range")
+	}
+	return &TDigest{
+		compression: compression,
+		min:         math.Inf(1),
+		max:         math.Inf(-1),
+	}, nil
+}
+
+// Add notes the value x with weight w into the digest held by the receiver.
+// Add panics if w is not positive or x is NaN.
+func (t *TDigest) Add(x, w float64) {
+	if !(w > 0) || math.IsNaN(x) {
+		panic("card: invalid value or weight")
+	}
+	t.buffer = append(t.buffer, x)
+	t.bufWeight = append(t.bufWeight, w)
+	t.total += w
+	t.min = math.Min(t.min, x)
+	t.max = math.Max(t.max, x)
+	if len(t.buffer) >= int(5*t.compression) {
+		t.merge()
+	}
+}
+
+// k returns the arcsine scale function at the quantile q.
+func (t *TDigest) k(q float64) float64 {
+	return t.compression / (2 * math.Pi) * math.Asin(2*q-1)
+}
+
+// kInv returns the inverse of the scale function.
+func (t *TDigest) kInv(k float64) float64 {
+	a := math.Min(2*math.Pi*k/t.compression, math.Pi/2)
+	return (1 + math.Sin(a)) / 2
+}
+
+// merge merges the buffered values into the centroids.
+func (t *TDigest) merge() {
+	if len(t.buffer) == 0 {
+		return
+	}
+	type centroid struct{ mean, weight float64 }
+	all := make([]centroid, 0, len(t.mean)+len(t.buffer))
+	for i, m := range t.mean {
+		all = append(all, centroid{m, t.weight[i]})
+	}
+	for i, x := range t.buffer {
+		all = append(all, centroid{x, t.bufWeight[i]})
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].mean < all[j].mean })
+
+	t.mean = t.mean[:0]
+	t.weight = t.weight[:0]
+	cur := all[0]
+	var sofar float64
+	limit := t.total * t.kInv(t.k(0)+1)
+	for _, c := range all[1:] {
+		if sofar+cur.weight+c.weight <= limit {
+			// Merge c into the current centroid.
+			cur.weight += c.weight
+			cur.mean += (c.mean - cur.mean) * c.weight / cur.weight
+			continue
+		}
+		sofar += cur.weight
+		t.mean = append(t.mean, cur.mean)
+		t.weight = append(t.weight, cur.weight)
+		limit = t.total * t.kInv(t.k(sofar/t.total)+1)
+		cur = c
+	}
+	t.mean = append(t.mean, cur.mean)
+	t.weight = append(t.weight, cur.weight)
+	t.buffer = t.buffer[:0]
+	t.bufWeight = t.bufWeight[:0]
+}
+
+// Weight returns the total weight of the values noted by the receiver.
+func (t *TDigest) Weight() float64 {
+	return t.total
+}
+
+// Quantile returns an estimate of the p quantile of the values noted by
+// the receiver. Quantile returns NaN if no values have been noted, and
+// panics if p is not in [0, 1].
+func (t *TDigest) Quantile(p float64) float64 {
+	if p < 0 || p > 1 {
+		panic("card: quantile out of range")
+	}
+	t.merge()
+	if t.total == 0 {
+		return math.NaN()
+	}
+	target := p * t.total
+	// The centroids are placed at the centers of their weight, and the
+	// extremes at the ends, with linear interpolation between them.
+	prevX, prevC := t.min, 0.0
+	var cum float64
+	for i, m := range t.mean {
+		c := cum + t.weight[i]/2
+		if target < c {
+			return interpolate(target, prevC, c, prevX, m)
+		}
+		prevX, prevC = m, c
+		cum += t.weight[i]
+	}
+	return interpolate(target, prevC, t.total, prevX, t.max)
+}
+
+// CDF returns an estimate of the fraction of the weight of the values noted
+// by the receiver that is less than or equal to x. CDF returns NaN if no
+// values have been noted.
+func (t *TDigest) CDF(x float64) float64 {
+	t.merge()
+	if t.total == 0 {
+		return math.NaN()
+	}
+	if x < t.min {
+		return 0
+	}
+	if x >= t.max {
+		return 1
+	}
+	prevX, prevC := t.min, 0.0
+	var cum float64
+	for i, m := range t.mean {
+		c := cum + t.weight[i]/2
+		if x < m {
+			return interpolate(x, prevX, m, prevC, c) / t.total
+		}
+		prevX, prevC = m, c
+		cum += t.weight[i]
+	}
+	return interpolate(x, prevX, t.max, prevC, t.total) / t.total
+}
+
+// interpolate returns the value at x of the line through (x0, y0) and (x1, y1).
+func interpolate(x, x0, x1, y0, y1 float64) float64 {
+	if x1 == x0 {
+		return y0
+	}
+	return y0 + (x-x0)/(x1-x0)*(y1-y0)
+}
+
+// Union places the union of the digests in a and b into the receiver, so that
+// the receiver summarizes the combined values. Union will return an error if
+// the compressions of a and b do not match.
+func (t *TDigest) Union(a, b *TDigest) error {
+	if a.compression != b.compression {
+		return errors.New("card: mismatched compression")
+	}
+	a.merge()
+	b.merge()
+	u := TDigest{
+		compression: a.compression,
+		total:       a.total + b.total,
+		min:         math.Min(a.min, b.min),
+		max:         math.Max(a.max, b.max),
+	}
+	u.buffer = append(append(u.buffer, a.mean...), b.mean...)
+	u.bufWeight = append(append(u.bufWeight, a.weight...), b.weight...)
+	u.merge()
+	*t = u
+	return nil
+}
+
+// Reset clears the receiver allowing it to be reused. Reset does not alter
+// the compression of the receiver.
+func (t *TDigest) Reset() {
+	*t = TDigest{
+		compression: t.compression,
+		mean:        t.mean[:0],
+		weight:      t.weight[:0],
+		buffer:      t.buffer[:0],
+		bufWeight:   t.bufWeight[:0],
+		min:         math.Inf(1),
+		max:         math.Inf(-1),
+	}
+}
+
+// MarshalBinary marshals the digest in the receiver. It encodes the
+// compression, the extremes and the centroids of the digest.
+func (t *TDigest) MarshalBinary() ([]byte, error) {
+	t.merge()
+	var buf bytes.Buffer
+	enc := gob.NewEncoder(&buf)
+	for _, v := range []interface{}{t.compression, t.total, t.min, t.max, t.mean, t.weight} {
+		err := enc.Encode(v)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary unmarshals the binary representation of a digest into
+// the receiver. The compression of the receiver will be set after return.
+func (t *TDigest) UnmarshalBinary(b []byte) error {
+	dec := gob.NewDecoder(bytes.NewReader(b))
+	t.mean = t.mean[:0]
+	t.weight = t.weight[:0]
+	t.buffer = t.buffer[:0]
+	t.bufWeight = t.bufWeight[:0]
+	for _, v := range []interface{}{&t.compression, &t.total, &t.min, &t.max, &t.mean, &t.weight} {
+		err := dec.Decode(v)
+		if err != nil {
+			return err
+		}
+	}
+	if len(t.mean) != len(t.weight) {
+		return errors.New("card: invalid digest data")
+	}
+	return nil
+}
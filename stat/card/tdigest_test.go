@@ -0,0 +1,95 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package card
+
+import (
+	"math"
+	"sort"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/stat"
+)
+
+func TestTDigest(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const n = 100000
+	a, err := NewTDigest(100)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b, _ := NewTDigest(100)
+	x := make([]float64, n)
+	for i := range x {
+		// Log-normal values resemble latencies.
+		x[i] = math.Exp(rnd.NormFloat64())
+		if i%3 == 0 {
+			a.Add(x[i], 1)
+		} else {
+			b.Add(x[i], 1)
+		}
+	}
+	sort.Float64s(x)
+
+	var u TDigest
+	err = u.Union(a, b)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if u.Weight() != n {
+		t.Errorf("unexpected weight: got %v, want %v", u.Weight(), n)
+	}
+	for _, p := range []float64{0, 0.001, 0.01, 0.1, 0.25, 0.5, 0.75, 0.9, 0.99, 0.999, 1} {
+		got := u.Quantile(p)
+		// Compare the ranks of the estimates with the requested rank.
+		rank := stat.CDF(got, stat.Empirical, x, nil)
+		tol := 0.01 * math.Sqrt(p*(1-p))
+		if tol < 1e-4 {
+			tol = 1e-4
+		}
+		if math.Abs(rank-p) > tol {
+			t.Errorf("unexpected quantile %v: got %v with rank %v", p, got, rank)
+		}
+		if cdf := u.CDF(got); math.Abs(cdf-p) > tol+1e-3 {
+			t.Errorf("unexpected CDF at quantile %v: got %v", p, cdf)
+		}
+	}
+	if got := u.Quantile(0); got != x[0] {
+		t.Errorf("unexpected minimum: got %v, want %v", got, x[0])
+	}
+	if got := u.Quantile(1); got != x[n-1] {
+		t.Errorf("unexpected maximum: got %v, want %v", got, x[n-1])
+	}
+	if len(u.mean) > 200 {
+		t.Errorf("too many centroids: %d", len(u.mean))
+	}
+
+	buf, err := u.MarshalBinary()
+	if err != nil {
+		t.Fatalf("unexpected error marshaling: %v", err)
+	}
+	var dst TDigest
+	err = dst.UnmarshalBinary(buf)
+	if err != nil {
+		t.Fatalf("unexpected error unmarshaling: %v", err)
+	}
+	for _, p := range []float64{0.01, 0.5, 0.99} {
+		if dst.Quantile(p) != u.Quantile(p) {
+			t.Errorf("mismatched quantile %v after round trip", p)
+		}
+	}
+
+	c, _ := NewTDigest(200)
+	if err := u.Union(a, c); err == nil {
+		t.Error("expected error for mismatched compression")
+	}
+
+	u.Reset()
+	if !math.IsNaN(u.Quantile(0.5)) || u.Weight() != 0 {
+		t.Error("digest not empty after reset")
+	}
+}
@@ -0,0 +1,470 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// AUC returns the area under the receiver operator characteristic curve
+// obtained when y is treated as a binary classifier for classes with weights,
+// as returned by ROC with all possible cutoffs. Tied values of y contribute
+// half of their weight, so AUC is the weighted probability that a randomly
+// chosen true observation has a greater y than a randomly chosen false
+// observation, counting ties as one half.
+//
+// The input y must be sorted, and values in y must correspond to values in
+// classes and weights. SortWeightedLabeled can be used to sort y together
+// with classes and weights. If weights is nil, all weights are treated as 1.
+func AUC(y []float64, classes []bool, weights []float64) float64 {
+	tpr, fpr, _ := ROC(nil, y, classes, weights)
+	var area float64
+	for i := 1; i < len(tpr); i++ {
+		area += (fpr[i] - fpr[i-1]) * (tpr[i] + tpr[i-1]) / 2
+	}
+	return area
+}
+
+// PrecisionRecall returns paired precision and recall values corresponding
+// to cutoff points on the precision-recall curve obtained when y is treated
+// as a binary classifier for classes with weights. The cutoff thresholds are
+// returned in thresh such that precision[i] and recall[i] are the precision
+// and recall of classifying observations with y >= thresh[i] as true.
+//
+// The inputs and the cutoffs are interpreted as for ROC. The precision at
+// the first threshold, where no observation is classified as true, is
+// defined to be 1.
+func PrecisionRecall(cutoffs, y []float64, classes []bool, weights []float64) (precision, recall, thresh []float64) {
+	tpr, fpr, thresh := ROC(cutoffs, y, classes, weights)
+	if tpr == nil {
+		return nil, nil, nil
+	}
+	var nPos, nNeg float64
+	for i, c := range classes {
+		w := 1.0
+		if weights != nil {
+			w = weights[i]
+		}
+		if c {
+			nPos += w
+		} else {
+			nNeg += w
+		}
+	}
+	precision = fpr
+	for i, r := range tpr {
+		tp := r * nPos
+		fp := fpr[i] * nNeg
+		if tp+fp == 0 {
+			precision[i] = 1
+		} else {
+			precision[i] = tp / (tp + fp)
+		}
+	}
+	return precision, tpr, thresh
+}
+
+// AveragePrecision returns the average precision of y treated as a binary
+// classifier for classes with weights,
+//  AP = \sum_i (R_i - R_{i-1}) P_i
+// where P_i and R_i are the precision and recall at the ith threshold
+// returned by PrecisionRecall with all possible cutoffs. The inputs are
+// interpreted as for ROC.
+func AveragePrecision(y []float64, classes []bool, weights []float64) float64 {
+	precision, recall, _ := PrecisionRecall(nil, y, classes, weights)
+	var ap float64
+	for i := 1; i < len(recall); i++ {
+		ap += (recall[i] - recall[i-1]) * precision[i]
+	}
+	return ap
+}
+
+// FScore returns the F_β score for the given precision and recall,
+//  F_β = (1 + β^2) precision recall / (β^2 precision + recall)
+// The F_1 score is the harmonic mean of the precision and recall.
+// FScore returns zero if both precision and recall are zero.
+func FScore(precision, recall, beta float64) float64 {
+	b2 := beta * beta
+	den := b2*precision + recall
+	if den == 0 {
+		return 0
+	}
+	return (1 + b2) * precision * recall / den
+}
+
+// ConfusionMatrix is the weighted count of the outcomes of a multi-class
+// classifier. Element (i, j) of the matrix is the total weight of the
+// observations of true class i that were predicted to be of class j.
+type ConfusionMatrix struct {
+	counts *mat.Dense
+}
+
+// NewConfusionMatrix returns the confusion matrix of the predicted classes
+// for observations with the given true classes and weights. Classes are
+// labeled from zero to k-1. If weights is nil, all weights are treated as 1.
+// NewConfusionMatrix panics if the lengths of the inputs differ or a class
+// label is out of range.
+func NewConfusionMatrix(k int, truth, predicted []int, weights []float64) *ConfusionMatrix {
+	if len(truth) != len(predicted) {
+		panic("stat: slice length mismatch")
+	}
+	if weights != nil && len(weights) != len(truth) {
+		panic("stat: slice length mismatch")
+	}
+	counts := mat.NewDense(k, k, nil)
+	for i, c := range truth {
+		p := predicted[i]
+		if c < 0 || k <= c || p < 0 || k <= p {
+			panic("stat: class label out of range")
+		}
+		w := 1.0
+		if weights != nil {
+			w = weights[i]
+		}
+		counts.Set(c, p, counts.At(c, p)+w)
+	}
+	return &ConfusionMatrix{counts: counts}
+}
+
+// Dims returns the dimensions of the confusion matrix.
+func (c *ConfusionMatrix) Dims() (r, cols int) { return c.counts.Dims() }
+
+// At returns the total weight of observations of class i predicted to be of
+// class j.
+func (c *ConfusionMatrix) At(i, j int) float64 { return c.counts.At(i, j) }
+
+// T returns the transpose of the confusion matrix.
+func (c *ConfusionMatrix) T() mat.Matrix { return mat.Transpose{Matrix: c} }
+
+// Classes returns the number of classes.
+func (c *ConfusionMatrix) Classes() int {
+	r, _ := c.counts.Dims()
+	return r
+}
+
+// Accuracy returns the weighted fraction of observations that are
+// classified correctly.
+func (c *ConfusionMatrix) Accuracy() float64 {
+	var correct float64
+	for i := 0; i < c.Classes(); i++ {
+		correct += c.counts.At(i, i)
+	}
+	return correct / mat.Sum(c.counts)
+}
+
+// Precision returns the precision of the classifier for the given class,
+// the weighted fraction of the observations predicted to be of the class
+// that truly are of the class.
+func (c *ConfusionMatrix) Precision(class int) float64 {
+	return c.counts.At(class, class) / mat.Sum(c.counts.ColView(class))
+}
+
+// Recall returns the recall of the classifier for the given class, the
+// weighted fraction of the observations of the class that are predicted
+// to be of the class.
+func (c *ConfusionMatrix) Recall(class int) float64 {
+	return c.counts.At(class, class) / mat.Sum(c.counts.RowView(class))
+}
+
+// FScore returns the F_β score of the classifier for the given class.
+func (c *ConfusionMatrix) FScore(class int, beta float64) float64 {
+	return FScore(c.Precision(class), c.Recall(class), beta)
+}
+
+// MacroFScore returns the unweighted mean over the classes of the F_β
+// scores. Classes with observations or predictions but no correctly
+// predicted observations have an F_β score of zero. Classes with neither
+// observations nor predictions are ignored.
+func (c *ConfusionMatrix) MacroFScore(beta float64) float64 {
+	var sum float64
+	var n int
+	for i := 0; i < c.Classes(); i++ {
+		if mat.Sum(c.counts.RowView(i)) == 0 && mat.Sum(c.counts.ColView(i)) == 0 {
+			continue
+		}
+		f := c.FScore(i, beta)
+		if math.IsNaN(f) {
+			// One of precision and recall is zero
+			// and the other is undefined.
+			f = 0
+		}
+		sum += f
+		n++
+	}
+	return sum / float64(n)
+}
+
+// Kappa returns Cohen's kappa statistic for the agreement between the true
+// and predicted classes, corrected for the agreement expected by chance.
+func (c *ConfusionMatrix) Kappa() float64 {
+	total := mat.Sum(c.counts)
+	var expect float64
+	for i := 0; i < c.Classes(); i++ {
+		expect += mat.Sum(c.counts.RowView(i)) * mat.Sum(c.counts.ColView(i))
+	}
+	expect /= total * total
+	return (c.Accuracy() - expect) / (1 - expect)
+}
+
+// LogLoss returns the weighted mean negative log-likelihood of the classes
+// under the predicted probabilities p of the true class,
+//  -\sum_i w_i (c_i log(p_i) + (1-c_i) log(1-p_i)) / \sum_i w_i
+// LogLoss returns +Inf if a true observation has a predicted probability of
+// zero or a false observation has a predicted probability of one. If weights
+// is nil, all weights are treated as 1.
+func LogLoss(p []float64, classes []bool, weights []float64) float64 {
+	checkProbClasses(p, classes, weights)
+	var loss, sumW float64
+	for i, v := range p {
+		w := 1.0
+		if weights != nil {
+			w = weights[i]
+		}
+		if !classes[i] {
+			v = 1 - v
+		}
+		if w != 0 {
+			loss -= w * math.Log(v)
+		}
+		sumW += w
+	}
+	return loss / sumW
+}
+
+// BrierScore returns the weighted mean squared difference between the
+// predicted probabilities p of the true class and the classes,
+//  \sum_i w_i (p_i - c_i)^2 / \sum_i w_i
+// If weights is nil, all weights are treated as 1.
+func BrierScore(p []float64, classes []bool, weights []float64) float64 {
+	checkProbClasses(p, classes, weights)
+	var score, sumW float64
+	for i, v := range p {
+		w := 1.0
+		if weights != nil {
+			w = weights[i]
+		}
+		if classes[i] {
+			v = 1 - v
+		}
+		score += w * v * v
+		sumW += w
+	}
+	return score / sumW
+}
+
+// Calibration returns the calibration curve of the predicted probabilities p
+// of the true class. The predictions are binned as for Histogram, with p[i]
+// placed in bin j if dividers[j] <= p[i] < dividers[j+1]. For each bin,
+// prob holds the weighted mean predicted probability, freq the weighted
+// fraction of the observations that are true, and count the total weight of
+// the observations. The prob and freq values of empty bins are NaN.
+//
+// The values in dividers must be sorted, and all values of p must lie within
+// [dividers[0], dividers[len(dividers)-1]). floats.Span can be used to
+// generate equally spaced dividers. If weights is nil, all weights are
+// treated as 1.
+func Calibration(dividers, p []float64, classes []bool, weights []float64) (prob, freq, count []float64) {
+	checkProbClasses(p, classes, weights)
+	if len(dividers) < 2 {
+		panic("stat: fewer than two dividers")
+	}
+	if !sort.Float64sAreSorted(dividers) {
+		panic("stat: dividers are not sorted")
+	}
+	n := len(dividers) - 1
+	prob = make([]float64, n)
+	freq = make([]float64, n)
+	count = make([]float64, n)
+	for i, v := range p {
+		if v < dividers[0] || dividers[n] <= v {
+			panic("stat: value outside dividers")
+		}
+		j := sort.Search(len(dividers), func(j int) bool { return dividers[j] > v }) - 1
+		w := 1.0
+		if weights != nil {
+			w = weights[i]
+		}
+		prob[j] += w * v
+		if classes[i] {
+			freq[j] += w
+		}
+		count[j] += w
+	}
+	for j, c := range count {
+		if c == 0 {
+			prob[j] = math.NaN()
+			freq[j] = math.NaN()
+			continue
+		}
+		prob[j] /= c
+		freq[j] /= c
+	}
+	return prob, freq, count
+}
+
+func checkProbClasses(p []float64, classes []bool, weights []float64) {
+	if len(p) != len(classes) {
+		panic("stat: slice length mismatch")
+	}
+	if weights != nil && len(weights) != len(p) {
+		panic("stat: slice length mismatch")
+	}
+}
+
+// DeLong returns the areas under the receiver operator characteristic curves
+// of the classifiers in scores for the given classes with weights, and stores
+// their covariance matrix in cov, estimated by the nonparametric method of
+// DeLong, DeLong and Clarke-Pearson, "Comparing the areas under two or more
+// correlated receiver operating characteristic curves: a nonparametric
+// approach", Biometrics 44 (1988) 837-845. Element i of scores holds the
+// scores of classifier i for each observation, with greater scores
+// indicating the true class. Ties count as one half, as for AUC. The scores
+// need not be sorted.
+//
+// The weights are treated as frequency weights, so an observation with
+// weight w contributes as w identical observations. If weights is nil, all
+// weights are treated as 1.
+//
+// The cov matrix must either be empty or have len(scores) rows. If cov is
+// empty, it is resized. DeLong panics if the total weight of the true or of
+// the false observations is less than two.
+func DeLong(cov *mat.SymDense, scores [][]float64, classes []bool, weights []float64) []float64 {
+	k := len(scores)
+	if cov.IsEmpty() {
+		*cov = *(cov.GrowSym(k).(*mat.SymDense))
+	} else if cov.Symmetric() != k {
+		panic(mat.ErrShape)
+	}
+	if weights != nil && len(weights) != len(classes) {
+		panic("stat: slice length mismatch")
+	}
+	var (
+		m, n       int
+		wPos, wNeg float64
+		posW, negW []float64
+	)
+	for i, c := range classes {
+		w := 1.0
+		if weights != nil {
+			w = weights[i]
+		}
+		if c {
+			m++
+			wPos += w
+			posW = append(posW, w)
+		} else {
+			n++
+			wNeg += w
+			negW = append(negW, w)
+		}
+	}
+	if wPos < 2 || wNeg < 2 {
+		panic("stat: too few observations of each class")
+	}
+	allW := append(append(make([]float64, 0, m+n), posW...), negW...)
+
+	auc := make([]float64, k)
+	v10 := mat.NewDense(m, k, nil)
+	v01 := mat.NewDense(n, k, nil)
+	pos := make([]float64, m)
+	neg := make([]float64, n)
+	for r, s := range scores {
+		if len(s) != len(classes) {
+			panic("stat: slice length mismatch")
+		}
+		pos, neg = pos[:0], neg[:0]
+		for i, c := range classes {
+			if c {
+				pos = append(pos, s[i])
+			} else {
+				neg = append(neg, s[i])
+			}
+		}
+		// The structural components are computed from the
+		// midranks of the scores within each class and in the
+		// combined sample.
+		all := append(append(make([]float64, 0, m+n), pos...), neg...)
+		rAll := midranks(all, allW)
+		rPos := midranks(pos, posW)
+		rNeg := midranks(neg, negW)
+		for i := range pos {
+			v := (rAll[i] - rPos[i]) / wNeg
+			v10.Set(i, r, v)
+			auc[r] += posW[i] * v
+		}
+		auc[r] /= wPos
+		for j := range neg {
+			v01.Set(j, r, 1-(rAll[m+j]-rNeg[j])/wPos)
+		}
+	}
+
+	var s10, s01 mat.SymDense
+	CovarianceMatrix(&s10, v10, posW)
+	CovarianceMatrix(&s01, v01, negW)
+	s10.ScaleSym(1/wPos, &s10)
+	s01.ScaleSym(1/wNeg, &s01)
+	cov.AddSym(&s10, &s01)
+	return auc
+}
+
+// DeLongInterval returns the confidence interval at the confidence level
+// 1-alpha for the area under the receiver operator characteristic curve of
+// the classifier scores a, or if b is not nil for the difference between the
+// areas for the classifiers a and b, using the normal approximation with the
+// DeLong estimate of the variance. The scores, classes and weights are
+// interpreted as for DeLong.
+func DeLongInterval(alpha float64, a, b []float64, classes []bool, weights []float64) (lo, hi float64) {
+	if !(0 < alpha && alpha < 1) {
+		panic("stat: confidence level out of range")
+	}
+	scores := [][]float64{a}
+	if b != nil {
+		scores = append(scores, b)
+	}
+	var cov mat.SymDense
+	auc := DeLong(&cov, scores, classes, weights)
+	est := auc[0]
+	v := cov.At(0, 0)
+	if b != nil {
+		est -= auc[1]
+		v += cov.At(1, 1) - 2*cov.At(0, 1)
+	}
+	// The standard normal quantile is computed directly to
+	// avoid an import cycle with distuv.
+	z := -math.Sqrt2 * math.Erfinv(alpha-1)
+	d := z * math.Sqrt(v)
+	return est - d, est + d
+}
+
+// midranks returns the ranks of x with weights w, with tied values assigned
+// the mean of their ranks. Ranks start at one, and an element with weight w
+// occupies w ranks.
+func midranks(x, w []float64) []float64 {
+	idx := make([]int, len(x))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.Slice(idx, func(i, j int) bool { return x[idx[i]] < x[idx[j]] })
+	r := make([]float64, len(x))
+	var below float64
+	for i := 0; i < len(idx); {
+		j := i
+		var tied float64
+		for j < len(idx) && x[idx[j]] == x[idx[i]] {
+			tied += w[idx[j]]
+			j++
+		}
+		mid := below + (tied+1)/2
+		for k := i; k < j; k++ {
+			r[idx[k]] = mid
+		}
+		below += tied
+		i = j
+	}
+	return r
+}
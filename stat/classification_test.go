@@ -0,0 +1,347 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestAUC(t *testing.T) {
+	t.Parallel()
+	const tol = 1e-14
+	for i, test := range []struct {
+		y []float64
+		c []bool
+		w []float64
+	}{
+		{
+			y: []float64{0.1, 0.35, 0.4, 0.8},
+			c: []bool{true, false, true, false},
+		},
+		{
+			y: []float64{0, 3, 5, 6, 7.5, 8},
+			c: []bool{false, true, false, true, true, true},
+			w: []float64{4, 1, 6, 3, 2, 2},
+		},
+		{
+			y: []float64{1, 1, 2, 2, 2, 3, 3},
+			c: []bool{false, true, false, true, true, false, true},
+			w: []float64{1, 2, 3, 1, 2, 3, 1},
+		},
+	} {
+		// The AUC is the probability that a true observation
+		// scores higher than a false observation.
+		var want, sumW float64
+		for a, ca := range test.c {
+			for b, cb := range test.c {
+				if !ca || cb {
+					continue
+				}
+				w := weightAt(test.w, a) * weightAt(test.w, b)
+				sumW += w
+				switch {
+				case test.y[a] > test.y[b]:
+					want += w
+				case test.y[a] == test.y[b]:
+					want += w / 2
+				}
+			}
+		}
+		want /= sumW
+		if got := AUC(test.y, test.c, test.w); math.Abs(got-want) > tol {
+			t.Errorf("unexpected AUC for test %d: got:%v want:%v", i, got, want)
+		}
+	}
+}
+
+func weightAt(w []float64, i int) float64 {
+	if w == nil {
+		return 1
+	}
+	return w[i]
+}
+
+func TestPrecisionRecall(t *testing.T) {
+	t.Parallel()
+	const tol = 1e-14
+	// Test values calculated using sklearn metrics.precision_recall_curve
+	// and metrics.average_precision_score.
+	y := []float64{0.1, 0.35, 0.4, 0.8}
+	c := []bool{false, true, false, true}
+	precision, recall, thresh := PrecisionRecall(nil, y, c, nil)
+	wantPrecision := []float64{1, 1, 0.5, 2.0 / 3, 0.5}
+	wantRecall := []float64{0, 0.5, 0.5, 1, 1}
+	wantThresh := []float64{math.Inf(1), 0.8, 0.4, 0.35, 0.1}
+	if !floats.EqualApprox(precision, wantPrecision, tol) {
+		t.Errorf("unexpected precision: got:%v want:%v", precision, wantPrecision)
+	}
+	if !floats.EqualApprox(recall, wantRecall, tol) {
+		t.Errorf("unexpected recall: got:%v want:%v", recall, wantRecall)
+	}
+	if !floats.Same(thresh, wantThresh) {
+		t.Errorf("unexpected thresholds: got:%v want:%v", thresh, wantThresh)
+	}
+	if got, want := AveragePrecision(y, c, nil), 0.8333333333333333; math.Abs(got-want) > tol {
+		t.Errorf("unexpected average precision: got:%v want:%v", got, want)
+	}
+
+	// Integer weights are equivalent to replicated observations.
+	w := []float64{2, 1, 3, 2}
+	var ry []float64
+	var rc []bool
+	for i, v := range y {
+		for j := 0; j < int(w[i]); j++ {
+			ry = append(ry, v)
+			rc = append(rc, c[i])
+		}
+	}
+	if got, want := AveragePrecision(y, c, w), AveragePrecision(ry, rc, nil); math.Abs(got-want) > tol {
+		t.Errorf("unexpected weighted average precision: got:%v want:%v", got, want)
+	}
+
+	if got := FScore(0.5, 1, 1); math.Abs(got-2.0/3) > tol {
+		t.Errorf("unexpected F1 score: got:%v want:%v", got, 2.0/3)
+	}
+	if got := FScore(0, 0, 2); got != 0 {
+		t.Errorf("unexpected F2 score for zero precision and recall: got:%v want:0", got)
+	}
+}
+
+func TestConfusionMatrix(t *testing.T) {
+	t.Parallel()
+	const tol = 1e-14
+	// Test values calculated using sklearn metrics.
+	truth := []int{2, 0, 2, 2, 0, 1}
+	pred := []int{0, 0, 2, 2, 0, 2}
+	c := NewConfusionMatrix(3, truth, pred, nil)
+	want := mat.NewDense(3, 3, []float64{
+		2, 0, 0,
+		0, 0, 1,
+		1, 0, 2,
+	})
+	if !mat.Equal(c, want) {
+		t.Errorf("unexpected confusion matrix:\ngot: %v\nwant:%v", mat.Formatted(c), mat.Formatted(want))
+	}
+	if got := c.Accuracy(); math.Abs(got-4.0/6) > tol {
+		t.Errorf("unexpected accuracy: got:%v want:%v", got, 4.0/6)
+	}
+	for class, want := range []struct{ precision, recall, f1 float64 }{
+		{precision: 2.0 / 3, recall: 1, f1: 0.8},
+		{precision: math.NaN(), recall: 0, f1: math.NaN()},
+		{precision: 2.0 / 3, recall: 2.0 / 3, f1: 2.0 / 3},
+	} {
+		if got := c.Precision(class); !same(got, want.precision, tol) {
+			t.Errorf("unexpected precision for class %d: got:%v want:%v", class, got, want.precision)
+		}
+		if got := c.Recall(class); !same(got, want.recall, tol) {
+			t.Errorf("unexpected recall for class %d: got:%v want:%v", class, got, want.recall)
+		}
+		if got := c.FScore(class, 1); !same(got, want.f1, tol) {
+			t.Errorf("unexpected F1 score for class %d: got:%v want:%v", class, got, want.f1)
+		}
+	}
+	if got, want := c.MacroFScore(1), (0.8+0+2.0/3)/3; math.Abs(got-want) > tol {
+		t.Errorf("unexpected macro F1 score: got:%v want:%v", got, want)
+	}
+	if got, want := c.Kappa(), 0.42857142857142855; math.Abs(got-want) > tol {
+		t.Errorf("unexpected kappa: got:%v want:%v", got, want)
+	}
+	// Classes without predictions score zero and
+	// classes without observations or predictions
+	// are ignored.
+	c = NewConfusionMatrix(3, []int{0, 0, 1, 1}, []int{0, 0, 0, 0}, nil)
+	if got, want := c.MacroFScore(1), (2.0/3+0)/2; math.Abs(got-want) > tol {
+		t.Errorf("unexpected macro F1 score with unpredicted class: got:%v want:%v", got, want)
+	}
+
+	w := []float64{1, 2, 3, 1, 2, 3}
+	c = NewConfusionMatrix(3, truth, pred, w)
+	want = mat.NewDense(3, 3, []float64{
+		4, 0, 0,
+		0, 0, 3,
+		1, 0, 4,
+	})
+	if !mat.Equal(c, want) {
+		t.Errorf("unexpected weighted confusion matrix:\ngot: %v\nwant:%v", mat.Formatted(c), mat.Formatted(want))
+	}
+}
+
+func same(a, b, tol float64) bool {
+	return (math.IsNaN(a) && math.IsNaN(b)) || math.Abs(a-b) <= tol
+}
+
+func TestProbabilityScores(t *testing.T) {
+	t.Parallel()
+	const tol = 1e-14
+	p := []float64{0.1, 0.9, 0.8, 0.35}
+	c := []bool{false, true, true, false}
+	w := []float64{1, 2, 1, 2}
+
+	// Test values calculated using sklearn metrics.log_loss
+	// and metrics.brier_score_loss.
+	if got, want := LogLoss(p, c, nil), 0.21616187468057912; math.Abs(got-want) > tol {
+		t.Errorf("unexpected log loss: got:%v want:%v", got, want)
+	}
+	if got, want := BrierScore(p, c, nil), 0.045625; math.Abs(got-want) > tol {
+		t.Errorf("unexpected Brier score: got:%v want:%v", got, want)
+	}
+	wantLoss := -(math.Log(0.9) + 2*math.Log(0.9) + math.Log(0.8) + 2*math.Log(0.65)) / 6
+	if got := LogLoss(p, c, w); math.Abs(got-wantLoss) > tol {
+		t.Errorf("unexpected weighted log loss: got:%v want:%v", got, wantLoss)
+	}
+	if got := LogLoss([]float64{0, 1}, []bool{false, true}, nil); got != 0 {
+		t.Errorf("unexpected log loss for certain predictions: got:%v want:0", got)
+	}
+	if got := LogLoss([]float64{1}, []bool{false}, nil); !math.IsInf(got, 1) {
+		t.Errorf("unexpected log loss for wrong certain prediction: got:%v want:+Inf", got)
+	}
+
+	prob, freq, count := Calibration([]float64{0, 0.5, 1}, p, c, w)
+	wantProb := []float64{(0.1 + 2*0.35) / 3, (2*0.9 + 0.8) / 3}
+	wantFreq := []float64{0, 1}
+	wantCount := []float64{3, 3}
+	if !floats.EqualApprox(prob, wantProb, tol) || !floats.EqualApprox(freq, wantFreq, tol) || !floats.Equal(count, wantCount) {
+		t.Errorf("unexpected calibration: got:%v %v %v want:%v %v %v", prob, freq, count, wantProb, wantFreq, wantCount)
+	}
+	prob, _, count = Calibration([]float64{0, 0.25, 0.5, 0.75, 1}, p, c, nil)
+	if !math.IsNaN(prob[2]) || count[2] != 0 {
+		t.Errorf("unexpected calibration for empty bin: got:%v %v", prob[2], count[2])
+	}
+}
+
+func TestDeLong(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const (
+		n   = 60
+		tol = 1e-12
+	)
+	classes := make([]bool, n)
+	a := make([]float64, n)
+	b := make([]float64, n)
+	for i := range classes {
+		classes[i] = rnd.Float64() < 0.4
+		shift := 0.0
+		if classes[i] {
+			shift = 1
+		}
+		// Round the scores to introduce ties.
+		a[i] = math.Round(4*(rnd.NormFloat64()+shift)) / 4
+		b[i] = math.Round(4*(0.5*a[i]+rnd.NormFloat64()+shift/2)) / 4
+	}
+
+	var cov mat.SymDense
+	auc := DeLong(&cov, [][]float64{a, b}, classes, nil)
+
+	// Compute the DeLong estimates directly from the
+	// structural components.
+	psi := func(x, y float64) float64 {
+		switch {
+		case x > y:
+			return 1
+		case x == y:
+			return 0.5
+		}
+		return 0
+	}
+	var pos, neg []int
+	for i, c := range classes {
+		if c {
+			pos = append(pos, i)
+		} else {
+			neg = append(neg, i)
+		}
+	}
+	scores := [][]float64{a, b}
+	v10 := mat.NewDense(len(pos), 2, nil)
+	v01 := mat.NewDense(len(neg), 2, nil)
+	for r, s := range scores {
+		for i, p := range pos {
+			for j, q := range neg {
+				v := psi(s[p], s[q])
+				v10.Set(i, r, v10.At(i, r)+v/float64(len(neg)))
+				v01.Set(j, r, v01.At(j, r)+v/float64(len(pos)))
+			}
+		}
+		sorted := append([]float64(nil), s...)
+		c := append([]bool(nil), classes...)
+		SortWeightedLabeled(sorted, c, nil)
+		if want := AUC(sorted, c, nil); math.Abs(auc[r]-want) > tol {
+			t.Errorf("unexpected AUC for classifier %d: got:%v want:%v", r, auc[r], want)
+		}
+	}
+	var s10, s01, want mat.SymDense
+	CovarianceMatrix(&s10, v10, nil)
+	CovarianceMatrix(&s01, v01, nil)
+	s10.ScaleSym(1/float64(len(pos)), &s10)
+	s01.ScaleSym(1/float64(len(neg)), &s01)
+	want.AddSym(&s10, &s01)
+	if !mat.EqualApprox(&cov, &want, tol) {
+		t.Errorf("unexpected covariance:\ngot: %v\nwant:%v", mat.Formatted(&cov), mat.Formatted(&want))
+	}
+
+	lo, hi := DeLongInterval(0.05, a, b, classes, nil)
+	diff := auc[0] - auc[1]
+	se := math.Sqrt(want.At(0, 0) + want.At(1, 1) - 2*want.At(0, 1))
+	if math.Abs(lo-(diff-1.959963984540054*se)) > 1e-10 || math.Abs(hi-(diff+1.959963984540054*se)) > 1e-10 {
+		t.Errorf("unexpected interval: got:[%v, %v] want:[%v, %v]", lo, hi, diff-1.96*se, diff+1.96*se)
+	}
+}
+
+func TestDeLongWeighted(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const (
+		n   = 40
+		tol = 1e-12
+	)
+	classes := make([]bool, n)
+	weights := make([]float64, n)
+	a := make([]float64, n)
+	b := make([]float64, n)
+	for i := range classes {
+		classes[i] = i%3 == 0
+		weights[i] = float64(rnd.Intn(4))
+		shift := 0.0
+		if classes[i] {
+			shift = 1
+		}
+		a[i] = math.Round(4*(rnd.NormFloat64()+shift)) / 4
+		b[i] = math.Round(4*(0.5*a[i]+rnd.NormFloat64()+shift/2)) / 4
+	}
+
+	// Frequency weights are equivalent to replicated observations.
+	var (
+		rc     []bool
+		ra, rb []float64
+	)
+	for i, w := range weights {
+		for j := 0; j < int(w); j++ {
+			rc = append(rc, classes[i])
+			ra = append(ra, a[i])
+			rb = append(rb, b[i])
+		}
+	}
+	var got, want mat.SymDense
+	gotAUC := DeLong(&got, [][]float64{a, b}, classes, weights)
+	wantAUC := DeLong(&want, [][]float64{ra, rb}, rc, nil)
+	if !floats.EqualApprox(gotAUC, wantAUC, tol) {
+		t.Errorf("unexpected weighted AUC: got:%v want:%v", gotAUC, wantAUC)
+	}
+	if !mat.EqualApprox(&got, &want, tol) {
+		t.Errorf("unexpected weighted covariance:\ngot: %v\nwant:%v", mat.Formatted(&got), mat.Formatted(&want))
+	}
+	gotLo, gotHi := DeLongInterval(0.05, a, b, classes, weights)
+	wantLo, wantHi := DeLongInterval(0.05, ra, rb, rc, nil)
+	if math.Abs(gotLo-wantLo) > tol || math.Abs(gotHi-wantHi) > tol {
+		t.Errorf("unexpected weighted interval: got:[%v, %v] want:[%v, %v]", gotLo, gotHi, wantLo, wantHi)
+	}
+}
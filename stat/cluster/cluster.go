@@ -0,0 +1,181 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cluster
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/spatial/kdtree"
+)
+
+// Noise is the label of observations not assigned to a cluster.
+const Noise = -1
+
+const (
+	badLength  = "cluster: slice length mismatch"
+	badWeights = "cluster: negative weight"
+	badK       = "cluster: invalid number of clusters"
+	badDims    = "cluster: dimension mismatch"
+)
+
+// Distances computes the matrix of Euclidean distances between the rows of x
+// and stores it in dst. If dst is empty, it is resized to be an n×n symmetric
+// matrix where n is the number of rows of x. When dst is non-empty, Distances
+// panics if dst is not n×n.
+func Distances(dst *mat.SymDense, x mat.Matrix) {
+	n, _ := x.Dims()
+	if dst.IsEmpty() {
+		dst.ReuseAsSym(n)
+	} else if dst.Symmetric() != n {
+		panic(badDims)
+	}
+	rows := rowsOf(x)
+	for i := 0; i < n; i++ {
+		dst.SetSym(i, i, 0)
+		for j := i + 1; j < n; j++ {
+			dst.SetSym(i, j, math.Sqrt(sqDist(rows[i], rows[j])))
+		}
+	}
+}
+
+// rowsOf returns the rows of x as slices.
+func rowsOf(x mat.Matrix) [][]float64 {
+	n, _ := x.Dims()
+	rows := make([][]float64, n)
+	for i := range rows {
+		rows[i] = mat.Row(nil, i, x)
+	}
+	return rows
+}
+
+// sqDist returns the squared Euclidean distance between a and b.
+func sqDist(a, b []float64) float64 {
+	var sum float64
+	for i, v := range a {
+		d := v - b[i]
+		sum += d * d
+	}
+	return sum
+}
+
+// nearest returns the index of the row of centers nearest to x
+// and the squared distance to it.
+func nearest(centers [][]float64, x []float64) (int, float64) {
+	best := -1
+	min := math.Inf(1)
+	for i, c := range centers {
+		d := sqDist(c, x)
+		if d < min {
+			best, min = i, d
+		}
+	}
+	return best, min
+}
+
+// checkWeights panics if weights is not nil and does not have length n
+// or has a negative element.
+func checkWeights(n int, weights []float64) {
+	if weights == nil {
+		return
+	}
+	if len(weights) != n {
+		panic(badLength)
+	}
+	for _, w := range weights {
+		if w < 0 {
+			panic(badWeights)
+		}
+	}
+}
+
+// weightAt returns weights[i], or 1 if weights is nil.
+func weightAt(weights []float64, i int) float64 {
+	if weights == nil {
+		return 1
+	}
+	return weights[i]
+}
+
+// numClusters returns one more than the largest label.
+func numClusters(labels []int) int {
+	k := 0
+	for _, l := range labels {
+		if l >= k {
+			k = l + 1
+		}
+	}
+	return k
+}
+
+// centroids returns the mean of the observations in each cluster.
+func centroids(rows [][]float64, labels []int, k int) [][]float64 {
+	d := len(rows[0])
+	c := make([][]float64, k)
+	counts := make([]float64, k)
+	for i := range c {
+		c[i] = make([]float64, d)
+	}
+	for i, l := range labels {
+		if l < 0 {
+			continue
+		}
+		floats.Add(c[l], rows[i])
+		counts[l]++
+	}
+	for i := range c {
+		if counts[i] > 0 {
+			floats.Scale(1/counts[i], c[i])
+		}
+	}
+	return c
+}
+
+// point is a kdtree.Comparable that records the row of the
+// observation it represents.
+type point struct {
+	kdtree.Point
+	index int
+}
+
+func (p point) Compare(c kdtree.Comparable, d kdtree.Dim) float64 {
+	return p.Point[d] - c.(point).Point[d]
+}
+
+func (p point) Distance(c kdtree.Comparable) float64 {
+	return p.Point.Distance(c.(point).Point)
+}
+
+// points is a collection of points that satisfies the kdtree.Interface.
+type points []point
+
+func (p points) Index(i int) kdtree.Comparable         { return p[i] }
+func (p points) Len() int                              { return len(p) }
+func (p points) Pivot(d kdtree.Dim) int                { return plane{points: p, Dim: d}.Pivot() }
+func (p points) Slice(start, end int) kdtree.Interface { return p[start:end] }
+
+// plane allows a points value to be pivoted on a dimension.
+type plane struct {
+	kdtree.Dim
+	points
+}
+
+func (p plane) Less(i, j int) bool { return p.points[i].Point[p.Dim] < p.points[j].Point[p.Dim] }
+func (p plane) Pivot() int {
+	return kdtree.Partition(p, kdtree.MedianOfRandoms(p, 100))
+}
+func (p plane) Slice(start, end int) kdtree.SortSlicer { p.points = p.points[start:end]; return p }
+func (p plane) Swap(i, j int)                          { p.points[i], p.points[j] = p.points[j], p.points[i] }
+
+// newTree returns a k-d tree holding the rows and the points stored in it.
+func newTree(rows [][]float64) (*kdtree.Tree, []point) {
+	p := make(points, len(rows))
+	for i, r := range rows {
+		p[i] = point{Point: r, index: i}
+	}
+	pts := append([]point(nil), p...)
+	return kdtree.New(p, false), pts
+}
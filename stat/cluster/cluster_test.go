@@ -0,0 +1,101 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cluster
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// blobs returns n observations from each of the isotropic normal clusters with
+// the given centers and standard deviations, and the true labels.
+func blobs(n int, centers [][]float64, std []float64, src rand.Source) (*mat.Dense, []int) {
+	rnd := rand.New(src)
+	d := len(centers[0])
+	x := mat.NewDense(n*len(centers), d, nil)
+	labels := make([]int, n*len(centers))
+	for c, ctr := range centers {
+		for i := 0; i < n; i++ {
+			row := c*n + i
+			for j, v := range ctr {
+				x.Set(row, j, v+std[c]*rnd.NormFloat64())
+			}
+			labels[row] = c
+		}
+	}
+	return x, labels
+}
+
+// samePartition returns whether the labels describe the same partition
+// up to a relabeling of the clusters.
+func samePartition(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	ab := make(map[int]int)
+	ba := make(map[int]int)
+	for i := range a {
+		if v, ok := ab[a[i]]; ok && v != b[i] {
+			return false
+		}
+		if v, ok := ba[b[i]]; ok && v != a[i] {
+			return false
+		}
+		ab[a[i]] = b[i]
+		ba[b[i]] = a[i]
+	}
+	return true
+}
+
+func TestDistances(t *testing.T) {
+	x := mat.NewDense(3, 2, []float64{0, 0, 3, 4, 6, 8})
+	var d mat.SymDense
+	Distances(&d, x)
+	want := mat.NewSymDense(3, []float64{0, 5, 10, 5, 0, 5, 10, 5, 0})
+	if !mat.EqualApprox(&d, want, 1e-14) {
+		t.Errorf("unexpected distances: got %v, want %v", mat.Formatted(&d), mat.Formatted(want))
+	}
+}
+
+func TestSilhouette(t *testing.T) {
+	// Points on a line with two clusters {0, 1} and {4, 6}.
+	x := mat.NewDense(4, 1, []float64{0, 1, 4, 6})
+	var d mat.SymDense
+	Distances(&d, x)
+	labels := []int{0, 0, 1, 1}
+	got := SilhouetteSamples(nil, &d, labels)
+	want := []float64{
+		1 - 1/5.0,
+		1 - 1/4.0,
+		1 - 2/3.5,
+		1 - 2/5.5,
+	}
+	if !floats.EqualApprox(got, want, 1e-14) {
+		t.Errorf("unexpected silhouette samples: got %v, want %v", got, want)
+	}
+	if s := Silhouette(&d, labels); math.Abs(s-floats.Sum(want)/4) > 1e-14 {
+		t.Errorf("unexpected silhouette: got %v, want %v", s, floats.Sum(want)/4)
+	}
+
+	labels = []int{0, 0, 1, Noise}
+	got = SilhouetteSamples(nil, &d, labels)
+	if got[2] != 0 || !math.IsNaN(got[3]) {
+		t.Errorf("unexpected silhouette for singleton or noise: got %v", got)
+	}
+}
+
+func TestDaviesBouldin(t *testing.T) {
+	x := mat.NewDense(4, 1, []float64{0, 2, 10, 14})
+	// Centroids 1 and 12, scatters 1 and 2.
+	want := (3.0/11 + 3.0/11) / 2
+	if got := DaviesBouldin(x, []int{0, 0, 1, 1}); math.Abs(got-want) > 1e-14 {
+		t.Errorf("unexpected Davies–Bouldin index: got %v, want %v", got, want)
+	}
+}
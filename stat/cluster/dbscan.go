@@ -0,0 +1,72 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cluster
+
+import (
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/spatial/kdtree"
+)
+
+// DBSCAN clusters the rows of x using the density-based spatial clustering of
+// applications with noise algorithm of Ester et al. An observation is a core
+// point if at least minPts observations, including itself, are within Euclidean
+// distance eps of it. Clusters are the sets of observations reachable from core
+// points through chains of core points, and observations not reachable from any
+// core point are labeled Noise. Neighborhoods are found using range queries on
+// a k-d tree.
+//
+// Clusters are numbered in the order their first core point appears in x.
+func DBSCAN(x mat.Matrix, eps float64, minPts int) []int {
+	n, _ := x.Dims()
+	rows := rowsOf(x)
+	tree, pts := newTree(rows)
+
+	neighbors := func(i int) []int {
+		k := kdtree.NewDistKeeper(eps * eps)
+		tree.NearestSet(k, pts[i])
+		idx := make([]int, len(k.Heap))
+		for j, c := range k.Heap {
+			idx[j] = c.Comparable.(point).index
+		}
+		return idx
+	}
+
+	const unvisited = -2
+	labels := make([]int, n)
+	for i := range labels {
+		labels[i] = unvisited
+	}
+	var k int
+	for i := range rows {
+		if labels[i] != unvisited {
+			continue
+		}
+		nb := neighbors(i)
+		if len(nb) < minPts {
+			labels[i] = Noise
+			continue
+		}
+		labels[i] = k
+		queue := nb
+		for len(queue) > 0 {
+			j := queue[0]
+			queue = queue[1:]
+			if labels[j] == Noise {
+				// Border point previously marked as noise.
+				labels[j] = k
+				continue
+			}
+			if labels[j] != unvisited {
+				continue
+			}
+			labels[j] = k
+			if nbj := neighbors(j); len(nbj) >= minPts {
+				queue = append(queue, nbj...)
+			}
+		}
+		k++
+	}
+	return labels
+}
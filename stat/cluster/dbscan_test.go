@@ -0,0 +1,119 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cluster
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestDBSCAN(t *testing.T) {
+	src := rand.NewSource(1)
+	x, want := blobs(50, blobCenters, []float64{0.5, 0.5, 0.5}, src)
+	n, _ := x.Dims()
+
+	// Add an isolated outlier.
+	xo := mat.NewDense(n+1, 2, nil)
+	xo.Copy(x)
+	xo.SetRow(n, []float64{30, 30})
+	want = append(want, Noise)
+
+	const (
+		eps    = 1.0
+		minPts = 5
+	)
+	labels := DBSCAN(xo, eps, minPts)
+	if labels[n] != Noise {
+		t.Errorf("outlier not labeled noise: %v", labels[n])
+	}
+	var clustered []int
+	var truth []int
+	for i, l := range labels {
+		if l != Noise {
+			clustered = append(clustered, l)
+			truth = append(truth, want[i])
+		}
+	}
+	if len(clustered) < n*9/10 {
+		t.Errorf("too many observations labeled noise: %d of %d", n+1-len(clustered), n+1)
+	}
+	if !samePartition(clustered, truth) {
+		t.Errorf("DBSCAN did not recover clusters")
+	}
+
+	// Check the labels against the definition using brute force.
+	d := distances(xo)
+	isCore := make([]bool, n+1)
+	for i := range isCore {
+		var count int
+		for j := 0; j <= n; j++ {
+			if d.At(i, j) <= eps {
+				count++
+			}
+		}
+		isCore[i] = count >= minPts
+	}
+	for i := 0; i <= n; i++ {
+		for j := 0; j <= n; j++ {
+			if isCore[i] && isCore[j] && d.At(i, j) <= eps && labels[i] != labels[j] {
+				t.Errorf("neighboring core points %d and %d in different clusters", i, j)
+			}
+		}
+		if isCore[i] {
+			continue
+		}
+		// A non-core point is noise if and only if
+		// it has no core point neighbor.
+		nearCore := false
+		for j := 0; j <= n; j++ {
+			if isCore[j] && d.At(i, j) <= eps {
+				nearCore = true
+			}
+		}
+		if nearCore == (labels[i] == Noise) {
+			t.Errorf("border point %d labeled %d", i, labels[i])
+		}
+	}
+}
+
+func TestHDBSCAN(t *testing.T) {
+	src := rand.NewSource(1)
+	// Clusters of differing densities.
+	x, want := blobs(60, blobCenters, []float64{0.3, 1, 0.6}, src)
+	n, _ := x.Dims()
+
+	// Add uniform background noise far from the clusters.
+	rnd := rand.New(src)
+	const noise = 5
+	xo := mat.NewDense(n+noise, 2, nil)
+	xo.Copy(x)
+	for i := 0; i < noise; i++ {
+		theta := 2 * math.Pi * float64(i) / noise
+		xo.SetRow(n+i, []float64{5 + 25*math.Cos(theta) + rnd.Float64(), 3 + 25*math.Sin(theta)})
+	}
+	labels := HDBSCAN(xo, 10, 5)
+	for i := n; i < n+noise; i++ {
+		if labels[i] != Noise {
+			t.Errorf("outlier %d not labeled noise: %v", i, labels[i])
+		}
+	}
+	var clustered, truth []int
+	for i := 0; i < n; i++ {
+		if labels[i] != Noise {
+			clustered = append(clustered, labels[i])
+			truth = append(truth, want[i])
+		}
+	}
+	if len(clustered) < n*9/10 {
+		t.Errorf("too many observations labeled noise: %d of %d", n-len(clustered), n)
+	}
+	if !samePartition(clustered, truth) || numClusters(labels) != 3 {
+		t.Errorf("HDBSCAN did not recover clusters: %v", labels)
+	}
+}
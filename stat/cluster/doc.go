@@ -0,0 +1,11 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package cluster provides clustering algorithms and measures of clustering
+// quality.
+//
+// Data matrices hold one observation per row. Cluster assignments are returned
+// as labels holding the index of the cluster of each observation; observations
+// that are not assigned to any cluster are labeled Noise.
+package cluster // import "gonum.org/v1/gonum/stat/cluster"
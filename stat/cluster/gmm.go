@@ -0,0 +1,212 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cluster
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distmv"
+)
+
+// gmmTol is the relative tolerance on the change in log-likelihood
+// used to determine convergence of EM.
+const gmmTol = 1e-10
+
+// GaussianMixture is a mixture of multivariate normal distributions.
+type GaussianMixture struct {
+	// Weights holds the mixing proportions
+	// of the components.
+	Weights []float64
+
+	// Components holds the mixture components.
+	Components []*distmv.Normal
+
+	// LogLikelihood is the weighted log-likelihood
+	// of the data the mixture was fitted to.
+	LogLikelihood float64
+
+	// Iterations is the number of iterations of
+	// EM performed and Converged is whether the
+	// change in log-likelihood fell below the
+	// convergence tolerance.
+	Iterations int
+	Converged  bool
+
+	src rand.Source
+}
+
+// FitGaussianMixture fits a mixture of normal distributions to the rows of x by
+// the expectation-maximization algorithm, performing at most maxIter iterations.
+// The component means are initialized to the rows of means, the covariances to
+// the covariance of x and the mixing proportions are initially equal. Initial
+// means may be obtained, for example, with KMeansPlusPlus or FitKMeans.
+//
+// The value of reg is added to the diagonal of each covariance matrix estimate
+// to ensure it remains positive definite. If weights is nil then all the
+// weights are 1. The source src is used by the fitted components and the
+// mixture to generate random numbers.
+//
+// FitGaussianMixture returns false if a component covariance is not positive
+// definite. It panics if the number of columns of x and means differ.
+func FitGaussianMixture(x mat.Matrix, weights []float64, means mat.Matrix, reg float64, maxIter int, src rand.Source) (*GaussianMixture, bool) {
+	n, d := x.Dims()
+	k, dm := means.Dims()
+	if d != dm {
+		panic(badDims)
+	}
+	checkWeights(n, weights)
+	rows := rowsOf(x)
+
+	cov := mat.NewSymDense(d, nil)
+	stat.CovarianceMatrix(cov, x, weights)
+	for i := 0; i < d; i++ {
+		cov.SetSym(i, i, cov.At(i, i)+reg)
+	}
+	g := &GaussianMixture{
+		Weights:       make([]float64, k),
+		Components:    make([]*distmv.Normal, k),
+		LogLikelihood: math.Inf(-1),
+		src:           src,
+	}
+	for c := range g.Components {
+		g.Weights[c] = 1 / float64(k)
+		var ok bool
+		g.Components[c], ok = distmv.NewNormal(mat.Row(nil, c, means), cov, src)
+		if !ok {
+			return nil, false
+		}
+	}
+
+	resp := mat.NewDense(n, k, nil)
+	logp := make([]float64, k)
+	mu := make([]float64, d)
+	diff := make([]float64, d)
+	sigma := mat.NewSymDense(d, nil)
+	for g.Iterations < maxIter {
+		g.Iterations++
+
+		// Expectation step.
+		var ll float64
+		for i, r := range rows {
+			for c, comp := range g.Components {
+				logp[c] = math.Log(g.Weights[c]) + comp.LogProb(r)
+			}
+			lse := floats.LogSumExp(logp)
+			ll += weightAt(weights, i) * lse
+			for c := range logp {
+				resp.Set(i, c, weightAt(weights, i)*math.Exp(logp[c]-lse))
+			}
+		}
+		if ll-g.LogLikelihood <= gmmTol*math.Abs(ll) {
+			g.LogLikelihood = ll
+			g.Converged = true
+			break
+		}
+		g.LogLikelihood = ll
+
+		// Maximization step.
+		var total float64
+		for c := range g.Components {
+			for j := range mu {
+				mu[j] = 0
+			}
+			var nk float64
+			for i, r := range rows {
+				floats.AddScaled(mu, resp.At(i, c), r)
+				nk += resp.At(i, c)
+			}
+			g.Weights[c] = nk
+			total += nk
+			if nk == 0 {
+				continue
+			}
+			floats.Scale(1/nk, mu)
+			sigma.Zero()
+			for i, r := range rows {
+				floats.SubTo(diff, r, mu)
+				sigma.SymRankOne(sigma, resp.At(i, c)/nk, mat.NewVecDense(d, diff))
+			}
+			for j := 0; j < d; j++ {
+				sigma.SetSym(j, j, sigma.At(j, j)+reg)
+			}
+			var ok bool
+			g.Components[c], ok = distmv.NewNormal(mu, sigma, src)
+			if !ok {
+				return nil, false
+			}
+		}
+		floats.Scale(1/total, g.Weights)
+	}
+	return g, true
+}
+
+// Dim returns the dimension of the distribution.
+func (g *GaussianMixture) Dim() int {
+	return g.Components[0].Dim()
+}
+
+// LogProb computes the log of the probability density of the mixture at x.
+func (g *GaussianMixture) LogProb(x []float64) float64 {
+	logp := make([]float64, len(g.Components))
+	for c, comp := range g.Components {
+		logp[c] = math.Log(g.Weights[c]) + comp.LogProb(x)
+	}
+	return floats.LogSumExp(logp)
+}
+
+// Prob computes the probability density of the mixture at x.
+func (g *GaussianMixture) Prob(x []float64) float64 {
+	return math.Exp(g.LogProb(x))
+}
+
+// Posterior computes the posterior probabilities that x was generated by each
+// of the components and stores them in dst. If dst is nil a new slice is
+// allocated and returned. If dst is not nil it must have length equal to the
+// number of components.
+func (g *GaussianMixture) Posterior(dst, x []float64) []float64 {
+	if dst == nil {
+		dst = make([]float64, len(g.Components))
+	}
+	if len(dst) != len(g.Components) {
+		panic(badLength)
+	}
+	for c, comp := range g.Components {
+		dst[c] = math.Log(g.Weights[c]) + comp.LogProb(x)
+	}
+	lse := floats.LogSumExp(dst)
+	for c, v := range dst {
+		dst[c] = math.Exp(v - lse)
+	}
+	return dst
+}
+
+// Predict returns the index of the component with the largest posterior
+// probability of having generated x.
+func (g *GaussianMixture) Predict(x []float64) int {
+	best := -1
+	max := math.Inf(-1)
+	for c, comp := range g.Components {
+		lp := math.Log(g.Weights[c]) + comp.LogProb(x)
+		if lp > max {
+			best, max = c, lp
+		}
+	}
+	return best
+}
+
+// Rand generates a random sample from the mixture. If x is nil, a new slice
+// will be allocated and returned. If x is non-nil, len(x) must equal Dim.
+func (g *GaussianMixture) Rand(x []float64) []float64 {
+	f64 := rand.Float64
+	if g.src != nil {
+		f64 = rand.New(g.src).Float64
+	}
+	return g.Components[sampleIndex(g.Weights, f64)].Rand(x)
+}
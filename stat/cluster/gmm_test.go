@@ -0,0 +1,102 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cluster
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+)
+
+func TestGaussianMixture(t *testing.T) {
+	src := rand.NewSource(1)
+	x, want := blobs(200, blobCenters, []float64{1, 0.5, 1.5}, src)
+	var init mat.Dense
+	KMeansPlusPlus(&init, x, nil, 3, src)
+	g, ok := FitGaussianMixture(x, nil, &init, 1e-6, 200, src)
+	if !ok {
+		t.Fatal("unexpected failure")
+	}
+	if !g.Converged {
+		t.Errorf("EM did not converge")
+	}
+	labels := make([]int, len(want))
+	for i := range labels {
+		labels[i] = g.Predict(x.RawRowView(i))
+	}
+	if !samePartition(labels, want) {
+		t.Errorf("mixture did not recover clusters")
+	}
+	for c, ctr := range blobCenters {
+		comp := g.Predict(ctr)
+		if math.Abs(g.Weights[comp]-1.0/3) > 1e-2 {
+			t.Errorf("unexpected weight for component %d: %v", c, g.Weights[comp])
+		}
+		if mean := g.Components[comp].Mean(nil); !floats.EqualApprox(mean, ctr, 0.3) {
+			t.Errorf("unexpected mean for component %d: got %v, want %v", c, mean, ctr)
+		}
+	}
+
+	var ll float64
+	for i := range labels {
+		ll += g.LogProb(x.RawRowView(i))
+	}
+	if math.Abs(ll-g.LogLikelihood) > 1e-6*math.Abs(ll) {
+		t.Errorf("log-likelihood mismatch: got %v, want %v", g.LogLikelihood, ll)
+	}
+	post := g.Posterior(nil, []float64{5, 4})
+	if math.Abs(floats.Sum(post)-1) > 1e-14 {
+		t.Errorf("posterior does not sum to one: %v", post)
+	}
+
+	const n = 30000
+	samples := mat.NewDense(n, 2, nil)
+	for i := 0; i < n; i++ {
+		g.Rand(samples.RawRowView(i))
+	}
+	for j := 0; j < 2; j++ {
+		got := stat.Mean(mat.Col(nil, j, samples), nil)
+		want := stat.Mean(mat.Col(nil, j, x), nil)
+		if math.Abs(got-want) > 0.1 {
+			t.Errorf("sample mean mismatch in dimension %d: got %v, want %v", j, got, want)
+		}
+	}
+}
+
+func TestGaussianMixtureSingle(t *testing.T) {
+	// A single component mixture is the maximum likelihood normal fit.
+	src := rand.NewSource(1)
+	x, _ := blobs(50, [][]float64{{1, 2, 3}}, []float64{2}, src)
+	weights := make([]float64, 50)
+	for i := range weights {
+		weights[i] = 1 + float64(i%3)
+	}
+	init := mat.NewDense(1, 3, []float64{0, 0, 0})
+	g, ok := FitGaussianMixture(x, weights, init, 0, 100, nil)
+	if !ok {
+		t.Fatal("unexpected failure")
+	}
+	mean := make([]float64, 3)
+	for j := range mean {
+		mean[j] = stat.Mean(mat.Col(nil, j, x), weights)
+	}
+	if got := g.Components[0].Mean(nil); !floats.EqualApprox(got, mean, 1e-12) {
+		t.Errorf("mean mismatch: got %v, want %v", got, mean)
+	}
+	var want mat.SymDense
+	stat.CovarianceMatrix(&want, x, weights)
+	sumW := floats.Sum(weights)
+	want.ScaleSym((sumW-1)/sumW, &want)
+	var got mat.SymDense
+	g.Components[0].CovarianceMatrix(&got)
+	if !mat.EqualApprox(&got, &want, 1e-12) {
+		t.Errorf("covariance mismatch: got %v, want %v", mat.Formatted(&got), mat.Formatted(&want))
+	}
+}
@@ -0,0 +1,239 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cluster
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/spatial/kdtree"
+)
+
+// HDBSCAN clusters the rows of x using the hierarchical density-based clustering
+// algorithm of Campello, Moulavi and Sander. The core distance of an observation
+// is the Euclidean distance to its minSamples^th nearest neighbor, counting the
+// observation itself, and is found using a k-d tree. The single linkage hierarchy
+// of the mutual reachability distance
+//  d_mreach(a, b) = max(core(a), core(b), d(a, b))
+// is condensed by discarding splits that produce clusters with fewer than
+// minClusterSize observations, and the flat clustering with the largest total
+// cluster stability is returned. The root of the hierarchy is not selected as a
+// cluster, observations that leave the hierarchy before joining any selected
+// cluster are labeled Noise.
+//
+// HDBSCAN panics if minClusterSize is less than 2 or minSamples is less than 1.
+func HDBSCAN(x mat.Matrix, minClusterSize, minSamples int) []int {
+	if minClusterSize < 2 {
+		panic("cluster: minimum cluster size less than 2")
+	}
+	if minSamples < 1 {
+		panic("cluster: minimum samples less than 1")
+	}
+	n, _ := x.Dims()
+	labels := make([]int, n)
+	for i := range labels {
+		labels[i] = Noise
+	}
+	if n < 2 {
+		return labels
+	}
+	rows := rowsOf(x)
+
+	// Compute the core distances.
+	tree, pts := newTree(rows)
+	core := make([]float64, n)
+	for i := range rows {
+		k := kdtree.NewNKeeper(minSamples)
+		tree.NearestSet(k, pts[i])
+		core[i] = math.Sqrt(k.Heap[len(k.Heap)-1].Dist)
+	}
+
+	// Construct the minimum spanning tree of the mutual
+	// reachability graph with Prim's algorithm.
+	type edge struct {
+		a, b int
+		dist float64
+	}
+	edges := make([]edge, 0, n-1)
+	inTree := make([]bool, n)
+	best := make([]float64, n)
+	from := make([]int, n)
+	for i := range best {
+		best[i] = math.Inf(1)
+	}
+	cur := 0
+	for len(edges) < n-1 {
+		inTree[cur] = true
+		next := -1
+		for j := range rows {
+			if inTree[j] {
+				continue
+			}
+			d := math.Max(math.Sqrt(sqDist(rows[cur], rows[j])), math.Max(core[cur], core[j]))
+			if d < best[j] {
+				best[j] = d
+				from[j] = cur
+			}
+			if next == -1 || best[j] < best[next] {
+				next = j
+			}
+		}
+		edges = append(edges, edge{a: from[next], b: next, dist: best[next]})
+		cur = next
+	}
+	sort.SliceStable(edges, func(i, j int) bool { return edges[i].dist < edges[j].dist })
+
+	// Build the single linkage tree. Nodes less than n are observations
+	// and node n+i is formed by joining the nodes in the i^th edge.
+	left := make([]int, n-1)
+	right := make([]int, n-1)
+	height := make([]float64, n-1)
+	size := make([]int, 2*n-1)
+	for i := 0; i < n; i++ {
+		size[i] = 1
+	}
+	uf := newUnionFind(n)
+	for i, e := range edges {
+		left[i] = uf.cluster(e.a)
+		right[i] = uf.cluster(e.b)
+		height[i] = e.dist
+		size[n+i] = size[left[i]] + size[right[i]]
+		uf.union(e.a, e.b, n+i)
+	}
+
+	// Condense the tree. Clusters are numbered in order of creation so
+	// that a parent always precedes its children.
+	var (
+		parent    = []int{-1}
+		birth     = []float64{0}
+		stability = []float64{0}
+		pointIn   = make([]int, n)
+	)
+	leaves := func(node int, c int) {
+		stack := []int{node}
+		for len(stack) > 0 {
+			v := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if v < n {
+				pointIn[v] = c
+				continue
+			}
+			stack = append(stack, left[v-n], right[v-n])
+		}
+	}
+	type item struct{ node, cluster int }
+	stack := []item{{node: 2*n - 2, cluster: 0}}
+	for len(stack) > 0 {
+		it := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		c := it.cluster
+		if it.node < n {
+			pointIn[it.node] = c
+			continue
+		}
+		v := it.node - n
+		lambda := 1 / height[v]
+		a, b := left[v], right[v]
+		bigA := size[a] >= minClusterSize
+		bigB := size[b] >= minClusterSize
+		switch {
+		case bigA && bigB:
+			stability[c] += (lambda - birth[c]) * float64(size[a]+size[b])
+			for _, child := range []int{a, b} {
+				parent = append(parent, c)
+				birth = append(birth, lambda)
+				stability = append(stability, 0)
+				stack = append(stack, item{node: child, cluster: len(parent) - 1})
+			}
+		case bigA:
+			stability[c] += (lambda - birth[c]) * float64(size[b])
+			leaves(b, c)
+			stack = append(stack, item{node: a, cluster: c})
+		case bigB:
+			stability[c] += (lambda - birth[c]) * float64(size[a])
+			leaves(a, c)
+			stack = append(stack, item{node: b, cluster: c})
+		default:
+			stability[c] += (lambda - birth[c]) * float64(size[a]+size[b])
+			leaves(a, c)
+			leaves(b, c)
+		}
+	}
+
+	// Select the clusters with maximal total stability, excluding the root.
+	nc := len(parent)
+	selected := make([]bool, nc)
+	hasChild := make([]bool, nc)
+	childSum := make([]float64, nc)
+	for c := nc - 1; c > 0; c-- {
+		value := stability[c]
+		if !hasChild[c] || stability[c] >= childSum[c] {
+			selected[c] = true
+		} else {
+			value = childSum[c]
+		}
+		hasChild[parent[c]] = true
+		childSum[parent[c]] += value
+	}
+
+	// Label observations with their selected ancestor cluster.
+	clusterLabel := make([]int, nc)
+	clusterLabel[0] = Noise
+	var k int
+	for c := 1; c < nc; c++ {
+		if clusterLabel[parent[c]] != Noise {
+			clusterLabel[c] = clusterLabel[parent[c]]
+			continue
+		}
+		if selected[c] {
+			clusterLabel[c] = k
+			k++
+		} else {
+			clusterLabel[c] = Noise
+		}
+	}
+	for i := range labels {
+		labels[i] = clusterLabel[pointIn[i]]
+	}
+	return labels
+}
+
+// unionFind is a disjoint set forest that records an identifier
+// for each set.
+type unionFind struct {
+	parent []int
+	id     []int
+}
+
+func newUnionFind(n int) *unionFind {
+	u := &unionFind{parent: make([]int, n), id: make([]int, n)}
+	for i := range u.parent {
+		u.parent[i] = i
+		u.id[i] = i
+	}
+	return u
+}
+
+func (u *unionFind) find(i int) int {
+	for u.parent[i] != i {
+		u.parent[i] = u.parent[u.parent[i]]
+		i = u.parent[i]
+	}
+	return i
+}
+
+// cluster returns the identifier of the set holding i.
+func (u *unionFind) cluster(i int) int {
+	return u.id[u.find(i)]
+}
+
+// union merges the sets holding a and b and sets the identifier
+// of the merged set to id.
+func (u *unionFind) union(a, b, id int) {
+	ra, rb := u.find(a), u.find(b)
+	u.parent[ra] = rb
+	u.id[rb] = id
+}
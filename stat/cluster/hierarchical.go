@@ -0,0 +1,259 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cluster
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Linkage specifies the dissimilarity between clusters used by
+// agglomerative clustering.
+type Linkage int
+
+const (
+	// Single linkage uses the minimum dissimilarity
+	// between the members of the clusters.
+	Single Linkage = iota
+	// Complete linkage uses the maximum dissimilarity
+	// between the members of the clusters.
+	Complete
+	// Average linkage uses the mean dissimilarity
+	// between the members of the clusters.
+	Average
+	// Ward linkage merges the clusters that minimize
+	// the increase in the within-cluster sum of squares.
+	// It requires Euclidean distances.
+	Ward
+)
+
+// Merge is a merge of two clusters in a dendrogram.
+type Merge struct {
+	// A and B are the merged clusters. Values less than
+	// the number of observations n refer to single
+	// observations, and the value n+i refers to the
+	// cluster formed by the i^th merge.
+	A, B int
+
+	// Height is the dissimilarity between A and B.
+	Height float64
+
+	// Size is the number of observations in the
+	// merged cluster.
+	Size int
+}
+
+// Dendrogram is the result of hierarchical agglomerative clustering.
+// Merges are ordered by non-decreasing height. A Dendrogram records the
+// number of observations it was constructed from, so Dendrogram values
+// should be obtained from Agglomerative.
+type Dendrogram struct {
+	Merges []Merge
+
+	// n is the number of observations.
+	n int
+}
+
+// Agglomerative performs hierarchical agglomerative clustering of n observations
+// with the pairwise dissimilarities held in dis using the specified linkage.
+// The clustering is computed with the nearest-neighbor chain algorithm in O(n²)
+// time, using the Lance–Williams update for the dissimilarities of merged
+// clusters. For Ward linkage dis must hold Euclidean distances, such as those
+// computed by Distances, and merge heights are the Ward distances
+//  sqrt(2*|A|*|B|/(|A|+|B|)) * ||c_A - c_B||
+// where c_A and c_B are the centroids of the merged clusters. If dis has no
+// rows, Agglomerative returns a dendrogram with no observations.
+func Agglomerative(dis mat.Symmetric, link Linkage) *Dendrogram {
+	n := dis.Symmetric()
+	if n == 0 {
+		return &Dendrogram{}
+	}
+	d := make([]float64, n*n)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			v := dis.At(i, j)
+			if link == Ward {
+				v *= v
+			}
+			d[i*n+j] = v
+		}
+	}
+	size := make([]int, n)
+	active := make([]bool, n)
+	for i := range size {
+		size[i] = 1
+		active[i] = true
+	}
+
+	// Merges are recorded with the slot of a representative
+	// observation of each cluster and relabeled afterwards.
+	type slotMerge struct {
+		a, b   int
+		height float64
+	}
+	merges := make([]slotMerge, 0, n-1)
+	var chain []int
+	for len(merges) < n-1 {
+		if len(chain) == 0 {
+			for i, ok := range active {
+				if ok {
+					chain = append(chain, i)
+					break
+				}
+			}
+		}
+		a := chain[len(chain)-1]
+		prev := -1
+		if len(chain) > 1 {
+			prev = chain[len(chain)-2]
+		}
+		b := prev
+		min := math.Inf(1)
+		if prev >= 0 {
+			min = d[a*n+prev]
+		}
+		for k, ok := range active {
+			if !ok || k == a {
+				continue
+			}
+			if d[a*n+k] < min {
+				b, min = k, d[a*n+k]
+			}
+		}
+		if b != prev {
+			chain = append(chain, b)
+			continue
+		}
+
+		// a and b are reciprocal nearest neighbors.
+		chain = chain[:len(chain)-2]
+		sa, sb := float64(size[a]), float64(size[b])
+		for k, ok := range active {
+			if !ok || k == a || k == b {
+				continue
+			}
+			dak, dbk := d[a*n+k], d[b*n+k]
+			var v float64
+			switch link {
+			case Single:
+				v = math.Min(dak, dbk)
+			case Complete:
+				v = math.Max(dak, dbk)
+			case Average:
+				v = (sa*dak + sb*dbk) / (sa + sb)
+			case Ward:
+				sk := float64(size[k])
+				v = ((sa+sk)*dak + (sb+sk)*dbk - sk*min) / (sa + sb + sk)
+			default:
+				panic("cluster: unknown linkage")
+			}
+			d[b*n+k] = v
+			d[k*n+b] = v
+		}
+		height := min
+		if link == Ward {
+			height = math.Sqrt(min)
+		}
+		merges = append(merges, slotMerge{a: a, b: b, height: height})
+		size[b] += size[a]
+		active[a] = false
+	}
+
+	sort.SliceStable(merges, func(i, j int) bool { return merges[i].height < merges[j].height })
+	dend := &Dendrogram{Merges: make([]Merge, len(merges)), n: n}
+	uf := newUnionFind(n)
+	count := make([]int, 2*n-1)
+	for i := 0; i < n; i++ {
+		count[i] = 1
+	}
+	for i, m := range merges {
+		a, b := uf.cluster(m.a), uf.cluster(m.b)
+		if a > b {
+			a, b = b, a
+		}
+		count[n+i] = count[a] + count[b]
+		dend.Merges[i] = Merge{A: a, B: b, Height: m.height, Size: count[n+i]}
+		uf.union(m.a, m.b, n+i)
+	}
+	return dend
+}
+
+// Len returns the number of observations in the dendrogram.
+func (d *Dendrogram) Len() int {
+	return d.n
+}
+
+// Cut returns the labels of the observations when the dendrogram is cut to
+// form k clusters. Clusters are numbered in order of their first observation.
+// Cut panics if k is not in [1, n] where n is the number of observations.
+func (d *Dendrogram) Cut(k int) []int {
+	n := d.Len()
+	if k < 1 || n < k {
+		panic(badK)
+	}
+	return d.labels(n - k)
+}
+
+// CutHeight returns the labels of the observations when the dendrogram is cut
+// at height h so that only merges with height no greater than h are performed.
+// Clusters are numbered in order of their first observation.
+func (d *Dendrogram) CutHeight(h float64) []int {
+	m := sort.Search(len(d.Merges), func(i int) bool { return d.Merges[i].Height > h })
+	return d.labels(m)
+}
+
+// labels returns the labels of the observations after the first m merges.
+func (d *Dendrogram) labels(m int) []int {
+	n := d.Len()
+	if n == 0 {
+		return []int{}
+	}
+	uf := newUnionFind(n)
+	// Map the merge clusters back to a representative observation.
+	rep := make([]int, 2*n-1)
+	for i := 0; i < n; i++ {
+		rep[i] = i
+	}
+	for i, mg := range d.Merges[:m] {
+		rep[n+i] = rep[mg.A]
+		uf.union(rep[mg.A], rep[mg.B], n+i)
+	}
+	labels := make([]int, n)
+	ids := make(map[int]int)
+	for i := range labels {
+		root := uf.find(i)
+		l, ok := ids[root]
+		if !ok {
+			l = len(ids)
+			ids[root] = l
+		}
+		labels[i] = l
+	}
+	return labels
+}
+
+// Order returns the order of the observations at the leaves of the dendrogram,
+// such that the members of every cluster are contiguous.
+func (d *Dendrogram) Order() []int {
+	n := d.Len()
+	order := make([]int, 0, n)
+	if n == 0 {
+		return order
+	}
+	stack := []int{2*n - 2}
+	for len(stack) > 0 {
+		v := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if v < n {
+			order = append(order, v)
+			continue
+		}
+		m := d.Merges[v-n]
+		stack = append(stack, m.B, m.A)
+	}
+	return order
+}
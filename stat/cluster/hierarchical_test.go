@@ -0,0 +1,205 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cluster
+
+import (
+	"math"
+	"sort"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// naiveLinkage returns the merge heights of agglomerative clustering computed
+// by repeatedly merging the closest pair of clusters using the definition of
+// the linkage.
+func naiveLinkage(x *mat.Dense, link Linkage) []float64 {
+	n, _ := x.Dims()
+	clusters := make([][]int, n)
+	for i := range clusters {
+		clusters[i] = []int{i}
+	}
+	dist := func(a, b []int) float64 {
+		switch link {
+		case Ward:
+			ca := centroids(rowsOf(x), labelsOf(n, a), 1)[0]
+			cb := centroids(rowsOf(x), labelsOf(n, b), 1)[0]
+			na, nb := float64(len(a)), float64(len(b))
+			return math.Sqrt(2 * na * nb / (na + nb) * sqDist(ca, cb))
+		}
+		var v float64
+		switch link {
+		case Single:
+			v = math.Inf(1)
+		case Complete:
+			v = math.Inf(-1)
+		}
+		for _, i := range a {
+			for _, j := range b {
+				d := math.Sqrt(sqDist(x.RawRowView(i), x.RawRowView(j)))
+				switch link {
+				case Single:
+					v = math.Min(v, d)
+				case Complete:
+					v = math.Max(v, d)
+				case Average:
+					v += d / float64(len(a)*len(b))
+				}
+			}
+		}
+		return v
+	}
+	var heights []float64
+	for len(clusters) > 1 {
+		bi, bj := 0, 1
+		min := math.Inf(1)
+		for i := range clusters {
+			for j := i + 1; j < len(clusters); j++ {
+				if d := dist(clusters[i], clusters[j]); d < min {
+					bi, bj, min = i, j, d
+				}
+			}
+		}
+		heights = append(heights, min)
+		clusters[bi] = append(clusters[bi], clusters[bj]...)
+		clusters = append(clusters[:bj], clusters[bj+1:]...)
+	}
+	return heights
+}
+
+// labelsOf returns labels for n observations with members
+// labeled 0 and others labeled Noise.
+func labelsOf(n int, members []int) []int {
+	l := make([]int, n)
+	for i := range l {
+		l[i] = Noise
+	}
+	for _, i := range members {
+		l[i] = 0
+	}
+	return l
+}
+
+func TestAgglomerative(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	const n = 25
+	x := mat.NewDense(n, 3, nil)
+	for i := 0; i < n; i++ {
+		for j := 0; j < 3; j++ {
+			x.Set(i, j, rnd.NormFloat64())
+		}
+	}
+	d := distances(x)
+	for _, link := range []Linkage{Single, Complete, Average, Ward} {
+		dend := Agglomerative(d, link)
+		if dend.Len() != n {
+			t.Fatalf("unexpected number of observations for linkage %d: %d", link, dend.Len())
+		}
+		got := make([]float64, n-1)
+		for i, m := range dend.Merges {
+			got[i] = m.Height
+			if m.A >= n+i || m.B >= n+i || m.A == m.B {
+				t.Errorf("invalid merge %d for linkage %d: %+v", i, link, m)
+			}
+		}
+		if !sort.Float64sAreSorted(got) {
+			t.Errorf("merge heights not sorted for linkage %d", link)
+		}
+		want := naiveLinkage(x, link)
+		if !floats.EqualApprox(got, want, 1e-10) {
+			t.Errorf("merge heights mismatch for linkage %d:\ngot  %v\nwant %v", link, got, want)
+		}
+		if last := dend.Merges[n-2]; last.Size != n {
+			t.Errorf("final merge size mismatch for linkage %d: %d", link, last.Size)
+		}
+
+		order := dend.Order()
+		sorted := append([]int(nil), order...)
+		sort.Ints(sorted)
+		for i, v := range sorted {
+			if v != i {
+				t.Fatalf("order is not a permutation for linkage %d: %v", link, order)
+			}
+		}
+		for k := 1; k <= n; k++ {
+			labels := dend.Cut(k)
+			if numClusters(labels) != k {
+				t.Errorf("cut into %d clusters for linkage %d gave %d clusters", k, link, numClusters(labels))
+			}
+			// Clusters are contiguous in the leaf order.
+			seen := make(map[int]bool)
+			for i, v := range order {
+				l := labels[v]
+				if seen[l] && labels[order[i-1]] != l {
+					t.Errorf("cluster %d not contiguous in leaf order for linkage %d", l, link)
+					break
+				}
+				seen[l] = true
+			}
+		}
+	}
+}
+
+func TestAgglomerativeSmall(t *testing.T) {
+	for _, link := range []Linkage{Single, Complete, Average, Ward} {
+		dend := Agglomerative(&mat.SymDense{}, link)
+		if dend.Len() != 0 || len(dend.Merges) != 0 {
+			t.Errorf("unexpected dendrogram for no observations with linkage %d: len=%d merges=%v", link, dend.Len(), dend.Merges)
+		}
+		if got := dend.Order(); len(got) != 0 {
+			t.Errorf("unexpected order for no observations with linkage %d: %v", link, got)
+		}
+		if got := dend.CutHeight(1); len(got) != 0 {
+			t.Errorf("unexpected labels for no observations with linkage %d: %v", link, got)
+		}
+
+		dend = Agglomerative(mat.NewSymDense(1, nil), link)
+		if dend.Len() != 1 || len(dend.Merges) != 0 {
+			t.Errorf("unexpected dendrogram for one observation with linkage %d: len=%d merges=%v", link, dend.Len(), dend.Merges)
+		}
+		if got := dend.Order(); len(got) != 1 || got[0] != 0 {
+			t.Errorf("unexpected order for one observation with linkage %d: %v", link, got)
+		}
+		if got := dend.Cut(1); len(got) != 1 || got[0] != 0 {
+			t.Errorf("unexpected labels for one observation with linkage %d: %v", link, got)
+		}
+	}
+}
+
+func TestDendrogramCut(t *testing.T) {
+	x := mat.NewDense(5, 1, []float64{0, 1, 3, 7, 20})
+	dend := Agglomerative(distances(x), Single)
+	want := []Merge{
+		{A: 0, B: 1, Height: 1, Size: 2},
+		{A: 2, B: 5, Height: 2, Size: 3},
+		{A: 3, B: 6, Height: 4, Size: 4},
+		{A: 4, B: 7, Height: 13, Size: 5},
+	}
+	for i, m := range dend.Merges {
+		if m != want[i] {
+			t.Errorf("merge %d mismatch: got %+v, want %+v", i, m, want[i])
+		}
+	}
+	for _, test := range []struct {
+		h    float64
+		want []int
+	}{
+		{h: 0.5, want: []int{0, 1, 2, 3, 4}},
+		{h: 1, want: []int{0, 0, 1, 2, 3}},
+		{h: 3, want: []int{0, 0, 0, 1, 2}},
+		{h: 100, want: []int{0, 0, 0, 0, 0}},
+	} {
+		got := dend.CutHeight(test.h)
+		for i := range got {
+			if got[i] != test.want[i] {
+				t.Errorf("cut at %v mismatch: got %v, want %v", test.h, got, test.want)
+				break
+			}
+		}
+	}
+}
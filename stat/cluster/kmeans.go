@@ -0,0 +1,243 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cluster
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// KMeans is a k-means clustering of a set of observations.
+type KMeans struct {
+	// Centers holds the cluster centers in its rows.
+	Centers *mat.Dense
+
+	// Labels holds the index of the center
+	// nearest to each observation.
+	Labels []int
+
+	// Inertia is the weighted sum of squared
+	// distances of the observations to their
+	// nearest center.
+	Inertia float64
+
+	// Iterations is the number of iterations
+	// performed and Converged is whether the
+	// labels were stable at termination.
+	Iterations int
+	Converged  bool
+}
+
+// KMeansPlusPlus chooses k initial cluster centers from the rows of x using
+// the k-means++ seeding of Arthur and Vassilvitskii and stores them in the rows
+// of dst. Each center is chosen with probability proportional to the weight of
+// the observation multiplied by its squared distance to the nearest center
+// already chosen. If dst is empty, it is resized to be k×d where d is the number
+// of columns of x. When dst is non-empty, KMeansPlusPlus panics if dst is not k×d.
+//
+// If weights is nil then all the weights are 1. If src is not nil it is used to
+// generate random numbers, otherwise rand.Float64 is used. KMeansPlusPlus panics
+// if k is not in [1, n] where n is the number of rows of x.
+func KMeansPlusPlus(dst *mat.Dense, x mat.Matrix, weights []float64, k int, src rand.Source) {
+	n, d := x.Dims()
+	if k < 1 || n < k {
+		panic(badK)
+	}
+	checkWeights(n, weights)
+	if dst.IsEmpty() {
+		dst.ReuseAs(k, d)
+	} else if r, c := dst.Dims(); r != k || c != d {
+		panic(badDims)
+	}
+	f64 := rand.Float64
+	if src != nil {
+		f64 = rand.New(src).Float64
+	}
+
+	rows := rowsOf(x)
+	prob := make([]float64, n)
+	for i := range prob {
+		prob[i] = weightAt(weights, i)
+	}
+	minDist := make([]float64, n)
+	for i := range minDist {
+		minDist[i] = math.Inf(1)
+	}
+	for c := 0; c < k; c++ {
+		idx := sampleIndex(prob, f64)
+		dst.SetRow(c, rows[idx])
+		for i, r := range rows {
+			minDist[i] = math.Min(minDist[i], sqDist(r, rows[idx]))
+			prob[i] = weightAt(weights, i) * minDist[i]
+		}
+	}
+}
+
+// sampleIndex returns an index sampled with probability proportional to prob.
+// If all the probabilities are zero the index is chosen uniformly.
+func sampleIndex(prob []float64, f64 func() float64) int {
+	sum := floats.Sum(prob)
+	if sum == 0 {
+		return int(f64() * float64(len(prob)))
+	}
+	u := f64() * sum
+	last := 0
+	for i, p := range prob {
+		if p == 0 {
+			continue
+		}
+		last = i
+		u -= p
+		if u < 0 {
+			return i
+		}
+	}
+	return last
+}
+
+// FitKMeans performs Lloyd's algorithm to cluster the rows of x starting from the
+// initial cluster centers held in the rows of centers, performing at most maxIter
+// iterations. The iteration terminates when no observation changes cluster.
+// If a cluster becomes empty its center is left unchanged.
+//
+// If weights is nil then all the weights are 1. FitKMeans panics if the number
+// of columns of x and centers differ.
+func FitKMeans(x mat.Matrix, weights []float64, centers mat.Matrix, maxIter int) *KMeans {
+	n, d := x.Dims()
+	k, dc := centers.Dims()
+	if d != dc {
+		panic(badDims)
+	}
+	checkWeights(n, weights)
+	rows := rowsOf(x)
+	ctrs := rowsOf(centers)
+
+	labels := make([]int, n)
+	for i := range labels {
+		labels[i] = -1
+	}
+	sums := make([][]float64, k)
+	for i := range sums {
+		sums[i] = make([]float64, d)
+	}
+	mass := make([]float64, k)
+	km := &KMeans{Labels: labels}
+	for km.Iterations < maxIter {
+		km.Iterations++
+		changed := false
+		for i, r := range rows {
+			l, _ := nearest(ctrs, r)
+			if l != labels[i] {
+				labels[i] = l
+				changed = true
+			}
+		}
+		if !changed {
+			km.Converged = true
+			break
+		}
+		for c := range sums {
+			for j := range sums[c] {
+				sums[c][j] = 0
+			}
+			mass[c] = 0
+		}
+		for i, r := range rows {
+			w := weightAt(weights, i)
+			floats.AddScaled(sums[labels[i]], w, r)
+			mass[labels[i]] += w
+		}
+		for c := range ctrs {
+			if mass[c] > 0 {
+				floats.ScaleTo(ctrs[c], 1/mass[c], sums[c])
+			}
+		}
+	}
+	km.finalize(rows, ctrs, weights)
+	return km
+}
+
+// FitMiniBatchKMeans performs the mini-batch k-means algorithm of Sculley to
+// cluster the rows of x starting from the initial cluster centers held in the
+// rows of centers. Each of the iter iterations updates the centers using a
+// batch of size observations sampled uniformly with replacement, with a per
+// center learning rate of the inverse of the number of observations assigned
+// to it so far. The returned labels and inertia are computed over all the
+// observations.
+//
+// If src is not nil it is used to generate random numbers, otherwise
+// rand.Intn is used. FitMiniBatchKMeans panics if the number of columns of
+// x and centers differ or if size is not positive.
+func FitMiniBatchKMeans(x mat.Matrix, centers mat.Matrix, size, iter int, src rand.Source) *KMeans {
+	n, d := x.Dims()
+	_, dc := centers.Dims()
+	if d != dc {
+		panic(badDims)
+	}
+	if size < 1 {
+		panic("cluster: non-positive batch size")
+	}
+	intn := rand.Intn
+	if src != nil {
+		intn = rand.New(src).Intn
+	}
+	rows := rowsOf(x)
+	ctrs := rowsOf(centers)
+
+	counts := make([]float64, len(ctrs))
+	batch := make([]int, size)
+	assign := make([]int, size)
+	for it := 0; it < iter; it++ {
+		for i := range batch {
+			batch[i] = intn(n)
+			assign[i], _ = nearest(ctrs, rows[batch[i]])
+		}
+		for i, idx := range batch {
+			c := assign[i]
+			counts[c]++
+			eta := 1 / counts[c]
+			floats.Scale(1-eta, ctrs[c])
+			floats.AddScaled(ctrs[c], eta, rows[idx])
+		}
+	}
+	km := &KMeans{Labels: make([]int, n), Iterations: iter}
+	km.finalize(rows, ctrs, nil)
+	return km
+}
+
+// finalize sets the centers, labels and inertia of the receiver.
+func (km *KMeans) finalize(rows, ctrs [][]float64, weights []float64) {
+	km.Centers = mat.NewDense(len(ctrs), len(ctrs[0]), nil)
+	for c, v := range ctrs {
+		km.Centers.SetRow(c, v)
+	}
+	km.Inertia = 0
+	for i, r := range rows {
+		var dist float64
+		km.Labels[i], dist = nearest(ctrs, r)
+		km.Inertia += weightAt(weights, i) * dist
+	}
+}
+
+// Predict returns the index of the cluster center nearest to x.
+func (km *KMeans) Predict(x []float64) int {
+	k, d := km.Centers.Dims()
+	if len(x) != d {
+		panic(badDims)
+	}
+	best := -1
+	min := math.Inf(1)
+	for c := 0; c < k; c++ {
+		dist := sqDist(km.Centers.RawRowView(c), x)
+		if dist < min {
+			best, min = c, dist
+		}
+	}
+	return best
+}
@@ -0,0 +1,114 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cluster
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+var blobCenters = [][]float64{{0, 0}, {10, 0}, {5, 8}}
+
+func TestKMeans(t *testing.T) {
+	src := rand.NewSource(1)
+	x, want := blobs(100, blobCenters, []float64{1, 1, 1}, src)
+
+	var init mat.Dense
+	KMeansPlusPlus(&init, x, nil, 3, src)
+	km := FitKMeans(x, nil, &init, 100)
+	if !km.Converged {
+		t.Errorf("k-means did not converge")
+	}
+	if !samePartition(km.Labels, want) {
+		t.Errorf("k-means did not recover clusters")
+	}
+	for c := 0; c < 3; c++ {
+		l := km.Predict(blobCenters[c])
+		if !floats.EqualApprox(km.Centers.RawRowView(l), blobCenters[c], 0.3) {
+			t.Errorf("center %d not recovered: got %v", c, km.Centers.RawRowView(l))
+		}
+	}
+	var inertia float64
+	for i := 0; i < 300; i++ {
+		inertia += sqDist(x.RawRowView(i), km.Centers.RawRowView(km.Labels[i]))
+	}
+	if math.Abs(inertia-km.Inertia) > 1e-10*inertia {
+		t.Errorf("inertia mismatch: got %v, want %v", km.Inertia, inertia)
+	}
+	s := Silhouette(distances(x), km.Labels)
+	if s < 0.7 {
+		t.Errorf("unexpectedly low silhouette for separated clusters: %v", s)
+	}
+	if db := DaviesBouldin(x, km.Labels); db > 0.5 {
+		t.Errorf("unexpectedly high Davies–Bouldin index for separated clusters: %v", db)
+	}
+
+	mb := FitMiniBatchKMeans(x, &init, 20, 200, src)
+	if !samePartition(mb.Labels, want) {
+		t.Errorf("mini-batch k-means did not recover clusters")
+	}
+	if mb.Inertia > 1.05*km.Inertia {
+		t.Errorf("mini-batch inertia too large: got %v, want close to %v", mb.Inertia, km.Inertia)
+	}
+}
+
+func TestKMeansWeights(t *testing.T) {
+	src := rand.NewSource(1)
+	rnd := rand.New(src)
+	x, _ := blobs(20, blobCenters, []float64{2, 2, 2}, src)
+	n, _ := x.Dims()
+	weights := make([]float64, n)
+	var rep [][]float64
+	for i := range weights {
+		weights[i] = float64(1 + rnd.Intn(3))
+		for k := 0; k < int(weights[i]); k++ {
+			rep = append(rep, x.RawRowView(i))
+		}
+	}
+	xr := mat.NewDense(len(rep), 2, nil)
+	for i, r := range rep {
+		xr.SetRow(i, r)
+	}
+	init := mat.NewDense(3, 2, []float64{1, 1, 8, 1, 4, 6})
+	got := FitKMeans(x, weights, init, 100)
+	want := FitKMeans(xr, nil, init, 100)
+	if !mat.EqualApprox(got.Centers, want.Centers, 1e-12) {
+		t.Errorf("weighted centers do not match replicated data")
+	}
+	if math.Abs(got.Inertia-want.Inertia) > 1e-10*want.Inertia {
+		t.Errorf("weighted inertia does not match replicated data: got %v, want %v", got.Inertia, want.Inertia)
+	}
+
+	// Observations with zero weight are never chosen as seeds.
+	zero := make([]float64, n)
+	for i := 0; i < 3; i++ {
+		zero[i*20] = 1
+	}
+	var seeds mat.Dense
+	KMeansPlusPlus(&seeds, x, zero, 3, src)
+	for c := 0; c < 3; c++ {
+		row := seeds.RawRowView(c)
+		found := false
+		for i := 0; i < 3; i++ {
+			if floats.Equal(row, x.RawRowView(i*20)) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("seed %d chosen from zero weight observation: %v", c, row)
+		}
+	}
+}
+
+func distances(x mat.Matrix) *mat.SymDense {
+	var d mat.SymDense
+	Distances(&d, x)
+	return &d
+}
@@ -0,0 +1,143 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cluster
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Silhouette returns the mean silhouette coefficient of the clustering of n
+// observations with the pairwise dissimilarities held in dis. Observations
+// labeled Noise are excluded. See SilhouetteSamples for the definition of the
+// coefficient.
+func Silhouette(dis mat.Symmetric, labels []int) float64 {
+	s := SilhouetteSamples(nil, dis, labels)
+	var sum float64
+	var n int
+	for i, v := range s {
+		if labels[i] < 0 {
+			continue
+		}
+		sum += v
+		n++
+	}
+	return sum / float64(n)
+}
+
+// SilhouetteSamples computes the silhouette coefficient of each observation
+// and stores it in dst. The silhouette coefficient of observation i is
+//  s(i) = (b(i) - a(i)) / max(a(i), b(i))
+// where a(i) is the mean dissimilarity of i to the other members of its cluster
+// and b(i) is the smallest mean dissimilarity of i to the members of another
+// cluster. The coefficient is zero for members of singleton clusters and NaN
+// for observations labeled Noise.
+//
+// If dst is nil a new slice is allocated and returned. If dst is not nil it
+// must have length n. SilhouetteSamples panics if len(labels) is not n.
+func SilhouetteSamples(dst []float64, dis mat.Symmetric, labels []int) []float64 {
+	n := dis.Symmetric()
+	if len(labels) != n {
+		panic(badLength)
+	}
+	if dst == nil {
+		dst = make([]float64, n)
+	}
+	if len(dst) != n {
+		panic(badLength)
+	}
+	k := numClusters(labels)
+	counts := make([]float64, k)
+	for _, l := range labels {
+		if l >= 0 {
+			counts[l]++
+		}
+	}
+	sums := make([]float64, k)
+	for i, li := range labels {
+		if li < 0 {
+			dst[i] = math.NaN()
+			continue
+		}
+		if counts[li] == 1 {
+			dst[i] = 0
+			continue
+		}
+		for c := range sums {
+			sums[c] = 0
+		}
+		for j, lj := range labels {
+			if lj < 0 || j == i {
+				continue
+			}
+			sums[lj] += dis.At(i, j)
+		}
+		a := sums[li] / (counts[li] - 1)
+		b := math.Inf(1)
+		for c, s := range sums {
+			if c == li || counts[c] == 0 {
+				continue
+			}
+			b = math.Min(b, s/counts[c])
+		}
+		if math.IsInf(b, 1) {
+			dst[i] = 0
+			continue
+		}
+		dst[i] = (b - a) / math.Max(a, b)
+	}
+	return dst
+}
+
+// DaviesBouldin returns the Davies–Bouldin index of the clustering of the rows
+// of x, given by
+//  DB = 1/k * \sum_i max_{j≠i} (s_i + s_j) / ||c_i - c_j||
+// where c_i is the centroid of cluster i and s_i is the mean Euclidean distance
+// of its members to c_i. Lower values indicate better separated clusters.
+// Observations labeled Noise are excluded.
+//
+// DaviesBouldin panics if len(labels) is not the number of rows of x.
+func DaviesBouldin(x mat.Matrix, labels []int) float64 {
+	n, _ := x.Dims()
+	if len(labels) != n {
+		panic(badLength)
+	}
+	rows := rowsOf(x)
+	k := numClusters(labels)
+	ctrs := centroids(rows, labels, k)
+	scatter := make([]float64, k)
+	counts := make([]float64, k)
+	for i, l := range labels {
+		if l < 0 {
+			continue
+		}
+		scatter[l] += math.Sqrt(sqDist(rows[i], ctrs[l]))
+		counts[l]++
+	}
+	var db float64
+	var m int
+	for i := range ctrs {
+		if counts[i] == 0 {
+			continue
+		}
+		scatter[i] /= counts[i]
+	}
+	for i := range ctrs {
+		if counts[i] == 0 {
+			continue
+		}
+		m++
+		var max float64
+		for j := range ctrs {
+			if j == i || counts[j] == 0 {
+				continue
+			}
+			max = math.Max(max, (scatter[i]+scatter[j])/math.Sqrt(sqDist(ctrs[i], ctrs[j])))
+		}
+		db += max
+	}
+	return db / float64(m)
+}
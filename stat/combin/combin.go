@@ -7,6 +7,8 @@ package combin
 import (
 	"math"
 	"sort"
+
+	"golang.org/x/exp/rand"
 )
 
 const (
@@ -518,6 +520,30 @@ func (p *PermutationGenerator) Permutation(dst []int) []int {
 	return dst
 }
 
+// UniformPermutation is a uniform random permutation generator.
+type UniformPermutation struct {
+	shuffle func(n int, swap func(i, j int))
+}
+
+// NewUniformPermutation returns a UniformPermutation that uses src as the
+// source of randomness. If src is nil, the global rand source is used.
+func NewUniformPermutation(src rand.Source) *UniformPermutation {
+	shuffle := rand.Shuffle
+	if src != nil {
+		shuffle = rand.New(src).Shuffle
+	}
+	return &UniformPermutation{shuffle: shuffle}
+}
+
+// Permute fills dst with a uniformly random permutation of the integers
+// 0 to len(dst)-1.
+func (p *UniformPermutation) Permute(dst []int) {
+	for i := range dst {
+		dst[i] = i
+	}
+	p.shuffle(len(dst), func(i, j int) { dst[i], dst[j] = dst[j], dst[i] })
+}
+
 // PermutationIndex returns the index of the given permutation.
 //
 // The functions PermutationIndex and IndexToPermutation define a bijection
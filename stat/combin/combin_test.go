@@ -10,6 +10,8 @@ import (
 	"strconv"
 	"testing"
 
+	"golang.org/x/exp/rand"
+
 	"gonum.org/v1/gonum/floats/scalar"
 )
 
@@ -371,3 +373,24 @@ func TestPermutationGenerator(t *testing.T) {
 		}
 	}
 }
+
+func TestUniformPermutation(t *testing.T) {
+	// Each of the 3! permutations is equally likely.
+	const (
+		n    = 3
+		reps = 60000
+	)
+	p := NewUniformPermutation(rand.NewSource(1))
+	counts := make([]int, NumPermutations(n, n))
+	perm := make([]int, n)
+	for i := 0; i < reps; i++ {
+		p.Permute(perm)
+		counts[PermutationIndex(perm, n, n)]++
+	}
+	want := reps / len(counts)
+	for i, c := range counts {
+		if c < want-500 || want+500 < c {
+			t.Errorf("unexpected count for permutation %v: got:%d want:%d", IndexToPermutation(nil, i, n, n), c, want)
+		}
+	}
+}
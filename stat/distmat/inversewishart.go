@@ -0,0 +1,162 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package distmat
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/mathext"
+)
+
+// InverseWishart is a distribution over d×d positive symmetric definite
+// matrices whose inverses are Wishart distributed. It is parametrized by
+// a scalar degrees of freedom parameter ν and a d×d positive definite scale
+// matrix Ψ.
+//
+// The inverse Wishart PDF is given by
+//  p(X) = [|Ψ|^(ν/2) * |X|^(-(ν+d+1)/2) * exp(-tr(Ψ * X^-1)/2)] / [2^(ν*d/2) * Γ_d(ν/2)]
+// where X is a d×d PSD matrix, ν > d-1, |·| denotes the determinant, tr is the
+// trace and Γ_d is the multivariate gamma function.
+//
+// See https://en.wikipedia.org/wiki/Inverse-Wishart_distribution for more information.
+type InverseWishart struct {
+	nu  float64
+	dim int
+
+	psi       mat.SymDense
+	logdetpsi float64
+
+	// wishart is the distribution of the inverse.
+	wishart *Wishart
+}
+
+// NewInverseWishart returns a new inverse Wishart distribution with the given
+// scale matrix and degrees of freedom parameter. NewInverseWishart returns
+// whether the creation was successful.
+//
+// NewInverseWishart panics if nu <= d - 1 where d is the order of psi.
+func NewInverseWishart(psi mat.Symmetric, nu float64, src rand.Source) (*InverseWishart, bool) {
+	dim := psi.Symmetric()
+	if nu <= float64(dim-1) {
+		panic("inversewishart: nu must be greater than dim-1")
+	}
+	var chol mat.Cholesky
+	ok := chol.Factorize(psi)
+	if !ok {
+		return nil, false
+	}
+	var psiInv mat.SymDense
+	err := chol.InverseTo(&psiInv)
+	if err != nil {
+		return nil, false
+	}
+	w, ok := NewWishart(&psiInv, nu, src)
+	if !ok {
+		return nil, false
+	}
+	iw := &InverseWishart{
+		nu:        nu,
+		dim:       dim,
+		logdetpsi: chol.LogDet(),
+		wishart:   w,
+	}
+	iw.psi = *mat.NewSymDense(dim, nil)
+	iw.psi.CopySym(psi)
+	return iw, true
+}
+
+// MeanSymTo calculates the mean matrix of the distribution in and stores it in dst.
+// If dst is empty, it is resized to be an d×d symmetric matrix where d is the order
+// of the receiver. When dst is non-empty, MeanSymTo panics if dst is not d×d.
+//
+// The mean is only defined when ν > d+1. If this is not the case, all elements
+// of dst are set to +∞.
+func (w *InverseWishart) MeanSymTo(dst *mat.SymDense) {
+	if dst.IsEmpty() {
+		dst.ReuseAsSym(w.dim)
+	} else if dst.Symmetric() != w.dim {
+		panic(badDim)
+	}
+	denom := w.nu - float64(w.dim) - 1
+	if denom <= 0 {
+		for i := 0; i < w.dim; i++ {
+			for j := i; j < w.dim; j++ {
+				dst.SetSym(i, j, math.Inf(1))
+			}
+		}
+		return
+	}
+	dst.ScaleSym(1/denom, &w.psi)
+}
+
+// ProbSym returns the probability of the symmetric matrix x. If x is not positive
+// definite (the Cholesky decomposition fails), it has 0 probability.
+func (w *InverseWishart) ProbSym(x mat.Symmetric) float64 {
+	return math.Exp(w.LogProbSym(x))
+}
+
+// LogProbSym returns the log of the probability of the input symmetric matrix.
+//
+// LogProbSym returns -∞ if the input matrix is not positive definite (the Cholesky
+// decomposition fails).
+func (w *InverseWishart) LogProbSym(x mat.Symmetric) float64 {
+	dim := x.Symmetric()
+	if dim != w.dim {
+		panic(badDim)
+	}
+	var chol mat.Cholesky
+	ok := chol.Factorize(x)
+	if !ok {
+		return math.Inf(-1)
+	}
+	return w.logProbSymChol(&chol)
+}
+
+// LogProbSymChol returns the log of the probability of the input symmetric matrix
+// given its Cholesky decomposition.
+func (w *InverseWishart) LogProbSymChol(cholX *mat.Cholesky) float64 {
+	dim := cholX.Symmetric()
+	if dim != w.dim {
+		panic(badDim)
+	}
+	return w.logProbSymChol(cholX)
+}
+
+func (w *InverseWishart) logProbSymChol(cholX *mat.Cholesky) float64 {
+	// The LogPDF is
+	//  ν/2 * log(|Ψ|) - (ν+d+1)/2 * log(|X|) - tr(X^-1 * Ψ)/2 - (ν*d/2)*log(2) - log(Γ_d(ν/2))
+	logdetx := cholX.LogDet()
+
+	var xinvpsi mat.Dense
+	err := cholX.SolveTo(&xinvpsi, &w.psi)
+	if err != nil {
+		return math.Inf(-1)
+	}
+	tr := mat.Trace(&xinvpsi)
+
+	fnu := w.nu
+	fdim := float64(w.dim)
+
+	return 0.5*(fnu*w.logdetpsi-(fnu+fdim+1)*logdetx-tr-fnu*fdim*math.Ln2) - mathext.MvLgamma(0.5*fnu, w.dim)
+}
+
+// RandSymTo generates a random symmetric matrix from the distribution.
+// If dst is empty, it is resized to be an d×d symmetric matrix where d is the order
+// of the receiver. When dst is non-empty, RandSymTo panics if dst is not d×d.
+func (w *InverseWishart) RandSymTo(dst *mat.SymDense) {
+	if dst.IsEmpty() {
+		dst.ReuseAsSym(w.dim)
+	} else if dst.Symmetric() != w.dim {
+		panic(badDim)
+	}
+	var c mat.Cholesky
+	w.wishart.RandCholTo(&c)
+	// The inverse is computed even when the Wishart draw
+	// is ill-conditioned, so a Condition error is ignored.
+	c.InverseTo(dst)
+}
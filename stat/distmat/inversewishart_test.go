@@ -0,0 +1,110 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package distmat
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestInverseWishart(t *testing.T) {
+	for c, test := range []struct {
+		psi *mat.SymDense
+		nu  float64
+		xs  []*mat.SymDense
+	}{
+		{
+			psi: mat.NewSymDense(2, []float64{1, 0, 0, 1}),
+			nu:  4,
+			xs: []*mat.SymDense{
+				mat.NewSymDense(2, []float64{0.9, 0.1, 0.1, 0.9}),
+			},
+		},
+		{
+			psi: mat.NewSymDense(3, []float64{0.8, 0.3, 0.1, 0.3, 0.7, -0.1, 0.1, -0.1, 7}),
+			nu:  5,
+			xs: []*mat.SymDense{
+				mat.NewSymDense(3, []float64{1, 0.2, -0.3, 0.2, 0.6, -0.2, -0.3, -0.2, 6}),
+				mat.NewSymDense(3, []float64{0.3, 0, 0, 0, 0.4, 0, 0, 0, 2}),
+			},
+		},
+	} {
+		iw, ok := NewInverseWishart(test.psi, test.nu, nil)
+		if !ok {
+			panic("bad test")
+		}
+		var chol mat.Cholesky
+		chol.Factorize(test.psi)
+		var psiInv mat.SymDense
+		chol.InverseTo(&psiInv)
+		w, ok := NewWishart(&psiInv, test.nu, nil)
+		if !ok {
+			panic("bad test")
+		}
+		dim := float64(test.psi.Symmetric())
+		for i, x := range test.xs {
+			// If X^-1 is Wishart distributed, the density of X is
+			//  p_W(X^-1) |X|^-(d+1).
+			var cx mat.Cholesky
+			cx.Factorize(x)
+			var xInv mat.SymDense
+			cx.InverseTo(&xInv)
+			want := w.LogProbSym(&xInv) - (dim+1)*cx.LogDet()
+			got := iw.LogProbSym(x)
+			if !scalar.EqualWithinAbsOrRel(got, want, 1e-12, 1e-12) {
+				t.Errorf("Case %d, test %d: got %v, want %v", c, i, got, want)
+			}
+			if lpc := iw.LogProbSymChol(&cx); math.Abs(lpc-got) > 1e-14 {
+				t.Errorf("Case %d, test %d: probability mismatch between chol and not", c, i)
+			}
+		}
+	}
+}
+
+func TestInverseWishartRand(t *testing.T) {
+	for c, test := range []struct {
+		psi     *mat.SymDense
+		nu      float64
+		samples int
+		tol     float64
+	}{
+		{
+			psi:     mat.NewSymDense(2, []float64{0.8, -0.2, -0.2, 0.7}),
+			nu:      8,
+			samples: 30000,
+			tol:     1e-2,
+		},
+		{
+			psi:     mat.NewSymDense(3, []float64{0.8, 0.3, 0.1, 0.3, 0.7, -0.1, 0.1, -0.1, 7}),
+			nu:      10,
+			samples: 30000,
+			tol:     3e-2,
+		},
+	} {
+		rnd := rand.New(rand.NewSource(1))
+		dim := test.psi.Symmetric()
+		iw, ok := NewInverseWishart(test.psi, test.nu, rnd)
+		if !ok {
+			panic("bad test")
+		}
+		mean := mat.NewSymDense(dim, nil)
+		x := mat.NewSymDense(dim, nil)
+		for i := 0; i < test.samples; i++ {
+			iw.RandSymTo(x)
+			x.ScaleSym(1/float64(test.samples), x)
+			mean.AddSym(mean, x)
+		}
+		var trueMean mat.SymDense
+		iw.MeanSymTo(&trueMean)
+		if !mat.EqualApprox(&trueMean, mean, test.tol) {
+			t.Errorf("Case %d: Mismatch between estimated and true mean. Got\n%0.4v\nWant\n%0.4v\n", c, mat.Formatted(mean), mat.Formatted(&trueMean))
+		}
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package distmat
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/mathext"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// LKJ is the Lewandowski–Kurowicka–Joe distribution over d×d correlation
+// matrices. It is parametrized by a shape parameter η > 0.
+//
+// The LKJ PDF is given by
+//  p(R) = |R|^(η-1) / c_d(η)
+// where R is a d×d correlation matrix and c_d is the normalizing constant.
+// When η = 1 the distribution is uniform over correlation matrices, larger
+// values of η concentrate the distribution around the identity.
+//
+// See Lewandowski, D., Kurowicka, D. and Joe, H. "Generating random correlation
+// matrices based on vines and extended onion method". Journal of Multivariate
+// Analysis 100 (2009) 1989-2001 for more information.
+type LKJ struct {
+	dim int
+	eta float64
+	src rand.Source
+
+	logNorm float64
+}
+
+// NewLKJ returns a new LKJ distribution over dim×dim correlation matrices with
+// the shape parameter eta. NewLKJ panics if dim < 1 or eta <= 0.
+func NewLKJ(dim int, eta float64, src rand.Source) *LKJ {
+	if dim < 1 {
+		panic("lkj: dimension less than 1")
+	}
+	if eta <= 0 {
+		panic("lkj: non-positive eta")
+	}
+	// The normalizing constant from equation 16 of Lewandowski et al. is
+	//  c_d = 2^(\sum_{k=1}^{d-1} (2η-2+d-k)(d-k)) * \prod_{k=1}^{d-1} B(η+(d-k-1)/2, η+(d-k-1)/2)^(d-k)
+	var logNorm float64
+	for k := 1; k < dim; k++ {
+		dk := float64(dim - k)
+		b := eta + (dk-1)/2
+		logNorm += (2*eta-2+dk)*dk*math.Ln2 + dk*mathext.Lbeta(b, b)
+	}
+	return &LKJ{dim: dim, eta: eta, src: src, logNorm: logNorm}
+}
+
+// LogProbSym returns the log of the probability of the input symmetric matrix.
+//
+// LogProbSym returns -∞ if the input matrix is not a positive definite matrix
+// with a unit diagonal.
+func (l *LKJ) LogProbSym(x mat.Symmetric) float64 {
+	if x.Symmetric() != l.dim {
+		panic(badDim)
+	}
+	for i := 0; i < l.dim; i++ {
+		if x.At(i, i) != 1 {
+			return math.Inf(-1)
+		}
+	}
+	var chol mat.Cholesky
+	ok := chol.Factorize(x)
+	if !ok {
+		return math.Inf(-1)
+	}
+	return (l.eta-1)*chol.LogDet() - l.logNorm
+}
+
+// MeanSymTo calculates the mean matrix of the distribution in and stores it in dst.
+// If dst is empty, it is resized to be an d×d symmetric matrix where d is the order
+// of the receiver. When dst is non-empty, MeanSymTo panics if dst is not d×d.
+//
+// The mean of the LKJ distribution is the identity matrix.
+func (l *LKJ) MeanSymTo(dst *mat.SymDense) {
+	if dst.IsEmpty() {
+		dst.ReuseAsSym(l.dim)
+	} else if dst.Symmetric() != l.dim {
+		panic(badDim)
+	}
+	for i := 0; i < l.dim; i++ {
+		dst.SetSym(i, i, 1)
+		for j := i + 1; j < l.dim; j++ {
+			dst.SetSym(i, j, 0)
+		}
+	}
+}
+
+// ProbSym returns the probability of the symmetric matrix x.
+func (l *LKJ) ProbSym(x mat.Symmetric) float64 {
+	return math.Exp(l.LogProbSym(x))
+}
+
+// RandSymTo generates a random correlation matrix from the distribution.
+// If dst is empty, it is resized to be an d×d symmetric matrix where d is the order
+// of the receiver. When dst is non-empty, RandSymTo panics if dst is not d×d.
+func (l *LKJ) RandSymTo(dst *mat.SymDense) {
+	if dst.IsEmpty() {
+		dst.ReuseAsSym(l.dim)
+	} else if dst.Symmetric() != l.dim {
+		panic(badDim)
+	}
+	// Use the C-vine method of section 2.4 of Lewandowski et al. The partial
+	// correlations on the k^th level of the vine are independently distributed
+	// as Beta(η+(d-k-1)/2, η+(d-k-1)/2) scaled to (-1, 1), and are converted
+	// to correlations by the recursive partial correlation formula.
+	d := l.dim
+	p := make([]float64, d*d)
+	beta := l.eta + float64(d-1)/2
+	for i := 0; i < d; i++ {
+		dst.SetSym(i, i, 1)
+	}
+	for k := 0; k < d-1; k++ {
+		beta -= 0.5
+		dist := distuv.Beta{Alpha: beta, Beta: beta, Src: l.src}
+		for i := k + 1; i < d; i++ {
+			p[k*d+i] = 2*dist.Rand() - 1
+			r := p[k*d+i]
+			for m := k - 1; m >= 0; m-- {
+				r = r*math.Sqrt((1-p[m*d+i]*p[m*d+i])*(1-p[m*d+k]*p[m*d+k])) + p[m*d+i]*p[m*d+k]
+			}
+			dst.SetSym(k, i, r)
+		}
+	}
+}
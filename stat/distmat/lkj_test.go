@@ -0,0 +1,98 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package distmat
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+)
+
+func TestLKJNormalization(t *testing.T) {
+	for _, eta := range []float64{0.5, 1, 2.5} {
+		// Two dimensions, integrate over the single correlation.
+		l := NewLKJ(2, eta, nil)
+		const n = 200000
+		x := mat.NewSymDense(2, []float64{1, 0, 0, 1})
+		var sum float64
+		for i := 0; i < n; i++ {
+			r := -1 + 2*(float64(i)+0.5)/n
+			x.SetSym(0, 1, r)
+			sum += l.ProbSym(x)
+		}
+		sum *= 2.0 / n
+		if math.Abs(sum-1) > 1e-2 {
+			t.Errorf("Dimension 2, eta %v: density integrates to %v", eta, sum)
+		}
+	}
+	for _, eta := range []float64{1, 2, 4} {
+		// Three dimensions, integrate over the cube of correlations
+		// using points on a regular grid.
+		l := NewLKJ(3, eta, nil)
+		const n = 60
+		x := mat.NewSymDense(3, []float64{1, 0, 0, 0, 1, 0, 0, 0, 1})
+		var sum float64
+		for i := 0; i < n; i++ {
+			x.SetSym(0, 1, -1+2*(float64(i)+0.5)/n)
+			for j := 0; j < n; j++ {
+				x.SetSym(0, 2, -1+2*(float64(j)+0.5)/n)
+				for k := 0; k < n; k++ {
+					x.SetSym(1, 2, -1+2*(float64(k)+0.5)/n)
+					sum += l.ProbSym(x)
+				}
+			}
+		}
+		sum *= 8.0 / (n * n * n)
+		if math.Abs(sum-1) > 2e-2 {
+			t.Errorf("Dimension 3, eta %v: density integrates to %v", eta, sum)
+		}
+	}
+}
+
+func TestLKJRand(t *testing.T) {
+	for c, test := range []struct {
+		dim int
+		eta float64
+	}{
+		{dim: 2, eta: 1},
+		{dim: 3, eta: 0.5},
+		{dim: 5, eta: 2},
+	} {
+		l := NewLKJ(test.dim, test.eta, rand.NewSource(1))
+		const samples = 20000
+		offDiag := make([][]float64, test.dim*test.dim)
+		var x mat.SymDense
+		var chol mat.Cholesky
+		for s := 0; s < samples; s++ {
+			l.RandSymTo(&x)
+			if !chol.Factorize(&x) {
+				t.Fatalf("Case %d: sample not positive definite", c)
+			}
+			for i := 0; i < test.dim; i++ {
+				if x.At(i, i) != 1 {
+					t.Fatalf("Case %d: sample diagonal not unity", c)
+				}
+				for j := i + 1; j < test.dim; j++ {
+					offDiag[i*test.dim+j] = append(offDiag[i*test.dim+j], x.At(i, j))
+				}
+			}
+		}
+		// The marginal distribution of each correlation is a Beta(b, b)
+		// on (-1, 1) with b = η-1+d/2, so its variance is 1/(2η+d-1).
+		wantVar := 1 / (2*test.eta + float64(test.dim) - 1)
+		for i := 0; i < test.dim; i++ {
+			for j := i + 1; j < test.dim; j++ {
+				mean, v := stat.MeanVariance(offDiag[i*test.dim+j], nil)
+				if math.Abs(mean) > 2e-2 || math.Abs(v-wantVar) > 2e-2 {
+					t.Errorf("Case %d, element (%d,%d): got mean %v variance %v, want 0 and %v", c, i, j, mean, v, wantVar)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package distmat
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+const logTwoPi = 1.8378770664093454835606594728112352797227949472755668
+
+// MatrixNormal is a distribution over n×p real matrices. It is parametrized by
+// an n×p mean matrix M, an n×n positive definite row covariance matrix U and a
+// p×p positive definite column covariance matrix V.
+//
+// The matrix normal PDF is given by
+//  p(X) = exp(-tr(V^-1 * (X-M)ᵀ * U^-1 * (X-M))/2) / [(2π)^(np/2) * |V|^(n/2) * |U|^(p/2)]
+// and X is distributed as the matrix normal if and only if vec(X) is
+// distributed as the multivariate normal with mean vec(M) and covariance V⊗U.
+//
+// See https://en.wikipedia.org/wiki/Matrix_normal_distribution for more information.
+type MatrixNormal struct {
+	r, c int
+	src  rand.Source
+
+	mean  mat.Dense
+	cholU mat.Cholesky
+	cholV mat.Cholesky
+	lower mat.TriDense // lower Cholesky factor of U.
+	upper mat.TriDense // upper Cholesky factor of V.
+
+	logNorm float64
+}
+
+// NewMatrixNormal returns a new matrix normal distribution with the given mean,
+// row covariance and column covariance matrices. NewMatrixNormal returns whether
+// the creation was successful, which is false if either covariance matrix is not
+// positive definite.
+//
+// NewMatrixNormal panics if the mean matrix is r×c and u is not r×r or v is not c×c.
+func NewMatrixNormal(mean mat.Matrix, u, v mat.Symmetric, src rand.Source) (*MatrixNormal, bool) {
+	r, c := mean.Dims()
+	if u.Symmetric() != r || v.Symmetric() != c {
+		panic(badDim)
+	}
+	m := &MatrixNormal{r: r, c: c, src: src}
+	if !m.cholU.Factorize(u) {
+		return nil, false
+	}
+	if !m.cholV.Factorize(v) {
+		return nil, false
+	}
+	m.mean.CloneFrom(mean)
+	m.cholU.LTo(&m.lower)
+	m.cholV.UTo(&m.upper)
+	fr, fc := float64(r), float64(c)
+	m.logNorm = -0.5 * (fr*fc*logTwoPi + fr*m.cholV.LogDet() + fc*m.cholU.LogDet())
+	return m, true
+}
+
+// Dims returns the dimensions of the matrices in the distribution.
+func (m *MatrixNormal) Dims() (r, c int) {
+	return m.r, m.c
+}
+
+// LogProb returns the log of the probability of the input matrix.
+//
+// LogProb panics if x is not the same size as the mean matrix.
+func (m *MatrixNormal) LogProb(x mat.Matrix) float64 {
+	r, c := x.Dims()
+	if r != m.r || c != m.c {
+		panic(badDim)
+	}
+	// tr(V^-1 * Dᵀ * U^-1 * D) = tr(A * B)
+	// where A = V^-1 * Dᵀ and B = U^-1 * D.
+	var d mat.Dense
+	d.Sub(x, &m.mean)
+	var a, b mat.Dense
+	err := m.cholV.SolveTo(&a, d.T())
+	if err != nil {
+		return math.Inf(-1)
+	}
+	err = m.cholU.SolveTo(&b, &d)
+	if err != nil {
+		return math.Inf(-1)
+	}
+	var tr float64
+	for i := 0; i < m.c; i++ {
+		for j := 0; j < m.r; j++ {
+			tr += a.At(i, j) * b.At(j, i)
+		}
+	}
+	return m.logNorm - 0.5*tr
+}
+
+// MeanTo calculates the mean matrix of the distribution and stores it in dst.
+// If dst is empty, it is resized to be an r×c matrix where r×c are the
+// dimensions of the receiver. When dst is non-empty, MeanTo panics if dst
+// is not r×c.
+func (m *MatrixNormal) MeanTo(dst *mat.Dense) {
+	if dst.IsEmpty() {
+		dst.ReuseAs(m.r, m.c)
+	} else if r, c := dst.Dims(); r != m.r || c != m.c {
+		panic(badDim)
+	}
+	dst.Copy(&m.mean)
+}
+
+// Prob returns the probability of the input matrix.
+func (m *MatrixNormal) Prob(x mat.Matrix) float64 {
+	return math.Exp(m.LogProb(x))
+}
+
+// RandTo generates a random matrix from the distribution and stores it in dst.
+// If dst is empty, it is resized to be an r×c matrix where r×c are the
+// dimensions of the receiver. When dst is non-empty, RandTo panics if dst
+// is not r×c.
+func (m *MatrixNormal) RandTo(dst *mat.Dense) {
+	if dst.IsEmpty() {
+		dst.ReuseAs(m.r, m.c)
+	} else if r, c := dst.Dims(); r != m.r || c != m.c {
+		panic(badDim)
+	}
+	// If Z is a matrix of independent standard normal variables, then
+	//  X = M + L_U * Z * U_V
+	// is distributed according to the matrix normal, where U = L_U * L_Uᵀ
+	// and V = U_Vᵀ * U_V.
+	norm := distuv.Normal{Mu: 0, Sigma: 1, Src: m.src}
+	z := mat.NewDense(m.r, m.c, nil)
+	for i := 0; i < m.r; i++ {
+		for j := 0; j < m.c; j++ {
+			z.Set(i, j, norm.Rand())
+		}
+	}
+	z.Mul(&m.lower, z)
+	z.Mul(z, &m.upper)
+	dst.Add(&m.mean, z)
+}
@@ -0,0 +1,127 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package distmat
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distmv"
+)
+
+func TestMatrixNormal(t *testing.T) {
+	for c, test := range []struct {
+		mean *mat.Dense
+		u, v *mat.SymDense
+		xs   []*mat.Dense
+	}{
+		{
+			mean: mat.NewDense(2, 3, []float64{1, 2, 3, 4, 5, 6}),
+			u:    mat.NewSymDense(2, []float64{1, 0.3, 0.3, 2}),
+			v:    mat.NewSymDense(3, []float64{0.8, 0.3, 0.1, 0.3, 0.7, -0.1, 0.1, -0.1, 2}),
+			xs: []*mat.Dense{
+				mat.NewDense(2, 3, []float64{1, 2, 3, 4, 5, 6}),
+				mat.NewDense(2, 3, []float64{0.5, 2.2, 3.1, 4.5, 4.4, 7}),
+			},
+		},
+		{
+			mean: mat.NewDense(3, 1, []float64{-1, 0, 1}),
+			u:    mat.NewSymDense(3, []float64{2, -0.5, 0, -0.5, 1, 0.2, 0, 0.2, 3}),
+			v:    mat.NewSymDense(1, []float64{0.5}),
+			xs: []*mat.Dense{
+				mat.NewDense(3, 1, []float64{0, 0, 0}),
+			},
+		},
+	} {
+		m, ok := NewMatrixNormal(test.mean, test.u, test.v, nil)
+		if !ok {
+			panic("bad test")
+		}
+		r, cols := m.Dims()
+
+		// vec(X) is normally distributed with covariance V⊗U.
+		var cov mat.Dense
+		cov.Kronecker(test.v, test.u)
+		sym := mat.NewSymDense(r*cols, nil)
+		for i := 0; i < r*cols; i++ {
+			for j := i; j < r*cols; j++ {
+				sym.SetSym(i, j, cov.At(i, j))
+			}
+		}
+		norm, ok := distmv.NewNormal(vec(test.mean), sym, nil)
+		if !ok {
+			panic("bad test")
+		}
+		for i, x := range test.xs {
+			got := m.LogProb(x)
+			want := norm.LogProb(vec(x))
+			if !scalar.EqualWithinAbsOrRel(got, want, 1e-12, 1e-12) {
+				t.Errorf("Case %d, test %d: got %v, want %v", c, i, got, want)
+			}
+		}
+	}
+}
+
+func TestMatrixNormalRand(t *testing.T) {
+	mean := mat.NewDense(2, 3, []float64{1, 2, 3, 4, 5, 6})
+	u := mat.NewSymDense(2, []float64{1, 0.3, 0.3, 2})
+	v := mat.NewSymDense(3, []float64{0.8, 0.3, 0.1, 0.3, 0.7, -0.1, 0.1, -0.1, 2})
+	m, ok := NewMatrixNormal(mean, u, v, rand.NewSource(1))
+	if !ok {
+		panic("bad test")
+	}
+
+	// E[X] = M, E[(X-M)(X-M)ᵀ] = tr(V) U and E[(X-M)ᵀ(X-M)] = tr(U) V.
+	const samples = 100000
+	var x, d, sumX, rowCov, colCov, tmp mat.Dense
+	sumX.ReuseAs(2, 3)
+	rowCov.ReuseAs(2, 2)
+	colCov.ReuseAs(3, 3)
+	for i := 0; i < samples; i++ {
+		m.RandTo(&x)
+		sumX.Add(&sumX, &x)
+		d.Sub(&x, mean)
+		tmp.Mul(&d, d.T())
+		rowCov.Add(&rowCov, &tmp)
+		tmp.Reset()
+		tmp.Mul(d.T(), &d)
+		colCov.Add(&colCov, &tmp)
+		tmp.Reset()
+	}
+	sumX.Scale(1.0/samples, &sumX)
+	rowCov.Scale(1.0/samples, &rowCov)
+	colCov.Scale(1.0/samples, &colCov)
+
+	var meanTo mat.Dense
+	m.MeanTo(&meanTo)
+	if !mat.EqualApprox(&sumX, &meanTo, 2e-2) {
+		t.Errorf("Mean mismatch. Got\n%0.4v\nWant\n%0.4v\n", mat.Formatted(&sumX), mat.Formatted(mean))
+	}
+	var want mat.Dense
+	want.Scale(mat.Trace(v), u)
+	if !mat.EqualApprox(&rowCov, &want, 5e-2) {
+		t.Errorf("Row covariance mismatch. Got\n%0.4v\nWant\n%0.4v\n", mat.Formatted(&rowCov), mat.Formatted(&want))
+	}
+	want.Reset()
+	want.Scale(mat.Trace(u), v)
+	if !mat.EqualApprox(&colCov, &want, 5e-2) {
+		t.Errorf("Column covariance mismatch. Got\n%0.4v\nWant\n%0.4v\n", mat.Formatted(&colCov), mat.Formatted(&want))
+	}
+}
+
+// vec returns the column-stacked elements of a.
+func vec(a mat.Matrix) []float64 {
+	r, c := a.Dims()
+	v := make([]float64, 0, r*c)
+	for j := 0; j < c; j++ {
+		for i := 0; i < r; i++ {
+			v = append(v, a.At(i, j))
+		}
+	}
+	return v
+}
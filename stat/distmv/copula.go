@@ -0,0 +1,553 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package distmv
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// Copula is a multivariate distribution over the unit hypercube whose
+// marginals are all uniform on [0, 1]. By Sklar's theorem any continuous
+// multivariate distribution can be decomposed into its univariate marginals
+// and a copula describing their dependence.
+//
+// See https://en.wikipedia.org/wiki/Copula_(probability_theory) for more information.
+type Copula interface {
+	// Dim returns the dimension of the copula.
+	Dim() int
+
+	// LogProb returns the log of the copula density at u.
+	LogProb(u []float64) float64
+
+	// Rand generates a random point in the unit hypercube according
+	// to the copula. If u is nil, new memory is allocated, otherwise
+	// the result is stored in place.
+	Rand(u []float64) []float64
+}
+
+// Marginal is a univariate continuous distribution that may be coupled with
+// others by a Copula. All of the distuv continuous distributions implement
+// Marginal.
+type Marginal interface {
+	CDF(x float64) float64
+	LogProb(x float64) float64
+	Quantile(p float64) float64
+}
+
+// CopulaDist is a multivariate distribution constructed from a copula and a
+// set of univariate marginal distributions. The density of CopulaDist is
+//  p(x) = c(F_1(x_1), ..., F_n(x_n)) \prod_i f_i(x_i)
+// where c is the copula density and F_i and f_i are the cumulative
+// distribution function and density of the i^th marginal.
+type CopulaDist struct {
+	copula    Copula
+	marginals []Marginal
+}
+
+// NewCopulaDist returns a new distribution coupling the given marginals with
+// the copula c. NewCopulaDist panics if len(marginals) != c.Dim().
+func NewCopulaDist(c Copula, marginals []Marginal) *CopulaDist {
+	if len(marginals) != c.Dim() {
+		panic(badSizeMismatch)
+	}
+	m := make([]Marginal, len(marginals))
+	copy(m, marginals)
+	return &CopulaDist{copula: c, marginals: m}
+}
+
+// Dim returns the dimension of the distribution.
+func (d *CopulaDist) Dim() int {
+	return len(d.marginals)
+}
+
+// LogProb computes the log of the pdf of the point x.
+func (d *CopulaDist) LogProb(x []float64) float64 {
+	if len(x) != len(d.marginals) {
+		panic(badSizeMismatch)
+	}
+	u := make([]float64, len(x))
+	var lp float64
+	for i, m := range d.marginals {
+		u[i] = m.CDF(x[i])
+		lp += m.LogProb(x[i])
+	}
+	if math.IsInf(lp, -1) {
+		return lp
+	}
+	return lp + d.copula.LogProb(u)
+}
+
+// Prob computes the value of the probability density function at x.
+func (d *CopulaDist) Prob(x []float64) float64 {
+	return math.Exp(d.LogProb(x))
+}
+
+// Rand generates a random number according to the distributon.
+// If the input slice is nil, new memory is allocated, otherwise the result is stored
+// in place.
+func (d *CopulaDist) Rand(x []float64) []float64 {
+	x = reuseAs(x, len(d.marginals))
+	d.copula.Rand(x)
+	for i, m := range d.marginals {
+		x[i] = m.Quantile(x[i])
+	}
+	return x
+}
+
+// GaussianCopula is the copula of a multivariate normal distribution with
+// correlation matrix R. Its density is
+//  c(u) = |R|^(-1/2) exp(-1/2 zᵀ(R^-1 - I)z)
+// where z_i = Φ^-1(u_i) and Φ is the standard normal cumulative distribution
+// function.
+type GaussianCopula struct {
+	normal *Normal
+}
+
+// NewGaussianCopula returns a new Gaussian copula with the correlation matrix
+// of the covariance matrix sigma. NewGaussianCopula panics if sigma has
+// zero size. If sigma is not positive-definite, the returned boolean is false.
+func NewGaussianCopula(sigma mat.Symmetric, src rand.Source) (*GaussianCopula, bool) {
+	corr, ok := correlationOf(sigma)
+	if !ok {
+		return nil, false
+	}
+	n, ok := NewNormal(make([]float64, corr.Symmetric()), corr, src)
+	if !ok {
+		return nil, false
+	}
+	return &GaussianCopula{normal: n}, true
+}
+
+// Dim returns the dimension of the copula.
+func (c *GaussianCopula) Dim() int {
+	return c.normal.dim
+}
+
+// LogProb returns the log of the copula density at u. LogProb returns -∞ if
+// any element of u is outside the open interval (0, 1).
+func (c *GaussianCopula) LogProb(u []float64) float64 {
+	if len(u) != c.normal.dim {
+		panic(badSizeMismatch)
+	}
+	z := make([]float64, len(u))
+	var lp float64
+	for i, v := range u {
+		if !(0 < v && v < 1) {
+			return math.Inf(-1)
+		}
+		z[i] = distuv.UnitNormal.Quantile(v)
+		lp -= distuv.UnitNormal.LogProb(z[i])
+	}
+	return lp + c.normal.LogProb(z)
+}
+
+// Rand generates a random point in the unit hypercube according to the
+// copula. If u is nil, new memory is allocated, otherwise the result is
+// stored in place.
+func (c *GaussianCopula) Rand(u []float64) []float64 {
+	u = c.normal.Rand(u)
+	for i, v := range u {
+		u[i] = distuv.UnitNormal.CDF(v)
+	}
+	return u
+}
+
+// StudentsTCopula is the copula of a multivariate Student's t distribution
+// with correlation matrix R and ν degrees of freedom. Compared to the
+// Gaussian copula it exhibits symmetric tail dependence.
+type StudentsTCopula struct {
+	t  *StudentsT
+	uv distuv.StudentsT
+}
+
+// NewStudentsTCopula returns a new Student's t copula with the correlation
+// matrix of the covariance matrix sigma and nu degrees of freedom.
+// NewStudentsTCopula panics if sigma has zero size or nu is not positive.
+// If sigma is not positive-definite, the returned boolean is false.
+func NewStudentsTCopula(sigma mat.Symmetric, nu float64, src rand.Source) (*StudentsTCopula, bool) {
+	if nu <= 0 {
+		panic("copula: non-positive degrees of freedom")
+	}
+	corr, ok := correlationOf(sigma)
+	if !ok {
+		return nil, false
+	}
+	t, ok := NewStudentsT(make([]float64, corr.Symmetric()), corr, nu, src)
+	if !ok {
+		return nil, false
+	}
+	return &StudentsTCopula{
+		t:  t,
+		uv: distuv.StudentsT{Mu: 0, Sigma: 1, Nu: nu},
+	}, true
+}
+
+// Dim returns the dimension of the copula.
+func (c *StudentsTCopula) Dim() int {
+	return c.t.dim
+}
+
+// LogProb returns the log of the copula density at u. LogProb returns -∞ if
+// any element of u is outside the open interval (0, 1).
+func (c *StudentsTCopula) LogProb(u []float64) float64 {
+	if len(u) != c.t.dim {
+		panic(badSizeMismatch)
+	}
+	z := make([]float64, len(u))
+	var lp float64
+	for i, v := range u {
+		if !(0 < v && v < 1) {
+			return math.Inf(-1)
+		}
+		z[i] = c.uv.Quantile(v)
+		lp -= c.uv.LogProb(z[i])
+	}
+	return lp + c.t.LogProb(z)
+}
+
+// Rand generates a random point in the unit hypercube according to the
+// copula. If u is nil, new memory is allocated, otherwise the result is
+// stored in place.
+func (c *StudentsTCopula) Rand(u []float64) []float64 {
+	u = c.t.Rand(u)
+	for i, v := range u {
+		u[i] = c.uv.CDF(v)
+	}
+	return u
+}
+
+// correlationOf returns the correlation matrix corresponding to the
+// covariance matrix sigma.
+func correlationOf(sigma mat.Symmetric) (*mat.SymDense, bool) {
+	n := sigma.Symmetric()
+	if n == 0 {
+		panic(badZeroDimension)
+	}
+	sd := make([]float64, n)
+	for i := range sd {
+		v := sigma.At(i, i)
+		if v <= 0 {
+			return nil, false
+		}
+		sd[i] = math.Sqrt(v)
+	}
+	corr := mat.NewSymDense(n, nil)
+	for i := 0; i < n; i++ {
+		corr.SetSym(i, i, 1)
+		for j := i + 1; j < n; j++ {
+			corr.SetSym(i, j, sigma.At(i, j)/(sd[i]*sd[j]))
+		}
+	}
+	return corr, true
+}
+
+// ClaytonCopula is the Archimedean copula with generator
+//  ψ(t) = (1+t)^(-1/θ)
+// for θ > 0. The Clayton copula exhibits lower tail dependence.
+//
+// See https://en.wikipedia.org/wiki/Copula_(probability_theory)#Archimedean_copulas
+// for more information.
+type ClaytonCopula struct {
+	dim   int
+	theta float64
+	src   rand.Source
+}
+
+// NewClaytonCopula returns a new dim-dimensional Clayton copula with parameter
+// theta. NewClaytonCopula panics if dim < 2 or theta <= 0.
+func NewClaytonCopula(dim int, theta float64, src rand.Source) *ClaytonCopula {
+	if dim < 2 {
+		panic("copula: dimension less than 2")
+	}
+	if theta <= 0 {
+		panic("copula: non-positive theta")
+	}
+	return &ClaytonCopula{dim: dim, theta: theta, src: src}
+}
+
+// Dim returns the dimension of the copula.
+func (c *ClaytonCopula) Dim() int {
+	return c.dim
+}
+
+// LogProb returns the log of the copula density at u. LogProb returns -∞ if
+// any element of u is outside the open interval (0, 1).
+func (c *ClaytonCopula) LogProb(u []float64) float64 {
+	if len(u) != c.dim {
+		panic(badSizeMismatch)
+	}
+	theta := c.theta
+	var lp, t float64
+	for i, v := range u {
+		if !(0 < v && v < 1) {
+			return math.Inf(-1)
+		}
+		lp += math.Log1p(float64(i)*theta) - (1+theta)*math.Log(v)
+		t += math.Pow(v, -theta) - 1
+	}
+	return lp - (float64(c.dim)+1/theta)*math.Log1p(t)
+}
+
+// Rand generates a random point in the unit hypercube according to the
+// copula. If u is nil, new memory is allocated, otherwise the result is
+// stored in place.
+func (c *ClaytonCopula) Rand(u []float64) []float64 {
+	// Use the Marshall–Olkin algorithm, the frailty is Γ(1/θ, 1).
+	v := distuv.Gamma{Alpha: 1 / c.theta, Beta: 1, Src: c.src}.Rand()
+	return archimedeanRand(u, c.dim, v, c.src, func(t float64) float64 {
+		return math.Pow(1+t, -1/c.theta)
+	})
+}
+
+// GumbelCopula is the Archimedean copula with generator
+//  ψ(t) = exp(-t^(1/θ))
+// for θ ≥ 1. The Gumbel copula exhibits upper tail dependence. When θ = 1
+// it is the independence copula.
+//
+// See https://en.wikipedia.org/wiki/Copula_(probability_theory)#Archimedean_copulas
+// for more information.
+type GumbelCopula struct {
+	dim   int
+	theta float64
+	src   rand.Source
+
+	// a holds the coefficients of the polynomial
+	// in t^(1/θ) of the dim^th derivative of ψ.
+	a []float64
+}
+
+// NewGumbelCopula returns a new dim-dimensional Gumbel copula with parameter
+// theta. NewGumbelCopula panics if dim < 2 or theta < 1.
+func NewGumbelCopula(dim int, theta float64, src rand.Source) *GumbelCopula {
+	if dim < 2 {
+		panic("copula: dimension less than 2")
+	}
+	if theta < 1 {
+		panic("copula: theta less than 1")
+	}
+	// The derivatives of the generator are given in
+	//  Hofert, M., Mächler, M. and McNeil, A. J. "Likelihood inference for
+	//  Archimedean copulas in high dimensions under known margins".
+	//  Journal of Multivariate Analysis 110 (2012) 133-150.
+	// as
+	//  ψ^(d)(t) = (-1)^d ψ(t)/t^d \sum_{k=1}^d a_{dk}(α) t^(αk)
+	// with α = 1/θ and
+	//  a_{dk}(α) = (-1)^(d-k) \sum_{j=k}^d α^j s(d,j) S(j,k)
+	// where s and S are the Stirling numbers of the first and second kind.
+	alpha := 1 / theta
+	s1 := stirlingFirst(dim)
+	s2 := stirlingSecond(dim)
+	a := make([]float64, dim+1)
+	for k := 1; k <= dim; k++ {
+		var sum float64
+		for j := k; j <= dim; j++ {
+			sum += math.Pow(alpha, float64(j)) * s1[dim][j] * s2[j][k]
+		}
+		if (dim-k)%2 == 1 {
+			sum = -sum
+		}
+		a[k] = sum
+	}
+	return &GumbelCopula{dim: dim, theta: theta, src: src, a: a}
+}
+
+// Dim returns the dimension of the copula.
+func (c *GumbelCopula) Dim() int {
+	return c.dim
+}
+
+// LogProb returns the log of the copula density at u. LogProb returns -∞ if
+// any element of u is outside the open interval (0, 1).
+func (c *GumbelCopula) LogProb(u []float64) float64 {
+	if len(u) != c.dim {
+		panic(badSizeMismatch)
+	}
+	theta := c.theta
+	var lp, t float64
+	for _, v := range u {
+		if !(0 < v && v < 1) {
+			return math.Inf(-1)
+		}
+		l := -math.Log(v)
+		t += math.Pow(l, theta)
+		// log |(ψ^-1)'(v)| = log θ + (θ-1) log(-log v) - log v.
+		lp += math.Log(theta) + (theta-1)*math.Log(l) + l
+	}
+	ta := math.Pow(t, 1/theta)
+	var poly float64
+	for k := c.dim; k >= 1; k-- {
+		poly = poly*ta + c.a[k]
+	}
+	poly *= ta
+	return lp - ta - float64(c.dim)*math.Log(t) + math.Log(poly)
+}
+
+// Rand generates a random point in the unit hypercube according to the
+// copula. If u is nil, new memory is allocated, otherwise the result is
+// stored in place.
+func (c *GumbelCopula) Rand(u []float64) []float64 {
+	// Use the Marshall–Olkin algorithm, the frailty is a positive
+	// stable random variable with Laplace transform exp(-t^(1/θ)),
+	// generated by Kanter's representation.
+	alpha := 1 / c.theta
+	v := 1.0
+	if alpha != 1 {
+		theta := distuv.Uniform{Min: 0, Max: math.Pi, Src: c.src}.Rand()
+		w := distuv.Exponential{Rate: 1, Src: c.src}.Rand()
+		v = math.Sin(alpha*theta) / math.Pow(math.Sin(theta), 1/alpha) *
+			math.Pow(math.Sin((1-alpha)*theta)/w, (1-alpha)/alpha)
+	}
+	return archimedeanRand(u, c.dim, v, c.src, func(t float64) float64 {
+		return math.Exp(-math.Pow(t, alpha))
+	})
+}
+
+// FrankCopula is the Archimedean copula with generator
+//  ψ(t) = -log(1 - (1-exp(-θ))exp(-t))/θ
+// for θ > 0. The Frank copula has no tail dependence.
+//
+// See https://en.wikipedia.org/wiki/Copula_(probability_theory)#Archimedean_copulas
+// for more information.
+type FrankCopula struct {
+	dim   int
+	theta float64
+	src   rand.Source
+
+	// s holds the Stirling numbers of the second kind S(dim, k).
+	s []float64
+}
+
+// NewFrankCopula returns a new dim-dimensional Frank copula with parameter
+// theta. NewFrankCopula panics if dim < 2 or theta <= 0.
+func NewFrankCopula(dim int, theta float64, src rand.Source) *FrankCopula {
+	if dim < 2 {
+		panic("copula: dimension less than 2")
+	}
+	if theta <= 0 {
+		panic("copula: non-positive theta")
+	}
+	return &FrankCopula{dim: dim, theta: theta, src: src, s: stirlingSecond(dim)[dim]}
+}
+
+// Dim returns the dimension of the copula.
+func (c *FrankCopula) Dim() int {
+	return c.dim
+}
+
+// LogProb returns the log of the copula density at u. LogProb returns -∞ if
+// any element of u is outside the open interval (0, 1).
+func (c *FrankCopula) LogProb(u []float64) float64 {
+	if len(u) != c.dim {
+		panic(badSizeMismatch)
+	}
+	theta := c.theta
+	logC := math.Log(-math.Expm1(-theta))
+	var lp, t float64
+	for _, v := range u {
+		if !(0 < v && v < 1) {
+			return math.Inf(-1)
+		}
+		// ψ^-1(v) = -log((1-exp(-θv))/(1-exp(-θ))).
+		l := math.Log(-math.Expm1(-theta * v))
+		t += logC - l
+		// log |(ψ^-1)'(v)| = log θ - θv - log(1-exp(-θv)).
+		lp += math.Log(theta) - theta*v - l
+	}
+	// |ψ^(d)(t)| = Li_{1-d}(z)/θ with z = (1-exp(-θ))exp(-t), and
+	//  Li_{-n}(z) = \sum_{k=0}^n k! S(n+1,k+1) (z/(1-z))^(k+1).
+	z := math.Exp(logC - t)
+	r := z / (1 - z)
+	var li float64
+	fact := 1.0
+	pow := r
+	for k := 0; k < c.dim; k++ {
+		if k > 0 {
+			fact *= float64(k)
+		}
+		li += fact * c.s[k+1] * pow
+		pow *= r
+	}
+	return lp + math.Log(li) - math.Log(theta)
+}
+
+// Rand generates a random point in the unit hypercube according to the
+// copula. If u is nil, new memory is allocated, otherwise the result is
+// stored in place.
+func (c *FrankCopula) Rand(u []float64) []float64 {
+	// Use the Marshall–Olkin algorithm, the frailty follows a logarithmic
+	// distribution with parameter p = 1-exp(-θ), generated by Kemp's
+	// algorithm LK.
+	//
+	// Kemp, A. W. "Efficient generation of logarithmically distributed
+	// pseudo-random variables". Applied Statistics 30(3) (1981) 249-253.
+	unif := distuv.Uniform{Min: 0, Max: 1, Src: c.src}
+	theta := c.theta
+	var v float64
+	w := unif.Rand()
+	if w > 1-math.Exp(-theta) {
+		v = 1
+	} else {
+		q := -math.Expm1(-theta * unif.Rand())
+		switch {
+		case w < q*q:
+			v = math.Floor(1 + math.Log(w)/math.Log(q))
+		case w > q:
+			v = 1
+		default:
+			v = 2
+		}
+	}
+	return archimedeanRand(u, c.dim, v, c.src, func(t float64) float64 {
+		return -math.Log1p(math.Expm1(-theta)*math.Exp(-t)) / theta
+	})
+}
+
+// archimedeanRand completes a Marshall–Olkin draw from an Archimedean copula
+// with generator psi given the frailty v.
+func archimedeanRand(u []float64, dim int, v float64, src rand.Source, psi func(float64) float64) []float64 {
+	u = reuseAs(u, dim)
+	exp := distuv.Exponential{Rate: 1, Src: src}
+	for i := range u {
+		u[i] = psi(exp.Rand() / v)
+	}
+	return u
+}
+
+// stirlingFirst returns the signed Stirling numbers of the first kind s(n, k)
+// for 0 ≤ k ≤ n ≤ max.
+func stirlingFirst(max int) [][]float64 {
+	s := make([][]float64, max+1)
+	for n := range s {
+		s[n] = make([]float64, max+1)
+	}
+	s[0][0] = 1
+	for n := 1; n <= max; n++ {
+		for k := 1; k <= n; k++ {
+			s[n][k] = s[n-1][k-1] - float64(n-1)*s[n-1][k]
+		}
+	}
+	return s
+}
+
+// stirlingSecond returns the Stirling numbers of the second kind S(n, k)
+// for 0 ≤ k ≤ n ≤ max.
+func stirlingSecond(max int) [][]float64 {
+	s := make([][]float64, max+1)
+	for n := range s {
+		s[n] = make([]float64, max+1)
+	}
+	s[0][0] = 1
+	for n := 1; n <= max; n++ {
+		for k := 1; k <= n; k++ {
+			s[n][k] = s[n-1][k-1] + float64(k)*s[n-1][k]
+		}
+	}
+	return s
+}
@@ -0,0 +1,228 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package distmv
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+func TestCopulaBivariateDensity(t *testing.T) {
+	const rho = 0.6
+	sigma := mat.NewSymDense(2, []float64{2, rho * 2, rho * 2, 2})
+	gauss, ok := NewGaussianCopula(sigma, nil)
+	if !ok {
+		t.Fatal("bad test")
+	}
+	gumbel := NewGumbelCopula(2, 2.5, nil)
+	frank := NewFrankCopula(2, 3, nil)
+	clayton := NewClaytonCopula(2, 1.5, nil)
+
+	for _, test := range []struct {
+		name   string
+		copula Copula
+		want   func(u, v float64) float64
+	}{
+		{
+			name:   "Gaussian",
+			copula: gauss,
+			want: func(u, v float64) float64 {
+				a := distuv.UnitNormal.Quantile(u)
+				b := distuv.UnitNormal.Quantile(v)
+				return math.Exp(-(rho*rho*(a*a+b*b)-2*rho*a*b)/(2*(1-rho*rho))) / math.Sqrt(1-rho*rho)
+			},
+		},
+		{
+			name:   "Clayton",
+			copula: clayton,
+			want: func(u, v float64) float64 {
+				const theta = 1.5
+				return (1 + theta) * math.Pow(u*v, -1-theta) * math.Pow(math.Pow(u, -theta)+math.Pow(v, -theta)-1, -2-1/theta)
+			},
+		},
+		{
+			name:   "Gumbel",
+			copula: gumbel,
+			want: func(u, v float64) float64 {
+				const theta = 2.5
+				x, y := -math.Log(u), -math.Log(v)
+				a := math.Pow(x, theta) + math.Pow(y, theta)
+				c := math.Exp(-math.Pow(a, 1/theta))
+				return c / (u * v) * math.Pow(x*y, theta-1) * math.Pow(a, 2/theta-2) * (1 + (theta-1)*math.Pow(a, -1/theta))
+			},
+		},
+		{
+			name:   "Frank",
+			copula: frank,
+			want: func(u, v float64) float64 {
+				const theta = 3
+				e := -math.Expm1(-theta)
+				d := e - (-math.Expm1(-theta*u))*(-math.Expm1(-theta*v))
+				return theta * e * math.Exp(-theta*(u+v)) / (d * d)
+			},
+		},
+	} {
+		for _, u := range []float64{0.05, 0.3, 0.5, 0.9} {
+			for _, v := range []float64{0.1, 0.45, 0.8, 0.99} {
+				got := math.Exp(test.copula.LogProb([]float64{u, v}))
+				want := test.want(u, v)
+				if !scalar.EqualWithinAbsOrRel(got, want, 1e-12, 1e-12) {
+					t.Errorf("%s density mismatch at (%v, %v): got %v, want %v", test.name, u, v, got, want)
+				}
+			}
+		}
+		if lp := test.copula.LogProb([]float64{0, 0.5}); !math.IsInf(lp, -1) {
+			t.Errorf("%s: expected -Inf LogProb outside the unit square, got %v", test.name, lp)
+		}
+	}
+}
+
+func TestCopulaIntegrates(t *testing.T) {
+	sigma := mat.NewSymDense(3, []float64{1, 0.3, -0.2, 0.3, 1, 0.4, -0.2, 0.4, 1})
+	gauss, ok := NewGaussianCopula(sigma, nil)
+	if !ok {
+		t.Fatal("bad test")
+	}
+	st, ok := NewStudentsTCopula(sigma, 5, nil)
+	if !ok {
+		t.Fatal("bad test")
+	}
+	for _, test := range []struct {
+		name   string
+		copula Copula
+	}{
+		{name: "Gaussian", copula: gauss},
+		{name: "StudentsT", copula: st},
+		{name: "Clayton", copula: NewClaytonCopula(3, 0.8, nil)},
+		{name: "Gumbel", copula: NewGumbelCopula(3, 1.4, nil)},
+		{name: "Frank", copula: NewFrankCopula(3, 2, nil)},
+	} {
+		// Integrate the density over the unit cube with the midpoint rule.
+		const n = 40
+		u := make([]float64, 3)
+		var sum float64
+		for i := 0; i < n; i++ {
+			u[0] = (float64(i) + 0.5) / n
+			for j := 0; j < n; j++ {
+				u[1] = (float64(j) + 0.5) / n
+				for k := 0; k < n; k++ {
+					u[2] = (float64(k) + 0.5) / n
+					sum += math.Exp(test.copula.LogProb(u))
+				}
+			}
+		}
+		sum /= n * n * n
+		if math.Abs(sum-1) > 2e-2 {
+			t.Errorf("%s density does not integrate to 1: got %v", test.name, sum)
+		}
+	}
+}
+
+func TestCopulaRand(t *testing.T) {
+	src := rand.NewSource(1)
+	sigma := mat.NewSymDense(2, []float64{1, 0.5, 0.5, 1})
+	gauss, ok := NewGaussianCopula(sigma, src)
+	if !ok {
+		t.Fatal("bad test")
+	}
+	st, ok := NewStudentsTCopula(sigma, 4, src)
+	if !ok {
+		t.Fatal("bad test")
+	}
+
+	// frankTau returns Kendall's τ for the Frank copula.
+	frankTau := func(theta float64) float64 {
+		const n = 10000
+		var debye float64
+		for i := 0; i < n; i++ {
+			x := (float64(i) + 0.5) / n * theta
+			debye += x / math.Expm1(x)
+		}
+		debye /= n
+		return 1 - 4/theta*(1-debye)
+	}
+
+	for _, test := range []struct {
+		copula Copula
+		tau    float64
+	}{
+		{copula: gauss, tau: 2 / math.Pi * math.Asin(0.5)},
+		{copula: st, tau: 2 / math.Pi * math.Asin(0.5)},
+		{copula: NewClaytonCopula(2, 2, src), tau: 0.5},
+		{copula: NewClaytonCopula(4, 0.5, src), tau: 0.2},
+		{copula: NewGumbelCopula(2, 2, src), tau: 0.5},
+		{copula: NewGumbelCopula(3, 1, src), tau: 0},
+		{copula: NewFrankCopula(2, 4, src), tau: frankTau(4)},
+		{copula: NewFrankCopula(3, 0.5, src), tau: frankTau(0.5)},
+	} {
+		name := fmt.Sprintf("%T", test.copula)
+		const n = 3000
+		dim := test.copula.Dim()
+		x := mat.NewDense(n, dim, nil)
+		generateSamples(x, test.copula)
+		col0 := mat.Col(nil, 0, x)
+		for j := 0; j < dim; j++ {
+			col := mat.Col(nil, j, x)
+			for _, v := range col {
+				if v < 0 || v > 1 {
+					t.Fatalf("%s: sample outside unit hypercube: %v", name, v)
+				}
+			}
+			mean, std := stat.MeanStdDev(col, nil)
+			if math.Abs(mean-0.5) > 0.03 || math.Abs(std-math.Sqrt(1.0/12)) > 0.02 {
+				t.Errorf("%s: marginal %d not uniform: mean %v, std %v", name, j, mean, std)
+			}
+			if j == 0 {
+				continue
+			}
+			tau := stat.Kendall(col0, col, nil)
+			if math.Abs(tau-test.tau) > 0.05 {
+				t.Errorf("%s: Kendall's τ mismatch for dimension %d: got %v, want %v", name, j, tau, test.tau)
+			}
+		}
+	}
+}
+
+func TestCopulaDist(t *testing.T) {
+	src := rand.NewSource(1)
+	sigma := mat.NewSymDense(2, []float64{1, 0.7, 0.7, 1})
+	gauss, ok := NewGaussianCopula(sigma, src)
+	if !ok {
+		t.Fatal("bad test")
+	}
+	marginals := []Marginal{
+		distuv.Normal{Mu: 1, Sigma: 2},
+		distuv.Normal{Mu: -1, Sigma: 0.5},
+	}
+	d := NewCopulaDist(gauss, marginals)
+
+	// A Gaussian copula with normal marginals is a multivariate normal.
+	cov := mat.NewSymDense(2, []float64{4, 0.7, 0.7, 0.25})
+	norm, ok := NewNormal([]float64{1, -1}, cov, nil)
+	if !ok {
+		t.Fatal("bad test")
+	}
+	for _, x := range [][]float64{{0, 0}, {1, -1}, {2.5, -0.3}, {-3, -2}} {
+		got := d.LogProb(x)
+		want := norm.LogProb(x)
+		if math.Abs(got-want) > 1e-10 {
+			t.Errorf("LogProb mismatch at %v: got %v, want %v", x, got, want)
+		}
+	}
+
+	const n = 1e5
+	x := mat.NewDense(n, 2, nil)
+	generateSamples(x, d)
+	checkMean(t, 0, x, norm, 2e-2)
+	checkCov(t, 0, x, norm, 2e-2)
+}
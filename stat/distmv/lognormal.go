@@ -0,0 +1,134 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package distmv
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// LogNormal is a multivariate log-normal distribution. A random vector x is
+// log-normally distributed if log(x), taken element-wise, is distributed
+// according to a multivariate normal distribution with mean μ and covariance
+// matrix Σ. Its pdf in k dimensions is given by
+//  (2 π)^(-k/2) |Σ|^(-1/2) exp(-1/2 (log(x)-μ)'Σ^-1(log(x)-μ)) / \prod_i x_i
+// for x with all positive elements.
+//
+// For more information see https://en.wikipedia.org/wiki/Log-normal_distribution#Multivariate_log-normal
+type LogNormal struct {
+	normal Normal
+}
+
+// NewLogNormal creates a new LogNormal with the given location vector μ and
+// scale matrix Σ of the underlying normal distribution. NewLogNormal panics if
+// len(mu) == 0, or if len(mu) != sigma.N. If the scale matrix is not
+// positive-definite, the returned boolean is false.
+func NewLogNormal(mu []float64, sigma mat.Symmetric, src rand.Source) (*LogNormal, bool) {
+	n, ok := NewNormal(mu, sigma, src)
+	if !ok {
+		return nil, false
+	}
+	return &LogNormal{normal: *n}, true
+}
+
+// CovarianceMatrix stores the covariance matrix of the distribution in dst.
+// Upon return, the value at element {i, j} of the covariance matrix is equal
+// to the covariance of the i^th and j^th variables.
+//  covariance(i, j) = E[(x_i - E[x_i])(x_j - E[x_j])]
+// If the dst matrix is empty it will be resized to the correct dimensions,
+// otherwise dst must match the dimension of the receiver or CovarianceMatrix
+// will panic.
+func (l *LogNormal) CovarianceMatrix(dst *mat.SymDense) {
+	dim := l.normal.dim
+	if dst.IsEmpty() {
+		*dst = *(dst.GrowSym(dim).(*mat.SymDense))
+	} else if dst.Symmetric() != dim {
+		panic("lognormal: input matrix size mismatch")
+	}
+	mu := l.normal.mu
+	sigma := &l.normal.sigma
+	for i := 0; i < dim; i++ {
+		for j := i; j < dim; j++ {
+			s := sigma.At(i, j)
+			v := math.Exp(mu[i]+mu[j]+0.5*(sigma.At(i, i)+sigma.At(j, j))) * math.Expm1(s)
+			dst.SetSym(i, j, v)
+		}
+	}
+}
+
+// Dim returns the dimension of the distribution.
+func (l *LogNormal) Dim() int {
+	return l.normal.dim
+}
+
+// LogProb computes the log of the pdf of the point x. LogProb returns -∞
+// if any element of x is not positive.
+func (l *LogNormal) LogProb(x []float64) float64 {
+	dim := l.normal.dim
+	if len(x) != dim {
+		panic(badSizeMismatch)
+	}
+	y := make([]float64, dim)
+	var logJac float64
+	for i, v := range x {
+		if v <= 0 {
+			return math.Inf(-1)
+		}
+		y[i] = math.Log(v)
+		logJac += y[i]
+	}
+	return normalLogProb(y, l.normal.mu, &l.normal.chol, l.normal.logSqrtDet) - logJac
+}
+
+// Mean returns the mean of the probability distribution at x. If the
+// input argument is nil, a new slice will be allocated, otherwise the result
+// will be put in-place into the receiver.
+func (l *LogNormal) Mean(x []float64) []float64 {
+	x = reuseAs(x, l.normal.dim)
+	for i, mu := range l.normal.mu {
+		x[i] = math.Exp(mu + 0.5*l.normal.sigma.At(i, i))
+	}
+	return x
+}
+
+// Normal returns the normal distribution of log(x) underlying the receiver.
+// The input src is passed to the call to NewNormalChol.
+func (l *LogNormal) Normal(src rand.Source) *Normal {
+	n := NewNormalChol(l.normal.mu, &l.normal.chol, src)
+	n.sigma = *mat.NewSymDense(n.dim, nil)
+	n.sigma.CopySym(&l.normal.sigma)
+	return n
+}
+
+// Prob computes the value of the probability density function at x.
+func (l *LogNormal) Prob(x []float64) float64 {
+	return math.Exp(l.LogProb(x))
+}
+
+// Quantile returns the multi-dimensional inverse cumulative distribution function.
+// If x is nil, a new slice will be allocated and returned. If x is non-nil,
+// len(x) must equal len(p) and the quantile will be stored in-place into x.
+// All of the values of p must be between 0 and 1, inclusive, or Quantile will panic.
+func (l *LogNormal) Quantile(x, p []float64) []float64 {
+	x = l.normal.Quantile(x, p)
+	for i, v := range x {
+		x[i] = math.Exp(v)
+	}
+	return x
+}
+
+// Rand generates a random number according to the distributon.
+// If the input slice is nil, new memory is allocated, otherwise the result is stored
+// in place.
+func (l *LogNormal) Rand(x []float64) []float64 {
+	x = l.normal.Rand(x)
+	for i, v := range x {
+		x[i] = math.Exp(v)
+	}
+	return x
+}
@@ -0,0 +1,63 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package distmv
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestLogNormal(t *testing.T) {
+	for cas, test := range []struct {
+		mu    []float64
+		sigma *mat.SymDense
+	}{
+		{
+			mu:    []float64{0, 0.5},
+			sigma: mat.NewSymDense(2, []float64{0.2, 0.05, 0.05, 0.1}),
+		},
+		{
+			mu:    []float64{-0.2, 0.1, 0.3},
+			sigma: mat.NewSymDense(3, []float64{0.1, 0.02, -0.01, 0.02, 0.2, 0.03, -0.01, 0.03, 0.15}),
+		},
+	} {
+		src := rand.New(rand.NewSource(1))
+		l, ok := NewLogNormal(test.mu, test.sigma, src)
+		if !ok {
+			t.Fatalf("Bad test, covariance matrix not positive definite")
+		}
+		n, _ := NewNormal(test.mu, test.sigma, nil)
+
+		// Check the density against the change of variables from the normal.
+		x := make([]float64, len(test.mu))
+		for i := range x {
+			x[i] = float64(i+1) * 0.7
+		}
+		y := make([]float64, len(x))
+		want := 0.0
+		for i, v := range x {
+			y[i] = math.Log(v)
+			want -= y[i]
+		}
+		want += n.LogProb(y)
+		if got := l.LogProb(x); math.Abs(got-want) > 1e-14 {
+			t.Errorf("LogProb mismatch. Case %v. Got %v, want %v", cas, got, want)
+		}
+		x[0] = -1
+		if got := l.LogProb(x); !math.IsInf(got, -1) {
+			t.Errorf("Expected -Inf LogProb for negative input. Case %v. Got %v", cas, got)
+		}
+
+		const size = 1e6
+		samples := mat.NewDense(size, len(test.mu), nil)
+		generateSamples(samples, l)
+		checkMean(t, cas, samples, l, 1e-2)
+		checkCov(t, cas, samples, l, 1e-2)
+	}
+}
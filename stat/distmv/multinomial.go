@@ -0,0 +1,169 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package distmv
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// Multinomial implements the multinomial distribution, a discrete probability
+// distribution over the number of occurrences of each of k outcomes in n
+// independent trials. The probability of the counts x is
+//  n! / (x_1! ... x_k!) p_1^x_1 ... p_k^x_k
+// where the x_i are non-negative integers summing to n.
+//
+// For more information see https://en.wikipedia.org/wiki/Multinomial_distribution
+type Multinomial struct {
+	n   float64
+	p   []float64
+	dim int
+	src rand.Source
+}
+
+// NewMultinomial creates a new multinomial distribution with n trials and
+// the given outcome probabilities. The probabilities are normalized to sum
+// to one. NewMultinomial panics if len(p) == 0, if any element of p is
+// negative, if p sums to zero or if n is negative or not an integer.
+func NewMultinomial(n float64, p []float64, src rand.Source) *Multinomial {
+	dim := len(p)
+	if dim == 0 {
+		panic(badZeroDimension)
+	}
+	if n < 0 || n != math.Floor(n) {
+		panic("multinomial: n must be a non-negative integer")
+	}
+	var sum float64
+	for _, v := range p {
+		if v < 0 {
+			panic("multinomial: negative probability")
+		}
+		sum += v
+	}
+	if sum == 0 {
+		panic("multinomial: probabilities sum to zero")
+	}
+	m := &Multinomial{
+		n:   n,
+		p:   make([]float64, dim),
+		dim: dim,
+		src: src,
+	}
+	copy(m.p, p)
+	floats.Scale(1/sum, m.p)
+	return m
+}
+
+// CovarianceMatrix calculates the covariance matrix of the distribution,
+// storing the result in dst. Upon return, the value at element {i, j} of the
+// covariance matrix is equal to the covariance of the i^th and j^th variables.
+//  covariance(i, j) = E[(x_i - E[x_i])(x_j - E[x_j])]
+// If the dst matrix is empty it will be resized to the correct dimensions,
+// otherwise dst must match the dimension of the receiver or CovarianceMatrix
+// will panic.
+func (m *Multinomial) CovarianceMatrix(dst *mat.SymDense) {
+	if dst.IsEmpty() {
+		*dst = *(dst.GrowSym(m.dim).(*mat.SymDense))
+	} else if dst.Symmetric() != m.dim {
+		panic("multinomial: input matrix size mismatch")
+	}
+	for i := 0; i < m.dim; i++ {
+		pi := m.p[i]
+		dst.SetSym(i, i, m.n*pi*(1-pi))
+		for j := i + 1; j < m.dim; j++ {
+			dst.SetSym(i, j, -m.n*pi*m.p[j])
+		}
+	}
+}
+
+// Dim returns the dimension of the distribution.
+func (m *Multinomial) Dim() int {
+	return m.dim
+}
+
+// LogProb computes the log of the probability mass function at the counts x.
+// LogProb returns -∞ if any element of x is negative or not an integer, or if
+// the elements of x do not sum to the number of trials.
+func (m *Multinomial) LogProb(x []float64) float64 {
+	if len(x) != m.dim {
+		panic(badSizeMismatch)
+	}
+	var sum float64
+	for _, v := range x {
+		if v < 0 || v != math.Floor(v) {
+			return math.Inf(-1)
+		}
+		sum += v
+	}
+	if sum != m.n {
+		return math.Inf(-1)
+	}
+	lp, _ := math.Lgamma(m.n + 1)
+	for i, v := range x {
+		if v == 0 {
+			continue
+		}
+		if m.p[i] == 0 {
+			return math.Inf(-1)
+		}
+		lg, _ := math.Lgamma(v + 1)
+		lp += v*math.Log(m.p[i]) - lg
+	}
+	return lp
+}
+
+// Mean returns the mean of the probability distribution at x. If the
+// input argument is nil, a new slice will be allocated, otherwise the result
+// will be put in-place into the receiver.
+func (m *Multinomial) Mean(x []float64) []float64 {
+	x = reuseAs(x, m.dim)
+	copy(x, m.p)
+	floats.Scale(m.n, x)
+	return x
+}
+
+// N returns the number of trials of the distribution.
+func (m *Multinomial) N() float64 {
+	return m.n
+}
+
+// Prob computes the value of the probability mass function at x.
+func (m *Multinomial) Prob(x []float64) float64 {
+	return math.Exp(m.LogProb(x))
+}
+
+// Rand generates a random number according to the distributon.
+// If the input slice is nil, new memory is allocated, otherwise the result is stored
+// in place.
+func (m *Multinomial) Rand(x []float64) []float64 {
+	x = reuseAs(x, m.dim)
+	// Generate the counts as a sequence of conditional binomial draws.
+	remaining := m.n
+	rest := 1.0
+	for i, p := range m.p {
+		if remaining == 0 || i == m.dim-1 {
+			x[i] = remaining
+			remaining = 0
+			continue
+		}
+		q := p / rest
+		switch {
+		case q <= 0:
+			x[i] = 0
+		case q >= 1:
+			x[i] = remaining
+		default:
+			x[i] = distuv.Binomial{N: remaining, P: q, Src: m.src}.Rand()
+		}
+		remaining -= x[i]
+		rest -= p
+	}
+	return x
+}
@@ -0,0 +1,59 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package distmv
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestMultinomial(t *testing.T) {
+	for cas, test := range []struct {
+		n    float64
+		p    []float64
+		x    []float64
+		prob float64
+	}{
+		{n: 5, p: []float64{0.2, 0.3, 0.5}, x: []float64{1, 2, 2}, prob: 0.135},
+		{n: 5, p: []float64{2, 3, 5}, x: []float64{1, 2, 2}, prob: 0.135},
+		{n: 3, p: []float64{0.5, 0.5}, x: []float64{3, 0}, prob: 0.125},
+		{n: 3, p: []float64{0.5, 0.5}, x: []float64{2, 0}, prob: 0},
+		{n: 3, p: []float64{0.5, 0.5}, x: []float64{2.5, 0.5}, prob: 0},
+		{n: 2, p: []float64{1, 0}, x: []float64{1, 1}, prob: 0},
+	} {
+		m := NewMultinomial(test.n, test.p, nil)
+		p := m.Prob(test.x)
+		if math.Abs(p-test.prob) > 1e-14 {
+			t.Errorf("Probability mismatch. Case %v. Got %v, want %v", cas, p, test.prob)
+		}
+	}
+
+	rnd := rand.New(rand.NewSource(1))
+	for cas, test := range []struct {
+		n float64
+		p []float64
+	}{
+		{n: 10, p: []float64{0.2, 0.3, 0.5}},
+		{n: 100, p: []float64{0.1, 0, 0.6, 0.3}},
+		{n: 1, p: []float64{0.5, 0.5}},
+	} {
+		const n = 1e5
+		m := NewMultinomial(test.n, test.p, rnd)
+		x := mat.NewDense(n, m.Dim(), nil)
+		generateSamples(x, m)
+		for i := 0; i < n; i++ {
+			if s := floats.Sum(x.RawRowView(i)); s != test.n {
+				t.Fatalf("Sample sum mismatch. Case %v. Got %v, want %v", cas, s, test.n)
+			}
+		}
+		checkMean(t, cas, x, m, 5e-2)
+		checkCov(t, cas, x, m, 1e-1)
+	}
+}
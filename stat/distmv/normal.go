@@ -177,6 +177,38 @@ func (n *Normal) Entropy() float64 {
 	return float64(n.dim)/2*(1+logTwoPi) + n.logSqrtDet
 }
 
+// Fit sets the mean and covariance matrix of the distribution from the data
+// samples held in the rows of x with relative weights. If weights is nil, then
+// all the weights are 1. If weights is not nil, then len(weights) must equal
+// the number of rows of x. Fit panics if the number of columns of x is not
+// equal to the dimension of the receiver.
+//
+// If the estimated covariance matrix is not positive definite, the receiver is
+// not modified and Fit returns false.
+func (n *Normal) Fit(x mat.Matrix, weights []float64) (ok bool) {
+	r, c := x.Dims()
+	if c != n.dim {
+		panic(badSizeMismatch)
+	}
+	if weights != nil && len(weights) != r {
+		panic("normal: weights length mismatch")
+	}
+	var sigma mat.SymDense
+	stat.CovarianceMatrix(&sigma, x, weights)
+	var chol mat.Cholesky
+	if !chol.Factorize(&sigma) {
+		return false
+	}
+	col := make([]float64, r)
+	for j := range n.mu {
+		n.mu[j] = stat.Mean(mat.Col(col, j, x), weights)
+	}
+	n.sigma = sigma
+	n.chol = chol
+	n.logSqrtDet = 0.5 * chol.LogDet()
+	return true
+}
+
 // LogProb computes the log of the pdf of the point x.
 func (n *Normal) LogProb(x []float64) float64 {
 	dim := n.dim
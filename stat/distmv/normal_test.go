@@ -572,3 +572,76 @@ func TestNormalScoreInput(t *testing.T) {
 		}
 	}
 }
+
+func TestNormalFit(t *testing.T) {
+	src := rand.New(rand.NewSource(1))
+	for cas, test := range []struct {
+		mu    []float64
+		sigma *mat.SymDense
+	}{
+		{
+			mu:    []float64{2, 3},
+			sigma: mat.NewSymDense(2, []float64{1, 0.5, 0.5, 2}),
+		},
+		{
+			mu:    []float64{-1, 0, 4},
+			sigma: mat.NewSymDense(3, []float64{2, 0.3, -0.4, 0.3, 1, 0.2, -0.4, 0.2, 3}),
+		},
+	} {
+		n, ok := NewNormal(test.mu, test.sigma, src)
+		if !ok {
+			t.Fatal("bad test")
+		}
+		const nSamples = 1e5
+		dim := len(test.mu)
+		x := mat.NewDense(nSamples, dim, nil)
+		generateSamples(x, n)
+
+		fit, _ := NewNormal(make([]float64, dim), eye(dim), nil)
+		if !fit.Fit(x, nil) {
+			t.Fatalf("Case %d: unexpected fit failure", cas)
+		}
+		checkMean(t, cas, x, fit, 1e-12)
+		checkCov(t, cas, x, fit, 1e-12)
+		if !floats.EqualApprox(fit.Mean(nil), test.mu, 2e-2) {
+			t.Errorf("Case %d: mean mismatch: got %v, want %v", cas, fit.Mean(nil), test.mu)
+		}
+
+		// Check that weights are equivalent to repeated samples.
+		weights := make([]float64, nSamples/2)
+		half := x.Slice(0, nSamples/2, 0, dim)
+		rep := mat.NewDense(nSamples/2+10, dim, nil)
+		rep.Slice(0, nSamples/2, 0, dim).(*mat.Dense).Copy(half)
+		for i := range weights {
+			weights[i] = 1
+		}
+		for i := 0; i < 10; i++ {
+			weights[i] = 2
+			rep.SetRow(nSamples/2+i, x.RawRowView(i))
+		}
+		fitW, _ := NewNormal(make([]float64, dim), eye(dim), nil)
+		fitW.Fit(half, weights)
+		fitR, _ := NewNormal(make([]float64, dim), eye(dim), nil)
+		fitR.Fit(rep, nil)
+		if !floats.EqualApprox(fitW.Mean(nil), fitR.Mean(nil), 1e-12) {
+			t.Errorf("Case %d: weighted mean mismatch", cas)
+		}
+
+		// A degenerate sample must not modify the receiver.
+		before := fit.Mean(nil)
+		if fit.Fit(mat.NewDense(3, dim, nil), nil) {
+			t.Errorf("Case %d: expected fit failure for degenerate samples", cas)
+		}
+		if !floats.Equal(before, fit.Mean(nil)) {
+			t.Errorf("Case %d: receiver modified by failed fit", cas)
+		}
+	}
+}
+
+func eye(n int) *mat.SymDense {
+	m := mat.NewSymDense(n, nil)
+	for i := 0; i < n; i++ {
+		m.SetSym(i, i, 1)
+	}
+	return m
+}
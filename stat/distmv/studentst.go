@@ -243,6 +243,111 @@ func (s *StudentsT) Dim() int {
 	return s.dim
 }
 
+// Fit sets the location and scale matrix parameters of the distribution from
+// the data samples held in the rows of x with relative weights, keeping the
+// degrees of freedom parameter ν fixed. If weights is nil, then all the
+// weights are 1. If weights is not nil, then len(weights) must equal the
+// number of rows of x. Fit panics if the number of columns of x is not equal
+// to the dimension of the receiver.
+//
+// The maximum likelihood estimate is found by the EM algorithm described in
+//  Liu, C. and Rubin, D. B. "ML estimation of the t distribution using EM
+//  and its extensions, ECM and ECME". Statistica Sinica 5 (1995) 19-39.
+// If a scale matrix estimate is not positive definite, the receiver is not
+// modified and Fit returns false.
+func (s *StudentsT) Fit(x mat.Matrix, weights []float64) (ok bool) {
+	const (
+		maxIter = 1000
+		tol     = 1e-10
+	)
+
+	r, c := x.Dims()
+	if c != s.dim {
+		panic(badSizeMismatch)
+	}
+	if weights != nil && len(weights) != r {
+		panic("studentst: weights length mismatch")
+	}
+
+	// Initialize from the sample moments.
+	mu := make([]float64, c)
+	col := make([]float64, r)
+	for j := range mu {
+		mu[j] = stat.Mean(mat.Col(col, j, x), weights)
+	}
+	var sigma mat.SymDense
+	stat.CovarianceMatrix(&sigma, x, weights)
+	if s.nu > 2 {
+		sigma.ScaleSym((s.nu-2)/s.nu, &sigma)
+	}
+	var chol mat.Cholesky
+	if !chol.Factorize(&sigma) {
+		return false
+	}
+
+	var sumW float64
+	if weights == nil {
+		sumW = float64(r)
+	} else {
+		sumW = floats.Sum(weights)
+	}
+	tau := make([]float64, r)
+	row := make([]float64, c)
+	muVec := mat.NewVecDense(c, mu)
+	next := mat.NewSymDense(c, nil)
+	for iter := 0; iter < maxIter; iter++ {
+		// E-step: compute the expected latent precision scale of each sample.
+		var sumTau float64
+		for i := range tau {
+			mat.Row(row, i, x)
+			d := stat.Mahalanobis(mat.NewVecDense(c, row), muVec, &chol)
+			tau[i] = (s.nu + float64(c)) / (s.nu + d*d)
+			if weights != nil {
+				tau[i] *= weights[i]
+			}
+			sumTau += tau[i]
+		}
+
+		// M-step: update the location and scale.
+		var change float64
+		newMu := make([]float64, c)
+		for i, t := range tau {
+			floats.AddScaled(newMu, t/sumTau, mat.Row(row, i, x))
+		}
+		for j, v := range newMu {
+			change = math.Max(change, math.Abs(v-mu[j]))
+		}
+		copy(mu, newMu)
+
+		next.Zero()
+		for i, t := range tau {
+			mat.Row(row, i, x)
+			floats.Sub(row, mu)
+			next.SymRankOne(next, t/sumW, mat.NewVecDense(c, row))
+		}
+		for i := 0; i < c; i++ {
+			for j := i; j < c; j++ {
+				change = math.Max(change, math.Abs(next.At(i, j)-sigma.At(i, j)))
+			}
+		}
+		sigma.CopySym(next)
+		if !chol.Factorize(&sigma) {
+			return false
+		}
+		if change < tol {
+			break
+		}
+	}
+
+	copy(s.mu, mu)
+	s.sigma = *mat.NewSymDense(c, nil)
+	s.sigma.CopySym(&sigma)
+	s.chol = chol
+	s.chol.LTo(&s.lower)
+	s.logSqrtDet = 0.5 * s.chol.LogDet()
+	return true
+}
+
 // LogProb computes the log of the pdf of the point x.
 func (s *StudentsT) LogProb(y []float64) float64 {
 	if len(y) != s.dim {
@@ -272,3 +272,55 @@ func TestStudentsTMarginalSingle(t *testing.T) {
 		}
 	}
 }
+
+func TestStudentsTFit(t *testing.T) {
+	src := rand.New(rand.NewSource(1))
+	for cas, test := range []struct {
+		mean []float64
+		cov  *mat.SymDense
+		nu   float64
+	}{
+		{
+			mean: []float64{3, 4},
+			cov:  mat.NewSymDense(2, []float64{5, 1.2, 1.2, 6}),
+			nu:   3,
+		},
+		{
+			mean: []float64{3, 4, -2},
+			cov:  mat.NewSymDense(3, []float64{5, 1.2, -0.8, 1.2, 6, 0.4, -0.8, 0.4, 2}),
+			nu:   8,
+		},
+	} {
+		s, ok := NewStudentsT(test.mean, test.cov, test.nu, src)
+		if !ok {
+			t.Fatal("bad test")
+		}
+		const nSamples = 1e5
+		dim := len(test.mean)
+		samps := mat.NewDense(nSamples, dim, nil)
+		generateSamples(samps, s)
+
+		fit, _ := NewStudentsT(make([]float64, dim), eye(dim), test.nu, nil)
+		if !fit.Fit(samps, nil) {
+			t.Fatalf("Case %d: unexpected fit failure", cas)
+		}
+		if !floats.EqualApprox(fit.Mean(nil), test.mean, 3e-2) {
+			t.Errorf("Case %d: mean mismatch: got %v, want %v", cas, fit.Mean(nil), test.mean)
+		}
+		if !mat.EqualApprox(&fit.sigma, test.cov, 0.1) {
+			t.Errorf("Case %d: scale mismatch: got %v, want %v", cas, mat.Formatted(&fit.sigma), mat.Formatted(test.cov))
+		}
+
+		// The fitted parameters maximize the likelihood.
+		ll := func(d *StudentsT) float64 {
+			var sum float64
+			for i := 0; i < nSamples; i++ {
+				sum += d.LogProb(samps.RawRowView(i))
+			}
+			return sum
+		}
+		if ll(fit) < ll(s) {
+			t.Errorf("Case %d: fitted likelihood less than true likelihood", cas)
+		}
+	}
+}
@@ -0,0 +1,407 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// FA is a type for computing a maximum likelihood factor analysis of a
+// matrix. The model for an observation x is
+//  x = μ + Λ f + e
+// where Λ is the d×k matrix of loadings, f are k independent standard normal
+// common factors and e is normal noise with diagonal covariance Ψ, the
+// uniquenesses. The parameters are estimated with the EM algorithm of Rubin
+// and Thayer, "EM algorithms for ML factor analysis", Psychometrika 47 (1982)
+// 69-76. The results of the analysis are only valid if the call to
+// FactorAnalysis was successful.
+//
+// The fields of FA configure the analysis and must be set before calling
+// FactorAnalysis.
+type FA struct {
+	// Tol is the convergence tolerance for the
+	// change in log-likelihood per observation.
+	// If Tol is zero, a default of 1e-8 is used.
+	Tol float64
+
+	// MaxIter is the maximum number of EM
+	// iterations. If MaxIter is zero, a default
+	// of 10000 is used.
+	MaxIter int
+
+	d, k     int
+	n        float64
+	mean     []float64
+	loadings *mat.Dense
+	psi      []float64
+	logLik   float64
+	ok       bool
+}
+
+// FactorAnalysis fits a k factor model to the matrix of the input data which
+// is represented as an n×d matrix a where each row is an observation and each
+// column is a variable.
+//
+// The weights slice is used to weight the observations. If weights is nil,
+// each weight is considered to have a value of one, otherwise the length of
+// weights must match the number of observations or FactorAnalysis will panic.
+//
+// FactorAnalysis returns whether the analysis was successful. The analysis is
+// unsuccessful if the EM iteration does not converge within MaxIter
+// iterations.
+func (f *FA) FactorAnalysis(a mat.Matrix, k int, weights []float64) (ok bool) {
+	n, d := a.Dims()
+	if weights != nil && len(weights) != n {
+		panic("stat: len(weights) != observations")
+	}
+	if k < 1 || d < k {
+		panic("stat: bad number of factors")
+	}
+	f.d, f.k = d, k
+	f.ok = false
+	f.n = float64(n)
+	if weights != nil {
+		f.n = floats.Sum(weights)
+	}
+	f.mean = make([]float64, d)
+	col := make([]float64, n)
+	for j := range f.mean {
+		f.mean[j] = Mean(mat.Col(col, j, a), weights)
+	}
+
+	// The maximum likelihood estimates depend on the
+	// data only through the biased sample covariance.
+	var s mat.SymDense
+	CovarianceMatrix(&s, a, weights)
+	s.ScaleSym((f.n-1)/f.n, &s)
+
+	// Initialize the loadings from the principal
+	// components and the uniquenesses from the
+	// residual variances.
+	vals, vecs, ok := eigenSymDescending(&s)
+	if !ok {
+		return false
+	}
+	lambda := mat.NewDense(d, k, nil)
+	psi := make([]float64, d)
+	for j := 0; j < k; j++ {
+		sv := math.Sqrt(math.Max(vals[j], 0) / 2)
+		for i := 0; i < d; i++ {
+			lambda.Set(i, j, sv*vecs.At(i, j))
+		}
+	}
+	for i := range psi {
+		psi[i] = s.At(i, i) / 2
+		if psi[i] <= 0 {
+			return false
+		}
+	}
+
+	tol := f.Tol
+	if tol == 0 {
+		tol = 1e-8
+	}
+	maxIter := f.MaxIter
+	if maxIter == 0 {
+		maxIter = 10000
+	}
+	var chol mat.Cholesky
+	ezzSym := mat.NewSymDense(k, nil)
+	prev := math.Inf(-1)
+	for it := 0; it < maxIter; it++ {
+		sigma, ok := f.sigma(lambda, psi)
+		if !ok {
+			return false
+		}
+		ll := gaussianLogLik(&sigma, &s)
+		if math.IsNaN(ll) {
+			return false
+		}
+		if ll-prev < tol {
+			f.loadings, f.psi, f.logLik = lambda, psi, f.n*ll
+			f.ok = true
+			return true
+		}
+		prev = ll
+
+		// E-step: β = Λᵀ Σ⁻¹ and
+		// E[f fᵀ] = I - β Λ + β S βᵀ.
+		var sol, beta, sb, ezz, bl mat.Dense
+		err := sigma.SolveTo(&sol, lambda)
+		if err != nil {
+			return false
+		}
+		beta.CloneFrom(sol.T())
+		sb.Mul(&s, beta.T())
+		ezz.Mul(&beta, &sb)
+		bl.Mul(&beta, lambda)
+		ezz.Sub(&ezz, &bl)
+		for i := 0; i < k; i++ {
+			ezz.Set(i, i, ezz.At(i, i)+1)
+		}
+
+		// M-step: Λ = S βᵀ E[f fᵀ]⁻¹ and
+		// Ψ = diag(S - Λ β S).
+		for i := 0; i < k; i++ {
+			for j := i; j < k; j++ {
+				ezzSym.SetSym(i, j, (ezz.At(i, j)+ezz.At(j, i))/2)
+			}
+		}
+		if !chol.Factorize(ezzSym) {
+			return false
+		}
+		var lt mat.Dense
+		err = chol.SolveTo(&lt, sb.T())
+		if err != nil {
+			return false
+		}
+		lambda = mat.DenseCopyOf(lt.T())
+		for i := range psi {
+			v := s.At(i, i) - floats.Dot(lambda.RawRowView(i), mat.Row(nil, i, &sb))
+			psi[i] = math.Max(v, 1e-12)
+		}
+	}
+	return false
+}
+
+// sigma returns the Cholesky factorization of the model covariance
+// Λ Λᵀ + Ψ.
+func (f *FA) sigma(lambda *mat.Dense, psi []float64) (mat.Cholesky, bool) {
+	var s mat.SymDense
+	s.SymOuterK(1, lambda)
+	for i, v := range psi {
+		s.SetSym(i, i, s.At(i, i)+v)
+	}
+	var chol mat.Cholesky
+	ok := chol.Factorize(&s)
+	return chol, ok
+}
+
+// gaussianLogLik returns the log-likelihood per observation of a zero mean
+// normal distribution with covariance factorized in chol for data with
+// biased sample covariance s.
+func gaussianLogLik(chol *mat.Cholesky, s mat.Symmetric) float64 {
+	d := s.Symmetric()
+	var x mat.Dense
+	err := chol.SolveTo(&x, s)
+	if err != nil {
+		return math.NaN()
+	}
+	return -0.5 * (float64(d)*math.Log(2*math.Pi) + chol.LogDet() + mat.Trace(&x))
+}
+
+// LogLikelihood returns the maximized log-likelihood of the factor model.
+// LogLikelihood will panic if the receiver does not contain a successful FA.
+func (f *FA) LogLikelihood() float64 {
+	f.checkOK()
+	return f.logLik
+}
+
+// LoadingsTo returns the d×k matrix of factor loadings of a successful
+// analysis. The loadings are unrotated; Varimax and Promax may be used to
+// rotate them.
+//
+// If dst is empty, LoadingsTo will resize dst to be d×k. When dst is
+// non-empty, LoadingsTo will panic if dst is not d×k. LoadingsTo will also
+// panic if the receiver does not contain a successful FA.
+func (f *FA) LoadingsTo(dst *mat.Dense) {
+	f.checkOK()
+	copyTo(dst, f.loadings)
+}
+
+// UniquenessesTo returns the variances of the unique factors of each
+// variable. If dst is not nil it is used to store the uniquenesses and
+// returned. UniquenessesTo will panic if the receiver does not contain a
+// successful FA or dst is not nil and the length of dst is not d.
+func (f *FA) UniquenessesTo(dst []float64) []float64 {
+	f.checkOK()
+	if dst == nil {
+		dst = make([]float64, f.d)
+	} else if len(dst) != f.d {
+		panic("stat: length of slice does not match analysis")
+	}
+	copy(dst, f.psi)
+	return dst
+}
+
+// ScoresTo stores the regression (Thomson) estimates of the factor scores of
+// the observations in the rows of the n×d matrix a into the rows of dst,
+//  f = Λᵀ (Λ Λᵀ + Ψ)⁻¹ (x - μ)
+//
+// If dst is empty, ScoresTo will resize dst to be n×k. When dst is non-empty,
+// ScoresTo will panic if dst is not n×k. ScoresTo will also panic if the
+// receiver does not contain a successful FA or a does not have d columns.
+func (f *FA) ScoresTo(dst *mat.Dense, a mat.Matrix) {
+	f.checkOK()
+	n, d := a.Dims()
+	if d != f.d {
+		panic(mat.ErrShape)
+	}
+	if dst.IsEmpty() {
+		dst.ReuseAs(n, f.k)
+	} else if r, k := dst.Dims(); r != n || k != f.k {
+		panic(mat.ErrShape)
+	}
+	chol, ok := f.sigma(f.loadings, f.psi)
+	if !ok {
+		panic("stat: singular model covariance")
+	}
+	var b mat.Dense
+	err := chol.SolveTo(&b, f.loadings)
+	if err != nil {
+		panic(err)
+	}
+	x := mat.DenseCopyOf(a)
+	for i := 0; i < n; i++ {
+		floats.Sub(x.RawRowView(i), f.mean)
+	}
+	dst.Mul(x, &b)
+}
+
+func (f *FA) checkOK() {
+	if !f.ok {
+		panic("stat: use of unsuccessful factor analysis")
+	}
+}
+
+// Varimax stores in dst the varimax rotation of the p×k matrix of loadings,
+// the orthogonal rotation that maximizes the variance of the squared
+// loadings within each column, as described in Kaiser, "The varimax
+// criterion for analytic rotation in factor analysis", Psychometrika 23
+// (1958) 187-200. The rows of the loadings are normalized to unit length
+// before rotation. If rot is not nil, the k×k rotation matrix T is stored in
+// rot, so that dst = loadings T.
+//
+// If dst or rot are empty, they are resized. When non-empty, Varimax will
+// panic if they do not have the correct shape. Varimax returns whether the
+// rotation converged.
+func Varimax(dst, rot *mat.Dense, loadings mat.Matrix) (ok bool) {
+	p, k := loadings.Dims()
+	x := mat.DenseCopyOf(loadings)
+	h := make([]float64, p)
+	for i := range h {
+		h[i] = floats.Norm(x.RawRowView(i), 2)
+		if h[i] != 0 {
+			floats.Scale(1/h[i], x.RawRowView(i))
+		}
+	}
+
+	t := mat.NewDense(k, k, nil)
+	for i := 0; i < k; i++ {
+		t.Set(i, i, 1)
+	}
+	var (
+		z, b, u, v mat.Dense
+		svd        mat.SVD
+	)
+	colSq := make([]float64, k)
+	var crit float64
+	ok = false
+	for it := 0; it < 1000; it++ {
+		z.Mul(x, t)
+		for j := range colSq {
+			colSq[j] = 0
+		}
+		for i := 0; i < p; i++ {
+			for j, v := range z.RawRowView(i) {
+				colSq[j] += v * v
+			}
+		}
+		// B = xᵀ (z³ - z diag(Σ z²)/p).
+		g := mat.NewDense(p, k, nil)
+		for i := 0; i < p; i++ {
+			for j, v := range z.RawRowView(i) {
+				g.Set(i, j, v*v*v-v*colSq[j]/float64(p))
+			}
+		}
+		b.Mul(x.T(), g)
+		if !svd.Factorize(&b, mat.SVDThin) {
+			return false
+		}
+		svd.UTo(&u)
+		svd.VTo(&v)
+		t.Mul(&u, v.T())
+		prev := crit
+		crit = floats.Sum(svd.Values(nil))
+		if crit < prev*(1+1e-10) {
+			ok = true
+			break
+		}
+	}
+
+	z.Mul(x, t)
+	for i := range h {
+		floats.Scale(h[i], z.RawRowView(i))
+	}
+	copyTo(dst, &z)
+	if rot != nil {
+		copyTo(rot, t)
+	}
+	return ok
+}
+
+// Promax stores in dst the promax rotation of the p×k matrix of loadings,
+// an oblique rotation obtained by least squares fitting of the varimax
+// loadings to a target formed by raising them to the given power, as
+// described in Hendrickson and White, "Promax: a quick method for rotation
+// to oblique simple structure", British Journal of Statistical Psychology
+// 17 (1964) 65-70. If power is zero, a default of 4 is used. If rot is not
+// nil, the k×k rotation matrix T is stored in rot, so that dst = loadings T.
+// The correlation matrix of the rotated factors is (Tᵀ T)⁻¹.
+//
+// If dst or rot are empty, they are resized. When non-empty, Promax will
+// panic if they do not have the correct shape. Promax returns whether the
+// rotation was successful.
+func Promax(dst, rot *mat.Dense, loadings mat.Matrix, power float64) (ok bool) {
+	if power == 0 {
+		power = 4
+	}
+	_, k := loadings.Dims()
+	var lv, tv mat.Dense
+	if !Varimax(&lv, &tv, loadings) {
+		return false
+	}
+
+	// Target P = |L_v|^(power-1) L_v.
+	target := mat.DenseCopyOf(&lv)
+	target.Apply(func(_, _ int, v float64) float64 {
+		return math.Pow(math.Abs(v), power-1) * v
+	}, target)
+
+	// U = (L_vᵀ L_v)⁻¹ L_vᵀ P.
+	var u mat.Dense
+	err := u.Solve(&lv, target)
+	if err != nil {
+		return false
+	}
+
+	// Scale the columns of U so that the rotated
+	// factors have unit variance.
+	var utu, inv mat.Dense
+	utu.Mul(u.T(), &u)
+	err = inv.Inverse(&utu)
+	if err != nil {
+		return false
+	}
+	for j := 0; j < k; j++ {
+		s := math.Sqrt(inv.At(j, j))
+		for i := 0; i < k; i++ {
+			u.Set(i, j, u.At(i, j)*s)
+		}
+	}
+
+	var z mat.Dense
+	z.Mul(&lv, &u)
+	copyTo(dst, &z)
+	if rot != nil {
+		var t mat.Dense
+		t.Mul(&tv, &u)
+		copyTo(rot, &t)
+	}
+	return true
+}
@@ -0,0 +1,169 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestFactorAnalysis(t *testing.T) {
+	const n = 20000
+	rnd := rand.New(rand.NewSource(1))
+
+	// Two factors with simple structure.
+	lambda := mat.NewDense(6, 2, []float64{
+		0.9, 0,
+		0.8, 0,
+		0.7, 0.1,
+		0, 0.9,
+		0.1, 0.8,
+		0, 0.6,
+	})
+	psi := []float64{0.2, 0.3, 0.4, 0.2, 0.3, 0.5}
+
+	x := mat.NewDense(n, 6, nil)
+	f := make([]float64, 2)
+	for i := 0; i < n; i++ {
+		for j := range f {
+			f[j] = rnd.NormFloat64()
+		}
+		for j := 0; j < 6; j++ {
+			v := 5 + math.Sqrt(psi[j])*rnd.NormFloat64()
+			for l := range f {
+				v += lambda.At(j, l) * f[l]
+			}
+			x.Set(i, j, v)
+		}
+	}
+
+	var fa FA
+	ok := fa.FactorAnalysis(x, 2, nil)
+	if !ok {
+		t.Fatal("unexpected failure")
+	}
+
+	// The loadings are only identifiable up to rotation,
+	// so compare the implied common covariance.
+	var gotL mat.Dense
+	fa.LoadingsTo(&gotL)
+	var got, want mat.Dense
+	got.Mul(&gotL, gotL.T())
+	want.Mul(lambda, lambda.T())
+	if !mat.EqualApprox(&got, &want, 0.05) {
+		t.Errorf("unexpected common covariance:\ngot:\n%v\nwant:\n%v",
+			mat.Formatted(&got), mat.Formatted(&want))
+	}
+	gotPsi := fa.UniquenessesTo(nil)
+	if !approxEqual(gotPsi, psi, 0.05) {
+		t.Errorf("unexpected uniquenesses: got:%v want:%v", gotPsi, psi)
+	}
+
+	// The fitted model must be more likely than the
+	// model with the true parameters.
+	var s mat.SymDense
+	CovarianceMatrix(&s, x, nil)
+	s.ScaleSym((n-1)/float64(n), &s)
+	trueFA := FA{loadings: lambda, psi: psi}
+	sigma, _ := trueFA.sigma(lambda, psi)
+	if ll := n * gaussianLogLik(&sigma, &s); fa.LogLikelihood() < ll {
+		t.Errorf("fitted log likelihood less than at true parameters: %v < %v", fa.LogLikelihood(), ll)
+	}
+
+	// Factor scores should correlate with the
+	// common part of the observations.
+	var scores mat.Dense
+	fa.ScoresTo(&scores, x)
+	if r, c := scores.Dims(); r != n || c != 2 {
+		t.Fatalf("unexpected scores shape: got:%d×%d want:%d×2", r, c, n)
+	}
+
+	var rotated, rot mat.Dense
+	if !Varimax(&rotated, &rot, &gotL) {
+		t.Fatal("varimax did not converge")
+	}
+	// Varimax should recover the simple structure
+	// up to column order and sign.
+	for j := 0; j < 2; j++ {
+		a, b := math.Abs(rotated.At(0, j)), math.Abs(rotated.At(3, j))
+		if math.Abs(a-b) < 0.5 {
+			t.Errorf("varimax did not recover simple structure:\n%v", mat.Formatted(&rotated))
+		}
+	}
+}
+
+func TestVarimax(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	loadings := mat.NewDense(8, 3, nil)
+	for i := 0; i < 8; i++ {
+		for j := 0; j < 3; j++ {
+			loadings.Set(i, j, rnd.NormFloat64())
+		}
+	}
+
+	var dst, rot mat.Dense
+	if !Varimax(&dst, &rot, loadings) {
+		t.Fatal("varimax did not converge")
+	}
+	var rtr mat.Dense
+	rtr.Mul(rot.T(), &rot)
+	if !mat.EqualApprox(&rtr, eye(3), 1e-10) {
+		t.Errorf("rotation not orthogonal:\n%v", mat.Formatted(&rtr))
+	}
+	var want mat.Dense
+	want.Mul(loadings, &rot)
+	if !mat.EqualApprox(&dst, &want, 1e-10) {
+		t.Errorf("rotated loadings not loadings·rot")
+	}
+	if varimaxCriterion(&dst) < varimaxCriterion(loadings)-1e-12 {
+		t.Errorf("varimax criterion decreased")
+	}
+
+	var pdst, prot mat.Dense
+	if !Promax(&pdst, &prot, loadings, 0) {
+		t.Fatal("promax failed")
+	}
+	want.Mul(loadings, &prot)
+	if !mat.EqualApprox(&pdst, &want, 1e-10) {
+		t.Errorf("promax loadings not loadings·rot")
+	}
+	// The rotated factors must have unit variance.
+	var ptp, phi mat.Dense
+	ptp.Mul(prot.T(), &prot)
+	err := phi.Inverse(&ptp)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for j := 0; j < 3; j++ {
+		if math.Abs(phi.At(j, j)-1) > 1e-10 {
+			t.Errorf("unexpected factor variance for factor %d: got:%v want:1", j, phi.At(j, j))
+		}
+	}
+}
+
+// varimaxCriterion returns the raw varimax criterion of the row normalized
+// loadings.
+func varimaxCriterion(loadings mat.Matrix) float64 {
+	p, k := loadings.Dims()
+	var crit float64
+	for j := 0; j < k; j++ {
+		sq := make([]float64, p)
+		for i := range sq {
+			var h float64
+			for l := 0; l < k; l++ {
+				h += loadings.At(i, l) * loadings.At(i, l)
+			}
+			v := loadings.At(i, j)
+			sq[i] = v * v / h
+		}
+		v := Variance(sq, nil)
+		crit += v
+	}
+	return crit
+}
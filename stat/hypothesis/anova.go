@@ -0,0 +1,81 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hypothesis
+
+import (
+	"gonum.org/v1/gonum/mathext"
+	"gonum.org/v1/gonum/stat"
+)
+
+// OneWayANOVA performs the one-way analysis of variance F-test of the null
+// hypothesis that the populations from which the groups were drawn all have
+// the same mean. The test assumes that the populations are normally
+// distributed with equal variance. The returned Result holds the F statistic,
+// the between-group degrees of freedom in DoF and the within-group degrees of
+// freedom in DenomDoF.
+//
+// If weights is nil then all the weights are 1. If weights is not nil, then
+// len(weights) must equal len(groups) and each element of weights is either
+// nil or holds the weights for the corresponding group. OneWayANOVA panics if
+// there are fewer than two groups or if the total weight of all the groups is
+// not greater than the number of groups.
+func OneWayANOVA(groups, weights [][]float64) Result {
+	k := len(groups)
+	if k < 2 {
+		panic(badSamples)
+	}
+	if weights != nil && len(weights) != k {
+		panic(badLength)
+	}
+
+	var n, grand float64
+	means := make([]float64, k)
+	sizes := make([]float64, k)
+	var ssWithin float64
+	for i, g := range groups {
+		w := groupWeights(weights, i)
+		checkWeights(g, w)
+		sizes[i] = sumWeights(len(g), w)
+		if sizes[i] == 0 {
+			panic(badSamples)
+		}
+		means[i] = stat.Mean(g, w)
+		for j, v := range g {
+			d := v - means[i]
+			ssWithin += weightAt(w, j) * d * d
+		}
+		n += sizes[i]
+		grand += sizes[i] * means[i]
+	}
+	if n <= float64(k) {
+		panic(badSamples)
+	}
+	grand /= n
+
+	var ssBetween float64
+	for i, m := range means {
+		d := m - grand
+		ssBetween += sizes[i] * d * d
+	}
+	df1 := float64(k - 1)
+	df2 := n - float64(k)
+	f := (ssBetween / df1) / (ssWithin / df2)
+	return Result{
+		Statistic: f,
+		DoF:       df1,
+		DenomDoF:  df2,
+		// Compute the survival function of the F distribution directly
+		// to retain precision for small p-values.
+		PValue: mathext.RegIncBeta(df2/2, df1/2, df2/(df2+df1*f)),
+	}
+}
+
+// groupWeights returns the weights of the i^th group.
+func groupWeights(weights [][]float64, i int) []float64 {
+	if weights == nil {
+		return nil
+	}
+	return weights[i]
+}
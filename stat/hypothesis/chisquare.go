@@ -0,0 +1,87 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hypothesis
+
+import (
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// ChiSquareGoodnessOfFit performs Pearson's χ² goodness of fit test of the
+// null hypothesis that the observed frequencies obs are drawn from the
+// categorical distribution with expected frequencies exp. The sums of obs
+// and exp should be equal.
+//
+// The degrees of freedom of the reference χ² distribution are
+// len(obs)-1-ddof, where ddof is the number of parameters of the expected
+// distribution that were estimated from the data.
+//
+// ChiSquareGoodnessOfFit panics if len(obs) != len(exp) or if the degrees of
+// freedom are not positive.
+func ChiSquareGoodnessOfFit(obs, exp []float64, ddof int) Result {
+	if len(obs) != len(exp) {
+		panic(badLength)
+	}
+	df := float64(len(obs) - 1 - ddof)
+	if df <= 0 {
+		panic("hypothesis: non-positive degrees of freedom")
+	}
+	x2 := stat.ChiSquare(obs, exp)
+	return Result{
+		Statistic: x2,
+		DoF:       df,
+		PValue:    distuv.ChiSquared{K: df}.Survival(x2),
+	}
+}
+
+// ChiSquareIndependence performs Pearson's χ² test of the null hypothesis
+// that the row and column variables of the contingency table of observed
+// frequencies are independent. No continuity correction is applied.
+//
+// ChiSquareIndependence panics if the table has fewer than two rows or
+// columns, or if any row or column has a zero total.
+func ChiSquareIndependence(table mat.Matrix) Result {
+	r, c := table.Dims()
+	if r < 2 || c < 2 {
+		panic(badSamples)
+	}
+	rows := make([]float64, r)
+	cols := make([]float64, c)
+	var total float64
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			v := table.At(i, j)
+			rows[i] += v
+			cols[j] += v
+			total += v
+		}
+	}
+	for _, v := range rows {
+		if v == 0 {
+			panic("hypothesis: zero row total")
+		}
+	}
+	for _, v := range cols {
+		if v == 0 {
+			panic("hypothesis: zero column total")
+		}
+	}
+
+	var x2 float64
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			e := rows[i] * cols[j] / total
+			d := table.At(i, j) - e
+			x2 += d * d / e
+		}
+	}
+	df := float64((r - 1) * (c - 1))
+	return Result{
+		Statistic: x2,
+		DoF:       df,
+		PValue:    distuv.ChiSquared{K: df}.Survival(x2),
+	}
+}
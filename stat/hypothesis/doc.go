@@ -0,0 +1,11 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package hypothesis provides statistical hypothesis tests.
+//
+// Each test returns a Result holding the value of the test statistic and
+// the p-value of the observed data under the null hypothesis. Tests that
+// accept weights treat them as frequency weights, so a weight of 2 is
+// equivalent to the observation appearing twice in the sample.
+package hypothesis // import "gonum.org/v1/gonum/stat/hypothesis"
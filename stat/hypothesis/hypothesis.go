@@ -0,0 +1,105 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hypothesis
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+const (
+	badLength  = "hypothesis: slice length mismatch"
+	badWeights = "hypothesis: negative weight"
+	badSamples = "hypothesis: not enough samples"
+)
+
+// Alternative specifies the alternative hypothesis of a test.
+type Alternative int
+
+const (
+	// TwoSided is the alternative that the tested quantity
+	// differs from its value under the null hypothesis.
+	TwoSided Alternative = iota
+	// Less is the alternative that the tested quantity
+	// is less than its value under the null hypothesis.
+	Less
+	// Greater is the alternative that the tested quantity
+	// is greater than its value under the null hypothesis.
+	Greater
+)
+
+// Result is the result of a hypothesis test.
+type Result struct {
+	// Statistic is the value of the test statistic.
+	Statistic float64
+
+	// DoF is the degrees of freedom of the null
+	// distribution of the statistic. DoF is zero for
+	// tests without a degrees of freedom parameter.
+	DoF float64
+
+	// DenomDoF is the denominator degrees of freedom
+	// for tests with an F-distributed statistic.
+	DenomDoF float64
+
+	// PValue is the probability, under the null
+	// hypothesis, of a statistic at least as extreme
+	// as the observed statistic.
+	PValue float64
+}
+
+// Reject returns whether the null hypothesis is rejected
+// at the significance level alpha.
+func (r Result) Reject(alpha float64) bool {
+	return r.PValue < alpha
+}
+
+// pValue returns the p-value for the given alternative from the
+// lower and upper tail probabilities of the statistic.
+func pValue(lower, upper float64, alt Alternative) float64 {
+	switch alt {
+	case TwoSided:
+		return math.Min(1, 2*math.Min(lower, upper))
+	case Less:
+		return lower
+	case Greater:
+		return upper
+	default:
+		panic("hypothesis: unknown alternative")
+	}
+}
+
+// checkWeights panics if weights is not nil and does not have the same
+// length as x or has a negative element.
+func checkWeights(x, weights []float64) {
+	if weights == nil {
+		return
+	}
+	if len(x) != len(weights) {
+		panic(badLength)
+	}
+	for _, w := range weights {
+		if w < 0 {
+			panic(badWeights)
+		}
+	}
+}
+
+// sumWeights returns the sum of weights, or n if weights is nil.
+func sumWeights(n int, weights []float64) float64 {
+	if weights == nil {
+		return float64(n)
+	}
+	return floats.Sum(weights)
+}
+
+// weightAt returns weights[i], or 1 if weights is nil.
+func weightAt(weights []float64, i int) float64 {
+	if weights == nil {
+		return 1
+	}
+	return weights[i]
+}
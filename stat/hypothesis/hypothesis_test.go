@@ -0,0 +1,395 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hypothesis
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// The sleep data set from R.
+var (
+	sleep1 = []float64{0.7, -1.6, -0.2, -1.2, -0.1, 3.4, 3.7, 0.8, 0.0, 2.0}
+	sleep2 = []float64{1.9, 0.8, 1.1, 0.1, -0.1, 4.4, 5.5, 1.6, 4.6, 3.4}
+)
+
+// The InsectSprays data set from R.
+var insectSprays = [][]float64{
+	{10, 7, 20, 14, 14, 12, 10, 23, 17, 20, 14, 13},
+	{11, 17, 21, 11, 16, 14, 17, 17, 19, 21, 7, 13},
+	{0, 1, 7, 2, 3, 1, 2, 1, 3, 0, 1, 4},
+	{3, 5, 12, 6, 4, 3, 5, 5, 5, 5, 2, 4},
+	{3, 5, 3, 5, 3, 6, 1, 1, 3, 2, 6, 4},
+	{11, 9, 15, 22, 15, 16, 13, 10, 26, 26, 24, 13},
+}
+
+func TestReference(t *testing.T) {
+	// Reference values from R.
+	for _, test := range []struct {
+		name   string
+		result Result
+		stat   float64
+		dof    float64
+		p      float64
+		tol    float64
+	}{
+		{
+			name:   "Welch t-test",
+			result: WelchT(sleep1, nil, sleep2, nil, TwoSided),
+			stat:   -1.8608, dof: 17.776, p: 0.07939,
+		},
+		{
+			name:   "two-sample t-test",
+			result: TwoSampleT(sleep1, nil, sleep2, nil, TwoSided),
+			stat:   -1.8608, dof: 18, p: 0.07919,
+		},
+		{
+			name:   "paired t-test",
+			result: PairedT(sleep1, sleep2, nil, 0, TwoSided),
+			stat:   -4.0621, dof: 9, p: 0.002833,
+		},
+		{
+			name:   "Mann-Whitney U",
+			result: MannWhitneyU(sleep1, nil, sleep2, nil, TwoSided),
+			stat:   25.5, p: 0.06933,
+		},
+		{
+			name:   "Wilcoxon signed-rank",
+			result: WilcoxonSignedRank(differences(sleep1, sleep2), nil, 0, TwoSided),
+			stat:   0, p: 0.009091,
+		},
+		{
+			name:   "Kruskal-Wallis",
+			result: KruskalWallis(insectSprays, nil),
+			stat:   54.691, dof: 5, p: 1.511e-10,
+		},
+		{
+			name:   "chi-square independence",
+			result: ChiSquareIndependence(mat.NewDense(2, 3, []float64{762, 327, 468, 484, 239, 477})),
+			stat:   30.07, dof: 2, p: 2.954e-07,
+		},
+	} {
+		r := test.result
+		if !scalar.EqualWithinAbsOrRel(r.Statistic, test.stat, 1e-4, 1e-4) {
+			t.Errorf("%s: statistic mismatch: got %v, want %v", test.name, r.Statistic, test.stat)
+		}
+		if !scalar.EqualWithinAbsOrRel(r.DoF, test.dof, 1e-3, 1e-3) {
+			t.Errorf("%s: degrees of freedom mismatch: got %v, want %v", test.name, r.DoF, test.dof)
+		}
+		if !scalar.EqualWithinRel(r.PValue, test.p, 1e-3) {
+			t.Errorf("%s: p-value mismatch: got %v, want %v", test.name, r.PValue, test.p)
+		}
+	}
+}
+
+func TestOneWayANOVA(t *testing.T) {
+	// Reference values from R.
+	r := OneWayANOVA(insectSprays, nil)
+	if math.Abs(r.Statistic-34.702) > 1e-3 || r.DoF != 5 || r.DenomDoF != 66 {
+		t.Errorf("unexpected result: %+v", r)
+	}
+	if r.PValue <= 0 || r.PValue > 2e-16 {
+		t.Errorf("unexpected p-value: got %v, want < 2e-16", r.PValue)
+	}
+
+	// Check the p-value for a statistic where the F survival function
+	// retains precision.
+	groups := [][]float64{{1, 2, 3, 4}, {2, 3, 4, 5.5}, {1.5, 2, 2.5, 3}}
+	r = OneWayANOVA(groups, nil)
+	want := distuv.F{D1: r.DoF, D2: r.DenomDoF}.Survival(r.Statistic)
+	if math.Abs(r.PValue-want) > 1e-14 {
+		t.Errorf("p-value mismatch: got %v, want %v", r.PValue, want)
+	}
+}
+
+func differences(x, y []float64) []float64 {
+	d := make([]float64, len(x))
+	for i := range x {
+		d[i] = x[i] - y[i]
+	}
+	return d
+}
+
+func TestAlternatives(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		fn   func(Alternative) Result
+	}{
+		{name: "one-sample t", fn: func(alt Alternative) Result { return OneSampleT(sleep2, nil, 1.5, alt) }},
+		{name: "paired t", fn: func(alt Alternative) Result { return PairedT(sleep1, sleep2, nil, 0, alt) }},
+		{name: "two-sample t", fn: func(alt Alternative) Result { return TwoSampleT(sleep1, nil, sleep2, nil, alt) }},
+		{name: "Welch t", fn: func(alt Alternative) Result { return WelchT(sleep1, nil, sleep2, nil, alt) }},
+	} {
+		two := test.fn(TwoSided)
+		less := test.fn(Less)
+		greater := test.fn(Greater)
+		if math.Abs(less.PValue+greater.PValue-1) > 1e-12 {
+			t.Errorf("%s: one-sided p-values do not sum to 1: %v + %v", test.name, less.PValue, greater.PValue)
+		}
+		if math.Abs(two.PValue-2*math.Min(less.PValue, greater.PValue)) > 1e-12 {
+			t.Errorf("%s: two-sided p-value mismatch: got %v, one-sided %v and %v", test.name, two.PValue, less.PValue, greater.PValue)
+		}
+	}
+
+	// Check the direction of the one-sided alternatives.
+	x := make([]float64, 20)
+	y := make([]float64, 20)
+	for i := range x {
+		x[i] = float64(i + 1)
+		y[i] = float64(i + 11)
+	}
+	for _, test := range []struct {
+		name string
+		fn   func(Alternative) Result
+	}{
+		{name: "Welch t", fn: func(alt Alternative) Result { return WelchT(x, nil, y, nil, alt) }},
+		{name: "Mann-Whitney", fn: func(alt Alternative) Result { return MannWhitneyU(x, nil, y, nil, alt) }},
+		{name: "Wilcoxon", fn: func(alt Alternative) Result { return WilcoxonSignedRank(x, nil, 15, alt) }},
+		{name: "Kolmogorov-Smirnov", fn: func(alt Alternative) Result {
+			// The empirical distribution of x lies above that of y.
+			return KolmogorovSmirnovTwoSample(x, nil, y, nil, invert(alt))
+		}},
+	} {
+		if test.fn(Less).PValue > 0.05 {
+			t.Errorf("%s: failed to reject with alternative Less: p = %v", test.name, test.fn(Less).PValue)
+		}
+		if test.fn(Greater).PValue < 0.5 {
+			t.Errorf("%s: unexpected small p-value with alternative Greater: p = %v", test.name, test.fn(Greater).PValue)
+		}
+	}
+}
+
+func invert(alt Alternative) Alternative {
+	switch alt {
+	case Less:
+		return Greater
+	case Greater:
+		return Less
+	}
+	return alt
+}
+
+func TestWeights(t *testing.T) {
+	x := []float64{1.2, 3.4, 2.2, 0.5, 3.4, 2.8}
+	xw := []float64{1, 2, 3, 1, 1, 2}
+	y := []float64{2.1, 4.5, 3.3, 2.2, 5.1}
+	yw := []float64{2, 1, 1, 3, 1}
+	xr := replicate(x, xw)
+	yr := replicate(y, yw)
+	norm := distuv.Normal{Mu: 2, Sigma: 1.5}
+
+	for _, test := range []struct {
+		name               string
+		weighted, expanded Result
+	}{
+		{
+			name:     "one-sample t",
+			weighted: OneSampleT(x, xw, 2, TwoSided),
+			expanded: OneSampleT(xr, nil, 2, TwoSided),
+		},
+		{
+			name:     "two-sample t",
+			weighted: TwoSampleT(x, xw, y, yw, Less),
+			expanded: TwoSampleT(xr, nil, yr, nil, Less),
+		},
+		{
+			name:     "Welch t",
+			weighted: WelchT(x, xw, y, yw, Greater),
+			expanded: WelchT(xr, nil, yr, nil, Greater),
+		},
+		{
+			name:     "ANOVA",
+			weighted: OneWayANOVA([][]float64{x, y}, [][]float64{xw, yw}),
+			expanded: OneWayANOVA([][]float64{xr, yr}, nil),
+		},
+		{
+			name:     "Mann-Whitney",
+			weighted: MannWhitneyU(x, xw, y, yw, TwoSided),
+			expanded: MannWhitneyU(xr, nil, yr, nil, TwoSided),
+		},
+		{
+			name:     "Wilcoxon",
+			weighted: WilcoxonSignedRank(x, xw, 2.2, TwoSided),
+			expanded: WilcoxonSignedRank(xr, nil, 2.2, TwoSided),
+		},
+		{
+			name:     "Kruskal-Wallis",
+			weighted: KruskalWallis([][]float64{x, y, {1, 2}}, [][]float64{xw, yw, nil}),
+			expanded: KruskalWallis([][]float64{xr, yr, {1, 2}}, nil),
+		},
+		{
+			name:     "Shapiro-Wilk",
+			weighted: ShapiroWilk(x, xw),
+			expanded: ShapiroWilk(xr, nil),
+		},
+		{
+			name:     "Anderson-Darling",
+			weighted: AndersonDarling(x, xw),
+			expanded: AndersonDarling(xr, nil),
+		},
+		{
+			name:     "one-sample Kolmogorov-Smirnov",
+			weighted: KolmogorovSmirnov(x, xw, norm.CDF, TwoSided),
+			expanded: KolmogorovSmirnov(xr, nil, norm.CDF, TwoSided),
+		},
+		{
+			name:     "two-sample Kolmogorov-Smirnov",
+			weighted: KolmogorovSmirnovTwoSample(x, xw, y, yw, Less),
+			expanded: KolmogorovSmirnovTwoSample(xr, nil, yr, nil, Less),
+		},
+	} {
+		w, e := test.weighted, test.expanded
+		if !scalar.EqualWithinAbsOrRel(w.Statistic, e.Statistic, 1e-12, 1e-12) ||
+			!scalar.EqualWithinAbsOrRel(w.PValue, e.PValue, 1e-12, 1e-12) ||
+			!scalar.EqualWithinAbsOrRel(w.DoF, e.DoF, 1e-12, 1e-12) {
+			t.Errorf("%s: weighted result %+v does not match expanded result %+v", test.name, w, e)
+		}
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("Shapiro-Wilk: expected panic for non-integer weight")
+			}
+		}()
+		ShapiroWilk(x, []float64{1, 2, 0.5, 1, 1, 2})
+	}()
+}
+
+func replicate(x, weights []float64) []float64 {
+	var r []float64
+	for i, v := range x {
+		for j := 0; j < int(weights[i]); j++ {
+			r = append(r, v)
+		}
+	}
+	return r
+}
+
+func TestNullCalibration(t *testing.T) {
+	// Under the null hypothesis the p-values are uniformly distributed,
+	// so the null should be rejected at the 5% level for about 5% of
+	// the replicates.
+	rnd := rand.New(rand.NewSource(1))
+	norm := distuv.Normal{Mu: 1, Sigma: 2, Src: rnd}
+	sample := func(n int) []float64 {
+		x := make([]float64, n)
+		for i := range x {
+			x[i] = norm.Rand()
+		}
+		return x
+	}
+	tests := []struct {
+		name string
+		fn   func() Result
+	}{
+		{name: "one-sample t", fn: func() Result { return OneSampleT(sample(15), nil, 1, TwoSided) }},
+		{name: "two-sample t", fn: func() Result { return TwoSampleT(sample(15), nil, sample(20), nil, Less) }},
+		{name: "Welch t", fn: func() Result { return WelchT(sample(15), nil, sample(20), nil, TwoSided) }},
+		{name: "ANOVA", fn: func() Result { return OneWayANOVA([][]float64{sample(10), sample(12), sample(8)}, nil) }},
+		{name: "Mann-Whitney", fn: func() Result { return MannWhitneyU(sample(25), nil, sample(20), nil, TwoSided) }},
+		{name: "Wilcoxon", fn: func() Result { return WilcoxonSignedRank(sample(30), nil, 1, Greater) }},
+		{name: "Kruskal-Wallis", fn: func() Result { return KruskalWallis([][]float64{sample(10), sample(12), sample(8)}, nil) }},
+		{name: "Shapiro-Wilk small", fn: func() Result { return ShapiroWilk(sample(8), nil) }},
+		{name: "Shapiro-Wilk", fn: func() Result { return ShapiroWilk(sample(50), nil) }},
+		{name: "Anderson-Darling", fn: func() Result { return AndersonDarling(sample(50), nil) }},
+		{name: "Kolmogorov-Smirnov", fn: func() Result { return KolmogorovSmirnov(sample(50), nil, norm.CDF, TwoSided) }},
+		{name: "Kolmogorov-Smirnov two-sample", fn: func() Result {
+			return KolmogorovSmirnovTwoSample(sample(300), nil, sample(400), nil, TwoSided)
+		}},
+		{name: "chi-square", fn: func() Result {
+			obs := make([]float64, 4)
+			for _, v := range sample(200) {
+				obs[int(distuv.Normal{Mu: 1, Sigma: 2}.CDF(v)*4)]++
+			}
+			return ChiSquareGoodnessOfFit(obs, []float64{50, 50, 50, 50}, 0)
+		}},
+	}
+	for _, test := range tests {
+		const reps = 2000
+		var reject int
+		for i := 0; i < reps; i++ {
+			if test.fn().Reject(0.05) {
+				reject++
+			}
+		}
+		rate := float64(reject) / reps
+		if rate < 0.03 || rate > 0.07 {
+			t.Errorf("%s: rejection rate under the null %v, want 0.05", test.name, rate)
+		}
+	}
+}
+
+func TestNormality(t *testing.T) {
+	// Shapiro and Wilk's example of the weights of 11 men,
+	// W = 0.79 in the original paper.
+	weights := []float64{148, 154, 158, 160, 161, 162, 166, 170, 182, 195, 236}
+	r := ShapiroWilk(weights, nil)
+	if math.Abs(r.Statistic-0.79) > 5e-3 {
+		t.Errorf("Shapiro-Wilk statistic mismatch: got %v, want 0.79", r.Statistic)
+	}
+	if !r.Reject(0.01) {
+		t.Errorf("Shapiro-Wilk failed to reject normality: p = %v", r.PValue)
+	}
+	if r := AndersonDarling(weights, nil); !r.Reject(0.05) {
+		t.Errorf("Anderson-Darling failed to reject normality: p = %v", r.PValue)
+	}
+
+	// The statistics for a uniform sample.
+	rnd := rand.New(rand.NewSource(1))
+	x := make([]float64, 200)
+	for i := range x {
+		x[i] = rnd.Float64()
+	}
+	if r := ShapiroWilk(x, nil); !r.Reject(0.01) {
+		t.Errorf("Shapiro-Wilk failed to reject normality of uniform sample: p = %v", r.PValue)
+	}
+	if r := AndersonDarling(x, nil); !r.Reject(0.01) {
+		t.Errorf("Anderson-Darling failed to reject normality of uniform sample: p = %v", r.PValue)
+	}
+	if r := KolmogorovSmirnov(x, nil, distuv.UnitNormal.CDF, TwoSided); !r.Reject(0.01) {
+		t.Errorf("Kolmogorov-Smirnov failed to reject normality of uniform sample: p = %v", r.PValue)
+	}
+	u := distuv.Uniform{Min: 0, Max: 1}
+	if r := KolmogorovSmirnov(x, nil, u.CDF, TwoSided); r.Reject(0.05) {
+		t.Errorf("Kolmogorov-Smirnov rejected uniformity of uniform sample: p = %v", r.PValue)
+	}
+
+	// The Anderson-Darling statistic for a known sample and distribution.
+	y := []float64{0.1, 0.3, 0.35, 0.6, 0.9}
+	var want float64
+	for i, v := range y {
+		want += float64(2*i+1) * (math.Log(v) + math.Log(1-y[len(y)-1-i]))
+	}
+	want = -float64(len(y)) - want/float64(len(y))
+	got := andersonDarling(y, nil, u.CDF)
+	if math.Abs(got-want) > 1e-12 {
+		t.Errorf("Anderson-Darling statistic mismatch: got %v, want %v", got, want)
+	}
+}
+
+func TestKolmogorovSurvival(t *testing.T) {
+	// Values of the Kolmogorov distribution from tables.
+	for _, test := range []struct {
+		x, want float64
+	}{
+		{x: 0.5, want: 0.963945243664},
+		{x: 1, want: 0.269999671677},
+		{x: 1.3581, want: 0.05},
+		{x: 1.6276, want: 0.01},
+		{x: 2, want: 0.000670925255805},
+	} {
+		got := kolmogorovSurvival(test.x)
+		if !scalar.EqualWithinAbsOrRel(got, test.want, 1e-5, 1e-4) {
+			t.Errorf("Kolmogorov survival mismatch at %v: got %v, want %v", test.x, got, test.want)
+		}
+	}
+}
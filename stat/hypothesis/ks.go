@@ -0,0 +1,164 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hypothesis
+
+import (
+	"math"
+	"sort"
+)
+
+// KolmogorovSmirnov performs the one-sample Kolmogorov–Smirnov test of the
+// null hypothesis that x was drawn from the continuous distribution with the
+// cumulative distribution function cdf. The alternative hypothesis Greater
+// is that the empirical distribution function of x lies above cdf for some
+// value, and Less is that it lies below cdf for some value.
+//
+// The returned statistic is sup|F_n(x)-F(x)| for the two-sided alternative,
+// sup(F_n(x)-F(x)) for Greater and sup(F(x)-F_n(x)) for Less, where F_n is
+// the empirical distribution function. The p-value is computed from the
+// asymptotic distribution of the statistic.
+//
+// If weights is nil then all the weights are 1. If weights is not nil, then
+// len(x) must equal len(weights). KolmogorovSmirnov panics if the sample has
+// zero total weight.
+func KolmogorovSmirnov(x, weights []float64, cdf func(float64) float64, alt Alternative) Result {
+	checkWeights(x, weights)
+	n := sumWeights(len(x), weights)
+	if n == 0 {
+		panic(badSamples)
+	}
+	idx := sortedIndices(x)
+
+	var dPlus, dMinus, c float64
+	for i := 0; i < len(idx); {
+		v := x[idx[i]]
+		f := cdf(v)
+		dMinus = math.Max(dMinus, f-c)
+		for ; i < len(idx) && x[idx[i]] == v; i++ {
+			c += weightAt(weights, idx[i]) / n
+		}
+		dPlus = math.Max(dPlus, c-f)
+	}
+	return ksResult(dPlus, dMinus, n, alt)
+}
+
+// KolmogorovSmirnovTwoSample performs the two-sample Kolmogorov–Smirnov test
+// of the null hypothesis that x and y were drawn from the same continuous
+// distribution. The alternative hypothesis Greater is that the empirical
+// distribution function of x lies above that of y for some value, and Less
+// is that it lies below that of y for some value.
+//
+// The returned statistic is sup|F_x(v)-F_y(v)| for the two-sided alternative,
+// sup(F_x(v)-F_y(v)) for Greater and sup(F_y(v)-F_x(v)) for Less, where F_x
+// and F_y are the empirical distribution functions of x and y. The p-value is
+// computed from the asymptotic distribution of the statistic. The statistic
+// for the two-sided alternative is the value returned by stat.KolmogorovSmirnov.
+//
+// If xWeights or yWeights is nil then all the corresponding weights are 1.
+// Otherwise their lengths must equal the lengths of x and y respectively.
+// KolmogorovSmirnovTwoSample panics if either sample has zero total weight.
+func KolmogorovSmirnovTwoSample(x, xWeights, y, yWeights []float64, alt Alternative) Result {
+	checkWeights(x, xWeights)
+	checkWeights(y, yWeights)
+	nx := sumWeights(len(x), xWeights)
+	ny := sumWeights(len(y), yWeights)
+	if nx == 0 || ny == 0 {
+		panic(badSamples)
+	}
+	ix := sortedIndices(x)
+	iy := sortedIndices(y)
+
+	var dPlus, dMinus, cx, cy float64
+	var i, j int
+	for i < len(ix) || j < len(iy) {
+		var v float64
+		switch {
+		case i == len(ix):
+			v = y[iy[j]]
+		case j == len(iy):
+			v = x[ix[i]]
+		default:
+			v = math.Min(x[ix[i]], y[iy[j]])
+		}
+		for ; i < len(ix) && x[ix[i]] == v; i++ {
+			cx += weightAt(xWeights, ix[i]) / nx
+		}
+		for ; j < len(iy) && y[iy[j]] == v; j++ {
+			cy += weightAt(yWeights, iy[j]) / ny
+		}
+		dPlus = math.Max(dPlus, cx-cy)
+		dMinus = math.Max(dMinus, cy-cx)
+	}
+	return ksResult(dPlus, dMinus, nx*ny/(nx+ny), alt)
+}
+
+// ksResult returns the result of a Kolmogorov–Smirnov test with the given
+// one-sided statistics and effective sample size.
+func ksResult(dPlus, dMinus, n float64, alt Alternative) Result {
+	var d, p float64
+	switch alt {
+	case TwoSided:
+		d = math.Max(dPlus, dMinus)
+		// Use the correction of Stephens, M. A. "Use of the Kolmogorov-Smirnov,
+		// Cramer-Von Mises and related statistics without extensive tables".
+		// Journal of the Royal Statistical Society, Series B 32(1) (1970) 115-122.
+		sn := math.Sqrt(n)
+		p = kolmogorovSurvival((sn + 0.12 + 0.11/sn) * d)
+	case Less, Greater:
+		d = dPlus
+		if alt == Less {
+			d = dMinus
+		}
+		p = math.Exp(-2 * n * d * d)
+	default:
+		panic("hypothesis: unknown alternative")
+	}
+	return Result{Statistic: d, PValue: math.Min(1, math.Max(0, p))}
+}
+
+// kolmogorovSurvival returns the survival function of the Kolmogorov
+// distribution, P(K > x).
+func kolmogorovSurvival(x float64) float64 {
+	if x <= 0 {
+		return 1
+	}
+	const eps = 1e-16
+	if x < 1.18 {
+		// Use the theta function representation that converges quickly
+		// for small x.
+		y := math.Exp(-math.Pi * math.Pi / (8 * x * x))
+		var sum float64
+		for k := 1; ; k += 2 {
+			t := math.Pow(y, float64(k*k))
+			sum += t
+			if t < eps*sum {
+				break
+			}
+		}
+		return 1 - math.Sqrt(2*math.Pi)/x*sum
+	}
+	var sum float64
+	sign := 1.0
+	for k := 1; ; k++ {
+		fk := float64(k)
+		t := math.Exp(-2 * fk * fk * x * x)
+		sum += sign * t
+		if t < eps {
+			break
+		}
+		sign = -sign
+	}
+	return 2 * sum
+}
+
+// sortedIndices returns the indices of x in increasing order of value.
+func sortedIndices(x []float64) []int {
+	idx := make([]int, len(x))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.Slice(idx, func(i, j int) bool { return x[idx[i]] < x[idx[j]] })
+	return idx
+}
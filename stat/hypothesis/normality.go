@@ -0,0 +1,214 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hypothesis
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// ShapiroWilk performs the Shapiro–Wilk test of the null hypothesis that x
+// was drawn from a normally distributed population. The returned statistic
+// is W, and the p-value is computed by Royston's approximation.
+//
+// If weights is nil then all the weights are 1. If weights is not nil, then
+// len(x) must equal len(weights). Since W is defined in terms of the order
+// statistics of the sample, the weights must be integers, with an observation
+// of weight w appearing w times among the order statistics. ShapiroWilk panics
+// if a weight is not an integer, if the total weight of the sample is less
+// than 3 or greater than 5000, or if all the elements of x with non-zero
+// weight are equal.
+//
+// The algorithm is described in
+//  Royston, P. "Remark AS R94: A remark on algorithm AS 181: The W-test for
+//  normality". Journal of the Royal Statistical Society, Series C 44(4)
+//  (1995) 547-551.
+func ShapiroWilk(x, weights []float64) Result {
+	checkWeights(x, weights)
+	fn := sumWeights(len(x), weights)
+	if fn < 3 || fn > 5000 {
+		panic("hypothesis: Shapiro-Wilk sample size out of range")
+	}
+	n := int(fn)
+	s := make([]float64, 0, n)
+	for i, v := range x {
+		w := weightAt(weights, i)
+		if w != math.Trunc(w) {
+			panic("hypothesis: non-integer weight")
+		}
+		for j := 0; j < int(w); j++ {
+			s = append(s, v)
+		}
+	}
+	sort.Float64s(s)
+	if s[0] == s[n-1] {
+		panic("hypothesis: all samples identical")
+	}
+
+	// Compute the coefficients of the upper half of the order statistics.
+	nn2 := n / 2
+	a := make([]float64, nn2)
+	if n == 3 {
+		a[0] = math.Sqrt2 / 2
+	} else {
+		m := make([]float64, nn2)
+		var summ2 float64
+		for i := range m {
+			m[i] = distuv.UnitNormal.Quantile((float64(i+1) - 0.375) / (fn + 0.25))
+			summ2 += m[i] * m[i]
+		}
+		summ2 *= 2
+		ssumm2 := math.Sqrt(summ2)
+		rsn := 1 / math.Sqrt(fn)
+		a1 := poly([]float64{0, 0.221157, -0.147981, -2.07119, 4.434685, -2.706056}, rsn) - m[0]/ssumm2
+
+		var i1 int
+		var fac float64
+		if n > 5 {
+			i1 = 2
+			a2 := -m[1]/ssumm2 + poly([]float64{0, 0.042981, -0.293762, -1.752461, 5.682633, -3.582633}, rsn)
+			fac = math.Sqrt((summ2 - 2*m[0]*m[0] - 2*m[1]*m[1]) / (1 - 2*a1*a1 - 2*a2*a2))
+			a[1] = a2
+		} else {
+			i1 = 1
+			fac = math.Sqrt((summ2 - 2*m[0]*m[0]) / (1 - 2*a1*a1))
+		}
+		a[0] = a1
+		for i := i1; i < nn2; i++ {
+			a[i] = -m[i] / fac
+		}
+	}
+
+	var num float64
+	for i, c := range a {
+		num += c * (s[n-1-i] - s[i])
+	}
+	mean := stat.Mean(s, nil)
+	var ss float64
+	for _, v := range s {
+		ss += (v - mean) * (v - mean)
+	}
+	w := math.Min(1, num*num/ss)
+
+	// Compute the p-value.
+	if n == 3 {
+		const (
+			pi6  = 6 / math.Pi
+			stqr = math.Pi / 3
+		)
+		return Result{
+			Statistic: w,
+			PValue:    math.Max(0, pi6*(math.Asin(math.Sqrt(w))-stqr)),
+		}
+	}
+	y := math.Log1p(-w)
+	var mu, sigma float64
+	if n <= 11 {
+		gamma := poly([]float64{-2.273, 0.459}, fn)
+		if y >= gamma {
+			return Result{Statistic: w, PValue: 0}
+		}
+		y = -math.Log(gamma - y)
+		mu = poly([]float64{0.544, -0.39978, 0.025054, -6.714e-4}, fn)
+		sigma = math.Exp(poly([]float64{1.3822, -0.77857, 0.062767, -0.0020322}, fn))
+	} else {
+		xx := math.Log(fn)
+		mu = poly([]float64{-1.5861, -0.31082, -0.083751, 0.0038915}, xx)
+		sigma = math.Exp(poly([]float64{-0.4803, -0.082676, 0.0030302}, xx))
+	}
+	return Result{
+		Statistic: w,
+		PValue:    distuv.Normal{Mu: mu, Sigma: sigma}.Survival(y),
+	}
+}
+
+// poly evaluates the polynomial with coefficients c in increasing order at x.
+func poly(c []float64, x float64) float64 {
+	var v float64
+	for i := len(c) - 1; i >= 0; i-- {
+		v = v*x + c[i]
+	}
+	return v
+}
+
+// AndersonDarling performs the Anderson–Darling test of the null hypothesis
+// that x was drawn from a normally distributed population with unknown mean
+// and variance. The returned statistic is A² adjusted for the estimation of
+// the parameters, and the p-value is computed from the approximation given
+// in table 4.9 of
+//  D'Agostino, R. B. and Stephens, M. A. "Goodness-of-Fit Techniques".
+//  Marcel Dekker (1986).
+//
+// If weights is nil then all the weights are 1. If weights is not nil, then
+// len(x) must equal len(weights). AndersonDarling panics if the total weight
+// of the sample is less than 3 or if the sample has zero variance.
+func AndersonDarling(x, weights []float64) Result {
+	checkWeights(x, weights)
+	n := sumWeights(len(x), weights)
+	if n < 3 {
+		panic(badSamples)
+	}
+	mean, std := stat.MeanStdDev(x, weights)
+	if std == 0 {
+		panic("hypothesis: all samples identical")
+	}
+	a2 := andersonDarling(x, weights, distuv.Normal{Mu: mean, Sigma: std}.CDF)
+	a2 *= 1 + 0.75/n + 2.25/(n*n)
+
+	var p float64
+	switch {
+	case a2 >= 0.6:
+		p = math.Exp(1.2937 - 5.709*a2 + 0.0186*a2*a2)
+	case a2 >= 0.34:
+		p = math.Exp(0.9177 - 4.279*a2 - 1.38*a2*a2)
+	case a2 >= 0.2:
+		p = 1 - math.Exp(-8.318+42.796*a2-59.938*a2*a2)
+	default:
+		p = 1 - math.Exp(-13.436+101.14*a2-223.73*a2*a2)
+	}
+	return Result{
+		Statistic: a2,
+		PValue:    math.Min(1, math.Max(0, p)),
+	}
+}
+
+// andersonDarling returns the Anderson–Darling statistic
+//  A² = n \int (F_n(x) - F(x))² / (F(x)(1-F(x))) dF(x)
+// of the weighted sample x for the cumulative distribution function cdf.
+func andersonDarling(x, weights []float64, cdf func(float64) float64) float64 {
+	idx := sortedIndices(x)
+	n := sumWeights(len(x), weights)
+
+	// Between consecutive sample points the empirical distribution function
+	// is a constant c and the integral over u = F(x) from a to b is
+	//  (a - b) + c² log(b/a) - (1-c)² log((1-b)/(1-a)).
+	var a2, c float64
+	lo := 0.0
+	for i := 0; i <= len(idx); i++ {
+		hi := 1.0
+		if i < len(idx) {
+			hi = cdf(x[idx[i]])
+		} else {
+			c = 1
+		}
+		if hi > lo {
+			if c > 0 {
+				a2 += c * c * (math.Log(hi) - math.Log(lo))
+			}
+			if c < 1 {
+				a2 -= (1 - c) * (1 - c) * (math.Log1p(-hi) - math.Log1p(-lo))
+			}
+			a2 += lo - hi
+		}
+		if i < len(idx) {
+			c += weightAt(weights, idx[i]) / n
+			lo = hi
+		}
+	}
+	return n * a2
+}
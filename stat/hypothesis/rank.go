@@ -0,0 +1,222 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hypothesis
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// MannWhitneyU performs the Mann–Whitney U test, also known as the Wilcoxon
+// rank-sum test, of the null hypothesis that the populations from which x and
+// y were drawn have the same distribution. The alternative hypotheses Less
+// and Greater are that the population of x is stochastically less than or
+// greater than the population of y.
+//
+// The returned statistic is the U statistic of x, the number of pairs (x_i, y_j)
+// with x_i > y_j plus half the number of tied pairs. The p-value is computed
+// using the normal approximation to the distribution of U, with a correction
+// for ties and a continuity correction.
+//
+// If xWeights or yWeights is nil then all the corresponding weights are 1.
+// Otherwise their lengths must equal the lengths of x and y respectively.
+// MannWhitneyU panics if either sample has zero total weight.
+func MannWhitneyU(x, xWeights, y, yWeights []float64, alt Alternative) Result {
+	checkWeights(x, xWeights)
+	checkWeights(y, yWeights)
+	nx := sumWeights(len(x), xWeights)
+	ny := sumWeights(len(y), yWeights)
+	if nx == 0 || ny == 0 {
+		panic(badSamples)
+	}
+
+	values := make([]float64, 0, len(x)+len(y))
+	values = append(values, x...)
+	values = append(values, y...)
+	var weights []float64
+	if xWeights != nil || yWeights != nil {
+		weights = make([]float64, len(values))
+		for i := range x {
+			weights[i] = weightAt(xWeights, i)
+		}
+		for i := range y {
+			weights[len(x)+i] = weightAt(yWeights, i)
+		}
+	}
+	ranks, ties := weightedRanks(values, weights)
+
+	var rx float64
+	for i := range x {
+		rx += weightAt(weights, i) * ranks[i]
+	}
+	u := rx - nx*(nx+1)/2
+
+	n := nx + ny
+	mean := nx * ny / 2
+	sd := math.Sqrt(nx * ny / 12 * ((n + 1) - ties/(n*(n-1))))
+	return normalResult(u, mean, sd, alt)
+}
+
+// WilcoxonSignedRank performs the Wilcoxon signed-rank test of the null
+// hypothesis that the distribution of x-mu is symmetric about zero. For paired
+// samples x should hold the differences between the pairs. The alternative
+// hypotheses Less and Greater are that the distribution of x is shifted to the
+// left or to the right of mu.
+//
+// Observations equal to mu are discarded. The returned statistic is the sum of
+// the ranks of |x-mu| for the observations greater than mu. The p-value is
+// computed using the normal approximation to the distribution of the
+// statistic, with a correction for ties and a continuity correction.
+//
+// If weights is nil then all the weights are 1. If weights is not nil, then
+// len(x) must equal len(weights). WilcoxonSignedRank panics if no observations
+// with a positive weight differ from mu.
+func WilcoxonSignedRank(x, weights []float64, mu float64, alt Alternative) Result {
+	checkWeights(x, weights)
+	var abs, sign, w []float64
+	for i, v := range x {
+		d := v - mu
+		if d == 0 || weightAt(weights, i) == 0 {
+			continue
+		}
+		abs = append(abs, math.Abs(d))
+		sign = append(sign, d)
+		w = append(w, weightAt(weights, i))
+	}
+	if len(abs) == 0 {
+		panic(badSamples)
+	}
+	if weights == nil {
+		w = nil
+	}
+	ranks, ties := weightedRanks(abs, w)
+
+	var v float64
+	for i, r := range ranks {
+		if sign[i] > 0 {
+			v += weightAt(w, i) * r
+		}
+	}
+	n := sumWeights(len(abs), w)
+	mean := n * (n + 1) / 4
+	sd := math.Sqrt(n*(n+1)*(2*n+1)/24 - ties/48)
+	return normalResult(v, mean, sd, alt)
+}
+
+// KruskalWallis performs the Kruskal–Wallis H test of the null hypothesis that
+// the populations from which the groups were drawn have the same distribution.
+// The p-value is computed from the asymptotic χ² distribution of the H
+// statistic with a correction for ties.
+//
+// If weights is nil then all the weights are 1. If weights is not nil, then
+// len(weights) must equal len(groups) and each element of weights is either
+// nil or holds the weights for the corresponding group. KruskalWallis panics
+// if there are fewer than two groups or if any group has zero total weight.
+func KruskalWallis(groups, weights [][]float64) Result {
+	k := len(groups)
+	if k < 2 {
+		panic(badSamples)
+	}
+	if weights != nil && len(weights) != k {
+		panic(badLength)
+	}
+
+	var values, w []float64
+	for i, g := range groups {
+		gw := groupWeights(weights, i)
+		checkWeights(g, gw)
+		if sumWeights(len(g), gw) == 0 {
+			panic(badSamples)
+		}
+		values = append(values, g...)
+		for j := range g {
+			w = append(w, weightAt(gw, j))
+		}
+	}
+	if weights == nil {
+		w = nil
+	}
+	ranks, ties := weightedRanks(values, w)
+
+	n := sumWeights(len(values), w)
+	var h float64
+	var off int
+	for i, g := range groups {
+		gw := groupWeights(weights, i)
+		var sum float64
+		for j := range g {
+			sum += weightAt(gw, j) * ranks[off+j]
+		}
+		h += sum * sum / sumWeights(len(g), gw)
+		off += len(g)
+	}
+	h = 12/(n*(n+1))*h - 3*(n+1)
+	h /= 1 - ties/(n*n*n-n)
+
+	df := float64(k - 1)
+	return Result{
+		Statistic: h,
+		DoF:       df,
+		PValue:    distuv.ChiSquared{K: df}.Survival(h),
+	}
+}
+
+// weightedRanks returns the mid-ranks of the values with the given
+// frequency weights, and the tie correction term, the sum of t³-t over
+// groups of tied values with total weight t.
+func weightedRanks(values, weights []float64) (ranks []float64, ties float64) {
+	idx := make([]int, len(values))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(i, j int) bool { return values[idx[i]] < values[idx[j]] })
+
+	ranks = make([]float64, len(values))
+	var cum float64
+	for i := 0; i < len(idx); {
+		j := i
+		var t float64
+		for j < len(idx) && values[idx[j]] == values[idx[i]] {
+			t += weightAt(weights, idx[j])
+			j++
+		}
+		r := cum + (t+1)/2
+		for _, k := range idx[i:j] {
+			ranks[k] = r
+		}
+		ties += t*t*t - t
+		cum += t
+		i = j
+	}
+	return ranks, ties
+}
+
+// normalResult returns the result of a test of a statistic that is
+// approximately normally distributed with the given mean and standard
+// deviation under the null hypothesis, applying a continuity correction.
+func normalResult(stat, mean, sd float64, alt Alternative) Result {
+	d := stat - mean
+	var corr float64
+	switch alt {
+	case TwoSided:
+		switch {
+		case d > 0:
+			corr = 0.5
+		case d < 0:
+			corr = -0.5
+		}
+	case Less:
+		corr = -0.5
+	case Greater:
+		corr = 0.5
+	}
+	z := (d - corr) / sd
+	return Result{
+		Statistic: stat,
+		PValue:    pValue(distuv.UnitNormal.CDF(z), distuv.UnitNormal.Survival(z), alt),
+	}
+}
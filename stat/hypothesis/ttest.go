@@ -0,0 +1,120 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hypothesis
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// OneSampleT performs Student's one-sample t-test of the null hypothesis
+// that the mean of the population from which x was drawn is mu. The
+// alternative hypotheses Less and Greater are that the population mean is
+// less than or greater than mu.
+//
+// If weights is nil then all the weights are 1. If weights is not nil, then
+// len(x) must equal len(weights). OneSampleT panics if the total weight of
+// the sample is not greater than 1.
+func OneSampleT(x, weights []float64, mu float64, alt Alternative) Result {
+	checkWeights(x, weights)
+	n := sumWeights(len(x), weights)
+	if n <= 1 {
+		panic(badSamples)
+	}
+	mean, variance := stat.MeanVariance(x, weights)
+	t := (mean - mu) / math.Sqrt(variance/n)
+	return tResult(t, n-1, alt)
+}
+
+// PairedT performs the paired t-test of the null hypothesis that the mean
+// of the differences x[i]-y[i] of the paired observations is mu. The
+// alternative hypotheses Less and Greater are that the mean difference is
+// less than or greater than mu.
+//
+// PairedT panics if len(x) != len(y). If weights is nil then all the weights
+// are 1. If weights is not nil, then len(x) must equal len(weights).
+func PairedT(x, y, weights []float64, mu float64, alt Alternative) Result {
+	if len(x) != len(y) {
+		panic(badLength)
+	}
+	d := make([]float64, len(x))
+	for i, v := range x {
+		d[i] = v - y[i]
+	}
+	return OneSampleT(d, weights, mu, alt)
+}
+
+// TwoSampleT performs Student's two-sample t-test of the null hypothesis
+// that the populations from which x and y were drawn have equal means,
+// assuming that the populations have equal variance. The alternative
+// hypotheses Less and Greater are that the mean of the population of x is
+// less than or greater than the mean of the population of y.
+//
+// If xWeights or yWeights is nil then all the corresponding weights are 1.
+// Otherwise their lengths must equal the lengths of x and y respectively.
+// TwoSampleT panics if the total weight of both samples combined is not
+// greater than 2.
+func TwoSampleT(x, xWeights, y, yWeights []float64, alt Alternative) Result {
+	checkWeights(x, xWeights)
+	checkWeights(y, yWeights)
+	nx := sumWeights(len(x), xWeights)
+	ny := sumWeights(len(y), yWeights)
+	if nx+ny <= 2 || nx == 0 || ny == 0 {
+		panic(badSamples)
+	}
+	mx, vx := stat.MeanVariance(x, xWeights)
+	my, vy := stat.MeanVariance(y, yWeights)
+	if nx == 1 {
+		vx = 0
+	}
+	if ny == 1 {
+		vy = 0
+	}
+	df := nx + ny - 2
+	pooled := ((nx-1)*vx + (ny-1)*vy) / df
+	t := (mx - my) / math.Sqrt(pooled*(1/nx+1/ny))
+	return tResult(t, df, alt)
+}
+
+// WelchT performs Welch's unequal variances t-test of the null hypothesis
+// that the populations from which x and y were drawn have equal means.
+// Unlike TwoSampleT, WelchT does not assume that the two populations have
+// equal variance. The degrees of freedom of the reference t distribution
+// are given by the Welch–Satterthwaite equation. The alternative hypotheses
+// Less and Greater are that the mean of the population of x is less than or
+// greater than the mean of the population of y.
+//
+// If xWeights or yWeights is nil then all the corresponding weights are 1.
+// Otherwise their lengths must equal the lengths of x and y respectively.
+// WelchT panics if the total weight of either sample is not greater than 1.
+func WelchT(x, xWeights, y, yWeights []float64, alt Alternative) Result {
+	checkWeights(x, xWeights)
+	checkWeights(y, yWeights)
+	nx := sumWeights(len(x), xWeights)
+	ny := sumWeights(len(y), yWeights)
+	if nx <= 1 || ny <= 1 {
+		panic(badSamples)
+	}
+	mx, vx := stat.MeanVariance(x, xWeights)
+	my, vy := stat.MeanVariance(y, yWeights)
+	sx := vx / nx
+	sy := vy / ny
+	t := (mx - my) / math.Sqrt(sx+sy)
+	df := (sx + sy) * (sx + sy) / (sx*sx/(nx-1) + sy*sy/(ny-1))
+	return tResult(t, df, alt)
+}
+
+// tResult returns the result of a t-test with the statistic t and df
+// degrees of freedom.
+func tResult(t, df float64, alt Alternative) Result {
+	dist := distuv.StudentsT{Mu: 0, Sigma: 1, Nu: df}
+	return Result{
+		Statistic: t,
+		DoF:       df,
+		PValue:    pValue(dist.CDF(t), dist.Survival(t), alt),
+	}
+}
@@ -0,0 +1,394 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// Contrast specifies the nonlinearity used by FastICA to measure the
+// non-Gaussianity of a component.
+type Contrast int
+
+const (
+	// LogCosh is the general purpose contrast G(u) = log cosh(u).
+	LogCosh Contrast = iota
+	// Exp is the contrast G(u) = -exp(-u²/2), which is robust for
+	// highly super-Gaussian sources.
+	Exp
+	// Kurtosis is the contrast G(u) = u⁴/4.
+	Kurtosis
+)
+
+// deriv returns the first and second derivatives of the contrast at u.
+func (c Contrast) deriv(u float64) (g, dg float64) {
+	switch c {
+	case LogCosh:
+		t := math.Tanh(u)
+		return t, 1 - t*t
+	case Exp:
+		e := math.Exp(-u * u / 2)
+		return u * e, (1 - u*u) * e
+	case Kurtosis:
+		return u * u * u, 3 * u * u
+	default:
+		panic("stat: unknown contrast")
+	}
+}
+
+// ICA is a type for computing an independent components analysis of a matrix
+// using the FastICA algorithm of Hyvärinen, "Fast and robust fixed-point
+// algorithms for independent component analysis", IEEE Transactions on Neural
+// Networks 10 (1999) 626-634. The results of the analysis are only valid if
+// the call to IndependentComponents was successful.
+//
+// The fields of ICA configure the analysis and must be set before calling
+// IndependentComponents.
+type ICA struct {
+	// Contrast is the nonlinearity used to
+	// measure non-Gaussianity.
+	Contrast Contrast
+
+	// Deflation specifies that components are
+	// estimated one at a time. Otherwise all
+	// components are estimated simultaneously
+	// with symmetric decorrelation.
+	Deflation bool
+
+	// Tol is the convergence tolerance of the
+	// fixed-point iteration. If Tol is zero,
+	// a default of 1e-6 is used.
+	Tol float64
+
+	// MaxIter is the maximum number of fixed-point
+	// iterations. If MaxIter is zero, a default
+	// of 500 is used.
+	MaxIter int
+
+	// Src is the source of random numbers used to
+	// initialize the iteration. If Src is nil,
+	// the global source is used.
+	Src rand.Source
+
+	d, k  int
+	mean  []float64
+	unmix *mat.Dense
+	mix   *mat.Dense
+	ok    bool
+	iter  int
+}
+
+// IndependentComponents estimates k independent components from the matrix
+// of the input data which is represented as an n×d matrix a where each row is
+// an observation and each column is a variable. The data are centered and
+// whitened by projecting onto the k leading principal components before the
+// unmixing matrix is estimated, so k must not be greater than d.
+//
+// IndependentComponents returns whether the analysis was successful. The
+// analysis is unsuccessful if the whitening fails or the iteration does not
+// converge within MaxIter iterations.
+func (c *ICA) IndependentComponents(a mat.Matrix, k int) (ok bool) {
+	n, d := a.Dims()
+	if k < 1 || d < k {
+		panic("stat: bad number of components")
+	}
+	c.d, c.k = d, k
+	c.ok = false
+
+	// Center and whiten the data.
+	c.mean = make([]float64, d)
+	x := mat.DenseCopyOf(a)
+	col := make([]float64, n)
+	for j := 0; j < d; j++ {
+		mat.Col(col, j, x)
+		c.mean[j] = Mean(col, nil)
+		floats.AddConst(-c.mean[j], col)
+		x.SetCol(j, col)
+	}
+	var cov mat.SymDense
+	CovarianceMatrix(&cov, x, nil)
+	vals, vecs, ok := eigenSymDescending(&cov)
+	if !ok || vals[k-1] <= 0 {
+		return false
+	}
+	// whiten is k×d and dewhiten is d×k.
+	whiten := mat.NewDense(k, d, nil)
+	dewhiten := mat.NewDense(d, k, nil)
+	for i := 0; i < k; i++ {
+		s := math.Sqrt(vals[i])
+		for j := 0; j < d; j++ {
+			v := vecs.At(j, i)
+			whiten.Set(i, j, v/s)
+			dewhiten.Set(j, i, v*s)
+		}
+	}
+	var z mat.Dense
+	z.Mul(x, whiten.T())
+
+	tol := c.Tol
+	if tol == 0 {
+		tol = 1e-6
+	}
+	maxIter := c.MaxIter
+	if maxIter == 0 {
+		maxIter = 500
+	}
+	norm := rand.NormFloat64
+	if c.Src != nil {
+		norm = rand.New(c.Src).NormFloat64
+	}
+	w := mat.NewDense(k, k, nil)
+	for i := 0; i < k; i++ {
+		for j := 0; j < k; j++ {
+			w.Set(i, j, norm())
+		}
+	}
+
+	if c.Deflation {
+		ok = c.deflation(w, &z, tol, maxIter)
+	} else {
+		ok = c.symmetric(w, &z, tol, maxIter)
+	}
+	if !ok {
+		return false
+	}
+
+	c.unmix = &mat.Dense{}
+	c.unmix.Mul(w, whiten)
+	c.mix = &mat.Dense{}
+	c.mix.Mul(dewhiten, w.T())
+	c.ok = true
+	return true
+}
+
+// symmetric estimates the rows of the orthogonal unmixing matrix w of the
+// whitened data z simultaneously.
+func (c *ICA) symmetric(w, z *mat.Dense, tol float64, maxIter int) bool {
+	if !symmetricDecorrelate(w) {
+		return false
+	}
+	n, k := z.Dims()
+	var y mat.Dense
+	next := mat.NewDense(k, k, nil)
+	gy := mat.NewDense(n, k, nil)
+	meanDg := make([]float64, k)
+	for c.iter = 0; c.iter < maxIter; c.iter++ {
+		y.Mul(z, w.T())
+		for j := range meanDg {
+			meanDg[j] = 0
+		}
+		for i := 0; i < n; i++ {
+			for j := 0; j < k; j++ {
+				g, dg := c.Contrast.deriv(y.At(i, j))
+				gy.Set(i, j, g)
+				meanDg[j] += dg
+			}
+		}
+		// next = E[g(y) zᵀ] - diag(E[g'(y)]) w.
+		next.Mul(gy.T(), z)
+		next.Scale(1/float64(n), next)
+		for j := 0; j < k; j++ {
+			row := next.RawRowView(j)
+			floats.AddScaled(row, -meanDg[j]/float64(n), w.RawRowView(j))
+		}
+		if !symmetricDecorrelate(next) {
+			return false
+		}
+		// Converged when each new row is parallel
+		// to the corresponding previous row.
+		var change float64
+		for j := 0; j < k; j++ {
+			d := math.Abs(floats.Dot(next.RawRowView(j), w.RawRowView(j)))
+			change = math.Max(change, math.Abs(d-1))
+		}
+		w.Copy(next)
+		if change < tol {
+			return true
+		}
+	}
+	return false
+}
+
+// deflation estimates the rows of the orthogonal unmixing matrix w of the
+// whitened data z one at a time.
+func (c *ICA) deflation(w, z *mat.Dense, tol float64, maxIter int) bool {
+	n, k := z.Dims()
+	y := make([]float64, n)
+	next := make([]float64, k)
+	c.iter = 0
+	for p := 0; p < k; p++ {
+		wp := w.RawRowView(p)
+		project := func(v []float64) bool {
+			for q := 0; q < p; q++ {
+				wq := w.RawRowView(q)
+				floats.AddScaled(v, -floats.Dot(v, wq), wq)
+			}
+			nrm := floats.Norm(v, 2)
+			if nrm == 0 {
+				return false
+			}
+			floats.Scale(1/nrm, v)
+			return true
+		}
+		if !project(wp) {
+			return false
+		}
+		converged := false
+		for it := 0; it < maxIter; it++ {
+			c.iter++
+			mat.NewVecDense(n, y).MulVec(z, mat.NewVecDense(k, wp))
+			for j := range next {
+				next[j] = 0
+			}
+			var meanDg float64
+			for i, v := range y {
+				g, dg := c.Contrast.deriv(v)
+				floats.AddScaled(next, g, z.RawRowView(i))
+				meanDg += dg
+			}
+			floats.Scale(1/float64(n), next)
+			floats.AddScaled(next, -meanDg/float64(n), wp)
+			if !project(next) {
+				return false
+			}
+			d := math.Abs(floats.Dot(next, wp))
+			copy(wp, next)
+			if math.Abs(d-1) < tol {
+				converged = true
+				break
+			}
+		}
+		if !converged {
+			return false
+		}
+	}
+	return true
+}
+
+// symmetricDecorrelate replaces w with (w wᵀ)^(-1/2) w.
+func symmetricDecorrelate(w *mat.Dense) bool {
+	k, _ := w.Dims()
+	s := mat.NewSymDense(k, nil)
+	s.SymOuterK(1, w)
+	var ed mat.EigenSym
+	if !ed.Factorize(s, true) {
+		return false
+	}
+	vals := ed.Values(nil)
+	var vecs mat.Dense
+	ed.VectorsTo(&vecs)
+	for i, v := range vals {
+		if v <= 0 {
+			return false
+		}
+		vals[i] = 1 / math.Sqrt(v)
+	}
+	var tmp, inv mat.Dense
+	tmp.Mul(&vecs, mat.NewDiagDense(k, vals))
+	inv.Mul(&tmp, vecs.T())
+	tmp.Mul(&inv, w)
+	w.Copy(&tmp)
+	return true
+}
+
+// Iterations returns the number of fixed-point iterations performed by the
+// last call to IndependentComponents.
+func (c *ICA) Iterations() int {
+	return c.iter
+}
+
+// UnmixingTo returns the k×d unmixing matrix W of a successful analysis, such
+// that the independent components of an observation x are W (x - μ), where
+// μ is the mean of the observations.
+//
+// If dst is empty, UnmixingTo will resize dst to be k×d. When dst is
+// non-empty, UnmixingTo will panic if dst is not k×d. UnmixingTo will also
+// panic if the receiver does not contain a successful ICA.
+func (c *ICA) UnmixingTo(dst *mat.Dense) {
+	c.checkOK()
+	copyTo(dst, c.unmix)
+}
+
+// MixingTo returns the d×k mixing matrix A of a successful analysis, such
+// that an observation x is approximated by μ + A s, where s holds the
+// independent components of x.
+//
+// If dst is empty, MixingTo will resize dst to be d×k. When dst is non-empty,
+// MixingTo will panic if dst is not d×k. MixingTo will also panic if the
+// receiver does not contain a successful ICA.
+func (c *ICA) MixingTo(dst *mat.Dense) {
+	c.checkOK()
+	copyTo(dst, c.mix)
+}
+
+// SourcesTo stores the independent components of the observations in the
+// rows of the n×d matrix a into the rows of dst.
+//
+// If dst is empty, SourcesTo will resize dst to be n×k. When dst is
+// non-empty, SourcesTo will panic if dst is not n×k. SourcesTo will also
+// panic if the receiver does not contain a successful ICA or a does not
+// have d columns.
+func (c *ICA) SourcesTo(dst *mat.Dense, a mat.Matrix) {
+	c.checkOK()
+	n, d := a.Dims()
+	if d != c.d {
+		panic(mat.ErrShape)
+	}
+	x := mat.DenseCopyOf(a)
+	for i := 0; i < n; i++ {
+		floats.Sub(x.RawRowView(i), c.mean)
+	}
+	if dst.IsEmpty() {
+		dst.ReuseAs(n, c.k)
+	} else if r, k := dst.Dims(); r != n || k != c.k {
+		panic(mat.ErrShape)
+	}
+	dst.Mul(x, c.unmix.T())
+}
+
+func (c *ICA) checkOK() {
+	if !c.ok {
+		panic("stat: use of unsuccessful independent components analysis")
+	}
+}
+
+// copyTo copies src into dst, resizing dst if it is empty and panicking if it
+// is not empty and has a different shape.
+func copyTo(dst *mat.Dense, src mat.Matrix) {
+	r, c := src.Dims()
+	if dst.IsEmpty() {
+		dst.ReuseAs(r, c)
+	} else if r2, c2 := dst.Dims(); r2 != r || c2 != c {
+		panic(mat.ErrShape)
+	}
+	dst.Copy(src)
+}
+
+// eigenSymDescending returns the eigenvalues of the symmetric matrix s in
+// descending order and the corresponding eigenvectors in the columns of vecs.
+func eigenSymDescending(s mat.Symmetric) (vals []float64, vecs *mat.Dense, ok bool) {
+	var ed mat.EigenSym
+	if !ed.Factorize(s, true) {
+		return nil, nil, false
+	}
+	vals = ed.Values(nil)
+	vecs = &mat.Dense{}
+	ed.VectorsTo(vecs)
+	n := len(vals)
+	for i, j := 0, n-1; i < j; i, j = i+1, j-1 {
+		vals[i], vals[j] = vals[j], vals[i]
+		for r := 0; r < n; r++ {
+			a, b := vecs.At(r, i), vecs.At(r, j)
+			vecs.Set(r, i, b)
+			vecs.Set(r, j, a)
+		}
+	}
+	return vals, vecs, true
+}
@@ -0,0 +1,87 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestIndependentComponents(t *testing.T) {
+	const n = 2000
+	rnd := rand.New(rand.NewSource(1))
+
+	// Independent sources: uniform, Laplace and a square wave.
+	src := mat.NewDense(n, 3, nil)
+	for i := 0; i < n; i++ {
+		src.Set(i, 0, rnd.Float64()*2-1)
+		src.Set(i, 1, rnd.ExpFloat64()*math.Copysign(1, rnd.Float64()-0.5))
+		src.Set(i, 2, math.Copysign(1, math.Sin(float64(i)/7)))
+	}
+	mixing := mat.NewDense(3, 3, []float64{
+		1, 0.5, 0.3,
+		0.4, 1, -0.6,
+		-0.2, 0.8, 1,
+	})
+	var x mat.Dense
+	x.Mul(src, mixing.T())
+
+	for _, contrast := range []Contrast{LogCosh, Exp, Kurtosis} {
+		for _, deflation := range []bool{false, true} {
+			ica := ICA{Contrast: contrast, Deflation: deflation, Src: rand.NewSource(1)}
+			ok := ica.IndependentComponents(&x, 3)
+			if !ok {
+				t.Errorf("unexpected failure for contrast=%d deflation=%t", contrast, deflation)
+				continue
+			}
+
+			var w, a mat.Dense
+			ica.UnmixingTo(&w)
+			ica.MixingTo(&a)
+			var wa mat.Dense
+			wa.Mul(&w, &a)
+			if !mat.EqualApprox(&wa, eye(3), 1e-10) {
+				t.Errorf("unexpected unmixing·mixing for contrast=%d deflation=%t:\n%v",
+					contrast, deflation, mat.Formatted(&wa))
+			}
+
+			var s mat.Dense
+			ica.SourcesTo(&s, &x)
+			// Each estimated source must be almost perfectly
+			// correlated with exactly one of the true sources.
+			used := make([]bool, 3)
+			for i := 0; i < 3; i++ {
+				match := -1
+				for j := 0; j < 3; j++ {
+					r := Correlation(mat.Col(nil, i, &s), mat.Col(nil, j, src), nil)
+					if math.Abs(r) > 0.99 {
+						match = j
+					}
+				}
+				if match < 0 || used[match] {
+					t.Errorf("source %d not recovered for contrast=%d deflation=%t", i, contrast, deflation)
+					continue
+				}
+				used[match] = true
+				if v := Variance(mat.Col(nil, i, &s), nil); math.Abs(v-1) > 1e-3 {
+					t.Errorf("unexpected source variance for contrast=%d deflation=%t: got:%v want:1",
+						contrast, deflation, v)
+				}
+			}
+		}
+	}
+}
+
+func eye(n int) *mat.Dense {
+	m := mat.NewDense(n, n, nil)
+	for i := 0; i < n; i++ {
+		m.Set(i, i, 1)
+	}
+	return m
+}
@@ -0,0 +1,244 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kde
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+)
+
+// The bandwidth selectors below are derived for the Gaussian kernel and
+// converted to other kernels by the ratio of the asymptotically optimal
+// bandwidths of the kernels. The sample size used by the selectors is the
+// sum of the weights, so weights are treated as frequency weights.
+
+// Scott returns Scott's rule of thumb bandwidth for the weighted observations
+// x using the kernel k,
+//  h = (4/3)^(1/5) σ n^(-1/5)
+// for the Gaussian kernel, where σ is the sample standard deviation and n is
+// the sample size. If weights is nil, all the observations have unit weight.
+func Scott(x, weights []float64, k Kernel) float64 {
+	n := sampleSize(x, weights)
+	return k.scale(1) * math.Pow(4.0/3, 0.2) * stat.StdDev(x, weights) * math.Pow(n, -0.2)
+}
+
+// Silverman returns Silverman's rule of thumb bandwidth for the weighted
+// observations x using the kernel k,
+//  h = 0.9 min(σ, IQR/1.349) n^(-1/5)
+// for the Gaussian kernel, where σ is the sample standard deviation, IQR is
+// the interquartile range and n is the sample size. If weights is nil, all
+// the observations have unit weight.
+func Silverman(x, weights []float64, k Kernel) float64 {
+	n := sampleSize(x, weights)
+	return k.scale(1) * 0.9 * spread(x, weights) * math.Pow(n, -0.2)
+}
+
+// spread returns the smaller of the sample standard deviation and the
+// normalized interquartile range of x, or the standard deviation if the
+// interquartile range is zero.
+func spread(x, weights []float64) float64 {
+	sd := stat.StdDev(x, weights)
+	xs := make([]float64, len(x))
+	copy(xs, x)
+	var ws []float64
+	if weights != nil {
+		ws = make([]float64, len(weights))
+		copy(ws, weights)
+	}
+	stat.SortWeighted(xs, ws)
+	iqr := stat.Quantile(0.75, stat.LinInterp, xs, ws) - stat.Quantile(0.25, stat.LinInterp, xs, ws)
+	if iqr <= 0 {
+		return sd
+	}
+	return math.Min(sd, iqr/1.349)
+}
+
+// PlugIn returns the two-stage direct plug-in bandwidth of Sheather and Jones
+// for the weighted observations x using the kernel k. The density functionals
+// of the asymptotically optimal bandwidth are estimated by kernel estimates
+// whose pilot bandwidths are chosen by a normal reference rule, as described
+// in Wand and Jones, Kernel Smoothing, Chapman & Hall (1995), section 3.6.
+// If weights is nil, all the observations have unit weight.
+//
+// PlugIn takes O(n²) time in the number of observations.
+func PlugIn(x, weights []float64, k Kernel) float64 {
+	n := sampleSize(x, weights)
+	s := spread(x, weights)
+
+	// Normal reference estimate of ψ_8.
+	psi8 := 105 / (32 * math.Sqrt(math.Pi) * math.Pow(s, 9))
+
+	// Estimate ψ_6 and ψ_4 with the pilot bandwidths
+	// that are optimal for estimating them.
+	g1 := math.Pow(30/(math.Sqrt(2*math.Pi)*psi8*n), 1.0/9)
+	psi6 := psi(6, g1, x, weights)
+	g2 := math.Pow(-6/(math.Sqrt(2*math.Pi)*psi6*n), 1.0/7)
+	psi4 := psi(4, g2, x, weights)
+
+	return k.scale(1) * math.Pow(1/(2*math.Sqrt(math.Pi)*psi4*n), 0.2)
+}
+
+// psi returns the kernel estimate of the density functional ψ_r with the
+// Gaussian kernel and bandwidth g, for r equal to 4 or 6.
+func psi(r int, g float64, x, weights []float64) float64 {
+	deriv := func(u float64) float64 {
+		u2 := u * u
+		phi := math.Exp(-u2/2) / math.Sqrt(2*math.Pi)
+		if r == 4 {
+			return (u2*u2 - 6*u2 + 3) * phi
+		}
+		return (u2*u2*u2 - 15*u2*u2 + 45*u2 - 15) * phi
+	}
+	var sum, sumW float64
+	for i, xi := range x {
+		wi := weightAt(weights, i)
+		sumW += wi
+		sum += wi * wi * deriv(0)
+		for j := i + 1; j < len(x); j++ {
+			sum += 2 * wi * weightAt(weights, j) * deriv((xi-x[j])/g)
+		}
+	}
+	return sum / (sumW * sumW * math.Pow(g, float64(r+1)))
+}
+
+// CrossValidation returns the bandwidth for the weighted observations x using
+// the kernel k that minimizes the least-squares cross-validation estimate of
+// the integrated squared error of the density estimate,
+//  LSCV(h) = ∫ f_h(x)² dx - 2/W Σ_i w_i f_{h,-i}(x_i)
+// where f_{h,-i} is the estimate with observation i left out. The criterion
+// is minimized over bandwidths between 1/20 and 2 times the Scott bandwidth.
+// If weights is nil, all the observations have unit weight.
+//
+// CrossValidation takes O(n²) time in the number of observations for each
+// evaluation of the criterion.
+func CrossValidation(x, weights []float64, k Kernel) float64 {
+	h0 := Scott(x, weights, Gaussian)
+	if h0 == 0 || math.IsNaN(h0) {
+		return k.scale(1) * h0
+	}
+	lscv := func(logh float64) float64 {
+		return leastSquaresCV(math.Exp(logh), x, weights)
+	}
+
+	// Coarse search over a logarithmic grid followed
+	// by golden section search about the minimum.
+	const steps = 40
+	lo, hi := math.Log(h0/20), math.Log(2*h0)
+	step := (hi - lo) / steps
+	best, bestVal := lo, math.Inf(1)
+	for i := 0; i <= steps; i++ {
+		v := lo + float64(i)*step
+		if f := lscv(v); f < bestVal {
+			best, bestVal = v, f
+		}
+	}
+	a, b := math.Max(lo, best-step), math.Min(hi, best+step)
+	invPhi := (math.Sqrt(5) - 1) / 2
+	c, d := b-invPhi*(b-a), a+invPhi*(b-a)
+	fc, fd := lscv(c), lscv(d)
+	for b-a > 1e-6 {
+		if fc < fd {
+			b, d, fd = d, c, fc
+			c = b - invPhi*(b-a)
+			fc = lscv(c)
+		} else {
+			a, c, fc = c, d, fd
+			d = a + invPhi*(b-a)
+			fd = lscv(d)
+		}
+	}
+	return k.scale(1) * math.Exp((a+b)/2)
+}
+
+// leastSquaresCV returns the least-squares cross-validation criterion for the
+// Gaussian kernel with bandwidth h.
+func leastSquaresCV(h float64, x, weights []float64) float64 {
+	n := len(x)
+	sumW := sampleSize(x, weights)
+	// loo[i] accumulates the leave-one-out estimate at x_i.
+	loo := make([]float64, n)
+	var sq float64
+	c := 1 / math.Sqrt(2*math.Pi)
+	for i, xi := range x {
+		wi := weightAt(weights, i)
+		sq += wi * wi * c / math.Sqrt2
+		for j := i + 1; j < n; j++ {
+			wj := weightAt(weights, j)
+			z := (xi - x[j]) / h
+			sq += 2 * wi * wj * c / math.Sqrt2 * math.Exp(-z*z/4)
+			kv := c * math.Exp(-z*z/2)
+			loo[i] += wj * kv
+			loo[j] += wi * kv
+		}
+	}
+	var cv float64
+	for i, v := range loo {
+		wi := weightAt(weights, i)
+		if wi == 0 || wi == sumW {
+			continue
+		}
+		cv += wi * v / (sumW - wi)
+	}
+	return sq/(sumW*sumW*h) - 2*cv/(sumW*h)
+}
+
+// ScottMatrix stores Scott's rule of thumb bandwidth matrix for the weighted
+// observations in the rows of x using the kernel k in dst,
+//  H = n^(-2/(d+4)) Σ
+// for the Gaussian kernel, where Σ is the sample covariance matrix, d is the
+// dimension and n is the sample size. If weights is nil, all the observations
+// have unit weight. The dst matrix must either be empty or have the dimension
+// of the observations.
+func ScottMatrix(dst *mat.SymDense, x mat.Matrix, weights []float64, k Kernel) {
+	r, d := x.Dims()
+	n := float64(r)
+	if weights != nil {
+		n = floats.Sum(weights)
+	}
+	s := k.scale(d)
+	bandwidthMatrix(dst, x, weights, s*s*math.Pow(n, -2/float64(d+4)))
+}
+
+// SilvermanMatrix stores Silverman's rule of thumb bandwidth matrix for the
+// weighted observations in the rows of x using the kernel k in dst,
+//  H = (4/((d+2) n))^(2/(d+4)) Σ
+// for the Gaussian kernel, where Σ is the sample covariance matrix, d is the
+// dimension and n is the sample size. If weights is nil, all the observations
+// have unit weight. The dst matrix must either be empty or have the dimension
+// of the observations.
+func SilvermanMatrix(dst *mat.SymDense, x mat.Matrix, weights []float64, k Kernel) {
+	r, d := x.Dims()
+	n := float64(r)
+	if weights != nil {
+		n = floats.Sum(weights)
+	}
+	s := k.scale(d)
+	bandwidthMatrix(dst, x, weights, s*s*math.Pow(4/(float64(d+2)*n), 2/float64(d+4)))
+}
+
+func bandwidthMatrix(dst *mat.SymDense, x mat.Matrix, weights []float64, scale float64) {
+	stat.CovarianceMatrix(dst, x, weights)
+	dst.ScaleSym(scale, dst)
+}
+
+func sampleSize(x, weights []float64) float64 {
+	if weights == nil {
+		return float64(len(x))
+	}
+	if len(weights) != len(x) {
+		panic(badLength)
+	}
+	return floats.Sum(weights)
+}
+
+func weightAt(weights []float64, i int) float64 {
+	if weights == nil {
+		return 1
+	}
+	return weights[i]
+}
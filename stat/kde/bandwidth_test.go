@@ -0,0 +1,142 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kde
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+)
+
+func TestKernelScale(t *testing.T) {
+	t.Parallel()
+	// Wand and Jones, Kernel Smoothing, table 2.1.
+	const want = 2.2138
+	if got := Epanechnikov.scale(1); math.Abs(got-want) > 1e-4 {
+		t.Errorf("unexpected Epanechnikov bandwidth scale: got:%v want:%v", got, want)
+	}
+	for d := 1; d <= 4; d++ {
+		for _, k := range []Kernel{Gaussian, Epanechnikov} {
+			// Check the moments of the kernel by Monte Carlo.
+			rnd := rand.New(rand.NewSource(uint64(d)))
+			u := make([]float64, d)
+			var m2 float64
+			const n = 100000
+			for i := 0; i < n; i++ {
+				k.rand(u, rnd.NormFloat64, rnd.Float64)
+				m2 += u[0] * u[0]
+			}
+			m2 /= n
+			if want := k.secondMoment(d); math.Abs(m2-want) > 0.02*want {
+				t.Errorf("unexpected second moment for kernel %d in %d dimensions: got:%v want:%v", k, d, m2, want)
+			}
+		}
+	}
+}
+
+func TestBandwidth(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const n = 500
+	x := make([]float64, n)
+	for i := range x {
+		x[i] = 2 * rnd.NormFloat64()
+	}
+	sd := stat.StdDev(x, nil)
+	scott := 1.0592238410488122 * sd * math.Pow(n, -0.2)
+	if got := Scott(x, nil, Gaussian); !floats.EqualWithinAbsOrRel(got, scott, 1e-12, 1e-12) {
+		t.Errorf("unexpected Scott bandwidth: got:%v want:%v", got, scott)
+	}
+	if got := Silverman(x, nil, Gaussian); got > 0.9*sd*math.Pow(n, -0.2) {
+		t.Errorf("unexpected Silverman bandwidth: got:%v want<=%v", got, 0.9*sd*math.Pow(n, -0.2))
+	}
+
+	// For normal data all the selectors should be near
+	// the asymptotically optimal bandwidth.
+	for _, k := range []Kernel{Gaussian, Epanechnikov} {
+		want := k.scale(1) * math.Pow(4.0/3, 0.2) * 2 * math.Pow(n, -0.2)
+		for _, sel := range []struct {
+			name string
+			fn   func([]float64, []float64, Kernel) float64
+		}{
+			{name: "Scott", fn: Scott},
+			{name: "Silverman", fn: Silverman},
+			{name: "PlugIn", fn: PlugIn},
+			{name: "CrossValidation", fn: CrossValidation},
+		} {
+			got := sel.fn(x, nil, k)
+			if got < 0.6*want || 1.5*want < got {
+				t.Errorf("unexpected %s bandwidth for kernel %d: got:%v want:≈%v", sel.name, k, got, want)
+			}
+		}
+	}
+}
+
+func TestBandwidthWeights(t *testing.T) {
+	t.Parallel()
+	// Integer weights are equivalent to replicated observations.
+	rnd := rand.New(rand.NewSource(1))
+	const n = 50
+	x := make([]float64, n)
+	w := make([]float64, n)
+	var rep []float64
+	for i := range x {
+		x[i] = rnd.ExpFloat64()
+		w[i] = float64(1 + rnd.Intn(3))
+		for j := 0; j < int(w[i]); j++ {
+			rep = append(rep, x[i])
+		}
+	}
+	for _, sel := range []struct {
+		name string
+		fn   func([]float64, []float64, Kernel) float64
+	}{
+		{name: "Scott", fn: Scott},
+		{name: "PlugIn", fn: PlugIn},
+	} {
+		got := sel.fn(x, w, Gaussian)
+		want := sel.fn(rep, nil, Gaussian)
+		if !floats.EqualWithinAbsOrRel(got, want, 1e-12, 1e-12) {
+			t.Errorf("unexpected weighted %s bandwidth: got:%v want:%v", sel.name, got, want)
+		}
+	}
+}
+
+func TestBandwidthMatrix(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const (
+		n = 100
+		d = 3
+	)
+	x := mat.NewDense(n, d, nil)
+	for i := 0; i < n; i++ {
+		for j := 0; j < d; j++ {
+			x.Set(i, j, float64(j+1)*rnd.NormFloat64())
+		}
+	}
+	var cov mat.SymDense
+	stat.CovarianceMatrix(&cov, x, nil)
+	for _, test := range []struct {
+		name   string
+		fn     func(*mat.SymDense, mat.Matrix, []float64, Kernel)
+		factor float64
+	}{
+		{name: "Scott", fn: ScottMatrix, factor: math.Pow(n, -2.0/(d+4))},
+		{name: "Silverman", fn: SilvermanMatrix, factor: math.Pow(4/float64((d+2)*n), 2.0/(d+4))},
+	} {
+		var got, want mat.SymDense
+		test.fn(&got, x, nil, Gaussian)
+		want.ScaleSym(test.factor, &cov)
+		if !mat.EqualApprox(&got, &want, 1e-12) {
+			t.Errorf("unexpected %s bandwidth matrix:\ngot: %v\nwant:%v", test.name, mat.Formatted(&got), mat.Formatted(&want))
+		}
+	}
+}
@@ -0,0 +1,13 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package kde provides kernel density estimation for univariate and
+// multivariate data, and methods for selecting the kernel bandwidth.
+//
+// The Univariate estimator satisfies the distuv.LogProber and distuv.Rander
+// interfaces, and the Multivariate estimator satisfies the distmv.LogProber
+// and distmv.Rander interfaces, so estimates may be used wherever a
+// distribution is required, for example as the target or proposal of the
+// samplers in packages sampleuv and samplemv.
+package kde // import "gonum.org/v1/gonum/stat/kde"
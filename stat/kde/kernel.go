@@ -0,0 +1,166 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kde
+
+import (
+	"math"
+)
+
+// Kernel is a spherically symmetric smoothing kernel.
+type Kernel int
+
+const (
+	// Gaussian is the standard normal kernel.
+	Gaussian Kernel = iota
+	// Epanechnikov is the kernel proportional to 1-‖u‖²
+	// on the unit ball.
+	Epanechnikov
+)
+
+// gaussianCutoff is the distance in units of the bandwidth beyond which
+// the contribution of a Gaussian kernel is neglected when a finite support
+// is required.
+const gaussianCutoff = 8
+
+// radius returns the radius of the support of the kernel. Gaussian kernels
+// are truncated at gaussianCutoff.
+func (k Kernel) radius() float64 {
+	switch k {
+	case Gaussian:
+		return gaussianCutoff
+	case Epanechnikov:
+		return 1
+	default:
+		panic(badKernel)
+	}
+}
+
+// logNorm returns the logarithm of the normalization constant of the
+// d-dimensional kernel.
+func (k Kernel) logNorm(d int) float64 {
+	switch k {
+	case Gaussian:
+		return -0.5 * float64(d) * math.Log(2*math.Pi)
+	case Epanechnikov:
+		return math.Log(float64(d+2)/2) - logUnitBallVolume(d)
+	default:
+		panic(badKernel)
+	}
+}
+
+// profile returns the unnormalized value of the kernel at squared distance r2
+// from its center.
+func (k Kernel) profile(r2 float64) float64 {
+	switch k {
+	case Gaussian:
+		return math.Exp(-r2 / 2)
+	case Epanechnikov:
+		if r2 >= 1 {
+			return 0
+		}
+		return 1 - r2
+	default:
+		panic(badKernel)
+	}
+}
+
+// prob returns the value of the univariate kernel at u.
+func (k Kernel) prob(u float64) float64 {
+	return math.Exp(k.logNorm(1)) * k.profile(u*u)
+}
+
+// cdf returns the cumulative distribution function of the univariate kernel
+// at u.
+func (k Kernel) cdf(u float64) float64 {
+	switch k {
+	case Gaussian:
+		return 0.5 * math.Erfc(-u/math.Sqrt2)
+	case Epanechnikov:
+		switch {
+		case u <= -1:
+			return 0
+		case u >= 1:
+			return 1
+		}
+		return (2 + 3*u - u*u*u) / 4
+	default:
+		panic(badKernel)
+	}
+}
+
+// secondMoment returns the variance of each coordinate of the d-dimensional
+// kernel.
+func (k Kernel) secondMoment(d int) float64 {
+	switch k {
+	case Gaussian:
+		return 1
+	case Epanechnikov:
+		return 1 / float64(d+4)
+	default:
+		panic(badKernel)
+	}
+}
+
+// roughness returns the integral of the square of the d-dimensional kernel.
+func (k Kernel) roughness(d int) float64 {
+	switch k {
+	case Gaussian:
+		return math.Pow(4*math.Pi, -float64(d)/2)
+	case Epanechnikov:
+		return 2 * float64(d+2) / (float64(d+4) * math.Exp(logUnitBallVolume(d)))
+	default:
+		panic(badKernel)
+	}
+}
+
+// scale returns the ratio of the asymptotically optimal bandwidth of the
+// d-dimensional kernel to that of the Gaussian kernel.
+func (k Kernel) scale(d int) float64 {
+	if k == Gaussian {
+		return 1
+	}
+	ratio := func(k Kernel) float64 {
+		m := k.secondMoment(d)
+		return k.roughness(d) / (m * m)
+	}
+	return math.Pow(ratio(k)/ratio(Gaussian), 1/float64(d+4))
+}
+
+// rand fills u with a random draw from the d-dimensional kernel where
+// d = len(u), using the provided sources of normal and uniform variates.
+func (k Kernel) rand(u []float64, norm, f64 func() float64) {
+	switch k {
+	case Gaussian:
+		for i := range u {
+			u[i] = norm()
+		}
+	case Epanechnikov:
+		// The kernel is the marginal of the uniform distribution
+		// on the unit ball in two more dimensions.
+		d := len(u)
+		var r2 float64
+		for i := range u {
+			u[i] = norm()
+			r2 += u[i] * u[i]
+		}
+		for i := 0; i < 2; i++ {
+			v := norm()
+			r2 += v * v
+		}
+		s := math.Pow(f64(), 1/float64(d+2)) / math.Sqrt(r2)
+		for i := range u {
+			u[i] *= s
+		}
+	default:
+		panic(badKernel)
+	}
+}
+
+// logUnitBallVolume returns the logarithm of the volume of the unit ball in
+// d dimensions.
+func logUnitBallVolume(d int) float64 {
+	lg, _ := math.Lgamma(float64(d)/2 + 1)
+	return float64(d)/2*math.Log(math.Pi) - lg
+}
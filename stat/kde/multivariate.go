@@ -0,0 +1,227 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kde
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/spatial/kdtree"
+	"gonum.org/v1/gonum/stat/distmv"
+)
+
+var (
+	_ distmv.LogProber = (*Multivariate)(nil)
+	_ distmv.Rander    = (*Multivariate)(nil)
+)
+
+// Multivariate is a kernel density estimate of a multivariate distribution
+// with a full bandwidth matrix,
+//  f(x) = 1/(W |H|^(1/2)) Σ_i w_i K(H^(-1/2) (x - x_i))
+// where K is the kernel, H is the bandwidth matrix, x_i and w_i are the
+// observations and their weights, and W is the sum of the weights.
+//
+// The observations are held in a k-d tree in the coordinates whitened by the
+// bandwidth matrix, so that only the observations within the support of the
+// kernel contribute to each evaluation. The Gaussian kernel is truncated at a
+// whitened distance of 8, except where no observation lies within that
+// distance, when all the observations are used.
+type Multivariate struct {
+	kernel Kernel
+	dim    int
+
+	chol    mat.Cholesky
+	logNorm float64
+
+	x    *mat.Dense
+	z    *kdtree.Tree
+	pts  wpoints
+	w    []float64
+	cum  []float64
+	sumW float64
+
+	norm, f64 func() float64
+}
+
+// NewMultivariate returns a kernel density estimate of the distribution of
+// the weighted observations in the rows of x using the kernel k with the
+// given bandwidth matrix. If weights is nil, all the observations have unit
+// weight, otherwise len(weights) must equal the number of rows of x. The Rand
+// method uses src as its source of random numbers, or the global source if
+// src is nil.
+//
+// The bandwidth matrix may be chosen with ScottMatrix or SilvermanMatrix.
+// If the bandwidth matrix is not positive definite, NewMultivariate returns
+// nil and false.
+func NewMultivariate(x mat.Matrix, weights []float64, k Kernel, bandwidth mat.Symmetric, src rand.Source) (*Multivariate, bool) {
+	r, d := x.Dims()
+	if r == 0 {
+		panic(badData)
+	}
+	if bandwidth.Symmetric() != d {
+		panic(badLength)
+	}
+	m := &Multivariate{
+		kernel: k,
+		dim:    d,
+		x:      mat.DenseCopyOf(x),
+		w:      unitWeights(r, weights),
+		norm:   rand.NormFloat64,
+		f64:    rand.Float64,
+	}
+	if !m.chol.Factorize(bandwidth) {
+		return nil, false
+	}
+	m.cum = make([]float64, r)
+	floats.CumSum(m.cum, m.w)
+	m.sumW = m.cum[r-1]
+	m.logNorm = k.logNorm(d) - 0.5*m.chol.LogDet() - math.Log(m.sumW)
+
+	// Whiten the observations by solving Uᵀ z = x
+	// where H = Uᵀ U.
+	var z mat.Dense
+	err := z.Solve(m.chol.RawU().T(), m.x.T())
+	if err != nil {
+		return nil, false
+	}
+	m.pts = make(wpoints, r)
+	for i := range m.pts {
+		m.pts[i] = wpoint{z: mat.Col(nil, i, &z), idx: i}
+	}
+	m.z = kdtree.New(append(wpoints(nil), m.pts...), false)
+
+	if src != nil {
+		rnd := rand.New(src)
+		m.norm = rnd.NormFloat64
+		m.f64 = rnd.Float64
+	}
+	return m, true
+}
+
+// Dim returns the dimension of the distribution.
+func (m *Multivariate) Dim() int {
+	return m.dim
+}
+
+// Prob returns the value of the density estimate at x.
+func (m *Multivariate) Prob(x []float64) float64 {
+	return math.Exp(m.LogProb(x))
+}
+
+// LogProb returns the log of the value of the density estimate at x.
+// LogProb panics if len(x) is not the dimension of the distribution.
+func (m *Multivariate) LogProb(x []float64) float64 {
+	if len(x) != m.dim {
+		panic(badLength)
+	}
+	q := make([]float64, m.dim)
+	err := mat.NewVecDense(m.dim, q).SolveVec(m.chol.RawU().T(), mat.NewVecDense(m.dim, x))
+	if err != nil {
+		return math.NaN()
+	}
+	query := wpoint{z: q}
+
+	r := m.kernel.radius()
+	keep := kdtree.NewDistKeeper(r * r)
+	m.z.NearestSet(keep, query)
+	var near []wpoint
+	for _, c := range keep.Heap {
+		if c.Comparable == nil {
+			continue
+		}
+		near = append(near, c.Comparable.(wpoint))
+	}
+	if len(near) == 0 {
+		if m.kernel != Gaussian {
+			return math.Inf(-1)
+		}
+		near = m.pts
+	}
+
+	// Sum the contributions in log space to avoid
+	// underflow with the Gaussian kernel.
+	max := math.Inf(-1)
+	logs := make([]float64, len(near))
+	for i, p := range near {
+		w := m.w[p.idx]
+		logs[i] = math.Log(w) + math.Log(m.kernel.profile(query.Distance(p)))
+		max = math.Max(max, logs[i])
+	}
+	if math.IsInf(max, -1) {
+		return max
+	}
+	var sum float64
+	for _, l := range logs {
+		sum += math.Exp(l - max)
+	}
+	return max + math.Log(sum) + m.logNorm
+}
+
+// Rand returns a random sample drawn from the density estimate. If dst is
+// not nil, the sample will be stored in-place into dst and returned,
+// otherwise a new slice will be allocated first. If dst is not nil, it must
+// have length equal to the dimension of the distribution.
+func (m *Multivariate) Rand(dst []float64) []float64 {
+	if dst == nil {
+		dst = make([]float64, m.dim)
+	} else if len(dst) != m.dim {
+		panic(badLength)
+	}
+	u := make([]float64, m.dim)
+	m.kernel.rand(u, m.norm, m.f64)
+	d := mat.NewVecDense(m.dim, dst)
+	d.MulVec(m.chol.RawU().T(), mat.NewVecDense(m.dim, u))
+	floats.Add(dst, m.x.RawRowView(pick(m.cum, m.f64)))
+	return dst
+}
+
+// wpoint is a whitened observation that satisfies the kdtree.Comparable
+// interface.
+type wpoint struct {
+	z   []float64
+	idx int
+}
+
+func (p wpoint) Compare(c kdtree.Comparable, d kdtree.Dim) float64 {
+	return p.z[d] - c.(wpoint).z[d]
+}
+
+func (p wpoint) Dims() int { return len(p.z) }
+
+func (p wpoint) Distance(c kdtree.Comparable) float64 {
+	return kdtree.Point(p.z).Distance(kdtree.Point(c.(wpoint).z))
+}
+
+// wpoints is a collection of whitened observations that satisfies the
+// kdtree.Interface.
+type wpoints []wpoint
+
+func (p wpoints) Index(i int) kdtree.Comparable         { return p[i] }
+func (p wpoints) Len() int                              { return len(p) }
+func (p wpoints) Pivot(d kdtree.Dim) int                { return wplane{wpoints: p, Dim: d}.Pivot() }
+func (p wpoints) Slice(start, end int) kdtree.Interface { return p[start:end] }
+
+// wplane allows a wpoints to be pivoted on a dimension.
+type wplane struct {
+	kdtree.Dim
+	wpoints
+}
+
+func (p wplane) Less(i, j int) bool {
+	return p.wpoints[i].z[p.Dim] < p.wpoints[j].z[p.Dim]
+}
+func (p wplane) Pivot() int {
+	return kdtree.Partition(p, kdtree.MedianOfRandoms(p, 100))
+}
+func (p wplane) Slice(start, end int) kdtree.SortSlicer {
+	p.wpoints = p.wpoints[start:end]
+	return p
+}
+func (p wplane) Swap(i, j int) {
+	p.wpoints[i], p.wpoints[j] = p.wpoints[j], p.wpoints[i]
+}
@@ -0,0 +1,111 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kde
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+)
+
+func TestMultivariate(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const (
+		n = 300
+		d = 2
+	)
+	x := mat.NewDense(n, d, nil)
+	w := make([]float64, n)
+	for i := 0; i < n; i++ {
+		z := rnd.NormFloat64()
+		x.Set(i, 0, z+rnd.NormFloat64()*0.5)
+		x.Set(i, 1, 2*z+rnd.NormFloat64())
+		w[i] = rnd.Float64()
+	}
+	for _, k := range []Kernel{Gaussian, Epanechnikov} {
+		for _, weights := range [][]float64{nil, w} {
+			name := fmt.Sprintf("kernel=%d weighted=%t", k, weights != nil)
+			var h mat.SymDense
+			ScottMatrix(&h, x, weights, k)
+			m, ok := NewMultivariate(x, weights, k, &h, rand.NewSource(1))
+			if !ok {
+				t.Fatalf("unexpected failure for %s", name)
+			}
+
+			var chol mat.Cholesky
+			chol.Factorize(&h)
+			var hinv mat.SymDense
+			chol.InverseTo(&hinv)
+			norm := math.Exp(k.logNorm(d)) / math.Sqrt(chol.Det())
+			for _, v := range [][]float64{{0, 0}, {1, 2}, {-1, 1}, {2, -2}, {0.5, 0.3}, {6, 10}} {
+				var want, sumW float64
+				for i := 0; i < n; i++ {
+					wi := weightAt(weights, i)
+					sumW += wi
+					diff := mat.NewVecDense(d, nil)
+					diff.SubVec(mat.NewVecDense(d, v), x.RowView(i))
+					want += wi * norm * k.profile(mat.Inner(diff, &hinv, diff))
+				}
+				want /= sumW
+				got := m.Prob(v)
+				// The absolute tolerance accounts for the truncation
+				// of the Gaussian kernel in the tails.
+				if !floats.EqualWithinAbsOrRel(got, want, 1e-12, 1e-10) {
+					t.Errorf("unexpected density for %s at %v: got:%v want:%v", name, v, got, want)
+				}
+			}
+
+			// Samples have the mean of the observations and their
+			// covariance inflated by the kernel covariance.
+			const samples = 50000
+			s := mat.NewDense(samples, d, nil)
+			for i := 0; i < samples; i++ {
+				m.Rand(s.RawRowView(i))
+			}
+			var cov, want mat.SymDense
+			stat.CovarianceMatrix(&cov, s, nil)
+			stat.CovarianceMatrix(&want, x, weights)
+			sumW := floats.Sum(m.w)
+			want.ScaleSym((sumW-1)/sumW, &want)
+			h.ScaleSym(k.secondMoment(d), &h)
+			want.AddSym(&want, &h)
+			if !mat.EqualApprox(&cov, &want, 0.1) {
+				t.Errorf("unexpected sample covariance for %s:\ngot: %v\nwant:%v", name, mat.Formatted(&cov), mat.Formatted(&want))
+			}
+		}
+	}
+}
+
+func TestMultivariateUnivariate(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const n = 100
+	x := make([]float64, n)
+	for i := range x {
+		x[i] = rnd.NormFloat64()
+	}
+	for _, k := range []Kernel{Gaussian, Epanechnikov} {
+		const h = 0.4
+		u := NewUnivariate(x, nil, k, h, nil)
+		m, ok := NewMultivariate(mat.NewDense(n, 1, x), nil, k, mat.NewSymDense(1, []float64{h * h}), nil)
+		if !ok {
+			t.Fatalf("unexpected failure for kernel %d", k)
+		}
+		for _, v := range []float64{-2, -0.3, 0, 1, 3} {
+			got := m.LogProb([]float64{v})
+			want := u.LogProb(v)
+			if !floats.EqualWithinAbsOrRel(got, want, 1e-12, 1e-12) {
+				t.Errorf("unexpected log density for kernel %d at %v: got:%v want:%v", k, v, got, want)
+			}
+		}
+	}
+}
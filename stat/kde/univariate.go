@@ -0,0 +1,280 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kde
+
+import (
+	"math"
+	"sort"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/dsp/fourier"
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+const (
+	badKernel    = "kde: unknown kernel"
+	badLength    = "kde: slice length mismatch"
+	badWeights   = "kde: negative weight"
+	badBandwidth = "kde: non-positive bandwidth"
+	badData      = "kde: no data"
+)
+
+var (
+	_ distuv.LogProber = (*Univariate)(nil)
+	_ distuv.Rander    = (*Univariate)(nil)
+)
+
+// Univariate is a kernel density estimate of a univariate distribution,
+//  f(x) = 1/(W h) Σ_i w_i K((x - x_i)/h)
+// where K is the kernel, h is the bandwidth, x_i and w_i are the
+// observations and their weights, and W is the sum of the weights.
+type Univariate struct {
+	kernel Kernel
+	h      float64
+
+	// x and w are the observations and their
+	// weights, sorted by x, and cum holds the
+	// cumulative sums of w.
+	x, w, cum []float64
+	sumW      float64
+
+	// lo, dx and grid hold the binned estimate
+	// when binning is enabled.
+	lo, dx float64
+	grid   []float64
+
+	norm, f64 func() float64
+}
+
+// NewUnivariate returns a kernel density estimate of the distribution of the
+// weighted observations x using the kernel k with the given bandwidth. If
+// weights is nil, all the observations have unit weight, otherwise
+// len(weights) must equal len(x). The Rand method uses src as its source of
+// random numbers, or the global source if src is nil.
+//
+// The bandwidth may be chosen with one of the selection functions Scott,
+// Silverman, PlugIn or CrossValidation. NewUnivariate panics if bandwidth
+// is not positive or x is empty.
+func NewUnivariate(x, weights []float64, k Kernel, bandwidth float64, src rand.Source) *Univariate {
+	if len(x) == 0 {
+		panic(badData)
+	}
+	if !(bandwidth > 0) {
+		panic(badBandwidth)
+	}
+	k.radius() // Check the kernel is valid.
+	xs := make([]float64, len(x))
+	copy(xs, x)
+	ws := unitWeights(len(x), weights)
+	stat.SortWeighted(xs, ws)
+	cum := make([]float64, len(ws))
+	floats.CumSum(cum, ws)
+
+	u := &Univariate{
+		kernel: k,
+		h:      bandwidth,
+		x:      xs,
+		w:      ws,
+		cum:    cum,
+		sumW:   cum[len(cum)-1],
+		norm:   rand.NormFloat64,
+		f64:    rand.Float64,
+	}
+	if src != nil {
+		rnd := rand.New(src)
+		u.norm = rnd.NormFloat64
+		u.f64 = rnd.Float64
+	}
+	return u
+}
+
+// Bandwidth returns the bandwidth of the estimate.
+func (u *Univariate) Bandwidth() float64 {
+	return u.h
+}
+
+// Bin enables fast approximate evaluation of the density. The estimate is
+// computed at n equally spaced grid points spanning the support of the
+// estimate by linearly binning the observations onto the grid and convolving
+// the bin weights with the kernel using the fast Fourier transform, taking
+// O(n log n) time independent of the number of observations. Subsequent calls
+// to Prob and LogProb interpolate linearly between the grid points, and
+// return zero density outside the grid. Calling Bin with n equal to zero
+// reverts to exact evaluation. Bin panics if n is 1 or negative.
+func (u *Univariate) Bin(n int) {
+	if n == 0 {
+		u.grid = nil
+		return
+	}
+	if n < 2 {
+		panic("kde: too few grid points")
+	}
+	cut := u.kernel.radius() * u.h
+	lo := u.x[0] - cut
+	hi := u.x[len(u.x)-1] + cut
+	dx := (hi - lo) / float64(n-1)
+
+	// Linear binning.
+	bins := make([]float64, n)
+	for i, x := range u.x {
+		pos := (x - lo) / dx
+		j := int(pos)
+		if j >= n-1 {
+			j = n - 2
+		}
+		frac := pos - float64(j)
+		bins[j] += u.w[i] * (1 - frac)
+		bins[j+1] += u.w[i] * frac
+	}
+
+	// Convolve with the kernel, padding to avoid wrap around.
+	lags := int(math.Ceil(cut / dx))
+	if lags > n-1 {
+		lags = n - 1
+	}
+	p := 1
+	for p < n+lags {
+		p <<= 1
+	}
+	seq := make([]float64, p)
+	copy(seq, bins)
+	kern := make([]float64, p)
+	for l := 0; l <= lags; l++ {
+		v := u.kernel.prob(float64(l)*dx/u.h) / u.h
+		kern[l] = v
+		if l != 0 {
+			kern[p-l] = v
+		}
+	}
+	fft := fourier.NewFFT(p)
+	cs := fft.Coefficients(nil, seq)
+	ck := fft.Coefficients(nil, kern)
+	for i := range cs {
+		cs[i] *= ck[i]
+	}
+	fft.Sequence(seq, cs)
+	grid := seq[:n]
+	scale := 1 / (float64(p) * u.sumW)
+	for i, v := range grid {
+		grid[i] = math.Max(0, v*scale)
+	}
+	u.lo, u.dx, u.grid = lo, dx, grid
+}
+
+// Prob returns the value of the density estimate at x.
+func (u *Univariate) Prob(x float64) float64 {
+	if u.grid != nil {
+		pos := (x - u.lo) / u.dx
+		if pos < 0 || pos > float64(len(u.grid)-1) {
+			return 0
+		}
+		j := int(pos)
+		if j == len(u.grid)-1 {
+			return u.grid[j]
+		}
+		frac := pos - float64(j)
+		return u.grid[j]*(1-frac) + u.grid[j+1]*frac
+	}
+	if u.kernel == Gaussian {
+		return math.Exp(u.LogProb(x))
+	}
+	lo, hi := u.neighbors(x)
+	var p float64
+	for i := lo; i < hi; i++ {
+		p += u.w[i] * u.kernel.prob((x-u.x[i])/u.h)
+	}
+	return p / (u.sumW * u.h)
+}
+
+// LogProb returns the log of the value of the density estimate at x.
+func (u *Univariate) LogProb(x float64) float64 {
+	if u.grid != nil || u.kernel != Gaussian {
+		return math.Log(u.Prob(x))
+	}
+	// Evaluate the Gaussian mixture in log space to
+	// avoid underflow far from the observations.
+	max := math.Inf(-1)
+	for i, xi := range u.x {
+		if u.w[i] == 0 {
+			continue
+		}
+		z := (x - xi) / u.h
+		max = math.Max(max, math.Log(u.w[i])-z*z/2)
+	}
+	if math.IsInf(max, -1) {
+		return max
+	}
+	var sum float64
+	for i, xi := range u.x {
+		if u.w[i] == 0 {
+			continue
+		}
+		z := (x - xi) / u.h
+		sum += math.Exp(math.Log(u.w[i]) - z*z/2 - max)
+	}
+	return max + math.Log(sum) + u.kernel.logNorm(1) - math.Log(u.sumW*u.h)
+}
+
+// CDF returns the value of the cumulative distribution function of the
+// density estimate at x.
+func (u *Univariate) CDF(x float64) float64 {
+	var p float64
+	for i, xi := range u.x {
+		p += u.w[i] * u.kernel.cdf((x-xi)/u.h)
+	}
+	return p / u.sumW
+}
+
+// Rand returns a random sample drawn from the density estimate.
+func (u *Univariate) Rand() float64 {
+	var v [1]float64
+	u.kernel.rand(v[:], u.norm, u.f64)
+	return u.x[pick(u.cum, u.f64)] + u.h*v[0]
+}
+
+// neighbors returns the range of indices of the observations within the
+// support of the kernel centered at x.
+func (u *Univariate) neighbors(x float64) (lo, hi int) {
+	r := u.kernel.radius() * u.h
+	lo = sort.SearchFloat64s(u.x, x-r)
+	hi = sort.Search(len(u.x), func(i int) bool { return u.x[i] > x+r })
+	return lo, hi
+}
+
+// pick returns an index drawn with probability proportional to the weights
+// whose cumulative sums are held in cum.
+func pick(cum []float64, f64 func() float64) int {
+	v := f64() * cum[len(cum)-1]
+	i := sort.Search(len(cum), func(i int) bool { return cum[i] > v })
+	if i == len(cum) {
+		i--
+	}
+	return i
+}
+
+// unitWeights returns a copy of weights, or unit weights if weights is nil,
+// after checking that it has length n and non-negative elements.
+func unitWeights(n int, weights []float64) []float64 {
+	w := make([]float64, n)
+	if weights == nil {
+		for i := range w {
+			w[i] = 1
+		}
+		return w
+	}
+	if len(weights) != n {
+		panic(badLength)
+	}
+	for i, v := range weights {
+		if v < 0 {
+			panic(badWeights)
+		}
+		w[i] = v
+	}
+	return w
+}
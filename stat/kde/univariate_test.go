@@ -0,0 +1,126 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kde
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/integrate/quad"
+	"gonum.org/v1/gonum/stat"
+)
+
+func TestUnivariate(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const n = 200
+	x := make([]float64, n)
+	w := make([]float64, n)
+	for i := range x {
+		if i%3 == 0 {
+			x[i] = rnd.NormFloat64() + 4
+		} else {
+			x[i] = rnd.ExpFloat64()
+		}
+		w[i] = rnd.Float64()
+	}
+	for _, k := range []Kernel{Gaussian, Epanechnikov} {
+		for _, weights := range [][]float64{nil, w} {
+			name := fmt.Sprintf("kernel=%d weighted=%t", k, weights != nil)
+			h := Silverman(x, weights, k)
+			u := NewUnivariate(x, weights, k, h, rand.NewSource(1))
+
+			// Compare with direct evaluation of the estimate.
+			for _, v := range []float64{-3, -0.5, 0, 0.1, 1, 2.5, 4, 7, 12} {
+				var want, sumW float64
+				for i, xi := range x {
+					wi := weightAt(weights, i)
+					sumW += wi
+					want += wi * k.prob((v-xi)/h)
+				}
+				want /= sumW * h
+				got := u.Prob(v)
+				if !floats.EqualWithinAbsOrRel(got, want, 1e-14, 1e-10) {
+					t.Errorf("unexpected density for %s at %v: got:%v want:%v", name, v, got, want)
+				}
+				if want > 0 {
+					if lp := u.LogProb(v); !floats.EqualWithinAbsOrRel(lp, math.Log(want), 1e-10, 1e-10) {
+						t.Errorf("unexpected log density for %s at %v: got:%v want:%v", name, v, lp, math.Log(want))
+					}
+				}
+				cdf := quad.Fixed(u.Prob, math.Inf(-1), v, 1000, nil, 0)
+				if k == Epanechnikov {
+					cdf = integrateFinite(u.Prob, u.x[0]-h, v, h/100)
+				}
+				if got := u.CDF(v); math.Abs(got-cdf) > 1e-6 {
+					t.Errorf("unexpected CDF for %s at %v: got:%v want:%v", name, v, got, cdf)
+				}
+			}
+
+			// The binned estimate approximates the exact estimate.
+			u.Bin(1 << 12)
+			for _, v := range []float64{-0.5, 0, 0.1, 1, 2.5, 4, 7} {
+				exact := NewUnivariate(x, weights, k, h, nil).Prob(v)
+				if got := u.Prob(v); math.Abs(got-exact) > 2e-3 {
+					t.Errorf("unexpected binned density for %s at %v: got:%v want:%v", name, v, got, exact)
+				}
+			}
+			u.Bin(0)
+
+			// Samples have the mean of the observations and their
+			// variance inflated by the kernel variance.
+			const samples = 100000
+			s := make([]float64, samples)
+			for i := range s {
+				s[i] = u.Rand()
+			}
+			mean, variance := stat.MeanVariance(s, nil)
+			wantMean, wantVar := stat.MeanVariance(x, weights)
+			sumW := sampleSize(x, weights)
+			wantVar = wantVar*(sumW-1)/sumW + h*h*k.secondMoment(1)
+			if math.Abs(mean-wantMean) > 0.03 {
+				t.Errorf("unexpected sample mean for %s: got:%v want:%v", name, mean, wantMean)
+			}
+			if math.Abs(variance-wantVar) > 0.03*wantVar {
+				t.Errorf("unexpected sample variance for %s: got:%v want:%v", name, variance, wantVar)
+			}
+		}
+	}
+}
+
+// integrateFinite integrates f over [a, b] using pieces of width at most h.
+func integrateFinite(f func(float64) float64, a, b, h float64) float64 {
+	if b <= a {
+		return 0
+	}
+	pieces := int(math.Ceil((b - a) / h))
+	step := (b - a) / float64(pieces)
+	var sum float64
+	for i := 0; i < pieces; i++ {
+		lo := a + float64(i)*step
+		sum += quad.Fixed(f, lo, lo+step, 50, nil, 0)
+	}
+	return sum
+}
+
+func TestUnivariateNormalizes(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	x := make([]float64, 50)
+	for i := range x {
+		x[i] = rnd.NormFloat64()
+	}
+	for _, k := range []Kernel{Gaussian, Epanechnikov} {
+		u := NewUnivariate(x, nil, k, 0.3, nil)
+		got := integrateFinite(u.Prob, floats.Min(x)-8*0.3, floats.Max(x)+8*0.3, 0.01)
+		if math.Abs(got-1) > 1e-6 {
+			t.Errorf("density for kernel %d does not integrate to 1: got:%v", k, got)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// KernelPC is a type for computing the kernel principal components of a
+// matrix, the principal components of the observations mapped into the
+// feature space implied by a positive semi-definite kernel function, as
+// described in Schölkopf, Smola and Müller, "Nonlinear component analysis as
+// a kernel eigenvalue problem", Neural Computation 10 (1998) 1299-1319. The
+// results of the analysis are only valid if the call to PrincipalComponents
+// was successful.
+type KernelPC struct {
+	x      *mat.Dense
+	kernel func(x, y []float64) float64
+	k      int
+
+	// colMean and mean are the column means and
+	// the overall mean of the kernel matrix.
+	colMean []float64
+	mean    float64
+
+	vals  []float64
+	alpha *mat.Dense
+	ok    bool
+}
+
+// PrincipalComponents performs a kernel principal components analysis with k
+// components on the matrix of the input data which is represented as an n×d
+// matrix a where each row is an observation and each column is a variable.
+// The kernel function returns the inner product of the images of x and y in
+// the feature space. With the kernel returning the dot product of x and y,
+// the analysis is equivalent to a principal components analysis.
+//
+// PrincipalComponents centers the observations in the feature space.
+// PrincipalComponents returns whether the analysis was successful. Components
+// with non-positive variance are not returned, so fewer than k components may
+// be available after a successful analysis.
+func (c *KernelPC) PrincipalComponents(a mat.Matrix, kernel func(x, y []float64) float64, k int) (ok bool) {
+	n, _ := a.Dims()
+	if k < 1 || n < k {
+		panic("stat: bad number of components")
+	}
+	c.ok = false
+	c.x = mat.DenseCopyOf(a)
+	c.kernel = kernel
+
+	gram := mat.NewSymDense(n, nil)
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			gram.SetSym(i, j, kernel(c.x.RawRowView(i), c.x.RawRowView(j)))
+		}
+	}
+	c.colMean = make([]float64, n)
+	c.mean = 0
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			c.colMean[j] += gram.At(i, j)
+		}
+	}
+	for j := range c.colMean {
+		c.colMean[j] /= float64(n)
+		c.mean += c.colMean[j]
+	}
+	c.mean /= float64(n)
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			gram.SetSym(i, j, gram.At(i, j)-c.colMean[i]-c.colMean[j]+c.mean)
+		}
+	}
+
+	vals, vecs, ok := eigenSymDescending(gram)
+	if !ok {
+		return false
+	}
+	// Keep the components with positive variance, with
+	// a tolerance relative to the largest eigenvalue.
+	tol := 1e-12 * math.Max(vals[0], 0) * float64(n)
+	for c.k = 0; c.k < k && vals[c.k] > tol; c.k++ {
+	}
+	if c.k == 0 {
+		return false
+	}
+	// The expansion coefficients of the component axes are
+	// the eigenvectors scaled to unit norm in feature space.
+	c.alpha = mat.NewDense(n, c.k, nil)
+	for j := 0; j < c.k; j++ {
+		s := 1 / math.Sqrt(vals[j])
+		for i := 0; i < n; i++ {
+			c.alpha.Set(i, j, s*vecs.At(i, j))
+		}
+	}
+	c.vals = make([]float64, c.k)
+	for j := range c.vals {
+		c.vals[j] = vals[j] / float64(n-1)
+	}
+	c.ok = true
+	return true
+}
+
+// Components returns the number of components of a successful analysis.
+// Components will panic if the receiver does not contain a successful
+// KernelPC.
+func (c *KernelPC) Components() int {
+	c.checkOK()
+	return c.k
+}
+
+// VarsTo returns the variances of the kernel principal component scores of
+// the observations in descending order. If dst is not nil it is used to store
+// the variances and returned. VarsTo will panic if the receiver does not
+// contain a successful KernelPC or dst is not nil and the length of dst is
+// not the number of components.
+func (c *KernelPC) VarsTo(dst []float64) []float64 {
+	c.checkOK()
+	if dst == nil {
+		dst = make([]float64, c.k)
+	} else if len(dst) != c.k {
+		panic("stat: length of slice does not match analysis")
+	}
+	copy(dst, c.vals)
+	return dst
+}
+
+// ScoresTo stores the kernel principal component scores of the observations
+// in the rows of the m×d matrix a into the rows of dst. The observations need
+// not be those used in the analysis.
+//
+// If dst is empty, ScoresTo will resize dst to be m×k. When dst is non-empty,
+// ScoresTo will panic if dst is not m×k. ScoresTo will also panic if the
+// receiver does not contain a successful KernelPC or a does not have d
+// columns.
+func (c *KernelPC) ScoresTo(dst *mat.Dense, a mat.Matrix) {
+	c.checkOK()
+	m, d := a.Dims()
+	n, d0 := c.x.Dims()
+	if d != d0 {
+		panic(mat.ErrShape)
+	}
+	if dst.IsEmpty() {
+		dst.ReuseAs(m, c.k)
+	} else if r, k := dst.Dims(); r != m || k != c.k {
+		panic(mat.ErrShape)
+	}
+	kx := mat.NewDense(m, n, nil)
+	row := make([]float64, d)
+	for i := 0; i < m; i++ {
+		mat.Row(row, i, a)
+		var rowMean float64
+		for j := 0; j < n; j++ {
+			v := c.kernel(row, c.x.RawRowView(j))
+			kx.Set(i, j, v)
+			rowMean += v
+		}
+		rowMean /= float64(n)
+		for j := 0; j < n; j++ {
+			kx.Set(i, j, kx.At(i, j)-rowMean-c.colMean[j]+c.mean)
+		}
+	}
+	dst.Mul(kx, c.alpha)
+}
+
+func (c *KernelPC) checkOK() {
+	if !c.ok {
+		panic("stat: use of unsuccessful kernel principal components analysis")
+	}
+}
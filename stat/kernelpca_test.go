@@ -0,0 +1,104 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestKernelPrincipalComponents(t *testing.T) {
+	const n, d, k = 50, 4, 3
+	rnd := rand.New(rand.NewSource(1))
+	x := mat.NewDense(n, d, nil)
+	for i := 0; i < n; i++ {
+		for j := 0; j < d; j++ {
+			x.Set(i, j, rnd.NormFloat64()*float64(d-j))
+		}
+	}
+
+	// With a linear kernel the analysis is
+	// equivalent to principal components.
+	var kpc KernelPC
+	if !kpc.PrincipalComponents(x, floats.Dot, k) {
+		t.Fatal("unexpected failure")
+	}
+	if got := kpc.Components(); got != k {
+		t.Fatalf("unexpected number of components: got:%d want:%d", got, k)
+	}
+	var pc PC
+	if !pc.PrincipalComponents(x, nil) {
+		t.Fatal("unexpected PCA failure")
+	}
+	wantVars := pc.VarsTo(nil)[:k]
+	gotVars := kpc.VarsTo(nil)
+	if !approxEqual(gotVars, wantVars, 1e-10) {
+		t.Errorf("unexpected variances: got:%v want:%v", gotVars, wantVars)
+	}
+
+	var vecs mat.Dense
+	pc.VectorsTo(&vecs)
+	var centered mat.Dense
+	centered.CloneFrom(x)
+	for j := 0; j < d; j++ {
+		m := Mean(mat.Col(nil, j, x), nil)
+		for i := 0; i < n; i++ {
+			centered.Set(i, j, centered.At(i, j)-m)
+		}
+	}
+	var want, got mat.Dense
+	want.Mul(&centered, vecs.Slice(0, d, 0, k))
+	kpc.ScoresTo(&got, x)
+	if !equalColsUpToSign(&got, &want, 1e-8) {
+		t.Errorf("unexpected scores:\ngot:\n%v\nwant:\n%v",
+			mat.Formatted(&got), mat.Formatted(&want))
+	}
+
+	// With a Gaussian kernel the scores of the training
+	// observations have the reported variances.
+	gauss := func(x, y []float64) float64 {
+		return math.Exp(-floats.Distance(x, y, 2) / 10)
+	}
+	if !kpc.PrincipalComponents(x, gauss, k) {
+		t.Fatal("unexpected failure")
+	}
+	var scores mat.Dense
+	kpc.ScoresTo(&scores, x)
+	gotVars = kpc.VarsTo(nil)
+	for j := range gotVars {
+		mean, v := MeanVariance(mat.Col(nil, j, &scores), nil)
+		if math.Abs(mean) > 1e-10 || math.Abs(v-gotVars[j]) > 1e-10 {
+			t.Errorf("unexpected score moments for component %d: got mean=%v var=%v want mean=0 var=%v",
+				j, mean, v, gotVars[j])
+		}
+	}
+}
+
+// equalColsUpToSign returns whether the columns of a and b are
+// equal within tol up to a change of sign of each column.
+func equalColsUpToSign(a, b mat.Matrix, tol float64) bool {
+	ra, ca := a.Dims()
+	rb, cb := b.Dims()
+	if ra != rb || ca != cb {
+		return false
+	}
+	for j := 0; j < ca; j++ {
+		sign := 1.0
+		if floats.Dot(mat.Col(nil, j, a), mat.Col(nil, j, b)) < 0 {
+			sign = -1
+		}
+		for i := 0; i < ra; i++ {
+			if !floats.EqualWithinAbsOrRel(a.At(i, j), sign*b.At(i, j), tol, tol) {
+				return false
+			}
+		}
+	}
+	return true
+}
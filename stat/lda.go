@@ -0,0 +1,247 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// LDA is a type for computing Fisher's linear discriminant analysis of a
+// matrix of observations with known classes. The discriminant directions
+// maximize the ratio of the between-class to the within-class variance of
+// the projected observations. The results of the analysis are only valid if
+// the call to LinearDiscriminants was successful.
+type LDA struct {
+	d, k    int
+	vectors *mat.Dense
+	values  []float64
+	means   *mat.Dense
+	classW  []float64
+	ok      bool
+}
+
+// LinearDiscriminants performs a weighted linear discriminant analysis on the
+// matrix of the input data which is represented as an n×d matrix a where each
+// row is an observation and each column is a variable. The class of each
+// observation is given by classes, with classes labeled from zero to g-1
+// where g is the number of classes.
+//
+// The weights slice is used to weight the observations. If weights is nil,
+// each weight is considered to have a value of one, otherwise the length of
+// weights must match the number of observations or LinearDiscriminants will
+// panic.
+//
+// LinearDiscriminants returns whether the analysis was successful. The
+// analysis is unsuccessful if the within-class scatter matrix is singular.
+func (l *LDA) LinearDiscriminants(a mat.Matrix, classes []int, weights []float64) (ok bool) {
+	n, d := a.Dims()
+	if len(classes) != n {
+		panic("stat: len(classes) != observations")
+	}
+	if weights != nil && len(weights) != n {
+		panic("stat: len(weights) != observations")
+	}
+	g := 0
+	for _, c := range classes {
+		if c < 0 {
+			panic("stat: negative class label")
+		}
+		if c >= g {
+			g = c + 1
+		}
+	}
+	l.ok = false
+	l.d = d
+
+	// Class means and the overall mean.
+	means := mat.NewDense(g, d, nil)
+	classW := make([]float64, g)
+	mean := make([]float64, d)
+	var sumW float64
+	for i := 0; i < n; i++ {
+		w := 1.0
+		if weights != nil {
+			w = weights[i]
+		}
+		c := classes[i]
+		classW[c] += w
+		sumW += w
+		for j := 0; j < d; j++ {
+			v := w * a.At(i, j)
+			means.Set(c, j, means.At(c, j)+v)
+			mean[j] += v
+		}
+	}
+	for c, w := range classW {
+		if w == 0 {
+			continue
+		}
+		for j := 0; j < d; j++ {
+			means.Set(c, j, means.At(c, j)/w)
+		}
+	}
+	for j := range mean {
+		mean[j] /= sumW
+	}
+
+	// Within and between class scatter matrices.
+	within := mat.NewSymDense(d, nil)
+	between := mat.NewSymDense(d, nil)
+	diff := mat.NewVecDense(d, nil)
+	for i := 0; i < n; i++ {
+		w := 1.0
+		if weights != nil {
+			w = weights[i]
+		}
+		c := classes[i]
+		for j := 0; j < d; j++ {
+			diff.SetVec(j, a.At(i, j)-means.At(c, j))
+		}
+		within.SymRankOne(within, w, diff)
+	}
+	groups := 0
+	for c, w := range classW {
+		if w == 0 {
+			continue
+		}
+		groups++
+		for j := 0; j < d; j++ {
+			diff.SetVec(j, means.At(c, j)-mean[j])
+		}
+		between.SymRankOne(between, w, diff)
+	}
+
+	// Solve the generalized eigenproblem B v = λ W v
+	// by transforming to L⁻¹ B L⁻ᵀ u = λ u where W = L Lᵀ.
+	var chol mat.Cholesky
+	if !chol.Factorize(within) {
+		return false
+	}
+	var lower mat.TriDense
+	chol.LTo(&lower)
+	var tmp, m mat.Dense
+	err := tmp.Solve(&lower, between)
+	if err != nil {
+		return false
+	}
+	err = m.Solve(&lower, tmp.T())
+	if err != nil {
+		return false
+	}
+	sym := mat.NewSymDense(d, nil)
+	for i := 0; i < d; i++ {
+		for j := i; j < d; j++ {
+			sym.SetSym(i, j, (m.At(i, j)+m.At(j, i))/2)
+		}
+	}
+	vals, vecs, ok := eigenSymDescending(sym)
+	if !ok {
+		return false
+	}
+	k := groups - 1
+	if k > d {
+		k = d
+	}
+	if k < 1 {
+		panic("stat: fewer than two classes")
+	}
+	u := vecs.Slice(0, d, 0, k)
+	var v mat.Dense
+	err = v.Solve(lower.T(), u)
+	if err != nil {
+		return false
+	}
+	// Scale the directions so that the projections have
+	// unit pooled within-class variance.
+	v.Scale(math.Sqrt(sumW-float64(groups)), &v)
+
+	l.k = k
+	l.vectors = &v
+	l.values = vals[:k]
+	l.means = means
+	l.classW = classW
+	l.ok = true
+	return true
+}
+
+// VectorsTo returns the discriminant direction vectors of a successful
+// analysis. The vectors are returned in the columns of a d×k matrix where k
+// is the smaller of d and one less than the number of classes, in order of
+// decreasing discriminant ratio. The vectors are scaled so that the projected
+// observations have unit pooled within-class variance.
+//
+// If dst is empty, VectorsTo will resize dst to be d×k. When dst is
+// non-empty, VectorsTo will panic if dst is not d×k. VectorsTo will also
+// panic if the receiver does not contain a successful LDA.
+func (l *LDA) VectorsTo(dst *mat.Dense) {
+	l.checkOK()
+	copyTo(dst, l.vectors)
+}
+
+// ValuesTo returns the ratios of the between-class to the within-class
+// scatter of the projections onto each discriminant direction, in
+// decreasing order. If dst is not nil it is used to store the ratios and
+// returned. ValuesTo will panic if the receiver does not contain a
+// successful LDA or dst is not nil and the length of dst is not k.
+func (l *LDA) ValuesTo(dst []float64) []float64 {
+	l.checkOK()
+	if dst == nil {
+		dst = make([]float64, l.k)
+	} else if len(dst) != l.k {
+		panic("stat: length of slice does not match analysis")
+	}
+	copy(dst, l.values)
+	return dst
+}
+
+// MeansTo returns the class means of a successful analysis in the rows of a
+// g×d matrix.
+//
+// If dst is empty, MeansTo will resize dst to be g×d. When dst is non-empty,
+// MeansTo will panic if dst is not g×d. MeansTo will also panic if the
+// receiver does not contain a successful LDA.
+func (l *LDA) MeansTo(dst *mat.Dense) {
+	l.checkOK()
+	copyTo(dst, l.means)
+}
+
+// Classify returns the class whose mean is nearest to x in the space of the
+// discriminant projections. Classes without observations are ignored.
+// Classify will panic if the receiver does not contain a successful LDA or
+// len(x) is not d.
+func (l *LDA) Classify(x []float64) int {
+	l.checkOK()
+	if len(x) != l.d {
+		panic("stat: slice length mismatch")
+	}
+	g, _ := l.means.Dims()
+	px := make([]float64, l.k)
+	pm := make([]float64, l.k)
+	mat.NewVecDense(l.k, px).MulVec(l.vectors.T(), mat.NewVecDense(l.d, x))
+	best, bestDist := -1, math.Inf(1)
+	for c := 0; c < g; c++ {
+		if l.classW[c] == 0 {
+			continue
+		}
+		mat.NewVecDense(l.k, pm).MulVec(l.vectors.T(), l.means.RowView(c))
+		var dist float64
+		for j := range px {
+			d := px[j] - pm[j]
+			dist += d * d
+		}
+		if dist < bestDist {
+			best, bestDist = c, dist
+		}
+	}
+	return best
+}
+
+func (l *LDA) checkOK() {
+	if !l.ok {
+		panic("stat: use of unsuccessful linear discriminant analysis")
+	}
+}
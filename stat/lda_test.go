@@ -0,0 +1,156 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestLinearDiscriminants(t *testing.T) {
+	const perClass = 200
+	rnd := rand.New(rand.NewSource(1))
+	centers := [][]float64{
+		{0, 0, 0, 0},
+		{3, 1, 0, 0},
+		{0, 4, 1, 0},
+	}
+	g, d := len(centers), len(centers[0])
+	n := g * perClass
+	x := mat.NewDense(n, d, nil)
+	classes := make([]int, n)
+	weights := make([]float64, n)
+	for i := 0; i < n; i++ {
+		c := i % g
+		classes[i] = c
+		weights[i] = 1 + rnd.Float64()
+		for j := 0; j < d; j++ {
+			x.Set(i, j, centers[c][j]+rnd.NormFloat64()*float64(j+1)/2)
+		}
+	}
+
+	var lda LDA
+	if !lda.LinearDiscriminants(x, classes, weights) {
+		t.Fatal("unexpected failure")
+	}
+	var v mat.Dense
+	lda.VectorsTo(&v)
+	if r, c := v.Dims(); r != d || c != g-1 {
+		t.Fatalf("unexpected vectors shape: got:%d×%d want:%d×%d", r, c, d, g-1)
+	}
+	vals := lda.ValuesTo(nil)
+	if !sortedDescending(vals) || vals[len(vals)-1] <= 0 {
+		t.Errorf("unexpected discriminant ratios: %v", vals)
+	}
+
+	// Check that the projections are uncorrelated with unit
+	// within-class variance and that the directions solve
+	// the generalized eigenproblem B v = λ W v.
+	var means mat.Dense
+	lda.MeansTo(&means)
+	within := mat.NewSymDense(d, nil)
+	between := mat.NewSymDense(d, nil)
+	mean := make([]float64, d)
+	sumW := floats.Sum(weights)
+	classW := make([]float64, g)
+	diff := mat.NewVecDense(d, nil)
+	for i := 0; i < n; i++ {
+		c := classes[i]
+		classW[c] += weights[i]
+		for j := 0; j < d; j++ {
+			diff.SetVec(j, x.At(i, j)-means.At(c, j))
+			mean[j] += weights[i] * x.At(i, j) / sumW
+		}
+		within.SymRankOne(within, weights[i], diff)
+	}
+	for c := 0; c < g; c++ {
+		for j := 0; j < d; j++ {
+			diff.SetVec(j, means.At(c, j)-mean[j])
+		}
+		between.SymRankOne(between, classW[c], diff)
+	}
+	var wv, bv, vwv mat.Dense
+	wv.Mul(within, &v)
+	bv.Mul(between, &v)
+	vwv.Mul(v.T(), &wv)
+	vwv.Scale(1/(sumW-float64(g)), &vwv)
+	if !mat.EqualApprox(&vwv, eye(g-1), 1e-10) {
+		t.Errorf("unexpected within-class covariance of projections:\n%v", mat.Formatted(&vwv))
+	}
+	for j, l := range vals {
+		for i := 0; i < d; i++ {
+			if math.Abs(bv.At(i, j)-l*wv.At(i, j)) > 1e-8*math.Max(1, math.Abs(bv.At(i, j))) {
+				t.Errorf("direction %d does not solve the eigenproblem", j)
+				break
+			}
+		}
+	}
+
+	var correct int
+	for i := 0; i < n; i++ {
+		if lda.Classify(x.RawRowView(i)) == classes[i] {
+			correct++
+		}
+	}
+	if acc := float64(correct) / float64(n); acc < 0.9 {
+		t.Errorf("unexpectedly low classification accuracy: %v", acc)
+	}
+}
+
+func TestLinearDiscriminantsTwoClass(t *testing.T) {
+	// With two classes the single discriminant direction
+	// is proportional to W⁻¹ (μ₁ - μ₀).
+	x := mat.NewDense(8, 2, []float64{
+		1, 2,
+		2, 3,
+		3, 3,
+		4, 5,
+		5, 5,
+		4, 2,
+		5, 0,
+		6, 2,
+	})
+	classes := []int{0, 0, 0, 0, 1, 1, 1, 1}
+
+	var lda LDA
+	if !lda.LinearDiscriminants(x, classes, nil) {
+		t.Fatal("unexpected failure")
+	}
+	var v, means mat.Dense
+	lda.VectorsTo(&v)
+	lda.MeansTo(&means)
+
+	within := mat.NewSymDense(2, nil)
+	diff := mat.NewVecDense(2, nil)
+	for i, c := range classes {
+		diff.SubVec(x.RowView(i), means.RowView(c))
+		within.SymRankOne(within, 1, diff)
+	}
+	var want mat.VecDense
+	diff.SubVec(means.RowView(1), means.RowView(0))
+	err := want.SolveVec(within, diff)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := mat.Col(nil, 0, &v)
+	r := floats.Dot(got, want.RawVector().Data) / (floats.Norm(got, 2) * mat.Norm(&want, 2))
+	if math.Abs(math.Abs(r)-1) > 1e-12 {
+		t.Errorf("discriminant direction not parallel to W⁻¹(μ₁-μ₀): cos=%v", r)
+	}
+}
+
+func sortedDescending(s []float64) bool {
+	for i := 1; i < len(s); i++ {
+		if s[i] > s[i-1] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,307 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mds
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// SMACOF holds the settings for multidimensional scaling by majorization of
+// the stress
+//  σ(X) = Σ_{i<j} w_ij (δ_ij - d_ij(X))²
+// where δ_ij are the target dissimilarities, d_ij(X) are the Euclidean
+// distances between the rows of the configuration X and w_ij are the weights,
+// using the SMACOF algorithm described in de Leeuw and Mair, "Multidimensional
+// scaling using majorization: SMACOF in R", Journal of Statistical Software 31
+// (2009) 1-30.
+type SMACOF struct {
+	// NonMetric specifies that only the order of
+	// the dissimilarities is to be preserved. The
+	// target dissimilarities are then the disparities,
+	// the monotone regression of the distances on the
+	// dissimilarities, with tied dissimilarities
+	// allowed to have different disparities.
+	NonMetric bool
+
+	// Weights holds the weights of the dissimilarities.
+	// If Weights is nil, all the weights are one.
+	// Pairs with zero weight are treated as missing.
+	Weights mat.Symmetric
+
+	// Initial is the initial configuration. If Initial
+	// is nil, the classical scaling of the dissimilarities
+	// computed by TorgersonScaling is used.
+	Initial mat.Matrix
+
+	// Tol is the convergence tolerance for the relative
+	// change in stress. If Tol is zero, a default of
+	// 1e-8 is used.
+	Tol float64
+
+	// MaxIter is the maximum number of iterations. If
+	// MaxIter is zero, a default of 1000 is used.
+	MaxIter int
+}
+
+// Scale computes a k-dimensional configuration whose interpoint distances
+// approximate the dissimilarities in dis, placing the coordinates of the
+// points in the rows of dst. Scale returns Kruskal's stress-1 of the
+// configuration,
+//  √(Σ_{i<j} w_ij (δ_ij - d_ij)² / Σ_{i<j} w_ij d_ij²)
+// and whether the iteration converged within MaxIter iterations.
+//
+// If dst is empty, it is resized to be n×k. When dst is non-empty, Scale will
+// panic if dst is not n×k.
+func (s SMACOF) Scale(dst *mat.Dense, dis mat.Symmetric, k int) (stress float64, ok bool) {
+	n := dis.Symmetric()
+	if k < 1 {
+		panic("mds: bad dimension")
+	}
+	if dst.IsEmpty() {
+		dst.ReuseAs(n, k)
+	} else if r, c := dst.Dims(); r != n || c != k {
+		panic(mat.ErrShape)
+	}
+	if s.Weights != nil && s.Weights.Symmetric() != n {
+		panic(mat.ErrShape)
+	}
+	weight := func(i, j int) float64 {
+		if s.Weights == nil {
+			return 1
+		}
+		return s.Weights.At(i, j)
+	}
+
+	x := mat.NewDense(n, k, nil)
+	if s.Initial != nil {
+		if r, c := s.Initial.Dims(); r != n || c != k {
+			panic(mat.ErrShape)
+		}
+		x.Copy(s.Initial)
+	} else {
+		initialConfiguration(x, dis)
+	}
+
+	// Build the pseudo-inverse of V = Σ w_ij (e_i - e_j)(e_i - e_j)ᵀ
+	// for weighted problems. For unit weights it is I/n
+	// on centered configurations.
+	var vinv *mat.Dense
+	if s.Weights != nil {
+		v := mat.NewSymDense(n, nil)
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				w := weight(i, j)
+				v.SetSym(i, j, v.At(i, j)-w+1)
+				v.SetSym(i, i, v.At(i, i)+w)
+				v.SetSym(j, j, v.At(j, j)+w)
+			}
+			v.SetSym(i, i, v.At(i, i)+1)
+		}
+		vinv = &mat.Dense{}
+		err := vinv.Inverse(v)
+		if err != nil {
+			panic("mds: disconnected weights")
+		}
+		c := 1 / float64(n*n)
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				vinv.Set(i, j, vinv.At(i, j)-c)
+			}
+		}
+	}
+
+	// Pairs in increasing order of dissimilarity for
+	// non-metric scaling.
+	type pair struct{ i, j int }
+	var pairs []pair
+	var sumWDelta2 float64
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			w := weight(i, j)
+			if w == 0 {
+				continue
+			}
+			pairs = append(pairs, pair{i, j})
+			d := dis.At(i, j)
+			sumWDelta2 += w * d * d
+		}
+	}
+	delta := mat.NewSymDense(n, nil)
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			delta.SetSym(i, j, dis.At(i, j))
+		}
+	}
+
+	dist := mat.NewSymDense(n, nil)
+	distances := func() {
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				var d2 float64
+				for c := 0; c < k; c++ {
+					v := x.At(i, c) - x.At(j, c)
+					d2 += v * v
+				}
+				dist.SetSym(i, j, math.Sqrt(d2))
+			}
+		}
+	}
+	rawStress := func() float64 {
+		var sum float64
+		for _, p := range pairs {
+			r := delta.At(p.i, p.j) - dist.At(p.i, p.j)
+			sum += weight(p.i, p.j) * r * r
+		}
+		return sum
+	}
+
+	tol := s.Tol
+	if tol == 0 {
+		tol = 1e-8
+	}
+	maxIter := s.MaxIter
+	if maxIter == 0 {
+		maxIter = 1000
+	}
+
+	distances()
+	var monotone func()
+	if s.NonMetric {
+		disp := make([]float64, len(pairs))
+		idx := make([]int, len(pairs))
+		monotone = func() {
+			for i := range idx {
+				idx[i] = i
+			}
+			sort.Slice(idx, func(a, b int) bool {
+				pa, pb := pairs[idx[a]], pairs[idx[b]]
+				da, db := dis.At(pa.i, pa.j), dis.At(pb.i, pb.j)
+				if da != db {
+					return da < db
+				}
+				return dist.At(pa.i, pa.j) < dist.At(pb.i, pb.j)
+			})
+			y := make([]float64, len(idx))
+			w := make([]float64, len(idx))
+			for r, q := range idx {
+				p := pairs[q]
+				y[r] = dist.At(p.i, p.j)
+				w[r] = weight(p.i, p.j)
+			}
+			isotonic(disp, y, w)
+			// Normalize the disparities to the scale
+			// of the dissimilarities.
+			var sum float64
+			for r, q := range idx {
+				sum += weight(pairs[q].i, pairs[q].j) * disp[r] * disp[r]
+			}
+			f := math.Sqrt(sumWDelta2 / sum)
+			for r, q := range idx {
+				p := pairs[q]
+				delta.SetSym(p.i, p.j, f*disp[r])
+			}
+		}
+		monotone()
+	}
+
+	b := mat.NewDense(n, n, nil)
+	var next mat.Dense
+	prev := rawStress()
+	ok = false
+	for it := 0; it < maxIter; it++ {
+		// Guttman transform.
+		b.Zero()
+		for _, p := range pairs {
+			d := dist.At(p.i, p.j)
+			if d == 0 {
+				continue
+			}
+			v := -weight(p.i, p.j) * delta.At(p.i, p.j) / d
+			b.Set(p.i, p.j, v)
+			b.Set(p.j, p.i, v)
+			b.Set(p.i, p.i, b.At(p.i, p.i)-v)
+			b.Set(p.j, p.j, b.At(p.j, p.j)-v)
+		}
+		next.Mul(b, x)
+		if vinv != nil {
+			x.Mul(vinv, &next)
+		} else {
+			x.Scale(1/float64(n), &next)
+		}
+		distances()
+		if s.NonMetric {
+			monotone()
+		}
+		cur := rawStress()
+		if prev-cur <= tol*prev {
+			ok = true
+			prev = cur
+			break
+		}
+		prev = cur
+	}
+
+	var sumD2 float64
+	for _, p := range pairs {
+		d := dist.At(p.i, p.j)
+		sumD2 += weight(p.i, p.j) * d * d
+	}
+	dst.Copy(x)
+	return math.Sqrt(prev / sumD2), ok
+}
+
+// initialConfiguration fills x with the classical scaling of dis, padding
+// with small deterministic perturbations when the classical scaling has
+// fewer than the required number of dimensions.
+func initialConfiguration(x *mat.Dense, dis mat.Symmetric) {
+	n, k := x.Dims()
+	var ts mat.Dense
+	m, _ := TorgersonScaling(&ts, nil, dis)
+	if m > k {
+		m = k
+	}
+	for i := 0; i < n; i++ {
+		for j := 0; j < k; j++ {
+			if j < m {
+				x.Set(i, j, ts.At(i, j))
+			} else {
+				x.Set(i, j, 1e-3*math.Sin(float64((i+1)*(j+1))))
+			}
+		}
+	}
+}
+
+// isotonic stores in dst the weighted least squares non-decreasing fit to y
+// computed by the pool adjacent violators algorithm.
+func isotonic(dst, y, w []float64) {
+	type block struct {
+		sum, w float64
+		n      int
+	}
+	blocks := make([]block, 0, len(y))
+	for i, v := range y {
+		blocks = append(blocks, block{sum: w[i] * v, w: w[i], n: 1})
+		for len(blocks) > 1 {
+			last := blocks[len(blocks)-1]
+			prev := blocks[len(blocks)-2]
+			if prev.sum*last.w <= last.sum*prev.w {
+				break
+			}
+			blocks = blocks[:len(blocks)-1]
+			blocks[len(blocks)-1] = block{sum: prev.sum + last.sum, w: prev.w + last.w, n: prev.n + last.n}
+		}
+	}
+	i := 0
+	for _, b := range blocks {
+		v := b.sum / b.w
+		for j := 0; j < b.n; j++ {
+			dst[i] = v
+			i++
+		}
+	}
+}
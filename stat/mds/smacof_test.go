@@ -0,0 +1,156 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mds
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestSMACOF(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const (
+		n = 20
+		k = 2
+	)
+	points := mat.NewDense(n, k, nil)
+	for i := 0; i < n; i++ {
+		for j := 0; j < k; j++ {
+			points.Set(i, j, 10*rnd.Float64())
+		}
+	}
+	dis := distanceMatrix(points)
+	weights := mat.NewSymDense(n, nil)
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			weights.SetSym(i, j, 0.5+rnd.Float64())
+		}
+	}
+	// A monotone transformation of the distances.
+	transformed := mat.NewSymDense(n, nil)
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			transformed.SetSym(i, j, math.Exp(dis.At(i, j)/4))
+		}
+	}
+	// A random starting configuration.
+	initial := mat.NewDense(n, k, nil)
+	for i := 0; i < n; i++ {
+		for j := 0; j < k; j++ {
+			initial.Set(i, j, rnd.NormFloat64())
+		}
+	}
+
+	for _, test := range []struct {
+		name string
+		s    SMACOF
+		dis  mat.Symmetric
+		tol  float64
+	}{
+		{name: "metric", s: SMACOF{}, dis: dis, tol: 1e-6},
+		{name: "metric random start", s: SMACOF{Initial: initial, MaxIter: 10000, Tol: 1e-12}, dis: dis, tol: 1e-4},
+		{name: "weighted", s: SMACOF{Weights: weights}, dis: dis, tol: 1e-6},
+		{name: "non-metric", s: SMACOF{NonMetric: true, MaxIter: 10000, Tol: 1e-12}, dis: transformed, tol: 0.02},
+	} {
+		var got mat.Dense
+		stress, ok := test.s.Scale(&got, test.dis, k)
+		if !ok {
+			t.Errorf("unexpected convergence failure for %s", test.name)
+		}
+		if stress > test.tol {
+			t.Errorf("unexpected stress for %s: got:%v want<=%v", test.name, stress, test.tol)
+		}
+		if test.s.NonMetric {
+			// The order of the distances is preserved.
+			gotDis := distanceMatrix(&got)
+			var discordant, pairs int
+			for i := 0; i < n; i++ {
+				for j := i + 1; j < n; j++ {
+					for p := 0; p < n; p++ {
+						for q := p + 1; q < n; q++ {
+							pairs++
+							if (dis.At(i, j)-dis.At(p, q))*(gotDis.At(i, j)-gotDis.At(p, q)) < 0 {
+								discordant++
+							}
+						}
+					}
+				}
+			}
+			if frac := float64(discordant) / float64(pairs); frac > 0.01 {
+				t.Errorf("unexpected fraction of discordant pairs for %s: got:%v", test.name, frac)
+			}
+			continue
+		}
+		gotDis := distanceMatrix(&got)
+		if !mat.EqualApprox(gotDis, dis, 100*test.tol) {
+			t.Errorf("unexpected distances for %s", test.name)
+		}
+	}
+}
+
+func TestSMACOFImproves(t *testing.T) {
+	t.Parallel()
+	// The Torgerson scaling of non-Euclidean dissimilarities
+	// is improved by stress majorization.
+	dis := torgersonScalingTests[0].dis
+	var classical mat.Dense
+	TorgersonScaling(&classical, nil, dis)
+	classical = *classical.Slice(0, dis.Symmetric(), 0, 2).(*mat.Dense)
+	initial := SMACOF{Initial: &classical, MaxIter: 1}
+	var tmp mat.Dense
+	start, _ := initial.Scale(&tmp, dis, 2)
+
+	var got mat.Dense
+	stress, ok := SMACOF{}.Scale(&got, dis, 2)
+	if !ok {
+		t.Errorf("unexpected convergence failure")
+	}
+	if stress >= start {
+		t.Errorf("stress not improved: got:%v start:%v", stress, start)
+	}
+}
+
+func TestIsotonic(t *testing.T) {
+	t.Parallel()
+	y := []float64{1, 3, 2, 4, 3.5, 5, 0}
+	w := []float64{1, 1, 1, 1, 1, 1, 6}
+	got := make([]float64, len(y))
+	isotonic(got, y, w)
+	// The heavily weighted last element pools with all
+	// earlier elements except the first.
+	mean := (3 + 2 + 4 + 3.5 + 5 + 6*0.0) / 11
+	want := []float64{1, mean, mean, mean, mean, mean, mean}
+	if !floats.EqualApprox(got, want, 1e-14) {
+		t.Errorf("unexpected isotonic regression: got:%v want:%v", got, want)
+	}
+	for i := 1; i < len(got); i++ {
+		if got[i] < got[i-1] {
+			t.Errorf("isotonic regression not monotone: %v", got)
+			break
+		}
+	}
+}
+
+func distanceMatrix(x mat.Matrix) *mat.SymDense {
+	n, k := x.Dims()
+	d := mat.NewSymDense(n, nil)
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			var sum float64
+			for c := 0; c < k; c++ {
+				v := x.At(i, c) - x.At(j, c)
+				sum += v * v
+			}
+			d.SetSym(i, j, math.Sqrt(sum))
+		}
+	}
+	return d
+}
@@ -0,0 +1,484 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// OnlineMoments accumulates the weighted mean, variance, skewness and excess
+// kurtosis of a stream of values in a single pass, without retaining the
+// values. The statistics match those returned by Mean, Variance, Skew and
+// ExKurtosis for the same weighted sample.
+//
+// The central moments are updated with the algorithm of Welford extended to
+// higher moments and weighted data by Pébay, "Formulas for robust, one-pass
+// parallel computation of covariances and arbitrary-order statistical
+// moments", Sandia Report SAND2008-6212 (2008). Accumulators for disjoint
+// parts of a sample may be combined with Merge, so that partial results
+// computed concurrently can be combined. The zero value of OnlineMoments is
+// an empty accumulator.
+type OnlineMoments struct {
+	w, mean    float64
+	m2, m3, m4 float64
+}
+
+// Add adds the value x with weight w to the accumulator. Add panics if w is
+// negative.
+func (m *OnlineMoments) Add(x, w float64) {
+	if w < 0 {
+		panic("stat: negative weight")
+	}
+	if w == 0 {
+		return
+	}
+	m.merge(w, x, 0, 0, 0)
+}
+
+// Merge adds the values accumulated by o to the receiver.
+func (m *OnlineMoments) Merge(o *OnlineMoments) {
+	if o.w == 0 {
+		return
+	}
+	m.merge(o.w, o.mean, o.m2, o.m3, o.m4)
+}
+
+// merge combines the receiver with the summary of a set of values with
+// total weight wb, mean mb and central sums m2b, m3b and m4b.
+func (m *OnlineMoments) merge(wb, mb, m2b, m3b, m4b float64) {
+	wa := m.w
+	if wa == 0 {
+		m.w, m.mean, m.m2, m.m3, m.m4 = wb, mb, m2b, m3b, m4b
+		return
+	}
+	w := wa + wb
+	d := mb - m.mean
+	d2 := d * d
+	m.m4 += m4b + d2*d2*wa*wb*(wa*wa-wa*wb+wb*wb)/(w*w*w) +
+		6*d2*(wa*wa*m2b+wb*wb*m.m2)/(w*w) +
+		4*d*(wa*m3b-wb*m.m3)/w
+	m.m3 += m3b + d2*d*wa*wb*(wa-wb)/(w*w) + 3*d*(wa*m2b-wb*m.m2)/w
+	m.m2 += m2b + d2*wa*wb/w
+	m.mean += d * wb / w
+	m.w = w
+}
+
+// Reset empties the accumulator.
+func (m *OnlineMoments) Reset() {
+	*m = OnlineMoments{}
+}
+
+// SumWeights returns the sum of the weights of the accumulated values.
+func (m *OnlineMoments) SumWeights() float64 {
+	return m.w
+}
+
+// Mean returns the weighted mean of the accumulated values.
+func (m *OnlineMoments) Mean() float64 {
+	if m.w == 0 {
+		return math.NaN()
+	}
+	return m.mean
+}
+
+// Variance returns the unbiased weighted sample variance of the accumulated
+// values, as computed by Variance.
+func (m *OnlineMoments) Variance() float64 {
+	return m.m2 / (m.w - 1)
+}
+
+// StdDev returns the sample standard deviation of the accumulated values.
+func (m *OnlineMoments) StdDev() float64 {
+	return math.Sqrt(m.Variance())
+}
+
+// Skew returns the skewness of the accumulated values, as computed by Skew.
+func (m *OnlineMoments) Skew() float64 {
+	std := m.StdDev()
+	return m.m3 / (std * std * std) * skewCorrection(m.w)
+}
+
+// ExKurtosis returns the population excess kurtosis of the accumulated
+// values, as computed by ExKurtosis.
+func (m *OnlineMoments) ExKurtosis() float64 {
+	v := m.Variance()
+	mul, offset := kurtosisCorrection(m.w)
+	return m.m4/(v*v)*mul - offset
+}
+
+// OnlineCovariance accumulates the weighted mean and covariance matrix of a
+// stream of vectors in a single pass, without retaining the vectors. The
+// covariance matches that computed by CovarianceMatrix for the same weighted
+// sample. Accumulators for disjoint parts of a sample may be combined with
+// Merge.
+type OnlineCovariance struct {
+	w    float64
+	mean []float64
+	// m2 holds the sum of weighted outer products
+	// of the deviations from the mean.
+	m2   *mat.SymDense
+	diff []float64
+}
+
+// NewOnlineCovariance returns a new empty covariance accumulator for vectors
+// of length dim.
+func NewOnlineCovariance(dim int) *OnlineCovariance {
+	return &OnlineCovariance{
+		mean: make([]float64, dim),
+		m2:   mat.NewSymDense(dim, nil),
+		diff: make([]float64, dim),
+	}
+}
+
+// Dim returns the dimension of the accumulated vectors.
+func (c *OnlineCovariance) Dim() int {
+	return len(c.mean)
+}
+
+// Add adds the vector x with weight w to the accumulator. Add panics if w is
+// negative or if len(x) is not the dimension of the accumulator.
+func (c *OnlineCovariance) Add(x []float64, w float64) {
+	if len(x) != len(c.mean) {
+		panic("stat: slice length mismatch")
+	}
+	if w < 0 {
+		panic("stat: negative weight")
+	}
+	if w == 0 {
+		return
+	}
+	c.w += w
+	for i, v := range x {
+		c.diff[i] = v - c.mean[i]
+		c.mean[i] += c.diff[i] * w / c.w
+	}
+	// m2 += w (x - mean_old)(x - mean_new)ᵀ, which in symmetric form is
+	// w (w_old/w_new) (x - mean_old)(x - mean_old)ᵀ.
+	c.m2.SymRankOne(c.m2, w*(c.w-w)/c.w, mat.NewVecDense(len(c.diff), c.diff))
+}
+
+// AddRows adds the rows of x to the accumulator. If weights is nil all the
+// weights are 1, otherwise len(weights) must equal the number of rows of x.
+func (c *OnlineCovariance) AddRows(x mat.Matrix, weights []float64) {
+	r, cols := x.Dims()
+	if cols != len(c.mean) {
+		panic("stat: slice length mismatch")
+	}
+	if weights != nil && len(weights) != r {
+		panic("stat: slice length mismatch")
+	}
+	row := make([]float64, cols)
+	for i := 0; i < r; i++ {
+		mat.Row(row, i, x)
+		w := 1.0
+		if weights != nil {
+			w = weights[i]
+		}
+		c.Add(row, w)
+	}
+}
+
+// Merge adds the vectors accumulated by o to the receiver. Merge panics if
+// the dimensions of the accumulators differ.
+func (c *OnlineCovariance) Merge(o *OnlineCovariance) {
+	if len(o.mean) != len(c.mean) {
+		panic("stat: slice length mismatch")
+	}
+	if o.w == 0 {
+		return
+	}
+	wa, wb := c.w, o.w
+	w := wa + wb
+	for i := range c.diff {
+		c.diff[i] = o.mean[i] - c.mean[i]
+		c.mean[i] += c.diff[i] * wb / w
+	}
+	c.m2.AddSym(c.m2, o.m2)
+	c.m2.SymRankOne(c.m2, wa*wb/w, mat.NewVecDense(len(c.diff), c.diff))
+	c.w = w
+}
+
+// Reset empties the accumulator.
+func (c *OnlineCovariance) Reset() {
+	c.w = 0
+	for i := range c.mean {
+		c.mean[i] = 0
+	}
+	c.m2.Zero()
+}
+
+// SumWeights returns the sum of the weights of the accumulated vectors.
+func (c *OnlineCovariance) SumWeights() float64 {
+	return c.w
+}
+
+// Mean returns the weighted mean of the accumulated vectors. If dst is nil,
+// a new slice is allocated and returned. If dst is not nil, its length must
+// be the dimension of the accumulator.
+func (c *OnlineCovariance) Mean(dst []float64) []float64 {
+	if dst == nil {
+		dst = make([]float64, len(c.mean))
+	} else if len(dst) != len(c.mean) {
+		panic("stat: slice length mismatch")
+	}
+	copy(dst, c.mean)
+	return dst
+}
+
+// CovarianceMatrix stores the unbiased weighted covariance matrix of the
+// accumulated vectors in dst, as computed by CovarianceMatrix. The dst matrix
+// must either be empty or have the dimension of the accumulator.
+func (c *OnlineCovariance) CovarianceMatrix(dst *mat.SymDense) {
+	c.reuseAs(dst)
+	dst.ScaleSym(1/(c.w-1), c.m2)
+}
+
+// CorrelationMatrix stores the weighted correlation matrix of the
+// accumulated vectors in dst, as computed by CorrelationMatrix. The dst
+// matrix must either be empty or have the dimension of the accumulator.
+func (c *OnlineCovariance) CorrelationMatrix(dst *mat.SymDense) {
+	c.reuseAs(dst)
+	n := len(c.mean)
+	for i := 0; i < n; i++ {
+		si := math.Sqrt(c.m2.At(i, i))
+		for j := i; j < n; j++ {
+			dst.SetSym(i, j, c.m2.At(i, j)/(si*math.Sqrt(c.m2.At(j, j))))
+		}
+	}
+}
+
+func (c *OnlineCovariance) reuseAs(dst *mat.SymDense) {
+	n := len(c.mean)
+	if dst.IsEmpty() {
+		*dst = *(dst.GrowSym(n).(*mat.SymDense))
+	} else if dst.Symmetric() != n {
+		panic(mat.ErrShape)
+	}
+}
+
+// P2Quantile estimates a quantile of a stream of values using the P² algorithm
+// of Jain and Chlamtac, "The P² algorithm for dynamic calculation of quantiles
+// and histograms without storing observations", Communications of the ACM 28
+// (1985) 1076-1085. The estimator keeps five markers whose heights approximate
+// the minimum, the p/2, p and (1+p)/2 quantiles and the maximum, adjusting them
+// with piecewise-parabolic interpolation as values arrive.
+//
+// The first values are stored until the desired positions of the markers are
+// at least one apart, which needs 1+⌈2/min(p, 1-p)⌉ values and at least five.
+// The markers are then placed at the order statistics nearest their desired
+// positions. Before this the empirical quantile of the stored values is used.
+type P2Quantile struct {
+	p float64
+
+	n int
+	// q, pos and want are the heights, positions and
+	// desired positions of the markers. Positions are
+	// one-based.
+	q, pos, want [5]float64
+
+	// init holds the values added before
+	// the markers are placed.
+	init []float64
+}
+
+// NewP2Quantile returns a new estimator for the p quantile. NewP2Quantile
+// panics if p is not in (0, 1).
+func NewP2Quantile(p float64) *P2Quantile {
+	if !(0 < p && p < 1) {
+		panic("stat: quantile out of range")
+	}
+	return &P2Quantile{p: p}
+}
+
+// increments returns the increments of the desired marker positions for
+// each new value.
+func (e *P2Quantile) increments() [5]float64 {
+	p := e.p
+	return [5]float64{0, p / 2, p, (1 + p) / 2, 1}
+}
+
+// warmup returns the number of values stored before the markers are placed.
+func (e *P2Quantile) warmup() int {
+	return 1 + int(math.Ceil(2/math.Min(e.p, 1-e.p)))
+}
+
+// Add adds the value x to the estimator.
+func (e *P2Quantile) Add(x float64) {
+	e.n++
+	if m := e.warmup(); e.n <= m {
+		e.init = append(e.init, x)
+		if e.n == m {
+			e.start()
+		}
+		return
+	}
+
+	var k int
+	switch {
+	case x < e.q[0]:
+		e.q[0] = x
+		k = 0
+	case x >= e.q[4]:
+		e.q[4] = x
+		k = 3
+	default:
+		for k = 0; k < 3 && x >= e.q[k+1]; k++ {
+		}
+	}
+	for i := k + 1; i < 5; i++ {
+		e.pos[i]++
+	}
+	dn := e.increments()
+	for i := range e.want {
+		e.want[i] += dn[i]
+	}
+	e.adjust()
+}
+
+// start places the markers at the order statistics of the stored values
+// nearest their desired positions.
+func (e *P2Quantile) start() {
+	sort.Float64s(e.init)
+	m := float64(len(e.init))
+	for i, f := range e.increments() {
+		e.want[i] = 1 + (m-1)*f
+		e.pos[i] = math.Round(e.want[i])
+		e.q[i] = e.init[int(e.pos[i])-1]
+	}
+	e.init = nil
+}
+
+// adjust moves the interior markers toward their desired positions.
+func (e *P2Quantile) adjust() {
+	for i := 1; i < 4; i++ {
+		d := e.want[i] - e.pos[i]
+		if (d >= 1 && e.pos[i+1]-e.pos[i] > 1) || (d <= -1 && e.pos[i-1]-e.pos[i] < -1) {
+			s := math.Copysign(1, d)
+			q := e.parabolic(i, s)
+			if e.q[i-1] < q && q < e.q[i+1] {
+				e.q[i] = q
+			} else {
+				e.q[i] = e.linear(i, s)
+			}
+			e.pos[i] += s
+		}
+	}
+}
+
+func (e *P2Quantile) parabolic(i int, d float64) float64 {
+	q, n := e.q, e.pos
+	return q[i] + d/(n[i+1]-n[i-1])*((n[i]-n[i-1]+d)*(q[i+1]-q[i])/(n[i+1]-n[i])+
+		(n[i+1]-n[i]-d)*(q[i]-q[i-1])/(n[i]-n[i-1]))
+}
+
+func (e *P2Quantile) linear(i int, d float64) float64 {
+	j := i + int(d)
+	return e.q[i] + d*(e.q[j]-e.q[i])/(e.pos[j]-e.pos[i])
+}
+
+// Count returns the number of values added to the estimator.
+func (e *P2Quantile) Count() int {
+	return e.n
+}
+
+// Quantile returns the estimate of the quantile. If the markers have not yet
+// been placed, the empirical quantile of the values is returned. Quantile
+// returns NaN if no values have been added.
+func (e *P2Quantile) Quantile() float64 {
+	if e.n == 0 {
+		return math.NaN()
+	}
+	if e.n < e.warmup() {
+		x := append([]float64(nil), e.init...)
+		sort.Float64s(x)
+		return Quantile(e.p, Empirical, x, nil)
+	}
+	return e.q[2]
+}
+
+// Merge combines the values added to o into the receiver. The markers of the
+// merged estimator are placed by inverting the sum of the piecewise-linear
+// cumulative counts described by the markers of the two estimators, so the
+// merged estimate is approximate. Merge panics if the estimators are for
+// different quantiles.
+func (e *P2Quantile) Merge(o *P2Quantile) {
+	if e.p != o.p {
+		panic("stat: mismatched quantiles")
+	}
+	switch {
+	case o.n == 0:
+		return
+	case o.n < o.warmup():
+		for _, x := range o.init {
+			e.Add(x)
+		}
+		return
+	case e.n < e.warmup():
+		init := append([]float64(nil), e.init...)
+		*e = P2Quantile{p: o.p, n: o.n, q: o.q, pos: o.pos, want: o.want}
+		for _, x := range init {
+			e.Add(x)
+		}
+		return
+	}
+
+	a, b := *e, *o
+	n := float64(a.n + b.n)
+	count := func(x float64) float64 {
+		return a.count(x) + b.count(x)
+	}
+	lo := math.Min(a.q[0], b.q[0])
+	hi := math.Max(a.q[4], b.q[4])
+	p := e.p
+	frac := [5]float64{0, p / 2, p, (1 + p) / 2, 1}
+	e.n = a.n + b.n
+	for i, f := range frac {
+		e.want[i] = 1 + (n-1)*f
+	}
+	e.q[0], e.q[4] = lo, hi
+	e.pos[0], e.pos[4] = 1, n
+	for i := 1; i < 4; i++ {
+		// Find the height at which the combined count reaches
+		// the desired position by bisection.
+		target := e.want[i]
+		l, h := lo, hi
+		for k := 0; k < 100; k++ {
+			m := l + (h-l)/2
+			if count(m) < target {
+				l = m
+			} else {
+				h = m
+			}
+		}
+		e.q[i] = l + (h-l)/2
+		e.pos[i] = math.Max(math.Round(target), e.pos[i-1]+1)
+	}
+	for i := 3; i >= 1; i-- {
+		e.pos[i] = math.Min(e.pos[i], e.pos[i+1]-1)
+	}
+}
+
+// count returns the approximate number of values at or below x described by
+// the piecewise-linear interpolation of the markers.
+func (e *P2Quantile) count(x float64) float64 {
+	if x < e.q[0] {
+		return 0
+	}
+	if x >= e.q[4] {
+		return e.pos[4]
+	}
+	for i := 0; i < 4; i++ {
+		if x < e.q[i+1] {
+			if e.q[i+1] == e.q[i] {
+				return e.pos[i]
+			}
+			return e.pos[i] + (x-e.q[i])/(e.q[i+1]-e.q[i])*(e.pos[i+1]-e.pos[i])
+		}
+	}
+	return e.pos[4]
+}
@@ -0,0 +1,250 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestOnlineMoments(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for test, n := range []int{10, 100, 1000} {
+		x := make([]float64, n)
+		w := make([]float64, n)
+		for i := range x {
+			x[i] = rnd.ExpFloat64() + 10
+			w[i] = rnd.Float64() + 0.5
+		}
+		for _, weights := range [][]float64{nil, w} {
+			var m OnlineMoments
+			for i, v := range x {
+				wi := 1.0
+				if weights != nil {
+					wi = weights[i]
+				}
+				m.Add(v, wi)
+			}
+			checkOnlineMoments(t, test, "add", &m, x, weights)
+
+			// Accumulate in parts concurrently and merge.
+			const parts = 4
+			acc := make([]OnlineMoments, parts)
+			var wg sync.WaitGroup
+			for p := range acc {
+				wg.Add(1)
+				go func(p int) {
+					defer wg.Done()
+					for i := p; i < n; i += parts {
+						wi := 1.0
+						if weights != nil {
+							wi = weights[i]
+						}
+						acc[p].Add(x[i], wi)
+					}
+				}(p)
+			}
+			wg.Wait()
+			var merged OnlineMoments
+			for p := range acc {
+				merged.Merge(&acc[p])
+			}
+			checkOnlineMoments(t, test, "merge", &merged, x, weights)
+		}
+	}
+}
+
+func checkOnlineMoments(t *testing.T, test int, kind string, m *OnlineMoments, x, weights []float64) {
+	const tol = 1e-10
+	for _, stat := range []struct {
+		name      string
+		got, want float64
+	}{
+		{name: "mean", got: m.Mean(), want: Mean(x, weights)},
+		{name: "variance", got: m.Variance(), want: Variance(x, weights)},
+		{name: "skew", got: m.Skew(), want: Skew(x, weights)},
+		{name: "kurtosis", got: m.ExKurtosis(), want: ExKurtosis(x, weights)},
+	} {
+		if !floats.EqualWithinAbsOrRel(stat.got, stat.want, tol, tol) {
+			t.Errorf("unexpected %s for test %d %s weighted=%t: got:%v want:%v",
+				stat.name, test, kind, weights != nil, stat.got, stat.want)
+		}
+	}
+}
+
+func TestOnlineCovariance(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const (
+		n   = 500
+		dim = 4
+		tol = 1e-10
+	)
+	x := mat.NewDense(n, dim, nil)
+	w := make([]float64, n)
+	for i := 0; i < n; i++ {
+		z := rnd.NormFloat64()
+		for j := 0; j < dim; j++ {
+			x.Set(i, j, float64(j+1)*z+rnd.NormFloat64()+float64(j))
+		}
+		w[i] = rnd.Float64() + 0.5
+	}
+	for _, weights := range [][]float64{nil, w} {
+		var wantCov, wantCorr mat.SymDense
+		CovarianceMatrix(&wantCov, x, weights)
+		CorrelationMatrix(&wantCorr, x, weights)
+
+		c := NewOnlineCovariance(dim)
+		c.AddRows(x, weights)
+
+		const parts = 3
+		acc := make([]*OnlineCovariance, parts)
+		var wg sync.WaitGroup
+		for p := range acc {
+			acc[p] = NewOnlineCovariance(dim)
+			wg.Add(1)
+			go func(p int) {
+				defer wg.Done()
+				lo, hi := p*n/parts, (p+1)*n/parts
+				var pw []float64
+				if weights != nil {
+					pw = weights[lo:hi]
+				}
+				acc[p].AddRows(x.Slice(lo, hi, 0, dim), pw)
+			}(p)
+		}
+		wg.Wait()
+		merged := NewOnlineCovariance(dim)
+		for _, a := range acc {
+			merged.Merge(a)
+		}
+
+		for _, test := range []struct {
+			name string
+			c    *OnlineCovariance
+		}{
+			{name: "add", c: c},
+			{name: "merge", c: merged},
+		} {
+			mean := test.c.Mean(nil)
+			for j := range mean {
+				want := Mean(mat.Col(nil, j, x), weights)
+				if !floats.EqualWithinAbsOrRel(mean[j], want, tol, tol) {
+					t.Errorf("unexpected mean for %s weighted=%t column %d: got:%v want:%v",
+						test.name, weights != nil, j, mean[j], want)
+				}
+			}
+			var cov, corr mat.SymDense
+			test.c.CovarianceMatrix(&cov)
+			test.c.CorrelationMatrix(&corr)
+			if !mat.EqualApprox(&cov, &wantCov, tol) {
+				t.Errorf("unexpected covariance for %s weighted=%t:\ngot: %v\nwant:%v",
+					test.name, weights != nil, mat.Formatted(&cov), mat.Formatted(&wantCov))
+			}
+			if !mat.EqualApprox(&corr, &wantCorr, tol) {
+				t.Errorf("unexpected correlation for %s weighted=%t:\ngot: %v\nwant:%v",
+					test.name, weights != nil, mat.Formatted(&corr), mat.Formatted(&wantCorr))
+			}
+		}
+	}
+}
+
+func TestP2Quantile(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		p    float64
+		n    int
+		rand func() float64
+		tol  float64
+	}{
+		{p: 0.5, n: 3, rand: rnd.NormFloat64, tol: 0},
+		{p: 0.5, n: 10000, rand: rnd.NormFloat64, tol: 0.05},
+		{p: 0.9, n: 10000, rand: rnd.NormFloat64, tol: 0.05},
+		{p: 0.1, n: 10000, rand: rnd.ExpFloat64, tol: 0.02},
+		{p: 0.99, n: 100000, rand: rnd.Float64, tol: 0.01},
+	} {
+		x := make([]float64, test.n)
+		for i := range x {
+			x[i] = test.rand()
+		}
+		sorted := append([]float64(nil), x...)
+		sort.Float64s(sorted)
+		want := Quantile(test.p, Empirical, sorted, nil)
+
+		e := NewP2Quantile(test.p)
+		for _, v := range x {
+			e.Add(v)
+		}
+		if e.Count() != test.n {
+			t.Errorf("unexpected count for p=%v n=%d: got:%d", test.p, test.n, e.Count())
+		}
+		if got := e.Quantile(); math.Abs(got-want) > test.tol {
+			t.Errorf("unexpected quantile for p=%v n=%d: got:%v want:%v", test.p, test.n, got, want)
+		}
+
+		a := NewP2Quantile(test.p)
+		b := NewP2Quantile(test.p)
+		for i, v := range x {
+			if i < test.n/3 {
+				a.Add(v)
+			} else {
+				b.Add(v)
+			}
+		}
+		a.Merge(b)
+		if a.Count() != test.n {
+			t.Errorf("unexpected merged count for p=%v n=%d: got:%d", test.p, test.n, a.Count())
+		}
+		if got := a.Quantile(); math.Abs(got-want) > 2*test.tol {
+			t.Errorf("unexpected merged quantile for p=%v n=%d: got:%v want:%v", test.p, test.n, got, want)
+		}
+		// Continue adding after merging.
+		for _, v := range x {
+			a.Add(v)
+		}
+		if got := a.Quantile(); math.Abs(got-want) > 2*test.tol {
+			t.Errorf("unexpected quantile after merge for p=%v n=%d: got:%v want:%v", test.p, test.n, got, want)
+		}
+	}
+}
+
+func TestP2QuantileSmallSample(t *testing.T) {
+	t.Parallel()
+	// The estimate follows the empirical quantile
+	// until the markers are placed, and does not
+	// jump back when they are.
+	for _, p := range []float64{0.05, 0.1, 0.5, 0.9, 0.95} {
+		e := NewP2Quantile(p)
+		var x []float64
+		prev := math.Inf(-1)
+		for i := 1; i <= 100; i++ {
+			v := float64(i)
+			e.Add(v)
+			x = append(x, v)
+			got := e.Quantile()
+			if i < e.warmup() {
+				if want := Quantile(p, Empirical, x, nil); got != want {
+					t.Errorf("unexpected quantile for p=%v n=%d: got:%v want:%v", p, i, got, want)
+				}
+			}
+			if got < prev {
+				t.Errorf("quantile of increasing values decreased for p=%v n=%d: got:%v previous:%v", p, i, got, prev)
+			}
+			prev = got
+		}
+		if want := Quantile(p, Empirical, x, nil); math.Abs(prev-want) > 1 {
+			t.Errorf("unexpected quantile for p=%v n=100: got:%v want:%v", p, prev, want)
+		}
+	}
+}
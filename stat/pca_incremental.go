@@ -0,0 +1,153 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// IncrementalPC is a type for computing the leading principal components of
+// data that are presented in batches, without holding all the observations
+// in memory. After each batch the retained components are updated from the
+// singular value decomposition of the previous components, scaled by their
+// singular values, stacked with the new centered observations and a
+// correction for the change in the mean, as described in Ross, Lim, Lin and
+// Yang, "Incremental learning for robust visual tracking", International
+// Journal of Computer Vision 77 (2008) 125-141.
+//
+// The components computed by IncrementalPC are exact when the number of
+// retained components is not less than the rank of the data.
+type IncrementalPC struct {
+	d, k   int
+	n      float64
+	mean   []float64
+	vecs   *mat.Dense // k×d with the components in the rows.
+	values []float64  // Singular values of the centered data.
+	ok     bool
+}
+
+// NewIncrementalPC returns a new IncrementalPC for observations of d
+// variables retaining k components.
+func NewIncrementalPC(d, k int) *IncrementalPC {
+	if k < 1 || d < k {
+		panic("stat: bad number of components")
+	}
+	return &IncrementalPC{d: d, k: k, mean: make([]float64, d)}
+}
+
+// Update updates the principal components with the observations in the rows
+// of the batch matrix, which must have d columns. Update returns whether the
+// update was successful. If the update is unsuccessful, the receiver is not
+// modified.
+func (c *IncrementalPC) Update(batch mat.Matrix) (ok bool) {
+	m, d := batch.Dims()
+	if d != c.d {
+		panic(mat.ErrShape)
+	}
+	if m == 0 {
+		return true
+	}
+
+	bmean := make([]float64, d)
+	for i := 0; i < m; i++ {
+		for j := 0; j < d; j++ {
+			bmean[j] += batch.At(i, j)
+		}
+	}
+	floats.Scale(1/float64(m), bmean)
+
+	rows := m
+	if c.vecs != nil {
+		rows += len(c.values) + 1
+	}
+	stack := mat.NewDense(rows, d, nil)
+	r := 0
+	if c.vecs != nil {
+		for i, s := range c.values {
+			floats.ScaleTo(stack.RawRowView(r), s, c.vecs.RawRowView(i))
+			r++
+		}
+	}
+	for i := 0; i < m; i++ {
+		row := stack.RawRowView(r)
+		mat.Row(row, i, batch)
+		floats.Sub(row, bmean)
+		r++
+	}
+	total := c.n + float64(m)
+	if c.vecs != nil {
+		row := stack.RawRowView(r)
+		floats.SubTo(row, c.mean, bmean)
+		floats.Scale(math.Sqrt(c.n*float64(m)/total), row)
+	}
+
+	var svd mat.SVD
+	if !svd.Factorize(stack, mat.SVDThin) {
+		return false
+	}
+	var v mat.Dense
+	svd.VTo(&v)
+	vals := svd.Values(nil)
+	k := c.k
+	if k > len(vals) {
+		k = len(vals)
+	}
+	c.vecs = mat.DenseCopyOf(v.Slice(0, d, 0, k).T())
+	c.values = vals[:k]
+	for j := range c.mean {
+		c.mean[j] = (c.n*c.mean[j] + float64(m)*bmean[j]) / total
+	}
+	c.n = total
+	c.ok = true
+	return true
+}
+
+// Mean returns the mean of the observations. If dst is not nil it is used to
+// store the mean and returned, in which case it must have length d.
+func (c *IncrementalPC) Mean(dst []float64) []float64 {
+	if dst == nil {
+		dst = make([]float64, c.d)
+	} else if len(dst) != c.d {
+		panic("stat: length of slice does not match analysis")
+	}
+	copy(dst, c.mean)
+	return dst
+}
+
+// VectorsTo returns the component direction vectors in the columns of a d×k
+// matrix, where k is the number of retained components, or fewer if fewer
+// observations have been seen.
+//
+// If dst is empty, VectorsTo will resize dst to be d×k. When dst is
+// non-empty, VectorsTo will panic if dst is not d×k. VectorsTo will also
+// panic if no successful update has been made.
+func (c *IncrementalPC) VectorsTo(dst *mat.Dense) {
+	if !c.ok {
+		panic("stat: use of empty incremental principal components analysis")
+	}
+	copyTo(dst, c.vecs.T())
+}
+
+// VarsTo returns the variances of the principal component scores in
+// descending order. If dst is not nil it is used to store the variances and
+// returned. VarsTo will panic if no successful update has been made or dst is
+// not nil and its length does not match the number of components.
+func (c *IncrementalPC) VarsTo(dst []float64) []float64 {
+	if !c.ok {
+		panic("stat: use of empty incremental principal components analysis")
+	}
+	if dst == nil {
+		dst = make([]float64, len(c.values))
+	} else if len(dst) != len(c.values) {
+		panic("stat: length of slice does not match analysis")
+	}
+	for i, v := range c.values {
+		dst[i] = v * v / (c.n - 1)
+	}
+	return dst
+}
@@ -0,0 +1,116 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestIncrementalPC(t *testing.T) {
+	const n, d = 100, 5
+	rnd := rand.New(rand.NewSource(1))
+	x := mat.NewDense(n, d, nil)
+	for i := 0; i < n; i++ {
+		for j := 0; j < d; j++ {
+			x.Set(i, j, 10+rnd.NormFloat64()*float64(d-j))
+		}
+	}
+	var pc PC
+	if !pc.PrincipalComponents(x, nil) {
+		t.Fatal("unexpected PCA failure")
+	}
+	var wantVecs mat.Dense
+	pc.VectorsTo(&wantVecs)
+	wantVars := pc.VarsTo(nil)
+
+	// When all components are retained the incremental
+	// analysis is exact regardless of the batch sizes.
+	for _, sizes := range [][]int{{100}, {50, 50}, {1, 9, 30, 60}, {7, 13, 80}} {
+		ipc := NewIncrementalPC(d, d)
+		var start int
+		for _, size := range sizes {
+			if !ipc.Update(x.Slice(start, start+size, 0, d)) {
+				t.Fatalf("unexpected failure for batches %v", sizes)
+			}
+			start += size
+		}
+		gotMean := ipc.Mean(nil)
+		for j, m := range gotMean {
+			if want := Mean(mat.Col(nil, j, x), nil); !floats.EqualWithinAbsOrRel(m, want, 1e-12, 1e-12) {
+				t.Errorf("unexpected mean for batches %v: got:%v want:%v", sizes, m, want)
+			}
+		}
+		var gotVecs mat.Dense
+		ipc.VectorsTo(&gotVecs)
+		if !equalColsUpToSign(&gotVecs, &wantVecs, 1e-10) {
+			t.Errorf("unexpected vectors for batches %v", sizes)
+		}
+		if got := ipc.VarsTo(nil); !approxEqual(got, wantVars, 1e-10) {
+			t.Errorf("unexpected variances for batches %v: got:%v want:%v", sizes, got, wantVars)
+		}
+	}
+}
+
+func TestRandomizedPC(t *testing.T) {
+	const n, d, rank = 200, 30, 3
+	rnd := rand.New(rand.NewSource(1))
+
+	// Low rank data with a little noise.
+	f := mat.NewDense(n, rank, nil)
+	l := mat.NewDense(rank, d, nil)
+	for i := 0; i < n; i++ {
+		for j := 0; j < rank; j++ {
+			f.Set(i, j, rnd.NormFloat64()*float64(rank-j)*3)
+		}
+	}
+	for i := 0; i < rank; i++ {
+		for j := 0; j < d; j++ {
+			l.Set(i, j, rnd.NormFloat64())
+		}
+	}
+	var x mat.Dense
+	x.Mul(f, l)
+	for i := 0; i < n; i++ {
+		for j := 0; j < d; j++ {
+			x.Set(i, j, x.At(i, j)+1e-3*rnd.NormFloat64())
+		}
+	}
+	weights := make([]float64, n)
+	for i := range weights {
+		weights[i] = 1 + rnd.Float64()
+	}
+
+	for _, w := range [][]float64{nil, weights} {
+		var pc PC
+		if !pc.PrincipalComponents(&x, w) {
+			t.Fatal("unexpected PCA failure")
+		}
+		var wantVecs mat.Dense
+		pc.VectorsTo(&wantVecs)
+		wantVars := pc.VarsTo(nil)[:rank]
+
+		rpc := RandomizedPC{PowerIters: 2, Src: rand.NewSource(1)}
+		if !rpc.PrincipalComponents(&x, rank, w) {
+			t.Fatal("unexpected failure")
+		}
+		var gotVecs mat.Dense
+		rpc.VectorsTo(&gotVecs)
+		if !equalColsUpToSign(&gotVecs, wantVecs.Slice(0, d, 0, rank), 1e-6) {
+			t.Errorf("unexpected vectors with weights=%t", w != nil)
+		}
+		gotVars := rpc.VarsTo(nil)
+		for i := range gotVars {
+			if !floats.EqualWithinRel(gotVars[i], wantVars[i], 1e-8) {
+				t.Errorf("unexpected variances with weights=%t: got:%v want:%v", w != nil, gotVars, wantVars)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// RandomizedPC is a type for computing approximations to the leading
+// principal components of a matrix using a randomized range finder, as
+// described in Halko, Martinsson and Tropp, "Finding structure with
+// randomness: probabilistic algorithms for constructing approximate matrix
+// decompositions", SIAM Review 53 (2011) 217-288. The data are projected onto
+// a random subspace refined by power iterations, and the principal components
+// are computed from the singular value decomposition of the small projected
+// matrix. The results of the analysis are only valid if the call to
+// PrincipalComponents was successful.
+//
+// The fields of RandomizedPC configure the analysis and must be set before
+// calling PrincipalComponents.
+type RandomizedPC struct {
+	// Oversample is the number of additional
+	// random directions used to capture the
+	// range of the data. If Oversample is zero,
+	// a default of 10 is used.
+	Oversample int
+
+	// PowerIters is the number of power
+	// iterations used to refine the range. If
+	// PowerIters is zero, a default of 2 is used.
+	// Negative values specify no iterations.
+	PowerIters int
+
+	// Src is the source of random numbers. If
+	// Src is nil, the global source is used.
+	Src rand.Source
+
+	d, k   int
+	vecs   *mat.Dense
+	values []float64
+	sumW   float64
+	ok     bool
+}
+
+// PrincipalComponents performs an approximate weighted principal components
+// analysis with k components on the matrix of the input data which is
+// represented as an n×d matrix a where each row is an observation and each
+// column is a variable.
+//
+// PrincipalComponents centers the variables but does not scale the variance.
+//
+// The weights slice is used to weight the observations. If weights is nil,
+// each weight is considered to have a value of one, otherwise the length of
+// weights must match the number of observations or PrincipalComponents will
+// panic.
+//
+// PrincipalComponents returns whether the analysis was successful.
+func (c *RandomizedPC) PrincipalComponents(a mat.Matrix, k int, weights []float64) (ok bool) {
+	n, d := a.Dims()
+	if weights != nil && len(weights) != n {
+		panic("stat: len(weights) != observations")
+	}
+	if k < 1 || min(n, d) < k {
+		panic("stat: bad number of components")
+	}
+	c.ok = false
+	c.d, c.k = d, k
+	c.sumW = float64(n)
+	if weights != nil {
+		c.sumW = floats.Sum(weights)
+	}
+
+	x := mat.NewDense(n, d, nil)
+	col := make([]float64, n)
+	for j := 0; j < d; j++ {
+		mat.Col(col, j, a)
+		floats.AddConst(-Mean(col, weights), col)
+		x.SetCol(j, col)
+	}
+	for i, w := range weights {
+		floats.Scale(math.Sqrt(w), x.RawRowView(i))
+	}
+
+	over := c.Oversample
+	if over == 0 {
+		over = 10
+	}
+	l := min(k+over, min(n, d))
+	iters := c.PowerIters
+	if iters == 0 {
+		iters = 2
+	}
+
+	norm := rand.NormFloat64
+	if c.Src != nil {
+		norm = rand.New(c.Src).NormFloat64
+	}
+	omega := mat.NewDense(d, l, nil)
+	for i := 0; i < d; i++ {
+		for j := 0; j < l; j++ {
+			omega.Set(i, j, norm())
+		}
+	}
+	var y, z mat.Dense
+	y.Mul(x, omega)
+	q, ok := orthonormalBasis(&y)
+	if !ok {
+		return false
+	}
+	for it := 0; it < iters; it++ {
+		z.Mul(x.T(), q)
+		p, ok := orthonormalBasis(&z)
+		if !ok {
+			return false
+		}
+		y.Mul(x, p)
+		q, ok = orthonormalBasis(&y)
+		if !ok {
+			return false
+		}
+	}
+
+	var b mat.Dense
+	b.Mul(q.T(), x)
+	var svd mat.SVD
+	if !svd.Factorize(&b, mat.SVDThin) {
+		return false
+	}
+	var v mat.Dense
+	svd.VTo(&v)
+	c.vecs = mat.DenseCopyOf(v.Slice(0, d, 0, k))
+	c.values = svd.Values(nil)[:k]
+	c.ok = true
+	return true
+}
+
+// orthonormalBasis returns an orthonormal basis for the column space of y.
+func orthonormalBasis(y *mat.Dense) (*mat.Dense, bool) {
+	var svd mat.SVD
+	if !svd.Factorize(y, mat.SVDThin) {
+		return nil, false
+	}
+	var u mat.Dense
+	svd.UTo(&u)
+	return &u, true
+}
+
+// VectorsTo returns the component direction vectors of a successful analysis
+// in the columns of a d×k matrix.
+//
+// If dst is empty, VectorsTo will resize dst to be d×k. When dst is
+// non-empty, VectorsTo will panic if dst is not d×k. VectorsTo will also
+// panic if the receiver does not contain a successful RandomizedPC.
+func (c *RandomizedPC) VectorsTo(dst *mat.Dense) {
+	if !c.ok {
+		panic("stat: use of unsuccessful principal components analysis")
+	}
+	copyTo(dst, c.vecs)
+}
+
+// VarsTo returns the column variances of the principal component scores in
+// descending order. If dst is not nil it is used to store the variances and
+// returned. VarsTo will panic if the receiver does not contain a successful
+// RandomizedPC or dst is not nil and the length of dst is not k.
+func (c *RandomizedPC) VarsTo(dst []float64) []float64 {
+	if !c.ok {
+		panic("stat: use of unsuccessful principal components analysis")
+	}
+	if dst == nil {
+		dst = make([]float64, c.k)
+	} else if len(dst) != c.k {
+		panic("stat: length of slice does not match analysis")
+	}
+	for i, v := range c.values {
+		dst[i] = v * v / (c.sumW - 1)
+	}
+	return dst
+}
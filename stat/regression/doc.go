@@ -0,0 +1,13 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package regression provides multiple linear regression and generalized
+// linear models with statistical inference on the fitted coefficients.
+//
+// Design matrices hold one observation per row and one predictor per column.
+// Unless a fit is forced through the origin an intercept term is added to the
+// model, and the first element of the fitted coefficients is the intercept.
+// Weights are frequency weights, so a weight of 2 is equivalent to the
+// observation appearing twice in the data.
+package regression // import "gonum.org/v1/gonum/stat/regression"
@@ -0,0 +1,153 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package regression
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// Link is the link function of a generalized linear model, relating the mean
+// of the response μ to the linear predictor η.
+type Link interface {
+	// Link returns the linear predictor η = g(μ).
+	Link(mu float64) float64
+	// Inverse returns the mean μ = g^-1(η).
+	Inverse(eta float64) float64
+	// Deriv returns the derivative of the link dη/dμ at μ.
+	Deriv(mu float64) float64
+}
+
+// Identity is the identity link function, η = μ.
+type Identity struct{}
+
+func (Identity) Link(mu float64) float64     { return mu }
+func (Identity) Inverse(eta float64) float64 { return eta }
+func (Identity) Deriv(mu float64) float64    { return 1 }
+
+// Log is the log link function, η = log(μ).
+type Log struct{}
+
+func (Log) Link(mu float64) float64     { return math.Log(mu) }
+func (Log) Inverse(eta float64) float64 { return math.Exp(eta) }
+func (Log) Deriv(mu float64) float64    { return 1 / mu }
+
+// eps is the machine epsilon. The inverses of the links for probabilities
+// are clamped to [eps, 1-eps] so that the variance and link derivative
+// remain finite during fitting.
+const eps = 1.0 / (1 << 52)
+
+// clampProb returns p clamped to [eps, 1-eps].
+func clampProb(p float64) float64 {
+	return math.Max(eps, math.Min(p, 1-eps))
+}
+
+// Logit is the logit link function, η = log(μ/(1-μ)).
+type Logit struct{}
+
+func (Logit) Link(mu float64) float64 { return math.Log(mu / (1 - mu)) }
+func (Logit) Inverse(eta float64) float64 {
+	if eta < 0 {
+		e := math.Exp(eta)
+		return clampProb(e / (1 + e))
+	}
+	return clampProb(1 / (1 + math.Exp(-eta)))
+}
+func (Logit) Deriv(mu float64) float64 { return 1 / (mu * (1 - mu)) }
+
+// Probit is the probit link function, η = Φ^-1(μ) where Φ is the
+// cumulative distribution function of the standard normal distribution.
+type Probit struct{}
+
+func (Probit) Link(mu float64) float64     { return distuv.UnitNormal.Quantile(mu) }
+func (Probit) Inverse(eta float64) float64 { return clampProb(distuv.UnitNormal.CDF(eta)) }
+func (Probit) Deriv(mu float64) float64 {
+	return 1 / distuv.UnitNormal.Prob(distuv.UnitNormal.Quantile(mu))
+}
+
+// CLogLog is the complementary log-log link function, η = log(-log(1-μ)).
+type CLogLog struct{}
+
+func (CLogLog) Link(mu float64) float64     { return math.Log(-math.Log1p(-mu)) }
+func (CLogLog) Inverse(eta float64) float64 { return clampProb(-math.Expm1(-math.Exp(eta))) }
+func (CLogLog) Deriv(mu float64) float64    { return -1 / ((1 - mu) * math.Log1p(-mu)) }
+
+// Reciprocal is the reciprocal link function, η = 1/μ.
+type Reciprocal struct{}
+
+func (Reciprocal) Link(mu float64) float64     { return 1 / mu }
+func (Reciprocal) Inverse(eta float64) float64 { return 1 / eta }
+func (Reciprocal) Deriv(mu float64) float64    { return -1 / (mu * mu) }
+
+// Family is the error distribution of a generalized linear model.
+type Family interface {
+	// Link returns the canonical link function of the family.
+	Link() Link
+	// Variance returns the variance function V(μ) of the family.
+	Variance(mu float64) float64
+	// Deviance returns the unit deviance of the observation y at the mean μ.
+	Deviance(y, mu float64) float64
+	// Initial returns the starting value of the mean for the observation y.
+	Initial(y float64) float64
+	// Dispersion returns the dispersion parameter of the family, or NaN
+	// if the dispersion is estimated from the data.
+	Dispersion() float64
+}
+
+// Gaussian is the normal family with variance function V(μ) = 1.
+// The canonical link is Identity.
+type Gaussian struct{}
+
+func (Gaussian) Link() Link                     { return Identity{} }
+func (Gaussian) Variance(mu float64) float64    { return 1 }
+func (Gaussian) Deviance(y, mu float64) float64 { return (y - mu) * (y - mu) }
+func (Gaussian) Initial(y float64) float64      { return y }
+func (Gaussian) Dispersion() float64            { return math.NaN() }
+
+// Binomial is the binomial family for proportions in [0, 1] with variance
+// function V(μ) = μ(1-μ). The observation weights are the number of trials.
+// The canonical link is Logit.
+type Binomial struct{}
+
+func (Binomial) Link() Link                  { return Logit{} }
+func (Binomial) Variance(mu float64) float64 { return mu * (1 - mu) }
+func (Binomial) Deviance(y, mu float64) float64 {
+	return 2 * (xlogy(y, y/mu) + xlogy(1-y, (1-y)/(1-mu)))
+}
+func (Binomial) Initial(y float64) float64 { return (y + 0.5) / 2 }
+func (Binomial) Dispersion() float64       { return 1 }
+
+// Poisson is the Poisson family for counts with variance function V(μ) = μ.
+// The canonical link is Log.
+type Poisson struct{}
+
+func (Poisson) Link() Link                  { return Log{} }
+func (Poisson) Variance(mu float64) float64 { return mu }
+func (Poisson) Deviance(y, mu float64) float64 {
+	return 2 * (xlogy(y, y/mu) - (y - mu))
+}
+func (Poisson) Initial(y float64) float64 { return y + 0.1 }
+func (Poisson) Dispersion() float64       { return 1 }
+
+// Gamma is the gamma family for positive responses with variance function
+// V(μ) = μ². The canonical link is Reciprocal.
+type Gamma struct{}
+
+func (Gamma) Link() Link                  { return Reciprocal{} }
+func (Gamma) Variance(mu float64) float64 { return mu * mu }
+func (Gamma) Deviance(y, mu float64) float64 {
+	return -2 * (math.Log(y/mu) - (y-mu)/mu)
+}
+func (Gamma) Initial(y float64) float64 { return y }
+func (Gamma) Dispersion() float64       { return math.NaN() }
+
+// xlogy returns x*log(y), with the convention that it is zero when x is zero.
+func xlogy(x, y float64) float64 {
+	if x == 0 {
+		return 0
+	}
+	return x * math.Log(y)
+}
@@ -0,0 +1,254 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package regression
+
+import (
+	"errors"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+const (
+	glmMaxIter = 25
+	glmTol     = 1e-8
+)
+
+// ErrNotConverged is returned by FitGLM when iteratively reweighted least
+// squares does not converge.
+var ErrNotConverged = errors.New("regression: iteratively reweighted least squares did not converge")
+
+// GLM is a generalized linear model fitted by maximum likelihood.
+type GLM struct {
+	// Family and Link are the error distribution and
+	// link function of the model.
+	Family Family
+	Link   Link
+
+	// Coef holds the fitted coefficients. If the model
+	// has an intercept it is the first coefficient.
+	Coef []float64
+
+	// StdErr, Stat and PValue hold the standard error,
+	// Wald statistic and two-sided p-value of each
+	// coefficient for the null hypothesis that it is zero.
+	// When the dispersion is estimated the statistic is
+	// compared to Student's t distribution with DoF degrees
+	// of freedom, otherwise it is compared to the standard
+	// normal distribution.
+	StdErr []float64
+	Stat   []float64
+	PValue []float64
+
+	// Cov is the estimated covariance matrix of Coef.
+	Cov *mat.SymDense
+
+	// Dispersion is the dispersion parameter of the model,
+	// estimated by the Pearson χ² statistic when not fixed
+	// by the family.
+	Dispersion float64
+
+	// Deviance and NullDeviance are the deviance of the
+	// fitted model and of the model with only an intercept,
+	// or with a zero linear predictor if the model has no
+	// intercept.
+	Deviance     float64
+	NullDeviance float64
+
+	// DoF is the residual degrees of freedom.
+	DoF float64
+
+	// Iterations is the number of iterations of IRLS.
+	Iterations int
+
+	origin bool
+}
+
+// FitGLM fits the generalized linear model
+//  g(E[y]) = X*β
+// for the given family and link function g by iteratively reweighted least
+// squares. If link is nil the canonical link of the family is used. If origin
+// is false, an intercept column is added to the design matrix.
+//
+// If weights is nil then all the weights are 1. If weights is not nil, then
+// len(weights) must equal len(y). For the Binomial family y holds the observed
+// proportions and weights the number of trials. FitGLM panics if the number of
+// rows of x is not equal to len(y), or if the total weight does not exceed the
+// number of coefficients.
+//
+// If the iteration does not converge FitGLM returns the last iterate together
+// with ErrNotConverged. If fewer observations have non-zero weight than there
+// are coefficients, FitGLM returns ErrTooFewObservations. If the weighted
+// design matrix becomes near rank deficient the result is returned with a
+// mat.Condition error.
+func FitGLM(x mat.Matrix, y, weights []float64, family Family, link Link, origin bool) (*GLM, error) {
+	n, _ := x.Dims()
+	if n != len(y) {
+		panic(badLength)
+	}
+	checkWeights(y, weights)
+	if link == nil {
+		link = family.Link()
+	}
+	design := designMatrix(x, origin)
+	_, p := design.Dims()
+	sumW := sumWeights(n, weights)
+	if sumW <= float64(p) {
+		panic(badShape)
+	}
+
+	mu := make([]float64, n)
+	eta := make([]float64, n)
+	for i, v := range y {
+		mu[i] = family.Initial(v)
+		eta[i] = link.Link(mu[i])
+	}
+	dev := deviance(family, y, mu, weights)
+
+	z := make([]float64, n)
+	w := make([]float64, n)
+	var (
+		coef []float64
+		err  error
+		iter int
+	)
+	converged := false
+	for iter < glmMaxIter {
+		iter++
+		for i := range y {
+			d := link.Deriv(mu[i])
+			z[i] = eta[i] + (y[i]-mu[i])*d
+			w[i] = weightAt(weights, i) / (family.Variance(mu[i]) * d * d)
+		}
+		_, coef, err = weightedLeastSquares(design, z, w)
+		if err != nil {
+			if _, ok := err.(mat.Condition); !ok {
+				return nil, err
+			}
+		}
+		c := mat.NewVecDense(p, coef)
+		for i := range y {
+			eta[i] = mat.Dot(design.RowView(i), c)
+			mu[i] = link.Inverse(eta[i])
+		}
+		devOld := dev
+		dev = deviance(family, y, mu, weights)
+		if math.Abs(dev-devOld)/(math.Abs(dev)+0.1) < glmTol {
+			converged = true
+			break
+		}
+	}
+
+	dof := sumW - float64(p)
+	phi := family.Dispersion()
+	estimated := math.IsNaN(phi)
+	if estimated {
+		var pearson float64
+		for i := range y {
+			r := y[i] - mu[i]
+			pearson += weightAt(weights, i) * r * r / family.Variance(mu[i])
+		}
+		phi = pearson / dof
+	}
+	// Compute the covariance from the working weights at the final estimate.
+	for i := range y {
+		d := link.Deriv(mu[i])
+		w[i] = weightAt(weights, i) / (family.Variance(mu[i]) * d * d)
+	}
+	sw := make([]float64, n)
+	for i := range w {
+		sw[i] = math.Sqrt(w[i])
+	}
+	var a mat.Dense
+	a.Apply(func(i, j int, v float64) float64 { return sw[i] * v }, design)
+	var qr mat.QR
+	qr.Factorize(&a)
+	cov := qrInverse(&qr, p)
+	cov.ScaleSym(phi, cov)
+
+	var nullMu float64
+	if origin {
+		nullMu = link.Inverse(0)
+	} else {
+		nullMu = stat.Mean(y, weights)
+	}
+	var nullDev float64
+	for i, v := range y {
+		nullDev += weightAt(weights, i) * family.Deviance(v, nullMu)
+	}
+
+	g := &GLM{
+		Family:       family,
+		Link:         link,
+		Coef:         coef,
+		StdErr:       make([]float64, p),
+		Stat:         make([]float64, p),
+		PValue:       make([]float64, p),
+		Cov:          cov,
+		Dispersion:   phi,
+		Deviance:     dev,
+		NullDeviance: nullDev,
+		DoF:          dof,
+		Iterations:   iter,
+		origin:       origin,
+	}
+	for i, c := range coef {
+		g.StdErr[i] = math.Sqrt(cov.At(i, i))
+		g.Stat[i] = c / g.StdErr[i]
+		if estimated {
+			g.PValue[i] = 2 * distuv.StudentsT{Mu: 0, Sigma: 1, Nu: dof}.Survival(math.Abs(g.Stat[i]))
+		} else {
+			g.PValue[i] = 2 * distuv.UnitNormal.Survival(math.Abs(g.Stat[i]))
+		}
+	}
+	if !converged {
+		return g, ErrNotConverged
+	}
+	return g, err
+}
+
+// Predict returns the predicted mean response at the predictors x. The length
+// of x must be the number of predictors in the model, not including the
+// intercept.
+func (g *GLM) Predict(x []float64) float64 {
+	return g.Link.Inverse(predict(g.Coef, x, g.origin))
+}
+
+// ConfidenceInterval returns the Wald confidence interval for the mean response
+// at the predictors x with the given confidence level. The interval is computed
+// on the scale of the linear predictor and transformed by the inverse link.
+// The length of x must be the number of predictors in the model, not including
+// the intercept.
+func (g *GLM) ConfidenceInterval(x []float64, level float64) (lo, hi float64) {
+	if !(0 < level && level < 1) {
+		panic(badLevel)
+	}
+	v := designRow(x, len(g.Coef), g.origin)
+	se := math.Sqrt(mat.Inner(v, g.Cov, v))
+	var q float64
+	if math.IsNaN(g.Family.Dispersion()) {
+		q = distuv.StudentsT{Mu: 0, Sigma: 1, Nu: g.DoF}.Quantile(0.5 + level/2)
+	} else {
+		q = distuv.UnitNormal.Quantile(0.5 + level/2)
+	}
+	eta := predict(g.Coef, x, g.origin)
+	lo = g.Link.Inverse(eta - q*se)
+	hi = g.Link.Inverse(eta + q*se)
+	if lo > hi {
+		lo, hi = hi, lo
+	}
+	return lo, hi
+}
+
+// deviance returns the weighted deviance of the observations y at the means mu.
+func deviance(family Family, y, mu, weights []float64) float64 {
+	var dev float64
+	for i, v := range y {
+		dev += weightAt(weights, i) * family.Deviance(v, mu[i])
+	}
+	return dev
+}
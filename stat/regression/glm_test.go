@@ -0,0 +1,249 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package regression
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+func TestFitGLMDobson(t *testing.T) {
+	// Dobson (1990) randomized controlled trial from the R glm documentation.
+	counts := []float64{18, 17, 15, 20, 10, 20, 25, 13, 12}
+	x := mat.NewDense(9, 4, nil)
+	for i := 0; i < 9; i++ {
+		outcome := i % 3
+		treatment := i / 3
+		if outcome > 0 {
+			x.Set(i, outcome-1, 1)
+		}
+		if treatment > 0 {
+			x.Set(i, 1+treatment, 1)
+		}
+	}
+	g, err := FitGLM(x, counts, nil, Poisson{}, nil, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Values from R summary(glm(counts ~ outcome + treatment, family = poisson())).
+	wantCoef := []float64{math.Log(21), math.Log(40.0 / 63), math.Log(47.0 / 63), 0, 0}
+	if !floats.EqualApprox(g.Coef, wantCoef, 1e-8) {
+		t.Errorf("coefficient mismatch: got %v, want %v", g.Coef, wantCoef)
+	}
+	wantSE := []float64{0.1709, 0.2022, 0.1927, 0.2000, 0.2000}
+	if !floats.EqualApprox(g.StdErr, wantSE, 1e-4) {
+		t.Errorf("standard error mismatch: got %v, want %v", g.StdErr, wantSE)
+	}
+	if !scalar.EqualWithinAbs(g.Deviance, 5.1291, 1e-4) {
+		t.Errorf("deviance mismatch: got %v, want 5.1291", g.Deviance)
+	}
+	if !scalar.EqualWithinAbs(g.NullDeviance, 10.5814, 1e-4) {
+		t.Errorf("null deviance mismatch: got %v, want 10.5814", g.NullDeviance)
+	}
+	if g.DoF != 4 || g.Dispersion != 1 {
+		t.Errorf("unexpected dof or dispersion: got %v, %v", g.DoF, g.Dispersion)
+	}
+}
+
+func TestFitGLMGaussian(t *testing.T) {
+	x := mat.NewDense(len(cars.speed), 1, cars.speed)
+	l, err := FitLinear(x, cars.dist, nil, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	g, err := FitGLM(x, cars.dist, nil, Gaussian{}, nil, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !floats.EqualApprox(g.Coef, l.Coef, 1e-10) || !floats.EqualApprox(g.StdErr, l.StdErr, 1e-10) ||
+		!floats.EqualApprox(g.PValue, l.PValue, 1e-10) {
+		t.Errorf("Gaussian GLM does not match linear fit: got %v %v, want %v %v", g.Coef, g.StdErr, l.Coef, l.StdErr)
+	}
+	if !scalar.EqualWithinAbsOrRel(g.Dispersion, l.Sigma*l.Sigma, 1e-10, 1e-10) {
+		t.Errorf("dispersion mismatch: got %v, want %v", g.Dispersion, l.Sigma*l.Sigma)
+	}
+	lo, hi := g.ConfidenceInterval([]float64{21}, 0.9)
+	wantLo, wantHi := l.ConfidenceInterval([]float64{21}, 0.9)
+	if !scalar.EqualWithinAbsOrRel(lo, wantLo, 1e-10, 1e-10) || !scalar.EqualWithinAbsOrRel(hi, wantHi, 1e-10, 1e-10) {
+		t.Errorf("confidence interval mismatch: got [%v, %v], want [%v, %v]", lo, hi, wantLo, wantHi)
+	}
+}
+
+func TestFitGLMRecovery(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	src := rand.NewSource(2)
+	const n = 2000
+	beta := []float64{0.5, -1, 0.3}
+	for _, test := range []struct {
+		name     string
+		family   Family
+		link     Link
+		generate func(mu float64) float64
+	}{
+		{
+			name:   "logistic",
+			family: Binomial{},
+			generate: func(mu float64) float64 {
+				if rnd.Float64() < mu {
+					return 1
+				}
+				return 0
+			},
+		},
+		{
+			name:   "probit",
+			family: Binomial{},
+			link:   Probit{},
+			generate: func(mu float64) float64 {
+				if rnd.Float64() < mu {
+					return 1
+				}
+				return 0
+			},
+		},
+		{
+			name:   "cloglog",
+			family: Binomial{},
+			link:   CLogLog{},
+			generate: func(mu float64) float64 {
+				if rnd.Float64() < mu {
+					return 1
+				}
+				return 0
+			},
+		},
+		{
+			name:   "Poisson",
+			family: Poisson{},
+			generate: func(mu float64) float64 {
+				return distuv.Poisson{Lambda: mu, Src: src}.Rand()
+			},
+		},
+		{
+			name:   "Gamma log",
+			family: Gamma{},
+			link:   Log{},
+			generate: func(mu float64) float64 {
+				const shape = 5
+				return distuv.Gamma{Alpha: shape, Beta: shape / mu, Src: src}.Rand()
+			},
+		},
+	} {
+		link := test.link
+		if link == nil {
+			link = test.family.Link()
+		}
+		x := mat.NewDense(n, 2, nil)
+		y := make([]float64, n)
+		for i := 0; i < n; i++ {
+			x.Set(i, 0, rnd.NormFloat64())
+			x.Set(i, 1, rnd.NormFloat64())
+			eta := beta[0] + beta[1]*x.At(i, 0) + beta[2]*x.At(i, 1)
+			y[i] = test.generate(link.Inverse(eta))
+		}
+		g, err := FitGLM(x, y, nil, test.family, test.link, false)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+		for j, b := range beta {
+			if math.Abs(g.Coef[j]-b) > 4*g.StdErr[j] {
+				t.Errorf("%s: coefficient %d not recovered: got %v±%v, want %v", test.name, j, g.Coef[j], g.StdErr[j], b)
+			}
+		}
+		if g.Deviance > g.NullDeviance {
+			t.Errorf("%s: deviance larger than null deviance: %v > %v", test.name, g.Deviance, g.NullDeviance)
+		}
+
+		if test.link != nil {
+			continue
+		}
+		// For canonical links the score equations are Xᵀ(y - μ) = 0.
+		d := designMatrix(x, false)
+		score := make([]float64, 3)
+		for i := 0; i < n; i++ {
+			r := y[i] - g.Predict(x.RawRowView(i))
+			for j := range score {
+				score[j] += d.At(i, j) * r
+			}
+		}
+		for j, s := range score {
+			if math.Abs(s) > 1e-6 {
+				t.Errorf("%s: score equation %d not satisfied: %v", test.name, j, s)
+			}
+		}
+	}
+}
+
+func TestFitGLMWeights(t *testing.T) {
+	// Binomial proportions with trial counts as weights match
+	// the expanded binary data.
+	dose := []float64{1, 2, 3, 4, 5}
+	trials := []float64{10, 12, 8, 10, 9}
+	successes := []float64{1, 4, 4, 7, 8}
+	prop := make([]float64, len(dose))
+	for i := range prop {
+		prop[i] = successes[i] / trials[i]
+	}
+	g, err := FitGLM(mat.NewDense(len(dose), 1, dose), prop, trials, Binomial{}, nil, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var xs, ys []float64
+	for i := range dose {
+		for k := 0; k < int(trials[i]); k++ {
+			xs = append(xs, dose[i])
+			if k < int(successes[i]) {
+				ys = append(ys, 1)
+			} else {
+				ys = append(ys, 0)
+			}
+		}
+	}
+	want, err := FitGLM(mat.NewDense(len(xs), 1, xs), ys, nil, Binomial{}, nil, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !floats.EqualApprox(g.Coef, want.Coef, 1e-6) || !floats.EqualApprox(g.StdErr, want.StdErr, 1e-6) {
+		t.Errorf("grouped binomial fit mismatch: got %v %v, want %v %v", g.Coef, g.StdErr, want.Coef, want.StdErr)
+	}
+	lo, hi := g.ConfidenceInterval([]float64{3}, 0.95)
+	if p := g.Predict([]float64{3}); !(0 < lo && lo < p && p < hi && hi < 1) {
+		t.Errorf("invalid confidence interval for probability %v: [%v, %v]", p, lo, hi)
+	}
+}
+
+func TestLinks(t *testing.T) {
+	const h = 1e-6
+	for _, test := range []struct {
+		name string
+		link Link
+		mu   []float64
+	}{
+		{name: "Identity", link: Identity{}, mu: []float64{-2, 0, 3}},
+		{name: "Log", link: Log{}, mu: []float64{0.1, 1, 5}},
+		{name: "Logit", link: Logit{}, mu: []float64{0.1, 0.5, 0.9}},
+		{name: "Probit", link: Probit{}, mu: []float64{0.1, 0.5, 0.9}},
+		{name: "CLogLog", link: CLogLog{}, mu: []float64{0.1, 0.5, 0.9}},
+		{name: "Reciprocal", link: Reciprocal{}, mu: []float64{0.5, 1, 4}},
+	} {
+		for _, mu := range test.mu {
+			eta := test.link.Link(mu)
+			if got := test.link.Inverse(eta); !scalar.EqualWithinAbsOrRel(got, mu, 1e-12, 1e-12) {
+				t.Errorf("%s: inverse mismatch at %v: got %v", test.name, mu, got)
+			}
+			deriv := (test.link.Link(mu+h) - test.link.Link(mu-h)) / (2 * h)
+			if got := test.link.Deriv(mu); !scalar.EqualWithinAbsOrRel(got, deriv, 1e-6, 1e-6) {
+				t.Errorf("%s: derivative mismatch at %v: got %v, want %v", test.name, mu, got, deriv)
+			}
+		}
+	}
+}
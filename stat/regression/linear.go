@@ -0,0 +1,336 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package regression
+
+import (
+	"errors"
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/mathext"
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+const (
+	badLength   = "regression: slice length mismatch"
+	badWeights  = "regression: negative weight"
+	badShape    = "regression: too few observations"
+	badLevel    = "regression: confidence level not in (0, 1)"
+	badNumPreds = "regression: predictor length mismatch"
+)
+
+// ErrTooFewObservations is returned when fewer observations have non-zero
+// weight than there are coefficients to fit.
+var ErrTooFewObservations = errors.New("regression: too few observations with non-zero weight")
+
+// Linear is a multiple linear regression model fitted by least squares.
+type Linear struct {
+	// Coef holds the fitted coefficients. If the model
+	// has an intercept it is the first coefficient.
+	Coef []float64
+
+	// StdErr, TStat and PValue hold the standard error,
+	// t statistic and two-sided p-value of each coefficient
+	// for the null hypothesis that it is zero.
+	StdErr []float64
+	TStat  []float64
+	PValue []float64
+
+	// Cov is the estimated covariance matrix of Coef.
+	Cov *mat.SymDense
+
+	// Sigma is the estimated standard deviation of the residuals.
+	Sigma float64
+
+	// DoF is the residual degrees of freedom.
+	DoF float64
+
+	// RSquared and AdjRSquared are the coefficient of
+	// determination and its value adjusted for the number
+	// of predictors. When the model has no intercept they
+	// are computed relative to a model of zero response.
+	RSquared    float64
+	AdjRSquared float64
+
+	// FStat and FPValue are the F statistic and its p-value
+	// for the null hypothesis that all the coefficients,
+	// other than the intercept, are zero.
+	FStat   float64
+	FPValue float64
+
+	origin bool
+}
+
+// FitLinear fits the linear model
+//  y = X*β + ε
+// by weighted least squares, minimizing
+//  \sum_i w[i]*(y[i] - x_i*β)^2
+// where x_i is the i^th row of x. If origin is false, an intercept column
+// is added to the design matrix. The coefficients are computed from the QR
+// decomposition of the weighted design matrix.
+//
+// If weights is nil then all the weights are 1. If weights is not nil, then
+// len(weights) must equal len(y). FitLinear panics if the number of rows of x
+// is not equal to len(y), or if the total weight does not exceed the number of
+// coefficients. If fewer observations have non-zero weight than there are
+// coefficients, FitLinear returns ErrTooFewObservations. If the design matrix
+// is rank deficient or near rank deficient, FitLinear returns the result
+// together with a mat.Condition error.
+func FitLinear(x mat.Matrix, y, weights []float64, origin bool) (*Linear, error) {
+	n, _ := x.Dims()
+	if n != len(y) {
+		panic(badLength)
+	}
+	checkWeights(y, weights)
+	design := designMatrix(x, origin)
+	_, p := design.Dims()
+	sumW := sumWeights(len(y), weights)
+	if sumW <= float64(p) {
+		panic(badShape)
+	}
+
+	qr, coef, err := weightedLeastSquares(design, y, weights)
+	if err != nil {
+		if _, ok := err.(mat.Condition); !ok {
+			return nil, err
+		}
+	}
+
+	var rss float64
+	for i := range y {
+		r := y[i] - mat.Dot(design.RowView(i), mat.NewVecDense(p, coef))
+		rss += weightAt(weights, i) * r * r
+	}
+	var tss float64
+	if origin {
+		for i, v := range y {
+			tss += weightAt(weights, i) * v * v
+		}
+	} else {
+		mean := stat.Mean(y, weights)
+		for i, v := range y {
+			tss += weightAt(weights, i) * (v - mean) * (v - mean)
+		}
+	}
+
+	dof := sumW - float64(p)
+	sigma2 := rss / dof
+	cov := qrInverse(qr, p)
+	cov.ScaleSym(sigma2, cov)
+
+	l := &Linear{
+		Coef:   coef,
+		StdErr: make([]float64, p),
+		TStat:  make([]float64, p),
+		PValue: make([]float64, p),
+		Cov:    cov,
+		Sigma:  math.Sqrt(sigma2),
+		DoF:    dof,
+		origin: origin,
+	}
+	tDist := distuv.StudentsT{Mu: 0, Sigma: 1, Nu: dof}
+	for i, c := range coef {
+		l.StdErr[i] = math.Sqrt(cov.At(i, i))
+		l.TStat[i] = c / l.StdErr[i]
+		l.PValue[i] = 2 * tDist.Survival(math.Abs(l.TStat[i]))
+	}
+
+	k := float64(p)
+	if !origin {
+		k--
+	}
+	l.RSquared = 1 - rss/tss
+	total := sumW
+	if !origin {
+		total--
+	}
+	l.AdjRSquared = 1 - (1-l.RSquared)*total/dof
+	if k > 0 {
+		l.FStat = ((tss - rss) / k) / sigma2
+		l.FPValue = fSurvival(l.FStat, k, dof)
+	}
+	return l, err
+}
+
+// Predict returns the predicted response at the predictors x. The length of x
+// must be the number of predictors in the model, not including the intercept.
+func (l *Linear) Predict(x []float64) float64 {
+	return predict(l.Coef, x, l.origin)
+}
+
+// ConfidenceInterval returns the confidence interval for the mean response at
+// the predictors x with the given confidence level. The length of x must be the
+// number of predictors in the model, not including the intercept.
+func (l *Linear) ConfidenceInterval(x []float64, level float64) (lo, hi float64) {
+	return l.interval(x, level, 0)
+}
+
+// PredictionInterval returns the prediction interval for a new observation of
+// the response at the predictors x with the given confidence level. The length
+// of x must be the number of predictors in the model, not including the
+// intercept.
+func (l *Linear) PredictionInterval(x []float64, level float64) (lo, hi float64) {
+	return l.interval(x, level, l.Sigma*l.Sigma)
+}
+
+func (l *Linear) interval(x []float64, level, extra float64) (lo, hi float64) {
+	if !(0 < level && level < 1) {
+		panic(badLevel)
+	}
+	v := designRow(x, len(l.Coef), l.origin)
+	se := math.Sqrt(mat.Inner(v, l.Cov, v) + extra)
+	t := distuv.StudentsT{Mu: 0, Sigma: 1, Nu: l.DoF}.Quantile(0.5 + level/2)
+	mean := l.Predict(x)
+	return mean - t*se, mean + t*se
+}
+
+// designMatrix returns the design matrix of x, prepending a column of ones
+// if origin is false.
+func designMatrix(x mat.Matrix, origin bool) *mat.Dense {
+	r, c := x.Dims()
+	if origin {
+		return mat.DenseCopyOf(x)
+	}
+	d := mat.NewDense(r, c+1, nil)
+	for i := 0; i < r; i++ {
+		d.Set(i, 0, 1)
+	}
+	d.Slice(0, r, 1, c+1).(*mat.Dense).Copy(x)
+	return d
+}
+
+// designRow returns the row of the design matrix for the predictors x.
+func designRow(x []float64, p int, origin bool) *mat.VecDense {
+	if origin {
+		if len(x) != p {
+			panic(badNumPreds)
+		}
+		return mat.NewVecDense(p, x)
+	}
+	if len(x) != p-1 {
+		panic(badNumPreds)
+	}
+	v := mat.NewVecDense(p, nil)
+	v.SetVec(0, 1)
+	for i, xi := range x {
+		v.SetVec(i+1, xi)
+	}
+	return v
+}
+
+// predict returns the linear predictor for the coefficients and predictors.
+func predict(coef, x []float64, origin bool) float64 {
+	if origin {
+		if len(x) != len(coef) {
+			panic(badNumPreds)
+		}
+		return floats.Dot(coef, x)
+	}
+	if len(x) != len(coef)-1 {
+		panic(badNumPreds)
+	}
+	return coef[0] + floats.Dot(coef[1:], x)
+}
+
+// weightedLeastSquares solves the weighted least squares problem for the design
+// matrix and response, returning the QR decomposition of the weighted design
+// matrix and the coefficients. If fewer than p rows of the design matrix have
+// non-zero weight, ErrTooFewObservations is returned. Any error returned from
+// the QR solve is returned.
+func weightedLeastSquares(design *mat.Dense, y, weights []float64) (*mat.QR, []float64, error) {
+	n, p := design.Dims()
+	nonZero := n
+	if weights != nil {
+		nonZero = 0
+		for _, w := range weights {
+			if w != 0 {
+				nonZero++
+			}
+		}
+	}
+	if nonZero < p {
+		return nil, nil, ErrTooFewObservations
+	}
+	a := design
+	b := mat.NewVecDense(n, nil)
+	if weights != nil {
+		a = mat.NewDense(n, p, nil)
+		for i := 0; i < n; i++ {
+			sw := math.Sqrt(weights[i])
+			for j := 0; j < p; j++ {
+				a.Set(i, j, sw*design.At(i, j))
+			}
+			b.SetVec(i, sw*y[i])
+		}
+	} else {
+		for i, v := range y {
+			b.SetVec(i, v)
+		}
+	}
+	var qr mat.QR
+	qr.Factorize(a)
+	coef := mat.NewVecDense(p, nil)
+	err := qr.SolveVecTo(coef, false, b)
+	return &qr, coef.RawVector().Data, err
+}
+
+// qrInverse returns (AᵀA)^-1 for the matrix A with n columns represented by
+// its QR decomposition.
+func qrInverse(qr *mat.QR, n int) *mat.SymDense {
+	var r mat.Dense
+	qr.RTo(&r)
+	u := mat.NewTriDense(n, mat.Upper, nil)
+	u.Copy(r.Slice(0, n, 0, n))
+	var chol mat.Cholesky
+	chol.SetFromU(u)
+	var inv mat.SymDense
+	// An ill-conditioned inverse is still returned, the condition
+	// of the problem is reported by the least squares solve.
+	chol.InverseTo(&inv)
+	return &inv
+}
+
+// fSurvival returns the survival function of the F distribution with d1 and
+// d2 degrees of freedom at x.
+func fSurvival(x, d1, d2 float64) float64 {
+	if x <= 0 {
+		return 1
+	}
+	return mathext.RegIncBeta(d2/2, d1/2, d2/(d2+d1*x))
+}
+
+// checkWeights panics if weights is not nil and does not have the same
+// length as y or has a negative element.
+func checkWeights(y, weights []float64) {
+	if weights == nil {
+		return
+	}
+	if len(y) != len(weights) {
+		panic(badLength)
+	}
+	for _, w := range weights {
+		if w < 0 {
+			panic(badWeights)
+		}
+	}
+}
+
+// sumWeights returns the sum of weights, or n if weights is nil.
+func sumWeights(n int, weights []float64) float64 {
+	if weights == nil {
+		return float64(n)
+	}
+	return floats.Sum(weights)
+}
+
+// weightAt returns weights[i], or 1 if weights is nil.
+func weightAt(weights []float64, i int) float64 {
+	if weights == nil {
+		return 1
+	}
+	return weights[i]
+}
@@ -0,0 +1,201 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package regression
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/floats/scalar"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// cars is the R cars data set.
+var cars = struct {
+	speed, dist []float64
+}{
+	speed: []float64{4, 4, 7, 7, 8, 9, 10, 10, 10, 11, 11, 12, 12, 12, 12, 13, 13, 13, 13, 14, 14, 14, 14, 15, 15, 15, 16, 16, 17, 17, 17, 18, 18, 18, 18, 19, 19, 19, 20, 20, 20, 20, 20, 22, 23, 24, 24, 24, 24, 25},
+	dist:  []float64{2, 10, 4, 22, 16, 10, 18, 26, 34, 17, 28, 14, 20, 24, 28, 26, 34, 34, 46, 26, 36, 60, 80, 20, 26, 54, 32, 40, 32, 40, 50, 42, 56, 76, 84, 36, 46, 68, 32, 48, 52, 56, 64, 66, 54, 70, 92, 93, 120, 85},
+}
+
+func TestFitLinearCars(t *testing.T) {
+	x := mat.NewDense(len(cars.speed), 1, cars.speed)
+	l, err := FitLinear(x, cars.dist, nil, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Values from R summary(lm(dist ~ speed, cars)).
+	for _, test := range []struct {
+		name      string
+		got, want float64
+		tol       float64
+	}{
+		{name: "intercept", got: l.Coef[0], want: -17.5791, tol: 1e-4},
+		{name: "slope", got: l.Coef[1], want: 3.9324, tol: 1e-4},
+		{name: "intercept se", got: l.StdErr[0], want: 6.7584, tol: 1e-4},
+		{name: "slope se", got: l.StdErr[1], want: 0.4155, tol: 1e-4},
+		{name: "slope t", got: l.TStat[1], want: 9.464, tol: 1e-3},
+		{name: "intercept p", got: l.PValue[0], want: 0.0123, tol: 1e-4},
+		{name: "sigma", got: l.Sigma, want: 15.38, tol: 1e-2},
+		{name: "r squared", got: l.RSquared, want: 0.6511, tol: 1e-4},
+		{name: "adjusted r squared", got: l.AdjRSquared, want: 0.6438, tol: 1e-4},
+		{name: "F", got: l.FStat, want: 89.57, tol: 1e-2},
+		{name: "dof", got: l.DoF, want: 48, tol: 0},
+	} {
+		if !scalar.EqualWithinAbs(test.got, test.want, test.tol) {
+			t.Errorf("%s mismatch: got %v, want %v", test.name, test.got, test.want)
+		}
+	}
+
+	alpha, beta := stat.LinearRegression(cars.speed, cars.dist, nil, false)
+	if !scalar.EqualWithinAbsOrRel(alpha, l.Coef[0], 1e-10, 1e-10) || !scalar.EqualWithinAbsOrRel(beta, l.Coef[1], 1e-10, 1e-10) {
+		t.Errorf("mismatch with stat.LinearRegression: got %v, want [%v %v]", l.Coef, alpha, beta)
+	}
+	if r2 := stat.RSquared(cars.speed, cars.dist, nil, alpha, beta); !scalar.EqualWithinAbsOrRel(r2, l.RSquared, 1e-10, 1e-10) {
+		t.Errorf("mismatch with stat.RSquared: got %v, want %v", l.RSquared, r2)
+	}
+
+	// Check the intervals against the closed form for a single predictor.
+	const x0 = 21
+	mean, variance := stat.MeanVariance(cars.speed, nil)
+	n := float64(len(cars.speed))
+	sxx := variance * (n - 1)
+	seMean := l.Sigma * math.Sqrt(1/n+(x0-mean)*(x0-mean)/sxx)
+	sePred := l.Sigma * math.Sqrt(1+1/n+(x0-mean)*(x0-mean)/sxx)
+	q := distuv.StudentsT{Mu: 0, Sigma: 1, Nu: n - 2}.Quantile(0.975)
+	mid := alpha + beta*x0
+	if got := l.Predict([]float64{x0}); !scalar.EqualWithinAbsOrRel(got, mid, 1e-10, 1e-10) {
+		t.Errorf("prediction mismatch: got %v, want %v", got, mid)
+	}
+	lo, hi := l.ConfidenceInterval([]float64{x0}, 0.95)
+	if !scalar.EqualWithinAbsOrRel(lo, mid-q*seMean, 1e-10, 1e-10) || !scalar.EqualWithinAbsOrRel(hi, mid+q*seMean, 1e-10, 1e-10) {
+		t.Errorf("confidence interval mismatch: got [%v, %v], want [%v, %v]", lo, hi, mid-q*seMean, mid+q*seMean)
+	}
+	lo, hi = l.PredictionInterval([]float64{x0}, 0.95)
+	if !scalar.EqualWithinAbsOrRel(lo, mid-q*sePred, 1e-10, 1e-10) || !scalar.EqualWithinAbsOrRel(hi, mid+q*sePred, 1e-10, 1e-10) {
+		t.Errorf("prediction interval mismatch: got [%v, %v], want [%v, %v]", lo, hi, mid-q*sePred, mid+q*sePred)
+	}
+}
+
+func TestFitLinearNormalEquations(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	const n, p = 40, 3
+	for _, origin := range []bool{false, true} {
+		x := mat.NewDense(n, p, nil)
+		y := make([]float64, n)
+		w := make([]float64, n)
+		for i := 0; i < n; i++ {
+			for j := 0; j < p; j++ {
+				x.Set(i, j, rnd.NormFloat64())
+			}
+			y[i] = 1 + 2*x.At(i, 0) - x.At(i, 2) + rnd.NormFloat64()
+			w[i] = float64(1 + rnd.Intn(3))
+		}
+		l, err := FitLinear(x, y, w, origin)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		// Solve (XᵀWX)β = XᵀWy directly.
+		d := designMatrix(x, origin)
+		_, q := d.Dims()
+		var xtwx mat.Dense
+		xtw := mat.NewDense(q, n, nil)
+		for i := 0; i < n; i++ {
+			for j := 0; j < q; j++ {
+				xtw.Set(j, i, w[i]*d.At(i, j))
+			}
+		}
+		xtwx.Mul(xtw, d)
+		var xtwy mat.VecDense
+		xtwy.MulVec(xtw, mat.NewVecDense(n, y))
+		var want mat.VecDense
+		err = want.SolveVec(&xtwx, &xtwy)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !floats.EqualApprox(l.Coef, want.RawVector().Data, 1e-10) {
+			t.Errorf("coefficient mismatch, origin=%t: got %v, want %v", origin, l.Coef, want.RawVector().Data)
+		}
+		var inv mat.Dense
+		err = inv.Inverse(&xtwx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		inv.Scale(l.Sigma*l.Sigma, &inv)
+		if !mat.EqualApprox(l.Cov, &inv, 1e-10) {
+			t.Errorf("covariance mismatch, origin=%t", origin)
+		}
+
+		// Frequency weights are equivalent to replicating observations.
+		var rows [][]float64
+		var ry []float64
+		for i := 0; i < n; i++ {
+			for k := 0; k < int(w[i]); k++ {
+				rows = append(rows, x.RawRowView(i))
+				ry = append(ry, y[i])
+			}
+		}
+		rx := mat.NewDense(len(rows), p, nil)
+		for i, r := range rows {
+			rx.SetRow(i, r)
+		}
+		rl, err := FitLinear(rx, ry, nil, origin)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !floats.EqualApprox(l.StdErr, rl.StdErr, 1e-10) || !scalar.EqualWithinAbsOrRel(l.RSquared, rl.RSquared, 1e-10, 1e-10) ||
+			!scalar.EqualWithinAbsOrRel(l.FStat, rl.FStat, 1e-10, 1e-10) {
+			t.Errorf("weighted fit does not match replicated data, origin=%t", origin)
+		}
+	}
+}
+
+func TestFitLinearRankDeficient(t *testing.T) {
+	x := mat.NewDense(5, 2, []float64{
+		1, 2,
+		2, 4,
+		3, 6,
+		4, 8,
+		5, 10,
+	})
+	y := []float64{1, 3, 2, 5, 4}
+	_, err := FitLinear(x, y, nil, false)
+	if _, ok := err.(mat.Condition); !ok {
+		t.Errorf("expected mat.Condition error for collinear predictors, got %v", err)
+	}
+}
+
+func TestFitLinearTooFewObservations(t *testing.T) {
+	for _, test := range []struct {
+		x       *mat.Dense
+		y       []float64
+		weights []float64
+	}{
+		// Fewer rows than coefficients with a total
+		// weight exceeding the number of coefficients.
+		{
+			x:       mat.NewDense(2, 2, []float64{1, 2, 3, 5}),
+			y:       []float64{1, 2},
+			weights: []float64{5, 5},
+		},
+		// Enough rows, but too few with non-zero weight.
+		{
+			x:       mat.NewDense(4, 2, []float64{1, 2, 3, 5, 4, 1, 2, 2}),
+			y:       []float64{1, 2, 3, 4},
+			weights: []float64{5, 0, 5, 0},
+		},
+	} {
+		_, err := FitLinear(test.x, test.y, test.weights, false)
+		if err != ErrTooFewObservations {
+			t.Errorf("unexpected error for weights %v: got:%v want:%v", test.weights, err, ErrTooFewObservations)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package regression
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+const (
+	badLambda = "regression: negative penalty"
+
+	lassoMaxIter = 10000
+	lassoTol     = 1e-10
+)
+
+// Ridge returns the coefficients of the ridge regression of y on x that minimize
+//  \sum_i w[i]*(y[i] - x_i*β)^2 + λ*\sum_j β_j^2
+// where x_i is the i^th row of x. If origin is false, an unpenalized intercept
+// is included in the model and is the first returned coefficient.
+//
+// If weights is nil then all the weights are 1. If weights is not nil, then
+// len(weights) must equal len(y). Ridge panics if the number of rows of x is
+// not equal to len(y) or if lambda is negative. If the penalized problem is
+// ill-conditioned the coefficients are returned with a mat.Condition error.
+func Ridge(x mat.Matrix, y, weights []float64, lambda float64, origin bool) ([]float64, error) {
+	n, p := x.Dims()
+	if n != len(y) {
+		panic(badLength)
+	}
+	if lambda < 0 {
+		panic(badLambda)
+	}
+	checkWeights(y, weights)
+	xc, yc, xMean, yMean := center(x, y, weights, origin)
+
+	// Solve the augmented least squares problem
+	//  [ √W*X  ]     [ √W*y ]
+	//  [ √λ*I  ] β = [  0   ]
+	a := mat.NewDense(n+p, p, nil)
+	b := mat.NewVecDense(n+p, nil)
+	for i := 0; i < n; i++ {
+		sw := math.Sqrt(weightAt(weights, i))
+		for j := 0; j < p; j++ {
+			a.Set(i, j, sw*xc.At(i, j))
+		}
+		b.SetVec(i, sw*yc[i])
+	}
+	sl := math.Sqrt(lambda)
+	for j := 0; j < p; j++ {
+		a.Set(n+j, j, sl)
+	}
+	var qr mat.QR
+	qr.Factorize(a)
+	beta := mat.NewVecDense(p, nil)
+	err := qr.SolveVecTo(beta, false, b)
+	if err != nil {
+		if _, ok := err.(mat.Condition); !ok {
+			return nil, err
+		}
+	}
+	return withIntercept(beta.RawVector().Data, xMean, yMean, origin), err
+}
+
+// Lasso returns the coefficients of the lasso regression of y on x that minimize
+//  1/(2*\sum_i w[i]) * \sum_i w[i]*(y[i] - x_i*β)^2 + λ*\sum_j |β_j|
+// where x_i is the i^th row of x, computed by cyclic coordinate descent. If
+// origin is false, an unpenalized intercept is included in the model and is
+// the first returned coefficient.
+//
+// If weights is nil then all the weights are 1. If weights is not nil, then
+// len(weights) must equal len(y). Lasso panics if the number of rows of x is
+// not equal to len(y) or if lambda is negative. If the coordinate descent does
+// not converge the last iterate is returned with ErrNotConverged.
+func Lasso(x mat.Matrix, y, weights []float64, lambda float64, origin bool) ([]float64, error) {
+	n, p := x.Dims()
+	if n != len(y) {
+		panic(badLength)
+	}
+	if lambda < 0 {
+		panic(badLambda)
+	}
+	checkWeights(y, weights)
+	xc, r, xMean, yMean := center(x, y, weights, origin)
+	sumW := sumWeights(n, weights)
+
+	// The residuals r are updated in place as each coordinate changes.
+	scale := make([]float64, p)
+	for j := range scale {
+		for i := 0; i < n; i++ {
+			v := xc.At(i, j)
+			scale[j] += weightAt(weights, i) * v * v
+		}
+		scale[j] /= sumW
+	}
+	beta := make([]float64, p)
+	for iter := 0; iter < lassoMaxIter; iter++ {
+		var maxDelta float64
+		for j := range beta {
+			if scale[j] == 0 {
+				continue
+			}
+			var rho float64
+			for i := 0; i < n; i++ {
+				rho += weightAt(weights, i) * xc.At(i, j) * r[i]
+			}
+			rho = rho/sumW + scale[j]*beta[j]
+			next := softThreshold(rho, lambda) / scale[j]
+			delta := next - beta[j]
+			if delta == 0 {
+				continue
+			}
+			for i := 0; i < n; i++ {
+				r[i] -= delta * xc.At(i, j)
+			}
+			beta[j] = next
+			maxDelta = math.Max(maxDelta, math.Abs(delta)*math.Sqrt(scale[j]))
+		}
+		if maxDelta < lassoTol {
+			return withIntercept(beta, xMean, yMean, origin), nil
+		}
+	}
+	return withIntercept(beta, xMean, yMean, origin), ErrNotConverged
+}
+
+// center returns copies of x and y centered by their weighted means, along with
+// the means. If origin is true the data are copied but not centered.
+func center(x mat.Matrix, y, weights []float64, origin bool) (xc *mat.Dense, yc, xMean []float64, yMean float64) {
+	n, p := x.Dims()
+	xc = mat.DenseCopyOf(x)
+	yc = make([]float64, n)
+	copy(yc, y)
+	xMean = make([]float64, p)
+	if origin {
+		return xc, yc, xMean, 0
+	}
+	sumW := sumWeights(n, weights)
+	for i := 0; i < n; i++ {
+		w := weightAt(weights, i)
+		for j := 0; j < p; j++ {
+			xMean[j] += w * xc.At(i, j)
+		}
+		yMean += w * y[i]
+	}
+	for j := range xMean {
+		xMean[j] /= sumW
+	}
+	yMean /= sumW
+	for i := 0; i < n; i++ {
+		for j := 0; j < p; j++ {
+			xc.Set(i, j, xc.At(i, j)-xMean[j])
+		}
+		yc[i] -= yMean
+	}
+	return xc, yc, xMean, yMean
+}
+
+// withIntercept returns the coefficients beta of centered data with the
+// intercept prepended if origin is false.
+func withIntercept(beta, xMean []float64, yMean float64, origin bool) []float64 {
+	if origin {
+		return beta
+	}
+	coef := make([]float64, len(beta)+1)
+	coef[0] = yMean
+	for j, b := range beta {
+		coef[0] -= b * xMean[j]
+		coef[j+1] = b
+	}
+	return coef
+}
+
+// softThreshold returns the soft-thresholding operator S(x, t) = sign(x)*max(|x|-t, 0).
+func softThreshold(x, t float64) float64 {
+	switch {
+	case x > t:
+		return x - t
+	case x < -t:
+		return x + t
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package regression
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+func penalizedData(n, p int, src rand.Source) (*mat.Dense, []float64, []float64) {
+	rnd := rand.New(src)
+	x := mat.NewDense(n, p, nil)
+	y := make([]float64, n)
+	w := make([]float64, n)
+	for i := 0; i < n; i++ {
+		for j := 0; j < p; j++ {
+			x.Set(i, j, rnd.NormFloat64())
+		}
+		y[i] = 2 + 3*x.At(i, 0) - 2*x.At(i, 1) + 0.5*rnd.NormFloat64()
+		w[i] = 0.5 + rnd.Float64()
+	}
+	return x, y, w
+}
+
+func TestRidge(t *testing.T) {
+	x, y, w := penalizedData(50, 4, rand.NewSource(1))
+	for _, origin := range []bool{false, true} {
+		l, err := FitLinear(x, y, w, origin)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got, err := Ridge(x, y, w, 0, origin)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !floats.EqualApprox(got, l.Coef, 1e-10) {
+			t.Errorf("unpenalized ridge does not match least squares, origin=%t: got %v, want %v", origin, got, l.Coef)
+		}
+
+		// The penalized coefficients satisfy (XᵀWX + λI)β = XᵀWy for centered data.
+		const lambda = 5
+		coef, err := Ridge(x, y, w, lambda, origin)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		xc, yc, _, _ := center(x, y, w, origin)
+		beta := coef
+		if !origin {
+			beta = coef[1:]
+		}
+		for j := range beta {
+			var grad float64
+			for i := range y {
+				r := yc[i] - floats.Dot(xc.RawRowView(i), beta)
+				grad -= w[i] * xc.At(i, j) * r
+			}
+			grad += lambda * beta[j]
+			if math.Abs(grad) > 1e-10 {
+				t.Errorf("ridge stationarity not satisfied for coefficient %d, origin=%t: %v", j, origin, grad)
+			}
+		}
+		if floats.Norm(beta, 2) >= floats.Norm(l.Coef[len(l.Coef)-len(beta):], 2) {
+			t.Errorf("ridge did not shrink coefficients, origin=%t", origin)
+		}
+	}
+}
+
+func TestLasso(t *testing.T) {
+	x, y, w := penalizedData(60, 5, rand.NewSource(1))
+	sumW := floats.Sum(w)
+	for _, origin := range []bool{false, true} {
+		l, err := FitLinear(x, y, w, origin)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got, err := Lasso(x, y, w, 0, origin)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !floats.EqualApprox(got, l.Coef, 1e-8) {
+			t.Errorf("unpenalized lasso does not match least squares, origin=%t: got %v, want %v", origin, got, l.Coef)
+		}
+
+		for _, lambda := range []float64{0.1, 0.5, 2} {
+			coef, err := Lasso(x, y, w, lambda, origin)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			xc, yc, _, _ := center(x, y, w, origin)
+			beta := coef
+			if !origin {
+				beta = coef[1:]
+			}
+			// Check the Karush–Kuhn–Tucker conditions.
+			for j, b := range beta {
+				var grad float64
+				for i := range y {
+					r := yc[i] - floats.Dot(xc.RawRowView(i), beta)
+					grad += w[i] * xc.At(i, j) * r
+				}
+				grad /= sumW
+				if b != 0 {
+					if math.Abs(grad-math.Copysign(lambda, b)) > 1e-8 {
+						t.Errorf("KKT condition not satisfied for active coefficient %d, λ=%v, origin=%t: %v", j, lambda, origin, grad)
+					}
+				} else if math.Abs(grad) > lambda+1e-8 {
+					t.Errorf("KKT condition not satisfied for zero coefficient %d, λ=%v, origin=%t: %v", j, lambda, origin, grad)
+				}
+			}
+		}
+	}
+
+	coef, err := Lasso(x, y, w, 100, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, b := range coef[1:] {
+		if b != 0 {
+			t.Errorf("expected all coefficients to be zero for large penalty, got %v", coef)
+			break
+		}
+	}
+}
@@ -0,0 +1,369 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package resample
+
+import (
+	"math"
+	"runtime"
+	"sort"
+	"sync"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+const (
+	badLength  = "resample: slice length mismatch"
+	badWeights = "resample: negative weight"
+)
+
+// Statistic returns the value of a statistic computed from a fixed set of
+// observations, with observation i weighted by weights[i]. Statistic must
+// be safe to call concurrently.
+type Statistic func(weights []float64) float64
+
+// Bootstrap estimates the sampling distribution of a statistic by
+// evaluating it on resamples of the observations.
+type Bootstrap struct {
+	// Statistic is the statistic to resample.
+	Statistic Statistic
+
+	// Resampler generates the resamples. If Resampler
+	// is nil, Nonparametric is used.
+	Resampler Resampler
+
+	// StdErr, if not nil, returns an estimate of the
+	// standard error of Statistic for the weighted
+	// observations, for use in studentized intervals.
+	StdErr func(weights []float64) float64
+
+	// Inner is the number of nested bootstrap replicates
+	// used to estimate the standard error of each replicate
+	// when StdErr is nil. If both StdErr is nil and Inner
+	// is zero, standard errors are not computed and
+	// studentized intervals are not available.
+	Inner int
+
+	// Src is the source of random numbers. If Src is nil,
+	// the global source is used.
+	Src rand.Source
+}
+
+// Replicate returns the result of evaluating the statistic on the given
+// number of resamples of n observations with the given weights. If weights
+// is nil, all the observations have unit weight, otherwise len(weights)
+// must equal n.
+//
+// The resamples are evaluated concurrently. Each resample uses a random
+// number generator seeded from b.Src, so results are reproducible for a
+// given source.
+func (b Bootstrap) Replicate(n, replicates int, weights []float64) Result {
+	weights = checkWeights(n, weights)
+	r := b.Resampler
+	if r == nil {
+		r = Nonparametric{}
+	}
+	studentize := b.StdErr != nil || b.Inner > 0
+
+	res := Result{
+		Estimate:   b.Statistic(weights),
+		Replicates: make([]float64, replicates),
+		Jackknife:  JackknifeValues(nil, b.Statistic, weights),
+	}
+	if studentize {
+		res.StdErrs = make([]float64, replicates)
+	}
+
+	parallelRand(replicates, b.Src, func() func(i int, rnd *rand.Rand) {
+		counts := make([]float64, n)
+		w := make([]float64, n)
+		var in *inner
+		if studentize && b.StdErr == nil {
+			in = newInner(n, b.Inner)
+		}
+		return func(i int, rnd *rand.Rand) {
+			if in != nil {
+				in.resample(r, counts, rnd)
+			} else {
+				r.Resample(counts, rnd)
+			}
+			for j, c := range counts {
+				w[j] = c * weights[j]
+			}
+			res.Replicates[i] = b.Statistic(w)
+			switch {
+			case b.StdErr != nil:
+				res.StdErrs[i] = b.StdErr(w)
+			case in != nil:
+				res.StdErrs[i] = in.stdErr(b.Statistic, r, weights, rnd)
+			}
+		}
+	})
+
+	if studentize {
+		if b.StdErr != nil {
+			res.EstimateStdErr = b.StdErr(weights)
+		} else {
+			res.EstimateStdErr = res.StdErr()
+		}
+	}
+	return res
+}
+
+// inner holds the workspace for nested bootstrap standard error estimates.
+type inner struct {
+	obs, idx []int
+	seq      []int
+	pos, c   []float64
+	w        []float64
+	vals     []float64
+}
+
+func newInner(n, replicates int) *inner {
+	obs := make([]int, n)
+	for i := range obs {
+		obs[i] = i
+	}
+	return &inner{
+		obs:  obs,
+		idx:  make([]int, n),
+		seq:  make([]int, n),
+		pos:  make([]float64, n),
+		c:    make([]float64, n),
+		w:    make([]float64, n),
+		vals: make([]float64, replicates),
+	}
+}
+
+// resample sets counts to an outer resample drawn by r and records the
+// observation indices of the resample for use by stdErr.
+func (in *inner) resample(r Resampler, counts []float64, rnd *rand.Rand) {
+	if s, ok := r.(sequenceResampler); ok {
+		s.resampleSequence(in.idx, in.obs, rnd)
+		zero(counts)
+		for _, o := range in.idx {
+			counts[o]++
+		}
+		return
+	}
+	r.Resample(counts, rnd)
+	in.idx = in.idx[:0]
+	for j, c := range counts {
+		for k := 0; k < int(c); k++ {
+			in.idx = append(in.idx, j)
+		}
+	}
+}
+
+// stdErr returns the nested bootstrap standard error of the statistic for the
+// outer resample last drawn by resample. The inner resamples of the resamplers
+// provided by this package are drawn from the observations of the outer
+// resample, retaining strata and blocks. Other resamplers are applied to the
+// positions of the observations of the outer resample ordered by observation
+// index.
+func (in *inner) stdErr(fn Statistic, r Resampler, weights []float64, rnd *rand.Rand) float64 {
+	s, isSequence := r.(sequenceResampler)
+	for k := range in.vals {
+		zero(in.c)
+		if isSequence {
+			s.resampleSequence(in.seq, in.idx, rnd)
+			for _, o := range in.seq {
+				in.c[o]++
+			}
+		} else {
+			r.Resample(in.pos, rnd)
+			for p, c := range in.pos {
+				in.c[in.idx[p]] += c
+			}
+		}
+		for j, c := range in.c {
+			in.w[j] = c * weights[j]
+		}
+		in.vals[k] = fn(in.w)
+	}
+	return stat.StdDev(in.vals, nil)
+}
+
+// Result holds the bootstrap replicates of a statistic.
+type Result struct {
+	// Estimate is the value of the statistic
+	// for the observed data.
+	Estimate float64
+
+	// Replicates holds the value of the statistic
+	// for each resample.
+	Replicates []float64
+
+	// Jackknife holds the leave-one-out jackknife
+	// values of the statistic, used to estimate the
+	// acceleration of BCa intervals.
+	Jackknife []float64
+
+	// StdErrs holds the estimated standard error of
+	// each replicate and EstimateStdErr the estimated
+	// standard error of Estimate. StdErrs is nil if
+	// standard errors were not computed.
+	StdErrs        []float64
+	EstimateStdErr float64
+}
+
+// Bias returns the bootstrap estimate of the bias of the statistic.
+func (r Result) Bias() float64 {
+	return stat.Mean(r.Replicates, nil) - r.Estimate
+}
+
+// StdErr returns the bootstrap estimate of the standard error of the
+// statistic.
+func (r Result) StdErr() float64 {
+	return stat.StdDev(r.Replicates, nil)
+}
+
+// Percentile returns the bootstrap percentile confidence interval for the
+// statistic at the confidence level 1-alpha.
+func (r Result) Percentile(alpha float64) (lo, hi float64) {
+	checkAlpha(alpha)
+	return quantiles(r.Replicates, alpha/2, 1-alpha/2)
+}
+
+// BCa returns the bias-corrected and accelerated bootstrap confidence
+// interval for the statistic at the confidence level 1-alpha. The bias
+// correction is estimated from the proportion of replicates less than the
+// estimate and the acceleration from the jackknife values, as described in
+// Efron, "Better bootstrap confidence intervals", Journal of the American
+// Statistical Association 82 (1987) 171-185.
+func (r Result) BCa(alpha float64) (lo, hi float64) {
+	checkAlpha(alpha)
+	var less float64
+	for _, v := range r.Replicates {
+		switch {
+		case v < r.Estimate:
+			less++
+		case v == r.Estimate:
+			less += 0.5
+		}
+	}
+	z0 := distuv.UnitNormal.Quantile(less / float64(len(r.Replicates)))
+
+	m := stat.Mean(r.Jackknife, nil)
+	var num, den float64
+	for _, v := range r.Jackknife {
+		d := m - v
+		num += d * d * d
+		den += d * d
+	}
+	var a float64
+	if den != 0 {
+		a = num / (6 * math.Pow(den, 1.5))
+	}
+
+	adjust := func(p float64) float64 {
+		z := z0 + distuv.UnitNormal.Quantile(p)
+		return distuv.UnitNormal.CDF(z0 + z/(1-a*z))
+	}
+	return quantiles(r.Replicates, adjust(alpha/2), adjust(1-alpha/2))
+}
+
+// Studentized returns the bootstrap-t confidence interval for the statistic
+// at the confidence level 1-alpha. Studentized panics if the standard errors
+// of the replicates were not computed.
+func (r Result) Studentized(alpha float64) (lo, hi float64) {
+	checkAlpha(alpha)
+	if r.StdErrs == nil {
+		panic("resample: standard errors not computed")
+	}
+	t := make([]float64, len(r.Replicates))
+	for i, v := range r.Replicates {
+		t[i] = (v - r.Estimate) / r.StdErrs[i]
+	}
+	tlo, thi := quantiles(t, alpha/2, 1-alpha/2)
+	return r.Estimate - thi*r.EstimateStdErr, r.Estimate - tlo*r.EstimateStdErr
+}
+
+// quantiles returns the linearly interpolated p and q quantiles of x.
+func quantiles(x []float64, p, q float64) (float64, float64) {
+	s := make([]float64, len(x))
+	copy(s, x)
+	sort.Float64s(s)
+	return stat.Quantile(p, stat.LinInterp, s, nil), stat.Quantile(q, stat.LinInterp, s, nil)
+}
+
+func checkAlpha(alpha float64) {
+	if !(0 < alpha && alpha < 1) {
+		panic("resample: confidence level out of range")
+	}
+}
+
+// checkWeights returns weights, or unit weights if weights is nil, after
+// checking that it has length n and non-negative elements.
+func checkWeights(n int, weights []float64) []float64 {
+	if weights == nil {
+		weights = make([]float64, n)
+		for i := range weights {
+			weights[i] = 1
+		}
+		return weights
+	}
+	if len(weights) != n {
+		panic(badLength)
+	}
+	for _, w := range weights {
+		if w < 0 {
+			panic(badWeights)
+		}
+	}
+	return weights
+}
+
+// parallel calls the functions returned by newWorker for each i in [0, n)
+// using up to GOMAXPROCS goroutines. Each goroutine calls newWorker once to
+// obtain a function holding its own workspace.
+func parallel(n int, newWorker func() func(i int)) {
+	workers := runtime.GOMAXPROCS(0)
+	if workers > n {
+		workers = n
+	}
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for k := 0; k < workers; k++ {
+		go func() {
+			defer wg.Done()
+			fn := newWorker()
+			for i := range jobs {
+				fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// parallelRand is like parallel, but passes a random number generator to
+// each call. The generator for call i is seeded with the ith value drawn
+// from src, or from the global source if src is nil, so that the results
+// do not depend on scheduling.
+func parallelRand(n int, src rand.Source, newWorker func() func(i int, rnd *rand.Rand)) {
+	u64 := rand.Uint64
+	if src != nil {
+		u64 = rand.New(src).Uint64
+	}
+	seeds := make([]uint64, n)
+	for i := range seeds {
+		seeds[i] = u64()
+	}
+	parallel(n, func() func(i int) {
+		fn := newWorker()
+		rnd := rand.New(rand.NewSource(0))
+		return func(i int) {
+			rnd.Seed(seeds[i])
+			fn(i, rnd)
+		}
+	})
+}
@@ -0,0 +1,179 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package resample
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/stat"
+)
+
+func TestBootstrapMean(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const n = 100
+	x := make([]float64, n)
+	for i := range x {
+		x[i] = rnd.NormFloat64()*3 + 2
+	}
+	mean := func(w []float64) float64 { return stat.Mean(x, w) }
+	b := Bootstrap{
+		Statistic: mean,
+		StdErr: func(w []float64) float64 {
+			return stat.StdErr(stat.StdDev(x, w), floats.Sum(w))
+		},
+		Src: rand.NewSource(2),
+	}
+	res := b.Replicate(n, 4000, nil)
+
+	if !floats.EqualWithinAbsOrRel(res.Estimate, stat.Mean(x, nil), 1e-14, 1e-14) {
+		t.Errorf("unexpected estimate: got:%v want:%v", res.Estimate, stat.Mean(x, nil))
+	}
+	se := stat.StdErr(stat.StdDev(x, nil), n)
+	if math.Abs(res.StdErr()-se) > 0.1*se {
+		t.Errorf("unexpected standard error: got:%v want:%v", res.StdErr(), se)
+	}
+	if math.Abs(res.Bias()) > 0.1*se {
+		t.Errorf("unexpected bias: got:%v want:0", res.Bias())
+	}
+
+	// All intervals should be close to the normal interval.
+	const alpha = 0.1
+	z := 1.6448536269514722
+	wantLo, wantHi := res.Estimate-z*se, res.Estimate+z*se
+	for _, interval := range []struct {
+		name string
+		fn   func(float64) (float64, float64)
+	}{
+		{name: "percentile", fn: res.Percentile},
+		{name: "BCa", fn: res.BCa},
+		{name: "studentized", fn: res.Studentized},
+	} {
+		lo, hi := interval.fn(alpha)
+		if math.Abs(lo-wantLo) > 0.2*se || math.Abs(hi-wantHi) > 0.2*se {
+			t.Errorf("unexpected %s interval: got:[%v, %v] want:≈[%v, %v]", interval.name, lo, hi, wantLo, wantHi)
+		}
+	}
+
+	// Results are reproducible for a given source.
+	b.Src = rand.NewSource(2)
+	again := b.Replicate(n, 4000, nil)
+	if !floats.Equal(res.Replicates, again.Replicates) {
+		t.Errorf("replicates not reproducible")
+	}
+}
+
+func TestBootstrapCoverage(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const (
+		n        = 30
+		datasets = 200
+		alpha    = 0.1
+		// Mean of the exponential distribution.
+		truth = 1.0
+	)
+	x := make([]float64, n)
+	mean := func(w []float64) float64 { return stat.Mean(x, w) }
+	var percentile, bca, studentized int
+	for k := 0; k < datasets; k++ {
+		for i := range x {
+			x[i] = rnd.ExpFloat64()
+		}
+		b := Bootstrap{Statistic: mean, Inner: 25, Src: rand.NewSource(rnd.Uint64())}
+		res := b.Replicate(n, 400, nil)
+		if lo, hi := res.Percentile(alpha); lo <= truth && truth <= hi {
+			percentile++
+		}
+		if lo, hi := res.BCa(alpha); lo <= truth && truth <= hi {
+			bca++
+		}
+		if lo, hi := res.Studentized(alpha); lo <= truth && truth <= hi {
+			studentized++
+		}
+	}
+	for _, test := range []struct {
+		name    string
+		covered int
+	}{
+		{name: "percentile", covered: percentile},
+		{name: "BCa", covered: bca},
+		{name: "studentized", covered: studentized},
+	} {
+		coverage := float64(test.covered) / datasets
+		if coverage < 0.8 || 0.97 < coverage {
+			t.Errorf("unexpected %s coverage: got:%v want:%v", test.name, coverage, 1-alpha)
+		}
+	}
+}
+
+func TestBootstrapWeights(t *testing.T) {
+	t.Parallel()
+	// Observations with zero weight never contribute to a replicate.
+	x := []float64{1, 2, 3, 100, 4, 5}
+	w := []float64{1, 1, 1, 0, 1, 1}
+	b := Bootstrap{
+		Statistic: func(w []float64) float64 { return floats.Max(weighted(x, w)) },
+		Resampler: NewStratified([]int{0, 0, 0, 1, 1, 1}),
+		Src:       rand.NewSource(1),
+	}
+	res := b.Replicate(len(x), 500, w)
+	for _, v := range res.Replicates {
+		if v > 5 {
+			t.Fatalf("zero weight observation included in replicate")
+		}
+	}
+}
+
+func TestBootstrapInnerStratified(t *testing.T) {
+	t.Parallel()
+	// The share of weight in stratum 0 is fixed by stratified
+	// resampling, so nested resamples must not vary it.
+	labels := []int{0, 1, 0, 1, 1, 0, 0, 1, 0, 1}
+	share := func(w []float64) float64 {
+		var in, sum float64
+		for i, v := range w {
+			if labels[i] == 0 {
+				in += v
+			}
+			sum += v
+		}
+		return in / sum
+	}
+	b := Bootstrap{
+		Statistic: share,
+		Resampler: NewStratified(labels),
+		Inner:     20,
+		Src:       rand.NewSource(1),
+	}
+	res := b.Replicate(len(labels), 100, nil)
+	for i, v := range res.Replicates {
+		if v != 0.5 {
+			t.Errorf("unexpected replicate %d: got:%v want:0.5", i, v)
+		}
+		if res.StdErrs[i] != 0 {
+			t.Errorf("unexpected inner standard error for replicate %d: got:%v want:0", i, res.StdErrs[i])
+		}
+	}
+}
+
+// weighted returns the elements of x with non-zero weight, or NaN if there
+// are none.
+func weighted(x, w []float64) []float64 {
+	var r []float64
+	for i, v := range x {
+		if w[i] != 0 {
+			r = append(r, v)
+		}
+	}
+	if r == nil {
+		r = []float64{math.NaN()}
+	}
+	return r
+}
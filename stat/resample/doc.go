@@ -0,0 +1,22 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package resample provides resampling methods for estimating the sampling
+// distribution of a statistic: the bootstrap, the jackknife and permutation
+// tests.
+//
+// Statistics are functions of the weights of a fixed set of observations.
+// A resample is represented by reweighting the original observations, so
+// an observation drawn k times into a resample has its weight multiplied by
+// k and an observation that is not drawn has zero weight. This allows any
+// of the weighted estimators in package stat, such as stat.Mean,
+// stat.Quantile, stat.Correlation and stat.ROC, to be resampled by closing
+// over the observed data. Replicates are computed concurrently.
+//
+// Permutations are drawn with combin.UniformPermutation. Bootstrap resamples
+// draw observations uniformly with replacement and apply the observation
+// weights by reweighting, so the resamplers draw indices directly from the
+// random number generator. The weighted samplers of sampleuv draw without
+// replacement and are not suitable for this.
+package resample // import "gonum.org/v1/gonum/stat/resample"
@@ -0,0 +1,56 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package resample
+
+import "gonum.org/v1/gonum/stat"
+
+// JackknifeValues returns the leave-one-out values of the statistic fn for
+// the weighted observations, with element i of the result holding the value
+// of fn when the weight of observation i is set to zero. The values are
+// computed concurrently.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil,
+// it must have length len(weights) and must not be weights.
+func JackknifeValues(dst []float64, fn Statistic, weights []float64) []float64 {
+	n := len(weights)
+	if dst == nil {
+		dst = make([]float64, n)
+	} else if len(dst) != n {
+		panic(badLength)
+	}
+	parallel(n, func() func(i int) {
+		w := make([]float64, n)
+		return func(i int) {
+			copy(w, weights)
+			w[i] = 0
+			dst[i] = fn(w)
+		}
+	})
+	return dst
+}
+
+// Jackknife returns the value of the statistic fn for n observations with the
+// given weights, and the jackknife estimates of its bias and variance. If
+// weights is nil, all the observations have unit weight, otherwise
+// len(weights) must equal n.
+//
+// The estimates are computed from the leave-one-out values θ_i returned by
+// JackknifeValues,
+//  bias = (n-1) (θ̄ - θ)
+//  variance = (n-1)/n Σ_i (θ_i - θ̄)²
+// where θ̄ is the mean of the θ_i. Observations are deleted one at a time
+// irrespective of their weight.
+func Jackknife(n int, fn Statistic, weights []float64) (estimate, bias, variance float64) {
+	weights = checkWeights(n, weights)
+	estimate = fn(weights)
+	vals := JackknifeValues(nil, fn, weights)
+	m := stat.Mean(vals, nil)
+	for _, v := range vals {
+		d := v - m
+		variance += d * d
+	}
+	nf := float64(n)
+	return estimate, (nf - 1) * (m - estimate), (nf - 1) / nf * variance
+}
@@ -0,0 +1,54 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package resample
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/stat"
+)
+
+func TestJackknife(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const (
+		n   = 40
+		tol = 1e-12
+	)
+	x := make([]float64, n)
+	for i := range x {
+		x[i] = rnd.NormFloat64()*2 + 1
+	}
+	s2 := stat.Variance(x, nil)
+
+	// The jackknife standard error of the mean is the usual
+	// standard error and its bias is zero.
+	mean := func(w []float64) float64 { return stat.Mean(x, w) }
+	est, bias, variance := Jackknife(n, mean, nil)
+	if !floats.EqualWithinAbsOrRel(est, stat.Mean(x, nil), tol, tol) {
+		t.Errorf("unexpected estimate: got:%v want:%v", est, stat.Mean(x, nil))
+	}
+	if math.Abs(bias) > tol {
+		t.Errorf("unexpected bias of mean: got:%v want:0", bias)
+	}
+	if !floats.EqualWithinAbsOrRel(variance, s2/n, tol, tol) {
+		t.Errorf("unexpected variance of mean: got:%v want:%v", variance, s2/n)
+	}
+
+	// Jackknife bias correction of the plug-in variance
+	// gives the unbiased variance.
+	plugin := func(w []float64) float64 {
+		sw := floats.Sum(w)
+		return stat.Variance(x, w) * (sw - 1) / sw
+	}
+	est, bias, _ = Jackknife(n, plugin, nil)
+	if !floats.EqualWithinAbsOrRel(est-bias, s2, tol, tol) {
+		t.Errorf("unexpected bias corrected variance: got:%v want:%v", est-bias, s2)
+	}
+}
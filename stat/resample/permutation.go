@@ -0,0 +1,72 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package resample
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/stat/combin"
+	"gonum.org/v1/gonum/stat/hypothesis"
+)
+
+// PermutationTest performs a Monte Carlo permutation test. The test statistic
+// fn is evaluated for a permutation perm of the n observations, where perm[i]
+// is the index of the observation taking the place of observation i, for
+// example by assigning the group label of observation i to observation
+// perm[i]. The observed statistic is fn evaluated at the identity
+// permutation. The null distribution of the statistic is estimated from the
+// given number of uniformly random permutations drawn using src, or the
+// global source if src is nil, and evaluated concurrently. The fn function
+// must be safe to call concurrently and must not retain or modify perm.
+//
+// The p-value for the alternative alt counts the observed statistic as one
+// of the permutations,
+//  p = (1 + #{T* ≥ T}) / (1 + permutations)
+// for the Greater alternative, with the Less alternative defined similarly
+// and the two-sided p-value twice the smaller of the one-sided values.
+func PermutationTest(n, permutations int, fn func(perm []int) float64, alt hypothesis.Alternative, src rand.Source) hypothesis.Result {
+	perm := make([]int, n)
+	for i := range perm {
+		perm[i] = i
+	}
+	observed := fn(perm)
+
+	vals := make([]float64, permutations)
+	parallelRand(permutations, src, func() func(i int, rnd *rand.Rand) {
+		p := make([]int, n)
+		return func(i int, rnd *rand.Rand) {
+			combin.NewUniformPermutation(rnd).Permute(p)
+			vals[i] = fn(p)
+		}
+	})
+
+	var less, greater float64
+	for _, v := range vals {
+		if v <= observed {
+			less++
+		}
+		if v >= observed {
+			greater++
+		}
+	}
+	total := float64(permutations) + 1
+	lower := (less + 1) / total
+	upper := (greater + 1) / total
+
+	var pv float64
+	switch alt {
+	case hypothesis.TwoSided:
+		pv = math.Min(1, 2*math.Min(lower, upper))
+	case hypothesis.Less:
+		pv = lower
+	case hypothesis.Greater:
+		pv = upper
+	default:
+		panic("resample: unknown alternative")
+	}
+	return hypothesis.Result{Statistic: observed, PValue: pv}
+}
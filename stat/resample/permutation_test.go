@@ -0,0 +1,56 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package resample
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/hypothesis"
+)
+
+func TestPermutationTest(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const n = 30
+	for _, shift := range []float64{0, 0.5, 1} {
+		x := make([]float64, n)
+		y := make([]float64, n)
+		for i := range x {
+			x[i] = rnd.NormFloat64()
+			y[i] = rnd.NormFloat64() + shift
+		}
+		all := append(append([]float64(nil), x...), y...)
+
+		// The statistic is the difference in means of the second
+		// and first groups after permuting the pooled observations.
+		diff := func(perm []int) float64 {
+			var sx, sy float64
+			for i, p := range perm {
+				if i < n {
+					sx += all[p]
+				} else {
+					sy += all[p]
+				}
+			}
+			return (sy - sx) / n
+		}
+		for _, alt := range []hypothesis.Alternative{hypothesis.TwoSided, hypothesis.Less, hypothesis.Greater} {
+			got := PermutationTest(2*n, 5000, diff, alt, rand.NewSource(2))
+			if want := stat.Mean(y, nil) - stat.Mean(x, nil); math.Abs(got.Statistic-want) > 1e-12 {
+				t.Errorf("unexpected statistic for shift=%v: got:%v want:%v", shift, got.Statistic, want)
+			}
+			// The permutation p-value is close to that of the
+			// two sample t-test for normal data.
+			want := hypothesis.TwoSampleT(y, nil, x, nil, alt)
+			if math.Abs(got.PValue-want.PValue) > 0.02+0.2*want.PValue {
+				t.Errorf("unexpected p-value for shift=%v alt=%v: got:%v want:≈%v", shift, alt, got.PValue, want.PValue)
+			}
+		}
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package resample
+
+import (
+	"golang.org/x/exp/rand"
+)
+
+// Resampler generates resamples of a set of observations.
+type Resampler interface {
+	// Resample sets counts[i] to the number of times observation i
+	// appears in a resample of len(counts) observations drawn using
+	// the random number generator rnd.
+	Resample(counts []float64, rnd *rand.Rand)
+}
+
+// sequenceResampler is a Resampler that can resample a sequence of
+// observations while retaining the structure used by the Resampler.
+// It is used to draw the nested resamples of a bootstrap replicate.
+type sequenceResampler interface {
+	Resampler
+
+	// resampleSequence sets dst to a resample of the observation
+	// indices held in src, in draw order, drawn using the random
+	// number generator rnd. The lengths of dst and src must match.
+	resampleSequence(dst, src []int, rnd *rand.Rand)
+}
+
+// Nonparametric is the ordinary nonparametric bootstrap resampler. Each
+// resample draws observations uniformly with replacement.
+type Nonparametric struct{}
+
+// Resample implements the Resampler interface.
+func (Nonparametric) Resample(counts []float64, rnd *rand.Rand) {
+	zero(counts)
+	n := len(counts)
+	for i := 0; i < n; i++ {
+		counts[rnd.Intn(n)]++
+	}
+}
+
+func (Nonparametric) resampleSequence(dst, src []int, rnd *rand.Rand) {
+	if len(dst) != len(src) {
+		panic(badLength)
+	}
+	for i := range dst {
+		dst[i] = src[rnd.Intn(len(src))]
+	}
+}
+
+// Block is the moving block bootstrap resampler for stationary dependent
+// sequences. Each resample is formed from blocks of Length consecutive
+// observations with uniformly chosen starting points, with the final block
+// truncated so that the resample has the length of the sequence. If Circular
+// is true, blocks wrap from the end of the sequence to its start.
+//
+// Since a resample is represented by observation counts, the order of the
+// observations within a resample is not retained. Block is therefore
+// suitable for statistics, such as the mean and quantiles, that depend only
+// on the weighted empirical distribution of the observations; the blocks
+// preserve the dependence structure that determines the variability of
+// such statistics.
+type Block struct {
+	Length   int
+	Circular bool
+}
+
+// Resample implements the Resampler interface. Resample panics if
+// b.Length is not positive or, if b.Circular is false, is greater than
+// len(counts).
+func (b Block) Resample(counts []float64, rnd *rand.Rand) {
+	n := len(counts)
+	if b.Length < 1 || (!b.Circular && b.Length > n) {
+		panic("resample: bad block length")
+	}
+	zero(counts)
+	starts := n
+	if !b.Circular {
+		starts = n - b.Length + 1
+	}
+	for drawn := 0; drawn < n; {
+		s := rnd.Intn(starts)
+		for j := 0; j < b.Length && drawn < n; j++ {
+			counts[(s+j)%n]++
+			drawn++
+		}
+	}
+}
+
+func (b Block) resampleSequence(dst, src []int, rnd *rand.Rand) {
+	if len(dst) != len(src) {
+		panic(badLength)
+	}
+	n := len(src)
+	if b.Length < 1 || (!b.Circular && b.Length > n) {
+		panic("resample: bad block length")
+	}
+	starts := n
+	if !b.Circular {
+		starts = n - b.Length + 1
+	}
+	for drawn := 0; drawn < n; {
+		s := rnd.Intn(starts)
+		for j := 0; j < b.Length && drawn < n; j++ {
+			dst[drawn] = src[(s+j)%n]
+			drawn++
+		}
+	}
+}
+
+// Stratified is the stratified bootstrap resampler. Each resample draws
+// observations uniformly with replacement within each stratum, keeping
+// the number of observations in each stratum fixed.
+type Stratified struct {
+	strata [][]int
+	label  []int
+}
+
+// NewStratified returns a stratified resampler where observation i belongs
+// to stratum labels[i].
+func NewStratified(labels []int) Stratified {
+	idx := make(map[int]int)
+	var strata [][]int
+	label := make([]int, len(labels))
+	for i, l := range labels {
+		s, ok := idx[l]
+		if !ok {
+			s = len(strata)
+			idx[l] = s
+			strata = append(strata, nil)
+		}
+		strata[s] = append(strata[s], i)
+		label[i] = s
+	}
+	return Stratified{strata: strata, label: label}
+}
+
+// Resample implements the Resampler interface. Resample panics if the
+// length of counts is not the number of labels used to construct s.
+func (s Stratified) Resample(counts []float64, rnd *rand.Rand) {
+	if len(counts) != len(s.label) {
+		panic(badLength)
+	}
+	zero(counts)
+	for _, members := range s.strata {
+		for range members {
+			counts[members[rnd.Intn(len(members))]]++
+		}
+	}
+}
+
+// resampleSequence draws the resample of each stratum from the
+// observations of src in that stratum, keeping the number drawn
+// from each stratum equal to the number in src.
+func (s Stratified) resampleSequence(dst, src []int, rnd *rand.Rand) {
+	if len(dst) != len(src) {
+		panic(badLength)
+	}
+	members := make([][]int, len(s.strata))
+	for _, o := range src {
+		k := s.label[o]
+		members[k] = append(members[k], o)
+	}
+	var drawn int
+	for _, m := range members {
+		for range m {
+			dst[drawn] = m[rnd.Intn(len(m))]
+			drawn++
+		}
+	}
+}
+
+func zero(f []float64) {
+	for i := range f {
+		f[i] = 0
+	}
+}
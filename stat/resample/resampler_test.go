@@ -0,0 +1,84 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package resample
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+func TestResamplers(t *testing.T) {
+	t.Parallel()
+	const n = 23
+	labels := make([]int, n)
+	for i := range labels {
+		labels[i] = i % 3
+	}
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		name string
+		r    Resampler
+	}{
+		{name: "nonparametric", r: Nonparametric{}},
+		{name: "block", r: Block{Length: 5}},
+		{name: "circular block", r: Block{Length: 5, Circular: true}},
+		{name: "stratified", r: NewStratified(labels)},
+	} {
+		counts := make([]float64, n)
+		hits := make([]float64, n)
+		const reps = 2000
+		for k := 0; k < reps; k++ {
+			test.r.Resample(counts, rnd)
+			if sum := floats.Sum(counts); sum != n {
+				t.Fatalf("unexpected resample size for %s: got:%v want:%d", test.name, sum, n)
+			}
+			for _, c := range counts {
+				if c < 0 || c != float64(int(c)) {
+					t.Fatalf("invalid count for %s: %v", test.name, c)
+				}
+			}
+			floats.Add(hits, counts)
+			if s, ok := test.r.(Stratified); ok {
+				for _, members := range s.strata {
+					var sum float64
+					for _, i := range members {
+						sum += counts[i]
+					}
+					if sum != float64(len(members)) {
+						t.Fatalf("unexpected stratum size for %s: got:%v want:%d", test.name, sum, len(members))
+					}
+				}
+			}
+		}
+		// All observations are drawn on average once per resample,
+		// except for the moving block bootstrap which under-represents
+		// the ends of the sequence.
+		if b, ok := test.r.(Block); ok && !b.Circular {
+			continue
+		}
+		for i, h := range hits {
+			if mean := h / reps; mean < 0.85 || 1.15 < mean {
+				t.Errorf("unexpected mean count for %s observation %d: got:%v want:1", test.name, i, mean)
+			}
+		}
+	}
+}
+
+func TestBlockContiguous(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	counts := make([]float64, 20)
+	for k := 0; k < 100; k++ {
+		Block{Length: 20}.Resample(counts, rnd)
+		for i, c := range counts {
+			if c != 1 {
+				t.Fatalf("unexpected count for single block at %d: got:%v want:1", i, c)
+			}
+		}
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package samplemv
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// ChainStats holds statistics of a Markov chain Monte Carlo run. Statistics
+// are computed over the iterations after burn-in. The mean statistics are
+// zero if no states were kept.
+type ChainStats struct {
+	// AcceptRate is the mean acceptance probability of
+	// the proposals. For samplers that always move it is 1.
+	AcceptRate float64
+
+	// Evaluations is the number of evaluations of the
+	// target density, including burn-in.
+	Evaluations int
+
+	// StepSize is the step size used after burn-in by
+	// the Hamiltonian samplers.
+	StepSize float64
+
+	// Divergences is the number of divergent trajectories
+	// of the Hamiltonian samplers.
+	Divergences int
+
+	// MeanTreeDepth is the mean depth of the trajectory
+	// trees built by NUTS.
+	MeanTreeDepth float64
+}
+
+// runChain fills the rows of batch with the states of the Markov chain
+// started at initial and advanced by step. The first burnIn states are
+// discarded and rate-1 states are discarded between each kept state,
+// matching the behavior of MetropolisHastingser.
+// The iteration index passed to step counts from zero at the start of
+// burn-in.
+func runChain(batch *mat.Dense, initial []float64, burnIn, rate int, step func(x []float64, iter int)) {
+	r, c := batch.Dims()
+	if len(initial) != c {
+		panic(errLengthMismatch)
+	}
+	if rate == 0 {
+		rate = 1
+	}
+	x := make([]float64, c)
+	copy(x, initial)
+	iter := 0
+	for ; iter < burnIn; iter++ {
+		step(x, iter)
+	}
+	for i := 0; i < r; i++ {
+		n := rate
+		if i == 0 {
+			n = 1
+		}
+		for k := 0; k < n; k++ {
+			step(x, iter)
+			iter++
+		}
+		batch.SetRow(i, x)
+	}
+}
+
+// dualAveraging implements the dual averaging step size adaptation of
+// Hoffman and Gelman, "The No-U-Turn Sampler", JMLR 15 (2014) 1593-1623.
+type dualAveraging struct {
+	target float64
+	mu     float64
+	logEps float64
+	logBar float64
+	hBar   float64
+	t      float64
+}
+
+const (
+	daGamma = 0.05
+	daT0    = 10
+	daKappa = 0.75
+)
+
+func newDualAveraging(eps, target float64) *dualAveraging {
+	return &dualAveraging{target: target, mu: math.Log(10 * eps), logEps: math.Log(eps)}
+}
+
+// update updates the step size with the acceptance statistic of the last
+// iteration and returns the step size for the next iteration.
+func (d *dualAveraging) update(accept float64) float64 {
+	d.t++
+	w := 1 / (d.t + daT0)
+	d.hBar = (1-w)*d.hBar + w*(d.target-accept)
+	d.logEps = d.mu - math.Sqrt(d.t)/daGamma*d.hBar
+	eta := math.Pow(d.t, -daKappa)
+	d.logBar = eta*d.logEps + (1-eta)*d.logBar
+	return math.Exp(d.logEps)
+}
+
+// final returns the adapted step size.
+func (d *dualAveraging) final() float64 {
+	return math.Exp(d.logBar)
+}
+
+// adaptWindows returns the iterations at the end of each slow adaptation
+// window for the mass matrix during burn-in, and the first iteration of the
+// first window, following the schedule used by Stan. Short burn-in periods
+// use a single window.
+func adaptWindows(burnIn int) (start int, ends []int) {
+	const (
+		initBuf = 75
+		termBuf = 50
+		base    = 25
+	)
+	if burnIn < 20 {
+		return burnIn, nil
+	}
+	if burnIn < initBuf+termBuf+base {
+		start = burnIn * 15 / 100
+		end := burnIn - burnIn/10
+		return start, []int{end}
+	}
+	start = initBuf
+	end := burnIn - termBuf
+	size := base
+	for w := start; w < end; {
+		next := w + size
+		if next+2*size > end {
+			next = end
+		}
+		ends = append(ends, next)
+		w = next
+		size *= 2
+	}
+	return start, ends
+}
+
+// covAccumulator accumulates the running mean and covariance of states.
+type covAccumulator struct {
+	n    float64
+	mean []float64
+	m2   *mat.SymDense
+	diff []float64
+}
+
+func newCovAccumulator(dim int) *covAccumulator {
+	return &covAccumulator{
+		mean: make([]float64, dim),
+		m2:   mat.NewSymDense(dim, nil),
+		diff: make([]float64, dim),
+	}
+}
+
+func (c *covAccumulator) reset() {
+	c.n = 0
+	for i := range c.mean {
+		c.mean[i] = 0
+	}
+	c.m2.Zero()
+}
+
+// add adds x to the accumulator using Welford's update.
+func (c *covAccumulator) add(x []float64) {
+	c.n++
+	floats.SubTo(c.diff, x, c.mean)
+	floats.AddScaled(c.mean, 1/c.n, c.diff)
+	// m2 += (n-1)/n * diff diffᵀ
+	c.m2.SymRankOne(c.m2, (c.n-1)/c.n, mat.NewVecDense(len(c.diff), c.diff))
+}
+
+// covariance stores the sample covariance in dst.
+func (c *covAccumulator) covariance(dst *mat.SymDense) {
+	dst.ScaleSym(1/(c.n-1), c.m2)
+}
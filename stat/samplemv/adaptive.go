@@ -0,0 +1,137 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package samplemv
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distmv"
+)
+
+var _ Sampler = (*AdaptiveMetropolis)(nil)
+
+// AdaptiveMetropolis is a type for generating samples using the adaptive
+// Metropolis algorithm of Haario, Saksman and Tamminen, "An adaptive Metropolis
+// algorithm", Bernoulli 7 (2001) 223-242, with the given target distribution,
+// starting at the location specified by Initial. If Src is not nil it will be
+// used to generate random numbers, otherwise rand.Float64 will be used.
+//
+// The proposal distribution is a normal distribution centered at the current
+// location. For the first AdaptStart iterations its covariance is InitialCov,
+// and after that it is
+//  2.38^2/d * (Σ + Epsilon*I)
+// where Σ is the sample covariance of all previous states of the chain,
+// including burn-in, and d is the dimension. If InitialCov is nil, 0.01/d*I
+// is used. If AdaptStart is zero it is 2*d, and if Epsilon is zero it is 1e-6.
+// Rate specifies the thinning of the chain as for MetropolisHastingser.
+//
+// Sample sets Stats to the statistics of the run. The Initial location is not
+// changed during calls to Sample.
+type AdaptiveMetropolis struct {
+	Initial    []float64
+	Target     distmv.LogProber
+	InitialCov *mat.SymDense
+	AdaptStart int
+	Epsilon    float64
+	Src        rand.Source
+
+	BurnIn int
+	Rate   int
+
+	Stats ChainStats
+}
+
+// Sample generates rows(batch) samples using adaptive Metropolis.
+// The number of columns in batch must equal len(a.Initial), otherwise Sample
+// will panic.
+func (a *AdaptiveMetropolis) Sample(batch *mat.Dense) {
+	f64 := rand.Float64
+	norm := rand.NormFloat64
+	if a.Src != nil {
+		rnd := rand.New(a.Src)
+		f64 = rnd.Float64
+		norm = rnd.NormFloat64
+	}
+	dim := len(a.Initial)
+	start := a.AdaptStart
+	if start == 0 {
+		start = 2 * dim
+	}
+	eps := a.Epsilon
+	if eps == 0 {
+		eps = 1e-6
+	}
+	scale := 2.38 * 2.38 / float64(dim)
+
+	cov := mat.NewSymDense(dim, nil)
+	if a.InitialCov == nil {
+		for i := 0; i < dim; i++ {
+			cov.SetSym(i, i, 0.01/float64(dim))
+		}
+	} else {
+		if a.InitialCov.Symmetric() != dim {
+			panic(errLengthMismatch)
+		}
+		cov.CopySym(a.InitialCov)
+	}
+	var chol mat.Cholesky
+	if !chol.Factorize(cov) {
+		panic("samplemv: initial covariance not positive definite")
+	}
+	var l mat.TriDense
+	chol.LTo(&l)
+
+	acc := newCovAccumulator(dim)
+	z := mat.NewVecDense(dim, nil)
+	step := mat.NewVecDense(dim, nil)
+	proposed := make([]float64, dim)
+	var logp float64
+	var evals, kept int
+	var sumAccept float64
+	runChain(batch, a.Initial, a.BurnIn, a.Rate, func(x []float64, iter int) {
+		if iter == 0 {
+			logp = a.Target.LogProb(x)
+			evals++
+			acc.add(x)
+		}
+		if iter >= start {
+			acc.covariance(cov)
+			cov.ScaleSym(scale, cov)
+			for i := 0; i < dim; i++ {
+				cov.SetSym(i, i, cov.At(i, i)+scale*eps)
+			}
+			if chol.Factorize(cov) {
+				chol.LTo(&l)
+			}
+		}
+
+		for i := 0; i < dim; i++ {
+			z.SetVec(i, norm())
+		}
+		step.MulVec(&l, z)
+		for i, v := range x {
+			proposed[i] = v + step.AtVec(i)
+		}
+		lp := a.Target.LogProb(proposed)
+		evals++
+		accept := math.Min(1, math.Exp(lp-logp))
+		if f64() < accept {
+			copy(x, proposed)
+			logp = lp
+		}
+		acc.add(x)
+		if iter >= a.BurnIn {
+			sumAccept += accept
+			kept++
+		}
+	})
+	a.Stats = ChainStats{Evaluations: evals}
+	if kept != 0 {
+		a.Stats.AcceptRate = sumAccept / float64(kept)
+	}
+}
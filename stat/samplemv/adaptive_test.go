@@ -0,0 +1,33 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package samplemv
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestAdaptiveMetropolis(t *testing.T) {
+	t.Parallel()
+	target := correlatedNormal(t)
+	a := &AdaptiveMetropolis{
+		Initial: make([]float64, 3),
+		Target:  target,
+		Src:     rand.NewSource(1),
+		BurnIn:  5000,
+	}
+	batch := mat.NewDense(50000, 3, nil)
+	a.Sample(batch)
+	compareNormal(t, target, batch, nil, 0.1, 0.2)
+
+	// The optimal scaling gives an acceptance rate of about
+	// 0.3 for a three-dimensional normal target.
+	if a.Stats.AcceptRate < 0.2 || a.Stats.AcceptRate > 0.45 {
+		t.Errorf("unexpected acceptance rate: %v", a.Stats.AcceptRate)
+	}
+}
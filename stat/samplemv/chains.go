@@ -0,0 +1,43 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package samplemv
+
+import (
+	"sync"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// SampleChains runs len(chains) independent Markov chains concurrently,
+// storing the draws of chain i in chains[i]. The sampler for chain i is
+// returned by the call newSampler(i, src), where src is a source of random
+// numbers used only by that chain. The sources are seeded from src if it
+// is not nil, otherwise from rand.Uint64, so that a run is reproducible for
+// a given src.
+//
+// The samplers returned by newSampler must not share state. The chains can
+// then be passed to the convergence diagnostics, such as SplitRHat and
+// BulkESS.
+func SampleChains(chains []*mat.Dense, newSampler func(i int, src rand.Source) Sampler, src rand.Source) {
+	u64 := rand.Uint64
+	if src != nil {
+		u64 = rand.New(src).Uint64
+	}
+	samplers := make([]Sampler, len(chains))
+	for i := range chains {
+		samplers[i] = newSampler(i, rand.NewSource(u64()))
+	}
+	var wg sync.WaitGroup
+	wg.Add(len(chains))
+	for i, s := range samplers {
+		go func(s Sampler, batch *mat.Dense) {
+			defer wg.Done()
+			s.Sample(batch)
+		}(s, chains[i])
+	}
+	wg.Wait()
+}
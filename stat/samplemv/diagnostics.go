@@ -0,0 +1,347 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package samplemv
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+	"gonum.org/v1/gonum/stat/timeseries"
+)
+
+// The convergence diagnostics in this file follow Vehtari, Gelman, Simpson,
+// Carpenter and Bürkner, "Rank-normalization, folding, and localization: An
+// improved R̂ for assessing convergence of MCMC", Bayesian Analysis 16 (2021)
+// 667-718. Each chain is held in the rows of a *mat.Dense with one column per
+// parameter, and the diagnostics are computed for each column.
+
+const (
+	errNoChains     = "samplemv: no chains"
+	errChainDims    = "samplemv: chain dimension mismatch"
+	errShortChain   = "samplemv: chain too short"
+	errBadFractions = "samplemv: bad Geweke fractions"
+)
+
+// SplitRHat computes the rank-normalized split-R̂ convergence diagnostic for
+// each parameter of the chains and stores it in dst. Each chain is split into
+// two halves and R̂ is computed from the between- and within-chain variances
+// of the rank-normalized draws and of the rank-normalized draws folded about
+// the median, and the maximum of the two is returned. Values close to 1
+// indicate convergence; values above 1.01 suggest the chains have not mixed.
+//
+// All chains must have the same dimensions and at least four draws. If dst is
+// nil, a new slice is allocated and returned. If dst is not nil, its length must
+// equal the number of columns of the chains.
+func SplitRHat(dst []float64, chains []*mat.Dense) []float64 {
+	dst = diagnose(dst, chains, func(split [][]float64) float64 {
+		bulk := rhat(rankNormalize(split))
+		tail := rhat(rankNormalize(fold(split)))
+		return math.Max(bulk, tail)
+	})
+	return dst
+}
+
+// ESS computes the effective sample size of the mean of each parameter of the
+// chains and stores it in dst. The effective sample size is computed from the
+// split chains using Geyer's initial monotone sequence estimator of the
+// integrated autocorrelation time, combining the autocorrelations of the
+// chains as done by Stan.
+//
+// All chains must have the same dimensions and at least four draws. If dst is
+// nil, a new slice is allocated and returned. If dst is not nil, its length must
+// equal the number of columns of the chains.
+func ESS(dst []float64, chains []*mat.Dense) []float64 {
+	return diagnose(dst, chains, ess)
+}
+
+// BulkESS computes the bulk effective sample size of each parameter of the
+// chains and stores it in dst. The bulk effective sample size is the effective
+// sample size of the rank-normalized split chains and measures the efficiency
+// of estimates of the center of the distribution.
+//
+// All chains must have the same dimensions and at least four draws. If dst is
+// nil, a new slice is allocated and returned. If dst is not nil, its length must
+// equal the number of columns of the chains.
+func BulkESS(dst []float64, chains []*mat.Dense) []float64 {
+	return diagnose(dst, chains, func(split [][]float64) float64 {
+		return ess(rankNormalize(split))
+	})
+}
+
+// TailESS computes the tail effective sample size of each parameter of the
+// chains and stores it in dst. The tail effective sample size is the minimum
+// of the effective sample sizes of the indicators of the draws being below
+// the 5% and 95% quantiles, and measures the efficiency of estimates of the
+// tails of the distribution.
+//
+// All chains must have the same dimensions and at least four draws. If dst is
+// nil, a new slice is allocated and returned. If dst is not nil, its length must
+// equal the number of columns of the chains.
+func TailESS(dst []float64, chains []*mat.Dense) []float64 {
+	return diagnose(dst, chains, func(split [][]float64) float64 {
+		return math.Min(ess(indicator(split, 0.05)), ess(indicator(split, 0.95)))
+	})
+}
+
+// MCSE computes the Monte Carlo standard error of the mean of each parameter
+// of the chains and stores it in dst. The standard error is
+//  σ/sqrt(ESS)
+// where σ is the standard deviation of all draws and ESS is the effective
+// sample size computed by ESS.
+//
+// All chains must have the same dimensions and at least four draws. If dst is
+// nil, a new slice is allocated and returned. If dst is not nil, its length must
+// equal the number of columns of the chains.
+func MCSE(dst []float64, chains []*mat.Dense) []float64 {
+	return diagnose(dst, chains, func(split [][]float64) float64 {
+		var all []float64
+		for _, c := range split {
+			all = append(all, c...)
+		}
+		return stat.StdDev(all, nil) / math.Sqrt(ess(split))
+	})
+}
+
+// Geweke computes the Geweke convergence diagnostic for each parameter of
+// the chain in batch and stores it in dst. The diagnostic compares the mean
+// of the first fraction first of the draws with the mean of the last fraction
+// last of the draws,
+//  z = (x̄_A - x̄_B) / sqrt(V_A + V_B)
+// where V_A and V_B are the squared Monte Carlo standard errors of the means
+// of the two windows estimated from their autocorrelations. If the chain is
+// stationary z is asymptotically standard normal. The fractions conventionally
+// used are first = 0.1 and last = 0.5.
+//
+// Geweke panics if first or last is not in (0, 1) or if first+last > 1, or if
+// either window contains fewer than four draws. If dst is nil, a new slice is
+// allocated and returned. If dst is not nil, its length must equal the number
+// of columns of batch.
+func Geweke(dst []float64, batch *mat.Dense, first, last float64) []float64 {
+	if first <= 0 || last <= 0 || first >= 1 || last >= 1 || first+last > 1 {
+		panic(errBadFractions)
+	}
+	r, c := batch.Dims()
+	na := int(first * float64(r))
+	nb := int(last * float64(r))
+	if na < 4 || nb < 4 {
+		panic(errShortChain)
+	}
+	dst = checkDiagDst(dst, c)
+	for j := 0; j < c; j++ {
+		col := mat.Col(nil, j, batch)
+		a := col[:na]
+		b := col[r-nb:]
+		va := varianceOfMean(a)
+		vb := varianceOfMean(b)
+		dst[j] = (stat.Mean(a, nil) - stat.Mean(b, nil)) / math.Sqrt(va+vb)
+	}
+	return dst
+}
+
+// varianceOfMean returns the squared Monte Carlo standard error of the
+// mean of the single chain x.
+func varianceOfMean(x []float64) float64 {
+	return stat.Variance(x, nil) / ess([][]float64{x})
+}
+
+func checkDiagDst(dst []float64, c int) []float64 {
+	if dst == nil {
+		return make([]float64, c)
+	}
+	if len(dst) != c {
+		panic(errLengthMismatch)
+	}
+	return dst
+}
+
+// diagnose computes fn over the split chains of each parameter.
+func diagnose(dst []float64, chains []*mat.Dense, fn func(split [][]float64) float64) []float64 {
+	if len(chains) == 0 {
+		panic(errNoChains)
+	}
+	r, c := chains[0].Dims()
+	for _, ch := range chains[1:] {
+		rc, cc := ch.Dims()
+		if rc != r || cc != c {
+			panic(errChainDims)
+		}
+	}
+	if r < 4 {
+		panic(errShortChain)
+	}
+	dst = checkDiagDst(dst, c)
+	half := r / 2
+	split := make([][]float64, 2*len(chains))
+	col := make([]float64, r)
+	for j := 0; j < c; j++ {
+		for i, ch := range chains {
+			mat.Col(col, j, ch)
+			// Drop the middle draw of chains with odd length.
+			split[2*i] = append(split[2*i][:0], col[:half]...)
+			split[2*i+1] = append(split[2*i+1][:0], col[r-half:]...)
+		}
+		dst[j] = fn(split)
+	}
+	return dst
+}
+
+// rhat returns the potential scale reduction factor of the chains.
+func rhat(chains [][]float64) float64 {
+	m := float64(len(chains))
+	n := float64(len(chains[0]))
+	means := make([]float64, len(chains))
+	var w float64
+	for i, c := range chains {
+		var v float64
+		means[i], v = stat.MeanVariance(c, nil)
+		w += v
+	}
+	w /= m
+	b := n * stat.Variance(means, nil)
+	varPlus := (n-1)/n*w + b/n
+	return math.Sqrt(varPlus / w)
+}
+
+// ess returns the effective sample size of the mean of the chains.
+func ess(chains [][]float64) float64 {
+	m := len(chains)
+	n := len(chains[0])
+	acov := make([][]float64, m)
+	means := make([]float64, m)
+	var w float64
+	for i, c := range chains {
+		acov[i] = timeseries.Autocovariance(nil, c, n-1)
+		means[i] = stat.Mean(c, nil)
+		w += acov[i][0] * float64(n) / float64(n-1)
+	}
+	w /= float64(m)
+	varPlus := w * float64(n-1) / float64(n)
+	if m > 1 {
+		varPlus += stat.Variance(means, nil)
+	}
+	meanAcov := func(t int) float64 {
+		var s float64
+		for _, a := range acov {
+			s += a[t]
+		}
+		return s / float64(m)
+	}
+
+	// Geyer's initial positive sequence.
+	rho := make([]float64, n)
+	rho[0] = 1
+	even := 1.0
+	odd := 1 - (w-meanAcov(1))/varPlus
+	rho[1] = odd
+	t := 1
+	for t < n-4 && even+odd > 0 {
+		even = 1 - (w-meanAcov(t+1))/varPlus
+		odd = 1 - (w-meanAcov(t+2))/varPlus
+		if even+odd >= 0 {
+			rho[t+1] = even
+			rho[t+2] = odd
+		}
+		t += 2
+	}
+	maxT := t
+	if even > 0 && maxT+1 < n {
+		rho[maxT+1] = even
+	}
+
+	// Geyer's initial monotone sequence.
+	for t := 1; t <= maxT-2; t += 2 {
+		if rho[t+1]+rho[t+2] > rho[t-1]+rho[t] {
+			rho[t+1] = (rho[t-1] + rho[t]) / 2
+			rho[t+2] = rho[t+1]
+		}
+	}
+
+	total := float64(m * n)
+	tau := -1.0
+	for _, r := range rho[:maxT+1] {
+		tau += 2 * r
+	}
+	if maxT+1 < n {
+		tau += rho[maxT+1]
+	}
+	tau = math.Max(tau, 1/math.Log10(total))
+	return total / tau
+}
+
+// rankNormalize returns the normal scores of the pooled ranks of the
+// draws in chains, with tied draws given their average rank.
+func rankNormalize(chains [][]float64) [][]float64 {
+	type draw struct {
+		v    float64
+		i, j int
+	}
+	var all []draw
+	for i, c := range chains {
+		for j, v := range c {
+			all = append(all, draw{v: v, i: i, j: j})
+		}
+	}
+	sort.Slice(all, func(a, b int) bool { return all[a].v < all[b].v })
+	s := float64(len(all))
+	z := make([][]float64, len(chains))
+	for i, c := range chains {
+		z[i] = make([]float64, len(c))
+	}
+	for lo := 0; lo < len(all); {
+		hi := lo + 1
+		for hi < len(all) && all[hi].v == all[lo].v {
+			hi++
+		}
+		// Ranks are one-based and ties share their average.
+		rank := float64(lo+hi+1) / 2
+		v := distuv.UnitNormal.Quantile((rank - 3.0/8) / (s + 1.0/4))
+		for _, d := range all[lo:hi] {
+			z[d.i][d.j] = v
+		}
+		lo = hi
+	}
+	return z
+}
+
+// fold returns the absolute deviations of the draws from their pooled median.
+func fold(chains [][]float64) [][]float64 {
+	med := pooledQuantile(chains, 0.5)
+	f := make([][]float64, len(chains))
+	for i, c := range chains {
+		f[i] = make([]float64, len(c))
+		for j, v := range c {
+			f[i][j] = math.Abs(v - med)
+		}
+	}
+	return f
+}
+
+// indicator returns the indicators of the draws being at most the pooled
+// p quantile.
+func indicator(chains [][]float64, p float64) [][]float64 {
+	q := pooledQuantile(chains, p)
+	f := make([][]float64, len(chains))
+	for i, c := range chains {
+		f[i] = make([]float64, len(c))
+		for j, v := range c {
+			if v <= q {
+				f[i][j] = 1
+			}
+		}
+	}
+	return f
+}
+
+func pooledQuantile(chains [][]float64, p float64) float64 {
+	var all []float64
+	for _, c := range chains {
+		all = append(all, c...)
+	}
+	sort.Float64s(all)
+	return stat.Quantile(p, stat.LinInterp, all, nil)
+}
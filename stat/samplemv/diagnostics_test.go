@@ -0,0 +1,166 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package samplemv
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+)
+
+// ar1Chains returns m chains of n draws of two independent AR(1)
+// processes with unit marginal variance and coefficients phi, offsetting
+// the mean of chain i by shift*i.
+func ar1Chains(m, n int, phi [2]float64, shift float64, rnd *rand.Rand) []*mat.Dense {
+	chains := make([]*mat.Dense, m)
+	for i := range chains {
+		chains[i] = mat.NewDense(n, 2, nil)
+		for j, p := range phi {
+			x := rnd.NormFloat64()
+			s := math.Sqrt(1 - p*p)
+			for k := 0; k < n; k++ {
+				chains[i].Set(k, j, x+shift*float64(i))
+				x = p*x + s*rnd.NormFloat64()
+			}
+		}
+	}
+	return chains
+}
+
+func TestESS(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const m, n = 4, 5000
+	phi := [2]float64{0, 0.9}
+	chains := ar1Chains(m, n, phi, 0, rnd)
+	for _, test := range []struct {
+		name string
+		fn   func([]float64, []*mat.Dense) []float64
+	}{
+		{name: "ESS", fn: ESS},
+		{name: "BulkESS", fn: BulkESS},
+	} {
+		got := test.fn(nil, chains)
+		for j, p := range phi {
+			// The effective sample size of an AR(1) process.
+			want := m * n * (1 - p) / (1 + p)
+			if math.Abs(got[j]-want) > 0.15*want {
+				t.Errorf("unexpected %s for phi=%v: got %v, want %v", test.name, p, got[j], want)
+			}
+		}
+	}
+	tail := TailESS(nil, chains)
+	if tail[1] >= tail[0] {
+		t.Errorf("unexpected tail ESS ordering: %v", tail)
+	}
+
+	mcse := MCSE(nil, chains)
+	for j, p := range phi {
+		want := math.Sqrt((1 + p) / (1 - p) / (m * n))
+		if math.Abs(mcse[j]-want) > 0.1*want {
+			t.Errorf("unexpected MCSE for phi=%v: got %v, want %v", p, mcse[j], want)
+		}
+	}
+}
+
+func TestSplitRHat(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	converged := SplitRHat(nil, ar1Chains(4, 1000, [2]float64{0, 0.5}, 0, rnd))
+	for j, r := range converged {
+		if r > 1.01 || r < 0.99 {
+			t.Errorf("unexpected R̂ for converged chain %d: %v", j, r)
+		}
+	}
+	shifted := SplitRHat(nil, ar1Chains(4, 1000, [2]float64{0, 0.5}, 1, rnd))
+	for j, r := range shifted {
+		if r < 1.1 {
+			t.Errorf("unexpected R̂ for shifted chain %d: %v", j, r)
+		}
+	}
+
+	// A single chain with a trend should fail because
+	// of the split.
+	trend := mat.NewDense(1000, 1, nil)
+	for i := 0; i < 1000; i++ {
+		trend.Set(i, 0, float64(i)/500+rnd.NormFloat64())
+	}
+	if r := SplitRHat(nil, []*mat.Dense{trend})[0]; r < 1.1 {
+		t.Errorf("unexpected R̂ for trending chain: %v", r)
+	}
+
+	// Chains with the same location but different scales are
+	// detected by the folded R̂.
+	scaled := ar1Chains(4, 1000, [2]float64{0, 0}, 0, rnd)
+	scaled[0].Scale(4, scaled[0])
+	if r := SplitRHat(nil, scaled)[0]; r < 1.05 {
+		t.Errorf("unexpected R̂ for chains with different scales: %v", r)
+	}
+}
+
+func TestGeweke(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	var z []float64
+	for i := 0; i < 200; i++ {
+		chain := ar1Chains(1, 2000, [2]float64{0, 0.8}, 0, rnd)[0]
+		z = append(z, Geweke(nil, chain, 0.1, 0.5)...)
+	}
+	mean, std := stat.MeanStdDev(z, nil)
+	if math.Abs(mean) > 0.15 || math.Abs(std-1) > 0.15 {
+		t.Errorf("Geweke scores not standard normal: mean=%v std=%v", mean, std)
+	}
+
+	trend := mat.NewDense(1000, 1, nil)
+	for i := 0; i < 1000; i++ {
+		trend.Set(i, 0, float64(i)/500+rnd.NormFloat64())
+	}
+	if z := Geweke(nil, trend, 0.1, 0.5)[0]; math.Abs(z) < 4 {
+		t.Errorf("unexpected Geweke score for trending chain: %v", z)
+	}
+}
+
+func TestSampleChains(t *testing.T) {
+	t.Parallel()
+	target := correlatedNormal(t)
+	run := func() []*mat.Dense {
+		chains := make([]*mat.Dense, 4)
+		for i := range chains {
+			chains[i] = mat.NewDense(1000, 3, nil)
+		}
+		SampleChains(chains, func(i int, src rand.Source) Sampler {
+			initial := []float64{float64(i), -float64(i), 0}
+			return &NUTS{Initial: initial, Target: target, Src: src, BurnIn: 500}
+		}, rand.NewSource(1))
+		return chains
+	}
+	a := run()
+	b := run()
+	for i := range a {
+		if !mat.Equal(a[i], b[i]) {
+			t.Errorf("chain %d not reproducible", i)
+		}
+		for j := i + 1; j < len(a); j++ {
+			if floats.Equal(a[i].RawRowView(0), a[j].RawRowView(0)) {
+				t.Errorf("chains %d and %d not independent", i, j)
+			}
+		}
+	}
+	for j, r := range SplitRHat(nil, a) {
+		if r > 1.01 {
+			t.Errorf("unexpected R̂ for parameter %d: %v", j, r)
+		}
+	}
+	for j, e := range BulkESS(nil, a) {
+		if e < 400 {
+			t.Errorf("unexpected bulk ESS for parameter %d: %v", j, e)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package samplemv
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// StarL2Discrepancy returns the L2-star discrepancy of the points in the rows
+// of x, which must lie in the unit hypercube. It is computed with Warnock's
+// formula
+//  D² = 3^-d - 2^(1-d)/n \sum_i \prod_k (1 - x_{ik}^2)
+//         + 1/n^2 \sum_{i,j} \prod_k (1 - max(x_{ik}, x_{jk}))
+// in O(n^2 d) time.
+func StarL2Discrepancy(x mat.Matrix) float64 {
+	n, d := x.Dims()
+	fd := float64(d)
+	fn := float64(n)
+	var s1, s2 float64
+	for i := 0; i < n; i++ {
+		p := 1.0
+		for k := 0; k < d; k++ {
+			v := x.At(i, k)
+			p *= 1 - v*v
+		}
+		s1 += p
+		for j := 0; j < n; j++ {
+			p := 1.0
+			for k := 0; k < d; k++ {
+				p *= 1 - math.Max(x.At(i, k), x.At(j, k))
+			}
+			s2 += p
+		}
+	}
+	return sqrtDiscrepancy(math.Pow(3, -fd) - math.Pow(2, 1-fd)/fn*s1 + s2/(fn*fn))
+}
+
+// CenteredL2Discrepancy returns the centered L2 discrepancy of Hickernell of
+// the points in the rows of x, which must lie in the unit hypercube. Unlike
+// the L2-star discrepancy, it is invariant to reflections of the coordinates
+// about 1/2. It is computed as
+//  D² = (13/12)^d - 2/n \sum_i \prod_k (1 + |z_{ik}|/2 - z_{ik}^2/2)
+//         + 1/n^2 \sum_{i,j} \prod_k (1 + |z_{ik}|/2 + |z_{jk}|/2 - |x_{ik} - x_{jk}|/2)
+// where z_{ik} = x_{ik} - 1/2.
+func CenteredL2Discrepancy(x mat.Matrix) float64 {
+	n, d := x.Dims()
+	fn := float64(n)
+	var s1, s2 float64
+	for i := 0; i < n; i++ {
+		p := 1.0
+		for k := 0; k < d; k++ {
+			z := math.Abs(x.At(i, k) - 0.5)
+			p *= 1 + z/2 - z*z/2
+		}
+		s1 += p
+		for j := 0; j < n; j++ {
+			p := 1.0
+			for k := 0; k < d; k++ {
+				xi, xj := x.At(i, k), x.At(j, k)
+				p *= 1 + math.Abs(xi-0.5)/2 + math.Abs(xj-0.5)/2 - math.Abs(xi-xj)/2
+			}
+			s2 += p
+		}
+	}
+	return sqrtDiscrepancy(math.Pow(13.0/12, float64(d)) - 2/fn*s1 + s2/(fn*fn))
+}
+
+// WrapAroundL2Discrepancy returns the wrap-around L2 discrepancy of Hickernell
+// of the points in the rows of x, which must lie in the unit hypercube. It is
+// invariant to shifts of the coordinates modulo 1. It is computed as
+//  D² = -(4/3)^d + 1/n^2 \sum_{i,j} \prod_k (3/2 - |x_{ik} - x_{jk}| (1 - |x_{ik} - x_{jk}|))
+func WrapAroundL2Discrepancy(x mat.Matrix) float64 {
+	n, d := x.Dims()
+	fn := float64(n)
+	var s float64
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			p := 1.0
+			for k := 0; k < d; k++ {
+				v := math.Abs(x.At(i, k) - x.At(j, k))
+				p *= 1.5 - v*(1-v)
+			}
+			s += p
+		}
+	}
+	return sqrtDiscrepancy(-math.Pow(4.0/3, float64(d)) + s/(fn*fn))
+}
+
+// sqrtDiscrepancy returns the square root of a squared discrepancy, which
+// may be slightly negative due to cancellation.
+func sqrtDiscrepancy(d2 float64) float64 {
+	return math.Sqrt(math.Max(d2, 0))
+}
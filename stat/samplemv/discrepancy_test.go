@@ -0,0 +1,102 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package samplemv
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distmv"
+)
+
+func TestStarL2Discrepancy(t *testing.T) {
+	t.Parallel()
+	// The L2-star discrepancy of a single point x in one dimension
+	// is the integral of (1{x ≤ t} - t)^2 over t.
+	for _, x := range []float64{0, 0.25, 0.5, 0.9, 1} {
+		want := math.Sqrt((x*x*x + (1-x)*(1-x)*(1-x)) / 3)
+		got := StarL2Discrepancy(mat.NewDense(1, 1, []float64{x}))
+		if math.Abs(got-want) > 1e-14 {
+			t.Errorf("unexpected discrepancy for point %v: got %v, want %v", x, got, want)
+		}
+	}
+
+	// Compare with midpoint integration of the squared local
+	// discrepancy in two dimensions.
+	rnd := rand.New(rand.NewSource(1))
+	pts := mat.NewDense(10, 2, nil)
+	for i := 0; i < 10; i++ {
+		pts.Set(i, 0, rnd.Float64())
+		pts.Set(i, 1, rnd.Float64())
+	}
+	const grid = 400
+	var sum float64
+	for a := 0; a < grid; a++ {
+		for b := 0; b < grid; b++ {
+			u := (float64(a) + 0.5) / grid
+			v := (float64(b) + 0.5) / grid
+			var count float64
+			for i := 0; i < 10; i++ {
+				if pts.At(i, 0) < u && pts.At(i, 1) < v {
+					count++
+				}
+			}
+			d := count/10 - u*v
+			sum += d * d
+		}
+	}
+	want := math.Sqrt(sum / (grid * grid))
+	got := StarL2Discrepancy(pts)
+	if math.Abs(got-want) > 1e-3 {
+		t.Errorf("unexpected discrepancy: got %v, want %v", got, want)
+	}
+}
+
+func TestDiscrepancyInvariance(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	pts := mat.NewDense(20, 3, nil)
+	reflected := mat.NewDense(20, 3, nil)
+	shifted := mat.NewDense(20, 3, nil)
+	for i := 0; i < 20; i++ {
+		for j := 0; j < 3; j++ {
+			x := rnd.Float64()
+			pts.Set(i, j, x)
+			reflected.Set(i, j, 1-x)
+			shifted.Set(i, j, math.Mod(x+0.3, 1))
+		}
+	}
+	if a, b := CenteredL2Discrepancy(pts), CenteredL2Discrepancy(reflected); math.Abs(a-b) > 1e-12 {
+		t.Errorf("centered discrepancy not reflection invariant: %v != %v", a, b)
+	}
+	if a, b := WrapAroundL2Discrepancy(pts), WrapAroundL2Discrepancy(shifted); math.Abs(a-b) > 1e-12 {
+		t.Errorf("wrap-around discrepancy not shift invariant: %v != %v", a, b)
+	}
+}
+
+func TestDiscrepancyOrdering(t *testing.T) {
+	t.Parallel()
+	const n, d = 256, 4
+	unif := distmv.NewUnitUniform(d, rand.NewSource(1))
+	random := mat.NewDense(n, d, nil)
+	IID{Dist: unif}.Sample(random)
+	sobol := mat.NewDense(n, d, nil)
+	Sobol{Scramble: OwenScramble, Q: unif, Src: rand.NewSource(1)}.Sample(sobol)
+	for _, test := range []struct {
+		name string
+		fn   func(mat.Matrix) float64
+	}{
+		{name: "star", fn: StarL2Discrepancy},
+		{name: "centered", fn: CenteredL2Discrepancy},
+		{name: "wrap-around", fn: WrapAroundL2Discrepancy},
+	} {
+		if test.fn(sobol) >= test.fn(random) {
+			t.Errorf("%s discrepancy of Sobol points not less than random points", test.name)
+		}
+	}
+}
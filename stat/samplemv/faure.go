@@ -0,0 +1,94 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package samplemv
+
+import (
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distmv"
+)
+
+var _ Sampler = Faure{}
+
+// Faure is a type for sampling using the Faure sequence from the given
+// distribution, as described in
+//  Discrépance de suites associées à un système de numération (en dimension s)
+//  Henri Faure
+//  Acta Arithmetica 41 (1982)
+// The sequence in d dimensions uses the smallest prime base b ≥ d, and the
+// generator matrix of coordinate j is the jth power of the Pascal matrix
+// modulo b. The first b^m points form a (0,m,d)-net in base b. Faure panics if
+// Q is nil.
+//
+// The first point of the sequence is the origin, which is mapped to -∞ by the
+// quantile function of distributions with unbounded support.
+type Faure struct {
+	Q distmv.Quantiler
+}
+
+// Sample generates rows(batch) samples using the Faure generation procedure.
+func (f Faure) Sample(batch *mat.Dense) {
+	if f.Q == nil {
+		panic("samplemv: nil quantiler")
+	}
+	n, d := batch.Dims()
+	b := 2
+	for i := 0; ; i++ {
+		b = nthPrime(i)
+		if b >= d {
+			break
+		}
+	}
+
+	// Find the number of base b digits needed.
+	digits := 1
+	for size := b; size < n; size *= b {
+		digits++
+	}
+
+	// binom holds the binomial coefficients modulo b.
+	binom := make([][]int, digits)
+	for k := range binom {
+		binom[k] = make([]int, k+1)
+		binom[k][0], binom[k][k] = 1, 1
+		for r := 1; r < k; r++ {
+			binom[k][r] = (binom[k-1][r-1] + binom[k-1][r]) % b
+		}
+	}
+
+	a := make([]int, digits)
+	y := make([]int, digits)
+	pow := make([]int, digits)
+	for j := 0; j < d; j++ {
+		// pow[t] = j^t mod b
+		pow[0] = 1
+		for t := 1; t < digits; t++ {
+			pow[t] = pow[t-1] * (j % b) % b
+		}
+		for i := 0; i < n; i++ {
+			for k, v := 0, i; k < digits; k++ {
+				a[k] = v % b
+				v /= b
+			}
+			// y_r = \sum_{k≥r} binom(k, r) j^(k-r) a_k mod b
+			for r := range y {
+				y[r] = 0
+				for k := r; k < digits; k++ {
+					y[r] += binom[k][r] * pow[k-r] * a[k]
+				}
+				y[r] %= b
+			}
+			var x float64
+			for r := digits - 1; r >= 0; r-- {
+				x = (x + float64(y[r])) / float64(b)
+			}
+			batch.Set(i, j, x)
+		}
+	}
+	p := make([]float64, d)
+	for i := 0; i < n; i++ {
+		copy(p, batch.RawRowView(i))
+		f.Q.Quantile(batch.RawRowView(i), p)
+	}
+}
@@ -0,0 +1,576 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package samplemv
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+var (
+	_ Sampler = (*HMC)(nil)
+	_ Sampler = (*NUTS)(nil)
+)
+
+// LogProbScorer is a log-density with a gradient. The distmv.Normal type
+// satisfies LogProbScorer.
+type LogProbScorer interface {
+	// LogProb returns the log of the (possibly unnormalized)
+	// probability density at x.
+	LogProb(x []float64) float64
+
+	// ScoreInput returns the gradient of the log-density with
+	// respect to the input x. If score is nil, a new slice is
+	// allocated and returned, otherwise the gradient is stored
+	// in-place into score.
+	ScoreInput(score, x []float64) []float64
+}
+
+// Metric specifies the adaptation of the mass matrix of a Hamiltonian sampler.
+type Metric int
+
+const (
+	// UnitMetric uses a fixed mass matrix.
+	UnitMetric Metric = iota
+	// DiagMetric adapts a diagonal mass matrix.
+	DiagMetric
+	// DenseMetric adapts a dense mass matrix.
+	DenseMetric
+)
+
+const (
+	// maxEnergyError is the energy error above which a
+	// trajectory is considered divergent.
+	maxEnergyError = 1000
+
+	defaultHMCAccept  = 0.65
+	defaultNUTSAccept = 0.8
+	defaultNUTSDepth  = 10
+)
+
+// HMC is a type for generating samples using Hamiltonian Monte Carlo, with the
+// given target distribution, starting at the location specified by Initial. If
+// Src is not nil it will be used to generate random numbers, otherwise
+// rand.Float64 will be used.
+//
+// Each iteration draws a momentum p ~ N(0, M) and simulates Hamiltonian
+// dynamics for the potential -log(target) and kinetic energy pᵀM^-1p/2 with
+// Steps leapfrog steps of size StepSize. The end point of the trajectory is
+// accepted with the Metropolis probability for the change in total energy.
+//
+// During the BurnIn iterations the step size is adapted by dual averaging to
+// achieve a mean acceptance probability of TargetAccept, and if Metric is not
+// UnitMetric the inverse mass matrix is adapted to the covariance of the target
+// using the windowed schedule of Stan. If StepSize is zero an initial step size
+// is found heuristically. InvMass specifies the initial inverse mass matrix; if
+// it is nil the identity is used. If Steps is zero, 10 leapfrog steps are used,
+// and if TargetAccept is zero it is 0.65. Rate specifies the thinning of the
+// chain as for MetropolisHastingser.
+//
+// Sample sets Stats to the statistics of the run. The Initial location is not
+// changed during calls to Sample.
+type HMC struct {
+	Initial []float64
+	Target  LogProbScorer
+	Src     rand.Source
+
+	StepSize     float64
+	Steps        int
+	Metric       Metric
+	InvMass      *mat.SymDense
+	TargetAccept float64
+
+	BurnIn int
+	Rate   int
+
+	Stats ChainStats
+}
+
+// Sample generates rows(batch) samples using Hamiltonian Monte Carlo.
+// The number of columns in batch must equal len(h.Initial), otherwise Sample
+// will panic.
+func (h *HMC) Sample(batch *mat.Dense) {
+	steps := h.Steps
+	if steps == 0 {
+		steps = 10
+	}
+	target := h.TargetAccept
+	if target == 0 {
+		target = defaultHMCAccept
+	}
+	h.Stats = ChainStats{}
+	sys := newHamiltonian(h.Target, h.Initial, h.InvMass, h.Src)
+	adapt := newAdapter(sys, h.StepSize, target, h.Metric, h.BurnIn, h.Initial)
+
+	var sumAccept float64
+	var kept int
+	runChain(batch, h.Initial, h.BurnIn, h.Rate, func(x []float64, iter int) {
+		eps := adapt.eps
+		cur := sys.state(x)
+		sys.sampleMomentum(cur.p)
+		h0 := sys.energy(cur)
+		next := cur.clone()
+		var diverged bool
+		for s := 0; s < steps; s++ {
+			sys.leapfrog(next, eps)
+			if math.IsNaN(next.logp) || sys.energy(next)-h0 > maxEnergyError {
+				diverged = true
+				break
+			}
+		}
+		accept := 0.0
+		if !diverged {
+			accept = math.Min(1, math.Exp(h0-sys.energy(next)))
+		}
+		if sys.rnd() < accept {
+			copy(x, next.x)
+		}
+		if iter < h.BurnIn {
+			adapt.update(iter, x, accept)
+			return
+		}
+		if diverged {
+			h.Stats.Divergences++
+		}
+		sumAccept += accept
+		kept++
+	})
+	if kept != 0 {
+		h.Stats.AcceptRate = sumAccept / float64(kept)
+	}
+	h.Stats.Evaluations = sys.evals
+	h.Stats.StepSize = adapt.eps
+}
+
+// NUTS is a type for generating samples using the No-U-Turn Sampler of Hoffman
+// and Gelman, with the given target distribution, starting at the location
+// specified by Initial. If Src is not nil it will be used to generate random
+// numbers, otherwise rand.Float64 will be used.
+//
+// NUTS is a variant of Hamiltonian Monte Carlo that builds a trajectory by
+// repeated doubling until it starts to turn back on itself, removing the need
+// to choose the number of leapfrog steps. The trajectory is limited to 2^MaxDepth
+// steps; if MaxDepth is zero it is 10.
+//
+// During the BurnIn iterations the step size is adapted by dual averaging to
+// achieve a mean acceptance statistic of TargetAccept, and if Metric is not
+// UnitMetric the inverse mass matrix is adapted to the covariance of the target
+// using the windowed schedule of Stan. If StepSize is zero an initial step size
+// is found heuristically. InvMass specifies the initial inverse mass matrix; if
+// it is nil the identity is used. If TargetAccept is zero it is 0.8. Rate
+// specifies the thinning of the chain as for MetropolisHastingser.
+//
+// Sample sets Stats to the statistics of the run. The Initial location is not
+// changed during calls to Sample.
+type NUTS struct {
+	Initial []float64
+	Target  LogProbScorer
+	Src     rand.Source
+
+	StepSize     float64
+	MaxDepth     int
+	Metric       Metric
+	InvMass      *mat.SymDense
+	TargetAccept float64
+
+	BurnIn int
+	Rate   int
+
+	Stats ChainStats
+}
+
+// Sample generates rows(batch) samples using the No-U-Turn Sampler.
+// The number of columns in batch must equal len(n.Initial), otherwise Sample
+// will panic.
+func (n *NUTS) Sample(batch *mat.Dense) {
+	maxDepth := n.MaxDepth
+	if maxDepth == 0 {
+		maxDepth = defaultNUTSDepth
+	}
+	target := n.TargetAccept
+	if target == 0 {
+		target = defaultNUTSAccept
+	}
+	n.Stats = ChainStats{}
+	sys := newHamiltonian(n.Target, n.Initial, n.InvMass, n.Src)
+	adapt := newAdapter(sys, n.StepSize, target, n.Metric, n.BurnIn, n.Initial)
+
+	var sumAccept, sumDepth float64
+	var kept int
+	runChain(batch, n.Initial, n.BurnIn, n.Rate, func(x []float64, iter int) {
+		t := &nutsTree{sys: sys, eps: adapt.eps, maxDepth: maxDepth}
+		depth, accept, diverged := t.transition(x)
+		if iter < n.BurnIn {
+			adapt.update(iter, x, accept)
+			return
+		}
+		if diverged {
+			n.Stats.Divergences++
+		}
+		sumAccept += accept
+		sumDepth += float64(depth)
+		kept++
+	})
+	if kept != 0 {
+		n.Stats.AcceptRate = sumAccept / float64(kept)
+		n.Stats.MeanTreeDepth = sumDepth / float64(kept)
+	}
+	n.Stats.Evaluations = sys.evals
+	n.Stats.StepSize = adapt.eps
+}
+
+// phaseState is a point in phase space.
+type phaseState struct {
+	x, p, grad []float64
+	logp       float64
+}
+
+func (s *phaseState) clone() *phaseState {
+	return &phaseState{
+		x:    append([]float64(nil), s.x...),
+		p:    append([]float64(nil), s.p...),
+		grad: append([]float64(nil), s.grad...),
+		logp: s.logp,
+	}
+}
+
+// hamiltonian is a Hamiltonian system for a target density and mass matrix.
+type hamiltonian struct {
+	target LogProbScorer
+	dim    int
+	rnd    func() float64
+	norm   func() float64
+	evals  int
+
+	invMass *mat.SymDense
+	// chol is the Cholesky factorization of the inverse mass matrix.
+	chol mat.Cholesky
+	tmp  []float64
+}
+
+func newHamiltonian(target LogProbScorer, initial []float64, invMass *mat.SymDense, src rand.Source) *hamiltonian {
+	dim := len(initial)
+	h := &hamiltonian{target: target, dim: dim, tmp: make([]float64, dim)}
+	if src == nil {
+		h.rnd = rand.Float64
+		h.norm = rand.NormFloat64
+	} else {
+		r := rand.New(src)
+		h.rnd = r.Float64
+		h.norm = r.NormFloat64
+	}
+	m := mat.NewSymDense(dim, nil)
+	if invMass == nil {
+		for i := 0; i < dim; i++ {
+			m.SetSym(i, i, 1)
+		}
+	} else {
+		if invMass.Symmetric() != dim {
+			panic(errLengthMismatch)
+		}
+		m.CopySym(invMass)
+	}
+	if !h.setInvMass(m) {
+		panic("samplemv: inverse mass matrix not positive definite")
+	}
+	return h
+}
+
+// setInvMass sets the inverse mass matrix, returning whether it is
+// positive definite.
+func (h *hamiltonian) setInvMass(m *mat.SymDense) bool {
+	var chol mat.Cholesky
+	if !chol.Factorize(m) {
+		return false
+	}
+	h.invMass = m
+	h.chol = chol
+	return true
+}
+
+// state returns the phase space state at x with zero momentum.
+func (h *hamiltonian) state(x []float64) *phaseState {
+	s := &phaseState{
+		x:    append([]float64(nil), x...),
+		p:    make([]float64, h.dim),
+		grad: make([]float64, h.dim),
+	}
+	h.evaluate(s)
+	return s
+}
+
+// evaluate sets the log-density and gradient of s.
+func (h *hamiltonian) evaluate(s *phaseState) {
+	s.logp = h.target.LogProb(s.x)
+	h.target.ScoreInput(s.grad, s.x)
+	h.evals++
+}
+
+// sampleMomentum stores a sample from N(0, M) in p. With M^-1 = L Lᵀ,
+// the momentum p = L^-ᵀ z has covariance M.
+func (h *hamiltonian) sampleMomentum(p []float64) {
+	for i := range h.tmp {
+		h.tmp[i] = h.norm()
+	}
+	var l mat.TriDense
+	h.chol.LTo(&l)
+	v := mat.NewVecDense(h.dim, p)
+	err := v.SolveVec(l.T(), mat.NewVecDense(h.dim, h.tmp))
+	if err != nil {
+		if _, ok := err.(mat.Condition); !ok {
+			panic(err)
+		}
+	}
+}
+
+// velocity stores M^-1 p in dst.
+func (h *hamiltonian) velocity(dst, p []float64) {
+	mat.NewVecDense(h.dim, dst).MulVec(h.invMass, mat.NewVecDense(h.dim, p))
+}
+
+// kinetic returns the kinetic energy pᵀM^-1p/2.
+func (h *hamiltonian) kinetic(p []float64) float64 {
+	v := mat.NewVecDense(h.dim, p)
+	return 0.5 * mat.Inner(v, h.invMass, v)
+}
+
+// energy returns the total energy of s.
+func (h *hamiltonian) energy(s *phaseState) float64 {
+	return -s.logp + h.kinetic(s.p)
+}
+
+// leapfrog advances s by a leapfrog step of size eps.
+func (h *hamiltonian) leapfrog(s *phaseState, eps float64) {
+	floats.AddScaled(s.p, eps/2, s.grad)
+	h.velocity(h.tmp, s.p)
+	floats.AddScaled(s.x, eps, h.tmp)
+	h.evaluate(s)
+	floats.AddScaled(s.p, eps/2, s.grad)
+}
+
+// findStepSize returns a step size for which the acceptance probability of
+// a single leapfrog step from x crosses 0.5, using the heuristic of Hoffman
+// and Gelman.
+func (h *hamiltonian) findStepSize(x []float64, eps float64) float64 {
+	cur := h.state(x)
+	h.sampleMomentum(cur.p)
+	h0 := h.energy(cur)
+	logAccept := func(eps float64) float64 {
+		next := cur.clone()
+		h.leapfrog(next, eps)
+		v := h0 - h.energy(next)
+		if math.IsNaN(v) {
+			return math.Inf(-1)
+		}
+		return v
+	}
+	dir := 1.0
+	if logAccept(eps) < math.Log(0.5) {
+		dir = -1
+	}
+	for i := 0; i < 100; i++ {
+		la := logAccept(eps)
+		if dir*la <= dir*math.Log(0.5) {
+			break
+		}
+		eps *= math.Pow(2, dir)
+	}
+	return eps
+}
+
+// adapter adapts the step size and mass matrix during burn-in.
+type adapter struct {
+	sys    *hamiltonian
+	target float64
+	burnIn int
+	metric Metric
+
+	eps float64
+	da  *dualAveraging
+
+	start int
+	ends  []int
+	acc   *covAccumulator
+}
+
+func newAdapter(sys *hamiltonian, eps, target float64, metric Metric, burnIn int, initial []float64) *adapter {
+	a := &adapter{sys: sys, target: target, burnIn: burnIn, metric: metric}
+	if eps == 0 {
+		eps = sys.findStepSize(initial, 1)
+	}
+	a.eps = eps
+	if burnIn > 0 {
+		a.da = newDualAveraging(eps, target)
+	}
+	if metric != UnitMetric {
+		a.start, a.ends = adaptWindows(burnIn)
+		a.acc = newCovAccumulator(sys.dim)
+	}
+	return a
+}
+
+// update updates the adaptation after burn-in iteration iter finishing at x
+// with the acceptance statistic accept.
+func (a *adapter) update(iter int, x []float64, accept float64) {
+	a.eps = a.da.update(accept)
+	if iter == a.burnIn-1 {
+		a.eps = a.da.final()
+		return
+	}
+	if a.acc == nil || iter < a.start || len(a.ends) == 0 {
+		return
+	}
+	a.acc.add(x)
+	if iter+1 != a.ends[0] {
+		return
+	}
+	a.ends = a.ends[1:]
+
+	// Regularize the covariance estimate toward a small
+	// multiple of the identity as done by Stan.
+	n := a.acc.n
+	cov := mat.NewSymDense(a.sys.dim, nil)
+	a.acc.covariance(cov)
+	cov.ScaleSym(n/(n+5), cov)
+	for i := 0; i < a.sys.dim; i++ {
+		if a.metric == DiagMetric {
+			for j := i + 1; j < a.sys.dim; j++ {
+				cov.SetSym(i, j, 0)
+			}
+		}
+		cov.SetSym(i, i, cov.At(i, i)+1e-3*5/(n+5))
+	}
+	a.acc.reset()
+	if a.sys.setInvMass(cov) {
+		a.eps = a.sys.findStepSize(x, a.eps)
+		a.da = newDualAveraging(a.eps, a.target)
+	}
+}
+
+// nutsTree builds the trajectories of the No-U-Turn Sampler.
+type nutsTree struct {
+	sys      *hamiltonian
+	eps      float64
+	maxDepth int
+
+	logu     float64
+	h0       float64
+	diverged bool
+	v1, v2   []float64
+}
+
+// transition performs a NUTS transition from x, updating x in-place and
+// returning the tree depth, the acceptance statistic and whether the
+// trajectory diverged. This is algorithm 6 of Hoffman and Gelman.
+func (t *nutsTree) transition(x []float64) (depth int, accept float64, diverged bool) {
+	t.v1 = make([]float64, t.sys.dim)
+	t.v2 = make([]float64, t.sys.dim)
+	cur := t.sys.state(x)
+	t.sys.sampleMomentum(cur.p)
+	t.h0 = t.sys.energy(cur)
+	// Sample the slice variable log(u) with u ~ U(0, exp(-H0)).
+	t.logu = -t.h0 + math.Log(t.sys.rnd())
+
+	minus, plus := cur, cur.clone()
+	n := 1
+	var sumAlpha float64
+	var nAlpha int
+	for depth = 0; depth < t.maxDepth; depth++ {
+		dir := 1.0
+		if t.sys.rnd() < 0.5 {
+			dir = -1
+		}
+		var sub *subtree
+		if dir < 0 {
+			sub = t.build(minus, dir, depth)
+			minus = sub.minus
+		} else {
+			sub = t.build(plus, dir, depth)
+			plus = sub.plus
+		}
+		sumAlpha += sub.alpha
+		nAlpha += sub.nAlpha
+		if !sub.ok {
+			break
+		}
+		if float64(sub.n)/float64(n) > t.sys.rnd() {
+			copy(x, sub.proposal.x)
+		}
+		n += sub.n
+		if !t.noUTurn(minus, plus) {
+			depth++
+			break
+		}
+	}
+	return depth, sumAlpha / float64(nAlpha), t.diverged
+}
+
+// subtree is the result of building a NUTS subtree.
+type subtree struct {
+	minus, plus *phaseState
+	proposal    *phaseState
+	n           int
+	ok          bool
+	alpha       float64
+	nAlpha      int
+}
+
+// build builds a subtree of height j from s in the direction dir.
+func (t *nutsTree) build(s *phaseState, dir float64, j int) *subtree {
+	if j == 0 {
+		next := s.clone()
+		t.sys.leapfrog(next, dir*t.eps)
+		h := t.sys.energy(next)
+		if math.IsNaN(h) {
+			h = math.Inf(1)
+		}
+		sub := &subtree{minus: next, plus: next, proposal: next, nAlpha: 1}
+		if t.logu <= -h {
+			sub.n = 1
+		}
+		sub.ok = t.logu < maxEnergyError-h
+		if !sub.ok {
+			t.diverged = true
+		}
+		sub.alpha = math.Min(1, math.Exp(t.h0-h))
+		return sub
+	}
+	sub := t.build(s, dir, j-1)
+	if !sub.ok {
+		return sub
+	}
+	var other *subtree
+	if dir < 0 {
+		other = t.build(sub.minus, dir, j-1)
+		sub.minus = other.minus
+	} else {
+		other = t.build(sub.plus, dir, j-1)
+		sub.plus = other.plus
+	}
+	if other.n > 0 && float64(other.n)/float64(sub.n+other.n) > t.sys.rnd() {
+		sub.proposal = other.proposal
+	}
+	sub.alpha += other.alpha
+	sub.nAlpha += other.nAlpha
+	sub.n += other.n
+	sub.ok = other.ok && t.noUTurn(sub.minus, sub.plus)
+	return sub
+}
+
+// noUTurn returns whether the trajectory between minus and plus has not
+// started to turn back on itself.
+func (t *nutsTree) noUTurn(minus, plus *phaseState) bool {
+	floats.SubTo(t.v1, plus.x, minus.x)
+	t.sys.velocity(t.v2, minus.p)
+	if floats.Dot(t.v1, t.v2) < 0 {
+		return false
+	}
+	t.sys.velocity(t.v2, plus.p)
+	return floats.Dot(t.v1, t.v2) >= 0
+}
@@ -0,0 +1,168 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package samplemv
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distmv"
+)
+
+// correlatedNormal returns a three-dimensional normal distribution with
+// strongly correlated and differently scaled components.
+func correlatedNormal(t *testing.T) *distmv.Normal {
+	sigma := mat.NewSymDense(3, []float64{
+		4, 1.8, 0.5,
+		1.8, 1, 0.1,
+		0.5, 0.1, 0.25,
+	})
+	target, ok := distmv.NewNormal([]float64{1, -2, 0.5}, sigma, nil)
+	if !ok {
+		t.Fatal("bad test, sigma not pos def")
+	}
+	return target
+}
+
+func TestHMC(t *testing.T) {
+	t.Parallel()
+	target := correlatedNormal(t)
+	for _, metric := range []Metric{UnitMetric, DiagMetric, DenseMetric} {
+		h := &HMC{
+			Initial: make([]float64, 3),
+			Target:  target,
+			Src:     rand.NewSource(1),
+			Steps:   15,
+			Metric:  metric,
+			BurnIn:  1000,
+		}
+		batch := mat.NewDense(10000, 3, nil)
+		h.Sample(batch)
+		compareNormal(t, target, batch, nil, 0.1, 0.2)
+		// The averaged step size from dual averaging is typically
+		// conservative, so only check for gross errors.
+		if h.Stats.AcceptRate < defaultHMCAccept-0.15 || h.Stats.AcceptRate > 0.99 {
+			t.Errorf("unexpected acceptance rate for metric %d: got %v, want about %v",
+				metric, h.Stats.AcceptRate, defaultHMCAccept)
+		}
+		if h.Stats.Divergences != 0 {
+			t.Errorf("unexpected divergences for metric %d: %d", metric, h.Stats.Divergences)
+		}
+		if h.Stats.StepSize <= 0 {
+			t.Errorf("unexpected step size for metric %d: %v", metric, h.Stats.StepSize)
+		}
+	}
+}
+
+func TestNUTS(t *testing.T) {
+	t.Parallel()
+	target := correlatedNormal(t)
+	for _, metric := range []Metric{UnitMetric, DiagMetric, DenseMetric} {
+		n := &NUTS{
+			Initial: make([]float64, 3),
+			Target:  target,
+			Src:     rand.NewSource(1),
+			Metric:  metric,
+			BurnIn:  1000,
+		}
+		batch := mat.NewDense(10000, 3, nil)
+		n.Sample(batch)
+		compareNormal(t, target, batch, nil, 0.1, 0.2)
+		if n.Stats.AcceptRate < defaultNUTSAccept-0.1 || n.Stats.AcceptRate > 0.99 {
+			t.Errorf("unexpected acceptance statistic for metric %d: got %v, want about %v",
+				metric, n.Stats.AcceptRate, defaultNUTSAccept)
+		}
+		if n.Stats.MeanTreeDepth < 1 || n.Stats.MeanTreeDepth > defaultNUTSDepth {
+			t.Errorf("unexpected mean tree depth for metric %d: %v", metric, n.Stats.MeanTreeDepth)
+		}
+		if n.Stats.Divergences != 0 {
+			t.Errorf("unexpected divergences for metric %d: %d", metric, n.Stats.Divergences)
+		}
+	}
+}
+
+func TestNUTSDenseMetricDepth(t *testing.T) {
+	t.Parallel()
+	// Adapting a dense metric to a correlated target should make
+	// the transformed target isotropic and the trajectories short.
+	target := correlatedNormal(t)
+	depth := make(map[Metric]float64)
+	for _, metric := range []Metric{UnitMetric, DenseMetric} {
+		n := &NUTS{
+			Initial: make([]float64, 3),
+			Target:  target,
+			Src:     rand.NewSource(1),
+			Metric:  metric,
+			BurnIn:  1000,
+		}
+		n.Sample(mat.NewDense(1000, 3, nil))
+		depth[metric] = n.Stats.MeanTreeDepth
+	}
+	if depth[DenseMetric] >= depth[UnitMetric] {
+		t.Errorf("dense metric did not reduce tree depth: unit=%v dense=%v", depth[UnitMetric], depth[DenseMetric])
+	}
+}
+
+func TestHamiltonianStatsReset(t *testing.T) {
+	t.Parallel()
+	// A large fixed step size without burn-in gives
+	// divergent trajectories.
+	target := correlatedNormal(t)
+	h := &HMC{Initial: make([]float64, 3), Target: target, StepSize: 100}
+	n := &NUTS{Initial: make([]float64, 3), Target: target, StepSize: 100}
+	for _, test := range []struct {
+		name   string
+		sample func(*mat.Dense)
+		reset  func()
+		stats  *ChainStats
+	}{
+		{name: "HMC", sample: h.Sample, reset: func() { h.Src = rand.NewSource(1) }, stats: &h.Stats},
+		{name: "NUTS", sample: n.Sample, reset: func() { n.Src = rand.NewSource(1) }, stats: &n.Stats},
+	} {
+		var runs [2]ChainStats
+		for i := range runs {
+			test.reset()
+			test.sample(mat.NewDense(200, 3, nil))
+			runs[i] = *test.stats
+		}
+		if runs[0].Divergences == 0 {
+			t.Errorf("%s: expected divergences for large step size", test.name)
+		}
+		if runs[0] != runs[1] {
+			t.Errorf("%s: statistics not reset between runs: first %+v, second %+v", test.name, runs[0], runs[1])
+		}
+	}
+}
+
+func TestAdaptWindows(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		burnIn int
+		start  int
+		ends   []int
+	}{
+		{burnIn: 10, start: 10},
+		{burnIn: 100, start: 15, ends: []int{90}},
+		{burnIn: 1000, start: 75, ends: []int{100, 150, 250, 450, 950}},
+		{burnIn: 2000, start: 75, ends: []int{100, 150, 250, 450, 850, 1950}},
+	} {
+		start, ends := adaptWindows(test.burnIn)
+		if start != test.start {
+			t.Errorf("unexpected start for burn-in %d: got %d, want %d", test.burnIn, start, test.start)
+		}
+		if len(ends) != len(test.ends) {
+			t.Errorf("unexpected windows for burn-in %d: got %v, want %v", test.burnIn, ends, test.ends)
+			continue
+		}
+		for i := range ends {
+			if ends[i] != test.ends[i] {
+				t.Errorf("unexpected windows for burn-in %d: got %v, want %v", test.burnIn, ends, test.ends)
+				break
+			}
+		}
+	}
+}
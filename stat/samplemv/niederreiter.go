@@ -0,0 +1,159 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package samplemv
+
+import (
+	"math/bits"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distmv"
+)
+
+// Niederreiter is a type for sampling using the base 2 Niederreiter sequence
+// from the given distribution, constructed as described in
+//  Implementation and tests of low-discrepancy sequences
+//  Paul Bratley, Bennett Fox and Harald Niederreiter
+//  ACM Transactions on Modeling and Computer Simulation 2 (1992)
+// The randomization of the sequence is specified by Scramble. If Src is not
+// nil, it will be used to generate the randomness needed to scramble the
+// sequence (if necessary), otherwise the rand package will be used.
+// Niederreiter panics if Scramble is unrecognized or if Q is nil.
+//
+// The sequence is a (t,s)-sequence in base 2 where t is the sum of the degrees
+// of the first s irreducible polynomials over GF(2), less s.
+type Niederreiter struct {
+	Scramble Scramble
+	Q        distmv.Quantiler
+	Src      rand.Source
+}
+
+// Sample generates rows(batch) samples using the Niederreiter generation
+// procedure.
+func (n Niederreiter) Sample(batch *mat.Dense) {
+	_, d := batch.Dims()
+	polys := irreducibleGF2(d)
+	gen := make([][]uint32, d)
+	for j, p := range polys {
+		gen[j] = niederreiterColumns(p)
+	}
+	digitalNet(batch, gen, n.Scramble, n.Q, n.Src)
+}
+
+// gf2Poly is a polynomial over GF(2). The ith element is the coefficient
+// of x^i.
+type gf2Poly []uint8
+
+func (p gf2Poly) degree() int { return len(p) - 1 }
+
+func (p gf2Poly) mul(q gf2Poly) gf2Poly {
+	r := make(gf2Poly, len(p)+len(q)-1)
+	for i, a := range p {
+		if a == 0 {
+			continue
+		}
+		for j, b := range q {
+			r[i+j] ^= b
+		}
+	}
+	return r
+}
+
+// irreducibleGF2 returns the first n monic irreducible polynomials over
+// GF(2) in order of increasing degree and, within a degree, increasing
+// binary value.
+func irreducibleGF2(n int) []gf2Poly {
+	var found []uint64
+	var polys []gf2Poly
+	for v := uint64(2); len(polys) < n; v++ {
+		deg := 63 - bits.LeadingZeros64(v)
+		irreducible := true
+		for _, f := range found {
+			fd := 63 - bits.LeadingZeros64(f)
+			if 2*fd > deg {
+				break
+			}
+			if gf2Mod(v, f) == 0 {
+				irreducible = false
+				break
+			}
+		}
+		if !irreducible {
+			continue
+		}
+		found = append(found, v)
+		p := make(gf2Poly, deg+1)
+		for i := range p {
+			p[i] = uint8(v>>uint(i)) & 1
+		}
+		polys = append(polys, p)
+	}
+	return polys
+}
+
+// gf2Mod returns a mod b for polynomials over GF(2) represented as bits.
+func gf2Mod(a, b uint64) uint64 {
+	db := 63 - bits.LeadingZeros64(b)
+	for a != 0 {
+		da := 63 - bits.LeadingZeros64(a)
+		if da < db {
+			break
+		}
+		a ^= b << uint(da-db)
+	}
+	return a
+}
+
+// niederreiterColumns returns the columns of the generator matrix of the
+// Niederreiter sequence for the irreducible polynomial p. Row i = Q*e + u of
+// the matrix, with e the degree of p and 0 ≤ u < e, holds the coefficients of
+// the Laurent expansion
+//  x^(e-u-1) / p(x)^(Q+1) = \sum_{r≥0} c_r x^(-r-1)
+// where c_r is the element in column r.
+func niederreiterColumns(p gf2Poly) []uint32 {
+	e := p.degree()
+	cols := make([]uint32, netBits)
+	pow := gf2Poly{1}
+	for i := 0; i < netBits; i++ {
+		u := i % e
+		if u == 0 {
+			// pow = p^(Q+1) where Q = i/e.
+			pow = pow.mul(p)
+		}
+		row := laurent(e-u-1, pow)
+		for k := range cols {
+			cols[k] |= ((row >> uint(netBits-1-k)) & 1) << uint(netBits-1-i)
+		}
+	}
+	return cols
+}
+
+// laurent returns the first netBits coefficients of the expansion of x^m/P(x)
+// in powers of 1/x, with m < degree(P), packed with the coefficient of 1/x as
+// the most significant bit.
+func laurent(m int, pp gf2Poly) uint32 {
+	d := pp.degree()
+	var c [netBits]uint8
+	var v uint32
+	for s := 0; s < netBits; s++ {
+		// The coefficient of x^(d-1-s) in P(x) \sum_r c_r x^(-r-1)
+		// must match that of x^m.
+		var b uint8
+		if d-1-s == m {
+			b = 1
+		}
+		lo := 0
+		if s > d {
+			lo = s - d
+		}
+		for r := lo; r < s; r++ {
+			b ^= c[r] & pp[d-s+r]
+		}
+		c[s] = b
+		v |= uint32(b) << uint(netBits-1-s)
+	}
+	return v
+}
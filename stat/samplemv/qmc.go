@@ -0,0 +1,45 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package samplemv
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+)
+
+// Integrate estimates the expected value of f under the distribution sampled
+// by s using reps independent replicates of n samples of dimension dim. The
+// estimate is the mean of the replicate means and stdErr is its standard error
+// estimated from the spread of the replicate means.
+//
+// With a randomized low-discrepancy sampler such as a Sobol sampler with
+// OwenScramble, each call to s.Sample generates an independently randomized
+// point set, and Integrate implements randomized quasi-Monte Carlo integration.
+// The standard error is then a valid error estimate that typically decreases
+// faster than n^-1/2. With a deterministic sampler the replicates are
+// identical and stdErr is zero. If reps is one, stdErr is NaN. Integrate
+// panics if reps is less than one.
+func Integrate(f func(x []float64) float64, s Sampler, n, dim, reps int) (estimate, stdErr float64) {
+	if reps < 1 {
+		panic("samplemv: bad number of replicates")
+	}
+	batch := mat.NewDense(n, dim, nil)
+	means := make([]float64, reps)
+	for r := range means {
+		s.Sample(batch)
+		var sum float64
+		for i := 0; i < n; i++ {
+			sum += f(batch.RawRowView(i))
+		}
+		means[r] = sum / float64(n)
+	}
+	if reps == 1 {
+		return means[0], math.NaN()
+	}
+	estimate = stat.Mean(means, nil)
+	return estimate, stat.StdDev(means, nil) / math.Sqrt(float64(reps))
+}
@@ -0,0 +1,44 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package samplemv
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/stat/distmv"
+)
+
+func TestIntegrate(t *testing.T) {
+	t.Parallel()
+	const d = 5
+	// The expected value of f over the unit cube is 1.
+	f := func(x []float64) float64 {
+		p := 1.0
+		for _, v := range x {
+			p *= 1 + (v-0.5)*(v-0.5)*12/5 - 0.2
+		}
+		return p
+	}
+	unif := distmv.NewUnitUniform(d, rand.NewSource(1))
+	mcEst, mcErr := Integrate(f, IID{Dist: unif}, 1024, d, 20)
+	qmcEst, qmcErr := Integrate(f, Sobol{Scramble: OwenScramble, Q: unif, Src: rand.NewSource(1)}, 1024, d, 20)
+	if math.Abs(mcEst-1) > 4*mcErr {
+		t.Errorf("unexpected Monte Carlo estimate: %v±%v", mcEst, mcErr)
+	}
+	if math.Abs(qmcEst-1) > 4*qmcErr {
+		t.Errorf("unexpected quasi-Monte Carlo estimate: %v±%v", qmcEst, qmcErr)
+	}
+	if qmcErr > mcErr/5 {
+		t.Errorf("randomized quasi-Monte Carlo error not smaller than Monte Carlo: %v vs %v", qmcErr, mcErr)
+	}
+
+	_, detErr := Integrate(f, Sobol{Q: unif}, 1024, d, 3)
+	if detErr != 0 {
+		t.Errorf("unexpected error for deterministic sampler: %v", detErr)
+	}
+}
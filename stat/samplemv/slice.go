@@ -0,0 +1,134 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package samplemv
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distmv"
+)
+
+var _ Sampler = (*Slice)(nil)
+
+// Slice is a type for generating samples using coordinate-wise slice sampling
+// with the stepping-out and shrinkage procedures of Neal, "Slice sampling",
+// Ann. Statist. 31 (2003) 705-767, with the given target distribution, starting
+// at the location specified by Initial. If Src is not nil it will be used to
+// generate random numbers, otherwise rand.Float64 will be used.
+//
+// Each iteration updates every coordinate in turn by sampling uniformly from
+// the slice {x : log(target(x)) > log(target(x_0)) + log(u)} along that
+// coordinate. The slice is found by stepping out from an interval of width
+// Width randomly positioned around the current location, using at most
+// MaxSteps steps. If Width is zero it is 1, and if MaxSteps is zero the
+// number of steps is unlimited. Rate specifies the thinning of the chain as
+// for MetropolisHastingser.
+//
+// Sample sets Stats to the statistics of the run. The Initial location is not
+// changed during calls to Sample.
+type Slice struct {
+	Initial  []float64
+	Target   distmv.LogProber
+	Width    float64
+	MaxSteps int
+	Src      rand.Source
+
+	BurnIn int
+	Rate   int
+
+	Stats ChainStats
+}
+
+// Sample generates rows(batch) samples using slice sampling.
+// The number of columns in batch must equal len(s.Initial), otherwise Sample
+// will panic.
+func (s *Slice) Sample(batch *mat.Dense) {
+	f64 := rand.Float64
+	if s.Src != nil {
+		f64 = rand.New(s.Src).Float64
+	}
+	w := s.Width
+	if w == 0 {
+		w = 1
+	}
+	if w < 0 {
+		panic("samplemv: negative slice width")
+	}
+	var evals int
+	logProb := func(x []float64) float64 {
+		evals++
+		return s.Target.LogProb(x)
+	}
+	var logp float64
+	first := true
+	runChain(batch, s.Initial, s.BurnIn, s.Rate, func(x []float64, iter int) {
+		if first {
+			logp = logProb(x)
+			first = false
+		}
+		for i, xi := range x {
+			logy := logp + math.Log(f64())
+
+			// Step out.
+			left := xi - w*f64()
+			right := left + w
+			if s.MaxSteps == 0 {
+				for {
+					x[i] = left
+					if logProb(x) <= logy {
+						break
+					}
+					left -= w
+				}
+				for {
+					x[i] = right
+					if logProb(x) <= logy {
+						break
+					}
+					right += w
+				}
+			} else {
+				j := int(math.Floor(float64(s.MaxSteps) * f64()))
+				k := s.MaxSteps - 1 - j
+				for ; j > 0; j-- {
+					x[i] = left
+					if logProb(x) <= logy {
+						break
+					}
+					left -= w
+				}
+				for ; k > 0; k-- {
+					x[i] = right
+					if logProb(x) <= logy {
+						break
+					}
+					right += w
+				}
+			}
+
+			// Shrink.
+			for {
+				x[i] = left + f64()*(right-left)
+				lp := logProb(x)
+				if lp > logy {
+					logp = lp
+					break
+				}
+				if x[i] < xi {
+					left = x[i]
+				} else {
+					right = x[i]
+				}
+			}
+		}
+	})
+	s.Stats = ChainStats{Evaluations: evals}
+	if r, _ := batch.Dims(); r != 0 {
+		s.Stats.AcceptRate = 1
+	}
+}
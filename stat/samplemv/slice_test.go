@@ -0,0 +1,63 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package samplemv
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestSlice(t *testing.T) {
+	t.Parallel()
+	target := correlatedNormal(t)
+	for _, maxSteps := range []int{0, 10} {
+		s := &Slice{
+			Initial:  make([]float64, 3),
+			Target:   target,
+			Width:    2,
+			MaxSteps: maxSteps,
+			Src:      rand.NewSource(1),
+			BurnIn:   500,
+		}
+		batch := mat.NewDense(50000, 3, nil)
+		s.Sample(batch)
+		compareNormal(t, target, batch, nil, 0.1, 0.2)
+		if s.Stats.AcceptRate != 1 || s.Stats.Evaluations == 0 {
+			t.Errorf("unexpected stats: %+v", s.Stats)
+		}
+	}
+}
+
+func TestSliceBurnInRate(t *testing.T) {
+	t.Parallel()
+	target := correlatedNormal(t)
+	const burnIn, rate, samples = 11, 7, 5
+	s := &Slice{
+		Initial: make([]float64, 3),
+		Target:  target,
+		Src:     rand.NewSource(1),
+	}
+	full := mat.NewDense(1+burnIn+rate*(samples-1), 3, nil)
+	s.Sample(full)
+
+	s = &Slice{
+		Initial: make([]float64, 3),
+		Target:  target,
+		Src:     rand.NewSource(1),
+		BurnIn:  burnIn,
+		Rate:    rate,
+	}
+	batch := mat.NewDense(samples, 3, nil)
+	s.Sample(batch)
+	for i := 0; i < samples; i++ {
+		if !floats.Equal(batch.RawRowView(i), full.RawRowView(burnIn+i*rate)) {
+			t.Errorf("sampling mismatch at sample %d", i)
+		}
+	}
+}
@@ -0,0 +1,272 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package samplemv
+
+import (
+	"fmt"
+	"math/bits"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distmv"
+)
+
+var (
+	_ Sampler = Sobol{}
+	_ Sampler = Niederreiter{}
+)
+
+// Scramble specifies the randomization applied to a digital sequence.
+type Scramble int
+
+const (
+	// NoScramble generates the deterministic sequence. The first point of
+	// an unscrambled sequence is the origin, which is mapped to -∞ by the
+	// quantile function of distributions with unbounded support.
+	NoScramble Scramble = iota
+
+	// OwenScramble applies the nested uniform scrambling of
+	//  Randomly permuted (t,m,s)-nets and (t,s)-sequences
+	//  Art Owen
+	//  Monte Carlo and Quasi-Monte Carlo Methods in Scientific Computing (1995)
+	// Each scrambled point is uniformly distributed over the unit cube and
+	// the scrambled sequence retains the net properties of the original.
+	OwenScramble
+
+	// LinearMatrixScramble applies a random linear matrix scramble followed
+	// by a random digital shift, as described in
+	//  On the L2-discrepancy for anchored boxes
+	//  Jiří Matoušek
+	//  Journal of Complexity 14 (1998)
+	// It is cheaper than OwenScramble but randomizes less.
+	LinearMatrixScramble
+)
+
+// Sobol is a type for sampling using the Sobol sequence from the given
+// distribution. The direction numbers are those of
+//  Constructing Sobol sequences with better two-dimensional projections
+//  Stephen Joe and Frances Kuo
+//  SIAM Journal on Scientific Computing 30 (2008)
+// and are currently limited to 21 dimensional inputs. The randomization of
+// the sequence is specified by Scramble. If Src is not nil, it will be used to
+// generate the randomness needed to scramble the sequence (if necessary),
+// otherwise the rand package will be used. Sobol panics if Scramble is
+// unrecognized or if Q is nil.
+//
+// The first 2^m points of a Sobol sequence form a (t,m,s)-net in base 2, so
+// sample sizes that are powers of two should be preferred. Each call to Sample
+// generates the first rows(batch) points of a newly scrambled sequence.
+type Sobol struct {
+	Scramble Scramble
+	Q        distmv.Quantiler
+	Src      rand.Source
+}
+
+// Sample generates rows(batch) samples using the Sobol generation procedure.
+func (s Sobol) Sample(batch *mat.Dense) {
+	_, d := batch.Dims()
+	if d > len(joeKuo)+1 {
+		panic(fmt.Sprintf("sobol: dimension must be at most %d", len(joeKuo)+1))
+	}
+	gen := make([][]uint32, d)
+	for j := range gen {
+		gen[j] = sobolDirections(j)
+	}
+	digitalNet(batch, gen, s.Scramble, s.Q, s.Src)
+}
+
+// netBits is the number of base 2 digits generated for each coordinate
+// of a digital net.
+const netBits = 32
+
+// sobolDirections returns the direction numbers of the jth dimension of the
+// Sobol sequence. The kth direction number holds the binary digits of the
+// kth column of the generator matrix, with the most significant bit being
+// the first digit.
+func sobolDirections(j int) []uint32 {
+	v := make([]uint32, netBits)
+	if j == 0 {
+		for k := range v {
+			v[k] = 1 << uint(netBits-1-k)
+		}
+		return v
+	}
+	p := joeKuo[j-1]
+	s := len(p.m)
+	for k := 0; k < s && k < netBits; k++ {
+		v[k] = p.m[k] << uint(netBits-1-k)
+	}
+	for k := s; k < netBits; k++ {
+		v[k] = v[k-s] ^ (v[k-s] >> uint(s))
+		for l := 1; l < s; l++ {
+			if (p.a>>uint(s-1-l))&1 == 1 {
+				v[k] ^= v[k-l]
+			}
+		}
+	}
+	return v
+}
+
+// digitalNet fills batch with the points of the base 2 digital sequence with
+// the given generator matrices, randomized according to scramble and mapped
+// through the quantile function of q.
+func digitalNet(batch *mat.Dense, gen [][]uint32, scramble Scramble, q distmv.Quantiler, src rand.Source) {
+	if q == nil {
+		panic("samplemv: nil quantiler")
+	}
+	n, d := batch.Dims()
+	if uint64(n) > 1<<netBits {
+		panic("samplemv: too many samples for digital sequence")
+	}
+	var rnd *rand.Rand
+	if src != nil {
+		rnd = rand.New(src)
+	}
+	uint32s := func() uint32 {
+		if rnd == nil {
+			return rand.Uint32()
+		}
+		return rnd.Uint32()
+	}
+	f64 := func() float64 {
+		if rnd == nil {
+			return rand.Float64()
+		}
+		return rnd.Float64()
+	}
+
+	const scale = 1.0 / (1 << netBits)
+	for j := 0; j < d; j++ {
+		cols := gen[j]
+		var shift uint32
+		switch scramble {
+		default:
+			panic("samplemv: unknown Scramble")
+		case NoScramble, OwenScramble:
+		case LinearMatrixScramble:
+			cols = linearScramble(cols, uint32s)
+			shift = uint32s()
+		}
+		var owen *owenTree
+		if scramble == OwenScramble {
+			owen = &owenTree{flips: make(map[uint64]bool), rnd: uint32s}
+		}
+		for i := 0; i < n; i++ {
+			var v uint32
+			for k := uint(0); k < netBits && i>>k != 0; k++ {
+				if (i>>k)&1 == 1 {
+					v ^= cols[k]
+				}
+			}
+			v ^= shift
+			x := float64(v) * scale
+			switch scramble {
+			case OwenScramble:
+				v = owen.scramble(v)
+				// The digits beyond the precision of the net are
+				// independent and uniform after nested scrambling.
+				x = (float64(v) + f64()) * scale
+			case LinearMatrixScramble:
+				x = (float64(v) + f64()) * scale
+			}
+			batch.Set(i, j, x)
+		}
+	}
+	p := make([]float64, d)
+	for i := 0; i < n; i++ {
+		copy(p, batch.RawRowView(i))
+		q.Quantile(batch.RawRowView(i), p)
+	}
+}
+
+// linearScramble returns the generator columns premultiplied by a random
+// lower triangular binary matrix with unit diagonal.
+func linearScramble(cols []uint32, rnd func() uint32) []uint32 {
+	// Row r of the matrix maps the first r digits and digit r of
+	// the input to output digit r.
+	var rows [netBits]uint32
+	for r := range rows {
+		top := uint32(1) << uint(netBits-1-r)
+		rows[r] = top | (rnd() &^ (top | (top - 1)))
+	}
+	out := make([]uint32, len(cols))
+	for k, c := range cols {
+		var v uint32
+		for r, row := range rows {
+			if bits.OnesCount32(row&c)&1 == 1 {
+				v |= 1 << uint(netBits-1-r)
+			}
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// owenTree holds the lazily generated random digit flips of a nested
+// uniform scramble of one coordinate.
+type owenTree struct {
+	flips map[uint64]bool
+	rnd   func() uint32
+}
+
+// scramble returns the nested uniform scramble of v. Each digit is flipped
+// according to a random bit associated with the preceding digits.
+func (o *owenTree) scramble(v uint32) uint32 {
+	var out uint32
+	for k := uint(0); k < netBits; k++ {
+		// The node of the digit tree is identified by its depth
+		// and the digits above it.
+		var prefix uint32
+		if k > 0 {
+			prefix = v >> (netBits - k)
+		}
+		node := uint64(k)<<netBits | uint64(prefix)
+		flip, ok := o.flips[node]
+		if !ok {
+			flip = o.rnd()&1 == 1
+			o.flips[node] = flip
+		}
+		bit := (v >> (netBits - 1 - k)) & 1
+		if flip {
+			bit ^= 1
+		}
+		out |= bit << (netBits - 1 - k)
+	}
+	return out
+}
+
+// sobolPoly holds a primitive polynomial and the initial direction numbers
+// of a dimension of the Sobol sequence. The degree of the polynomial is
+// len(m) and a holds its interior coefficients.
+type sobolPoly struct {
+	a uint32
+	m []uint32
+}
+
+// joeKuo holds the direction numbers for dimensions 2 to 21 from the file
+// new-joe-kuo-6.21201 of Joe and Kuo.
+var joeKuo = []sobolPoly{
+	{a: 0, m: []uint32{1}},
+	{a: 1, m: []uint32{1, 3}},
+	{a: 1, m: []uint32{1, 3, 1}},
+	{a: 2, m: []uint32{1, 1, 1}},
+	{a: 1, m: []uint32{1, 1, 3, 3}},
+	{a: 4, m: []uint32{1, 3, 5, 13}},
+	{a: 2, m: []uint32{1, 1, 5, 5, 17}},
+	{a: 4, m: []uint32{1, 1, 5, 5, 5}},
+	{a: 7, m: []uint32{1, 1, 7, 11, 19}},
+	{a: 11, m: []uint32{1, 1, 5, 1, 1}},
+	{a: 13, m: []uint32{1, 1, 1, 3, 11}},
+	{a: 14, m: []uint32{1, 3, 5, 5, 31}},
+	{a: 1, m: []uint32{1, 3, 3, 9, 7, 49}},
+	{a: 13, m: []uint32{1, 1, 1, 15, 21, 21}},
+	{a: 16, m: []uint32{1, 3, 1, 13, 27, 49}},
+	{a: 19, m: []uint32{1, 1, 1, 15, 7, 5}},
+	{a: 22, m: []uint32{1, 3, 1, 15, 13, 25}},
+	{a: 25, m: []uint32{1, 1, 5, 5, 19, 61}},
+	{a: 1, m: []uint32{1, 3, 7, 11, 23, 15, 103}},
+	{a: 4, m: []uint32{1, 3, 7, 13, 13, 15, 69}},
+}
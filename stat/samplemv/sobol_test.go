@@ -0,0 +1,198 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package samplemv
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distmv"
+)
+
+// isNet returns whether the projection of the points in the rows of batch onto
+// dimensions j and k is a (t,m,2)-net in base b, where the number of rows is b^m.
+func isNet(batch *mat.Dense, j, k, b, m, t int) bool {
+	for m1 := 0; m1 <= m-t; m1++ {
+		m2 := m - t - m1
+		n1 := int(math.Pow(float64(b), float64(m1)))
+		n2 := int(math.Pow(float64(b), float64(m2)))
+		counts := make([]int, n1*n2)
+		r, _ := batch.Dims()
+		for i := 0; i < r; i++ {
+			// Allow for rounding of points on the boundaries.
+			c1 := int(batch.At(i, j)*float64(n1) + 1e-9)
+			c2 := int(batch.At(i, k)*float64(n2) + 1e-9)
+			counts[c1*n2+c2]++
+		}
+		want := int(math.Pow(float64(b), float64(t)))
+		for _, c := range counts {
+			if c != want {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TestSobolPoints(t *testing.T) {
+	t.Parallel()
+	batch := mat.NewDense(8, 3, nil)
+	Sobol{Q: distmv.NewUnitUniform(3, nil)}.Sample(batch)
+	// Points in natural order computed by hand from the direction numbers.
+	want := mat.NewDense(8, 3, []float64{
+		0, 0, 0,
+		0.5, 0.5, 0.5,
+		0.25, 0.75, 0.75,
+		0.75, 0.25, 0.25,
+		0.125, 0.625, 0.375,
+		0.625, 0.125, 0.875,
+		0.375, 0.375, 0.625,
+		0.875, 0.875, 0.125,
+	})
+	if !mat.Equal(batch, want) {
+		t.Errorf("unexpected Sobol points:\ngot:\n%v\nwant:\n%v", mat.Formatted(batch), mat.Formatted(want))
+	}
+}
+
+func TestDirectionNumbers(t *testing.T) {
+	t.Parallel()
+	polys := make(map[uint64]bool)
+	for _, p := range irreducibleGF2(200) {
+		var v uint64
+		for i, c := range p {
+			v |= uint64(c) << uint(i)
+		}
+		polys[v] = true
+	}
+	for j, p := range joeKuo {
+		s := len(p.m)
+		// Reconstruct the polynomial from its degree and interior
+		// coefficients, and check it is irreducible.
+		v := uint64(1)<<uint(s) | uint64(p.a)<<1 | 1
+		if !polys[v] {
+			t.Errorf("polynomial for dimension %d not irreducible: %b", j+2, v)
+		}
+		for k, m := range p.m {
+			if m%2 == 0 || m >= 1<<uint(k+1) {
+				t.Errorf("bad initial direction number %d for dimension %d: %d", k+1, j+2, m)
+			}
+		}
+	}
+}
+
+func TestIrreducibleGF2(t *testing.T) {
+	t.Parallel()
+	// The number of irreducible polynomials over GF(2) of each degree.
+	want := []int{0, 2, 1, 2, 3, 6, 9, 18, 30}
+	got := make([]int, len(want))
+	for _, p := range irreducibleGF2(71) {
+		got[p.degree()]++
+	}
+	for d := range want {
+		if got[d] != want[d] {
+			t.Errorf("unexpected number of irreducible polynomials of degree %d: got %d, want %d", d, got[d], want[d])
+		}
+	}
+}
+
+func TestDigitalNets(t *testing.T) {
+	t.Parallel()
+	const m = 10
+	n := 1 << m
+	for _, scramble := range []Scramble{NoScramble, OwenScramble, LinearMatrixScramble} {
+		src := rand.NewSource(1)
+		sobol := mat.NewDense(n, 21, nil)
+		Sobol{Scramble: scramble, Q: distmv.NewUnitUniform(21, nil), Src: src}.Sample(sobol)
+		// Each coordinate is a (0,m,1)-net and the first two
+		// coordinates form a (0,m,2)-net.
+		for j := 0; j < 21; j++ {
+			if !isNet(sobol, j, j, 2, m, m) {
+				t.Errorf("Sobol coordinate %d with scramble %d not stratified", j, scramble)
+			}
+		}
+		if !isNet(sobol, 0, 1, 2, m, 0) {
+			t.Errorf("Sobol coordinates 0 and 1 with scramble %d not a (0,m,2)-net", scramble)
+		}
+
+		nied := mat.NewDense(n, 6, nil)
+		Niederreiter{Scramble: scramble, Q: distmv.NewUnitUniform(6, nil), Src: src}.Sample(nied)
+		for j := 0; j < 6; j++ {
+			if !isNet(nied, j, j, 2, m, m) {
+				t.Errorf("Niederreiter coordinate %d with scramble %d not stratified", j, scramble)
+			}
+		}
+		// The polynomials x, x+1, x^2+x+1 and x^3+x+1 give t-values
+		// of 0 for the first two coordinates and 1+2 for coordinates
+		// 2 and 3.
+		if !isNet(nied, 0, 1, 2, m, 0) {
+			t.Errorf("Niederreiter coordinates 0 and 1 with scramble %d not a (0,m,2)-net", scramble)
+		}
+		if !isNet(nied, 2, 3, 2, m, 3) {
+			t.Errorf("Niederreiter coordinates 2 and 3 with scramble %d not a (3,m,2)-net", scramble)
+		}
+	}
+}
+
+func TestSobolMaxDimension(t *testing.T) {
+	t.Parallel()
+	// The embedded direction numbers support
+	// len(joeKuo)+1 dimensions.
+	d := len(joeKuo) + 1
+	Sobol{Q: distmv.NewUnitUniform(d, nil)}.Sample(mat.NewDense(4, d, nil))
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected panic for dimension %d", d+1)
+		}
+	}()
+	Sobol{Q: distmv.NewUnitUniform(d+1, nil)}.Sample(mat.NewDense(4, d+1, nil))
+}
+
+func TestFaure(t *testing.T) {
+	t.Parallel()
+	const d, b, m = 5, 5, 4
+	n := int(math.Pow(b, m))
+	batch := mat.NewDense(n, d, nil)
+	Faure{Q: distmv.NewUnitUniform(d, nil)}.Sample(batch)
+	for j := 0; j < d; j++ {
+		for k := j + 1; k < d; k++ {
+			if !isNet(batch, j, k, b, m, 0) {
+				t.Errorf("Faure coordinates %d and %d not a (0,m,2)-net", j, k)
+			}
+		}
+	}
+	// The first coordinate is the van der Corput sequence in base b.
+	want := []float64{0, 0.2, 0.4, 0.6, 0.8, 0.04, 0.24}
+	if got := mat.Col(nil, 0, batch)[:len(want)]; !floats.EqualApprox(got, want, 1e-14) {
+		t.Errorf("unexpected van der Corput sequence: got %v, want %v", got, want)
+	}
+}
+
+func TestOwenScrambleUniform(t *testing.T) {
+	t.Parallel()
+	// The scrambled points are uniformly distributed, so the mean
+	// of a point over replicates is 1/2 in each coordinate.
+	const reps = 2000
+	src := rand.NewSource(1)
+	batch := mat.NewDense(4, 3, nil)
+	sum := mat.NewDense(4, 3, nil)
+	for r := 0; r < reps; r++ {
+		Sobol{Scramble: OwenScramble, Q: distmv.NewUnitUniform(3, nil), Src: src}.Sample(batch)
+		sum.Add(sum, batch)
+	}
+	sum.Scale(1.0/reps, sum)
+	tol := 4 / math.Sqrt(12*reps)
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 3; j++ {
+			if math.Abs(sum.At(i, j)-0.5) > tol {
+				t.Errorf("scrambled point %d coordinate %d not uniform: mean %v", i, j, sum.At(i, j))
+			}
+		}
+	}
+}
@@ -0,0 +1,8 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package smc implements sequential Monte Carlo methods: particle filters
+// for state estimation in nonlinear non-Gaussian state-space models, and
+// tempered SMC samplers for static targets.
+package smc // import "gonum.org/v1/gonum/stat/smc"
@@ -0,0 +1,266 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package smc
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// Model is a state-space model
+//  x_0 ~ p(x_0)
+//  x_t ~ p(x_t | x_{t-1})
+//  y_t ~ p(y_t | x_t)
+// with hidden states x_t and observations y_t.
+type Model interface {
+	// InitialRand stores a sample from the distribution of the
+	// initial state in x.
+	InitialRand(x []float64, rnd *rand.Rand)
+
+	// TransitionRand stores in x a sample of the state at time t
+	// given the state prev at time t-1.
+	TransitionRand(x, prev []float64, t int, rnd *rand.Rand)
+
+	// LogLikelihood returns the log-density of the observation y
+	// at time t given the state x.
+	LogLikelihood(y, x []float64, t int) float64
+}
+
+// LookaheadModel is a Model that can be used with the auxiliary particle
+// filter.
+type LookaheadModel interface {
+	Model
+
+	// LogLookahead returns an approximation to the log predictive
+	// density of the observation y at time t given the state prev at
+	// time t-1, for example the log-likelihood of y evaluated at the
+	// mean of the transition from prev.
+	LogLookahead(y, prev []float64, t int) float64
+}
+
+// ParticleFilter is a sequential Monte Carlo filter for a state-space model.
+// The filter approximates the distribution of the state given the observations
+// so far by a set of weighted particles and estimates the log marginal
+// likelihood of the observations.
+//
+// The bootstrap filter propagates the particles with the transition density
+// of the model and weights them by the likelihood of the observation. The
+// particles are resampled with Resample before propagation when the effective
+// sample size of the weights falls below Threshold times the number of
+// particles. If Threshold is zero the particles are resampled at every step.
+// If Resample is nil, Systematic is used.
+//
+// If Auxiliary is true, the auxiliary particle filter of Pitt and Shephard,
+// "Filtering via simulation: auxiliary particle filters", JASA 94 (1999)
+// 590-599, is used instead. The particles are resampled at every step with
+// weights adjusted by the look-ahead density of the model, which must implement
+// LookaheadModel, and then reweighted to correct for the adjustment.
+//
+// If Src is not nil it is used to generate random numbers, otherwise the rand
+// package is used.
+type ParticleFilter struct {
+	Model     Model
+	Particles int
+	Dim       int
+
+	Resample  Resampler
+	Threshold float64
+	Auxiliary bool
+	Src       rand.Source
+
+	t       int
+	x, next *mat.Dense
+	logw    []float64
+	look    []float64
+	logZ    float64
+	resamp  int
+	idx     []int
+	w       []float64
+	rnd     *rand.Rand
+	resSrc  rand.Source
+}
+
+// Reset discards the state of the filter so that the next call to Update
+// starts from the initial state distribution.
+func (f *ParticleFilter) Reset() {
+	f.t = 0
+	f.x = nil
+}
+
+// Update incorporates the next observation y into the filter.
+func (f *ParticleFilter) Update(y []float64) {
+	if f.Particles <= 0 || f.Dim <= 0 {
+		panic("smc: bad filter dimensions")
+	}
+	if f.x == nil {
+		f.init()
+	}
+	n := f.Particles
+	if f.t == 0 {
+		for i := 0; i < n; i++ {
+			f.Model.InitialRand(f.x.RawRowView(i), f.rnd)
+		}
+		f.weight(y, nil)
+		f.t++
+		return
+	}
+
+	if f.Auxiliary {
+		m, ok := f.Model.(LookaheadModel)
+		if !ok {
+			panic("smc: auxiliary filter requires a LookaheadModel")
+		}
+		// First stage weights.
+		norm := floats.LogSumExp(f.logw)
+		for i := range f.logw {
+			f.logw[i] -= norm
+			f.look[i] = m.LogLookahead(y, f.x.RawRowView(i), f.t)
+			f.logw[i] += f.look[i]
+		}
+		f.logZ += floats.LogSumExp(f.logw)
+		f.resampleAll()
+		f.propagate()
+		// Second stage weights correct for the look-ahead.
+		for i, k := range f.idx {
+			f.logw[i] = -f.look[k]
+		}
+		f.weight(y, f.logw)
+		f.t++
+		return
+	}
+
+	if f.Threshold == 0 || ESS(f.weights()) < f.Threshold*float64(n) {
+		f.resampleAll()
+	} else {
+		for i := range f.idx {
+			f.idx[i] = i
+		}
+	}
+	f.propagate()
+	f.weight(y, nil)
+	f.t++
+}
+
+func (f *ParticleFilter) init() {
+	n := f.Particles
+	f.x = mat.NewDense(n, f.Dim, nil)
+	f.next = mat.NewDense(n, f.Dim, nil)
+	f.logw = make([]float64, n)
+	f.look = make([]float64, n)
+	f.idx = make([]int, n)
+	f.w = make([]float64, n)
+	f.logZ = 0
+	f.resamp = 0
+	if f.Src == nil {
+		f.rnd = rand.New(rand.NewSource(rand.Uint64()))
+	} else {
+		f.rnd = rand.New(f.Src)
+	}
+	f.resSrc = rand.NewSource(f.rnd.Uint64())
+}
+
+// weights returns the weights of the particles normalized to sum to one.
+func (f *ParticleFilter) weights() []float64 {
+	max := floats.Max(f.logw)
+	for i, lw := range f.logw {
+		f.w[i] = math.Exp(lw - max)
+	}
+	floats.Scale(1/floats.Sum(f.w), f.w)
+	return f.w
+}
+
+// resampleAll resamples the particles according to their weights, storing the
+// ancestor indices in f.idx and resetting the weights.
+func (f *ParticleFilter) resampleAll() {
+	resample := f.Resample
+	if resample == nil {
+		resample = Systematic
+	}
+	resample(f.idx, f.weights(), f.resSrc)
+	for i := range f.logw {
+		f.logw[i] = 0
+	}
+	f.resamp++
+}
+
+// propagate moves the particles with ancestors f.idx through the transition.
+func (f *ParticleFilter) propagate() {
+	for i, k := range f.idx {
+		f.Model.TransitionRand(f.next.RawRowView(i), f.x.RawRowView(k), f.t, f.rnd)
+	}
+	f.x, f.next = f.next, f.x
+}
+
+// weight adds the log-likelihood of y to the particle weights, with initial
+// log weights base if not nil, and updates the log marginal likelihood.
+func (f *ParticleFilter) weight(y []float64, base []float64) {
+	// Normalize the incoming weights so that the log-sum-exp of the
+	// updated weights is the log of the incremental likelihood.
+	norm := floats.LogSumExp(f.logw)
+	if base != nil {
+		// Second stage weights of the auxiliary filter are
+		// averaged uniformly.
+		norm = math.Log(float64(len(f.logw)))
+	}
+	for i := range f.logw {
+		if base == nil {
+			f.logw[i] -= norm
+		} else {
+			f.logw[i] = base[i] - norm
+		}
+		f.logw[i] += f.Model.LogLikelihood(y, f.x.RawRowView(i), f.t)
+	}
+	f.logZ += floats.LogSumExp(f.logw)
+}
+
+// Time returns the number of observations incorporated by the filter.
+func (f *ParticleFilter) Time() int {
+	return f.t
+}
+
+// State returns the particles and their normalized weights. The particles
+// are held in the rows of the returned matrix. The returned values must not be
+// modified and are only valid until the next call to Update.
+func (f *ParticleFilter) State() (particles *mat.Dense, weights []float64) {
+	if f.x == nil {
+		return nil, nil
+	}
+	return f.x, f.weights()
+}
+
+// Mean returns the weighted mean of the particles, an estimate of the mean of
+// the current state given the observations. If dst is nil, a new slice is
+// allocated and returned. If dst is not nil, its length must equal Dim.
+func (f *ParticleFilter) Mean(dst []float64) []float64 {
+	if dst == nil {
+		dst = make([]float64, f.Dim)
+	} else if len(dst) != f.Dim {
+		panic(badLength)
+	}
+	w := f.weights()
+	mat.NewVecDense(f.Dim, dst).MulVec(f.x.T(), mat.NewVecDense(len(w), w))
+	return dst
+}
+
+// ESS returns the effective sample size of the current particle weights.
+func (f *ParticleFilter) ESS() float64 {
+	return ESS(f.weights())
+}
+
+// LogLikelihood returns the estimate of the log marginal likelihood of the
+// observations incorporated so far. The estimate of the marginal likelihood
+// is unbiased.
+func (f *ParticleFilter) LogLikelihood() float64 {
+	return f.logZ
+}
+
+// Resamples returns the number of resampling steps performed.
+func (f *ParticleFilter) Resamples() int {
+	return f.resamp
+}
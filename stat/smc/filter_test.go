@@ -0,0 +1,108 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package smc
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distuv"
+	"gonum.org/v1/gonum/stat/timeseries"
+)
+
+// linearModel is the linear Gaussian state-space model
+//  x_0 ~ N(0, 1)
+//  x_t = phi x_{t-1} + N(0, q)
+//  y_t = x_t + N(0, r)
+type linearModel struct {
+	phi, q, r float64
+}
+
+func (m linearModel) InitialRand(x []float64, rnd *rand.Rand) {
+	x[0] = rnd.NormFloat64()
+}
+
+func (m linearModel) TransitionRand(x, prev []float64, t int, rnd *rand.Rand) {
+	x[0] = m.phi*prev[0] + math.Sqrt(m.q)*rnd.NormFloat64()
+}
+
+func (m linearModel) LogLikelihood(y, x []float64, t int) float64 {
+	return distuv.Normal{Mu: x[0], Sigma: math.Sqrt(m.r)}.LogProb(y[0])
+}
+
+func (m linearModel) LogLookahead(y, prev []float64, t int) float64 {
+	return distuv.Normal{Mu: m.phi * prev[0], Sigma: math.Sqrt(m.r)}.LogProb(y[0])
+}
+
+func (m linearModel) stateSpace() *timeseries.StateSpace {
+	return &timeseries.StateSpace{
+		T:  mat.NewDense(1, 1, []float64{m.phi}),
+		Z:  mat.NewDense(1, 1, []float64{1}),
+		Q:  mat.NewSymDense(1, []float64{m.q}),
+		H:  mat.NewSymDense(1, []float64{m.r}),
+		X0: []float64{0},
+		P0: mat.NewSymDense(1, []float64{1}),
+	}
+}
+
+func TestParticleFilter(t *testing.T) {
+	t.Parallel()
+	model := linearModel{phi: 0.9, q: 0.5, r: 1}
+	rnd := rand.New(rand.NewSource(1))
+	const steps = 50
+	y := mat.NewDense(steps, 1, nil)
+	x := rnd.NormFloat64()
+	for i := 0; i < steps; i++ {
+		y.Set(i, 0, x+rnd.NormFloat64())
+		x = model.phi*x + math.Sqrt(model.q)*rnd.NormFloat64()
+	}
+	kalman, ok := model.stateSpace().Filter(y)
+	if !ok {
+		t.Fatal("unexpected Kalman filter failure")
+	}
+
+	for _, test := range []struct {
+		name      string
+		resample  Resampler
+		threshold float64
+		auxiliary bool
+	}{
+		{name: "bootstrap", resample: Systematic},
+		{name: "bootstrap ESS", resample: Stratified, threshold: 0.5},
+		{name: "bootstrap residual", resample: Residual, threshold: 0.5},
+		{name: "auxiliary", resample: Multinomial, auxiliary: true},
+	} {
+		pf := &ParticleFilter{
+			Model:     model,
+			Particles: 10000,
+			Dim:       1,
+			Resample:  test.resample,
+			Threshold: test.threshold,
+			Auxiliary: test.auxiliary,
+			Src:       rand.NewSource(1),
+		}
+		for i := 0; i < steps; i++ {
+			pf.Update(y.RawRowView(i))
+			got := pf.Mean(nil)[0]
+			want := kalman.State.At(i, 0)
+			sd := math.Sqrt(kalman.StateCov[i].At(0, 0))
+			if math.Abs(got-want) > 0.05*sd {
+				t.Errorf("%s: unexpected filtered mean at step %d: got %v, want %v", test.name, i, got, want)
+			}
+		}
+		if pf.Time() != steps {
+			t.Errorf("%s: unexpected time: %d", test.name, pf.Time())
+		}
+		if got, want := pf.LogLikelihood(), kalman.LogLikelihood; math.Abs(got-want) > 0.2 {
+			t.Errorf("%s: unexpected log-likelihood: got %v, want %v", test.name, got, want)
+		}
+		if test.threshold != 0 && (pf.Resamples() == 0 || pf.Resamples() >= steps-1) {
+			t.Errorf("%s: unexpected number of resampling steps: %d", test.name, pf.Resamples())
+		}
+	}
+}
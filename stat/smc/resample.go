@@ -0,0 +1,148 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package smc
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/stat/sampleuv"
+)
+
+const (
+	badWeights = "smc: bad weights"
+	badLength  = "smc: slice length mismatch"
+)
+
+// Resampler is a function that stores in dst the indices of len(dst)
+// particles drawn with replacement in proportion to the non-negative weights.
+// Each index i must appear on average len(dst)*weights[i]/sum(weights) times.
+// If src is not nil it is used to generate random numbers, otherwise the rand
+// package is used.
+type Resampler func(dst []int, weights []float64, src rand.Source)
+
+var (
+	_ Resampler = Multinomial
+	_ Resampler = Stratified
+	_ Resampler = Systematic
+	_ Resampler = Residual
+)
+
+// Multinomial draws the indices independently in proportion to the weights
+// using a sampleuv.Weighted, so each draw takes O(log n) time.
+func Multinomial(dst []int, weights []float64, src rand.Source) {
+	checkWeights(weights)
+	multinomial(dst, weights, src)
+}
+
+func multinomial(dst []int, weights []float64, src rand.Source) {
+	w := sampleuv.NewWeighted(weights, src)
+	for i := range dst {
+		idx, ok := w.Take()
+		if !ok {
+			panic(badWeights)
+		}
+		// Replace the taken item to sample with replacement.
+		w.Reweight(idx, weights[idx])
+		dst[i] = idx
+	}
+}
+
+// Stratified draws one index from each of len(dst) equal strata of the
+// cumulative weights, using an independent uniform variate within each
+// stratum. The returned indices are in increasing order.
+func Stratified(dst []int, weights []float64, src rand.Source) {
+	f64 := rand.Float64
+	if src != nil {
+		f64 = rand.New(src).Float64
+	}
+	inverseCDF(dst, weights, func(i int) float64 { return f64() })
+}
+
+// Systematic draws one index from each of len(dst) equal strata of the
+// cumulative weights, using the same uniform variate offset within each
+// stratum. Each index i appears either floor or ceil of len(dst)*weights[i]/sum
+// times. The returned indices are in increasing order.
+func Systematic(dst []int, weights []float64, src rand.Source) {
+	f64 := rand.Float64
+	if src != nil {
+		f64 = rand.New(src).Float64
+	}
+	u := f64()
+	inverseCDF(dst, weights, func(int) float64 { return u })
+}
+
+// inverseCDF stores in dst the indices found by inverting the cumulative
+// weights at the points (i + u(i))/len(dst).
+func inverseCDF(dst []int, weights []float64, u func(i int) float64) {
+	sum := checkWeights(weights)
+	n := float64(len(dst))
+	var cum float64
+	j := 0
+	for i := range dst {
+		p := (float64(i) + u(i)) / n * sum
+		for j < len(weights)-1 && cum+weights[j] <= p {
+			cum += weights[j]
+			j++
+		}
+		// Rounding may leave p beyond the total weight, so
+		// step back over trailing zero weights.
+		k := j
+		for weights[k] == 0 {
+			k--
+		}
+		dst[i] = k
+	}
+}
+
+// Residual deterministically draws floor(len(dst)*weights[i]/sum) copies of
+// each index i and draws the remaining indices by multinomial resampling of
+// the residual weights.
+func Residual(dst []int, weights []float64, src rand.Source) {
+	sum := checkWeights(weights)
+	n := float64(len(dst))
+	residual := make([]float64, len(weights))
+	k := 0
+	for i, w := range weights {
+		e := n * w / sum
+		c := math.Floor(e)
+		for ; c > 0 && k < len(dst); c-- {
+			dst[k] = i
+			k++
+		}
+		residual[i] = e - math.Floor(e)
+	}
+	if k < len(dst) {
+		multinomial(dst[k:], residual, src)
+	}
+}
+
+// checkWeights returns the sum of the weights, panicking if any weight is
+// negative or not finite or if all are zero.
+func checkWeights(weights []float64) float64 {
+	var sum float64
+	for _, w := range weights {
+		if w < 0 || math.IsInf(w, 0) || math.IsNaN(w) {
+			panic(badWeights)
+		}
+		sum += w
+	}
+	if sum == 0 {
+		panic(badWeights)
+	}
+	return sum
+}
+
+// ESS returns the effective sample size of the weights,
+//  (\sum_i w_i)^2 / \sum_i w_i^2
+func ESS(weights []float64) float64 {
+	var sum, sum2 float64
+	for _, w := range weights {
+		sum += w
+		sum2 += w * w
+	}
+	return sum * sum / sum2
+}
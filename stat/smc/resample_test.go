@@ -0,0 +1,82 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package smc
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+var resamplers = []struct {
+	name string
+	fn   Resampler
+}{
+	{name: "Multinomial", fn: Multinomial},
+	{name: "Stratified", fn: Stratified},
+	{name: "Systematic", fn: Systematic},
+	{name: "Residual", fn: Residual},
+}
+
+func TestResamplers(t *testing.T) {
+	t.Parallel()
+	weights := []float64{0, 3, 1, 0, 0.5, 2.5, 0, 1, 0}
+	sum := 8.0
+	const n, reps = 20, 5000
+	for _, test := range resamplers {
+		src := rand.NewSource(1)
+		dst := make([]int, n)
+		mean := make([]float64, len(weights))
+		for r := 0; r < reps; r++ {
+			test.fn(dst, weights, src)
+			counts := make([]int, len(weights))
+			for _, k := range dst {
+				if weights[k] == 0 {
+					t.Fatalf("%s: sampled index %d with zero weight", test.name, k)
+				}
+				counts[k]++
+			}
+			for i, c := range counts {
+				mean[i] += float64(c) / reps
+				e := n * weights[i] / sum
+				switch test.name {
+				case "Systematic":
+					if float64(c) < math.Floor(e) || float64(c) > math.Ceil(e) {
+						t.Errorf("%s: count %d for index %d not within one of %v", test.name, c, i, e)
+					}
+				case "Residual":
+					if float64(c) < math.Floor(e) {
+						t.Errorf("%s: count %d for index %d less than floor of %v", test.name, c, i, e)
+					}
+				}
+			}
+		}
+		for i, m := range mean {
+			want := n * weights[i] / sum
+			if math.Abs(m-want) > 0.1 {
+				t.Errorf("%s: biased count for index %d: got %v, want %v", test.name, i, m, want)
+			}
+		}
+	}
+}
+
+func TestESS(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		w    []float64
+		want float64
+	}{
+		{w: []float64{1, 1, 1, 1}, want: 4},
+		{w: []float64{2, 2, 2, 2}, want: 4},
+		{w: []float64{1, 0, 0, 0}, want: 1},
+		{w: []float64{1, 1, 0, 0}, want: 2},
+		{w: []float64{3, 1}, want: 1.6},
+	} {
+		if got := ESS(test.w); math.Abs(got-test.want) > 1e-14 {
+			t.Errorf("unexpected ESS for %v: got %v, want %v", test.w, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package smc
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distmv"
+	"gonum.org/v1/gonum/stat/samplemv"
+)
+
+var _ samplemv.Sampler = (*Tempering)(nil)
+
+// Tempering is an SMC sampler for a static target density proportional to
+//  prior(x) * exp(LogLikelihood(x))
+// as described in Del Moral, Doucet and Jasra, "Sequential Monte Carlo
+// samplers", JRSS B 68 (2006) 411-436. The particles are initially drawn from
+// the prior and moved through the sequence of tempered targets
+//  prior(x) * exp(β LogLikelihood(x))
+// with β increasing from zero to one. Each inverse temperature β is chosen
+// adaptively so that the effective sample size of the incremental weights
+// is ESSFraction times the number of particles. After each reweighting the
+// particles are resampled with Resample and moved with Moves steps of a
+// random walk Metropolis kernel invariant for the current tempered target,
+// using a normal proposal with the scaled covariance of the particles.
+//
+// If ESSFraction is zero it is 0.5, if Moves is zero it is 5, and if
+// Resample is nil Systematic is used. If Src is not nil it is used to
+// generate random numbers, otherwise the rand package is used.
+//
+// Sample sets LogEvidence to the estimate of the log normalizing constant of
+// the target relative to the prior, Temperatures to the sequence of inverse
+// temperatures, and AcceptRate to the mean acceptance rate of the moves.
+type Tempering struct {
+	Prior         distmv.RandLogProber
+	LogLikelihood func(x []float64) float64
+	ESSFraction   float64
+	Moves         int
+	Resample      Resampler
+	Src           rand.Source
+
+	LogEvidence  float64
+	Temperatures []float64
+	AcceptRate   float64
+}
+
+// Sample generates rows(batch) equally weighted samples from the target,
+// using one particle per row.
+func (s *Tempering) Sample(batch *mat.Dense) {
+	n, d := batch.Dims()
+	frac := s.ESSFraction
+	if frac == 0 {
+		frac = 0.5
+	}
+	if frac <= 0 || frac >= 1 {
+		panic("smc: bad ESS fraction")
+	}
+	moves := s.Moves
+	if moves == 0 {
+		moves = 5
+	}
+	resample := s.Resample
+	if resample == nil {
+		resample = Systematic
+	}
+	var rnd *rand.Rand
+	if s.Src == nil {
+		rnd = rand.New(rand.NewSource(rand.Uint64()))
+	} else {
+		rnd = rand.New(s.Src)
+	}
+	resSrc := rand.NewSource(rnd.Uint64())
+
+	for i := 0; i < n; i++ {
+		s.Prior.Rand(batch.RawRowView(i))
+	}
+	loglike := make([]float64, n)
+	logprior := make([]float64, n)
+	for i := range loglike {
+		x := batch.RawRowView(i)
+		loglike[i] = s.LogLikelihood(x)
+		logprior[i] = s.Prior.LogProb(x)
+	}
+
+	s.LogEvidence = 0
+	s.Temperatures = []float64{0}
+	var accepted, proposed int
+	beta := 0.0
+	logw := make([]float64, n)
+	w := make([]float64, n)
+	idx := make([]int, n)
+	tmp := mat.NewDense(n, d, nil)
+	for beta < 1 {
+		next := nextTemperature(loglike, beta, frac*float64(n), logw)
+		for i, ll := range loglike {
+			logw[i] = (next - beta) * ll
+		}
+		// The incremental evidence is the mean of the incremental weights
+		// since the particles are equally weighted after resampling.
+		s.LogEvidence += floats.LogSumExp(logw) - math.Log(float64(n))
+		beta = next
+		s.Temperatures = append(s.Temperatures, beta)
+
+		max := floats.Max(logw)
+		for i, lw := range logw {
+			w[i] = math.Exp(lw - max)
+		}
+		resample(idx, w, resSrc)
+		tmp.Copy(batch)
+		ll := append([]float64(nil), loglike...)
+		lp := append([]float64(nil), logprior...)
+		for i, k := range idx {
+			batch.SetRow(i, tmp.RawRowView(k))
+			loglike[i] = ll[k]
+			logprior[i] = lp[k]
+		}
+
+		a, p := s.move(batch, loglike, logprior, beta, moves, rnd)
+		accepted += a
+		proposed += p
+	}
+	s.AcceptRate = float64(accepted) / float64(proposed)
+}
+
+// nextTemperature returns the inverse temperature above beta at which the
+// effective sample size of the incremental weights is target, or one if
+// the effective sample size at one exceeds target. The work slice logw
+// must have the same length as loglike.
+func nextTemperature(loglike []float64, beta, target float64, logw []float64) float64 {
+	ess := func(next float64) float64 {
+		for i, ll := range loglike {
+			logw[i] = (next - beta) * ll
+		}
+		// ESS from log weights, (\sum w)^2 / \sum w^2.
+		lse := floats.LogSumExp(logw)
+		floats.Scale(2, logw)
+		return math.Exp(2*lse - floats.LogSumExp(logw))
+	}
+	if ess(1) >= target {
+		return 1
+	}
+	lo, hi := beta, 1.0
+	for i := 0; i < 100 && hi-lo > 1e-12; i++ {
+		mid := (lo + hi) / 2
+		if ess(mid) >= target {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	if lo == beta {
+		// Guarantee progress.
+		return hi
+	}
+	return lo
+}
+
+// move applies moves steps of random walk Metropolis invariant for the
+// tempered target to each particle, returning the number of accepted and
+// proposed moves.
+func (s *Tempering) move(batch *mat.Dense, loglike, logprior []float64, beta float64, moves int, rnd *rand.Rand) (accepted, proposed int) {
+	n, d := batch.Dims()
+	var cov mat.SymDense
+	stat.CovarianceMatrix(&cov, batch, nil)
+	// Regularize for degenerate particle sets.
+	for j := 0; j < d; j++ {
+		cov.SetSym(j, j, cov.At(j, j)+1e-10)
+	}
+	cov.ScaleSym(2.38*2.38/float64(d), &cov)
+	var chol mat.Cholesky
+	if !chol.Factorize(&cov) {
+		panic("smc: degenerate particle covariance")
+	}
+	var l mat.TriDense
+	chol.LTo(&l)
+
+	z := mat.NewVecDense(d, nil)
+	step := mat.NewVecDense(d, nil)
+	prop := make([]float64, d)
+	for i := 0; i < n; i++ {
+		x := batch.RawRowView(i)
+		for m := 0; m < moves; m++ {
+			for j := 0; j < d; j++ {
+				z.SetVec(j, rnd.NormFloat64())
+			}
+			step.MulVec(&l, z)
+			for j := range prop {
+				prop[j] = x[j] + step.AtVec(j)
+			}
+			lp := s.Prior.LogProb(prop)
+			proposed++
+			if math.IsInf(lp, -1) {
+				continue
+			}
+			ll := s.LogLikelihood(prop)
+			logr := lp + beta*ll - logprior[i] - beta*loglike[i]
+			if math.Log(rnd.Float64()) < logr {
+				copy(x, prop)
+				loglike[i] = ll
+				logprior[i] = lp
+				accepted++
+			}
+		}
+	}
+	return accepted, proposed
+}
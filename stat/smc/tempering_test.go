@@ -0,0 +1,68 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package smc
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distmv"
+)
+
+func TestTempering(t *testing.T) {
+	t.Parallel()
+	// A normal prior with a normal likelihood gives a normal posterior
+	// and an evidence that can be computed exactly.
+	const d = 2
+	tau2 := 4.0
+	sigma := mat.NewSymDense(d, []float64{tau2, 0, 0, tau2})
+	prior, ok := distmv.NewNormal(make([]float64, d), sigma, rand.NewSource(1))
+	if !ok {
+		t.Fatal("bad test")
+	}
+	y := []float64{3, -1}
+	lik, _ := distmv.NewNormal(y, mat.NewSymDense(d, []float64{1, 0, 0, 1}), nil)
+	evidence, _ := distmv.NewNormal(make([]float64, d), mat.NewSymDense(d, []float64{tau2 + 1, 0, 0, tau2 + 1}), nil)
+
+	s := &Tempering{
+		Prior:         prior,
+		LogLikelihood: lik.LogProb,
+		Moves:         10,
+		Src:           rand.NewSource(1),
+	}
+	batch := mat.NewDense(5000, d, nil)
+	s.Sample(batch)
+
+	if got, want := s.LogEvidence, evidence.LogProb(y); math.Abs(got-want) > 0.05 {
+		t.Errorf("unexpected log evidence: got %v, want %v", got, want)
+	}
+	temps := s.Temperatures
+	if temps[0] != 0 || temps[len(temps)-1] != 1 || len(temps) < 3 {
+		t.Errorf("unexpected temperatures: %v", temps)
+	}
+	for i := 1; i < len(temps); i++ {
+		if temps[i] <= temps[i-1] {
+			t.Errorf("temperatures not increasing: %v", temps)
+		}
+	}
+	if s.AcceptRate <= 0 || s.AcceptRate >= 1 {
+		t.Errorf("unexpected acceptance rate: %v", s.AcceptRate)
+	}
+
+	postVar := tau2 / (tau2 + 1)
+	for j := 0; j < d; j++ {
+		mean, std := stat.MeanStdDev(mat.Col(nil, j, batch), nil)
+		if want := postVar * y[j]; math.Abs(mean-want) > 0.05 {
+			t.Errorf("unexpected posterior mean %d: got %v, want %v", j, mean, want)
+		}
+		if want := math.Sqrt(postVar); math.Abs(std-want) > 0.05 {
+			t.Errorf("unexpected posterior standard deviation %d: got %v, want %v", j, std, want)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package timeseries
+
+import (
+	"gonum.org/v1/gonum/dsp/fourier"
+	"gonum.org/v1/gonum/stat"
+)
+
+const (
+	badLags   = "timeseries: number of lags out of range"
+	badLength = "timeseries: slice length mismatch"
+	badOrder  = "timeseries: invalid model order"
+)
+
+// Autocovariance computes the sample autocovariance of the series x at lags 0
+// through lags and stores it in dst. The autocovariance at lag k is
+//  γ_k = 1/n \sum_{t=0}^{n-k-1} (x_t - x̄)(x_{t+k} - x̄)
+// where n = len(x) and x̄ is the mean of x. The autocovariance is computed
+// using the fast Fourier transform.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil, its
+// length must be lags+1. Autocovariance panics if lags is not in [0, len(x)).
+func Autocovariance(dst, x []float64, lags int) []float64 {
+	n := len(x)
+	if lags < 0 || n <= lags {
+		panic(badLags)
+	}
+	if dst == nil {
+		dst = make([]float64, lags+1)
+	} else if len(dst) != lags+1 {
+		panic(badLength)
+	}
+
+	// Pad the series to avoid circular correlation.
+	mean := stat.Mean(x, nil)
+	m := 2 * n
+	seq := make([]float64, m)
+	for i, v := range x {
+		seq[i] = v - mean
+	}
+	fft := fourier.NewFFT(m)
+	coeff := fft.Coefficients(nil, seq)
+	for i, c := range coeff {
+		coeff[i] = complex(real(c)*real(c)+imag(c)*imag(c), 0)
+	}
+	fft.Sequence(seq, coeff)
+	for k := range dst {
+		dst[k] = seq[k] / float64(m*n)
+	}
+	return dst
+}
+
+// Autocorrelation computes the sample autocorrelation of the series x at lags 0
+// through lags and stores it in dst. The autocorrelation at lag k is γ_k/γ_0
+// where γ_k is the autocovariance computed by Autocovariance.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil, its
+// length must be lags+1. Autocorrelation panics if lags is not in [0, len(x)).
+func Autocorrelation(dst, x []float64, lags int) []float64 {
+	dst = Autocovariance(dst, x, lags)
+	v := dst[0]
+	for k := range dst {
+		dst[k] /= v
+	}
+	return dst
+}
+
+// PartialAutocorrelation computes the sample partial autocorrelation of the
+// series x at lags 0 through lags and stores it in dst. The partial
+// autocorrelation at lag k is the last coefficient of the order k Yule–Walker
+// autoregression and is computed from the sample autocorrelation by the
+// Durbin–Levinson recursion. The partial autocorrelation at lag 0 is 1.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil, its
+// length must be lags+1. PartialAutocorrelation panics if lags is not in
+// [0, len(x)).
+func PartialAutocorrelation(dst, x []float64, lags int) []float64 {
+	if dst == nil {
+		dst = make([]float64, lags+1)
+	} else if len(dst) != lags+1 {
+		panic(badLength)
+	}
+	r := Autocovariance(nil, x, lags)
+	_, pacf, _ := durbinLevinson(r, lags)
+	dst[0] = 1
+	copy(dst[1:], pacf)
+	return dst
+}
+
+// durbinLevinson solves the Yule–Walker equations of order p for the
+// autocovariances r[0] through r[p], returning the autoregressive coefficients,
+// the partial autocorrelations at lags 1 through p and the innovation variance.
+func durbinLevinson(r []float64, p int) (phi, pacf []float64, v float64) {
+	phi = make([]float64, p)
+	pacf = make([]float64, p)
+	prev := make([]float64, p)
+	v = r[0]
+	for k := 1; k <= p; k++ {
+		acc := r[k]
+		for j := 1; j < k; j++ {
+			acc -= prev[j-1] * r[k-j]
+		}
+		a := acc / v
+		phi[k-1] = a
+		for j := 1; j < k; j++ {
+			phi[j-1] = prev[j-1] - a*prev[k-j-1]
+		}
+		pacf[k-1] = a
+		v *= 1 - a*a
+		copy(prev, phi)
+	}
+	return phi, pacf, v
+}
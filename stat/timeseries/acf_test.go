@@ -0,0 +1,142 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package timeseries
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/stat"
+)
+
+// simulateARMA returns n values of the ARMA process with the given
+// coefficients, mean and unit innovation variance after a burn in.
+func simulateARMA(n int, ar, ma []float64, mean float64, src rand.Source) []float64 {
+	rnd := rand.New(src)
+	const burn = 500
+	x := make([]float64, n+burn)
+	e := make([]float64, n+burn)
+	for t := range x {
+		e[t] = rnd.NormFloat64()
+		v := e[t]
+		for i, a := range ar {
+			if t-i-1 >= 0 {
+				v += a * x[t-i-1]
+			}
+		}
+		for i, m := range ma {
+			if t-i-1 >= 0 {
+				v += m * e[t-i-1]
+			}
+		}
+		x[t] = v
+	}
+	x = x[burn:]
+	for i := range x {
+		x[i] += mean
+	}
+	return x
+}
+
+func TestAutocovariance(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 7, 64, 101} {
+		x := make([]float64, n)
+		for i := range x {
+			x[i] = rnd.NormFloat64() + 3
+		}
+		lags := n - 1
+		if lags > 20 {
+			lags = 20
+		}
+		got := Autocovariance(nil, x, lags)
+		mean := stat.Mean(x, nil)
+		want := make([]float64, lags+1)
+		for k := range want {
+			for i := 0; i+k < n; i++ {
+				want[k] += (x[i] - mean) * (x[i+k] - mean)
+			}
+			want[k] /= float64(n)
+		}
+		if !floats.EqualApprox(got, want, 1e-12) {
+			t.Errorf("autocovariance mismatch for n=%d: got %v, want %v", n, got, want)
+		}
+		if n < 2 {
+			continue
+		}
+		acf := Autocorrelation(nil, x, lags)
+		if acf[0] != 1 || math.Abs(acf[1]-want[1]/want[0]) > 1e-12 {
+			t.Errorf("autocorrelation mismatch for n=%d: got %v", n, acf)
+		}
+	}
+}
+
+func TestPartialAutocorrelation(t *testing.T) {
+	x := simulateARMA(5000, []float64{0.5, -0.3}, nil, 0, rand.NewSource(1))
+	const lags = 6
+	pacf := PartialAutocorrelation(nil, x, lags)
+	acf := Autocorrelation(nil, x, lags)
+	if pacf[0] != 1 || math.Abs(pacf[1]-acf[1]) > 1e-12 {
+		t.Errorf("unexpected partial autocorrelation at lags 0 and 1: %v", pacf[:2])
+	}
+	for k := 1; k <= lags; k++ {
+		phi, _ := YuleWalker(x, k)
+		if math.Abs(pacf[k]-phi[k-1]) > 1e-12 {
+			t.Errorf("partial autocorrelation at lag %d does not match Yule–Walker: got %v, want %v", k, pacf[k], phi[k-1])
+		}
+	}
+	// The partial autocorrelation of an AR(2) process
+	// vanishes beyond lag 2.
+	if math.Abs(pacf[2]+0.3) > 0.05 {
+		t.Errorf("unexpected partial autocorrelation at lag 2: %v", pacf[2])
+	}
+	bound := 3 / math.Sqrt(5000)
+	for k := 3; k <= lags; k++ {
+		if math.Abs(pacf[k]) > bound {
+			t.Errorf("partial autocorrelation at lag %d too large: %v", k, pacf[k])
+		}
+	}
+}
+
+func TestAR(t *testing.T) {
+	want := []float64{0.5, -0.3, 0.2}
+	x := simulateARMA(10000, want, nil, 2, rand.NewSource(1))
+	for _, test := range []struct {
+		name string
+		fit  func([]float64, int) ([]float64, float64)
+	}{
+		{name: "YuleWalker", fit: YuleWalker},
+		{name: "Burg", fit: Burg},
+	} {
+		phi, sigma2 := test.fit(x, 3)
+		if !floats.EqualApprox(phi, want, 0.04) {
+			t.Errorf("%s coefficients mismatch: got %v, want %v", test.name, phi, want)
+		}
+		if math.Abs(sigma2-1) > 0.05 {
+			t.Errorf("%s innovation variance mismatch: got %v, want 1", test.name, sigma2)
+		}
+		phi, sigma2 = test.fit(x, 0)
+		if len(phi) != 0 || math.Abs(sigma2-stat.Variance(x, nil)*float64(len(x)-1)/float64(len(x))) > 1e-10 {
+			t.Errorf("%s order zero mismatch: got %v, %v", test.name, phi, sigma2)
+		}
+	}
+
+	// Burg's method for order 1 has the closed form estimate.
+	y := []float64{1, 3, 2, 5, 4, 6, 3}
+	phi, _ := Burg(y, 1)
+	mean := stat.Mean(y, nil)
+	var num, den float64
+	for i := 1; i < len(y); i++ {
+		a, b := y[i]-mean, y[i-1]-mean
+		num += a * b
+		den += a*a + b*b
+	}
+	if math.Abs(phi[0]-2*num/den) > 1e-14 {
+		t.Errorf("Burg order 1 mismatch: got %v, want %v", phi[0], 2*num/den)
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package timeseries
+
+import (
+	"gonum.org/v1/gonum/stat"
+)
+
+// YuleWalker estimates the coefficients of the autoregressive model of order p
+//  x_t - μ = \sum_{i=1}^p φ_i (x_{t-i} - μ) + ε_t
+// by solving the Yule–Walker equations for the sample autocovariance of x,
+// where μ is estimated by the sample mean. YuleWalker returns the coefficients
+// φ and the estimated innovation variance.
+//
+// YuleWalker panics if p is not in [0, len(x)).
+func YuleWalker(x []float64, p int) (phi []float64, sigma2 float64) {
+	if p < 0 || len(x) <= p {
+		panic(badOrder)
+	}
+	r := Autocovariance(nil, x, p)
+	phi, _, sigma2 = durbinLevinson(r, p)
+	return phi, sigma2
+}
+
+// Burg estimates the coefficients of the autoregressive model of order p
+//  x_t - μ = \sum_{i=1}^p φ_i (x_{t-i} - μ) + ε_t
+// using Burg's method, which minimizes the sum of the forward and backward
+// prediction errors at each stage of the Levinson recursion. The mean μ is
+// estimated by the sample mean. Burg returns the coefficients φ and the
+// estimated innovation variance.
+//
+// Burg panics if p is not in [0, len(x)).
+func Burg(x []float64, p int) (phi []float64, sigma2 float64) {
+	n := len(x)
+	if p < 0 || n <= p {
+		panic(badOrder)
+	}
+	mean := stat.Mean(x, nil)
+	f := make([]float64, n)
+	b := make([]float64, n)
+	for i, v := range x {
+		f[i] = v - mean
+		b[i] = v - mean
+	}
+	for _, v := range f {
+		sigma2 += v * v
+	}
+	sigma2 /= float64(n)
+
+	phi = make([]float64, p)
+	prev := make([]float64, p)
+	for k := 1; k <= p; k++ {
+		// Forward errors f[t] and backward errors b[t-1] for t in [k, n).
+		var num, den float64
+		for t := k; t < n; t++ {
+			num += f[t] * b[t-1]
+			den += f[t]*f[t] + b[t-1]*b[t-1]
+		}
+		a := 2 * num / den
+		copy(prev, phi)
+		phi[k-1] = a
+		for j := 1; j < k; j++ {
+			phi[j-1] = prev[j-1] - a*prev[k-j-1]
+		}
+		for t := n - 1; t >= k; t-- {
+			ft := f[t]
+			f[t] = ft - a*b[t-1]
+			b[t] = b[t-1] - a*ft
+		}
+		sigma2 *= 1 - a*a
+	}
+	return phi, sigma2
+}
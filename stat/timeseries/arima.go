@@ -0,0 +1,495 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package timeseries
+
+import (
+	"errors"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/optimize"
+	"gonum.org/v1/gonum/stat"
+)
+
+// Order is the order of an ARIMA model, with P autoregressive terms, D
+// differences and Q moving average terms.
+type Order struct {
+	P, D, Q int
+}
+
+// ARIMA is a seasonal autoregressive integrated moving average model
+//  φ(B) Φ(B^s) (w_t - μ) = θ(B) Θ(B^s) ε_t,  ε_t ~ N(0, σ²)
+//  w_t = (1-B)^d (1-B^s)^D y_t
+// where B is the backshift operator, s is the seasonal period and
+//  φ(B) = 1 - φ_1 B - ... - φ_p B^p
+//  θ(B) = 1 + θ_1 B + ... + θ_q B^q
+// with the seasonal polynomials Φ and Θ defined analogously.
+type ARIMA struct {
+	// Order and Seasonal are the non-seasonal and
+	// seasonal orders of the model, and Period is
+	// the seasonal period s.
+	Order    Order
+	Seasonal Order
+	Period   int
+
+	// AR, MA, SAR and SMA hold the coefficients of
+	// the non-seasonal and seasonal polynomials.
+	AR, MA   []float64
+	SAR, SMA []float64
+
+	// Mean is the mean μ of the differenced series.
+	Mean float64
+
+	// Sigma2 is the innovation variance σ².
+	Sigma2 float64
+
+	// LogLikelihood is the exact log-likelihood of
+	// the differenced series and AIC is the Akaike
+	// information criterion of the fit.
+	LogLikelihood float64
+	AIC           float64
+
+	hasMean bool
+	y       []float64
+}
+
+// FitARIMA fits an ARIMA model to the series y by maximizing the exact Gaussian
+// likelihood of the differenced series, computed by the Kalman filter applied
+// to the state-space form of the model. The innovation variance is profiled
+// out of the likelihood, and the remaining parameters are optimized with the
+// Nelder–Mead simplex method.
+// The autoregressive parameters are constrained to the stationary region by
+// optimizing over their partial autocorrelations. If mean is true the mean of
+// the differenced series is estimated, otherwise it is zero.
+//
+// If the model has no seasonal terms, period is ignored. FitARIMA panics if
+// the series is not longer than the differencing order plus the number of
+// parameters. Any error from the optimization is returned.
+func FitARIMA(y []float64, order, seasonal Order, period int, mean bool) (*ARIMA, error) {
+	if order.P < 0 || order.D < 0 || order.Q < 0 || seasonal.P < 0 || seasonal.D < 0 || seasonal.Q < 0 {
+		panic(badOrder)
+	}
+	if seasonal == (Order{}) {
+		period = 0
+	} else if period < 1 {
+		panic("timeseries: invalid seasonal period")
+	}
+	m := &ARIMA{
+		Order:    order,
+		Seasonal: seasonal,
+		Period:   period,
+		hasMean:  mean,
+		y:        append([]float64(nil), y...),
+	}
+	w := difference(y, m.differencing())
+	nparam := order.P + order.Q + seasonal.P + seasonal.Q
+	if mean {
+		nparam++
+	}
+	if len(w) <= nparam {
+		panic("timeseries: series too short")
+	}
+
+	init := make([]float64, nparam)
+	if mean {
+		init[nparam-1] = stat.Mean(w, nil)
+	}
+	objective := func(x []float64) float64 {
+		m.setParams(x)
+		ll, _, ok := m.concentrated(w)
+		if !ok {
+			return math.Inf(1)
+		}
+		return -ll
+	}
+
+	if nparam > 0 {
+		problem := optimize.Problem{Func: objective}
+		result, err := optimize.Minimize(problem, init, nil, &optimize.NelderMead{})
+		if err != nil {
+			return nil, err
+		}
+		init = result.X
+	}
+	m.setParams(init)
+	ll, sigma2, ok := m.concentrated(w)
+	if !ok {
+		return nil, errNotStationary
+	}
+	m.Sigma2 = sigma2
+	m.LogLikelihood = ll
+	m.AIC = -2*ll + 2*float64(nparam+1)
+	return m, nil
+}
+
+var errNotStationary = errors.New("timeseries: innovation covariance not positive definite")
+
+// setParams sets the model coefficients from the optimization parameters x.
+func (m *ARIMA) setParams(x []float64) {
+	p, q := m.Order.P, m.Order.Q
+	sp, sq := m.Seasonal.P, m.Seasonal.Q
+	m.AR = stationary(x[:p])
+	m.MA = append([]float64(nil), x[p:p+q]...)
+	m.SAR = stationary(x[p+q : p+q+sp])
+	m.SMA = append([]float64(nil), x[p+q+sp:p+q+sp+sq]...)
+	m.Mean = 0
+	if m.hasMean {
+		m.Mean = x[len(x)-1]
+	}
+}
+
+// stationary returns the coefficients of a stationary autoregression whose
+// partial autocorrelations are tanh(u).
+func stationary(u []float64) []float64 {
+	phi := make([]float64, len(u))
+	prev := make([]float64, len(u))
+	for k := range u {
+		a := math.Tanh(u[k])
+		phi[k] = a
+		for j := 0; j < k; j++ {
+			phi[j] = prev[j] - a*prev[k-j-1]
+		}
+		copy(prev, phi)
+	}
+	return phi
+}
+
+// polynomials returns the coefficients of the expanded autoregressive and
+// moving average polynomials, such that
+//  φ(B)Φ(B^s) = 1 - \sum_i ar[i] B^(i+1)
+//  θ(B)Θ(B^s) = 1 + \sum_i ma[i] B^(i+1)
+func (m *ARIMA) polynomials() (ar, ma []float64) {
+	ar = seasonalProduct(m.AR, m.SAR, m.Period, -1)
+	ma = seasonalProduct(m.MA, m.SMA, m.Period, 1)
+	return ar, ma
+}
+
+// seasonalProduct returns the coefficients of the product of the polynomials
+// 1 + sign*\sum_i a[i] B^(i+1) and 1 + sign*\sum_i b[i] B^(s*(i+1)), scaled
+// so that the result is in the same form.
+func seasonalProduct(a, b []float64, s int, sign float64) []float64 {
+	pa := make([]float64, len(a)+1)
+	pa[0] = 1
+	for i, v := range a {
+		pa[i+1] = sign * v
+	}
+	pb := make([]float64, s*len(b)+1)
+	pb[0] = 1
+	for i, v := range b {
+		pb[s*(i+1)] = sign * v
+	}
+	prod := polyMul(pa, pb)
+	for i := range prod {
+		prod[i] *= sign
+	}
+	return prod[1:]
+}
+
+// polyMul returns the product of the polynomials with coefficients a and b
+// in increasing order of degree.
+func polyMul(a, b []float64) []float64 {
+	c := make([]float64, len(a)+len(b)-1)
+	for i, u := range a {
+		for j, v := range b {
+			c[i+j] += u * v
+		}
+	}
+	return c
+}
+
+// differencing returns the coefficients of (1-B)^d (1-B^s)^D in increasing
+// order of degree.
+func (m *ARIMA) differencing() []float64 {
+	c := []float64{1}
+	for i := 0; i < m.Order.D; i++ {
+		c = polyMul(c, []float64{1, -1})
+	}
+	if m.Period > 0 {
+		seas := make([]float64, m.Period+1)
+		seas[0] = 1
+		seas[m.Period] = -1
+		for i := 0; i < m.Seasonal.D; i++ {
+			c = polyMul(c, seas)
+		}
+	}
+	return c
+}
+
+// difference returns the series y filtered by the polynomial c.
+func difference(y, c []float64) []float64 {
+	nd := len(c) - 1
+	if len(y) <= nd {
+		return nil
+	}
+	w := make([]float64, len(y)-nd)
+	for t := range w {
+		for j, v := range c {
+			w[t] += v * y[t+nd-j]
+		}
+	}
+	return w
+}
+
+// stateSpace returns the state-space form of the ARMA part of the model with
+// unit innovation variance. The state dimension is r = max(p, q+1) where p
+// and q are the degrees of the expanded polynomials, and the initial state
+// covariance is the stationary covariance.
+func (m *ARIMA) stateSpace() (*StateSpace, bool) {
+	ar, ma := m.polynomials()
+	r := len(ar)
+	if len(ma)+1 > r {
+		r = len(ma) + 1
+	}
+	T := mat.NewDense(r, r, nil)
+	for i, v := range ar {
+		T.Set(i, 0, v)
+	}
+	for i := 0; i < r-1; i++ {
+		T.Set(i, i+1, 1)
+	}
+	R := make([]float64, r)
+	R[0] = 1
+	copy(R[1:], ma)
+	Q := mat.NewSymDense(r, nil)
+	Q.SymOuterK(1, mat.NewDense(r, 1, R))
+	Z := mat.NewDense(1, r, nil)
+	Z.Set(0, 0, 1)
+
+	// Solve the Lyapunov equation P = T P Tᵀ + Q for the
+	// stationary covariance using (I - T⊗T) vec(P) = vec(Q).
+	var k mat.Dense
+	k.Kronecker(T, T)
+	k.Scale(-1, &k)
+	for i := 0; i < r*r; i++ {
+		k.Set(i, i, k.At(i, i)+1)
+	}
+	vq := make([]float64, r*r)
+	for i := 0; i < r; i++ {
+		for j := 0; j < r; j++ {
+			vq[i*r+j] = Q.At(i, j)
+		}
+	}
+	var vp mat.VecDense
+	err := vp.SolveVec(&k, mat.NewVecDense(r*r, vq))
+	if err != nil {
+		return nil, false
+	}
+	P0 := mat.NewSymDense(r, nil)
+	for i := 0; i < r; i++ {
+		for j := i; j < r; j++ {
+			P0.SetSym(i, j, 0.5*(vp.AtVec(i*r+j)+vp.AtVec(j*r+i)))
+		}
+	}
+	return &StateSpace{
+		T:  T,
+		Z:  Z,
+		Q:  Q,
+		H:  mat.NewSymDense(1, []float64{0}),
+		X0: make([]float64, r),
+		P0: P0,
+	}, true
+}
+
+// concentrated returns the log-likelihood of the differenced series w with
+// the innovation variance replaced by its maximum likelihood estimate, and
+// that estimate.
+func (m *ARIMA) concentrated(w []float64) (ll, sigma2 float64, ok bool) {
+	ss, ok := m.stateSpace()
+	if !ok {
+		return 0, 0, false
+	}
+	logDet, sumSq, ok := m.armaFilter(ss, w)
+	if !ok {
+		return 0, 0, false
+	}
+	n := float64(len(w))
+	sigma2 = sumSq / n
+	ll = -0.5 * (n*math.Log(2*math.Pi*sigma2) + logDet + n)
+	return ll, sigma2, true
+}
+
+// armaFilter runs the Kalman filter for the state-space form of the model
+// over the differenced series w, exploiting the companion structure of the
+// transition matrix and the scalar observation. It returns the sum of the
+// log innovation variances and the sum of the squared standardized
+// innovations.
+func (m *ARIMA) armaFilter(ss *StateSpace, w []float64) (logDet, sumSq float64, ok bool) {
+	r := len(ss.X0)
+	phi := make([]float64, r+1)
+	for i := 0; i < r; i++ {
+		phi[i] = ss.T.At(i, 0)
+	}
+	a := make([]float64, r+1)
+	P := make([]float64, (r+1)*(r+1))
+	for i := 0; i < r; i++ {
+		for j := 0; j < r; j++ {
+			P[i*(r+1)+j] = ss.P0.At(i, j)
+		}
+	}
+	Q := make([]float64, r*r)
+	for i := 0; i < r; i++ {
+		for j := 0; j < r; j++ {
+			Q[i*r+j] = ss.Q.At(i, j)
+		}
+	}
+	stride := r + 1
+	col := make([]float64, r)
+	tp := make([]float64, r*stride)
+	for _, y := range w {
+		// Update with the observation of the first state.
+		F := P[0]
+		if !(F > 0) {
+			return 0, 0, false
+		}
+		v := y - m.Mean - a[0]
+		logDet += math.Log(F)
+		sumSq += v * v / F
+		for i := 0; i < r; i++ {
+			col[i] = P[i*stride]
+		}
+		for i := 0; i < r; i++ {
+			a[i] += col[i] * v / F
+			for j := 0; j < r; j++ {
+				P[i*stride+j] -= col[i] * col[j] / F
+			}
+		}
+
+		// Predict with the companion transition. The extra
+		// row and column of a and P are zero.
+		a0 := a[0]
+		for i := 0; i < r; i++ {
+			a[i] = phi[i]*a0 + a[i+1]
+		}
+		for i := 0; i < r; i++ {
+			for j := 0; j < r; j++ {
+				tp[i*stride+j] = phi[i]*P[j] + P[(i+1)*stride+j]
+			}
+		}
+		for i := 0; i < r; i++ {
+			for j := 0; j < r; j++ {
+				P[i*stride+j] = phi[j]*tp[i*stride] + tp[i*stride+j+1] + Q[i*r+j]
+			}
+		}
+	}
+	return logDet, sumSq, true
+}
+
+// Forecast returns the forecasts of the next h values of the series and their
+// mean squared errors, conditional on the observed series. The forecasts are
+// computed exactly from the state-space form of the model augmented with the
+// lagged values of the undifferenced series.
+func (m *ARIMA) Forecast(h int) (mean, variance []float64) {
+	ss, ok := m.stateSpace()
+	if !ok {
+		panic(errNotStationary)
+	}
+	c := m.differencing()
+	w := difference(m.y, c)
+	y := mat.NewDense(len(w), 1, nil)
+	for i, v := range w {
+		y.Set(i, 0, v-m.Mean)
+	}
+	ss.Q = scaledSym(m.Sigma2, ss.Q)
+	ss.P0 = scaledSym(m.Sigma2, ss.P0)
+	res, ok := ss.Filter(y)
+	if !ok {
+		panic(errNotStationary)
+	}
+
+	// The augmented state is the ARMA state followed by
+	// the nl most recent values of the series.
+	r := len(ss.X0)
+	nl := len(c) - 1
+	if nl == 0 {
+		nl = 1
+	}
+	dim := r + nl
+	s := mat.NewVecDense(dim, nil)
+	S := mat.NewSymDense(dim, nil)
+	n := len(w)
+	if n > 0 {
+		for i := 0; i < r; i++ {
+			s.SetVec(i, res.State.At(n-1, i))
+			for j := i; j < r; j++ {
+				S.SetSym(i, j, res.StateCov[n-1].At(i, j))
+			}
+		}
+	}
+	for j := 0; j < len(c)-1; j++ {
+		s.SetVec(r+j, m.y[len(m.y)-1-j])
+	}
+
+	// Transition: x' = T x, y' = Z T x + μ + \sum_j δ_j y_{-j}.
+	A := mat.NewDense(dim, dim, nil)
+	A.Slice(0, r, 0, r).(*mat.Dense).Copy(ss.T)
+	for j := 0; j < r; j++ {
+		A.Set(r, j, ss.T.At(0, j))
+	}
+	for j := 1; j < len(c); j++ {
+		A.Set(r, r+j-1, -c[j])
+	}
+	for j := 1; j < nl; j++ {
+		A.Set(r+j, r+j-1, 1)
+	}
+	G := mat.NewDense(dim, r, nil)
+	for i := 0; i < r; i++ {
+		G.Set(i, i, 1)
+	}
+	G.Set(r, 0, 1)
+	var GQ mat.Dense
+	GQ.Mul(G, ss.Q)
+	var noise mat.Dense
+	noise.Mul(&GQ, G.T())
+
+	mean = make([]float64, h)
+	variance = make([]float64, h)
+	var tmp, next mat.Dense
+	for k := 0; k < h; k++ {
+		s.MulVec(A, s)
+		s.SetVec(r, s.AtVec(r)+m.Mean)
+		tmp.Mul(A, S)
+		next.Mul(&tmp, A.T())
+		for i := 0; i < dim; i++ {
+			for j := i; j < dim; j++ {
+				S.SetSym(i, j, 0.5*(next.At(i, j)+next.At(j, i))+noise.At(i, j))
+			}
+		}
+		mean[k] = s.AtVec(r)
+		variance[k] = S.At(r, r)
+	}
+	return mean, variance
+}
+
+// scaledSym returns a copy of a scaled by f.
+func scaledSym(f float64, a mat.Symmetric) *mat.SymDense {
+	s := mat.NewSymDense(a.Symmetric(), nil)
+	s.ScaleSym(f, a)
+	return s
+}
+
+// Residuals returns the one-step prediction errors of the differenced series,
+// each divided by the square root of its variance relative to σ², so that the
+// residuals of a correctly specified model are uncorrelated with variance σ².
+func (m *ARIMA) Residuals() []float64 {
+	ss, ok := m.stateSpace()
+	if !ok {
+		panic(errNotStationary)
+	}
+	w := difference(m.y, m.differencing())
+	res := make([]float64, len(w))
+	y := mat.NewDense(len(w), 1, nil)
+	for i, v := range w {
+		y.Set(i, 0, v-m.Mean)
+	}
+	f, ok := ss.Filter(y)
+	if !ok {
+		panic(errNotStationary)
+	}
+	for t := range res {
+		pred := f.Predicted.At(t, 0)
+		res[t] = (y.At(t, 0) - pred) / math.Sqrt(f.PredictedCov[t].At(0, 0))
+	}
+	return res
+}
@@ -0,0 +1,194 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package timeseries
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distmv"
+)
+
+func TestARIMALikelihood(t *testing.T) {
+	// Compare the exact likelihood of an ARMA(1,1) model with
+	// the density of the multivariate normal with the ARMA
+	// autocovariance.
+	const (
+		phi   = 0.6
+		theta = 0.3
+		mean  = 1.5
+	)
+	w := []float64{1.2, 2.5, 0.3, 1.9, 1.1, 2.2, 1.7, 0.4}
+	m := &ARIMA{
+		Order:   Order{P: 1, Q: 1},
+		AR:      []float64{phi},
+		MA:      []float64{theta},
+		Mean:    mean,
+		hasMean: true,
+	}
+	ll, sigma2, ok := m.concentrated(w)
+	if !ok {
+		t.Fatal("unexpected failure")
+	}
+
+	n := len(w)
+	gamma := make([]float64, n)
+	gamma[0] = (1 + 2*phi*theta + theta*theta) / (1 - phi*phi)
+	gamma[1] = (1 + phi*theta) * (phi + theta) / (1 - phi*phi)
+	for k := 2; k < n; k++ {
+		gamma[k] = phi * gamma[k-1]
+	}
+	cov := mat.NewSymDense(n, nil)
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			cov.SetSym(i, j, sigma2*gamma[j-i])
+		}
+	}
+	mu := make([]float64, n)
+	for i := range mu {
+		mu[i] = mean
+	}
+	norm, ok := distmv.NewNormal(mu, cov, nil)
+	if !ok {
+		t.Fatal("bad test")
+	}
+	if want := norm.LogProb(w); math.Abs(ll-want) > 1e-10 {
+		t.Errorf("log-likelihood mismatch: got %v, want %v", ll, want)
+	}
+}
+
+func TestFitARIMA(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		order    Order
+		ar, ma   []float64
+		mean     float64
+		hasMean  bool
+		tol      float64
+		n        int
+		integral int
+	}{
+		{name: "AR(2)", order: Order{P: 2}, ar: []float64{0.5, -0.3}, mean: 3, hasMean: true, tol: 0.06, n: 1000},
+		{name: "ARMA(1,1)", order: Order{P: 1, Q: 1}, ar: []float64{0.7}, ma: []float64{-0.4}, hasMean: true, tol: 0.08, n: 1000},
+		{name: "ARIMA(1,1,1)", order: Order{P: 1, D: 1, Q: 1}, ar: []float64{0.4}, ma: []float64{0.3}, tol: 0.1, n: 1000, integral: 1},
+	} {
+		x := simulateARMA(test.n, test.ar, test.ma, test.mean, rand.NewSource(1))
+		for i := 0; i < test.integral; i++ {
+			floats.CumSum(x, x)
+		}
+		m, err := FitARIMA(x, test.order, Order{}, 0, test.hasMean)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+		if !floats.EqualApprox(m.AR, test.ar, test.tol) || !floats.EqualApprox(m.MA, test.ma, test.tol) {
+			t.Errorf("%s: coefficients not recovered: got %v %v, want %v %v", test.name, m.AR, m.MA, test.ar, test.ma)
+		}
+		if math.Abs(m.Sigma2-1) > 0.1 {
+			t.Errorf("%s: innovation variance not recovered: got %v", test.name, m.Sigma2)
+		}
+		if test.hasMean && math.Abs(m.Mean-test.mean) > 0.3 {
+			t.Errorf("%s: mean not recovered: got %v, want %v", test.name, m.Mean, test.mean)
+		}
+		res := m.Residuals()
+		if v := stat.Variance(res, nil); math.Abs(v-m.Sigma2) > 0.1 {
+			t.Errorf("%s: residual variance mismatch: got %v, want %v", test.name, v, m.Sigma2)
+		}
+		acf := Autocorrelation(nil, res, 5)
+		for k := 1; k <= 5; k++ {
+			if math.Abs(acf[k]) > 3/math.Sqrt(float64(len(res))) {
+				t.Errorf("%s: residuals autocorrelated at lag %d: %v", test.name, k, acf[k])
+			}
+		}
+	}
+}
+
+func TestFitSARIMA(t *testing.T) {
+	// The airline model (0,1,1)(0,1,1)_12.
+	const (
+		period = 12
+		theta  = -0.4
+		stheta = -0.6
+		n      = 600
+	)
+	ma := make([]float64, period+1)
+	ma[0] = theta
+	ma[period-1] = stheta
+	ma[period] = theta * stheta
+	w := simulateARMA(n, nil, ma, 0, rand.NewSource(1))
+	y := make([]float64, n+period+1)
+	for t := range w {
+		i := t + period + 1
+		y[i] = w[t] + y[i-1] + y[i-period] - y[i-period-1]
+	}
+	m, err := FitARIMA(y, Order{D: 1, Q: 1}, Order{D: 1, Q: 1}, period, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if math.Abs(m.MA[0]-theta) > 0.1 || math.Abs(m.SMA[0]-stheta) > 0.1 {
+		t.Errorf("coefficients not recovered: got %v %v, want %v %v", m.MA, m.SMA, theta, stheta)
+	}
+	if math.Abs(m.Sigma2-1) > 0.15 {
+		t.Errorf("innovation variance not recovered: got %v", m.Sigma2)
+	}
+}
+
+func TestARIMAForecast(t *testing.T) {
+	x := simulateARMA(500, []float64{0.7}, nil, 2, rand.NewSource(1))
+	m, err := FitARIMA(x, Order{P: 1}, Order{}, 0, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	const h = 5
+	mean, variance := m.Forecast(h)
+	phi := m.AR[0]
+	last := x[len(x)-1]
+	for k := 1; k <= h; k++ {
+		want := m.Mean + math.Pow(phi, float64(k))*(last-m.Mean)
+		var v float64
+		for j := 0; j < k; j++ {
+			v += math.Pow(phi, float64(2*j))
+		}
+		v *= m.Sigma2
+		if math.Abs(mean[k-1]-want) > 1e-8 || math.Abs(variance[k-1]-v) > 1e-8 {
+			t.Errorf("AR(1) forecast mismatch at step %d: got %v±%v, want %v±%v", k, mean[k-1], variance[k-1], want, v)
+		}
+	}
+
+	// A random walk forecasts the last value with linearly growing variance.
+	rw := make([]float64, 200)
+	floats.CumSum(rw, simulateARMA(200, nil, nil, 0, rand.NewSource(2)))
+	m, err = FitARIMA(rw, Order{D: 1}, Order{}, 0, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	mean, variance = m.Forecast(h)
+	for k := 1; k <= h; k++ {
+		if math.Abs(mean[k-1]-rw[len(rw)-1]) > 1e-12 || math.Abs(variance[k-1]-float64(k)*m.Sigma2) > 1e-12 {
+			t.Errorf("random walk forecast mismatch at step %d: got %v±%v", k, mean[k-1], variance[k-1])
+		}
+	}
+
+	// ARIMA(0,1,1) forecasts are constant with variance
+	// σ²(1 + (k-1)(1+θ)²).
+	y := make([]float64, 400)
+	floats.CumSum(y, simulateARMA(400, nil, []float64{0.5}, 0, rand.NewSource(3)))
+	m, err = FitARIMA(y, Order{D: 1, Q: 1}, Order{}, 0, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	mean, variance = m.Forecast(h)
+	theta := m.MA[0]
+	for k := 1; k <= h; k++ {
+		want := m.Sigma2 * (1 + float64(k-1)*(1+theta)*(1+theta))
+		if math.Abs(mean[k-1]-mean[0]) > 1e-10 || math.Abs(variance[k-1]-want) > 1e-6 {
+			t.Errorf("ARIMA(0,1,1) forecast mismatch at step %d: got %v±%v, want %v±%v", k, mean[k-1], variance[k-1], mean[0], want)
+		}
+	}
+}
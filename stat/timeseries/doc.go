@@ -0,0 +1,8 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package timeseries provides time series analysis, including autocorrelation
+// estimation, autoregressive and ARIMA models, and linear Gaussian state-space
+// models with Kalman filtering and smoothing.
+package timeseries // import "gonum.org/v1/gonum/stat/timeseries"
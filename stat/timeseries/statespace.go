@@ -0,0 +1,251 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package timeseries
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+const badStateSpace = "timeseries: state-space dimension mismatch"
+
+// StateSpace is a linear Gaussian state-space model
+//  x_{t+1} = T x_t + η_t,  η_t ~ N(0, Q)
+//  y_t     = Z x_t + ε_t,  ε_t ~ N(0, H)
+// where x_t is the m-dimensional state and y_t is the p-dimensional
+// observation at time t. The state at the first time step is distributed
+// as x_0 ~ N(X0, P0).
+type StateSpace struct {
+	// T is the m×m state transition matrix.
+	T mat.Matrix
+	// Z is the p×m observation matrix.
+	Z mat.Matrix
+	// Q is the m×m state noise covariance.
+	Q mat.Symmetric
+	// H is the p×p observation noise covariance.
+	H mat.Symmetric
+
+	// X0 and P0 are the mean and covariance
+	// of the initial state.
+	X0 []float64
+	P0 mat.Symmetric
+}
+
+// FilterResult holds the output of the Kalman filter.
+type FilterResult struct {
+	// Predicted and PredictedCov hold the mean and covariance of
+	// the state at each time step given the preceding observations.
+	// The means are stored in the rows of Predicted.
+	Predicted    *mat.Dense
+	PredictedCov []*mat.SymDense
+
+	// State and StateCov hold the mean and covariance of the state
+	// at each time step given the observations up to and including
+	// that time step. The means are stored in the rows of State.
+	State    *mat.Dense
+	StateCov []*mat.SymDense
+
+	// LogLikelihood is the log-likelihood of the observations.
+	LogLikelihood float64
+}
+
+// dims returns the state and observation dimensions of the model,
+// panicking if the model dimensions are not consistent.
+func (s *StateSpace) dims() (m, p int) {
+	m, c := s.T.Dims()
+	if m != c {
+		panic(badStateSpace)
+	}
+	p, c = s.Z.Dims()
+	if c != m || s.Q.Symmetric() != m || s.H.Symmetric() != p || len(s.X0) != m || s.P0.Symmetric() != m {
+		panic(badStateSpace)
+	}
+	return m, p
+}
+
+// Filter runs the Kalman filter over the observations held in the rows of y.
+// Rows of y containing a NaN value are treated as missing and the filter
+// performs only the prediction step at those times. The filtered covariance
+// is updated as
+//  P_{t|t} = P_{t|t-1} - W Wᵀ,  W = P_{t|t-1} Zᵀ L^-ᵀ
+// where L is the Cholesky factor of the innovation covariance, which keeps
+// the covariance symmetric and positive semi-definite.
+//
+// Filter returns false if an innovation covariance is not positive definite.
+// Filter panics if the number of columns of y is not the observation dimension
+// of the model.
+func (s *StateSpace) Filter(y mat.Matrix) (*FilterResult, bool) {
+	m, p := s.dims()
+	n, c := y.Dims()
+	if c != p {
+		panic(badStateSpace)
+	}
+	res := &FilterResult{
+		Predicted:    mat.NewDense(n, m, nil),
+		PredictedCov: make([]*mat.SymDense, n),
+		State:        mat.NewDense(n, m, nil),
+		StateCov:     make([]*mat.SymDense, n),
+	}
+	logDet, sumSq, nobs, ok := s.filter(y, res)
+	if !ok {
+		return nil, false
+	}
+	res.LogLikelihood = -0.5 * (float64(nobs)*math.Log(2*math.Pi) + logDet + sumSq)
+	return res, true
+}
+
+// filter runs the Kalman filter over y, storing the states in res if it is
+// not nil. It returns the sum of the log determinants of the innovation
+// covariances, the sum of the squared standardized innovations and the
+// number of observed values.
+func (s *StateSpace) filter(y mat.Matrix, res *FilterResult) (logDet, sumSq float64, nobs int, ok bool) {
+	m, p := s.dims()
+	n, _ := y.Dims()
+
+	a := mat.NewVecDense(m, nil)
+	a.CopyVec(mat.NewVecDense(m, s.X0))
+	P := mat.NewSymDense(m, nil)
+	P.CopySym(s.P0)
+
+	var (
+		v    = mat.NewVecDense(p, nil)
+		F    = mat.NewSymDense(p, nil)
+		M    mat.Dense
+		zpz  mat.Dense
+		L    mat.TriDense
+		Wt   mat.Dense
+		u    mat.VecDense
+		chol mat.Cholesky
+		tmp  mat.Dense
+		next mat.Dense
+		row  = make([]float64, p)
+	)
+	for t := 0; t < n; t++ {
+		if res != nil {
+			res.Predicted.SetRow(t, a.RawVector().Data)
+			res.PredictedCov[t] = mat.NewSymDense(m, nil)
+			res.PredictedCov[t].CopySym(P)
+		}
+
+		mat.Row(row, t, y)
+		missing := false
+		for _, val := range row {
+			if math.IsNaN(val) {
+				missing = true
+				break
+			}
+		}
+		if !missing {
+			// Innovation v = y - Z a and its covariance F = Z P Zᵀ + H.
+			v.MulVec(s.Z, a)
+			v.SubVec(mat.NewVecDense(p, row), v)
+			M.Mul(P, s.Z.T())
+			zpz.Mul(s.Z, &M)
+			for i := 0; i < p; i++ {
+				for j := i; j < p; j++ {
+					F.SetSym(i, j, 0.5*(zpz.At(i, j)+zpz.At(j, i))+s.H.At(i, j))
+				}
+			}
+			if !chol.Factorize(F) {
+				return 0, 0, 0, false
+			}
+			chol.LTo(&L)
+			// Wᵀ = L^-1 (P Zᵀ)ᵀ and u = L^-1 v.
+			err := Wt.Solve(&L, M.T())
+			if err != nil {
+				if _, ok := err.(mat.Condition); !ok {
+					return 0, 0, 0, false
+				}
+			}
+			err = u.SolveVec(&L, v)
+			if err != nil {
+				if _, ok := err.(mat.Condition); !ok {
+					return 0, 0, 0, false
+				}
+			}
+			logDet += chol.LogDet()
+			sumSq += mat.Dot(&u, &u)
+			nobs += p
+
+			var gain mat.VecDense
+			gain.MulVec(Wt.T(), &u)
+			a.AddVec(a, &gain)
+			P.SymRankK(P, -1, Wt.T())
+		}
+		if res != nil {
+			res.State.SetRow(t, a.RawVector().Data)
+			res.StateCov[t] = mat.NewSymDense(m, nil)
+			res.StateCov[t].CopySym(P)
+		}
+
+		// Predict the next state.
+		a.MulVec(s.T, a)
+		tmp.Mul(s.T, P)
+		next.Mul(&tmp, s.T.T())
+		for i := 0; i < m; i++ {
+			for j := i; j < m; j++ {
+				P.SetSym(i, j, 0.5*(next.At(i, j)+next.At(j, i))+s.Q.At(i, j))
+			}
+		}
+	}
+	return logDet, sumSq, nobs, true
+}
+
+// Smooth runs the Rauch–Tung–Striebel smoother on the output of the Kalman
+// filter, returning the mean and covariance of the state at each time step
+// given all the observations. The means are stored in the rows of the
+// returned matrix. The predicted state covariances must be non-singular.
+func (s *StateSpace) Smooth(f *FilterResult) (*mat.Dense, []*mat.SymDense) {
+	m, _ := s.dims()
+	n, c := f.State.Dims()
+	if c != m {
+		panic(badStateSpace)
+	}
+	mean := mat.NewDense(n, m, nil)
+	cov := make([]*mat.SymDense, n)
+	if n == 0 {
+		return mean, cov
+	}
+	mean.SetRow(n-1, f.State.RawRowView(n-1))
+	cov[n-1] = mat.NewSymDense(m, nil)
+	cov[n-1].CopySym(f.StateCov[n-1])
+
+	var (
+		tp   mat.Dense
+		Jt   mat.Dense
+		chol mat.Cholesky
+		diff mat.VecDense
+		corr mat.VecDense
+		dcov mat.Dense
+		tmp  mat.Dense
+		next mat.Dense
+	)
+	for t := n - 2; t >= 0; t-- {
+		// Jᵀ = P_{t+1|t}^-1 T P_{t|t}.
+		tp.Mul(s.T, f.StateCov[t])
+		if chol.Factorize(f.PredictedCov[t+1]) {
+			chol.SolveTo(&Jt, &tp)
+		} else {
+			Jt.Solve(f.PredictedCov[t+1], &tp)
+		}
+
+		diff.SubVec(mean.RowView(t+1), f.Predicted.RowView(t+1))
+		corr.MulVec(Jt.T(), &diff)
+		corr.AddVec(&corr, f.State.RowView(t))
+		mean.SetRow(t, corr.RawVector().Data)
+
+		dcov.Sub(cov[t+1], f.PredictedCov[t+1])
+		tmp.Mul(Jt.T(), &dcov)
+		next.Mul(&tmp, &Jt)
+		cov[t] = mat.NewSymDense(m, nil)
+		for i := 0; i < m; i++ {
+			for j := i; j < m; j++ {
+				cov[t].SetSym(i, j, f.StateCov[t].At(i, j)+0.5*(next.At(i, j)+next.At(j, i)))
+			}
+		}
+	}
+	return mean, cov
+}
@@ -0,0 +1,213 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package timeseries
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distmv"
+)
+
+// jointMoments returns the mean and covariance of the stacked states
+// and the stacked observations of the model over n time steps.
+func jointMoments(s *StateSpace, n int) (mu []float64, cov *mat.SymDense) {
+	m, _ := s.T.Dims()
+	p, _ := s.Z.Dims()
+	means := make([]*mat.VecDense, n)
+	vars := make([]*mat.Dense, n)
+	means[0] = mat.NewVecDense(m, append([]float64(nil), s.X0...))
+	vars[0] = mat.DenseCopyOf(s.P0)
+	for t := 1; t < n; t++ {
+		means[t] = mat.NewVecDense(m, nil)
+		means[t].MulVec(s.T, means[t-1])
+		var tmp mat.Dense
+		tmp.Mul(s.T, vars[t-1])
+		vars[t] = mat.NewDense(m, m, nil)
+		vars[t].Mul(&tmp, s.T.T())
+		vars[t].Add(vars[t], s.Q)
+	}
+	// crossState returns Cov(x_a, x_b).
+	crossState := func(a, b int) *mat.Dense {
+		if a < b {
+			var c mat.Dense
+			c.CloneFrom(crossStateT(s.T, vars[a], b-a))
+			return &c
+		}
+		var c mat.Dense
+		c.CloneFrom(crossStateT(s.T, vars[b], a-b).T())
+		return &c
+	}
+
+	// The joint vector is the n states followed by the n observations.
+	dim := n*m + n*p
+	mu = make([]float64, dim)
+	full := mat.NewDense(dim, dim, nil)
+	for a := 0; a < n; a++ {
+		for i := 0; i < m; i++ {
+			mu[a*m+i] = means[a].AtVec(i)
+		}
+		var ym mat.VecDense
+		ym.MulVec(s.Z, means[a])
+		for i := 0; i < p; i++ {
+			mu[n*m+a*p+i] = ym.AtVec(i)
+		}
+		for b := 0; b < n; b++ {
+			c := crossState(a, b)
+			full.Slice(a*m, (a+1)*m, b*m, (b+1)*m).(*mat.Dense).Copy(c)
+			var xy mat.Dense
+			xy.Mul(c, s.Z.T())
+			full.Slice(a*m, (a+1)*m, n*m+b*p, n*m+(b+1)*p).(*mat.Dense).Copy(&xy)
+			full.Slice(n*m+b*p, n*m+(b+1)*p, a*m, (a+1)*m).(*mat.Dense).Copy(xy.T())
+			var yy mat.Dense
+			yy.Mul(s.Z, &xy)
+			if a == b {
+				yy.Add(&yy, s.H)
+			}
+			full.Slice(n*m+a*p, n*m+(a+1)*p, n*m+b*p, n*m+(b+1)*p).(*mat.Dense).Copy(&yy)
+		}
+	}
+	cov = mat.NewSymDense(dim, nil)
+	for i := 0; i < dim; i++ {
+		for j := i; j < dim; j++ {
+			cov.SetSym(i, j, full.At(i, j))
+		}
+	}
+	return mu, cov
+}
+
+// crossStateT returns Cov(x_a, x_{a+k}) = V_a (T^k)ᵀ.
+func crossStateT(T mat.Matrix, v mat.Matrix, k int) mat.Matrix {
+	var c mat.Dense
+	c.CloneFrom(v)
+	for i := 0; i < k; i++ {
+		var next mat.Dense
+		next.Mul(&c, T.T())
+		c = next
+	}
+	return &c
+}
+
+func TestStateSpace(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	const (
+		n = 6
+		m = 2
+		p = 2
+	)
+	s := &StateSpace{
+		T:  mat.NewDense(m, m, []float64{0.8, 0.2, -0.1, 0.9}),
+		Z:  mat.NewDense(p, m, []float64{1, 0.5, 0, 1}),
+		Q:  mat.NewSymDense(m, []float64{0.5, 0.1, 0.1, 0.3}),
+		H:  mat.NewSymDense(p, []float64{0.2, 0.05, 0.05, 0.4}),
+		X0: []float64{1, -1},
+		P0: mat.NewSymDense(m, []float64{1, 0.2, 0.2, 2}),
+	}
+	y := mat.NewDense(n, p, nil)
+	for i := 0; i < n; i++ {
+		for j := 0; j < p; j++ {
+			y.Set(i, j, rnd.NormFloat64())
+		}
+	}
+	// Mark one observation as missing.
+	y.Set(3, 1, math.NaN())
+
+	mu, cov := jointMoments(s, n)
+	var obs []int
+	var yObs []float64
+	for i := 0; i < n; i++ {
+		missing := math.IsNaN(y.At(i, 1))
+		for j := 0; j < p; j++ {
+			if !missing {
+				obs = append(obs, n*m+i*p+j)
+				yObs = append(yObs, y.At(i, j))
+			}
+		}
+	}
+	joint, ok := distmv.NewNormal(mu, cov, nil)
+	if !ok {
+		t.Fatal("bad test")
+	}
+	marg, ok := joint.MarginalNormal(obs, nil)
+	if !ok {
+		t.Fatal("bad test")
+	}
+
+	f, ok := s.Filter(y)
+	if !ok {
+		t.Fatal("unexpected filter failure")
+	}
+	if want := marg.LogProb(yObs); math.Abs(f.LogLikelihood-want) > 1e-10 {
+		t.Errorf("log-likelihood mismatch: got %v, want %v", f.LogLikelihood, want)
+	}
+
+	cond, ok := joint.ConditionNormal(obs, yObs, nil)
+	if !ok {
+		t.Fatal("bad test")
+	}
+	condMean := cond.Mean(nil)
+	var condCov mat.SymDense
+	cond.CovarianceMatrix(&condCov)
+
+	mean, covs := s.Smooth(f)
+	for i := 0; i < n; i++ {
+		for a := 0; a < m; a++ {
+			if got, want := mean.At(i, a), condMean[i*m+a]; math.Abs(got-want) > 1e-10 {
+				t.Errorf("smoothed mean mismatch at time %d: got %v, want %v", i, got, want)
+			}
+			for b := 0; b < m; b++ {
+				if got, want := covs[i].At(a, b), condCov.At(i*m+a, i*m+b); math.Abs(got-want) > 1e-10 {
+					t.Errorf("smoothed covariance mismatch at time %d: got %v, want %v", i, got, want)
+				}
+			}
+		}
+	}
+
+	// The filtered state at the last time step is the smoothed state.
+	if !mat.EqualApprox(f.State.RowView(n-1), mean.RowView(n-1), 1e-14) {
+		t.Errorf("final filtered and smoothed states differ")
+	}
+}
+
+func TestStateSpaceLocalLevel(t *testing.T) {
+	// The local level model has a scalar closed form filter.
+	const (
+		q  = 0.3
+		h  = 1.2
+		p0 = 5
+	)
+	y := []float64{1.2, 0.8, 2.5, 1.9, 3.1, 2.7}
+	s := &StateSpace{
+		T:  mat.NewDense(1, 1, []float64{1}),
+		Z:  mat.NewDense(1, 1, []float64{1}),
+		Q:  mat.NewSymDense(1, []float64{q}),
+		H:  mat.NewSymDense(1, []float64{h}),
+		X0: []float64{0},
+		P0: mat.NewSymDense(1, []float64{p0}),
+	}
+	f, ok := s.Filter(mat.NewDense(len(y), 1, y))
+	if !ok {
+		t.Fatal("unexpected filter failure")
+	}
+	a, P := 0.0, float64(p0)
+	var ll float64
+	for i, v := range y {
+		F := P + h
+		ll -= 0.5 * (math.Log(2*math.Pi*F) + (v-a)*(v-a)/F)
+		k := P / F
+		a += k * (v - a)
+		P *= 1 - k
+		if math.Abs(f.State.At(i, 0)-a) > 1e-14 || math.Abs(f.StateCov[i].At(0, 0)-P) > 1e-14 {
+			t.Errorf("filtered state mismatch at %d: got %v, %v, want %v, %v", i, f.State.At(i, 0), f.StateCov[i].At(0, 0), a, P)
+		}
+		P += q
+	}
+	if math.Abs(f.LogLikelihood-ll) > 1e-12 {
+		t.Errorf("log-likelihood mismatch: got %v, want %v", f.LogLikelihood, ll)
+	}
+}